@@ -0,0 +1,241 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v4.25.3
+// source: admin/admin.proto
+
+package admin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	// This returns the metadata of a blob in its raw DynamoDB representation
+	// alongside the decoded metadata, e.g. to tell deserialization bugs from
+	// actually missing attributes.
+	GetRawBlobMetadata(ctx context.Context, in *RawMetadataRequest, opts ...grpc.CallOption) (*RawMetadataReply, error)
+	// This finds the blobs whose hash starts with the given prefix, e.g. to look
+	// up a blob from a truncated hash in logs. It scans all the blobs, so it must
+	// be enabled on the disperser with the allow-prefix-scan flag.
+	FindBlobsByHashPrefix(ctx context.Context, in *HashPrefixRequest, opts ...grpc.CallOption) (*HashPrefixReply, error)
+	// This returns the blobs of a status requested within a time range, sorted
+	// by request time, e.g. the blobs of the last hour which are still processing.
+	// The number of blobs is limited by the time-range-query-limit flag of the
+	// disperser, the later blobs are left out.
+	QueryBlobs(ctx context.Context, in *QueryBlobsRequest, opts ...grpc.CallOption) (*QueryBlobsReply, error)
+	// This sets the status of a blob to recover it when it is stuck, e.g. in
+	// PROCESSING after the encoder or the confirmer crashed. Any blob can be
+	// failed, and a confirmed blob can be finalized; the other transitions are
+	// rejected. The reason is recorded in the status history of the blob.
+	AdminOverrideBlobStatus(ctx context.Context, in *AdminOverrideBlobStatusRequest, opts ...grpc.CallOption) (*AdminOverrideBlobStatusReply, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) GetRawBlobMetadata(ctx context.Context, in *RawMetadataRequest, opts ...grpc.CallOption) (*RawMetadataReply, error) {
+	out := new(RawMetadataReply)
+	err := c.cc.Invoke(ctx, "/admin.Admin/GetRawBlobMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) FindBlobsByHashPrefix(ctx context.Context, in *HashPrefixRequest, opts ...grpc.CallOption) (*HashPrefixReply, error) {
+	out := new(HashPrefixReply)
+	err := c.cc.Invoke(ctx, "/admin.Admin/FindBlobsByHashPrefix", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) QueryBlobs(ctx context.Context, in *QueryBlobsRequest, opts ...grpc.CallOption) (*QueryBlobsReply, error) {
+	out := new(QueryBlobsReply)
+	err := c.cc.Invoke(ctx, "/admin.Admin/QueryBlobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AdminOverrideBlobStatus(ctx context.Context, in *AdminOverrideBlobStatusRequest, opts ...grpc.CallOption) (*AdminOverrideBlobStatusReply, error) {
+	out := new(AdminOverrideBlobStatusReply)
+	err := c.cc.Invoke(ctx, "/admin.Admin/AdminOverrideBlobStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	// This returns the metadata of a blob in its raw DynamoDB representation
+	// alongside the decoded metadata, e.g. to tell deserialization bugs from
+	// actually missing attributes.
+	GetRawBlobMetadata(context.Context, *RawMetadataRequest) (*RawMetadataReply, error)
+	// This finds the blobs whose hash starts with the given prefix, e.g. to look
+	// up a blob from a truncated hash in logs. It scans all the blobs, so it must
+	// be enabled on the disperser with the allow-prefix-scan flag.
+	FindBlobsByHashPrefix(context.Context, *HashPrefixRequest) (*HashPrefixReply, error)
+	// This returns the blobs of a status requested within a time range, sorted
+	// by request time, e.g. the blobs of the last hour which are still processing.
+	// The number of blobs is limited by the time-range-query-limit flag of the
+	// disperser, the later blobs are left out.
+	QueryBlobs(context.Context, *QueryBlobsRequest) (*QueryBlobsReply, error)
+	// This sets the status of a blob to recover it when it is stuck, e.g. in
+	// PROCESSING after the encoder or the confirmer crashed. Any blob can be
+	// failed, and a confirmed blob can be finalized; the other transitions are
+	// rejected. The reason is recorded in the status history of the blob.
+	AdminOverrideBlobStatus(context.Context, *AdminOverrideBlobStatusRequest) (*AdminOverrideBlobStatusReply, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) GetRawBlobMetadata(context.Context, *RawMetadataRequest) (*RawMetadataReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRawBlobMetadata not implemented")
+}
+func (UnimplementedAdminServer) FindBlobsByHashPrefix(context.Context, *HashPrefixRequest) (*HashPrefixReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindBlobsByHashPrefix not implemented")
+}
+func (UnimplementedAdminServer) QueryBlobs(context.Context, *QueryBlobsRequest) (*QueryBlobsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryBlobs not implemented")
+}
+func (UnimplementedAdminServer) AdminOverrideBlobStatus(context.Context, *AdminOverrideBlobStatusRequest) (*AdminOverrideBlobStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminOverrideBlobStatus not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_GetRawBlobMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RawMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetRawBlobMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.Admin/GetRawBlobMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetRawBlobMetadata(ctx, req.(*RawMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_FindBlobsByHashPrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HashPrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).FindBlobsByHashPrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.Admin/FindBlobsByHashPrefix",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).FindBlobsByHashPrefix(ctx, req.(*HashPrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_QueryBlobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryBlobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).QueryBlobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.Admin/QueryBlobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).QueryBlobs(ctx, req.(*QueryBlobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AdminOverrideBlobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminOverrideBlobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AdminOverrideBlobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.Admin/AdminOverrideBlobStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AdminOverrideBlobStatus(ctx, req.(*AdminOverrideBlobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "admin.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRawBlobMetadata",
+			Handler:    _Admin_GetRawBlobMetadata_Handler,
+		},
+		{
+			MethodName: "FindBlobsByHashPrefix",
+			Handler:    _Admin_FindBlobsByHashPrefix_Handler,
+		},
+		{
+			MethodName: "QueryBlobs",
+			Handler:    _Admin_QueryBlobs_Handler,
+		},
+		{
+			MethodName: "AdminOverrideBlobStatus",
+			Handler:    _Admin_AdminOverrideBlobStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin/admin.proto",
+}
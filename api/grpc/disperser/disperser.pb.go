@@ -92,17 +92,25 @@ type DisperseBlobRequest struct {
 	unknownFields protoimpl.UnknownFields
 
 	// The data to be dispersed.
-	// The size of data must be <= 512KiB.
+	// The size of data must be <= 512KiB, and at least the minimum blob size of the disperser, 1 byte by default.
 	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 	// Security parameters allowing clients to customize the safety (via adversary threshold)
 	// and liveness (via quorum threshold).
 	// Clients can define one SecurityParams per quorum, and specify multiple quorums.
 	// The disperser will ensure that the encoded blobs for each quorum are all processed
 	// within the same batch.
+	// At least one SecurityParams is required.
 	SecurityParams []*SecurityParams `protobuf:"bytes,2,rep,name=security_params,json=securityParams,proto3" json:"security_params,omitempty"`
 	// The number of rows that encoded blob split into.
 	// The number will be aligned to the next power of 2 and be bounded by blob size.
 	TargetRowNum uint32 `protobuf:"varint,3,opt,name=target_row_num,json=targetRowNum,proto3" json:"target_row_num,omitempty"`
+	// An optional key identifying the request, of at most 256 characters. A request retried with the same key by
+	// the same account within the idempotency window returns the reply of the original request, with its request_id,
+	// without storing the blob again.
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// The priority of the blob, from 0 (low, the default) to 255 (high). The batcher encodes and batches the higher
+	// priority blobs first, the blobs waiting for longer than the starvation age of the batcher go first regardless.
+	Priority uint32 `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
 }
 
 func (x *DisperseBlobRequest) Reset() {
@@ -158,26 +166,41 @@ func (x *DisperseBlobRequest) GetTargetRowNum() uint32 {
 	return 0
 }
 
-type DisperseBlobReply struct {
+func (x *DisperseBlobRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *DisperseBlobRequest) GetPriority() uint32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+// DisperseBlobChunk is a part of a blob streamed to DisperseBlobStream.
+type DisperseBlobChunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The status of the blob associated with the request_id.
-	Result BlobStatus `protobuf:"varint,1,opt,name=result,proto3,enum=disperser.BlobStatus" json:"result,omitempty"`
-	// The request ID generated by the disperser.
-	// Once a request is accepted (although not processed), a unique request ID will be
-	// generated.
-	// Two different DisperseBlobRequests (determined by the hash of the DisperseBlobRequest)
-	// will have different IDs, and the same DisperseBlobRequest sent repeatedly at different
-	// times will also have different IDs.
-	// The client should use this ID to query the processing status of the request (via
-	// the GetBlobStatus API).
-	RequestId []byte `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The next part of the data to be dispersed, the chunks are concatenated in order.
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// The security parameters of the blob, see DisperseBlobRequest. Only read from the first chunk, which must have
+	// at least one.
+	SecurityParams []*SecurityParams `protobuf:"bytes,2,rep,name=security_params,json=securityParams,proto3" json:"security_params,omitempty"`
+	// The number of rows that encoded blob split into, see DisperseBlobRequest. Only read from the first chunk.
+	TargetRowNum uint32 `protobuf:"varint,3,opt,name=target_row_num,json=targetRowNum,proto3" json:"target_row_num,omitempty"`
+	// Set on the last chunk of the blob.
+	Flush bool `protobuf:"varint,4,opt,name=flush,proto3" json:"flush,omitempty"`
+	// The priority of the blob, see DisperseBlobRequest. Only read from the first chunk.
+	Priority uint32 `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
 }
 
-func (x *DisperseBlobReply) Reset() {
-	*x = DisperseBlobReply{}
+func (x *DisperseBlobChunk) Reset() {
+	*x = DisperseBlobChunk{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -185,13 +208,13 @@ func (x *DisperseBlobReply) Reset() {
 	}
 }
 
-func (x *DisperseBlobReply) String() string {
+func (x *DisperseBlobChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DisperseBlobReply) ProtoMessage() {}
+func (*DisperseBlobChunk) ProtoMessage() {}
 
-func (x *DisperseBlobReply) ProtoReflect() protoreflect.Message {
+func (x *DisperseBlobChunk) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -203,36 +226,82 @@ func (x *DisperseBlobReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DisperseBlobReply.ProtoReflect.Descriptor instead.
-func (*DisperseBlobReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use DisperseBlobChunk.ProtoReflect.Descriptor instead.
+func (*DisperseBlobChunk) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *DisperseBlobReply) GetResult() BlobStatus {
+func (x *DisperseBlobChunk) GetData() []byte {
 	if x != nil {
-		return x.Result
+		return x.Data
 	}
-	return BlobStatus_UNKNOWN
+	return nil
 }
 
-func (x *DisperseBlobReply) GetRequestId() []byte {
+func (x *DisperseBlobChunk) GetSecurityParams() []*SecurityParams {
 	if x != nil {
-		return x.RequestId
+		return x.SecurityParams
 	}
 	return nil
 }
 
-// BlobStatusRequest is used to query the status of a blob.
-type BlobStatusRequest struct {
+func (x *DisperseBlobChunk) GetTargetRowNum() uint32 {
+	if x != nil {
+		return x.TargetRowNum
+	}
+	return 0
+}
+
+func (x *DisperseBlobChunk) GetFlush() bool {
+	if x != nil {
+		return x.Flush
+	}
+	return false
+}
+
+func (x *DisperseBlobChunk) GetPriority() uint32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type DisperseBlobReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RequestId []byte `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The status of the blob associated with the request_id.
+	Result BlobStatus `protobuf:"varint,1,opt,name=result,proto3,enum=disperser.BlobStatus" json:"result,omitempty"`
+	// The request ID generated by the disperser.
+	// Once a request is accepted (although not processed), a unique request ID will be
+	// generated.
+	// Two different DisperseBlobRequests (determined by the hash of the DisperseBlobRequest)
+	// will have different IDs, and the same DisperseBlobRequest sent repeatedly at different
+	// times will also have different IDs.
+	// The client should use this ID to query the processing status of the request (via
+	// the GetBlobStatus API).
+	RequestId []byte `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The signature of the disperser over sha3(request_id || requested_at), attesting the
+	// blob was received at requested_at. It is empty if the disperser has no attestation key.
+	AttestationSignature []byte `protobuf:"bytes,3,opt,name=attestation_signature,json=attestationSignature,proto3" json:"attestation_signature,omitempty"`
+	// The public key of the disperser attestation key, compressed secp256k1 format.
+	AttestationPubkey []byte `protobuf:"bytes,4,opt,name=attestation_pubkey,json=attestationPubkey,proto3" json:"attestation_pubkey,omitempty"`
+	// The time at which the disperser received the blob, in nanoseconds since the unix epoch.
+	RequestedAt uint64 `protobuf:"varint,5,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	// The ABI encoded receipt of the blob, for smart contracts to verify the blob was accepted:
+	// struct DisperserReceipt { bytes32 metadataHash; uint64 requestedAt; bytes32 blobHash; uint8[] quorumIds; }
+	// It is empty if the disperser has no attestation key.
+	Receipt []byte `protobuf:"bytes,6,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	// The signature of the disperser attestation key over keccak256(receipt).
+	ReceiptSignature []byte `protobuf:"bytes,7,opt,name=receipt_signature,json=receiptSignature,proto3" json:"receipt_signature,omitempty"`
+	// The current load of the disperser in [0, 100], i.e. how much of the system rate limit is consumed.
+	// Clients observing a load above 80 should voluntarily back off.
+	ServerLoadPercent uint32 `protobuf:"varint,8,opt,name=server_load_percent,json=serverLoadPercent,proto3" json:"server_load_percent,omitempty"`
 }
 
-func (x *BlobStatusRequest) Reset() {
-	*x = BlobStatusRequest{}
+func (x *DisperseBlobReply) Reset() {
+	*x = DisperseBlobReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -240,13 +309,13 @@ func (x *BlobStatusRequest) Reset() {
 	}
 }
 
-func (x *BlobStatusRequest) String() string {
+func (x *DisperseBlobReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlobStatusRequest) ProtoMessage() {}
+func (*DisperseBlobReply) ProtoMessage() {}
 
-func (x *BlobStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *DisperseBlobReply) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -258,31 +327,78 @@ func (x *BlobStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlobStatusRequest.ProtoReflect.Descriptor instead.
-func (*BlobStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DisperseBlobReply.ProtoReflect.Descriptor instead.
+func (*DisperseBlobReply) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *BlobStatusRequest) GetRequestId() []byte {
+func (x *DisperseBlobReply) GetResult() BlobStatus {
+	if x != nil {
+		return x.Result
+	}
+	return BlobStatus_UNKNOWN
+}
+
+func (x *DisperseBlobReply) GetRequestId() []byte {
 	if x != nil {
 		return x.RequestId
 	}
 	return nil
 }
 
-type BlobStatusReply struct {
+func (x *DisperseBlobReply) GetAttestationSignature() []byte {
+	if x != nil {
+		return x.AttestationSignature
+	}
+	return nil
+}
+
+func (x *DisperseBlobReply) GetAttestationPubkey() []byte {
+	if x != nil {
+		return x.AttestationPubkey
+	}
+	return nil
+}
+
+func (x *DisperseBlobReply) GetRequestedAt() uint64 {
+	if x != nil {
+		return x.RequestedAt
+	}
+	return 0
+}
+
+func (x *DisperseBlobReply) GetReceipt() []byte {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+func (x *DisperseBlobReply) GetReceiptSignature() []byte {
+	if x != nil {
+		return x.ReceiptSignature
+	}
+	return nil
+}
+
+func (x *DisperseBlobReply) GetServerLoadPercent() uint32 {
+	if x != nil {
+		return x.ServerLoadPercent
+	}
+	return 0
+}
+
+// DisperseBlobBatchRequest contains the blobs to disperse, at most 100.
+type DisperseBlobBatchRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The status of the blob.
-	Status BlobStatus `protobuf:"varint,1,opt,name=status,proto3,enum=disperser.BlobStatus" json:"status,omitempty"`
-	// The blob info needed for clients to confirm the blob against the ZGDA contracts.
-	Info *BlobInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	Blobs []*DisperseBlobRequest `protobuf:"bytes,1,rep,name=blobs,proto3" json:"blobs,omitempty"`
 }
 
-func (x *BlobStatusReply) Reset() {
-	*x = BlobStatusReply{}
+func (x *DisperseBlobBatchRequest) Reset() {
+	*x = DisperseBlobBatchRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -290,13 +406,13 @@ func (x *BlobStatusReply) Reset() {
 	}
 }
 
-func (x *BlobStatusReply) String() string {
+func (x *DisperseBlobBatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlobStatusReply) ProtoMessage() {}
+func (*DisperseBlobBatchRequest) ProtoMessage() {}
 
-func (x *BlobStatusReply) ProtoReflect() protoreflect.Message {
+func (x *DisperseBlobBatchRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -308,37 +424,34 @@ func (x *BlobStatusReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlobStatusReply.ProtoReflect.Descriptor instead.
-func (*BlobStatusReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use DisperseBlobBatchRequest.ProtoReflect.Descriptor instead.
+func (*DisperseBlobBatchRequest) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *BlobStatusReply) GetStatus() BlobStatus {
-	if x != nil {
-		return x.Status
-	}
-	return BlobStatus_UNKNOWN
-}
-
-func (x *BlobStatusReply) GetInfo() *BlobInfo {
+func (x *DisperseBlobBatchRequest) GetBlobs() []*DisperseBlobRequest {
 	if x != nil {
-		return x.Info
+		return x.Blobs
 	}
 	return nil
 }
 
-// RetrieveBlobRequest contains parameters to retrieve the blob.
-type RetrieveBlobRequest struct {
+// DisperseBlobBatchResult is the result of the dispersal of a blob of a DisperseBlobBatchRequest.
+type DisperseBlobBatchResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	BatchHeaderHash []byte `protobuf:"bytes,1,opt,name=batch_header_hash,json=batchHeaderHash,proto3" json:"batch_header_hash,omitempty"`
-	BlobIndex       uint32 `protobuf:"varint,2,opt,name=blob_index,json=blobIndex,proto3" json:"blob_index,omitempty"`
+	// The status of the blob, FAILED if the blob was rejected.
+	Result BlobStatus `protobuf:"varint,1,opt,name=result,proto3,enum=disperser.BlobStatus" json:"result,omitempty"`
+	// The request ID of the blob, see DisperseBlobReply. It is empty if the blob was rejected.
+	RequestId []byte `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The reason the blob was rejected, empty if the blob was accepted.
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *RetrieveBlobRequest) Reset() {
-	*x = RetrieveBlobRequest{}
+func (x *DisperseBlobBatchResult) Reset() {
+	*x = DisperseBlobBatchResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -346,13 +459,13 @@ func (x *RetrieveBlobRequest) Reset() {
 	}
 }
 
-func (x *RetrieveBlobRequest) String() string {
+func (x *DisperseBlobBatchResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RetrieveBlobRequest) ProtoMessage() {}
+func (*DisperseBlobBatchResult) ProtoMessage() {}
 
-func (x *RetrieveBlobRequest) ProtoReflect() protoreflect.Message {
+func (x *DisperseBlobBatchResult) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -364,36 +477,43 @@ func (x *RetrieveBlobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RetrieveBlobRequest.ProtoReflect.Descriptor instead.
-func (*RetrieveBlobRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DisperseBlobBatchResult.ProtoReflect.Descriptor instead.
+func (*DisperseBlobBatchResult) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RetrieveBlobRequest) GetBatchHeaderHash() []byte {
+func (x *DisperseBlobBatchResult) GetResult() BlobStatus {
 	if x != nil {
-		return x.BatchHeaderHash
+		return x.Result
+	}
+	return BlobStatus_UNKNOWN
+}
+
+func (x *DisperseBlobBatchResult) GetRequestId() []byte {
+	if x != nil {
+		return x.RequestId
 	}
 	return nil
 }
 
-func (x *RetrieveBlobRequest) GetBlobIndex() uint32 {
+func (x *DisperseBlobBatchResult) GetError() string {
 	if x != nil {
-		return x.BlobIndex
+		return x.Error
 	}
-	return 0
+	return ""
 }
 
-// RetrieveBlobReply contains the retrieved blob data
-type RetrieveBlobReply struct {
+type DisperseBlobBatchReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// The results of the blobs, in the order of the request.
+	Results []*DisperseBlobBatchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 }
 
-func (x *RetrieveBlobReply) Reset() {
-	*x = RetrieveBlobReply{}
+func (x *DisperseBlobBatchReply) Reset() {
+	*x = DisperseBlobBatchReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -401,13 +521,13 @@ func (x *RetrieveBlobReply) Reset() {
 	}
 }
 
-func (x *RetrieveBlobReply) String() string {
+func (x *DisperseBlobBatchReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RetrieveBlobReply) ProtoMessage() {}
+func (*DisperseBlobBatchReply) ProtoMessage() {}
 
-func (x *RetrieveBlobReply) ProtoReflect() protoreflect.Message {
+func (x *DisperseBlobBatchReply) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -419,57 +539,33 @@ func (x *RetrieveBlobReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RetrieveBlobReply.ProtoReflect.Descriptor instead.
-func (*RetrieveBlobReply) Descriptor() ([]byte, []int) {
+// Deprecated: Use DisperseBlobBatchReply.ProtoReflect.Descriptor instead.
+func (*DisperseBlobBatchReply) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *RetrieveBlobReply) GetData() []byte {
+func (x *DisperseBlobBatchReply) GetResults() []*DisperseBlobBatchResult {
 	if x != nil {
-		return x.Data
+		return x.Results
 	}
 	return nil
 }
 
-// SecurityParams contains the security parameters for a given quorum.
-type SecurityParams struct {
+// BlobStatusRequest is used to query the status of a blob.
+type BlobStatusRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The ID of the quorum.
-	// The quorum must be already registered on EigenLayer. The ID must be
-	// in range [0, 255].
-	QuorumId uint32 `protobuf:"varint,1,opt,name=quorum_id,json=quorumId,proto3" json:"quorum_id,omitempty"`
-	// The max percentage of stake within the quorum that can be held by or delegated
-	// to adversarial operators.
-	//
-	// Clients use this to customize the trust assumption (safety).
-	//
-	// Requires: 1 <= adversary_threshold < 100
-	AdversaryThreshold uint32 `protobuf:"varint,2,opt,name=adversary_threshold,json=adversaryThreshold,proto3" json:"adversary_threshold,omitempty"`
-	// The min percentage of stake that must attest in order to consider
-	// the dispersal is successful.
-	//
-	// Clients use this to customize liveness requirement. The higher this number,
-	// the more operators may need to be up for attesting the blob, so the chance
-	// the dispersal request to fail may be higher (liveness for dispersal).
-	//
-	// Requires:
-	//
-	//	1 <= quorum_threshld <= 100
-	//	quorum_threshld > adversary_threshold + 10.
-	//
-	// Note: The adversary_threshold and quorum_threshold will directly influence the
-	// cost of encoding for the blob to be dispersed, roughly by a factor of
-	// 100 / (quorum_threshold - adversary_threshold). See the spec for more details:
-	// https://github.com/0glabs/0g-data-avail/blob/master/docs/spec/protocol-modules/storage/overview.md
-	// Currently it's required that the difference must be at least 10.
-	QuorumThreshold uint32 `protobuf:"varint,3,opt,name=quorum_threshold,json=quorumThreshold,proto3" json:"quorum_threshold,omitempty"`
+	RequestId []byte `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Optional location of the blob on chain. When the blob is not known to this disperser and the
+	// on-chain fallback is enabled, the confirmation is read from the contract at this location.
+	BatchHeaderHash []byte `protobuf:"bytes,2,opt,name=batch_header_hash,json=batchHeaderHash,proto3" json:"batch_header_hash,omitempty"`
+	BlobIndex       uint32 `protobuf:"varint,3,opt,name=blob_index,json=blobIndex,proto3" json:"blob_index,omitempty"`
 }
 
-func (x *SecurityParams) Reset() {
-	*x = SecurityParams{}
+func (x *BlobStatusRequest) Reset() {
+	*x = BlobStatusRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -477,13 +573,13 @@ func (x *SecurityParams) Reset() {
 	}
 }
 
-func (x *SecurityParams) String() string {
+func (x *BlobStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecurityParams) ProtoMessage() {}
+func (*BlobStatusRequest) ProtoMessage() {}
 
-func (x *SecurityParams) ProtoReflect() protoreflect.Message {
+func (x *BlobStatusRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -495,44 +591,51 @@ func (x *SecurityParams) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecurityParams.ProtoReflect.Descriptor instead.
-func (*SecurityParams) Descriptor() ([]byte, []int) {
+// Deprecated: Use BlobStatusRequest.ProtoReflect.Descriptor instead.
+func (*BlobStatusRequest) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SecurityParams) GetQuorumId() uint32 {
+func (x *BlobStatusRequest) GetRequestId() []byte {
 	if x != nil {
-		return x.QuorumId
+		return x.RequestId
 	}
-	return 0
+	return nil
 }
 
-func (x *SecurityParams) GetAdversaryThreshold() uint32 {
+func (x *BlobStatusRequest) GetBatchHeaderHash() []byte {
 	if x != nil {
-		return x.AdversaryThreshold
+		return x.BatchHeaderHash
 	}
-	return 0
+	return nil
 }
 
-func (x *SecurityParams) GetQuorumThreshold() uint32 {
+func (x *BlobStatusRequest) GetBlobIndex() uint32 {
 	if x != nil {
-		return x.QuorumThreshold
+		return x.BlobIndex
 	}
 	return 0
 }
 
-// BlobInfo contains information needed to confirm the blob against the ZGDA contracts
-type BlobInfo struct {
+type BlobStatusReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	BlobHeader            *BlobHeader            `protobuf:"bytes,1,opt,name=blob_header,json=blobHeader,proto3" json:"blob_header,omitempty"`
-	BlobVerificationProof *BlobVerificationProof `protobuf:"bytes,2,opt,name=blob_verification_proof,json=blobVerificationProof,proto3" json:"blob_verification_proof,omitempty"`
+	// The status of the blob.
+	Status BlobStatus `protobuf:"varint,1,opt,name=status,proto3,enum=disperser.BlobStatus" json:"status,omitempty"`
+	// The blob info needed for clients to confirm the blob against the ZGDA contracts.
+	Info *BlobInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	// The acknowledgements of the storage nodes which stored the blob, only set once
+	// the disperser collected them after the batch was confirmed.
+	StorageNodeReceipts []*StorageNodeReceipt `protobuf:"bytes,3,rep,name=storage_node_receipts,json=storageNodeReceipts,proto3" json:"storage_node_receipts,omitempty"`
+	// The transitions of the status of the blob, oldest first. At most 50 are kept,
+	// the last one being replaced by the latest transition once there are 50.
+	History []*BlobStatusEvent `protobuf:"bytes,4,rep,name=history,proto3" json:"history,omitempty"`
 }
 
-func (x *BlobInfo) Reset() {
-	*x = BlobInfo{}
+func (x *BlobStatusReply) Reset() {
+	*x = BlobStatusReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -540,13 +643,13 @@ func (x *BlobInfo) Reset() {
 	}
 }
 
-func (x *BlobInfo) String() string {
+func (x *BlobStatusReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlobInfo) ProtoMessage() {}
+func (*BlobStatusReply) ProtoMessage() {}
 
-func (x *BlobInfo) ProtoReflect() protoreflect.Message {
+func (x *BlobStatusReply) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -558,40 +661,55 @@ func (x *BlobInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlobInfo.ProtoReflect.Descriptor instead.
-func (*BlobInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use BlobStatusReply.ProtoReflect.Descriptor instead.
+func (*BlobStatusReply) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *BlobInfo) GetBlobHeader() *BlobHeader {
+func (x *BlobStatusReply) GetStatus() BlobStatus {
 	if x != nil {
-		return x.BlobHeader
+		return x.Status
+	}
+	return BlobStatus_UNKNOWN
+}
+
+func (x *BlobStatusReply) GetInfo() *BlobInfo {
+	if x != nil {
+		return x.Info
 	}
 	return nil
 }
 
-func (x *BlobInfo) GetBlobVerificationProof() *BlobVerificationProof {
+func (x *BlobStatusReply) GetStorageNodeReceipts() []*StorageNodeReceipt {
 	if x != nil {
-		return x.BlobVerificationProof
+		return x.StorageNodeReceipts
 	}
 	return nil
 }
 
-type BlobHeader struct {
+func (x *BlobStatusReply) GetHistory() []*BlobStatusEvent {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+// BlobStatusEvent is a transition of the status of a blob.
+type BlobStatusEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Merkle root of KZG commitments of blob.
-	CommitmentRoot []byte `protobuf:"bytes,1,opt,name=commitment_root,json=commitmentRoot,proto3" json:"commitment_root,omitempty"`
-	// The length of the blob in symbols (each symbol is 31 bytes).
-	DataLength uint32 `protobuf:"varint,2,opt,name=data_length,json=dataLength,proto3" json:"data_length,omitempty"`
-	// The params of the quorums that this blob participates in.
-	BlobQuorumParams []*BlobQuorumParam `protobuf:"bytes,3,rep,name=blob_quorum_params,json=blobQuorumParams,proto3" json:"blob_quorum_params,omitempty"`
+	Status BlobStatus `protobuf:"varint,1,opt,name=status,proto3,enum=disperser.BlobStatus" json:"status,omitempty"`
+	// The time of the transition, in nanoseconds since the unix epoch.
+	Timestamp uint64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Why the status changed, e.g. the retry of a failed dispersal. It is empty for the
+	// transitions of the normal flow.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-func (x *BlobHeader) Reset() {
-	*x = BlobHeader{}
+func (x *BlobStatusEvent) Reset() {
+	*x = BlobStatusEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -599,13 +717,13 @@ func (x *BlobHeader) Reset() {
 	}
 }
 
-func (x *BlobHeader) String() string {
+func (x *BlobStatusEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlobHeader) ProtoMessage() {}
+func (*BlobStatusEvent) ProtoMessage() {}
 
-func (x *BlobHeader) ProtoReflect() protoreflect.Message {
+func (x *BlobStatusEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -617,49 +735,43 @@ func (x *BlobHeader) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlobHeader.ProtoReflect.Descriptor instead.
-func (*BlobHeader) Descriptor() ([]byte, []int) {
+// Deprecated: Use BlobStatusEvent.ProtoReflect.Descriptor instead.
+func (*BlobStatusEvent) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *BlobHeader) GetCommitmentRoot() []byte {
+func (x *BlobStatusEvent) GetStatus() BlobStatus {
 	if x != nil {
-		return x.CommitmentRoot
+		return x.Status
 	}
-	return nil
+	return BlobStatus_UNKNOWN
 }
 
-func (x *BlobHeader) GetDataLength() uint32 {
+func (x *BlobStatusEvent) GetTimestamp() uint64 {
 	if x != nil {
-		return x.DataLength
+		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *BlobHeader) GetBlobQuorumParams() []*BlobQuorumParam {
+func (x *BlobStatusEvent) GetReason() string {
 	if x != nil {
-		return x.BlobQuorumParams
+		return x.Reason
 	}
-	return nil
+	return ""
 }
 
-type BlobQuorumParam struct {
+// BulkBlobStatusRequest contains the request IDs of the blobs, at most 100.
+type BulkBlobStatusRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The ID of the quorum.
-	QuorumNumber uint32 `protobuf:"varint,1,opt,name=quorum_number,json=quorumNumber,proto3" json:"quorum_number,omitempty"`
-	// Same as SecurityParams.adversary_threshold.
-	AdversaryThresholdPercentage uint32 `protobuf:"varint,2,opt,name=adversary_threshold_percentage,json=adversaryThresholdPercentage,proto3" json:"adversary_threshold_percentage,omitempty"`
-	// Same as SecurityParams.quorum_threshold.
-	QuorumThresholdPercentage uint32 `protobuf:"varint,3,opt,name=quorum_threshold_percentage,json=quorumThresholdPercentage,proto3" json:"quorum_threshold_percentage,omitempty"`
-	// The length of each chunk.
-	ChunkLength uint32 `protobuf:"varint,4,opt,name=chunk_length,json=chunkLength,proto3" json:"chunk_length,omitempty"`
+	RequestIds [][]byte `protobuf:"bytes,1,rep,name=request_ids,json=requestIds,proto3" json:"request_ids,omitempty"`
 }
 
-func (x *BlobQuorumParam) Reset() {
-	*x = BlobQuorumParam{}
+func (x *BulkBlobStatusRequest) Reset() {
+	*x = BulkBlobStatusRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -667,13 +779,13 @@ func (x *BlobQuorumParam) Reset() {
 	}
 }
 
-func (x *BlobQuorumParam) String() string {
+func (x *BulkBlobStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlobQuorumParam) ProtoMessage() {}
+func (*BulkBlobStatusRequest) ProtoMessage() {}
 
-func (x *BlobQuorumParam) ProtoReflect() protoreflect.Message {
+func (x *BulkBlobStatusRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -685,75 +797,29 @@ func (x *BlobQuorumParam) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlobQuorumParam.ProtoReflect.Descriptor instead.
-func (*BlobQuorumParam) Descriptor() ([]byte, []int) {
+// Deprecated: Use BulkBlobStatusRequest.ProtoReflect.Descriptor instead.
+func (*BulkBlobStatusRequest) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *BlobQuorumParam) GetQuorumNumber() uint32 {
-	if x != nil {
-		return x.QuorumNumber
-	}
-	return 0
-}
-
-func (x *BlobQuorumParam) GetAdversaryThresholdPercentage() uint32 {
-	if x != nil {
-		return x.AdversaryThresholdPercentage
-	}
-	return 0
-}
-
-func (x *BlobQuorumParam) GetQuorumThresholdPercentage() uint32 {
-	if x != nil {
-		return x.QuorumThresholdPercentage
-	}
-	return 0
-}
-
-func (x *BlobQuorumParam) GetChunkLength() uint32 {
+func (x *BulkBlobStatusRequest) GetRequestIds() [][]byte {
 	if x != nil {
-		return x.ChunkLength
+		return x.RequestIds
 	}
-	return 0
+	return nil
 }
 
-type BlobVerificationProof struct {
+type BulkBlobStatusReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// batch_id is an incremental ID assigned to a batch by ZGDAServiceManager
-	BatchId uint32 `protobuf:"varint,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
-	// The index of the blob in the batch (which is logically an ordered list of blobs).
-	BlobIndex     uint32         `protobuf:"varint,2,opt,name=blob_index,json=blobIndex,proto3" json:"blob_index,omitempty"`
-	BatchMetadata *BatchMetadata `protobuf:"bytes,3,opt,name=batch_metadata,json=batchMetadata,proto3" json:"batch_metadata,omitempty"`
-	// inclusion_proof is a merkle proof for a blob header's inclusion in a batch
-	InclusionProof []byte `protobuf:"bytes,4,opt,name=inclusion_proof,json=inclusionProof,proto3" json:"inclusion_proof,omitempty"`
-	// indexes of quorums in BatchHeader.quorum_numbers that match the quorums in BlobHeader.blob_quorum_params
-	// Ex. BlobHeader.blob_quorum_params = [
-	//
-	//	{
-	//		quorum_number = 0,
-	//		...
-	//	},
-	//	{
-	//		quorum_number = 3,
-	//		...
-	//	},
-	//	{
-	//		quorum_number = 5,
-	//		...
-	//	},
-	//
-	// ]
-	// BatchHeader.quorum_numbers = [0, 5, 3] => 0x000503
-	// Then, quorum_indexes = [0, 2, 1] => 0x000201
-	QuorumIndexes []byte `protobuf:"bytes,5,opt,name=quorum_indexes,json=quorumIndexes,proto3" json:"quorum_indexes,omitempty"`
+	// The statuses of the blobs, in the order of the request.
+	Statuses []*BlobStatusReply `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
 }
 
-func (x *BlobVerificationProof) Reset() {
-	*x = BlobVerificationProof{}
+func (x *BulkBlobStatusReply) Reset() {
+	*x = BulkBlobStatusReply{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_disperser_disperser_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -761,13 +827,13 @@ func (x *BlobVerificationProof) Reset() {
 	}
 }
 
-func (x *BlobVerificationProof) String() string {
+func (x *BulkBlobStatusReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlobVerificationProof) ProtoMessage() {}
+func (*BulkBlobStatusReply) ProtoMessage() {}
 
-func (x *BlobVerificationProof) ProtoReflect() protoreflect.Message {
+func (x *BulkBlobStatusReply) ProtoReflect() protoreflect.Message {
 	mi := &file_disperser_disperser_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -779,82 +845,99 @@ func (x *BlobVerificationProof) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlobVerificationProof.ProtoReflect.Descriptor instead.
-func (*BlobVerificationProof) Descriptor() ([]byte, []int) {
+// Deprecated: Use BulkBlobStatusReply.ProtoReflect.Descriptor instead.
+func (*BulkBlobStatusReply) Descriptor() ([]byte, []int) {
 	return file_disperser_disperser_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *BlobVerificationProof) GetBatchId() uint32 {
+func (x *BulkBlobStatusReply) GetStatuses() []*BlobStatusReply {
 	if x != nil {
-		return x.BatchId
+		return x.Statuses
 	}
-	return 0
+	return nil
 }
 
-func (x *BlobVerificationProof) GetBlobIndex() uint32 {
-	if x != nil {
-		return x.BlobIndex
+// RegisterBlobCallbackRequest is used to be notified of the final status of a blob.
+type RegisterBlobCallbackRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The request_id of the blob, as returned by DisperseBlob.
+	RequestId []byte `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The URL the final blob status is POSTed to.
+	CallbackUrl string `protobuf:"bytes,2,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+}
+
+func (x *RegisterBlobCallbackRequest) Reset() {
+	*x = RegisterBlobCallbackRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *BlobVerificationProof) GetBatchMetadata() *BatchMetadata {
-	if x != nil {
-		return x.BatchMetadata
+func (x *RegisterBlobCallbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterBlobCallbackRequest) ProtoMessage() {}
+
+func (x *RegisterBlobCallbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *BlobVerificationProof) GetInclusionProof() []byte {
+// Deprecated: Use RegisterBlobCallbackRequest.ProtoReflect.Descriptor instead.
+func (*RegisterBlobCallbackRequest) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RegisterBlobCallbackRequest) GetRequestId() []byte {
 	if x != nil {
-		return x.InclusionProof
+		return x.RequestId
 	}
 	return nil
 }
 
-func (x *BlobVerificationProof) GetQuorumIndexes() []byte {
+func (x *RegisterBlobCallbackRequest) GetCallbackUrl() string {
 	if x != nil {
-		return x.QuorumIndexes
+		return x.CallbackUrl
 	}
-	return nil
+	return ""
 }
 
-type BatchMetadata struct {
+type RegisterBlobCallbackReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	BatchHeader *BatchHeader `protobuf:"bytes,1,opt,name=batch_header,json=batchHeader,proto3" json:"batch_header,omitempty"`
-	// The hash of all public keys of the operators that did not sign the batch.
-	SignatoryRecordHash []byte `protobuf:"bytes,2,opt,name=signatory_record_hash,json=signatoryRecordHash,proto3" json:"signatory_record_hash,omitempty"`
-	// The fee payment paid by users for dispersing this batch. It's the bytes
-	// representation of a big.Int value.
-	Fee []byte `protobuf:"bytes,3,opt,name=fee,proto3" json:"fee,omitempty"`
-	// The Ethereum block number at which the batch is confirmed onchain.
-	ConfirmationBlockNumber uint32 `protobuf:"varint,4,opt,name=confirmation_block_number,json=confirmationBlockNumber,proto3" json:"confirmation_block_number,omitempty"`
-	// This is the hash of the ReducedBatchHeader defined onchain, see:
-	// https://github.com/0glabs/0g-data-avail/blob/master/contracts/src/interfaces/IZGDAServiceManager.sol#L43
-	// The is the message that the operators will sign their signatures on.
-	BatchHeaderHash []byte `protobuf:"bytes,5,opt,name=batch_header_hash,json=batchHeaderHash,proto3" json:"batch_header_hash,omitempty"`
 }
 
-func (x *BatchMetadata) Reset() {
-	*x = BatchMetadata{}
+func (x *RegisterBlobCallbackReply) Reset() {
+	*x = RegisterBlobCallbackReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_disperser_disperser_proto_msgTypes[11]
+		mi := &file_disperser_disperser_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BatchMetadata) String() string {
+func (x *RegisterBlobCallbackReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BatchMetadata) ProtoMessage() {}
+func (*RegisterBlobCallbackReply) ProtoMessage() {}
 
-func (x *BatchMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_disperser_disperser_proto_msgTypes[11]
+func (x *RegisterBlobCallbackReply) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -865,81 +948,87 @@ func (x *BatchMetadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BatchMetadata.ProtoReflect.Descriptor instead.
-func (*BatchMetadata) Descriptor() ([]byte, []int) {
-	return file_disperser_disperser_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use RegisterBlobCallbackReply.ProtoReflect.Descriptor instead.
+func (*RegisterBlobCallbackReply) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *BatchMetadata) GetBatchHeader() *BatchHeader {
-	if x != nil {
-		return x.BatchHeader
-	}
-	return nil
+// CancelBlobRequest is used to cancel the dispersal of a blob.
+type CancelBlobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The request_id of the blob, as returned by DisperseBlob.
+	RequestId []byte `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *BatchMetadata) GetSignatoryRecordHash() []byte {
-	if x != nil {
-		return x.SignatoryRecordHash
+func (x *CancelBlobRequest) Reset() {
+	*x = CancelBlobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *BatchMetadata) GetFee() []byte {
-	if x != nil {
-		return x.Fee
-	}
-	return nil
+func (x *CancelBlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *BatchMetadata) GetConfirmationBlockNumber() uint32 {
-	if x != nil {
-		return x.ConfirmationBlockNumber
+func (*CancelBlobRequest) ProtoMessage() {}
+
+func (x *CancelBlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *BatchMetadata) GetBatchHeaderHash() []byte {
+// Deprecated: Use CancelBlobRequest.ProtoReflect.Descriptor instead.
+func (*CancelBlobRequest) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CancelBlobRequest) GetRequestId() []byte {
 	if x != nil {
-		return x.BatchHeaderHash
+		return x.RequestId
 	}
 	return nil
 }
 
-type BatchHeader struct {
+type CancelBlobReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The root of the merkle tree with the hashes of blob headers as leaves.
-	BatchRoot []byte `protobuf:"bytes,1,opt,name=batch_root,json=batchRoot,proto3" json:"batch_root,omitempty"`
-	// All quorums associated with blobs in this batch.
-	QuorumNumbers []byte `protobuf:"bytes,2,opt,name=quorum_numbers,json=quorumNumbers,proto3" json:"quorum_numbers,omitempty"`
-	// The percentage of stake that has signed for this batch.
-	// The quorum_signed_percentages[i] is percentage for the quorum_numbers[i].
-	QuorumSignedPercentages []byte `protobuf:"bytes,3,opt,name=quorum_signed_percentages,json=quorumSignedPercentages,proto3" json:"quorum_signed_percentages,omitempty"`
-	// The Ethereum block number at which the batch was created.
-	// The Disperser will encode and disperse the blobs based on the onchain info
-	// (e.g. operator stakes) at this block number.
-	ReferenceBlockNumber uint32 `protobuf:"varint,4,opt,name=reference_block_number,json=referenceBlockNumber,proto3" json:"reference_block_number,omitempty"`
+	// The status of the blob once cancelled: FAILED, or INSUFFICIENT_SIGNATURES if
+	// it had already failed that way.
+	Status BlobStatus `protobuf:"varint,1,opt,name=status,proto3,enum=disperser.BlobStatus" json:"status,omitempty"`
 }
 
-func (x *BatchHeader) Reset() {
-	*x = BatchHeader{}
+func (x *CancelBlobReply) Reset() {
+	*x = CancelBlobReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_disperser_disperser_proto_msgTypes[12]
+		mi := &file_disperser_disperser_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BatchHeader) String() string {
+func (x *CancelBlobReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BatchHeader) ProtoMessage() {}
+func (*CancelBlobReply) ProtoMessage() {}
 
-func (x *BatchHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_disperser_disperser_proto_msgTypes[12]
+func (x *CancelBlobReply) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -950,16 +1039,1362 @@ func (x *BatchHeader) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BatchHeader.ProtoReflect.Descriptor instead.
-func (*BatchHeader) Descriptor() ([]byte, []int) {
-	return file_disperser_disperser_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use CancelBlobReply.ProtoReflect.Descriptor instead.
+func (*CancelBlobReply) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *BatchHeader) GetBatchRoot() []byte {
+func (x *CancelBlobReply) GetStatus() BlobStatus {
 	if x != nil {
-		return x.BatchRoot
+		return x.Status
 	}
-	return nil
+	return BlobStatus_UNKNOWN
+}
+
+// RequeueBlobRequest is used to disperse a failed blob again.
+type RequeueBlobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The request_id of the failed blob, as returned by DisperseBlob.
+	RequestId []byte `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+}
+
+func (x *RequeueBlobRequest) Reset() {
+	*x = RequeueBlobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequeueBlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueBlobRequest) ProtoMessage() {}
+
+func (x *RequeueBlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueBlobRequest.ProtoReflect.Descriptor instead.
+func (*RequeueBlobRequest) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RequeueBlobRequest) GetRequestId() []byte {
+	if x != nil {
+		return x.RequestId
+	}
+	return nil
+}
+
+type RequeueBlobReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The request_id of the new dispersal of the blob.
+	RequestId []byte `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+}
+
+func (x *RequeueBlobReply) Reset() {
+	*x = RequeueBlobReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequeueBlobReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueBlobReply) ProtoMessage() {}
+
+func (x *RequeueBlobReply) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueBlobReply.ProtoReflect.Descriptor instead.
+func (*RequeueBlobReply) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RequeueBlobReply) GetRequestId() []byte {
+	if x != nil {
+		return x.RequestId
+	}
+	return nil
+}
+
+// ExtendBlobTTLRequest is used to extend the retention of a blob.
+type ExtendBlobTTLRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The request_id of the blob, as returned by DisperseBlob.
+	RequestId []byte `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The duration added to the current expiry of the blob, in seconds.
+	ExtensionSeconds uint64 `protobuf:"varint,2,opt,name=extension_seconds,json=extensionSeconds,proto3" json:"extension_seconds,omitempty"`
+}
+
+func (x *ExtendBlobTTLRequest) Reset() {
+	*x = ExtendBlobTTLRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendBlobTTLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendBlobTTLRequest) ProtoMessage() {}
+
+func (x *ExtendBlobTTLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendBlobTTLRequest.ProtoReflect.Descriptor instead.
+func (*ExtendBlobTTLRequest) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExtendBlobTTLRequest) GetRequestId() []byte {
+	if x != nil {
+		return x.RequestId
+	}
+	return nil
+}
+
+func (x *ExtendBlobTTLRequest) GetExtensionSeconds() uint64 {
+	if x != nil {
+		return x.ExtensionSeconds
+	}
+	return 0
+}
+
+type ExtendBlobTTLReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The new expiry of the blob, in unix seconds. It is 0 if the blob never expires.
+	Expiry uint64 `protobuf:"varint,1,opt,name=expiry,proto3" json:"expiry,omitempty"`
+}
+
+func (x *ExtendBlobTTLReply) Reset() {
+	*x = ExtendBlobTTLReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendBlobTTLReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendBlobTTLReply) ProtoMessage() {}
+
+func (x *ExtendBlobTTLReply) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendBlobTTLReply.ProtoReflect.Descriptor instead.
+func (*ExtendBlobTTLReply) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExtendBlobTTLReply) GetExpiry() uint64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+type GetRateLimitStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The quorums to return the buckets of. The buckets of all the rate limited quorums are returned if empty.
+	QuorumIds []uint32 `protobuf:"varint,1,rep,packed,name=quorum_ids,json=quorumIds,proto3" json:"quorum_ids,omitempty"`
+}
+
+func (x *GetRateLimitStatusRequest) Reset() {
+	*x = GetRateLimitStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRateLimitStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitStatusRequest) ProtoMessage() {}
+
+func (x *GetRateLimitStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetRateLimitStatusRequest) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetRateLimitStatusRequest) GetQuorumIds() []uint32 {
+	if x != nil {
+		return x.QuorumIds
+	}
+	return nil
+}
+
+type GetRateLimitStatusReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The buckets of the caller, two per quorum: one limiting the bytes and one limiting the blobs.
+	Buckets []*RateBucketStatus `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	// The time scales of the buckets, in milliseconds. Each bucket has a level per time scale.
+	BucketSizesMs []uint64 `protobuf:"varint,2,rep,packed,name=bucket_sizes_ms,json=bucketSizesMs,proto3" json:"bucket_sizes_ms,omitempty"`
+	// The relaxation of the rate at each time scale, the rate applied at the i-th scale is rate * multipliers[i].
+	Multipliers []float32 `protobuf:"fixed32,3,rep,packed,name=multipliers,proto3" json:"multipliers,omitempty"`
+	// Whether rejected requests consume the buckets too.
+	CountFailed bool `protobuf:"varint,4,opt,name=count_failed,json=countFailed,proto3" json:"count_failed,omitempty"`
+	// The number of requests per second allowed regardless of the blob size, 0 if unlimited.
+	RequestsPerSecond float64 `protobuf:"fixed64,5,opt,name=requests_per_second,json=requestsPerSecond,proto3" json:"requests_per_second,omitempty"`
+}
+
+func (x *GetRateLimitStatusReply) Reset() {
+	*x = GetRateLimitStatusReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRateLimitStatusReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitStatusReply) ProtoMessage() {}
+
+func (x *GetRateLimitStatusReply) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitStatusReply.ProtoReflect.Descriptor instead.
+func (*GetRateLimitStatusReply) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetRateLimitStatusReply) GetBuckets() []*RateBucketStatus {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetRateLimitStatusReply) GetBucketSizesMs() []uint64 {
+	if x != nil {
+		return x.BucketSizesMs
+	}
+	return nil
+}
+
+func (x *GetRateLimitStatusReply) GetMultipliers() []float32 {
+	if x != nil {
+		return x.Multipliers
+	}
+	return nil
+}
+
+func (x *GetRateLimitStatusReply) GetCountFailed() bool {
+	if x != nil {
+		return x.CountFailed
+	}
+	return false
+}
+
+func (x *GetRateLimitStatusReply) GetRequestsPerSecond() float64 {
+	if x != nil {
+		return x.RequestsPerSecond
+	}
+	return 0
+}
+
+// RateBucketStatus is the state of a rate limit bucket. A bucket is refilled by one millisecond per millisecond, up to
+// its size, and a request of n bytes (or one blob) consumes n / (rate * multiplier) seconds. A request is allowed while
+// the level of every time scale is above 0.
+type RateBucketStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuorumId uint32 `protobuf:"varint,1,opt,name=quorum_id,json=quorumId,proto3" json:"quorum_id,omitempty"`
+	// "bytes" or "blobs".
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// The rate of the bucket, in bytes per second or blobs per second.
+	Rate float64 `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	// The level of each time scale as of now, in milliseconds.
+	BucketLevelsMs []uint64 `protobuf:"varint,4,rep,packed,name=bucket_levels_ms,json=bucketLevelsMs,proto3" json:"bucket_levels_ms,omitempty"`
+	// The level of each time scale of the request count limit, in milliseconds. Empty if requests_per_second is 0.
+	RequestBucketLevelsMs []uint64 `protobuf:"varint,5,rep,packed,name=request_bucket_levels_ms,json=requestBucketLevelsMs,proto3" json:"request_bucket_levels_ms,omitempty"`
+	// How full each time scale is, in [0, 100].
+	FillPercentages []float64 `protobuf:"fixed64,6,rep,packed,name=fill_percentages,json=fillPercentages,proto3" json:"fill_percentages,omitempty"`
+	// The time of the last request, in unix milliseconds. It is 0 if no request was made.
+	LastRequestTimeMs uint64 `protobuf:"varint,7,opt,name=last_request_time_ms,json=lastRequestTimeMs,proto3" json:"last_request_time_ms,omitempty"`
+}
+
+func (x *RateBucketStatus) Reset() {
+	*x = RateBucketStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RateBucketStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateBucketStatus) ProtoMessage() {}
+
+func (x *RateBucketStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateBucketStatus.ProtoReflect.Descriptor instead.
+func (*RateBucketStatus) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RateBucketStatus) GetQuorumId() uint32 {
+	if x != nil {
+		return x.QuorumId
+	}
+	return 0
+}
+
+func (x *RateBucketStatus) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *RateBucketStatus) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *RateBucketStatus) GetBucketLevelsMs() []uint64 {
+	if x != nil {
+		return x.BucketLevelsMs
+	}
+	return nil
+}
+
+func (x *RateBucketStatus) GetRequestBucketLevelsMs() []uint64 {
+	if x != nil {
+		return x.RequestBucketLevelsMs
+	}
+	return nil
+}
+
+func (x *RateBucketStatus) GetFillPercentages() []float64 {
+	if x != nil {
+		return x.FillPercentages
+	}
+	return nil
+}
+
+func (x *RateBucketStatus) GetLastRequestTimeMs() uint64 {
+	if x != nil {
+		return x.LastRequestTimeMs
+	}
+	return 0
+}
+
+// RetrieveBlobRequest contains parameters to retrieve the blob.
+type RetrieveBlobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchHeaderHash []byte `protobuf:"bytes,1,opt,name=batch_header_hash,json=batchHeaderHash,proto3" json:"batch_header_hash,omitempty"`
+	BlobIndex       uint32 `protobuf:"varint,2,opt,name=blob_index,json=blobIndex,proto3" json:"blob_index,omitempty"`
+	// If set, the reply includes the Merkle inclusion proof of the blob in the batch.
+	IncludeProof bool `protobuf:"varint,3,opt,name=include_proof,json=includeProof,proto3" json:"include_proof,omitempty"`
+}
+
+func (x *RetrieveBlobRequest) Reset() {
+	*x = RetrieveBlobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetrieveBlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveBlobRequest) ProtoMessage() {}
+
+func (x *RetrieveBlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveBlobRequest.ProtoReflect.Descriptor instead.
+func (*RetrieveBlobRequest) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RetrieveBlobRequest) GetBatchHeaderHash() []byte {
+	if x != nil {
+		return x.BatchHeaderHash
+	}
+	return nil
+}
+
+func (x *RetrieveBlobRequest) GetBlobIndex() uint32 {
+	if x != nil {
+		return x.BlobIndex
+	}
+	return 0
+}
+
+func (x *RetrieveBlobRequest) GetIncludeProof() bool {
+	if x != nil {
+		return x.IncludeProof
+	}
+	return false
+}
+
+// RetrieveBlobByRequestIdRequest identifies the blob to retrieve by its request ID.
+type RetrieveBlobByRequestIdRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId []byte `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// If set, the reply includes the Merkle inclusion proof of the blob in the batch.
+	IncludeProof bool `protobuf:"varint,2,opt,name=include_proof,json=includeProof,proto3" json:"include_proof,omitempty"`
+}
+
+func (x *RetrieveBlobByRequestIdRequest) Reset() {
+	*x = RetrieveBlobByRequestIdRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetrieveBlobByRequestIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveBlobByRequestIdRequest) ProtoMessage() {}
+
+func (x *RetrieveBlobByRequestIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveBlobByRequestIdRequest.ProtoReflect.Descriptor instead.
+func (*RetrieveBlobByRequestIdRequest) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RetrieveBlobByRequestIdRequest) GetRequestId() []byte {
+	if x != nil {
+		return x.RequestId
+	}
+	return nil
+}
+
+func (x *RetrieveBlobByRequestIdRequest) GetIncludeProof() bool {
+	if x != nil {
+		return x.IncludeProof
+	}
+	return false
+}
+
+// RetrieveBlobReply contains the retrieved blob data
+type RetrieveBlobReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// The Merkle proof of the blob header in the batch, only set if include_proof is
+	// set in the request.
+	InclusionProof [][]byte `protobuf:"bytes,2,rep,name=inclusion_proof,json=inclusionProof,proto3" json:"inclusion_proof,omitempty"`
+	// The root of the Merkle tree of the blob headers in the batch.
+	BatchRoot []byte `protobuf:"bytes,3,opt,name=batch_root,json=batchRoot,proto3" json:"batch_root,omitempty"`
+	// The root of the Merkle tree of the KZG commitments of the blob, whose hash
+	// is the leaf of the blob in the batch.
+	CommitmentRoot []byte `protobuf:"bytes,4,opt,name=commitment_root,json=commitmentRoot,proto3" json:"commitment_root,omitempty"`
+}
+
+func (x *RetrieveBlobReply) Reset() {
+	*x = RetrieveBlobReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RetrieveBlobReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveBlobReply) ProtoMessage() {}
+
+func (x *RetrieveBlobReply) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveBlobReply.ProtoReflect.Descriptor instead.
+func (*RetrieveBlobReply) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RetrieveBlobReply) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *RetrieveBlobReply) GetInclusionProof() [][]byte {
+	if x != nil {
+		return x.InclusionProof
+	}
+	return nil
+}
+
+func (x *RetrieveBlobReply) GetBatchRoot() []byte {
+	if x != nil {
+		return x.BatchRoot
+	}
+	return nil
+}
+
+func (x *RetrieveBlobReply) GetCommitmentRoot() []byte {
+	if x != nil {
+		return x.CommitmentRoot
+	}
+	return nil
+}
+
+type GetAllBatchesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The maximum number of batches to return, 100 if not set and at most 1000.
+	PageSize uint32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The hash of the last batch of the previous page. The first page is returned if not set.
+	LastBatchHeaderHash []byte `protobuf:"bytes,2,opt,name=last_batch_header_hash,json=lastBatchHeaderHash,proto3" json:"last_batch_header_hash,omitempty"`
+}
+
+func (x *GetAllBatchesRequest) Reset() {
+	*x = GetAllBatchesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAllBatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllBatchesRequest) ProtoMessage() {}
+
+func (x *GetAllBatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllBatchesRequest.ProtoReflect.Descriptor instead.
+func (*GetAllBatchesRequest) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetAllBatchesRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetAllBatchesRequest) GetLastBatchHeaderHash() []byte {
+	if x != nil {
+		return x.LastBatchHeaderHash
+	}
+	return nil
+}
+
+type GetAllBatchesReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The batches following last_batch_header_hash. There are no more batches if there are fewer than page_size.
+	Batches []*BatchSummary `protobuf:"bytes,1,rep,name=batches,proto3" json:"batches,omitempty"`
+}
+
+func (x *GetAllBatchesReply) Reset() {
+	*x = GetAllBatchesReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAllBatchesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllBatchesReply) ProtoMessage() {}
+
+func (x *GetAllBatchesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllBatchesReply.ProtoReflect.Descriptor instead.
+func (*GetAllBatchesReply) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetAllBatchesReply) GetBatches() []*BatchSummary {
+	if x != nil {
+		return x.Batches
+	}
+	return nil
+}
+
+// BatchSummary describes a confirmed batch.
+type BatchSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchHeaderHash []byte `protobuf:"bytes,1,opt,name=batch_header_hash,json=batchHeaderHash,proto3" json:"batch_header_hash,omitempty"`
+	// The number of the block the batch was confirmed in.
+	ConfirmationBlockNumber uint32 `protobuf:"varint,2,opt,name=confirmation_block_number,json=confirmationBlockNumber,proto3" json:"confirmation_block_number,omitempty"`
+	// The number of blobs in the batch.
+	BlobCount uint32 `protobuf:"varint,3,opt,name=blob_count,json=blobCount,proto3" json:"blob_count,omitempty"`
+	// The total size of the blobs in the batch, in bytes.
+	TotalBytes uint64 `protobuf:"varint,4,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+}
+
+func (x *BatchSummary) Reset() {
+	*x = BatchSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSummary) ProtoMessage() {}
+
+func (x *BatchSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSummary.ProtoReflect.Descriptor instead.
+func (*BatchSummary) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BatchSummary) GetBatchHeaderHash() []byte {
+	if x != nil {
+		return x.BatchHeaderHash
+	}
+	return nil
+}
+
+func (x *BatchSummary) GetConfirmationBlockNumber() uint32 {
+	if x != nil {
+		return x.ConfirmationBlockNumber
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetBlobCount() uint32 {
+	if x != nil {
+		return x.BlobCount
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+// SecurityParams contains the security parameters for a given quorum.
+type SecurityParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ID of the quorum.
+	// The quorum must be already registered on EigenLayer. The ID must be
+	// in range [0, 255].
+	QuorumId uint32 `protobuf:"varint,1,opt,name=quorum_id,json=quorumId,proto3" json:"quorum_id,omitempty"`
+	// The max percentage of stake within the quorum that can be held by or delegated
+	// to adversarial operators.
+	//
+	// Clients use this to customize the trust assumption (safety).
+	//
+	// Requires: 1 <= adversary_threshold < 100
+	AdversaryThreshold uint32 `protobuf:"varint,2,opt,name=adversary_threshold,json=adversaryThreshold,proto3" json:"adversary_threshold,omitempty"`
+	// The min percentage of stake that must attest in order to consider
+	// the dispersal is successful.
+	//
+	// Clients use this to customize liveness requirement. The higher this number,
+	// the more operators may need to be up for attesting the blob, so the chance
+	// the dispersal request to fail may be higher (liveness for dispersal).
+	//
+	// Requires:
+	//     1 <= quorum_threshld <= 100
+	//     quorum_threshld > adversary_threshold + 10.
+	//
+	// Note: The adversary_threshold and quorum_threshold will directly influence the
+	// cost of encoding for the blob to be dispersed, roughly by a factor of
+	// 100 / (quorum_threshold - adversary_threshold). See the spec for more details:
+	// https://github.com/0glabs/0g-data-avail/blob/master/docs/spec/protocol-modules/storage/overview.md
+	// Currently it's required that the difference must be at least 10.
+	QuorumThreshold uint32 `protobuf:"varint,3,opt,name=quorum_threshold,json=quorumThreshold,proto3" json:"quorum_threshold,omitempty"`
+}
+
+func (x *SecurityParams) Reset() {
+	*x = SecurityParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecurityParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecurityParams) ProtoMessage() {}
+
+func (x *SecurityParams) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecurityParams.ProtoReflect.Descriptor instead.
+func (*SecurityParams) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SecurityParams) GetQuorumId() uint32 {
+	if x != nil {
+		return x.QuorumId
+	}
+	return 0
+}
+
+func (x *SecurityParams) GetAdversaryThreshold() uint32 {
+	if x != nil {
+		return x.AdversaryThreshold
+	}
+	return 0
+}
+
+func (x *SecurityParams) GetQuorumThreshold() uint32 {
+	if x != nil {
+		return x.QuorumThreshold
+	}
+	return 0
+}
+
+// BlobInfo contains information needed to confirm the blob against the ZGDA contracts
+type BlobInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlobHeader            *BlobHeader            `protobuf:"bytes,1,opt,name=blob_header,json=blobHeader,proto3" json:"blob_header,omitempty"`
+	BlobVerificationProof *BlobVerificationProof `protobuf:"bytes,2,opt,name=blob_verification_proof,json=blobVerificationProof,proto3" json:"blob_verification_proof,omitempty"`
+}
+
+func (x *BlobInfo) Reset() {
+	*x = BlobInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlobInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobInfo) ProtoMessage() {}
+
+func (x *BlobInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobInfo.ProtoReflect.Descriptor instead.
+func (*BlobInfo) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *BlobInfo) GetBlobHeader() *BlobHeader {
+	if x != nil {
+		return x.BlobHeader
+	}
+	return nil
+}
+
+func (x *BlobInfo) GetBlobVerificationProof() *BlobVerificationProof {
+	if x != nil {
+		return x.BlobVerificationProof
+	}
+	return nil
+}
+
+type BlobHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Merkle root of KZG commitments of blob.
+	CommitmentRoot []byte `protobuf:"bytes,1,opt,name=commitment_root,json=commitmentRoot,proto3" json:"commitment_root,omitempty"`
+	// The length of the blob in symbols (each symbol is 31 bytes).
+	DataLength uint32 `protobuf:"varint,2,opt,name=data_length,json=dataLength,proto3" json:"data_length,omitempty"`
+	// The params of the quorums that this blob participates in.
+	BlobQuorumParams []*BlobQuorumParam `protobuf:"bytes,3,rep,name=blob_quorum_params,json=blobQuorumParams,proto3" json:"blob_quorum_params,omitempty"`
+}
+
+func (x *BlobHeader) Reset() {
+	*x = BlobHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlobHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobHeader) ProtoMessage() {}
+
+func (x *BlobHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobHeader.ProtoReflect.Descriptor instead.
+func (*BlobHeader) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BlobHeader) GetCommitmentRoot() []byte {
+	if x != nil {
+		return x.CommitmentRoot
+	}
+	return nil
+}
+
+func (x *BlobHeader) GetDataLength() uint32 {
+	if x != nil {
+		return x.DataLength
+	}
+	return 0
+}
+
+func (x *BlobHeader) GetBlobQuorumParams() []*BlobQuorumParam {
+	if x != nil {
+		return x.BlobQuorumParams
+	}
+	return nil
+}
+
+type BlobQuorumParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ID of the quorum.
+	QuorumNumber uint32 `protobuf:"varint,1,opt,name=quorum_number,json=quorumNumber,proto3" json:"quorum_number,omitempty"`
+	// Same as SecurityParams.adversary_threshold.
+	AdversaryThresholdPercentage uint32 `protobuf:"varint,2,opt,name=adversary_threshold_percentage,json=adversaryThresholdPercentage,proto3" json:"adversary_threshold_percentage,omitempty"`
+	// Same as SecurityParams.quorum_threshold.
+	QuorumThresholdPercentage uint32 `protobuf:"varint,3,opt,name=quorum_threshold_percentage,json=quorumThresholdPercentage,proto3" json:"quorum_threshold_percentage,omitempty"`
+	// The length of each chunk.
+	ChunkLength uint32 `protobuf:"varint,4,opt,name=chunk_length,json=chunkLength,proto3" json:"chunk_length,omitempty"`
+}
+
+func (x *BlobQuorumParam) Reset() {
+	*x = BlobQuorumParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlobQuorumParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobQuorumParam) ProtoMessage() {}
+
+func (x *BlobQuorumParam) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobQuorumParam.ProtoReflect.Descriptor instead.
+func (*BlobQuorumParam) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BlobQuorumParam) GetQuorumNumber() uint32 {
+	if x != nil {
+		return x.QuorumNumber
+	}
+	return 0
+}
+
+func (x *BlobQuorumParam) GetAdversaryThresholdPercentage() uint32 {
+	if x != nil {
+		return x.AdversaryThresholdPercentage
+	}
+	return 0
+}
+
+func (x *BlobQuorumParam) GetQuorumThresholdPercentage() uint32 {
+	if x != nil {
+		return x.QuorumThresholdPercentage
+	}
+	return 0
+}
+
+func (x *BlobQuorumParam) GetChunkLength() uint32 {
+	if x != nil {
+		return x.ChunkLength
+	}
+	return 0
+}
+
+type BlobVerificationProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// batch_id is an incremental ID assigned to a batch by ZGDAServiceManager
+	BatchId uint32 `protobuf:"varint,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	// The index of the blob in the batch (which is logically an ordered list of blobs).
+	BlobIndex     uint32         `protobuf:"varint,2,opt,name=blob_index,json=blobIndex,proto3" json:"blob_index,omitempty"`
+	BatchMetadata *BatchMetadata `protobuf:"bytes,3,opt,name=batch_metadata,json=batchMetadata,proto3" json:"batch_metadata,omitempty"`
+	// inclusion_proof is a merkle proof for a blob header's inclusion in a batch
+	InclusionProof []byte `protobuf:"bytes,4,opt,name=inclusion_proof,json=inclusionProof,proto3" json:"inclusion_proof,omitempty"`
+	// indexes of quorums in BatchHeader.quorum_numbers that match the quorums in BlobHeader.blob_quorum_params
+	// Ex. BlobHeader.blob_quorum_params = [
+	// 	{
+	//		quorum_number = 0,
+	// 		...
+	// 	},
+	// 	{
+	//		quorum_number = 3,
+	// 		...
+	// 	},
+	// 	{
+	//		quorum_number = 5,
+	// 		...
+	// 	},
+	// ]
+	// BatchHeader.quorum_numbers = [0, 5, 3] => 0x000503
+	// Then, quorum_indexes = [0, 2, 1] => 0x000201
+	QuorumIndexes []byte `protobuf:"bytes,5,opt,name=quorum_indexes,json=quorumIndexes,proto3" json:"quorum_indexes,omitempty"`
+}
+
+func (x *BlobVerificationProof) Reset() {
+	*x = BlobVerificationProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlobVerificationProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobVerificationProof) ProtoMessage() {}
+
+func (x *BlobVerificationProof) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobVerificationProof.ProtoReflect.Descriptor instead.
+func (*BlobVerificationProof) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BlobVerificationProof) GetBatchId() uint32 {
+	if x != nil {
+		return x.BatchId
+	}
+	return 0
+}
+
+func (x *BlobVerificationProof) GetBlobIndex() uint32 {
+	if x != nil {
+		return x.BlobIndex
+	}
+	return 0
+}
+
+func (x *BlobVerificationProof) GetBatchMetadata() *BatchMetadata {
+	if x != nil {
+		return x.BatchMetadata
+	}
+	return nil
+}
+
+func (x *BlobVerificationProof) GetInclusionProof() []byte {
+	if x != nil {
+		return x.InclusionProof
+	}
+	return nil
+}
+
+func (x *BlobVerificationProof) GetQuorumIndexes() []byte {
+	if x != nil {
+		return x.QuorumIndexes
+	}
+	return nil
+}
+
+type BatchMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchHeader *BatchHeader `protobuf:"bytes,1,opt,name=batch_header,json=batchHeader,proto3" json:"batch_header,omitempty"`
+	// The hash of all public keys of the operators that did not sign the batch.
+	SignatoryRecordHash []byte `protobuf:"bytes,2,opt,name=signatory_record_hash,json=signatoryRecordHash,proto3" json:"signatory_record_hash,omitempty"`
+	// The fee payment paid by users for dispersing this batch. It's the bytes
+	// representation of a big.Int value.
+	Fee []byte `protobuf:"bytes,3,opt,name=fee,proto3" json:"fee,omitempty"`
+	// The Ethereum block number at which the batch is confirmed onchain.
+	ConfirmationBlockNumber uint32 `protobuf:"varint,4,opt,name=confirmation_block_number,json=confirmationBlockNumber,proto3" json:"confirmation_block_number,omitempty"`
+	// This is the hash of the ReducedBatchHeader defined onchain, see:
+	// https://github.com/0glabs/0g-data-avail/blob/master/contracts/src/interfaces/IZGDAServiceManager.sol#L43
+	// The is the message that the operators will sign their signatures on.
+	BatchHeaderHash []byte `protobuf:"bytes,5,opt,name=batch_header_hash,json=batchHeaderHash,proto3" json:"batch_header_hash,omitempty"`
+}
+
+func (x *BatchMetadata) Reset() {
+	*x = BatchMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchMetadata) ProtoMessage() {}
+
+func (x *BatchMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchMetadata.ProtoReflect.Descriptor instead.
+func (*BatchMetadata) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BatchMetadata) GetBatchHeader() *BatchHeader {
+	if x != nil {
+		return x.BatchHeader
+	}
+	return nil
+}
+
+func (x *BatchMetadata) GetSignatoryRecordHash() []byte {
+	if x != nil {
+		return x.SignatoryRecordHash
+	}
+	return nil
+}
+
+func (x *BatchMetadata) GetFee() []byte {
+	if x != nil {
+		return x.Fee
+	}
+	return nil
+}
+
+func (x *BatchMetadata) GetConfirmationBlockNumber() uint32 {
+	if x != nil {
+		return x.ConfirmationBlockNumber
+	}
+	return 0
+}
+
+func (x *BatchMetadata) GetBatchHeaderHash() []byte {
+	if x != nil {
+		return x.BatchHeaderHash
+	}
+	return nil
+}
+
+type BatchHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The root of the merkle tree with the hashes of blob headers as leaves.
+	BatchRoot []byte `protobuf:"bytes,1,opt,name=batch_root,json=batchRoot,proto3" json:"batch_root,omitempty"`
+	// All quorums associated with blobs in this batch.
+	QuorumNumbers []byte `protobuf:"bytes,2,opt,name=quorum_numbers,json=quorumNumbers,proto3" json:"quorum_numbers,omitempty"`
+	// The percentage of stake that has signed for this batch.
+	// The quorum_signed_percentages[i] is percentage for the quorum_numbers[i].
+	QuorumSignedPercentages []byte `protobuf:"bytes,3,opt,name=quorum_signed_percentages,json=quorumSignedPercentages,proto3" json:"quorum_signed_percentages,omitempty"`
+	// The Ethereum block number at which the batch was created.
+	// The Disperser will encode and disperse the blobs based on the onchain info
+	// (e.g. operator stakes) at this block number.
+	ReferenceBlockNumber uint32 `protobuf:"varint,4,opt,name=reference_block_number,json=referenceBlockNumber,proto3" json:"reference_block_number,omitempty"`
+}
+
+func (x *BatchHeader) Reset() {
+	*x = BatchHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchHeader) ProtoMessage() {}
+
+func (x *BatchHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchHeader.ProtoReflect.Descriptor instead.
+func (*BatchHeader) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *BatchHeader) GetBatchRoot() []byte {
+	if x != nil {
+		return x.BatchRoot
+	}
+	return nil
 }
 
 func (x *BatchHeader) GetQuorumNumbers() []byte {
@@ -983,12 +2418,79 @@ func (x *BatchHeader) GetReferenceBlockNumber() uint32 {
 	return 0
 }
 
+// StorageNodeReceipt is the acknowledgement of a storage node that it stored a confirmed batch.
+type StorageNodeReceipt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The id of the storage node.
+	NodeId string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// The signature of the storage node over the batch header hash.
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	// The unix time in seconds at which the storage node stored the batch.
+	Timestamp uint64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *StorageNodeReceipt) Reset() {
+	*x = StorageNodeReceipt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_disperser_disperser_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StorageNodeReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageNodeReceipt) ProtoMessage() {}
+
+func (x *StorageNodeReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_disperser_disperser_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageNodeReceipt.ProtoReflect.Descriptor instead.
+func (*StorageNodeReceipt) Descriptor() ([]byte, []int) {
+	return file_disperser_disperser_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *StorageNodeReceipt) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *StorageNodeReceipt) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *StorageNodeReceipt) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
 var File_disperser_disperser_proto protoreflect.FileDescriptor
 
 var file_disperser_disperser_proto_rawDesc = []byte{
 	0x0a, 0x19, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2f, 0x64, 0x69, 0x73, 0x70,
 	0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x64, 0x69, 0x73,
-	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x22, 0x93, 0x01, 0x0a, 0x13, 0x44, 0x69, 0x73, 0x70, 0x65,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x22, 0xd8, 0x01, 0x0a, 0x13, 0x44, 0x69, 0x73, 0x70, 0x65,
 	0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
 	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61,
 	0x74, 0x61, 0x12, 0x42, 0x0a, 0x0f, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x70,
@@ -997,144 +2499,398 @@ var file_disperser_disperser_proto_rawDesc = []byte{
 	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x0e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79,
 	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
 	0x5f, 0x72, 0x6f, 0x77, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c,
-	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x6f, 0x77, 0x4e, 0x75, 0x6d, 0x22, 0x61, 0x0a, 0x11,
-	0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x12, 0x2d, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c,
-	0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22,
-	0x32, 0x0a, 0x11, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x6f, 0x77, 0x4e, 0x75, 0x6d, 0x12, 0x27, 0x0a, 0x0f,
+	0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74,
+	0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74,
+	0x79, 0x22, 0xc3, 0x01, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c,
+	0x6f, 0x62, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x42, 0x0a, 0x0f, 0x73,
+	0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52,
+	0x0e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12,
+	0x24, 0x0a, 0x0e, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x6f, 0x77, 0x5f, 0x6e, 0x75,
+	0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52,
+	0x6f, 0x77, 0x4e, 0x75, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6c, 0x75, 0x73, 0x68, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6c, 0x75, 0x73, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x22, 0xdf, 0x02, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x70,
+	0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2d, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x33, 0x0a, 0x15, 0x61,
+	0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x14, 0x61, 0x74, 0x74, 0x65,
+	0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x12, 0x2d, 0x0a, 0x12, 0x61, 0x74, 0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x61, 0x74,
+	0x74, 0x65, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12,
+	0x21, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64,
+	0x41, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x12, 0x2b, 0x0a, 0x11,
+	0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74,
+	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x5f, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4c, 0x6f,
+	0x61, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0x50, 0x0a, 0x18, 0x44, 0x69, 0x73,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x22, 0x7d, 0x0a, 0x17, 0x44,
+	0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2d, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x56, 0x0a, 0x16, 0x44, 0x69,
+	0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x3c, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x22, 0x7d, 0x0a, 0x11, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x22, 0xf2, 0x01, 0x0a, 0x0f, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42,
+	0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x51, 0x0a,
+	0x15, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x72, 0x65,
+	0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x64,
+	0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x52, 0x13, 0x73, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73,
+	0x12, 0x34, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c,
+	0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x68,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x76, 0x0a, 0x0f, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x70,
+	0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x38,
+	0x0a, 0x15, 0x42, 0x75, 0x6c, 0x6b, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x73, 0x22, 0x4d, 0x0a, 0x13, 0x42, 0x75, 0x6c, 0x6b,
+	0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x36, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c,
+	0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x52, 0x08, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x22, 0x5f, 0x0a, 0x1b, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x22, 0x1b, 0x0a, 0x19, 0x52, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x32, 0x0a, 0x11, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42,
+	0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x40, 0x0a, 0x0f, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2d, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x64,
+	0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x33, 0x0a, 0x12, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x75, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64,
+	0x22, 0x31, 0x0a, 0x10, 0x52, 0x65, 0x71, 0x75, 0x65, 0x75, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x64, 0x22, 0x62, 0x0a, 0x14, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f,
+	0x62, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x2b, 0x0a, 0x11, 0x65, 0x78,
+	0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e,
+	0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x2c, 0x0a, 0x12, 0x45, 0x78, 0x74, 0x65, 0x6e,
+	0x64, 0x42, 0x6c, 0x6f, 0x62, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x79, 0x22, 0x3a, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x69, 0x64, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x09, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x49, 0x64,
+	0x73, 0x22, 0xed, 0x01, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x35, 0x0a,
+	0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x42,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x07, 0x62, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x73, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0d, 0x62,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x73, 0x4d, 0x73, 0x12, 0x20, 0x0a, 0x0b,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x02, 0x52, 0x0b, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x73, 0x12, 0x21,
+	0x0a, 0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x65,
+	0x64, 0x12, 0x2e, 0x0a, 0x13, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x5f, 0x70, 0x65,
+	0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x22, 0x96, 0x02, 0x0a, 0x10, 0x52, 0x61, 0x74, 0x65, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x71, 0x75, 0x6f, 0x72, 0x75,
+	0x6d, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x74, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x62,
+	0x75, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x5f, 0x6d, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x04, 0x52, 0x0e, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x73, 0x4d, 0x73, 0x12, 0x37, 0x0a, 0x18, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x5f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x5f, 0x6d,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x04, 0x52, 0x15, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x4d, 0x73, 0x12, 0x29,
+	0x0a, 0x10, 0x66, 0x69, 0x6c, 0x6c, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0f, 0x66, 0x69, 0x6c, 0x6c, 0x50, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x14, 0x6c, 0x61, 0x73,
+	0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6d,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x13, 0x52,
+	0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1d,
+	0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x23, 0x0a,
+	0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x22, 0x64, 0x0a, 0x1e, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c,
+	0x6f, 0x62, 0x42, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x52, 0x65, 0x71,
 	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
 	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x49, 0x64, 0x22, 0x69, 0x0a, 0x0f, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73,
-	0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e,
-	0x42, 0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x60,
-	0x0a, 0x13, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x68,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x0f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x61, 0x73,
-	0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x64, 0x65, 0x78,
-	0x22, 0x27, 0x0a, 0x11, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x89, 0x01, 0x0a, 0x0e, 0x53, 0x65,
-	0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x1b, 0x0a, 0x09,
-	0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x08, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x13, 0x61, 0x64, 0x76,
-	0x65, 0x72, 0x73, 0x61, 0x72, 0x79, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x61, 0x64, 0x76, 0x65, 0x72, 0x73, 0x61, 0x72,
-	0x79, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x71, 0x75,
-	0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x54, 0x68, 0x72, 0x65,
-	0x73, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x9c, 0x01, 0x0a, 0x08, 0x42, 0x6c, 0x6f, 0x62, 0x49, 0x6e,
-	0x66, 0x6f, 0x12, 0x36, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72,
-	0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x0a,
-	0x62, 0x6c, 0x6f, 0x62, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x58, 0x0a, 0x17, 0x62, 0x6c,
-	0x6f, 0x62, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x64, 0x69,
-	0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x56, 0x65, 0x72, 0x69,
-	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x15, 0x62,
-	0x6c, 0x6f, 0x62, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50,
-	0x72, 0x6f, 0x6f, 0x66, 0x22, 0xa0, 0x01, 0x0a, 0x0a, 0x42, 0x6c, 0x6f, 0x62, 0x48, 0x65, 0x61,
-	0x64, 0x65, 0x72, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e,
-	0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x63, 0x6f,
-	0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1f, 0x0a, 0x0b,
-	0x64, 0x61, 0x74, 0x61, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x48, 0x0a,
-	0x12, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x70, 0x61, 0x72,
-	0x61, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x70,
-	0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d,
-	0x50, 0x61, 0x72, 0x61, 0x6d, 0x52, 0x10, 0x62, 0x6c, 0x6f, 0x62, 0x51, 0x75, 0x6f, 0x72, 0x75,
-	0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0xdf, 0x01, 0x0a, 0x0f, 0x42, 0x6c, 0x6f, 0x62,
-	0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x71,
-	0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x0c, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72,
-	0x12, 0x44, 0x0a, 0x1e, 0x61, 0x64, 0x76, 0x65, 0x72, 0x73, 0x61, 0x72, 0x79, 0x5f, 0x74, 0x68,
-	0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61,
-	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1c, 0x61, 0x64, 0x76, 0x65, 0x72, 0x73,
-	0x61, 0x72, 0x79, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x50, 0x65, 0x72, 0x63,
-	0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x1b, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d,
-	0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65,
-	0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x19, 0x71, 0x75, 0x6f,
-	0x72, 0x75, 0x6d, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x50, 0x65, 0x72, 0x63,
-	0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f,
-	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x68,
-	0x75, 0x6e, 0x6b, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0xe2, 0x01, 0x0a, 0x15, 0x42, 0x6c,
+	0x74, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x98, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x74,
+	0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0e, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1d, 0x0a, 0x0a, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52,
+	0x6f, 0x6f, 0x74, 0x22, 0x68, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x33, 0x0a, 0x16, 0x6c, 0x61, 0x73, 0x74,
+	0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x6c, 0x61, 0x73, 0x74, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x61, 0x73, 0x68, 0x22, 0x47, 0x0a,
+	0x12, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x31, 0x0a, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x07, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0xb6, 0x01, 0x0a, 0x0c, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x74, 0x63, 0x68,
+	0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x3a, 0x0a, 0x19, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x17, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22,
+	0x89, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x49, 0x64, 0x12,
+	0x2f, 0x0a, 0x13, 0x61, 0x64, 0x76, 0x65, 0x72, 0x73, 0x61, 0x72, 0x79, 0x5f, 0x74, 0x68, 0x72,
+	0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x61, 0x64,
+	0x76, 0x65, 0x72, 0x73, 0x61, 0x72, 0x79, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64,
+	0x12, 0x29, 0x0a, 0x10, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73,
+	0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x71, 0x75, 0x6f, 0x72,
+	0x75, 0x6d, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x9c, 0x01, 0x0a, 0x08,
+	0x42, 0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x36, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x62,
+	0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x52, 0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x12, 0x58, 0x0a, 0x17, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x20, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c,
 	0x6f, 0x62, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72,
-	0x6f, 0x6f, 0x66, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x12, 0x1d,
-	0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x3f, 0x0a,
-	0x0e, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65,
-	0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52,
-	0x0d, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x27,
-	0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x6f,
-	0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69,
-	0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x25, 0x0a, 0x0e, 0x71, 0x75, 0x6f, 0x72, 0x75,
-	0x6d, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0d, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x22, 0xf8,
-	0x01, 0x0a, 0x0d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x12, 0x39, 0x0a, 0x0c, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73,
-	0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x0b,
-	0x62, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x15, 0x73,
-	0x69, 0x67, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f,
-	0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x73, 0x69, 0x67, 0x6e,
-	0x61, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12,
-	0x10, 0x0a, 0x03, 0x66, 0x65, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x66, 0x65,
-	0x65, 0x12, 0x3a, 0x0a, 0x19, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x17, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x2a, 0x0a,
-	0x11, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x68, 0x61,
-	0x73, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x48,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x61, 0x73, 0x68, 0x22, 0xc5, 0x01, 0x0a, 0x0b, 0x42, 0x61,
-	0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x74,
-	0x63, 0x68, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x62,
-	0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x71, 0x75, 0x6f, 0x72,
-	0x75, 0x6d, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x0d, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12,
-	0x3a, 0x0a, 0x19, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64,
-	0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x17, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64,
-	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x72,
-	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e,
-	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x72, 0x65, 0x66,
-	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65,
-	0x72, 0x2a, 0x70, 0x0a, 0x0a, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
-	0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a,
-	0x50, 0x52, 0x4f, 0x43, 0x45, 0x53, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09,
-	0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x46,
-	0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x49, 0x4e, 0x41, 0x4c,
-	0x49, 0x5a, 0x45, 0x44, 0x10, 0x04, 0x12, 0x1b, 0x0a, 0x17, 0x49, 0x4e, 0x53, 0x55, 0x46, 0x46,
-	0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45,
-	0x53, 0x10, 0x05, 0x32, 0xf8, 0x01, 0x0a, 0x09, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65,
-	0x72, 0x12, 0x4e, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f,
-	0x62, 0x12, 0x1e, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x69,
-	0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x69,
-	0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22,
-	0x00, 0x12, 0x4b, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42,
-	0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f,
-	0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4e,
-	0x0a, 0x0c, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x12, 0x1e,
-	0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x69,
-	0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
-	0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x69,
-	0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x34,
-	0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x30, 0x67, 0x6c,
-	0x61, 0x62, 0x73, 0x2f, 0x30, 0x67, 0x2d, 0x64, 0x61, 0x74, 0x61, 0x2d, 0x61, 0x76, 0x61, 0x69,
-	0x6c, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x64, 0x69, 0x73, 0x70, 0x65,
-	0x72, 0x73, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x6f, 0x66, 0x52, 0x15, 0x62, 0x6c, 0x6f, 0x62, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0xa0, 0x01, 0x0a, 0x0a, 0x42,
+	0x6c, 0x6f, 0x62, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x6f,
+	0x6f, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x4c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x12, 0x48, 0x0a, 0x12, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x71, 0x75, 0x6f, 0x72,
+	0x75, 0x6d, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62,
+	0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x52, 0x10, 0x62, 0x6c, 0x6f,
+	0x62, 0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0xdf, 0x01,
+	0x0a, 0x0f, 0x42, 0x6c, 0x6f, 0x62, 0x51, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x6e, 0x75, 0x6d, 0x62,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d,
+	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x44, 0x0a, 0x1e, 0x61, 0x64, 0x76, 0x65, 0x72, 0x73,
+	0x61, 0x72, 0x79, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1c,
+	0x61, 0x64, 0x76, 0x65, 0x72, 0x73, 0x61, 0x72, 0x79, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x1b,
+	0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64,
+	0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x19, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22,
+	0xe2, 0x01, 0x0a, 0x15, 0x42, 0x6c, 0x6f, 0x62, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x61, 0x74,
+	0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x62, 0x61, 0x74,
+	0x63, 0x68, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x62, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x3f, 0x0a, 0x0e, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x69,
+	0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x0d, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f,
+	0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x25, 0x0a,
+	0x0e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x65, 0x73, 0x22, 0xf8, 0x01, 0x0a, 0x0d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x39, 0x0a, 0x0c, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64,
+	0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x52, 0x0b, 0x62, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x32, 0x0a, 0x15, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x72,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x13, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x48, 0x61, 0x73, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x66, 0x65, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x03, 0x66, 0x65, 0x65, 0x12, 0x3a, 0x0a, 0x19, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x17, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f,
+	0x62, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x61, 0x73, 0x68, 0x22,
+	0xc5, 0x01, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
+	0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x25,
+	0x0a, 0x0e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x4e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x3a, 0x0a, 0x19, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x17, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65,
+	0x73, 0x12, 0x34, 0x0a, 0x16, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x14, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x69, 0x0a, 0x12, 0x53, 0x74, 0x6f, 0x72, 0x61,
+	0x67, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x2a, 0x70, 0x0a, 0x0a, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0e, 0x0a,
+	0x0a, 0x50, 0x52, 0x4f, 0x43, 0x45, 0x53, 0x53, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a,
+	0x09, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06,
+	0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x46, 0x49, 0x4e, 0x41,
+	0x4c, 0x49, 0x5a, 0x45, 0x44, 0x10, 0x04, 0x12, 0x1b, 0x0a, 0x17, 0x49, 0x4e, 0x53, 0x55, 0x46,
+	0x46, 0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52,
+	0x45, 0x53, 0x10, 0x05, 0x32, 0xc4, 0x09, 0x0a, 0x09, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x72, 0x12, 0x4e, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c,
+	0x6f, 0x62, 0x12, 0x1e, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44,
+	0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44,
+	0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x54, 0x0a, 0x12, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c,
+	0x6f, 0x62, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f,
+	0x62, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x28, 0x01, 0x12, 0x5d, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x70,
+	0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x23, 0x2e,
+	0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x65, 0x72,
+	0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x21, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x44,
+	0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x42, 0x6c,
+	0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x11, 0x42, 0x75, 0x6c, 0x6b, 0x47, 0x65, 0x74, 0x42,
+	0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x20, 0x2e, 0x64, 0x69, 0x73, 0x70,
+	0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x64, 0x69,
+	0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x75, 0x6c, 0x6b, 0x42, 0x6c, 0x6f, 0x62,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f, 0x0a,
+	0x0f, 0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f,
+	0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x66,
+	0x0a, 0x14, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x26, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x62, 0x43,
+	0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24,
+	0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x42, 0x6c, 0x6f, 0x62, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0a, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
+	0x42, 0x6c, 0x6f, 0x62, 0x12, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x51, 0x0a, 0x0d, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x62, 0x54, 0x54,
+	0x4c, 0x12, 0x1f, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x45, 0x78,
+	0x74, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x62, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x62, 0x54, 0x54, 0x4c, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x75, 0x65, 0x42, 0x6c,
+	0x6f, 0x62, 0x12, 0x1d, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x75, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x75, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x60, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x24, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64,
+	0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x4e, 0x0a, 0x0c, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c,
+	0x6f, 0x62, 0x12, 0x1e, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52,
+	0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52,
+	0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x22, 0x00, 0x12, 0x64, 0x0a, 0x17, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c,
+	0x6f, 0x62, 0x42, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x29, 0x2e,
+	0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65,
+	0x76, 0x65, 0x42, 0x6c, 0x6f, 0x62, 0x42, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x64, 0x69, 0x73, 0x70, 0x65,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x76, 0x65, 0x42, 0x6c, 0x6f,
+	0x62, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x41,
+	0x6c, 0x6c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x64, 0x69, 0x73, 0x70,
+	0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x64, 0x69, 0x73,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x34, 0x5a, 0x32, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x30, 0x67, 0x6c, 0x61, 0x62, 0x73,
+	0x2f, 0x30, 0x67, 0x2d, 0x64, 0x61, 0x74, 0x61, 0x2d, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x64, 0x69, 0x73, 0x70, 0x65, 0x72, 0x73, 0x65,
+	0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1150,44 +2906,100 @@ func file_disperser_disperser_proto_rawDescGZIP() []byte {
 }
 
 var file_disperser_disperser_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_disperser_disperser_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_disperser_disperser_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_disperser_disperser_proto_goTypes = []interface{}{
-	(BlobStatus)(0),               // 0: disperser.BlobStatus
-	(*DisperseBlobRequest)(nil),   // 1: disperser.DisperseBlobRequest
-	(*DisperseBlobReply)(nil),     // 2: disperser.DisperseBlobReply
-	(*BlobStatusRequest)(nil),     // 3: disperser.BlobStatusRequest
-	(*BlobStatusReply)(nil),       // 4: disperser.BlobStatusReply
-	(*RetrieveBlobRequest)(nil),   // 5: disperser.RetrieveBlobRequest
-	(*RetrieveBlobReply)(nil),     // 6: disperser.RetrieveBlobReply
-	(*SecurityParams)(nil),        // 7: disperser.SecurityParams
-	(*BlobInfo)(nil),              // 8: disperser.BlobInfo
-	(*BlobHeader)(nil),            // 9: disperser.BlobHeader
-	(*BlobQuorumParam)(nil),       // 10: disperser.BlobQuorumParam
-	(*BlobVerificationProof)(nil), // 11: disperser.BlobVerificationProof
-	(*BatchMetadata)(nil),         // 12: disperser.BatchMetadata
-	(*BatchHeader)(nil),           // 13: disperser.BatchHeader
+	(BlobStatus)(0),                        // 0: disperser.BlobStatus
+	(*DisperseBlobRequest)(nil),            // 1: disperser.DisperseBlobRequest
+	(*DisperseBlobChunk)(nil),              // 2: disperser.DisperseBlobChunk
+	(*DisperseBlobReply)(nil),              // 3: disperser.DisperseBlobReply
+	(*DisperseBlobBatchRequest)(nil),       // 4: disperser.DisperseBlobBatchRequest
+	(*DisperseBlobBatchResult)(nil),        // 5: disperser.DisperseBlobBatchResult
+	(*DisperseBlobBatchReply)(nil),         // 6: disperser.DisperseBlobBatchReply
+	(*BlobStatusRequest)(nil),              // 7: disperser.BlobStatusRequest
+	(*BlobStatusReply)(nil),                // 8: disperser.BlobStatusReply
+	(*BlobStatusEvent)(nil),                // 9: disperser.BlobStatusEvent
+	(*BulkBlobStatusRequest)(nil),          // 10: disperser.BulkBlobStatusRequest
+	(*BulkBlobStatusReply)(nil),            // 11: disperser.BulkBlobStatusReply
+	(*RegisterBlobCallbackRequest)(nil),    // 12: disperser.RegisterBlobCallbackRequest
+	(*RegisterBlobCallbackReply)(nil),      // 13: disperser.RegisterBlobCallbackReply
+	(*CancelBlobRequest)(nil),              // 14: disperser.CancelBlobRequest
+	(*CancelBlobReply)(nil),                // 15: disperser.CancelBlobReply
+	(*RequeueBlobRequest)(nil),             // 16: disperser.RequeueBlobRequest
+	(*RequeueBlobReply)(nil),               // 17: disperser.RequeueBlobReply
+	(*ExtendBlobTTLRequest)(nil),           // 18: disperser.ExtendBlobTTLRequest
+	(*ExtendBlobTTLReply)(nil),             // 19: disperser.ExtendBlobTTLReply
+	(*GetRateLimitStatusRequest)(nil),      // 20: disperser.GetRateLimitStatusRequest
+	(*GetRateLimitStatusReply)(nil),        // 21: disperser.GetRateLimitStatusReply
+	(*RateBucketStatus)(nil),               // 22: disperser.RateBucketStatus
+	(*RetrieveBlobRequest)(nil),            // 23: disperser.RetrieveBlobRequest
+	(*RetrieveBlobByRequestIdRequest)(nil), // 24: disperser.RetrieveBlobByRequestIdRequest
+	(*RetrieveBlobReply)(nil),              // 25: disperser.RetrieveBlobReply
+	(*GetAllBatchesRequest)(nil),           // 26: disperser.GetAllBatchesRequest
+	(*GetAllBatchesReply)(nil),             // 27: disperser.GetAllBatchesReply
+	(*BatchSummary)(nil),                   // 28: disperser.BatchSummary
+	(*SecurityParams)(nil),                 // 29: disperser.SecurityParams
+	(*BlobInfo)(nil),                       // 30: disperser.BlobInfo
+	(*BlobHeader)(nil),                     // 31: disperser.BlobHeader
+	(*BlobQuorumParam)(nil),                // 32: disperser.BlobQuorumParam
+	(*BlobVerificationProof)(nil),          // 33: disperser.BlobVerificationProof
+	(*BatchMetadata)(nil),                  // 34: disperser.BatchMetadata
+	(*BatchHeader)(nil),                    // 35: disperser.BatchHeader
+	(*StorageNodeReceipt)(nil),             // 36: disperser.StorageNodeReceipt
 }
 var file_disperser_disperser_proto_depIdxs = []int32{
-	7,  // 0: disperser.DisperseBlobRequest.security_params:type_name -> disperser.SecurityParams
-	0,  // 1: disperser.DisperseBlobReply.result:type_name -> disperser.BlobStatus
-	0,  // 2: disperser.BlobStatusReply.status:type_name -> disperser.BlobStatus
-	8,  // 3: disperser.BlobStatusReply.info:type_name -> disperser.BlobInfo
-	9,  // 4: disperser.BlobInfo.blob_header:type_name -> disperser.BlobHeader
-	11, // 5: disperser.BlobInfo.blob_verification_proof:type_name -> disperser.BlobVerificationProof
-	10, // 6: disperser.BlobHeader.blob_quorum_params:type_name -> disperser.BlobQuorumParam
-	12, // 7: disperser.BlobVerificationProof.batch_metadata:type_name -> disperser.BatchMetadata
-	13, // 8: disperser.BatchMetadata.batch_header:type_name -> disperser.BatchHeader
-	1,  // 9: disperser.Disperser.DisperseBlob:input_type -> disperser.DisperseBlobRequest
-	3,  // 10: disperser.Disperser.GetBlobStatus:input_type -> disperser.BlobStatusRequest
-	5,  // 11: disperser.Disperser.RetrieveBlob:input_type -> disperser.RetrieveBlobRequest
-	2,  // 12: disperser.Disperser.DisperseBlob:output_type -> disperser.DisperseBlobReply
-	4,  // 13: disperser.Disperser.GetBlobStatus:output_type -> disperser.BlobStatusReply
-	6,  // 14: disperser.Disperser.RetrieveBlob:output_type -> disperser.RetrieveBlobReply
-	12, // [12:15] is the sub-list for method output_type
-	9,  // [9:12] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	29, // 0: disperser.DisperseBlobRequest.security_params:type_name -> disperser.SecurityParams
+	29, // 1: disperser.DisperseBlobChunk.security_params:type_name -> disperser.SecurityParams
+	0,  // 2: disperser.DisperseBlobReply.result:type_name -> disperser.BlobStatus
+	1,  // 3: disperser.DisperseBlobBatchRequest.blobs:type_name -> disperser.DisperseBlobRequest
+	0,  // 4: disperser.DisperseBlobBatchResult.result:type_name -> disperser.BlobStatus
+	5,  // 5: disperser.DisperseBlobBatchReply.results:type_name -> disperser.DisperseBlobBatchResult
+	0,  // 6: disperser.BlobStatusReply.status:type_name -> disperser.BlobStatus
+	30, // 7: disperser.BlobStatusReply.info:type_name -> disperser.BlobInfo
+	36, // 8: disperser.BlobStatusReply.storage_node_receipts:type_name -> disperser.StorageNodeReceipt
+	9,  // 9: disperser.BlobStatusReply.history:type_name -> disperser.BlobStatusEvent
+	0,  // 10: disperser.BlobStatusEvent.status:type_name -> disperser.BlobStatus
+	8,  // 11: disperser.BulkBlobStatusReply.statuses:type_name -> disperser.BlobStatusReply
+	0,  // 12: disperser.CancelBlobReply.status:type_name -> disperser.BlobStatus
+	22, // 13: disperser.GetRateLimitStatusReply.buckets:type_name -> disperser.RateBucketStatus
+	28, // 14: disperser.GetAllBatchesReply.batches:type_name -> disperser.BatchSummary
+	31, // 15: disperser.BlobInfo.blob_header:type_name -> disperser.BlobHeader
+	33, // 16: disperser.BlobInfo.blob_verification_proof:type_name -> disperser.BlobVerificationProof
+	32, // 17: disperser.BlobHeader.blob_quorum_params:type_name -> disperser.BlobQuorumParam
+	34, // 18: disperser.BlobVerificationProof.batch_metadata:type_name -> disperser.BatchMetadata
+	35, // 19: disperser.BatchMetadata.batch_header:type_name -> disperser.BatchHeader
+	1,  // 20: disperser.Disperser.DisperseBlob:input_type -> disperser.DisperseBlobRequest
+	2,  // 21: disperser.Disperser.DisperseBlobStream:input_type -> disperser.DisperseBlobChunk
+	4,  // 22: disperser.Disperser.DisperseBlobBatch:input_type -> disperser.DisperseBlobBatchRequest
+	7,  // 23: disperser.Disperser.GetBlobStatus:input_type -> disperser.BlobStatusRequest
+	10, // 24: disperser.Disperser.BulkGetBlobStatus:input_type -> disperser.BulkBlobStatusRequest
+	7,  // 25: disperser.Disperser.WatchBlobStatus:input_type -> disperser.BlobStatusRequest
+	12, // 26: disperser.Disperser.RegisterBlobCallback:input_type -> disperser.RegisterBlobCallbackRequest
+	14, // 27: disperser.Disperser.CancelBlob:input_type -> disperser.CancelBlobRequest
+	18, // 28: disperser.Disperser.ExtendBlobTTL:input_type -> disperser.ExtendBlobTTLRequest
+	16, // 29: disperser.Disperser.RequeueBlob:input_type -> disperser.RequeueBlobRequest
+	20, // 30: disperser.Disperser.GetRateLimitStatus:input_type -> disperser.GetRateLimitStatusRequest
+	23, // 31: disperser.Disperser.RetrieveBlob:input_type -> disperser.RetrieveBlobRequest
+	24, // 32: disperser.Disperser.RetrieveBlobByRequestId:input_type -> disperser.RetrieveBlobByRequestIdRequest
+	26, // 33: disperser.Disperser.GetAllBatches:input_type -> disperser.GetAllBatchesRequest
+	3,  // 34: disperser.Disperser.DisperseBlob:output_type -> disperser.DisperseBlobReply
+	3,  // 35: disperser.Disperser.DisperseBlobStream:output_type -> disperser.DisperseBlobReply
+	6,  // 36: disperser.Disperser.DisperseBlobBatch:output_type -> disperser.DisperseBlobBatchReply
+	8,  // 37: disperser.Disperser.GetBlobStatus:output_type -> disperser.BlobStatusReply
+	11, // 38: disperser.Disperser.BulkGetBlobStatus:output_type -> disperser.BulkBlobStatusReply
+	8,  // 39: disperser.Disperser.WatchBlobStatus:output_type -> disperser.BlobStatusReply
+	13, // 40: disperser.Disperser.RegisterBlobCallback:output_type -> disperser.RegisterBlobCallbackReply
+	15, // 41: disperser.Disperser.CancelBlob:output_type -> disperser.CancelBlobReply
+	19, // 42: disperser.Disperser.ExtendBlobTTL:output_type -> disperser.ExtendBlobTTLReply
+	17, // 43: disperser.Disperser.RequeueBlob:output_type -> disperser.RequeueBlobReply
+	21, // 44: disperser.Disperser.GetRateLimitStatus:output_type -> disperser.GetRateLimitStatusReply
+	25, // 45: disperser.Disperser.RetrieveBlob:output_type -> disperser.RetrieveBlobReply
+	25, // 46: disperser.Disperser.RetrieveBlobByRequestId:output_type -> disperser.RetrieveBlobReply
+	27, // 47: disperser.Disperser.GetAllBatches:output_type -> disperser.GetAllBatchesReply
+	34, // [34:48] is the sub-list for method output_type
+	20, // [20:34] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_disperser_disperser_proto_init() }
@@ -1209,7 +3021,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DisperseBlobReply); i {
+			switch v := v.(*DisperseBlobChunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1221,7 +3033,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlobStatusRequest); i {
+			switch v := v.(*DisperseBlobReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1233,7 +3045,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlobStatusReply); i {
+			switch v := v.(*DisperseBlobBatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1245,7 +3057,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RetrieveBlobRequest); i {
+			switch v := v.(*DisperseBlobBatchResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1257,7 +3069,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RetrieveBlobReply); i {
+			switch v := v.(*DisperseBlobBatchReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1269,7 +3081,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SecurityParams); i {
+			switch v := v.(*BlobStatusRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1281,7 +3093,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlobInfo); i {
+			switch v := v.(*BlobStatusReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1293,7 +3105,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlobHeader); i {
+			switch v := v.(*BlobStatusEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1305,7 +3117,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlobQuorumParam); i {
+			switch v := v.(*BulkBlobStatusRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1317,7 +3129,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlobVerificationProof); i {
+			switch v := v.(*BulkBlobStatusReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1329,7 +3141,7 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BatchMetadata); i {
+			switch v := v.(*RegisterBlobCallbackRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1341,6 +3153,270 @@ func file_disperser_disperser_proto_init() {
 			}
 		}
 		file_disperser_disperser_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterBlobCallbackReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelBlobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelBlobReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequeueBlobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequeueBlobReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendBlobTTLRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendBlobTTLReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRateLimitStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRateLimitStatusReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RateBucketStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetrieveBlobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetrieveBlobByRequestIdRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetrieveBlobReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAllBatchesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAllBatchesReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecurityParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobQuorumParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobVerificationProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_disperser_disperser_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BatchHeader); i {
 			case 0:
 				return &v.state
@@ -1352,6 +3428,18 @@ func file_disperser_disperser_proto_init() {
 				return nil
 			}
 		}
+		file_disperser_disperser_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StorageNodeReceipt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1359,7 +3447,7 @@ func file_disperser_disperser_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_disperser_disperser_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   13,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
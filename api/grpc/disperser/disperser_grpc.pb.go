@@ -27,8 +27,49 @@ type DisperserClient interface {
 	// is accepted. The client could use GetBlobStatus() API to poll the the
 	// processing status of the blob.
 	DisperseBlob(ctx context.Context, in *DisperseBlobRequest, opts ...grpc.CallOption) (*DisperseBlobReply, error)
+	// This API accepts blobs too large to be sent in a single DisperseBlobRequest.
+	// The client streams the blob in chunks and sets flush on the last one, the
+	// blob is then dispersed the same way as by DisperseBlob. The blob is dropped
+	// if the stream ends before the flush.
+	DisperseBlobStream(ctx context.Context, opts ...grpc.CallOption) (Disperser_DisperseBlobStreamClient, error)
+	// This API accepts several blobs in a single request, each of them is
+	// dispersed the same way as by DisperseBlob. The reply has one result per
+	// blob, in the order of the request, and a blob failing doesn't fail the
+	// other blobs.
+	DisperseBlobBatch(ctx context.Context, in *DisperseBlobBatchRequest, opts ...grpc.CallOption) (*DisperseBlobBatchReply, error)
 	// This API is meant to be polled for the blob status.
 	GetBlobStatus(ctx context.Context, in *BlobStatusRequest, opts ...grpc.CallOption) (*BlobStatusReply, error)
+	// This API returns the status of several blobs in a single call, for
+	// clients polling many blobs. The reply has one status per request ID, in
+	// the order of the request, and a blob which can't be looked up has the
+	// UNKNOWN status rather than failing the call.
+	BulkGetBlobStatus(ctx context.Context, in *BulkBlobStatusRequest, opts ...grpc.CallOption) (*BulkBlobStatusReply, error)
+	// This API sends the current blob status, then a new reply each time the
+	// status changes, instead of the client polling GetBlobStatus. The stream
+	// ends once the blob is finalized or failed, or when the watch times out.
+	WatchBlobStatus(ctx context.Context, in *BlobStatusRequest, opts ...grpc.CallOption) (Disperser_WatchBlobStatusClient, error)
+	// This API registers a URL the disperser POSTs the GetBlobStatus reply of
+	// the blob to, JSON encoded, once the blob is finalized or failed, instead
+	// of the client polling GetBlobStatus. The URL must start with one of the
+	// prefixes allowed by the disperser.
+	RegisterBlobCallback(ctx context.Context, in *RegisterBlobCallbackRequest, opts ...grpc.CallOption) (*RegisterBlobCallbackReply, error)
+	// This API withdraws a blob which is still processing, it is marked as
+	// failed and won't be dispersed. Blobs which are already confirmed or
+	// finalized can't be cancelled.
+	CancelBlob(ctx context.Context, in *CancelBlobRequest, opts ...grpc.CallOption) (*CancelBlobReply, error)
+	// This API extends the time a blob is retained for, without submitting it
+	// again. The extension is capped by the disperser, and failed blobs can't
+	// be extended.
+	ExtendBlobTTL(ctx context.Context, in *ExtendBlobTTLRequest, opts ...grpc.CallOption) (*ExtendBlobTTLReply, error)
+	// This API disperses a failed blob again from the content kept by the
+	// disperser, so that the client doesn't have to keep the blob to submit it
+	// again. It returns the request_id of the new dispersal, the failed blob is
+	// left as it is.
+	RequeueBlob(ctx context.Context, in *RequeueBlobRequest, opts ...grpc.CallOption) (*RequeueBlobReply, error)
+	// This API returns how full the rate limit buckets of the caller are, so
+	// that clients can pace their requests instead of waiting to be rate
+	// limited. The buckets are not consumed by this API.
+	GetRateLimitStatus(ctx context.Context, in *GetRateLimitStatusRequest, opts ...grpc.CallOption) (*GetRateLimitStatusReply, error)
 	// This retrieves the requested blob from the Disperser's backend.
 	// This is a more efficient way to retrieve blobs than directly retrieving
 	// from the DA Nodes (see detail about this approach in
@@ -36,6 +77,14 @@ type DisperserClient interface {
 	// The blob should have been initially dispersed via this Disperser service
 	// for this API to work.
 	RetrieveBlob(ctx context.Context, in *RetrieveBlobRequest, opts ...grpc.CallOption) (*RetrieveBlobReply, error)
+	// This retrieves the blob by the request ID returned by DisperseBlob, so
+	// that clients don't need the location of the blob in its batch from
+	// GetBlobStatus. The blob must be confirmed.
+	RetrieveBlobByRequestId(ctx context.Context, in *RetrieveBlobByRequestIdRequest, opts ...grpc.CallOption) (*RetrieveBlobReply, error)
+	// This API lists the confirmed batches, ordered by the number of the block
+	// they were confirmed in, e.g. for block explorers to walk the history of the
+	// batches. The next page starts after the last batch of the previous page.
+	GetAllBatches(ctx context.Context, in *GetAllBatchesRequest, opts ...grpc.CallOption) (*GetAllBatchesReply, error)
 }
 
 type disperserClient struct {
@@ -55,6 +104,49 @@ func (c *disperserClient) DisperseBlob(ctx context.Context, in *DisperseBlobRequ
 	return out, nil
 }
 
+func (c *disperserClient) DisperseBlobStream(ctx context.Context, opts ...grpc.CallOption) (Disperser_DisperseBlobStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Disperser_ServiceDesc.Streams[0], "/disperser.Disperser/DisperseBlobStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &disperserDisperseBlobStreamClient{stream}
+	return x, nil
+}
+
+type Disperser_DisperseBlobStreamClient interface {
+	Send(*DisperseBlobChunk) error
+	CloseAndRecv() (*DisperseBlobReply, error)
+	grpc.ClientStream
+}
+
+type disperserDisperseBlobStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *disperserDisperseBlobStreamClient) Send(m *DisperseBlobChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *disperserDisperseBlobStreamClient) CloseAndRecv() (*DisperseBlobReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(DisperseBlobReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *disperserClient) DisperseBlobBatch(ctx context.Context, in *DisperseBlobBatchRequest, opts ...grpc.CallOption) (*DisperseBlobBatchReply, error) {
+	out := new(DisperseBlobBatchReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/DisperseBlobBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *disperserClient) GetBlobStatus(ctx context.Context, in *BlobStatusRequest, opts ...grpc.CallOption) (*BlobStatusReply, error) {
 	out := new(BlobStatusReply)
 	err := c.cc.Invoke(ctx, "/disperser.Disperser/GetBlobStatus", in, out, opts...)
@@ -64,6 +156,92 @@ func (c *disperserClient) GetBlobStatus(ctx context.Context, in *BlobStatusReque
 	return out, nil
 }
 
+func (c *disperserClient) BulkGetBlobStatus(ctx context.Context, in *BulkBlobStatusRequest, opts ...grpc.CallOption) (*BulkBlobStatusReply, error) {
+	out := new(BulkBlobStatusReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/BulkGetBlobStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *disperserClient) WatchBlobStatus(ctx context.Context, in *BlobStatusRequest, opts ...grpc.CallOption) (Disperser_WatchBlobStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Disperser_ServiceDesc.Streams[1], "/disperser.Disperser/WatchBlobStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &disperserWatchBlobStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Disperser_WatchBlobStatusClient interface {
+	Recv() (*BlobStatusReply, error)
+	grpc.ClientStream
+}
+
+type disperserWatchBlobStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *disperserWatchBlobStatusClient) Recv() (*BlobStatusReply, error) {
+	m := new(BlobStatusReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *disperserClient) RegisterBlobCallback(ctx context.Context, in *RegisterBlobCallbackRequest, opts ...grpc.CallOption) (*RegisterBlobCallbackReply, error) {
+	out := new(RegisterBlobCallbackReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/RegisterBlobCallback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *disperserClient) CancelBlob(ctx context.Context, in *CancelBlobRequest, opts ...grpc.CallOption) (*CancelBlobReply, error) {
+	out := new(CancelBlobReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/CancelBlob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *disperserClient) ExtendBlobTTL(ctx context.Context, in *ExtendBlobTTLRequest, opts ...grpc.CallOption) (*ExtendBlobTTLReply, error) {
+	out := new(ExtendBlobTTLReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/ExtendBlobTTL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *disperserClient) RequeueBlob(ctx context.Context, in *RequeueBlobRequest, opts ...grpc.CallOption) (*RequeueBlobReply, error) {
+	out := new(RequeueBlobReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/RequeueBlob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *disperserClient) GetRateLimitStatus(ctx context.Context, in *GetRateLimitStatusRequest, opts ...grpc.CallOption) (*GetRateLimitStatusReply, error) {
+	out := new(GetRateLimitStatusReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/GetRateLimitStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *disperserClient) RetrieveBlob(ctx context.Context, in *RetrieveBlobRequest, opts ...grpc.CallOption) (*RetrieveBlobReply, error) {
 	out := new(RetrieveBlobReply)
 	err := c.cc.Invoke(ctx, "/disperser.Disperser/RetrieveBlob", in, out, opts...)
@@ -73,6 +251,24 @@ func (c *disperserClient) RetrieveBlob(ctx context.Context, in *RetrieveBlobRequ
 	return out, nil
 }
 
+func (c *disperserClient) RetrieveBlobByRequestId(ctx context.Context, in *RetrieveBlobByRequestIdRequest, opts ...grpc.CallOption) (*RetrieveBlobReply, error) {
+	out := new(RetrieveBlobReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/RetrieveBlobByRequestId", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *disperserClient) GetAllBatches(ctx context.Context, in *GetAllBatchesRequest, opts ...grpc.CallOption) (*GetAllBatchesReply, error) {
+	out := new(GetAllBatchesReply)
+	err := c.cc.Invoke(ctx, "/disperser.Disperser/GetAllBatches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DisperserServer is the server API for Disperser service.
 // All implementations must embed UnimplementedDisperserServer
 // for forward compatibility
@@ -82,8 +278,49 @@ type DisperserServer interface {
 	// is accepted. The client could use GetBlobStatus() API to poll the the
 	// processing status of the blob.
 	DisperseBlob(context.Context, *DisperseBlobRequest) (*DisperseBlobReply, error)
+	// This API accepts blobs too large to be sent in a single DisperseBlobRequest.
+	// The client streams the blob in chunks and sets flush on the last one, the
+	// blob is then dispersed the same way as by DisperseBlob. The blob is dropped
+	// if the stream ends before the flush.
+	DisperseBlobStream(Disperser_DisperseBlobStreamServer) error
+	// This API accepts several blobs in a single request, each of them is
+	// dispersed the same way as by DisperseBlob. The reply has one result per
+	// blob, in the order of the request, and a blob failing doesn't fail the
+	// other blobs.
+	DisperseBlobBatch(context.Context, *DisperseBlobBatchRequest) (*DisperseBlobBatchReply, error)
 	// This API is meant to be polled for the blob status.
 	GetBlobStatus(context.Context, *BlobStatusRequest) (*BlobStatusReply, error)
+	// This API returns the status of several blobs in a single call, for
+	// clients polling many blobs. The reply has one status per request ID, in
+	// the order of the request, and a blob which can't be looked up has the
+	// UNKNOWN status rather than failing the call.
+	BulkGetBlobStatus(context.Context, *BulkBlobStatusRequest) (*BulkBlobStatusReply, error)
+	// This API sends the current blob status, then a new reply each time the
+	// status changes, instead of the client polling GetBlobStatus. The stream
+	// ends once the blob is finalized or failed, or when the watch times out.
+	WatchBlobStatus(*BlobStatusRequest, Disperser_WatchBlobStatusServer) error
+	// This API registers a URL the disperser POSTs the GetBlobStatus reply of
+	// the blob to, JSON encoded, once the blob is finalized or failed, instead
+	// of the client polling GetBlobStatus. The URL must start with one of the
+	// prefixes allowed by the disperser.
+	RegisterBlobCallback(context.Context, *RegisterBlobCallbackRequest) (*RegisterBlobCallbackReply, error)
+	// This API withdraws a blob which is still processing, it is marked as
+	// failed and won't be dispersed. Blobs which are already confirmed or
+	// finalized can't be cancelled.
+	CancelBlob(context.Context, *CancelBlobRequest) (*CancelBlobReply, error)
+	// This API extends the time a blob is retained for, without submitting it
+	// again. The extension is capped by the disperser, and failed blobs can't
+	// be extended.
+	ExtendBlobTTL(context.Context, *ExtendBlobTTLRequest) (*ExtendBlobTTLReply, error)
+	// This API disperses a failed blob again from the content kept by the
+	// disperser, so that the client doesn't have to keep the blob to submit it
+	// again. It returns the request_id of the new dispersal, the failed blob is
+	// left as it is.
+	RequeueBlob(context.Context, *RequeueBlobRequest) (*RequeueBlobReply, error)
+	// This API returns how full the rate limit buckets of the caller are, so
+	// that clients can pace their requests instead of waiting to be rate
+	// limited. The buckets are not consumed by this API.
+	GetRateLimitStatus(context.Context, *GetRateLimitStatusRequest) (*GetRateLimitStatusReply, error)
 	// This retrieves the requested blob from the Disperser's backend.
 	// This is a more efficient way to retrieve blobs than directly retrieving
 	// from the DA Nodes (see detail about this approach in
@@ -91,6 +328,14 @@ type DisperserServer interface {
 	// The blob should have been initially dispersed via this Disperser service
 	// for this API to work.
 	RetrieveBlob(context.Context, *RetrieveBlobRequest) (*RetrieveBlobReply, error)
+	// This retrieves the blob by the request ID returned by DisperseBlob, so
+	// that clients don't need the location of the blob in its batch from
+	// GetBlobStatus. The blob must be confirmed.
+	RetrieveBlobByRequestId(context.Context, *RetrieveBlobByRequestIdRequest) (*RetrieveBlobReply, error)
+	// This API lists the confirmed batches, ordered by the number of the block
+	// they were confirmed in, e.g. for block explorers to walk the history of the
+	// batches. The next page starts after the last batch of the previous page.
+	GetAllBatches(context.Context, *GetAllBatchesRequest) (*GetAllBatchesReply, error)
 	mustEmbedUnimplementedDisperserServer()
 }
 
@@ -101,12 +346,45 @@ type UnimplementedDisperserServer struct {
 func (UnimplementedDisperserServer) DisperseBlob(context.Context, *DisperseBlobRequest) (*DisperseBlobReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DisperseBlob not implemented")
 }
+func (UnimplementedDisperserServer) DisperseBlobStream(Disperser_DisperseBlobStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method DisperseBlobStream not implemented")
+}
+func (UnimplementedDisperserServer) DisperseBlobBatch(context.Context, *DisperseBlobBatchRequest) (*DisperseBlobBatchReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisperseBlobBatch not implemented")
+}
 func (UnimplementedDisperserServer) GetBlobStatus(context.Context, *BlobStatusRequest) (*BlobStatusReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBlobStatus not implemented")
 }
+func (UnimplementedDisperserServer) BulkGetBlobStatus(context.Context, *BulkBlobStatusRequest) (*BulkBlobStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkGetBlobStatus not implemented")
+}
+func (UnimplementedDisperserServer) WatchBlobStatus(*BlobStatusRequest, Disperser_WatchBlobStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBlobStatus not implemented")
+}
+func (UnimplementedDisperserServer) RegisterBlobCallback(context.Context, *RegisterBlobCallbackRequest) (*RegisterBlobCallbackReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterBlobCallback not implemented")
+}
+func (UnimplementedDisperserServer) CancelBlob(context.Context, *CancelBlobRequest) (*CancelBlobReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelBlob not implemented")
+}
+func (UnimplementedDisperserServer) ExtendBlobTTL(context.Context, *ExtendBlobTTLRequest) (*ExtendBlobTTLReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendBlobTTL not implemented")
+}
+func (UnimplementedDisperserServer) RequeueBlob(context.Context, *RequeueBlobRequest) (*RequeueBlobReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequeueBlob not implemented")
+}
+func (UnimplementedDisperserServer) GetRateLimitStatus(context.Context, *GetRateLimitStatusRequest) (*GetRateLimitStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateLimitStatus not implemented")
+}
 func (UnimplementedDisperserServer) RetrieveBlob(context.Context, *RetrieveBlobRequest) (*RetrieveBlobReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RetrieveBlob not implemented")
 }
+func (UnimplementedDisperserServer) RetrieveBlobByRequestId(context.Context, *RetrieveBlobByRequestIdRequest) (*RetrieveBlobReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetrieveBlobByRequestId not implemented")
+}
+func (UnimplementedDisperserServer) GetAllBatches(context.Context, *GetAllBatchesRequest) (*GetAllBatchesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllBatches not implemented")
+}
 func (UnimplementedDisperserServer) mustEmbedUnimplementedDisperserServer() {}
 
 // UnsafeDisperserServer may be embedded to opt out of forward compatibility for this service.
@@ -138,6 +416,50 @@ func _Disperser_DisperseBlob_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Disperser_DisperseBlobStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DisperserServer).DisperseBlobStream(&disperserDisperseBlobStreamServer{stream})
+}
+
+type Disperser_DisperseBlobStreamServer interface {
+	SendAndClose(*DisperseBlobReply) error
+	Recv() (*DisperseBlobChunk, error)
+	grpc.ServerStream
+}
+
+type disperserDisperseBlobStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *disperserDisperseBlobStreamServer) SendAndClose(m *DisperseBlobReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *disperserDisperseBlobStreamServer) Recv() (*DisperseBlobChunk, error) {
+	m := new(DisperseBlobChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Disperser_DisperseBlobBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisperseBlobBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).DisperseBlobBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/DisperseBlobBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).DisperseBlobBatch(ctx, req.(*DisperseBlobBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Disperser_GetBlobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlobStatusRequest)
 	if err := dec(in); err != nil {
@@ -156,6 +478,135 @@ func _Disperser_GetBlobStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Disperser_BulkGetBlobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkBlobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).BulkGetBlobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/BulkGetBlobStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).BulkGetBlobStatus(ctx, req.(*BulkBlobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Disperser_WatchBlobStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlobStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DisperserServer).WatchBlobStatus(m, &disperserWatchBlobStatusServer{stream})
+}
+
+type Disperser_WatchBlobStatusServer interface {
+	Send(*BlobStatusReply) error
+	grpc.ServerStream
+}
+
+type disperserWatchBlobStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *disperserWatchBlobStatusServer) Send(m *BlobStatusReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Disperser_RegisterBlobCallback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterBlobCallbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).RegisterBlobCallback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/RegisterBlobCallback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).RegisterBlobCallback(ctx, req.(*RegisterBlobCallbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Disperser_CancelBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).CancelBlob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/CancelBlob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).CancelBlob(ctx, req.(*CancelBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Disperser_ExtendBlobTTL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendBlobTTLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).ExtendBlobTTL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/ExtendBlobTTL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).ExtendBlobTTL(ctx, req.(*ExtendBlobTTLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Disperser_RequeueBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).RequeueBlob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/RequeueBlob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).RequeueBlob(ctx, req.(*RequeueBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Disperser_GetRateLimitStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRateLimitStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).GetRateLimitStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/GetRateLimitStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).GetRateLimitStatus(ctx, req.(*GetRateLimitStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Disperser_RetrieveBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RetrieveBlobRequest)
 	if err := dec(in); err != nil {
@@ -174,6 +625,42 @@ func _Disperser_RetrieveBlob_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Disperser_RetrieveBlobByRequestId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetrieveBlobByRequestIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).RetrieveBlobByRequestId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/RetrieveBlobByRequestId",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).RetrieveBlobByRequestId(ctx, req.(*RetrieveBlobByRequestIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Disperser_GetAllBatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllBatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DisperserServer).GetAllBatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/disperser.Disperser/GetAllBatches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DisperserServer).GetAllBatches(ctx, req.(*GetAllBatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Disperser_ServiceDesc is the grpc.ServiceDesc for Disperser service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -185,15 +672,62 @@ var Disperser_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DisperseBlob",
 			Handler:    _Disperser_DisperseBlob_Handler,
 		},
+		{
+			MethodName: "DisperseBlobBatch",
+			Handler:    _Disperser_DisperseBlobBatch_Handler,
+		},
 		{
 			MethodName: "GetBlobStatus",
 			Handler:    _Disperser_GetBlobStatus_Handler,
 		},
+		{
+			MethodName: "BulkGetBlobStatus",
+			Handler:    _Disperser_BulkGetBlobStatus_Handler,
+		},
+		{
+			MethodName: "RegisterBlobCallback",
+			Handler:    _Disperser_RegisterBlobCallback_Handler,
+		},
+		{
+			MethodName: "CancelBlob",
+			Handler:    _Disperser_CancelBlob_Handler,
+		},
+		{
+			MethodName: "ExtendBlobTTL",
+			Handler:    _Disperser_ExtendBlobTTL_Handler,
+		},
+		{
+			MethodName: "RequeueBlob",
+			Handler:    _Disperser_RequeueBlob_Handler,
+		},
+		{
+			MethodName: "GetRateLimitStatus",
+			Handler:    _Disperser_GetRateLimitStatus_Handler,
+		},
 		{
 			MethodName: "RetrieveBlob",
 			Handler:    _Disperser_RetrieveBlob_Handler,
 		},
+		{
+			MethodName: "RetrieveBlobByRequestId",
+			Handler:    _Disperser_RetrieveBlobByRequestId_Handler,
+		},
+		{
+			MethodName: "GetAllBatches",
+			Handler:    _Disperser_GetAllBatches_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DisperseBlobStream",
+			Handler:       _Disperser_DisperseBlobStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchBlobStatus",
+			Handler:       _Disperser_WatchBlobStatus_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "disperser/disperser.proto",
 }
@@ -26,6 +26,18 @@ var (
 		Required: true,
 		EnvVar:   common.PrefixEnvVar(envVarPrefix, "TABLE_NAME"),
 	}
+	OutputFileFlag = cli.StringFlag{
+		Name:     "output",
+		Usage:    "Path of the JSON Lines file to write the backup to",
+		Required: true,
+		EnvVar:   common.PrefixEnvVar(envVarPrefix, "OUTPUT"),
+	}
+	InputFileFlag = cli.StringFlag{
+		Name:     "input",
+		Usage:    "Path of the JSON Lines file to restore the backup from",
+		Required: true,
+		EnvVar:   common.PrefixEnvVar(envVarPrefix, "INPUT"),
+	}
 )
 
 // Flags contains the list of configuration options available to the binary.
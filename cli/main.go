@@ -71,6 +71,13 @@ func main() {
 					Flags:   append(flags.Flags, flags.DynamoDBTableNameFlag),
 					Action:  CreateBucketTable,
 				},
+				{
+					Name:    "create_batch_header_table",
+					Aliases: []string{"cbht"},
+					Usage:   "create a batch header table",
+					Flags:   append(flags.Flags, flags.DynamoDBTableNameFlag),
+					Action:  CreateBatchHeaderTable,
+				},
 				{
 					Name:    "delete_metadata_table",
 					Aliases: []string{"dmt"},
@@ -85,6 +92,13 @@ func main() {
 					Flags:   append(flags.Flags, flags.DynamoDBTableNameFlag),
 					Action:  DeleteTable,
 				},
+				{
+					Name:    "delete_batch_header_table",
+					Aliases: []string{"dbht"},
+					Usage:   "delete a batch header table",
+					Flags:   append(flags.Flags, flags.DynamoDBTableNameFlag),
+					Action:  DeleteTable,
+				},
 				{
 					Name:    "empty_metadata_table",
 					Aliases: []string{"emt"},
@@ -99,6 +113,18 @@ func main() {
 					Flags:   append(flags.Flags, flags.DynamoDBTableNameFlag),
 					Action:  ClearBucketTable,
 				},
+				{
+					Name:   "backup-metadata",
+					Usage:  "back up all the blob metadata of a metadata table to a JSON Lines file",
+					Flags:  append(flags.Flags, flags.DynamoDBTableNameFlag, flags.OutputFileFlag),
+					Action: BackupMetadata,
+				},
+				{
+					Name:   "restore-metadata",
+					Usage:  "restore the blob metadata of a JSON Lines backup into a metadata table",
+					Flags:  append(flags.Flags, flags.DynamoDBTableNameFlag, flags.InputFileFlag),
+					Action: RestoreMetadata,
+				},
 			},
 		},
 	}
@@ -174,6 +200,19 @@ func CreateBucketTable(ctx *cli.Context) error {
 	return createTable(ctx, false)
 }
 
+func CreateBatchHeaderTable(ctx *cli.Context) error {
+	config := NewConfig(ctx)
+
+	dynamoClient, err := getDynamodbClient(config)
+	if err != nil {
+		return err
+	}
+
+	tableName := ctx.String(flags.DynamoDBTableNameFlag.Name)
+	_, err = dynamoClient.CreateTable(context.Background(), config.AwsClientConfig, tableName, blobstore.GenerateBatchHeaderTableSchema(tableName, 10, 10))
+	return err
+}
+
 func createTable(ctx *cli.Context, isMetadata bool) error {
 	config := NewConfig(ctx)
 
@@ -244,6 +283,67 @@ func ClearBucketTable(ctx *cli.Context) error {
 	return nil
 }
 
+func BackupMetadata(ctx *cli.Context) error {
+	config := NewConfig(ctx)
+
+	metadataStore, err := getBlobMetadataStore(config, ctx.String(flags.DynamoDBTableNameFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(ctx.String(flags.OutputFileFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	count, err := blobstore.BackupBlobMetadata(context.Background(), metadataStore, file)
+	if err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	log.Println("backed up blob metadata: ", count)
+	return nil
+}
+
+func RestoreMetadata(ctx *cli.Context) error {
+	config := NewConfig(ctx)
+
+	metadataStore, err := getBlobMetadataStore(config, ctx.String(flags.DynamoDBTableNameFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(ctx.String(flags.InputFileFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	count, err := blobstore.RestoreBlobMetadata(context.Background(), metadataStore, file)
+	if err != nil {
+		return err
+	}
+	log.Println("restored blob metadata: ", count)
+	return nil
+}
+
+func getBlobMetadataStore(cfg *Config, tableName string) (*blobstore.BlobMetadataStore, error) {
+	logger, err := logging.GetLogger(cfg.LoggerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamoClient, err := dynamodb.NewClient(cfg.AwsClientConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return blobstore.NewBlobMetadataStore(dynamoClient, logger, tableName, 0), nil
+}
+
 func getS3Client(cfg *Config) (*s3.Client, error) {
 	logger, err := logging.GetLogger(cfg.LoggerConfig)
 	if err != nil {
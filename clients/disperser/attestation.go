@@ -0,0 +1,32 @@
+package disperser
+
+import (
+	"errors"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/disperser"
+)
+
+// VerifySubmissionAttestation verifies the disperser attestation that the blob of the DisperseBlob reply was received
+// at the returned timestamp. It returns an error if the reply carries no attestation.
+// The signature is checked against the public key in the reply, clients relying on the attestation for
+// non-repudiation should also check the key is the one published by the disperser.
+func VerifySubmissionAttestation(reply *pb.DisperseBlobReply) (bool, error) {
+	if len(reply.GetAttestationSignature()) == 0 {
+		return false, errors.New("the reply has no submission attestation")
+	}
+	err := disperser.VerifySubmissionAttestation(reply.GetRequestId(), reply.GetRequestedAt(), reply.GetAttestationSignature(), reply.GetAttestationPubkey())
+	return err == nil, nil
+}
+
+// VerifyDisperserReceipt checks the receipt of the reply was signed by the disperser attestation key of the reply,
+// and returns the decoded receipt
+func VerifyDisperserReceipt(reply *pb.DisperseBlobReply) (*disperser.DisperserReceipt, error) {
+	if len(reply.GetReceipt()) == 0 {
+		return nil, errors.New("the reply has no receipt")
+	}
+	if err := disperser.VerifyDisperserReceipt(reply.GetReceipt(), reply.GetReceiptSignature(), reply.GetAttestationPubkey()); err != nil {
+		return nil, err
+	}
+	return disperser.DecodeDisperserReceipt(reply.GetReceipt())
+}
@@ -0,0 +1,66 @@
+package disperser
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/0glabs/0g-data-avail/core"
+)
+
+// BlobCommitter computes the KZG commitments of the encoded rows of the blob data, with the encoding parameters of the
+// disperser, e.g. by encoding the data again with a trusted encoder
+type BlobCommitter func(data []byte) ([]core.Commitment, error)
+
+// VerifyRetrievedBlob verifies the data returned by RetrieveBlob is the blob included at the given index of the batch
+// with the given root. The commitments of the data must have the commitment root returned by RetrieveBlob, and the
+// blob header with that commitment root must be included in the batch according to the inclusion proof.
+// The quorumInfos are the quorum parameters of the blob returned by GetBlobStatus, for a batch whose leaves are hashed
+// with them, see core.BlobHeader.GetBlobHeaderHashV2. They are nil for a batch whose leaves only hash the commitment root.
+// It returns false without an error if the data or the proof doesn't match, and an error if the commitments of the
+// data can't be computed or the proof is malformed.
+func VerifyRetrievedBlob(data []byte, commit BlobCommitter, commitmentRoot []byte, quorumInfos []*core.BlobQuorumInfo, proof [][]byte, batchRoot [32]byte, index uint32) (bool, error) {
+	commitments, err := commit(data)
+	if err != nil {
+		return false, fmt.Errorf("failed to compute the commitments of the blob: %w", err)
+	}
+	blobHeader := &core.BlobHeader{QuorumInfos: quorumInfos}
+	if err := blobHeader.SetCommitmentRoot(commitments); err != nil {
+		return false, fmt.Errorf("failed to compute the commitment root of the blob: %w", err)
+	}
+	if !bytes.Equal(blobHeader.CommitmentRoot, commitmentRoot) {
+		return false, nil
+	}
+	if quorumInfos != nil {
+		return core.VerifyBlobInclusionProofV2(batchRoot, blobHeader, proof, uint(index))
+	}
+	return core.VerifyBlobInclusionProof(batchRoot, blobHeader, proof, uint(index))
+}
+
+// VerifyBatchRoot verifies the batch root returned by RetrieveBlob is the root of the blobs with the given commitment
+// roots, in the order of the blobs in the batch. Clients which retrieved all the blobs of a batch can use it to check
+// the batch root without trusting the disperser. The quorumInfos are the quorum parameters of each of the blobs, nil
+// like for VerifyRetrievedBlob if the leaves of the batch only hash the commitment roots.
+func VerifyBatchRoot(batchRoot []byte, commitmentRoots [][]byte, quorumInfos [][]*core.BlobQuorumInfo) (bool, error) {
+	batchHeader := &core.BatchHeader{}
+	if len(batchRoot) != len(batchHeader.BatchRoot) {
+		return false, nil
+	}
+	copy(batchHeader.BatchRoot[:], batchRoot)
+	if quorumInfos != nil && len(quorumInfos) != len(commitmentRoots) {
+		return false, fmt.Errorf("got the quorum parameters of %d blobs for %d commitment roots", len(quorumInfos), len(commitmentRoots))
+	}
+
+	blobHeaders := make([]*core.BlobHeader, len(commitmentRoots))
+	for i, commitmentRoot := range commitmentRoots {
+		blobHeaders[i] = &core.BlobHeader{
+			CommitmentRoot: commitmentRoot,
+		}
+	}
+	if quorumInfos != nil {
+		for i := range blobHeaders {
+			blobHeaders[i].QuorumInfos = quorumInfos[i]
+		}
+		return batchHeader.ValidateBatchRootV2(blobHeaders)
+	}
+	return batchHeader.ValidateBatchRoot(blobHeaders)
+}
@@ -0,0 +1,40 @@
+package common
+
+import "time"
+
+// AuditOperation is the API operation an audit event records
+type AuditOperation string
+
+const (
+	AuditOperationDisperseBlob  AuditOperation = "DisperseBlob"
+	AuditOperationGetBlobStatus AuditOperation = "GetBlobStatus"
+	AuditOperationRetrieveBlob  AuditOperation = "RetrieveBlob"
+)
+
+// AuditOutcome is how the audited operation ended
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess     AuditOutcome = "success"
+	AuditOutcomeRateLimited AuditOutcome = "ratelimited"
+	AuditOutcomeError       AuditOutcome = "error"
+)
+
+// AuditEvent is the record of an operation of a requester
+type AuditEvent struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Operation AuditOperation `json:"operation"`
+	// RequesterID is the authenticated account of the requester, or its address if it isn't authenticated
+	RequesterID RequesterID `json:"requesterId"`
+	// BlobKey is the key of the blob the operation is about, if it is known
+	BlobKey  string       `json:"blobKey,omitempty"`
+	BlobSize int          `json:"blobSize"`
+	Outcome  AuditOutcome `json:"outcome"`
+	// ErrorCode is the grpc status code of the failed operations
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+// AuditLogger records the operations of the requesters for the security audits
+type AuditLogger interface {
+	Log(event AuditEvent)
+}
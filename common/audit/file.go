@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// the audit log is rotated when it reaches maxFileSizeMB, the rotated files are compressed and kept for
+	// maxFileAgeDays
+	maxFileSizeMB  = 100
+	maxFileAgeDays = 90
+)
+
+// FileAuditLogger writes the audit events as newline delimited JSON to a rotating log file
+type FileAuditLogger struct {
+	mu     sync.Mutex
+	writer *lumberjack.Logger
+	logger common.Logger
+}
+
+var _ common.AuditLogger = (*FileAuditLogger)(nil)
+
+// NewFileAuditLogger creates an audit logger appending to the file at path, the file is created if it doesn't exist
+func NewFileAuditLogger(path string, logger common.Logger) *FileAuditLogger {
+	return &FileAuditLogger{
+		writer: &lumberjack.Logger{
+			Filename: path,
+			MaxSize:  maxFileSizeMB,
+			MaxAge:   maxFileAgeDays,
+			Compress: true,
+		},
+		logger: logger,
+	}
+}
+
+// Log appends the event to the audit log. The events which can't be written are logged instead, so they aren't lost.
+func (l *FileAuditLogger) Log(event common.AuditEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		l.logger.Error("[audit] failed to encode audit event", "event", event, "err", err)
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.writer.Write(line); err != nil {
+		l.logger.Error("[audit] failed to write audit event", "event", string(line), "err", err)
+	}
+}
+
+// Close closes the audit log file
+func (l *FileAuditLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.writer.Close()
+}
@@ -0,0 +1,56 @@
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/audit"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAuditLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger := audit.NewFileAuditLogger(path, &mock.Logger{})
+
+	events := []common.AuditEvent{
+		{
+			Timestamp:   time.Unix(1700000000, 0).UTC(),
+			Operation:   common.AuditOperationDisperseBlob,
+			RequesterID: "rollup",
+			BlobKey:     "hash-key",
+			BlobSize:    128,
+			Outcome:     common.AuditOutcomeSuccess,
+		},
+		{
+			Timestamp:   time.Unix(1700000001, 0).UTC(),
+			Operation:   common.AuditOperationGetBlobStatus,
+			RequesterID: "10.0.0.1",
+			Outcome:     common.AuditOutcomeError,
+			ErrorCode:   "NotFound",
+		},
+	}
+	for _, event := range events {
+		logger.Log(event)
+	}
+	require.NoError(t, logger.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	logged := make([]common.AuditEvent, 0)
+	for scanner.Scan() {
+		var event common.AuditEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		logged = append(logged, event)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, events, logged)
+}
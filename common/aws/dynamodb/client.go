@@ -2,6 +2,7 @@ package dynamodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"sync"
@@ -15,6 +16,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -36,6 +38,9 @@ var (
 	clientRef *Client
 )
 
+// ErrConditionFailed is returned when the condition of a conditional write doesn't hold
+var ErrConditionFailed = errors.New("condition failed")
+
 type Item = map[string]types.AttributeValue
 type Key = map[string]types.AttributeValue
 type ExpresseionValues = map[string]types.AttributeValue
@@ -118,6 +123,18 @@ func (c *Client) DeleteTable(ctx context.Context, tableName string) error {
 	return nil
 }
 
+// DescribeTable returns the description of the table, including its secondary indexes
+func (c *Client) DescribeTable(ctx context.Context, tableName string) (*types.TableDescription, error) {
+	response, err := c.dynamoClient.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Table, nil
+}
+
 func (c *Client) PutItem(ctx context.Context, tableName string, item Item) (err error) {
 	_, err = c.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(tableName), Item: item,
@@ -129,6 +146,32 @@ func (c *Client) PutItem(ctx context.Context, tableName string, item Item) (err
 	return nil
 }
 
+// PutItemWithCondition puts the item only if the condition holds, e.g. to not overwrite an existing item.
+// It returns ErrConditionFailed if the condition doesn't hold.
+func (c *Client) PutItemWithCondition(ctx context.Context, tableName string, item Item, condition expression.ConditionBuilder) error {
+	expr, err := expression.NewBuilder().WithCondition(condition).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(tableName),
+		Item:                      item,
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ConditionExpression:       expr.Condition(),
+	})
+	if err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return ErrConditionFailed
+		}
+		return err
+	}
+
+	return nil
+}
+
 // PutItems puts items in batches of 25 items (which is a limit DynamoDB imposes)
 // It returns the items that failed to be put.
 func (c *Client) PutItems(ctx context.Context, tableName string, items []Item) ([]Item, error) {
@@ -166,6 +209,70 @@ func (c *Client) UpdateItem(ctx context.Context, tableName string, key Key, item
 	return resp.Attributes, err
 }
 
+// TransactUpdateItems sets the attributes of the item on all the keys in a single transaction,
+// either all the keys are updated or none is. DynamoDB limits the number of items of a transaction.
+func (c *Client) TransactUpdateItems(ctx context.Context, tableName string, keys []Key, item Item) error {
+	update := expression.UpdateBuilder{}
+	for itemKey, itemValue := range item {
+		update = update.Set(expression.Name(itemKey), expression.Value(itemValue))
+	}
+	return c.TransactUpdateItemsWithExpression(ctx, tableName, keys, update)
+}
+
+// TransactUpdateItemsWithExpression applies the update expression to all the keys in a single transaction, like
+// TransactUpdateItems.
+func (c *Client) TransactUpdateItemsWithExpression(ctx context.Context, tableName string, keys []Key, update expression.UpdateBuilder) error {
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return err
+	}
+
+	transactItems := make([]types.TransactWriteItem, len(keys))
+	for i, key := range keys {
+		transactItems[i] = types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:                 aws.String(tableName),
+				Key:                       key,
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				UpdateExpression:          expr.Update(),
+			},
+		}
+	}
+	_, err = c.dynamoClient.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	return err
+}
+
+// UpdateItemWithCondition applies the update expression to the item only if the condition holds.
+// It returns ErrConditionFailed if the condition doesn't hold.
+func (c *Client) UpdateItemWithCondition(ctx context.Context, tableName string, key Key, update expression.UpdateBuilder, condition expression.ConditionBuilder) (Item, error) {
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       key,
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return nil, ErrConditionFailed
+		}
+		return nil, err
+	}
+
+	return resp.Attributes, nil
+}
+
 func (c *Client) GetItem(ctx context.Context, tableName string, key Key) (Item, error) {
 	resp, err := c.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{Key: key, TableName: aws.String(tableName)})
 	if err != nil {
@@ -175,6 +282,20 @@ func (c *Client) GetItem(ctx context.Context, tableName string, key Key) (Item,
 	return resp.Item, nil
 }
 
+// Query returns all items in the table that match the given key
+func (c *Client) Query(ctx context.Context, tableName string, keyCondition string, expAttributeValues ExpresseionValues) ([]Item, error) {
+	response, err := c.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeValues: expAttributeValues,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Items, nil
+}
+
 // QueryIndex returns all items in the index that match the given key
 func (c *Client) QueryIndex(ctx context.Context, tableName string, indexName string, keyCondition string, expAttributeValues ExpresseionValues) ([]Item, error) {
 	response, err := c.dynamoClient.Query(ctx, &dynamodb.QueryInput{
@@ -190,6 +311,83 @@ func (c *Client) QueryIndex(ctx context.Context, tableName string, indexName str
 	return response.Items, nil
 }
 
+// QueryIndexWithPagination returns up to limit items in the index that match the given key, starting after exclusiveStartKey.
+// It also returns the last evaluated key to continue the query from, which is nil when there are no more items
+func (c *Client) QueryIndexWithPagination(ctx context.Context, tableName string, indexName string, keyCondition string, expAttributeValues ExpresseionValues, limit int32, exclusiveStartKey Key) ([]Item, Key, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		IndexName:                 aws.String(indexName),
+		KeyConditionExpression:    aws.String(keyCondition),
+		ExpressionAttributeValues: expAttributeValues,
+		Limit:                     aws.Int32(limit),
+	}
+	if len(exclusiveStartKey) > 0 {
+		queryInput.ExclusiveStartKey = exclusiveStartKey
+	}
+
+	response, err := c.dynamoClient.Query(ctx, queryInput)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response.Items, response.LastEvaluatedKey, nil
+}
+
+// Scan returns up to limit items in the table that match the given filter.
+// The whole table may be read to find them, so it should only be used for infrequent operations.
+func (c *Client) Scan(ctx context.Context, tableName string, filterExpression string, expAttributeValues ExpresseionValues, limit int) ([]Item, error) {
+	paginator := dynamodb.NewScanPaginator(c.dynamoClient, &dynamodb.ScanInput{
+		TableName:                 aws.String(tableName),
+		FilterExpression:          aws.String(filterExpression),
+		ExpressionAttributeValues: expAttributeValues,
+	})
+
+	items := make([]Item, 0)
+	for paginator.HasMorePages() && len(items) < limit {
+		response, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, response.Items...)
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items, nil
+}
+
+// ParallelScan reads the entire table with consistent reads, scanning totalSegments segments of the table in parallel.
+// fn is called with each page of items, concurrently from the segments, and the scan stops at the first error.
+func (c *Client) ParallelScan(ctx context.Context, tableName string, totalSegments int32, fn func(items []Item) error) error {
+	if totalSegments <= 0 {
+		return fmt.Errorf("total segments must be positive, got %d", totalSegments)
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for segment := int32(0); segment < totalSegments; segment++ {
+		paginator := dynamodb.NewScanPaginator(c.dynamoClient, &dynamodb.ScanInput{
+			TableName:      aws.String(tableName),
+			ConsistentRead: aws.Bool(true),
+			Segment:        aws.Int32(segment),
+			TotalSegments:  aws.Int32(totalSegments),
+		})
+		group.Go(func() error {
+			for paginator.HasMorePages() {
+				response, err := paginator.NextPage(groupCtx)
+				if err != nil {
+					return err
+				}
+				if err := fn(response.Items); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return group.Wait()
+}
+
 func (c *Client) DeleteItem(ctx context.Context, tableName string, key Key) error {
 	_, err := c.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{Key: key, TableName: aws.String(tableName)})
 	if err != nil {
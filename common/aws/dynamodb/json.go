@@ -0,0 +1,162 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// MarshalItemJSON encodes the item in the DynamoDB JSON format, e.g. {"Expiry":{"N":"1"}}, which keeps the type of each attribute
+func MarshalItemJSON(item Item) ([]byte, error) {
+	encoded, err := encodeAttributeMap(item)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalItemJSON decodes an item encoded by MarshalItemJSON
+func UnmarshalItemJSON(data []byte) (Item, error) {
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+	return decodeAttributeMap(encoded)
+}
+
+func encodeAttributeMap(item map[string]types.AttributeValue) (map[string]interface{}, error) {
+	encoded := make(map[string]interface{}, len(item))
+	for name, value := range item {
+		v, err := encodeAttribute(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		encoded[name] = v
+	}
+	return encoded, nil
+}
+
+func encodeAttribute(value types.AttributeValue) (map[string]interface{}, error) {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return map[string]interface{}{"S": v.Value}, nil
+	case *types.AttributeValueMemberN:
+		return map[string]interface{}{"N": v.Value}, nil
+	case *types.AttributeValueMemberB:
+		return map[string]interface{}{"B": v.Value}, nil
+	case *types.AttributeValueMemberBOOL:
+		return map[string]interface{}{"BOOL": v.Value}, nil
+	case *types.AttributeValueMemberNULL:
+		return map[string]interface{}{"NULL": v.Value}, nil
+	case *types.AttributeValueMemberSS:
+		return map[string]interface{}{"SS": v.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return map[string]interface{}{"NS": v.Value}, nil
+	case *types.AttributeValueMemberBS:
+		return map[string]interface{}{"BS": v.Value}, nil
+	case *types.AttributeValueMemberL:
+		list := make([]interface{}, len(v.Value))
+		for i, elem := range v.Value {
+			encoded, err := encodeAttribute(elem)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = encoded
+		}
+		return map[string]interface{}{"L": list}, nil
+	case *types.AttributeValueMemberM:
+		m, err := encodeAttributeMap(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"M": m}, nil
+	default:
+		return nil, fmt.Errorf("unsupported attribute value type %T", value)
+	}
+}
+
+func decodeAttributeMap(encoded map[string]json.RawMessage) (map[string]types.AttributeValue, error) {
+	item := make(map[string]types.AttributeValue, len(encoded))
+	for name, data := range encoded {
+		value, err := decodeAttribute(data)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		item[name] = value
+	}
+	return item, nil
+}
+
+func decodeAttribute(data json.RawMessage) (types.AttributeValue, error) {
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, err
+	}
+	if len(typed) != 1 {
+		return nil, fmt.Errorf("expected a single type, got %d", len(typed))
+	}
+
+	for kind, raw := range typed {
+		var err error
+		switch kind {
+		case "S":
+			v := &types.AttributeValueMemberS{}
+			err = json.Unmarshal(raw, &v.Value)
+			return v, err
+		case "N":
+			v := &types.AttributeValueMemberN{}
+			err = json.Unmarshal(raw, &v.Value)
+			return v, err
+		case "B":
+			v := &types.AttributeValueMemberB{}
+			err = json.Unmarshal(raw, &v.Value)
+			return v, err
+		case "BOOL":
+			v := &types.AttributeValueMemberBOOL{}
+			err = json.Unmarshal(raw, &v.Value)
+			return v, err
+		case "NULL":
+			v := &types.AttributeValueMemberNULL{}
+			err = json.Unmarshal(raw, &v.Value)
+			return v, err
+		case "SS":
+			v := &types.AttributeValueMemberSS{}
+			err = json.Unmarshal(raw, &v.Value)
+			return v, err
+		case "NS":
+			v := &types.AttributeValueMemberNS{}
+			err = json.Unmarshal(raw, &v.Value)
+			return v, err
+		case "BS":
+			v := &types.AttributeValueMemberBS{}
+			err = json.Unmarshal(raw, &v.Value)
+			return v, err
+		case "L":
+			var list []json.RawMessage
+			if err = json.Unmarshal(raw, &list); err != nil {
+				return nil, err
+			}
+			v := &types.AttributeValueMemberL{Value: make([]types.AttributeValue, len(list))}
+			for i, elem := range list {
+				if v.Value[i], err = decodeAttribute(elem); err != nil {
+					return nil, err
+				}
+			}
+			return v, nil
+		case "M":
+			var m map[string]json.RawMessage
+			if err = json.Unmarshal(raw, &m); err != nil {
+				return nil, err
+			}
+			value, err := decodeAttributeMap(m)
+			if err != nil {
+				return nil, err
+			}
+			return &types.AttributeValueMemberM{Value: value}, nil
+		default:
+			return nil, fmt.Errorf("unsupported attribute value type %s", kind)
+		}
+	}
+	return nil, nil
+}
@@ -5,10 +5,12 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"net/url"
 	"sync"
 
 	"github.com/0glabs/0g-data-avail/common"
 	commonaws "github.com/0glabs/0g-data-avail/common/aws"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -16,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -29,12 +32,44 @@ type Object struct {
 	Size int64
 }
 
+// ObjectAttributes are the attributes of an object returned by HeadObject
+type ObjectAttributes struct {
+	ETag     string
+	Metadata map[string]string
+}
+
+// ObjectStorage is the object storage used by the blob store
+type ObjectStorage interface {
+	DownloadObject(ctx context.Context, bucket string, key string) ([]byte, error)
+	HeadObject(ctx context.Context, bucket string, key string) (*ObjectAttributes, error)
+	UploadObject(ctx context.Context, bucket string, key string, data []byte) error
+	PutObject(ctx context.Context, bucket string, key string, data []byte, metadata map[string]string) error
+	DeleteObject(ctx context.Context, bucket string, key string) error
+	CopyObject(ctx context.Context, bucket string, srcKey string, dstKey string) error
+	ListObjects(ctx context.Context, bucket string, prefix string) ([]Object, error)
+	PutObjectTags(ctx context.Context, bucket string, key string, tags map[string]string) error
+}
+
+var _ ObjectStorage = (*Client)(nil)
+
 type Client struct {
-	s3Client *s3.Client
-	logger   common.Logger
+	s3Client  *s3.Client
+	multipart MultipartConfig
+	logger    common.Logger
 }
 
-func NewClient(cfg commonaws.ClientConfig, logger common.Logger) (*Client, error) {
+// ClientOption configures optional features of the Client
+type ClientOption func(*Client)
+
+// WithMultipartUpload uploads the objects larger than config.ThresholdBytes in parts, see UploadMultipart
+func WithMultipartUpload(config MultipartConfig) ClientOption {
+	return func(c *Client) {
+		c.multipart = config
+	}
+}
+
+// NewClient returns the S3 client of the process, it is created on the first call with the options of that call
+func NewClient(cfg commonaws.ClientConfig, logger common.Logger, opts ...ClientOption) (*Client, error) {
 	var err error
 	logger.Info("url", cfg.EndpointURL)
 	once.Do(func() {
@@ -66,11 +101,17 @@ func NewClient(cfg commonaws.ClientConfig, logger common.Logger) (*Client, error
 			o.UsePathStyle = true
 		})
 		ref = &Client{s3Client: s3Client, logger: logger}
+		for _, opt := range opts {
+			opt(ref)
+		}
 	})
 	return ref, err
 }
 
-func (s *Client) DownloadObject(ctx context.Context, bucket string, key string) ([]byte, error) {
+func (s *Client) DownloadObject(ctx context.Context, bucket string, key string) (data []byte, err error) {
+	ctx, span := tracing.StartSpan(ctx, "S3.DownloadObject", attribute.String("s3.bucket", bucket), attribute.String("s3.key", key))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	var partMiBs int64 = 10
 	downloader := manager.NewDownloader(s.s3Client, func(d *manager.Downloader) {
 		d.PartSize = partMiBs * 1024 * 1024 // 10MB per part
@@ -78,11 +119,15 @@ func (s *Client) DownloadObject(ctx context.Context, bucket string, key string)
 	})
 
 	buffer := manager.NewWriteAtBuffer([]byte{})
-	_, err := downloader.Download(ctx, buffer, &s3.GetObjectInput{
+	_, err = downloader.Download(ctx, buffer, &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 	})
 	if err != nil {
+		var responseError *awshttp.ResponseError
+		if errors.As(err, &responseError) && responseError.ResponseError.HTTPStatusCode() == http.StatusNotFound {
+			return nil, ErrObjectNotFound
+		}
 		return nil, err
 	}
 
@@ -108,19 +153,44 @@ func (s *Client) keyExists(ctx context.Context, bucket string, key string) (bool
 	return true, nil
 }
 
-func (s *Client) UploadObject(ctx context.Context, bucket string, key string, data []byte) error {
+// HeadObject returns the attributes of the object, or ErrObjectNotFound if it doesn't exist
+func (s *Client) HeadObject(ctx context.Context, bucket string, key string) (*ObjectAttributes, error) {
+	output, err := s.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var responseError *awshttp.ResponseError
+		if errors.As(err, &responseError) && responseError.ResponseError.HTTPStatusCode() == http.StatusNotFound {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return &ObjectAttributes{
+		ETag:     aws.ToString(output.ETag),
+		Metadata: output.Metadata,
+	}, nil
+}
+
+func (s *Client) UploadObject(ctx context.Context, bucket string, key string, data []byte) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "S3.UploadObject", attribute.String("s3.bucket", bucket), attribute.String("s3.key", key), attribute.Int("s3.size", len(data)))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	var partMiBs int64 = 10
 	uploaded, _ := s.keyExists(ctx, bucket, key)
 	if uploaded {
 		s.logger.Info("object already uploaded, skip", "key", key)
 		return nil
 	}
+	if s.isMultipart(data) {
+		return UploadMultipart(ctx, s.s3Client, bucket, key, data, nil, s.multipart, s.logger)
+	}
 	uploader := manager.NewUploader(s.s3Client, func(u *manager.Uploader) {
 		u.PartSize = partMiBs * 1024 * 1024 // 10MB per part
 		u.Concurrency = 3                   //The number of goroutines to spin up in parallel per call to Upload when sending parts
 	})
 
-	_, err := uploader.Upload(ctx, &s3.PutObjectInput{
+	_, err = uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 		Body:   bytes.NewReader(data),
@@ -132,6 +202,34 @@ func (s *Client) UploadObject(ctx context.Context, bucket string, key string, da
 	return nil
 }
 
+// PutObject uploads the data with the given user metadata, overwriting the object if it exists
+func (s *Client) PutObject(ctx context.Context, bucket string, key string, data []byte, metadata map[string]string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "S3.UploadObject", attribute.String("s3.bucket", bucket), attribute.String("s3.key", key), attribute.Int("s3.size", len(data)))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	if s.isMultipart(data) {
+		return UploadMultipart(ctx, s.s3Client, bucket, key, data, metadata, s.multipart, s.logger)
+	}
+	var partMiBs int64 = 10
+	uploader := manager.NewUploader(s.s3Client, func(u *manager.Uploader) {
+		u.PartSize = partMiBs * 1024 * 1024 // 10MB per part
+		u.Concurrency = 3                   //The number of goroutines to spin up in parallel per call to Upload when sending parts
+	})
+
+	_, err = uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Body:     bytes.NewReader(data),
+		Metadata: metadata,
+	})
+	return err
+}
+
+// isMultipart returns whether the data is uploaded in parts
+func (s *Client) isMultipart(data []byte) bool {
+	return s.multipart.ThresholdBytes > 0 && int64(len(data)) > s.multipart.ThresholdBytes
+}
+
 func (s *Client) DeleteObject(ctx context.Context, bucket string, key string) error {
 	_, err := s.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(bucket),
@@ -144,6 +242,16 @@ func (s *Client) DeleteObject(ctx context.Context, bucket string, key string) er
 	return err
 }
 
+// CopyObject copies the object to another key of the same bucket server side, overwriting the object if it exists
+func (s *Client) CopyObject(ctx context.Context, bucket string, srcKey string, dstKey string) error {
+	_, err := s.s3Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(bucket),
+		CopySource: aws.String((&url.URL{Path: bucket + "/" + srcKey}).EscapedPath()),
+		Key:        aws.String(dstKey),
+	})
+	return err
+}
+
 func (s *Client) ListObjects(ctx context.Context, bucket string, prefix string) ([]Object, error) {
 	output, err := s.s3Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
 		Bucket: aws.String(bucket),
@@ -163,6 +271,20 @@ func (s *Client) ListObjects(ctx context.Context, bucket string, prefix string)
 	return objects, nil
 }
 
+// PutObjectTags replaces the tags of the object, e.g. the ones matched by the lifecycle rules of the bucket
+func (s *Client) PutObjectTags(ctx context.Context, bucket string, key string, tags map[string]string) error {
+	tagSet := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	_, err := s.s3Client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(bucket),
+		Key:     aws.String(key),
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	return err
+}
+
 func (s *Client) CreateBucket(ctx context.Context, name, region string) error {
 	_, err := s.s3Client.CreateBucket(ctx, &s3.CreateBucketInput{
 		Bucket: aws.String(name),
@@ -0,0 +1,62 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ContentMD5MetadataKey is the user metadata key (x-amz-meta-content-md5) holding the hex encoded MD5 of the object data.
+// It's needed because the ETag of multipart uploads is not the MD5 of the data.
+const ContentMD5MetadataKey = "content-md5"
+
+// UploadObjectIfChanged uploads the data unless the object already holds the same data, which is checked by comparing
+// the MD5 of the data against the content-md5 metadata of the object, or its ETag if the object doesn't have it.
+// It returns whether the data was uploaded.
+func UploadObjectIfChanged(ctx context.Context, storage ObjectStorage, bucket string, key string, data []byte) (bool, error) {
+	return UploadObjectIfChangedWithMetadata(ctx, storage, bucket, key, data, nil)
+}
+
+// UploadObjectIfChangedWithMetadata is UploadObjectIfChanged with user metadata stored along the data
+func UploadObjectIfChangedWithMetadata(ctx context.Context, storage ObjectStorage, bucket string, key string, data []byte, metadata map[string]string) (bool, error) {
+	sum := md5.Sum(data)
+	contentMD5 := hex.EncodeToString(sum[:])
+
+	attributes, err := storage.HeadObject(ctx, bucket, key)
+	if err != nil && !errors.Is(err, ErrObjectNotFound) {
+		return false, err
+	}
+	if err == nil && existingMD5(attributes) == contentMD5 {
+		return false, nil
+	}
+
+	objectMetadata := map[string]string{ContentMD5MetadataKey: contentMD5}
+	for k, v := range metadata {
+		objectMetadata[k] = v
+	}
+	err = storage.PutObject(ctx, bucket, key, data, objectMetadata)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func existingMD5(attributes *ObjectAttributes) string {
+	if md5 := attributes.MetadataValue(ContentMD5MetadataKey); md5 != "" {
+		return md5
+	}
+	return strings.Trim(attributes.ETag, `"`)
+}
+
+// MetadataValue returns the value of the user metadata key, or "" if the object doesn't have it
+func (a *ObjectAttributes) MetadataValue(key string) string {
+	for k, v := range a.Metadata {
+		// metadata keys may come back with a different case
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
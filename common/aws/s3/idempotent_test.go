@@ -0,0 +1,115 @@
+package s3_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/aws/s3"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeObjectStorage returns the configured attributes on HeadObject and counts the uploads
+type fakeObjectStorage struct {
+	*mock.S3Client
+
+	attributes *s3.ObjectAttributes
+	headErr    error
+	puts       int
+	metadata   map[string]string
+}
+
+func (f *fakeObjectStorage) HeadObject(ctx context.Context, bucket string, key string) (*s3.ObjectAttributes, error) {
+	if f.headErr != nil {
+		return nil, f.headErr
+	}
+	return f.attributes, nil
+}
+
+func (f *fakeObjectStorage) PutObject(ctx context.Context, bucket string, key string, data []byte, metadata map[string]string) error {
+	f.puts++
+	f.metadata = metadata
+	return nil
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUploadObjectIfChanged(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("idempotent blob")
+
+	testCases := []struct {
+		name       string
+		attributes *s3.ObjectAttributes
+		headErr    error
+		uploaded   bool
+	}{
+		{name: "object not found", headErr: s3.ErrObjectNotFound, uploaded: true},
+		{name: "matching etag", attributes: &s3.ObjectAttributes{ETag: `"` + md5Hex(data) + `"`}, uploaded: false},
+		{name: "non-matching etag", attributes: &s3.ObjectAttributes{ETag: `"` + md5Hex([]byte("other blob")) + `"`}, uploaded: true},
+		{
+			name: "matching content md5 of multipart upload",
+			attributes: &s3.ObjectAttributes{
+				ETag:     `"` + md5Hex([]byte("other blob")) + `-2"`,
+				Metadata: map[string]string{"Content-Md5": md5Hex(data)},
+			},
+			uploaded: false,
+		},
+		{
+			name: "non-matching content md5",
+			attributes: &s3.ObjectAttributes{
+				ETag:     `"` + md5Hex(data) + `"`,
+				Metadata: map[string]string{s3.ContentMD5MetadataKey: md5Hex([]byte("other blob"))},
+			},
+			uploaded: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := &fakeObjectStorage{S3Client: mock.NewS3Client(), attributes: tc.attributes, headErr: tc.headErr}
+
+			uploaded, err := s3.UploadObjectIfChanged(ctx, storage, "bucket", "key", data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.uploaded, uploaded)
+			if tc.uploaded {
+				assert.Equal(t, 1, storage.puts)
+				assert.Equal(t, map[string]string{s3.ContentMD5MetadataKey: md5Hex(data)}, storage.metadata)
+			} else {
+				assert.Equal(t, 0, storage.puts)
+			}
+		})
+	}
+
+	// other errors are returned without uploading
+	storage := &fakeObjectStorage{S3Client: mock.NewS3Client(), headErr: errors.New("access denied")}
+	_, err := s3.UploadObjectIfChanged(ctx, storage, "bucket", "key", data)
+	assert.Error(t, err)
+	assert.Equal(t, 0, storage.puts)
+}
+
+func TestUploadObjectIfChangedMockClient(t *testing.T) {
+	ctx := context.Background()
+	client := mock.NewS3Client()
+
+	uploaded, err := s3.UploadObjectIfChanged(ctx, client, "bucket", "key", []byte("first"))
+	assert.NoError(t, err)
+	assert.True(t, uploaded)
+
+	// retried upload of the same data
+	uploaded, err = s3.UploadObjectIfChanged(ctx, client, "bucket", "key", []byte("first"))
+	assert.NoError(t, err)
+	assert.False(t, uploaded)
+
+	uploaded, err = s3.UploadObjectIfChanged(ctx, client, "bucket", "key", []byte("second"))
+	assert.NoError(t, err)
+	assert.True(t, uploaded)
+	data, err := client.DownloadObject(ctx, "bucket", "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("second"), data)
+}
@@ -0,0 +1,111 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// minPartSize is the minimum size of the parts of a multipart upload but the last one
+	minPartSize = 5 * 1024 * 1024
+
+	defaultPartSize           = 8 * 1024 * 1024
+	defaultMaxConcurrentParts = 4
+)
+
+// MultipartConfig configures the multipart uploads of the Client
+type MultipartConfig struct {
+	// ThresholdBytes is the size above which objects are uploaded in parts, they are uploaded in a single request if 0
+	ThresholdBytes int64
+	// PartSize is the size of the parts, it defaults to 8MiB and is at least 5MiB, the minimum of S3
+	PartSize int64
+	// MaxConcurrentParts is the maximum number of parts uploaded in parallel, it defaults to 4 if not positive
+	MaxConcurrentParts int
+}
+
+// MultipartAPI is the part of the S3 API used by the multipart uploads, it is implemented by the S3 client of the SDK
+type MultipartAPI interface {
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// UploadMultipart uploads the data in parts of config.PartSize, with up to config.MaxConcurrentParts parts uploaded in
+// parallel. If any part fails, the upload is aborted so that the uploaded parts aren't left behind.
+func UploadMultipart(ctx context.Context, api MultipartAPI, bucket string, key string, data []byte, metadata map[string]string, config MultipartConfig, logger common.Logger) (err error) {
+	partSize := config.PartSize
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+	partSize = max(partSize, minPartSize)
+	maxConcurrentParts := config.MaxConcurrentParts
+	if maxConcurrentParts <= 0 {
+		maxConcurrentParts = defaultMaxConcurrentParts
+	}
+
+	created, err := api.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Metadata: metadata,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		// the context may be the reason of the failure, the upload is aborted regardless
+		_, abortErr := api.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			UploadId: created.UploadId,
+		})
+		if abortErr != nil {
+			logger.Error("failed to abort multipart upload", "key", key, "uploadId", aws.ToString(created.UploadId), "err", abortErr)
+		}
+	}()
+
+	numParts := (int64(len(data)) + partSize - 1) / partSize
+	parts := make([]types.CompletedPart, numParts)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentParts)
+	for i := int64(0); i < numParts; i++ {
+		i := i
+		part := data[i*partSize : min((i+1)*partSize, int64(len(data)))]
+		partNumber := int32(i + 1)
+		group.Go(func() error {
+			output, err := api.UploadPart(groupCtx, &s3.UploadPartInput{
+				Bucket:     aws.String(bucket),
+				Key:        aws.String(key),
+				UploadId:   created.UploadId,
+				PartNumber: partNumber,
+				Body:       bytes.NewReader(part),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to upload part %d of %s: %w", partNumber, key, err)
+			}
+			parts[i] = types.CompletedPart{ETag: output.ETag, PartNumber: partNumber}
+			return nil
+		})
+	}
+	if err = group.Wait(); err != nil {
+		return err
+	}
+
+	_, err = api.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        created.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
+}
@@ -0,0 +1,93 @@
+package s3_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/aws/s3"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMultipartAPI assembles the uploaded parts, failing the upload of failPart if it isn't 0
+type fakeMultipartAPI struct {
+	mu        sync.Mutex
+	parts     map[int32][]byte
+	failPart  int32
+	completed []byte
+	metadata  map[string]string
+	aborted   bool
+}
+
+func (f *fakeMultipartAPI) CreateMultipartUpload(ctx context.Context, params *awss3.CreateMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.CreateMultipartUploadOutput, error) {
+	f.parts = make(map[int32][]byte)
+	f.metadata = params.Metadata
+	return &awss3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil
+}
+
+func (f *fakeMultipartAPI) UploadPart(ctx context.Context, params *awss3.UploadPartInput, optFns ...func(*awss3.Options)) (*awss3.UploadPartOutput, error) {
+	if params.PartNumber == f.failPart {
+		return nil, errors.New("part failed")
+	}
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.parts[params.PartNumber] = data
+	return &awss3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag%d", params.PartNumber))}, nil
+}
+
+func (f *fakeMultipartAPI) CompleteMultipartUpload(ctx context.Context, params *awss3.CompleteMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.CompleteMultipartUploadOutput, error) {
+	parts := params.MultipartUpload.Parts
+	if !sort.SliceIsSorted(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber }) {
+		return nil, errors.New("parts are not in order")
+	}
+	var completed bytes.Buffer
+	for _, part := range parts {
+		if aws.ToString(part.ETag) != fmt.Sprintf("etag%d", part.PartNumber) {
+			return nil, errors.New("wrong etag")
+		}
+		completed.Write(f.parts[part.PartNumber])
+	}
+	f.completed = completed.Bytes()
+	return &awss3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (f *fakeMultipartAPI) AbortMultipartUpload(ctx context.Context, params *awss3.AbortMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.AbortMultipartUploadOutput, error) {
+	f.aborted = true
+	return &awss3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestUploadMultipart(t *testing.T) {
+	ctx := context.Background()
+	// 3 parts of 5MiB, the minimum part size, and a last shorter one
+	data := bytes.Repeat([]byte("0123456789"), 1024*1024*16/10)
+	config := s3.MultipartConfig{ThresholdBytes: 1024, PartSize: 1024, MaxConcurrentParts: 2}
+
+	api := &fakeMultipartAPI{}
+	metadata := map[string]string{"content-md5": "md5"}
+	err := s3.UploadMultipart(ctx, api, "bucket", "key", data, metadata, config, &mock.Logger{})
+	assert.NoError(t, err)
+	assert.Len(t, api.parts, 4)
+	assert.Len(t, api.parts[1], 5*1024*1024)
+	assert.Equal(t, data, api.completed)
+	assert.Equal(t, metadata, api.metadata)
+	assert.False(t, api.aborted)
+
+	// the upload is aborted if a part fails
+	api = &fakeMultipartAPI{failPart: 3}
+	err = s3.UploadMultipart(ctx, api, "bucket", "key", data, nil, config, &mock.Logger{})
+	assert.Error(t, err)
+	assert.True(t, api.aborted)
+	assert.Nil(t, api.completed)
+}
@@ -1,11 +1,22 @@
 package common
 
-import "github.com/ethereum/go-ethereum/log"
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/log"
+)
 
 type Logger interface {
 	// New returns a new Logger that has this logger's context plus the given context
 	New(ctx ...interface{}) Logger
 
+	// WithField returns a new Logger that adds the given key/value pair to all its log messages
+	WithField(key string, value interface{}) Logger
+
+	// WithFields returns a new Logger that adds the given key/value pairs to all its log messages,
+	// sorted by key
+	WithFields(fields map[string]interface{}) Logger
+
 	// SetHandler updates the logger to write records to the specified handler.
 	SetHandler(h log.Handler)
 
@@ -83,3 +94,18 @@ type Logger interface {
 	// but should have same semantic as Critf
 	Fatalf(template string, args ...interface{})
 }
+
+// FieldsToContext returns the fields as key/value pairs sorted by key, so they are logged in a stable order
+func FieldsToContext(fields map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ctx := make([]interface{}, 0, 2*len(fields))
+	for _, key := range keys {
+		ctx = append(ctx, key, fields[key])
+	}
+	return ctx
+}
@@ -16,6 +16,14 @@ func (l *Logger) New(ctx ...interface{}) common.Logger {
 	return &Logger{Logger: l.Logger.New(ctx...)}
 }
 
+func (l *Logger) WithField(key string, value interface{}) common.Logger {
+	return l.New(key, value)
+}
+
+func (l *Logger) WithFields(fields map[string]interface{}) common.Logger {
+	return l.New(common.FieldsToContext(fields)...)
+}
+
 func (l *Logger) SetHandler(h log.Handler) {
 	l.Logger.SetHandler(h)
 }
@@ -0,0 +1,59 @@
+package logging_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/logging"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBufferLogger(buf *bytes.Buffer) *logging.Logger {
+	logger := &logging.Logger{Logger: log.New()}
+	logger.SetHandler(log.StreamHandler(buf, log.LogfmtFormat()))
+	return logger
+}
+
+func TestWithField(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newBufferLogger(&buf)
+	child := logger.WithField("component", "apiserver")
+
+	child.Info("info message", "key", "value")
+	child.Warn("warn message")
+	child.Error("error message")
+	child.Infof("formatted %d", 1)
+	child.New("nested", true).Info("nested message")
+	logger.Info("parent message")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 6)
+	for _, line := range lines[:5] {
+		assert.Contains(t, line, "component=apiserver")
+	}
+	assert.Contains(t, lines[0], "key=value")
+	assert.Contains(t, lines[4], "nested=true")
+	// the parent logger isn't changed
+	assert.NotContains(t, lines[5], "component=apiserver")
+}
+
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newBufferLogger(&buf)
+	child := logger.WithFields(map[string]interface{}{
+		"origin":    "127.0.0.1",
+		"component": "apiserver",
+	}).WithField("request", 7)
+
+	child.Info("first")
+	child.Error("second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		// the fields are sorted by key and come before the ones added later
+		assert.Contains(t, line, "component=apiserver origin=127.0.0.1 request=7")
+	}
+}
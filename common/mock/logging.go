@@ -9,6 +9,8 @@ import (
 
 type Logger struct {
 	print bool
+	// ctx is added to all the printed messages
+	ctx []interface{}
 }
 
 func NewLogger(print bool) common.Logger {
@@ -21,12 +23,30 @@ func (l *Logger) New(ctx ...interface{}) common.Logger {
 	return &Logger{}
 }
 
+func (l *Logger) WithField(key string, value interface{}) common.Logger {
+	return l.withContext(key, value)
+}
+
+func (l *Logger) WithFields(fields map[string]interface{}) common.Logger {
+	return l.withContext(common.FieldsToContext(fields)...)
+}
+
+func (l *Logger) withContext(ctx ...interface{}) *Logger {
+	newCtx := make([]interface{}, 0, len(l.ctx)+len(ctx))
+	newCtx = append(newCtx, l.ctx...)
+	return &Logger{
+		print: l.print,
+		ctx:   append(newCtx, ctx...),
+	}
+}
+
 func (l *Logger) printLog(level ethlog.Lvl, msg string, ctx ...interface{}) {
 	if l.print {
 		info := []interface{}{
 			level,
 			msg,
 		}
+		info = append(info, l.ctx...)
 		info = append(info, ctx...)
 		log.Println(info)
 	}
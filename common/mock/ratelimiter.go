@@ -9,6 +9,14 @@ import (
 type NoopRatelimiter struct {
 }
 
-func (r *NoopRatelimiter) AllowRequest(ctx context.Context, retrieverID string, blobSize uint, rate common.RateParam) (bool, error) {
-	return true, nil
+func (r *NoopRatelimiter) AllowRequest(ctx context.Context, retrieverID string, blobSize uint, rate common.RateParam) (bool, common.RateLimitedBy, error) {
+	return true, "", nil
+}
+
+func (r *NoopRatelimiter) GetBucketUsage(ctx context.Context, requesterID string) (float64, error) {
+	return 0, nil
+}
+
+func (r *NoopRatelimiter) InspectBucket(ctx context.Context, requesterID string) (*common.RateBucketStatus, error) {
+	return &common.RateBucketStatus{}, nil
 }
@@ -2,17 +2,23 @@ package mock
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"strings"
 
 	"github.com/0glabs/0g-data-avail/common/aws/s3"
 )
 
 type S3Client struct {
-	bucket map[string][]byte
+	bucket   map[string][]byte
+	metadata map[string]map[string]string
+	tags     map[string]map[string]string
 }
 
+var _ s3.ObjectStorage = (*S3Client)(nil)
+
 func NewS3Client() *S3Client {
-	return &S3Client{bucket: make(map[string][]byte)}
+	return &S3Client{bucket: make(map[string][]byte), metadata: make(map[string]map[string]string), tags: make(map[string]map[string]string)}
 }
 
 func (s *S3Client) DownloadObject(ctx context.Context, bucket string, key string) ([]byte, error) {
@@ -23,13 +29,43 @@ func (s *S3Client) DownloadObject(ctx context.Context, bucket string, key string
 	return data, nil
 }
 
+func (s *S3Client) HeadObject(ctx context.Context, bucket string, key string) (*s3.ObjectAttributes, error) {
+	data, ok := s.bucket[key]
+	if !ok {
+		return nil, s3.ErrObjectNotFound
+	}
+	sum := md5.Sum(data)
+	return &s3.ObjectAttributes{
+		ETag:     `"` + hex.EncodeToString(sum[:]) + `"`,
+		Metadata: s.metadata[key],
+	}, nil
+}
+
 func (s *S3Client) UploadObject(ctx context.Context, bucket string, key string, data []byte) error {
 	s.bucket[key] = data
 	return nil
 }
 
+func (s *S3Client) PutObject(ctx context.Context, bucket string, key string, data []byte, metadata map[string]string) error {
+	s.bucket[key] = data
+	s.metadata[key] = metadata
+	return nil
+}
+
 func (s *S3Client) DeleteObject(ctx context.Context, bucket string, key string) error {
 	delete(s.bucket, key)
+	delete(s.metadata, key)
+	delete(s.tags, key)
+	return nil
+}
+
+func (s *S3Client) CopyObject(ctx context.Context, bucket string, srcKey string, dstKey string) error {
+	data, ok := s.bucket[srcKey]
+	if !ok {
+		return s3.ErrObjectNotFound
+	}
+	s.bucket[dstKey] = data
+	s.metadata[dstKey] = s.metadata[srcKey]
 	return nil
 }
 
@@ -43,6 +79,19 @@ func (s *S3Client) ListObjects(ctx context.Context, bucket string, prefix string
 	return objects, nil
 }
 
+func (s *S3Client) PutObjectTags(ctx context.Context, bucket string, key string, tags map[string]string) error {
+	if _, ok := s.bucket[key]; !ok {
+		return s3.ErrObjectNotFound
+	}
+	s.tags[key] = tags
+	return nil
+}
+
+// ObjectTags returns the tags of the object
+func (s *S3Client) ObjectTags(key string) map[string]string {
+	return s.tags[key]
+}
+
 func (s *S3Client) CreateBucket(ctx context.Context, tableName string, region string) error {
 	return nil
 }
@@ -15,8 +15,35 @@ import (
 // ID is the authenticated Account ID. For retrieval requests, the requester ID will be the requester's IP address.
 type RequesterID = string
 
+// SystemRequesterPrefix prefixes the requester IDs of buckets shared by all requesters, e.g. the total throughput of a quorum.
+// The request count limit is a per requester limit, so it isn't applied to them.
+const SystemRequesterPrefix = "system:"
+
+// RateLimitedBy is the kind of bucket which was exhausted when a request is not allowed
+type RateLimitedBy string
+
+const (
+	// RateLimitedByBandwidth means the request exceeded the rate, i.e. the blob size based buckets are exhausted
+	RateLimitedByBandwidth RateLimitedBy = "bandwidth"
+	// RateLimitedByRequestCount means the requester made too many requests, see GlobalRateParams.RequestsPerSecond
+	RateLimitedByRequestCount RateLimitedBy = "request_count"
+	// RateLimitedByBlocklist means the requester is blocked, regardless of its usage
+	RateLimitedByBlocklist RateLimitedBy = "blocklist"
+)
+
 type RateLimiter interface {
-	AllowRequest(ctx context.Context, requesterID RequesterID, blobSize uint, rate RateParam) (bool, error)
+	// AllowRequest returns whether the request is allowed, and the kind of bucket which was exhausted if it isn't
+	AllowRequest(ctx context.Context, requesterID RequesterID, blobSize uint, rate RateParam) (bool, RateLimitedBy, error)
+	// GetBucketUsage returns the consumed fraction, in [0, 1], of the most consumed bandwidth bucket of the requester
+	GetBucketUsage(ctx context.Context, requesterID RequesterID) (float64, error)
+	// InspectBucket returns the current state of the buckets of the requester, without modifying them
+	InspectBucket(ctx context.Context, requesterID RequesterID) (*RateBucketStatus, error)
+}
+
+// BlocklistSetter is implemented by the rate limiters whose blocklist can be replaced while they are in use
+type BlocklistSetter interface {
+	// SetBlocklist replaces the requester IDs which are always rejected
+	SetBlocklist(ids []string)
 }
 
 type GlobalRateParams struct {
@@ -32,6 +59,15 @@ type GlobalRateParams struct {
 	Multipliers []float32
 	// CountFailed indicates whether failed requests should be counted towards the rate limit.
 	CountFailed bool
+	// RequestsPerSecond limits how many requests a single requester can make per second regardless of the blob size.
+	// Each request empties the request buckets by `1/(RequestsPerSecond*Multiplier[i])` seconds. Zero disables the limit.
+	RequestsPerSecond float64
+	// WindowDuration is the duration over which the sliding window rate limiter sums the requests, a requester may send
+	// up to RateParam*WindowDuration within any window. It is not used by the token bucket rate limiter.
+	WindowDuration time.Duration
+	// WindowGranularity is the duration of each of the counters the sliding window is made of, the window slides by
+	// one counter at a time
+	WindowGranularity time.Duration
 }
 
 // RateParam is the type used for expressing a bandwidth based rate limit in units of Bytes/second
@@ -42,8 +78,35 @@ type RateBucketParams struct {
 	// that the requester can consume one minute worth of bandwidth (in terms of amount of data, this equals RateParam * one minute)
 	// before the rate limiter will throttle them
 	BucketLevels []time.Duration
+	// RequestBucketLevels are the request count counterpart of BucketLevels, used when GlobalRateParams.RequestsPerSecond is set
+	RequestBucketLevels []time.Duration
 	// LastRequestTime stores the time of the last request received from a given requester. All times are stored in UTC.
 	LastRequestTime time.Time
+	// WindowBytes is the ring of per granule counters of the sliding window rate limiter, the counter of the granule
+	// starting at t is WindowBytes[(t/WindowGranularity) % len(WindowBytes)]
+	WindowBytes []uint64
+	// WindowRate is the rate the sliding window was last checked against
+	WindowRate RateParam
+}
+
+// RateBucketStatus is the state of the buckets of a requester, as returned by RateLimiter.InspectBucket. The bucket levels
+// include the time refilled since LastRequestTime, they are the levels a request made now would be checked against.
+type RateBucketStatus struct {
+	RateBucketParams
+	// GlobalRateParams are the parameters the buckets are limited with
+	GlobalRateParams GlobalRateParams
+}
+
+// FillPercentages returns how full each bandwidth bucket is, in [0, 100]
+func (s *RateBucketStatus) FillPercentages() []float64 {
+	percentages := make([]float64, len(s.GlobalRateParams.BucketSizes))
+	for i, size := range s.GlobalRateParams.BucketSizes {
+		if i >= len(s.BucketLevels) || size <= 0 {
+			continue
+		}
+		percentages[i] = float64(s.BucketLevels[i]) / float64(size) * 100
+	}
+	return percentages
 }
 
 // GetClientAddress returns the client address from the context. If the header is not empty, it will
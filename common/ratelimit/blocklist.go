@@ -0,0 +1,36 @@
+package ratelimit
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/0glabs/0g-data-avail/common"
+)
+
+// blocklist rejects the requesters matching one of its IDs, like the allowlist it matches the IDs contained in the
+// requester IDs. It is embedded by the rate limiters, so their blocklist can be replaced while they are in use.
+type blocklist struct {
+	mu  sync.RWMutex
+	ids []string
+}
+
+var _ common.BlocklistSetter = (*blocklist)(nil)
+
+// SetBlocklist replaces the blocked IDs
+func (b *blocklist) SetBlocklist(ids []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ids = ids
+}
+
+// blocked returns whether the requester is blocked
+func (b *blocklist) blocked(requesterID common.RequesterID) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, id := range b.ids {
+		if id != "" && strings.Contains(requesterID, id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	requesterIDAttribute = "RequesterID"
+	versionAttribute     = "Version"
+
+	// bucketReadCacheSize is the number of requesters whose last read buckets are kept to version their next update
+	bucketReadCacheSize = 100_000
+)
+
+// DynamoDBBucketStore stores the rate buckets in a DynamoDB table shared by all the disperser replicas, so a requester
+// is limited against the same buckets whichever replica it reaches. The table has the schema of store.GenerateTableSchema.
+//
+// The items are versioned: UpdateItem only writes the buckets if the item is still at the version GetItem read it at.
+// If another replica updated the item in the meantime, the update is retried once, with the consumption of the other
+// replica deducted from the buckets.
+type DynamoDBBucketStore struct {
+	client    *commondynamodb.Client
+	tableName string
+	// reads are the buckets last read of each requester, the base of their next update
+	reads *lru.Cache[string, bucketRead]
+}
+
+type bucketRead struct {
+	version int64
+	// params are nil if the requester had no buckets
+	params *common.RateBucketParams
+}
+
+var _ BucketStore = (*DynamoDBBucketStore)(nil)
+
+func NewDynamoDBBucketStore(client *commondynamodb.Client, tableName string) (*DynamoDBBucketStore, error) {
+	reads, err := lru.New[string, bucketRead](bucketReadCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &DynamoDBBucketStore{
+		client:    client,
+		tableName: tableName,
+		reads:     reads,
+	}, nil
+}
+
+// GetItem returns the buckets of the requester, or an error if it has none
+func (s *DynamoDBBucketStore) GetItem(ctx context.Context, requesterID string) (*common.RateBucketParams, error) {
+	params, version, err := s.getItem(ctx, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	s.reads.Add(requesterID, bucketRead{version: version, params: copyBucketParams(params)})
+	if params == nil {
+		return nil, errors.New("item not found")
+	}
+	return params, nil
+}
+
+// UpdateItem writes the buckets of the requester if they weren't updated since they were last read. Otherwise the
+// consumption of the concurrent update is deducted from the buckets, and they are written if the item didn't change again.
+func (s *DynamoDBBucketStore) UpdateItem(ctx context.Context, requesterID string, params *common.RateBucketParams) error {
+	read, ok := s.reads.Get(requesterID)
+	// the read is only the base of one update, a concurrent request of the same requester conflicts instead of
+	// overwriting this update
+	s.reads.Remove(requesterID)
+	if !ok {
+		read = bucketRead{}
+	}
+
+	err := s.putItem(ctx, requesterID, params, read.version)
+	if !errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return err
+	}
+
+	current, version, err := s.getItem(ctx, requesterID)
+	if err != nil {
+		return err
+	}
+	err = s.putItem(ctx, requesterID, mergeBucketParams(read.params, params, current), version)
+	if errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return fmt.Errorf("rate buckets of %s updated concurrently: %w", requesterID, err)
+	}
+	return err
+}
+
+// getItem returns the buckets of the requester and their version, the buckets are nil and the version 0 if it has none
+func (s *DynamoDBBucketStore) getItem(ctx context.Context, requesterID string) (*common.RateBucketParams, int64, error) {
+	item, err := s.client.GetItem(ctx, s.tableName, commondynamodb.Key{
+		requesterIDAttribute: &types.AttributeValueMemberS{Value: requesterID},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if item == nil {
+		return nil, 0, nil
+	}
+
+	params := new(common.RateBucketParams)
+	if err := attributevalue.UnmarshalMap(item, params); err != nil {
+		return nil, 0, err
+	}
+	// the items written before the buckets were versioned are at version 0
+	var version int64
+	if value, ok := item[versionAttribute]; ok {
+		if err := attributevalue.Unmarshal(value, &version); err != nil {
+			return nil, 0, err
+		}
+	}
+	return params, version, nil
+}
+
+// putItem writes the buckets of the requester at version+1 if the item is at version
+func (s *DynamoDBBucketStore) putItem(ctx context.Context, requesterID string, params *common.RateBucketParams, version int64) error {
+	item, err := attributevalue.MarshalMap(params)
+	if err != nil {
+		return err
+	}
+	item[requesterIDAttribute] = &types.AttributeValueMemberS{Value: requesterID}
+	item[versionAttribute] = &types.AttributeValueMemberN{Value: fmt.Sprint(version + 1)}
+
+	condition := expression.AttributeNotExists(expression.Name(versionAttribute))
+	if version > 0 {
+		condition = expression.Name(versionAttribute).Equal(expression.Value(version))
+	}
+	return s.client.PutItemWithCondition(ctx, s.tableName, item, condition)
+}
+
+// mergeBucketParams returns the updated buckets with the consumption of a concurrent update, i.e. the difference between
+// the read and the current buckets, deducted. If there were no buckets when they were read, the concurrent consumption
+// is unknown and the least filled of the updated and the current buckets are kept.
+func mergeBucketParams(read, updated, current *common.RateBucketParams) *common.RateBucketParams {
+	merged := copyBucketParams(updated)
+	if current == nil {
+		return merged
+	}
+
+	var refilled time.Duration
+	var readBucketLevels, readRequestBucketLevels []time.Duration
+	var readWindowBytes []uint64
+	if read != nil {
+		// the current buckets were refilled from the read levels since the read request
+		refilled = current.LastRequestTime.Sub(read.LastRequestTime)
+		readBucketLevels, readRequestBucketLevels, readWindowBytes = read.BucketLevels, read.RequestBucketLevels, read.WindowBytes
+	}
+	mergeBucketLevels(merged.BucketLevels, readBucketLevels, current.BucketLevels, refilled, read != nil)
+	mergeBucketLevels(merged.RequestBucketLevels, readRequestBucketLevels, current.RequestBucketLevels, refilled, read != nil)
+
+	if len(current.WindowBytes) == len(merged.WindowBytes) {
+		for i, bytes := range current.WindowBytes {
+			var readBytes uint64
+			if i < len(readWindowBytes) {
+				readBytes = readWindowBytes[i]
+			}
+			if bytes > readBytes {
+				merged.WindowBytes[i] += bytes - readBytes
+			}
+		}
+	}
+	if current.LastRequestTime.After(merged.LastRequestTime) {
+		merged.LastRequestTime = current.LastRequestTime
+	}
+	return merged
+}
+
+func mergeBucketLevels(merged, read, current []time.Duration, refilled time.Duration, wasRead bool) {
+	for i := range merged {
+		if i >= len(current) {
+			break
+		}
+		if !wasRead || i >= len(read) {
+			merged[i] = min(merged[i], current[i])
+			continue
+		}
+		if consumed := read[i] + refilled - current[i]; consumed > 0 {
+			merged[i] -= consumed
+		}
+	}
+}
+
+func copyBucketParams(params *common.RateBucketParams) *common.RateBucketParams {
+	if params == nil {
+		return nil
+	}
+	c := *params
+	c.BucketLevels = append([]time.Duration(nil), params.BucketLevels...)
+	c.RequestBucketLevels = append([]time.Duration(nil), params.RequestBucketLevels...)
+	c.WindowBytes = append([]uint64(nil), params.WindowBytes...)
+	return &c
+}
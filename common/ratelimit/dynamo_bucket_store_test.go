@@ -0,0 +1,106 @@
+//go:build integration
+
+package ratelimit_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/aws"
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	test_utils "github.com/0glabs/0g-data-avail/common/aws/dynamodb/utils"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/inabox/deploy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The test runs against DynamoDB provided by localstack, which is started in docker unless DEPLOY_LOCALSTACK=false,
+// in which case an instance must be listening on LOCALSTACK_PORT.
+//
+//	go test -tags integration -run TestDynamoDBBucketStore ./common/ratelimit/
+
+func TestDynamoDBBucketStoreConcurrentUpdates(t *testing.T) {
+	localStackPort := "4566"
+	if os.Getenv("DEPLOY_LOCALSTACK") == "false" {
+		localStackPort = os.Getenv("LOCALSTACK_PORT")
+	} else {
+		pool, resource, err := deploy.StartDockertestWithLocalstackContainer(localStackPort)
+		require.NoError(t, err, "failed to start localstack container")
+		t.Cleanup(func() {
+			deploy.PurgeDockertestResources(pool, resource)
+		})
+	}
+
+	ctx := context.Background()
+	cfg := aws.ClientConfig{
+		Region:          "us-east-1",
+		AccessKey:       "localstack",
+		SecretAccessKey: "localstack",
+		EndpointURL:     fmt.Sprintf("http://0.0.0.0:%s", localStackPort),
+	}
+	tableName := "test-DynamoDBBucketStore"
+	_, err := test_utils.CreateTable(ctx, cfg, tableName, store.GenerateTableSchema(10, 10, tableName))
+	require.NoError(t, err)
+	client, err := commondynamodb.NewClient(cfg, &mock.Logger{})
+	require.NoError(t, err)
+
+	// two replicas sharing the table
+	replica1, err := ratelimit.NewDynamoDBBucketStore(client, tableName)
+	require.NoError(t, err)
+	replica2, err := ratelimit.NewDynamoDBBucketStore(client, tableName)
+	require.NoError(t, err)
+
+	requesterID := "requester"
+	_, err = replica1.GetItem(ctx, requesterID)
+	assert.Error(t, err)
+	lastRequestTime := time.Now().UTC().Truncate(time.Millisecond)
+	assert.NoError(t, replica1.UpdateItem(ctx, requesterID, &common.RateBucketParams{
+		BucketLevels:    []time.Duration{10 * time.Second},
+		LastRequestTime: lastRequestTime,
+	}))
+
+	// both replicas read the same buckets and consume from them
+	params1, err := replica1.GetItem(ctx, requesterID)
+	require.NoError(t, err)
+	params2, err := replica2.GetItem(ctx, requesterID)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, params1.BucketLevels[0])
+
+	params1.BucketLevels[0] -= 2 * time.Second
+	assert.NoError(t, replica1.UpdateItem(ctx, requesterID, params1))
+	// the update of the second replica conflicts, the consumption of the first one is deducted from it
+	params2.BucketLevels[0] -= 3 * time.Second
+	assert.NoError(t, replica2.UpdateItem(ctx, requesterID, params2))
+
+	params, err := replica1.GetItem(ctx, requesterID)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, params.BucketLevels[0])
+	assert.True(t, lastRequestTime.Equal(params.LastRequestTime))
+
+	// a rate limiter on each replica limits the requester against the shared buckets
+	globalParams := common.GlobalRateParams{
+		BucketSizes: []time.Duration{time.Minute},
+		Multipliers: []float32{1},
+	}
+	limiter1 := ratelimit.NewRateLimiter(globalParams, replica1, nil, nil, nil, &mock.Logger{})
+	limiter2 := ratelimit.NewRateLimiter(globalParams, replica2, nil, nil, nil, &mock.Logger{})
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		for _, limiter := range []common.RateLimiter{limiter1, limiter2} {
+			// each request consumes 11s of the 1 minute bucket
+			ok, _, err := limiter.AllowRequest(ctx, "limited", 11, 1)
+			assert.NoError(t, err)
+			if ok {
+				allowed++
+			}
+		}
+	}
+	assert.Equal(t, 5, allowed)
+}
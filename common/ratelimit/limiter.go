@@ -15,26 +15,37 @@ type rateLimiter struct {
 
 	bucketStore BucketStore
 	allowlist   []string
+	blocklist
 
-	logger common.Logger
+	metrics *Metrics
+	logger  common.Logger
 }
 
-func NewRateLimiter(rateParams common.GlobalRateParams, bucketStore BucketStore, allowlist []string, logger common.Logger) common.RateLimiter {
-	return &rateLimiter{
+func NewRateLimiter(rateParams common.GlobalRateParams, bucketStore BucketStore, allowlist []string, blocklist []string, metrics *Metrics, logger common.Logger) common.RateLimiter {
+	limiter := &rateLimiter{
 		globalRateParams: rateParams,
 		bucketStore:      bucketStore,
 		allowlist:        allowlist,
+		metrics:          metrics,
 		logger:           logger,
 	}
+	limiter.SetBlocklist(blocklist)
+	return limiter
 }
 
 // Checks whether a request from the given requesterID is allowed
-func (d *rateLimiter) AllowRequest(ctx context.Context, requesterID common.RequesterID, blobSize uint, rate common.RateParam) (bool, error) {
+func (d *rateLimiter) AllowRequest(ctx context.Context, requesterID common.RequesterID, blobSize uint, rate common.RateParam) (bool, common.RateLimitedBy, error) {
+	// blocked requesters are rejected before the allowlist and without touching their buckets
+	if d.blocked(requesterID) {
+		d.metrics.IncrementRequestsBlocked()
+		return false, common.RateLimitedByBlocklist, nil
+	}
+
 	// TODO: temporary allowlist that unconditionally allows request
 	// for testing purposes only
 	for _, id := range d.allowlist {
 		if strings.Contains(requesterID, id) {
-			return true, nil
+			return true, "", nil
 		}
 	}
 
@@ -53,6 +64,15 @@ func (d *rateLimiter) AllowRequest(ctx context.Context, requesterID common.Reque
 		}
 	}
 
+	// The request count limit only applies to actual requesters
+	limitRequests := d.globalRateParams.RequestsPerSecond > 0 && !strings.HasPrefix(requesterID, common.SystemRequesterPrefix)
+
+	// Buckets stored before the request count limit was enabled start out full
+	if limitRequests && len(bucketParams.RequestBucketLevels) != len(d.globalRateParams.BucketSizes) {
+		bucketParams.RequestBucketLevels = make([]time.Duration, len(d.globalRateParams.BucketSizes))
+		copy(bucketParams.RequestBucketLevels, d.globalRateParams.BucketSizes)
+	}
+
 	// Check whether the request is allowed based on the rate
 
 	// Get interval since last request
@@ -71,22 +91,110 @@ func (d *rateLimiter) AllowRequest(ctx context.Context, requesterID common.Reque
 
 		allowed = allowed && bucketParams.BucketLevels[i] > 0
 	}
+	var limitedBy common.RateLimitedBy
+	if !allowed {
+		limitedBy = common.RateLimitedByBandwidth
+	}
+
+	// Each request deducts one request from the request buckets regardless of the blob size
+	if limitRequests {
+		requestsAllowed := true
+		for i, size := range d.globalRateParams.BucketSizes {
+			deduction := time.Microsecond * time.Duration(1e6/(d.globalRateParams.RequestsPerSecond*float64(d.globalRateParams.Multipliers[i])))
+
+			bucketParams.RequestBucketLevels[i] = getBucketLevel(bucketParams.RequestBucketLevels[i], size, interval, deduction)
+
+			requestsAllowed = requestsAllowed && bucketParams.RequestBucketLevels[i] > 0
+		}
+
+		if !requestsAllowed {
+			d.metrics.IncrementRequestsPerSecondRejected(requesterID)
+			if limitedBy == "" {
+				limitedBy = common.RateLimitedByRequestCount
+			}
+		}
+		allowed = allowed && requestsAllowed
+	}
 
 	// Update the bucket based on blob size and current rate
 	if allowed || d.globalRateParams.CountFailed {
 		// Update bucket params
 		err := d.bucketStore.UpdateItem(ctx, requesterID, bucketParams)
 		if err != nil {
-			return allowed, err
+			return allowed, limitedBy, err
 		}
 
 	}
 
-	return allowed, nil
+	return allowed, limitedBy, nil
 
 	// (DA Node) Store the rate params and account ID along with the blob
 }
 
+// GetBucketUsage returns the consumed fraction of the most consumed bandwidth bucket of the requester, taking into
+// account the buckets refilled since its last request. Requesters without buckets haven't consumed anything.
+func (d *rateLimiter) GetBucketUsage(ctx context.Context, requesterID common.RequesterID) (float64, error) {
+	bucketParams, err := d.bucketStore.GetItem(ctx, requesterID)
+	if err != nil {
+		return 0, nil
+	}
+
+	interval := time.Since(bucketParams.LastRequestTime)
+	usage := 0.0
+	for i, size := range d.globalRateParams.BucketSizes {
+		if i >= len(bucketParams.BucketLevels) || size <= 0 {
+			continue
+		}
+		level := getBucketLevel(bucketParams.BucketLevels[i], size, interval, 0)
+		if consumed := float64(size-level) / float64(size); consumed > usage {
+			usage = consumed
+		}
+	}
+	return usage, nil
+}
+
+// InspectBucket returns the bucket levels of the requester as of now, i.e. refilled since its last request. Requesters
+// without buckets have full buckets and a zero LastRequestTime. The stored buckets are not modified.
+func (d *rateLimiter) InspectBucket(ctx context.Context, requesterID common.RequesterID) (*common.RateBucketStatus, error) {
+	status := &common.RateBucketStatus{
+		RateBucketParams: common.RateBucketParams{
+			BucketLevels: make([]time.Duration, len(d.globalRateParams.BucketSizes)),
+		},
+		GlobalRateParams: d.globalRateParams,
+	}
+	limitRequests := d.globalRateParams.RequestsPerSecond > 0 && !strings.HasPrefix(requesterID, common.SystemRequesterPrefix)
+	if limitRequests {
+		status.RequestBucketLevels = make([]time.Duration, len(d.globalRateParams.BucketSizes))
+	}
+
+	bucketParams, err := d.bucketStore.GetItem(ctx, requesterID)
+	if err != nil {
+		copy(status.BucketLevels, d.globalRateParams.BucketSizes)
+		copy(status.RequestBucketLevels, d.globalRateParams.BucketSizes)
+		return status, nil
+	}
+
+	status.LastRequestTime = bucketParams.LastRequestTime
+	interval := time.Since(bucketParams.LastRequestTime)
+	for i, size := range d.globalRateParams.BucketSizes {
+		level := size
+		if i < len(bucketParams.BucketLevels) {
+			level = getBucketLevel(bucketParams.BucketLevels[i], size, interval, 0)
+		}
+		status.BucketLevels[i] = level
+
+		if limitRequests {
+			// request buckets stored before the request count limit was enabled are full
+			level = size
+			if len(bucketParams.RequestBucketLevels) == len(d.globalRateParams.BucketSizes) {
+				level = getBucketLevel(bucketParams.RequestBucketLevels[i], size, interval, 0)
+			}
+			status.RequestBucketLevels[i] = level
+		}
+	}
+	return status, nil
+}
+
 func getBucketLevel(bucketLevel, bucketSize, interval, deduction time.Duration) time.Duration {
 
 	newLevel := bucketLevel + interval - deduction
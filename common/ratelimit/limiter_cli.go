@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
@@ -16,6 +17,20 @@ const (
 	CountFailedFlagName       = "count-failed"
 	BucketStoreSizeFlagName   = "bucket-store-size"
 	AllowlistFlagName         = "allowlist"
+	RequestsPerSecondFlagName = "requests-per-second"
+	RateLimiterTypeFlagName   = "rate-limiter-type"
+	WindowDurationFlagName    = "window-duration"
+	WindowGranularityFlagName = "window-granularity"
+)
+
+// RateLimiterType selects the algorithm of the rate limiter
+type RateLimiterType string
+
+const (
+	// TokenBucketRateLimiterType refills buckets of BucketSizes over time, see NewRateLimiter
+	TokenBucketRateLimiterType RateLimiterType = "token-bucket"
+	// SlidingWindowRateLimiterType sums the requests within a window of WindowDuration, see NewSlidingWindowRateLimiter
+	SlidingWindowRateLimiterType RateLimiterType = "sliding-window"
 )
 
 type Config struct {
@@ -23,6 +38,18 @@ type Config struct {
 	BucketStoreSize  int
 	UniformRateParam common.RateParam
 	Allowlist        []string
+	// Blocklist is the requester IDs which are always rejected, e.g. abusive IPs
+	Blocklist []string
+	// RateLimiterType is the algorithm of the rate limiter, the token bucket is used if empty
+	RateLimiterType RateLimiterType
+}
+
+// NewRateLimiterFromConfig creates the rate limiter of the configured type
+func NewRateLimiterFromConfig(cfg Config, bucketStore BucketStore, metrics *Metrics, logger common.Logger) common.RateLimiter {
+	if cfg.RateLimiterType == SlidingWindowRateLimiterType {
+		return NewSlidingWindowRateLimiter(cfg.GlobalRateParams, bucketStore, cfg.Allowlist, cfg.Blocklist, metrics, logger)
+	}
+	return NewRateLimiter(cfg.GlobalRateParams, bucketStore, cfg.Allowlist, cfg.Blocklist, metrics, logger)
 }
 
 func RatelimiterCLIFlags(envPrefix string, flagPrefix string) []cli.Flag {
@@ -61,22 +88,80 @@ func RatelimiterCLIFlags(envPrefix string, flagPrefix string) []cli.Flag {
 			Required: false,
 			Value:    &cli.StringSlice{},
 		},
+		cli.Float64Flag{
+			Name:     common.PrefixFlag(flagPrefix, RequestsPerSecondFlagName),
+			Usage:    "Number of requests per second allowed for a single requester regardless of blob size (0 disables the limit)",
+			EnvVar:   common.PrefixEnvVar(envPrefix, "REQUESTS_PER_SECOND"),
+			Required: false,
+		},
+		cli.StringFlag{
+			Name:     common.PrefixFlag(flagPrefix, RateLimiterTypeFlagName),
+			Usage:    "Rate limiter algorithm, token-bucket or sliding-window",
+			Value:    string(TokenBucketRateLimiterType),
+			EnvVar:   common.PrefixEnvVar(envPrefix, "RATE_LIMITER_TYPE"),
+			Required: false,
+		},
+		cli.DurationFlag{
+			Name:     common.PrefixFlag(flagPrefix, WindowDurationFlagName),
+			Usage:    "Duration of the window of the sliding-window rate limiter",
+			Value:    time.Minute,
+			EnvVar:   common.PrefixEnvVar(envPrefix, "WINDOW_DURATION"),
+			Required: false,
+		},
+		cli.DurationFlag{
+			Name:     common.PrefixFlag(flagPrefix, WindowGranularityFlagName),
+			Usage:    "Duration of each counter of the window of the sliding-window rate limiter",
+			Value:    time.Second,
+			EnvVar:   common.PrefixEnvVar(envPrefix, "WINDOW_GRANULARITY"),
+			Required: false,
+		},
 	}
 }
 
+// ParseBlocklist parses a comma separated list of requester IDs, ignoring the blanks around and between them
+func ParseBlocklist(list string) []string {
+	ids := make([]string, 0)
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 func DefaultCLIConfig() Config {
 	return Config{}
 }
 
-func validateConfig(cfg Config) error {
-	if len(cfg.BucketSizes) != len(cfg.Multipliers) {
+// ValidateGlobalRateParams checks the rate params are consistent, i.e. each bucket has a positive size and multiplier
+func ValidateGlobalRateParams(params common.GlobalRateParams) error {
+	if len(params.BucketSizes) != len(params.Multipliers) {
 		return errors.New("number of bucket sizes does not match number of multipliers")
 	}
-	for _, mult := range cfg.Multipliers {
+	for _, size := range params.BucketSizes {
+		if size <= 0 {
+			return fmt.Errorf("bucket size must be positive")
+		}
+	}
+	for _, mult := range params.Multipliers {
 		if mult <= 0 {
 			return fmt.Errorf("multiplier must be positive")
 		}
 	}
+	if params.RequestsPerSecond < 0 {
+		return errors.New("requests per second must not be negative")
+	}
+	return nil
+}
+
+// ValidateWindowParams checks the sliding window has a positive duration made of at least one granule
+func ValidateWindowParams(params common.GlobalRateParams) error {
+	if params.WindowDuration <= 0 || params.WindowGranularity <= 0 {
+		return errors.New("window duration and granularity must be positive")
+	}
+	if params.WindowGranularity > params.WindowDuration {
+		return errors.New("window granularity must not exceed the window duration")
+	}
 	return nil
 }
 
@@ -107,11 +192,25 @@ func ReadCLIConfig(ctx *cli.Context, flagPrefix string) (Config, error) {
 	cfg.GlobalRateParams.CountFailed = ctx.Bool(common.PrefixFlag(flagPrefix, CountFailedFlagName))
 	cfg.BucketStoreSize = ctx.Int(common.PrefixFlag(flagPrefix, BucketStoreSizeFlagName))
 	cfg.Allowlist = ctx.StringSlice(common.PrefixFlag(flagPrefix, AllowlistFlagName))
+	cfg.GlobalRateParams.RequestsPerSecond = ctx.Float64(common.PrefixFlag(flagPrefix, RequestsPerSecondFlagName))
+	cfg.GlobalRateParams.WindowDuration = ctx.Duration(common.PrefixFlag(flagPrefix, WindowDurationFlagName))
+	cfg.GlobalRateParams.WindowGranularity = ctx.Duration(common.PrefixFlag(flagPrefix, WindowGranularityFlagName))
 
-	err := validateConfig(cfg)
+	err := ValidateGlobalRateParams(cfg.GlobalRateParams)
 	if err != nil {
 		return Config{}, err
 	}
 
+	cfg.RateLimiterType = RateLimiterType(ctx.String(common.PrefixFlag(flagPrefix, RateLimiterTypeFlagName)))
+	switch cfg.RateLimiterType {
+	case "", TokenBucketRateLimiterType:
+	case SlidingWindowRateLimiterType:
+		if err := ValidateWindowParams(cfg.GlobalRateParams); err != nil {
+			return Config{}, err
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown rate limiter type: %s", cfg.RateLimiterType)
+	}
+
 	return cfg, nil
 }
@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type Metrics struct {
+	RequestsPerSecondRejected *prometheus.CounterVec
+	// RequestsBlocked counts the requests of blocked requesters, separately from the requests over their limits
+	RequestsBlocked prometheus.Counter
+}
+
+func NewMetrics(reg prometheus.Registerer, namespace string) *Metrics {
+	return &Metrics{
+		RequestsPerSecondRejected: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "requests_per_second_rejected_total",
+				Help:      "the number of requests rejected because the requester exceeded the request count limit",
+			},
+			[]string{"requester_id"},
+		),
+		RequestsBlocked: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "requests_blocked_total",
+				Help:      "the number of requests rejected because the requester is in the blocklist",
+			},
+		),
+	}
+}
+
+// IncrementRequestsPerSecondRejected is a no-op when the rate limiter has no metrics
+func (m *Metrics) IncrementRequestsPerSecondRejected(requesterID string) {
+	if m == nil {
+		return
+	}
+	m.RequestsPerSecondRejected.WithLabelValues(requesterID).Inc()
+}
+
+// IncrementRequestsBlocked is a no-op when the rate limiter has no metrics
+func (m *Metrics) IncrementRequestsBlocked() {
+	if m == nil {
+		return
+	}
+	m.RequestsBlocked.Inc()
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/0glabs/0g-data-avail/common/mock"
 	"github.com/0glabs/0g-data-avail/common/ratelimit"
 	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,7 +27,7 @@ func makeTestRatelimiter() (common.RateLimiter, error) {
 		return nil, err
 	}
 
-	ratelimiter := ratelimit.NewRateLimiter(globalParams, bucketStore, []string{"testRetriever2"}, &mock.Logger{})
+	ratelimiter := ratelimit.NewRateLimiter(globalParams, bucketStore, []string{"testRetriever2"}, nil, nil, &mock.Logger{})
 
 	return ratelimiter, nil
 
@@ -41,14 +43,15 @@ func TestRatelimit(t *testing.T) {
 	retreiverID := "testRetriever"
 
 	for i := 0; i < 10; i++ {
-		allow, err := ratelimiter.AllowRequest(ctx, retreiverID, 10, 100)
+		allow, _, err := ratelimiter.AllowRequest(ctx, retreiverID, 10, 100)
 		assert.NoError(t, err)
 		assert.Equal(t, true, allow)
 	}
 
-	allow, err := ratelimiter.AllowRequest(ctx, retreiverID, 10, 100)
+	allow, limitedBy, err := ratelimiter.AllowRequest(ctx, retreiverID, 10, 100)
 	assert.NoError(t, err)
 	assert.Equal(t, false, allow)
+	assert.Equal(t, common.RateLimitedByBandwidth, limitedBy)
 }
 
 func TestRatelimitAllowlist(t *testing.T) {
@@ -61,8 +64,161 @@ func TestRatelimitAllowlist(t *testing.T) {
 
 	// 10x more requests allowed for allowlisted IDs
 	for i := 0; i < 100; i++ {
-		allow, err := ratelimiter.AllowRequest(ctx, retreiverID, 10, 100)
+		allow, _, err := ratelimiter.AllowRequest(ctx, retreiverID, 10, 100)
 		assert.NoError(t, err)
 		assert.Equal(t, true, allow)
 	}
 }
+
+func TestRatelimitBlocklist(t *testing.T) {
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](1000)
+	assert.NoError(t, err)
+	metrics := ratelimit.NewMetrics(prometheus.NewRegistry(), "test")
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{time.Second},
+		Multipliers: []float32{1},
+	}, bucketStore, []string{"10.0.0.1"}, []string{"10.0.0.1", "10.0.0.2"}, metrics, &mock.Logger{})
+	ctx := context.Background()
+
+	// the blocklist is checked before the allowlist, without touching the buckets
+	for _, requesterID := range []string{"10.0.0.1:0-bytes", "10.0.0.2:0-blobs"} {
+		allow, limitedBy, err := ratelimiter.AllowRequest(ctx, requesterID, 10, 100)
+		assert.NoError(t, err)
+		assert.False(t, allow)
+		assert.Equal(t, common.RateLimitedByBlocklist, limitedBy)
+		_, err = bucketStore.GetItem(ctx, requesterID)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.RequestsBlocked))
+
+	allow, _, err := ratelimiter.AllowRequest(ctx, "10.0.0.3:0-bytes", 10, 100)
+	assert.NoError(t, err)
+	assert.True(t, allow)
+
+	// the blocklist can be replaced while the rate limiter is in use
+	ratelimiter.(common.BlocklistSetter).SetBlocklist([]string{"10.0.0.3"})
+	allow, _, err = ratelimiter.AllowRequest(ctx, "10.0.0.2:0-bytes", 10, 100)
+	assert.NoError(t, err)
+	assert.True(t, allow)
+	allow, _, err = ratelimiter.AllowRequest(ctx, "10.0.0.3:0-bytes", 10, 100)
+	assert.NoError(t, err)
+	assert.False(t, allow)
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.RequestsBlocked))
+}
+
+func TestParseBlocklist(t *testing.T) {
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, ratelimit.ParseBlocklist(" 10.0.0.1, ,10.0.0.2,"))
+	assert.Empty(t, ratelimit.ParseBlocklist(""))
+}
+
+func TestRatelimitRequestsPerSecond(t *testing.T) {
+	globalParams := common.GlobalRateParams{
+		BucketSizes:       []time.Duration{100 * time.Millisecond},
+		Multipliers:       []float32{1},
+		RequestsPerSecond: 100,
+	}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](1000)
+	assert.NoError(t, err)
+	metrics := ratelimit.NewMetrics(prometheus.NewRegistry(), "test")
+	ratelimiter := ratelimit.NewRateLimiter(globalParams, bucketStore, nil, nil, metrics, &mock.Logger{})
+
+	ctx := context.Background()
+
+	// Small blobs at a high bandwidth rate only exercise the request count limit
+	var blobSize uint = 1
+	var rate common.RateParam = 1e6
+
+	// Below the limit: one request every 15ms refills more than the 10ms each request deducts
+	for i := 0; i < 20; i++ {
+		allow, _, err := ratelimiter.AllowRequest(ctx, "slowRequester", blobSize, rate)
+		assert.NoError(t, err)
+		assert.True(t, allow)
+		time.Sleep(15 * time.Millisecond)
+	}
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.RequestsPerSecondRejected.WithLabelValues("slowRequester")))
+
+	// Above the limit: a burst drains the 100ms bucket after 10 requests
+	for i := 0; i < 10; i++ {
+		allow, _, err := ratelimiter.AllowRequest(ctx, "fastRequester", blobSize, rate)
+		assert.NoError(t, err)
+		assert.True(t, allow)
+	}
+	allow, limitedBy, err := ratelimiter.AllowRequest(ctx, "fastRequester", blobSize, rate)
+	assert.NoError(t, err)
+	assert.False(t, allow)
+	assert.Equal(t, common.RateLimitedByRequestCount, limitedBy)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RequestsPerSecondRejected.WithLabelValues("fastRequester")))
+
+	// The buckets shared by all requesters are not limited by the request count
+	for i := 0; i < 20; i++ {
+		allow, _, err := ratelimiter.AllowRequest(ctx, common.SystemRequesterPrefix+"0-bytes", blobSize, rate)
+		assert.NoError(t, err)
+		assert.True(t, allow)
+	}
+
+	// The bucket refills once the requester slows down
+	time.Sleep(50 * time.Millisecond)
+	allow, _, err = ratelimiter.AllowRequest(ctx, "fastRequester", blobSize, rate)
+	assert.NoError(t, err)
+	assert.True(t, allow)
+}
+
+func TestRatelimitGetBucketUsage(t *testing.T) {
+	ratelimiter, err := makeTestRatelimiter()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	retreiverID := "testRetriever"
+
+	usage, err := ratelimiter.GetBucketUsage(ctx, retreiverID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, usage)
+
+	// half of the 1s bucket is consumed
+	_, _, err = ratelimiter.AllowRequest(ctx, retreiverID, 50, 100)
+	assert.NoError(t, err)
+	usage, err = ratelimiter.GetBucketUsage(ctx, retreiverID)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, usage, 0.05)
+
+	// the bucket is refilled over time
+	time.Sleep(300 * time.Millisecond)
+	refilled, err := ratelimiter.GetBucketUsage(ctx, retreiverID)
+	assert.NoError(t, err)
+	assert.Less(t, refilled, usage)
+}
+
+func TestRatelimitInspectBucket(t *testing.T) {
+	ratelimiter, err := makeTestRatelimiter()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	retreiverID := "testRetriever"
+
+	status, err := ratelimiter.InspectBucket(ctx, retreiverID)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, time.Minute}, status.BucketLevels)
+	assert.True(t, status.LastRequestTime.IsZero())
+	assert.Equal(t, []float32{1, 1}, status.GlobalRateParams.Multipliers)
+	assert.Equal(t, []float64{100, 100}, status.FillPercentages())
+
+	// half of the 1s bucket is consumed
+	_, _, err = ratelimiter.AllowRequest(ctx, retreiverID, 50, 100)
+	assert.NoError(t, err)
+	status, err = ratelimiter.InspectBucket(ctx, retreiverID)
+	assert.NoError(t, err)
+	assert.False(t, status.LastRequestTime.IsZero())
+	assert.InDelta(t, 50, status.FillPercentages()[0], 5)
+	assert.InDelta(t, 99.2, status.FillPercentages()[1], 0.1)
+
+	// inspecting the bucket doesn't consume it
+	for i := 0; i < 10; i++ {
+		_, err = ratelimiter.InspectBucket(ctx, retreiverID)
+		assert.NoError(t, err)
+	}
+	allow, _, err := ratelimiter.AllowRequest(ctx, retreiverID, 40, 100)
+	assert.NoError(t, err)
+	assert.True(t, allow)
+}
@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+)
+
+// SlidingWindowRateLimiter limits the bytes sent by a requester within a sliding window of WindowDuration to
+// RateParam*WindowDuration. Unlike the token bucket, a burst stops counting against the requester as soon as it leaves
+// the window. The window is a ring of per granule byte counters stored in the bucket store, see RateBucketParams.
+// The bucket sizes, multipliers and request count limit of the GlobalRateParams are not used.
+type SlidingWindowRateLimiter struct {
+	globalRateParams common.GlobalRateParams
+
+	bucketStore BucketStore
+	allowlist   []string
+	blocklist
+
+	metrics *Metrics
+	logger  common.Logger
+}
+
+func NewSlidingWindowRateLimiter(rateParams common.GlobalRateParams, bucketStore BucketStore, allowlist []string, blocklist []string, metrics *Metrics, logger common.Logger) common.RateLimiter {
+	limiter := &SlidingWindowRateLimiter{
+		globalRateParams: rateParams,
+		bucketStore:      bucketStore,
+		allowlist:        allowlist,
+		metrics:          metrics,
+		logger:           logger,
+	}
+	limiter.SetBlocklist(blocklist)
+	return limiter
+}
+
+// AllowRequest allows the request if the bytes sent within the window, including blobSize, don't exceed rate*WindowDuration
+func (l *SlidingWindowRateLimiter) AllowRequest(ctx context.Context, requesterID common.RequesterID, blobSize uint, rate common.RateParam) (bool, common.RateLimitedBy, error) {
+	if l.blocked(requesterID) {
+		l.metrics.IncrementRequestsBlocked()
+		return false, common.RateLimitedByBlocklist, nil
+	}
+	for _, id := range l.allowlist {
+		if strings.Contains(requesterID, id) {
+			return true, "", nil
+		}
+	}
+
+	now := time.Now().UTC()
+	bucketParams := l.getWindow(ctx, requesterID, now)
+	bucketParams.LastRequestTime = now
+	bucketParams.WindowRate = rate
+
+	limit := float64(rate) * l.globalRateParams.WindowDuration.Seconds()
+	allowed := float64(sumWindow(bucketParams.WindowBytes)+uint64(blobSize)) <= limit
+	var limitedBy common.RateLimitedBy
+	if !allowed {
+		limitedBy = common.RateLimitedByBandwidth
+	}
+
+	if allowed || l.globalRateParams.CountFailed {
+		bucketParams.WindowBytes[l.granule(now)%int64(len(bucketParams.WindowBytes))] += uint64(blobSize)
+		if err := l.bucketStore.UpdateItem(ctx, requesterID, bucketParams); err != nil {
+			return allowed, limitedBy, err
+		}
+	}
+
+	return allowed, limitedBy, nil
+}
+
+// GetBucketUsage returns the fraction of rate*WindowDuration sent within the window, with the rate of the last request
+func (l *SlidingWindowRateLimiter) GetBucketUsage(ctx context.Context, requesterID common.RequesterID) (float64, error) {
+	bucketParams := l.getWindow(ctx, requesterID, time.Now().UTC())
+	limit := float64(bucketParams.WindowRate) * l.globalRateParams.WindowDuration.Seconds()
+	if limit <= 0 {
+		return 0, nil
+	}
+	usage := float64(sumWindow(bucketParams.WindowBytes)) / limit
+	if usage > 1 {
+		usage = 1
+	}
+	return usage, nil
+}
+
+// InspectBucket reports the window as a single bucket of WindowDuration, whose level is the time it would take to send
+// the bytes still allowed within the window at the rate of the last request
+func (l *SlidingWindowRateLimiter) InspectBucket(ctx context.Context, requesterID common.RequesterID) (*common.RateBucketStatus, error) {
+	usage, err := l.GetBucketUsage(ctx, requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &common.RateBucketStatus{
+		RateBucketParams: common.RateBucketParams{
+			BucketLevels: []time.Duration{time.Duration((1 - usage) * float64(l.globalRateParams.WindowDuration))},
+		},
+		GlobalRateParams: l.globalRateParams,
+	}
+	status.GlobalRateParams.BucketSizes = []time.Duration{l.globalRateParams.WindowDuration}
+	status.GlobalRateParams.Multipliers = []float32{1}
+	status.GlobalRateParams.RequestsPerSecond = 0
+	if bucketParams, err := l.bucketStore.GetItem(ctx, requesterID); err == nil {
+		status.LastRequestTime = bucketParams.LastRequestTime
+	}
+	return status, nil
+}
+
+// getWindow returns the window of the requester with the granules which left the window since its last request cleared
+func (l *SlidingWindowRateLimiter) getWindow(ctx context.Context, requesterID common.RequesterID, now time.Time) *common.RateBucketParams {
+	numGranules := l.numGranules()
+	bucketParams, err := l.bucketStore.GetItem(ctx, requesterID)
+	if err != nil || len(bucketParams.WindowBytes) != numGranules {
+		// windows stored with another granularity are reset
+		return &common.RateBucketParams{
+			WindowBytes: make([]uint64, numGranules),
+		}
+	}
+
+	current, last := l.granule(now), l.granule(bucketParams.LastRequestTime)
+	if current-last >= int64(numGranules) {
+		clear(bucketParams.WindowBytes)
+		return bucketParams
+	}
+	for g := last + 1; g <= current; g++ {
+		bucketParams.WindowBytes[g%int64(numGranules)] = 0
+	}
+	return bucketParams
+}
+
+func (l *SlidingWindowRateLimiter) granule(t time.Time) int64 {
+	return t.UnixNano() / int64(l.globalRateParams.WindowGranularity)
+}
+
+// numGranules is the number of granules covering the window
+func (l *SlidingWindowRateLimiter) numGranules() int {
+	granularity := l.globalRateParams.WindowGranularity
+	return int((l.globalRateParams.WindowDuration + granularity - 1) / granularity)
+}
+
+func sumWindow(window []uint64) uint64 {
+	var sum uint64
+	for _, bytes := range window {
+		sum += bytes
+	}
+	return sum
+}
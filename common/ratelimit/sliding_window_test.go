@@ -0,0 +1,109 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestSlidingWindowRatelimiter(countFailed bool) (common.RateLimiter, error) {
+	globalParams := common.GlobalRateParams{
+		CountFailed:       countFailed,
+		WindowDuration:    500 * time.Millisecond,
+		WindowGranularity: 50 * time.Millisecond,
+	}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](1000)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewRateLimiterFromConfig(ratelimit.Config{
+		GlobalRateParams: globalParams,
+		RateLimiterType:  ratelimit.SlidingWindowRateLimiterType,
+		Allowlist:        []string{"testRetriever2"},
+	}, bucketStore, nil, &mock.Logger{}), nil
+}
+
+func TestSlidingWindowRatelimit(t *testing.T) {
+	ratelimiter, err := makeTestSlidingWindowRatelimiter(false)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	// 100 bytes/s over a 500ms window allows 50 bytes
+	for i := 0; i < 5; i++ {
+		allow, _, err := ratelimiter.AllowRequest(ctx, "testRetriever", 10, 100)
+		assert.NoError(t, err)
+		assert.True(t, allow)
+	}
+	allow, limitedBy, err := ratelimiter.AllowRequest(ctx, "testRetriever", 10, 100)
+	assert.NoError(t, err)
+	assert.False(t, allow)
+	assert.Equal(t, common.RateLimitedByBandwidth, limitedBy)
+
+	usage, err := ratelimiter.GetBucketUsage(ctx, "testRetriever")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, usage)
+
+	// the whole burst leaves the window at once
+	time.Sleep(600 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		allow, _, err := ratelimiter.AllowRequest(ctx, "testRetriever", 10, 100)
+		assert.NoError(t, err)
+		assert.True(t, allow)
+	}
+
+	// allowlisted requesters are not limited
+	for i := 0; i < 10; i++ {
+		allow, _, err := ratelimiter.AllowRequest(ctx, "testRetriever2", 10, 100)
+		assert.NoError(t, err)
+		assert.True(t, allow)
+	}
+}
+
+func TestSlidingWindowRatelimitCountFailed(t *testing.T) {
+	ctx := context.Background()
+
+	ratelimiter, err := makeTestSlidingWindowRatelimiter(false)
+	assert.NoError(t, err)
+	allow, _, err := ratelimiter.AllowRequest(ctx, "testRetriever", 60, 100)
+	assert.NoError(t, err)
+	assert.False(t, allow)
+	allow, _, err = ratelimiter.AllowRequest(ctx, "testRetriever", 50, 100)
+	assert.NoError(t, err)
+	assert.True(t, allow)
+
+	ratelimiter, err = makeTestSlidingWindowRatelimiter(true)
+	assert.NoError(t, err)
+	allow, _, err = ratelimiter.AllowRequest(ctx, "testRetriever", 60, 100)
+	assert.NoError(t, err)
+	assert.False(t, allow)
+	allow, _, err = ratelimiter.AllowRequest(ctx, "testRetriever", 10, 100)
+	assert.NoError(t, err)
+	assert.False(t, allow)
+}
+
+func TestSlidingWindowInspectBucket(t *testing.T) {
+	ratelimiter, err := makeTestSlidingWindowRatelimiter(false)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	status, err := ratelimiter.InspectBucket(ctx, "testRetriever")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond}, status.GlobalRateParams.BucketSizes)
+	assert.Equal(t, []float64{100}, status.FillPercentages())
+	assert.True(t, status.LastRequestTime.IsZero())
+
+	_, _, err = ratelimiter.AllowRequest(ctx, "testRetriever", 20, 100)
+	assert.NoError(t, err)
+	status, err = ratelimiter.InspectBucket(ctx, "testRetriever")
+	assert.NoError(t, err)
+	assert.InDelta(t, 60, status.FillPercentages()[0], 0.01)
+	assert.False(t, status.LastRequestTime.IsZero())
+}
@@ -12,12 +12,14 @@ var (
 	KVStreamIDFlagName          = "storage.kv-stream-id"
 	FlowContractAddressFlagName = "storage.flow-contract"
 	UploadTaskSizeFlagName      = "storage.upload-task-size"
+	StorageNodeAddrsFlagName    = "storage.node-addrs"
 )
 
 type ClientConfig struct {
 	StorageNodeURLs     []string
 	FlowContractAddress string
 	KVNodeURL           string
+	StorageNodeAddrs    []string
 	KVStreamId          eth_common.Hash
 	UploadTaskSize      uint
 }
@@ -59,6 +61,13 @@ func ClientFlags(envPrefix string, flagPrefix string) []cli.Flag {
 			Value:    10,
 			EnvVar:   common.PrefixEnvVar(envPrefix, "UPLOAD_TASK_SIZE"),
 		},
+		cli.StringSliceFlag{
+			Name:     common.PrefixFlag(flagPrefix, StorageNodeAddrsFlagName),
+			Usage:    "kv node urls to read from, the streams are spread over them and fail over to each other",
+			Required: false,
+			Value:    nil,
+			EnvVar:   common.PrefixEnvVar(envPrefix, "STORAGE_NODE_ADDRS"),
+		},
 	}
 }
 
@@ -68,6 +77,7 @@ func ReadClientConfig(ctx *cli.Context, flagPrefix string) ClientConfig {
 		StorageNodeURLs:     ctx.GlobalStringSlice(common.PrefixFlag(flagPrefix, StorageNodeURLsFlagName)),
 		FlowContractAddress: ctx.GlobalString(common.PrefixFlag(flagPrefix, FlowContractAddressFlagName)),
 		KVNodeURL:           ctx.GlobalString(common.PrefixFlag(flagPrefix, KVNodeURLFlagName)),
+		StorageNodeAddrs:    ctx.GlobalStringSlice(common.PrefixFlag(flagPrefix, StorageNodeAddrsFlagName)),
 		KVStreamId:          streamId,
 		UploadTaskSize:      ctx.GlobalUint(common.PrefixFlag(flagPrefix, UploadTaskSizeFlagName)),
 	}
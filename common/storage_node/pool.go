@@ -0,0 +1,177 @@
+package storage_node
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-storage-client/kv"
+	"github.com/0glabs/0g-storage-client/node"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// virtualNodesPerNode is the number of points each storage node takes on the hash ring, to spread the streams evenly
+const virtualNodesPerNode = 64
+
+// KVClient is the part of the kv client used to read from a storage node
+type KVClient interface {
+	GetValue(streamId eth_common.Hash, key []byte, version ...uint64) (*node.Value, error)
+	NewIterator(streamId eth_common.Hash, version ...uint64) *kv.Iterator
+}
+
+var _ KVClient = (*kv.Client)(nil)
+
+// DialKVClient creates the kv client of the storage node at the given address
+func DialKVClient(addr string) (KVClient, error) {
+	client, err := node.NewClient(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to storage node %s: %w", addr, err)
+	}
+	return kv.NewClient(client, nil), nil
+}
+
+// PoolMetrics are the metrics of the StorageNodePool
+type PoolMetrics struct {
+	PoolSize      prometheus.Gauge
+	FailoverCount prometheus.Counter
+}
+
+func NewPoolMetrics(reg prometheus.Registerer, namespace string) *PoolMetrics {
+	return &PoolMetrics{
+		PoolSize: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "storage_node_pool_size",
+				Help:      "the number of storage nodes in the kv connection pool",
+			},
+		),
+		FailoverCount: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "storage_node_failover_total",
+				Help:      "the number of kv requests which failed over to the next storage node",
+			},
+		),
+	}
+}
+
+type ringPoint struct {
+	hash uint32
+	addr string
+}
+
+// StorageNodePool keeps kv connections to multiple storage nodes. The requests of a stream are routed to its node on a
+// consistent hash ring, and fail over to the next nodes on the ring when that node fails.
+type StorageNodePool struct {
+	ring    []ringPoint
+	clients map[string]KVClient
+	metrics *PoolMetrics
+	logger  common.Logger
+}
+
+var _ KVClient = (*StorageNodePool)(nil)
+
+// NewStorageNodePool connects to the storage nodes at the given addresses with dial, e.g. DialKVClient
+func NewStorageNodePool(addrs []string, dial func(addr string) (KVClient, error), metrics *PoolMetrics, logger common.Logger) (*StorageNodePool, error) {
+	if len(addrs) == 0 {
+		return nil, errors.New("no storage node addresses")
+	}
+
+	pool := &StorageNodePool{
+		ring:    make([]ringPoint, 0, len(addrs)*virtualNodesPerNode),
+		clients: make(map[string]KVClient, len(addrs)),
+		metrics: metrics,
+		logger:  logger,
+	}
+	for _, addr := range addrs {
+		if _, ok := pool.clients[addr]; ok {
+			continue
+		}
+		client, err := dial(addr)
+		if err != nil {
+			return nil, err
+		}
+		pool.clients[addr] = client
+		for i := 0; i < virtualNodesPerNode; i++ {
+			pool.ring = append(pool.ring, ringPoint{hash: crc32.ChecksumIEEE([]byte(addr + "#" + strconv.Itoa(i))), addr: addr})
+		}
+	}
+	sort.Slice(pool.ring, func(i, j int) bool {
+		if pool.ring[i].hash == pool.ring[j].hash {
+			return pool.ring[i].addr < pool.ring[j].addr
+		}
+		return pool.ring[i].hash < pool.ring[j].hash
+	})
+
+	if metrics != nil {
+		metrics.PoolSize.Set(float64(len(pool.clients)))
+	}
+	return pool, nil
+}
+
+// Nodes returns the addresses of the storage nodes in the order the requests of the stream are sent to them,
+// starting with its primary node
+func (p *StorageNodePool) Nodes(streamId eth_common.Hash) []string {
+	hash := crc32.ChecksumIEEE(streamId[:])
+	start := sort.Search(len(p.ring), func(i int) bool {
+		return p.ring[i].hash >= hash
+	})
+
+	nodes := make([]string, 0, len(p.clients))
+	seen := make(map[string]struct{}, len(p.clients))
+	for i := 0; i < len(p.ring) && len(nodes) < len(p.clients); i++ {
+		point := p.ring[(start+i)%len(p.ring)]
+		if _, ok := seen[point.addr]; ok {
+			continue
+		}
+		seen[point.addr] = struct{}{}
+		nodes = append(nodes, point.addr)
+	}
+	return nodes
+}
+
+// Do calls f with the client of the primary node of the stream, and with the clients of the next nodes on the ring
+// as long as it fails
+func (p *StorageNodePool) Do(streamId eth_common.Hash, f func(client KVClient) error) error {
+	var err error
+	for i, addr := range p.Nodes(streamId) {
+		if i > 0 {
+			if p.metrics != nil {
+				p.metrics.FailoverCount.Inc()
+			}
+			p.logger.Warn("[storage node pool] failing over to the next storage node", "addr", addr, "err", err)
+		}
+		if err = f(p.clients[addr]); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("all storage nodes failed: %w", err)
+}
+
+func (p *StorageNodePool) GetValue(streamId eth_common.Hash, key []byte, version ...uint64) (*node.Value, error) {
+	var val *node.Value
+	err := p.Do(streamId, func(client KVClient) (err error) {
+		val, err = client.GetValue(streamId, key, version...)
+		return err
+	})
+	return val, err
+}
+
+// NewIterator returns an iterator over the stream on its primary node, iterators don't fail over
+func (p *StorageNodePool) NewIterator(streamId eth_common.Hash, version ...uint64) *kv.Iterator {
+	return p.clients[p.Nodes(streamId)[0]].NewIterator(streamId, version...)
+}
+
+// NewKVClient returns a StorageNodePool over the configured storage nodes, or the client of the kv node when no
+// storage node addresses are configured
+func NewKVClient(cfg ClientConfig, metrics *PoolMetrics, logger common.Logger) (KVClient, error) {
+	if len(cfg.StorageNodeAddrs) == 0 {
+		return DialKVClient(cfg.KVNodeURL)
+	}
+	return NewStorageNodePool(cfg.StorageNodeAddrs, DialKVClient, metrics, logger)
+}
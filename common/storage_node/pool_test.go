@@ -0,0 +1,129 @@
+package storage_node_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-storage-client/kv"
+	"github.com/0glabs/0g-storage-client/node"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKVClient serves its address as the value of every key, unless it is down
+type fakeKVClient struct {
+	addr  string
+	down  bool
+	calls int
+}
+
+func (c *fakeKVClient) GetValue(streamId eth_common.Hash, key []byte, version ...uint64) (*node.Value, error) {
+	c.calls++
+	if c.down {
+		return nil, errors.New("storage node is down")
+	}
+	return &node.Value{Data: []byte(c.addr), Size: uint64(len(c.addr))}, nil
+}
+
+func (c *fakeKVClient) NewIterator(streamId eth_common.Hash, version ...uint64) *kv.Iterator {
+	return nil
+}
+
+func newTestPool(t *testing.T, addrs []string, metrics *storage_node.PoolMetrics) (*storage_node.StorageNodePool, map[string]*fakeKVClient) {
+	clients := make(map[string]*fakeKVClient)
+	pool, err := storage_node.NewStorageNodePool(addrs, func(addr string) (storage_node.KVClient, error) {
+		clients[addr] = &fakeKVClient{addr: addr}
+		return clients[addr], nil
+	}, metrics, &mock.Logger{})
+	assert.NoError(t, err)
+	return pool, clients
+}
+
+func makeStreamIds(n int) []eth_common.Hash {
+	streamIds := make([]eth_common.Hash, n)
+	for i := range streamIds {
+		streamIds[i] = crypto.Keccak256Hash([]byte(fmt.Sprintf("stream%d", i)))
+	}
+	return streamIds
+}
+
+func TestStorageNodePoolConsistentHashing(t *testing.T) {
+	addrs := []string{"http://node0:5678", "http://node1:5678", "http://node2:5678"}
+	pool, _ := newTestPool(t, addrs, nil)
+	reordered, _ := newTestPool(t, []string{addrs[2], addrs[0], addrs[1], addrs[0]}, nil)
+	grown, _ := newTestPool(t, append(addrs, "http://node3:5678"), nil)
+
+	streamIds := makeStreamIds(1000)
+	primaries := make(map[string]int)
+	moved := 0
+	for _, streamId := range streamIds {
+		nodes := pool.Nodes(streamId)
+		assert.ElementsMatch(t, addrs, nodes)
+		// the route of a stream doesn't depend on the order the nodes are configured in
+		assert.Equal(t, nodes, reordered.Nodes(streamId))
+		primaries[nodes[0]]++
+
+		// adding a node only moves streams to the new node
+		grownPrimary := grown.Nodes(streamId)[0]
+		if grownPrimary != nodes[0] {
+			assert.Equal(t, "http://node3:5678", grownPrimary)
+			moved++
+		}
+	}
+
+	// the streams are spread over all nodes
+	for _, addr := range addrs {
+		assert.Greater(t, primaries[addr], 150)
+	}
+	assert.Greater(t, moved, 100)
+	assert.Less(t, moved, 450)
+}
+
+func TestStorageNodePoolFailover(t *testing.T) {
+	addrs := []string{"http://node0:5678", "http://node1:5678", "http://node2:5678"}
+	metrics := storage_node.NewPoolMetrics(prometheus.NewRegistry(), "test")
+	pool, clients := newTestPool(t, addrs, metrics)
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.PoolSize))
+
+	streamId := makeStreamIds(1)[0]
+	nodes := pool.Nodes(streamId)
+	val, err := pool.GetValue(streamId, []byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, nodes[0], string(val.Data))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.FailoverCount))
+
+	// the requests to a failing primary go to the next node on the ring
+	clients[nodes[0]].down = true
+	val, err = pool.GetValue(streamId, []byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, nodes[1], string(val.Data))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.FailoverCount))
+	assert.Equal(t, 0, clients[nodes[2]].calls)
+
+	clients[nodes[1]].down = true
+	val, err = pool.GetValue(streamId, []byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, nodes[2], string(val.Data))
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.FailoverCount))
+
+	clients[nodes[2]].down = true
+	_, err = pool.GetValue(streamId, []byte("key"))
+	assert.Error(t, err)
+
+	// the primary serves the stream again once it recovers
+	clients[nodes[0]].down = false
+	val, err = pool.GetValue(streamId, []byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, nodes[0], string(val.Data))
+}
+
+func TestStorageNodePoolNoNodes(t *testing.T) {
+	_, err := storage_node.NewStorageNodePool(nil, storage_node.DialKVClient, nil, &mock.Logger{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/urfave/cli"
+)
+
+const (
+	EndpointFlagName    = "tracing.endpoint"
+	InsecureFlagName    = "tracing.insecure"
+	ServiceNameFlagName = "tracing.service-name"
+	SampleRateFlagName  = "tracing.sample-rate"
+)
+
+// TracingConfig is the configuration of the export of the traces
+type TracingConfig struct {
+	// Endpoint is the host:port of the OTLP gRPC collector the spans are exported to. If empty, tracing is disabled.
+	Endpoint string
+	// Insecure exports the spans without TLS
+	Insecure    bool
+	ServiceName string
+	// SampleRate is the fraction, in [0, 1], of the traces started by the service which are sampled
+	SampleRate float64
+}
+
+func CLIFlags(envPrefix string, flagPrefix string, serviceName string) []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   common.PrefixFlag(flagPrefix, EndpointFlagName),
+			Usage:  "host:port of the OTLP gRPC collector the traces are exported to. If empty, tracing is disabled",
+			Value:  "",
+			EnvVar: common.PrefixEnvVar(envPrefix, "TRACING_ENDPOINT"),
+		},
+		cli.BoolFlag{
+			Name:   common.PrefixFlag(flagPrefix, InsecureFlagName),
+			Usage:  "export the traces to the collector without TLS",
+			EnvVar: common.PrefixEnvVar(envPrefix, "TRACING_INSECURE"),
+		},
+		cli.StringFlag{
+			Name:   common.PrefixFlag(flagPrefix, ServiceNameFlagName),
+			Usage:  "service name the traces are exported under",
+			Value:  serviceName,
+			EnvVar: common.PrefixEnvVar(envPrefix, "TRACING_SERVICE_NAME"),
+		},
+		cli.Float64Flag{
+			Name:   common.PrefixFlag(flagPrefix, SampleRateFlagName),
+			Usage:  "fraction of the traces which are sampled, between 0 and 1",
+			Value:  1,
+			EnvVar: common.PrefixEnvVar(envPrefix, "TRACING_SAMPLE_RATE"),
+		},
+	}
+}
+
+func ReadCLIConfig(ctx *cli.Context, flagPrefix string) TracingConfig {
+	return TracingConfig{
+		Endpoint:    ctx.GlobalString(common.PrefixFlag(flagPrefix, EndpointFlagName)),
+		Insecure:    ctx.GlobalBool(common.PrefixFlag(flagPrefix, InsecureFlagName)),
+		ServiceName: ctx.GlobalString(common.PrefixFlag(flagPrefix, ServiceNameFlagName)),
+		SampleRate:  ctx.GlobalFloat64(common.PrefixFlag(flagPrefix, SampleRateFlagName)),
+	}
+}
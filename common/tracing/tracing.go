@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// instrumentationName is the name of the tracer of the spans of the services
+const instrumentationName = "github.com/0glabs/0g-data-avail"
+
+// Setup installs the global tracer provider exporting the spans to the OTLP collector of the config. If no collector
+// is configured, the global tracer provider is left as is, i.e. the no-op one, so the spans cost nothing.
+// The returned function flushes the pending spans and stops the export.
+func Setup(ctx context.Context, config TracingConfig, logger common.Logger) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("tracing sample rate %v must be between 0 and 1", config.SampleRate)
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the trace exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// the traces started by a caller are sampled as the caller decided
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRate))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	logger.Info("Exporting traces", "endpoint", config.Endpoint, "serviceName", config.ServiceName, "sampleRate", config.SampleRate)
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span as a child of the span of the context, if any. The tracer is looked up from the global
+// tracer provider on each call, so the spans started before Setup are no-ops.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the error, if any, on the span and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// grpcMetadataCarrier reads the trace context propagated in the metadata of a grpc request
+type grpcMetadataCarrier metadata.MD
+
+func (c grpcMetadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c grpcMetadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c grpcMetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ContextFromIncomingGRPC returns the context with the trace context propagated by the caller of the grpc request, so
+// the spans of the request are part of the trace of the caller
+func ContextFromIncomingGRPC(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, grpcMetadataCarrier(md))
+}
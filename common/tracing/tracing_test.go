@@ -0,0 +1,76 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/logging"
+	"github.com/0glabs/0g-data-avail/common/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	logger, err := logging.GetLogger(logging.DefaultCLIConfig())
+	require.NoError(t, err)
+
+	shutdown, err := tracing.Setup(context.Background(), tracing.TracingConfig{SampleRate: 1}, logger)
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	// the spans of the no-op provider aren't recorded
+	_, span := tracing.StartSpan(context.Background(), "noop")
+	assert.False(t, span.IsRecording())
+	tracing.EndSpan(span, nil)
+
+	_, err = tracing.Setup(context.Background(), tracing.TracingConfig{Endpoint: "localhost:4317", SampleRate: 2}, logger)
+	assert.Error(t, err)
+}
+
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx, parent := tracing.StartSpan(context.Background(), "parent", attribute.Int("blob.size", 10))
+	_, child := tracing.StartSpan(ctx, "child")
+	tracing.EndSpan(child, errors.New("upload failed"))
+	tracing.EndSpan(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "upload failed", spans[0].Status().Description)
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, "parent", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Contains(t, spans[1].Attributes(), attribute.Int("blob.size", 10))
+}
+
+func TestContextFromIncomingGRPC(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	md := metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := tracing.ContextFromIncomingGRPC(metadata.NewIncomingContext(context.Background(), md))
+
+	provider := sdktrace.NewTracerProvider()
+	_, span := provider.Tracer("test").Start(ctx, "request")
+	defer span.End()
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+
+	// the requests without metadata start a new trace
+	assert.Equal(t, context.Background(), tracing.ContextFromIncomingGRPC(context.Background()))
+}
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"errors"
+	"fmt"
+
 	eth_common "github.com/ethereum/go-ethereum/common"
 
 	"github.com/0glabs/0g-data-avail/common"
@@ -26,6 +29,37 @@ type SecurityParam struct {
 	QuorumRate common.RateParam `json:"quorum_rate"`
 }
 
+// ValidateSecurityParams checks the security params of a blob can be met: there must be at least one, each quorum at
+// most once, with thresholds in [1, 100] and an adversary threshold lower than the quorum threshold.
+// The returned error joins all the violations.
+func ValidateSecurityParams(params []*SecurityParam) error {
+	if len(params) == 0 {
+		return errors.New("at least one security param is required")
+	}
+	var errs []error
+	seen := make(map[QuorumID]bool, len(params))
+	for i, param := range params {
+		if param == nil {
+			errs = append(errs, fmt.Errorf("security param %d is missing", i))
+			continue
+		}
+		if seen[param.QuorumID] {
+			errs = append(errs, fmt.Errorf("quorum %d: duplicate security param", param.QuorumID))
+		}
+		seen[param.QuorumID] = true
+		if param.AdversaryThreshold < 1 || param.AdversaryThreshold > 100 {
+			errs = append(errs, fmt.Errorf("quorum %d: adversary threshold %d must be in [1, 100]", param.QuorumID, param.AdversaryThreshold))
+		}
+		if param.QuorumThreshold < 1 || param.QuorumThreshold > 100 {
+			errs = append(errs, fmt.Errorf("quorum %d: quorum threshold %d must be in [1, 100]", param.QuorumID, param.QuorumThreshold))
+		}
+		if param.AdversaryThreshold >= param.QuorumThreshold {
+			errs = append(errs, fmt.Errorf("quorum %d: adversary threshold %d must be lower than the quorum threshold %d", param.QuorumID, param.AdversaryThreshold, param.QuorumThreshold))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // QuorumResult contains the quorum ID and the amount signed for the quorum
 type QuorumResult struct {
 	QuorumID QuorumID
@@ -47,6 +81,23 @@ type BlobRequestHeader struct {
 	AccountID AccountID `json:"account_id"`
 	// TargetRowNum is the number of rows that encoded blob split into
 	TargetRowNum uint32 `json:"target_row_num"`
+	// Priority orders the blobs waiting to be batched, from 0 (low) to 255 (high)
+	Priority uint8 `json:"priority"`
+}
+
+// Clone returns a deep copy of the header, so the security params can be modified without affecting other holders of the header
+func (h *BlobRequestHeader) Clone() *BlobRequestHeader {
+	clone := *h
+	if h.SecurityParams != nil {
+		clone.SecurityParams = make([]*SecurityParam, len(h.SecurityParams))
+		for i, param := range h.SecurityParams {
+			if param != nil {
+				paramCopy := *param
+				clone.SecurityParams[i] = &paramCopy
+			}
+		}
+	}
+	return &clone
 }
 
 // BlobQuorumInfo contains the quorum IDs and parameters for a blob specific to a given quorum
@@ -61,6 +112,8 @@ type BlobHeader struct {
 	// CommitmentRoot the root of merkle tree of kzg commitments
 	CommitmentRoot []byte `json:"commitment_root"`
 	Length         uint   `json:"length"`
+	// QuorumInfos are the parameters of the quorums of the blob, they are only committed to by GetBlobHeaderHashV2
+	QuorumInfos []*BlobQuorumInfo `json:"quorum_infos"`
 }
 
 type Coeff = [32]byte
@@ -0,0 +1,110 @@
+package core_test
+
+import (
+	"sync"
+	"testing"
+	"testing/quick"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobRequestHeaderClone(t *testing.T) {
+	header := &core.BlobRequestHeader{
+		SecurityParams: []*core.SecurityParam{
+			{QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 80},
+			{QuorumID: 1, AdversaryThreshold: 33, QuorumThreshold: 67},
+		},
+		AccountID:    "account",
+		TargetRowNum: 8,
+	}
+
+	// a copy of the struct still shares the security params with the original
+	shallow := *header
+	assert.Same(t, header.SecurityParams[0], shallow.SecurityParams[0])
+
+	clone := header.Clone()
+	assert.Equal(t, header, clone)
+	for i := range header.SecurityParams {
+		assert.NotSame(t, header.SecurityParams[i], clone.SecurityParams[i])
+	}
+
+	// goroutines modifying their own clone don't race with each other nor modify the original, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clone := header.Clone()
+			clone.SecurityParams[0].AdversaryThreshold = uint8(i)
+			clone.SecurityParams = append(clone.SecurityParams, &core.SecurityParam{QuorumID: core.QuorumID(i + 2)})
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, header.SecurityParams, 2)
+	assert.Equal(t, uint8(50), header.SecurityParams[0].AdversaryThreshold)
+
+	empty := (&core.BlobRequestHeader{}).Clone()
+	assert.Nil(t, empty.SecurityParams)
+}
+
+func TestValidateSecurityParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []*core.SecurityParam
+		wantErr string
+	}{
+		{"lowest bounds", []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 1, QuorumThreshold: 2}}, ""},
+		{"highest bounds", []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 99, QuorumThreshold: 100}}, ""},
+		{"several quorums", []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 80}, {QuorumID: 1, AdversaryThreshold: 33, QuorumThreshold: 67}}, ""},
+		{"empty", nil, "at least one security param"},
+		{"nil param", []*core.SecurityParam{nil}, "security param 0 is missing"},
+		{"zero adversary threshold", []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 0, QuorumThreshold: 50}}, "adversary threshold 0 must be in [1, 100]"},
+		{"quorum threshold above 100", []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 101}}, "quorum threshold 101 must be in [1, 100]"},
+		{"equal thresholds", []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 50}}, "must be lower than the quorum threshold 50"},
+		{"inverted thresholds", []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 100, QuorumThreshold: 50}}, "adversary threshold 100 must be lower"},
+		{"duplicate quorum", []*core.SecurityParam{{QuorumID: 3, AdversaryThreshold: 50, QuorumThreshold: 80}, {QuorumID: 3, AdversaryThreshold: 33, QuorumThreshold: 67}}, "quorum 3: duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := core.ValidateSecurityParams(tt.params)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	// all the violations are reported
+	err := core.ValidateSecurityParams([]*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 0, QuorumThreshold: 0}, {QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 80}})
+	assert.ErrorContains(t, err, "adversary threshold 0")
+	assert.ErrorContains(t, err, "quorum threshold 0")
+	assert.ErrorContains(t, err, "duplicate")
+}
+
+func TestValidateSecurityParamsProperties(t *testing.T) {
+	// a single param is valid iff 1 <= adversary < quorum <= 100
+	single := func(quorumID, adversary, quorum uint8) bool {
+		err := core.ValidateSecurityParams([]*core.SecurityParam{{QuorumID: quorumID, AdversaryThreshold: adversary, QuorumThreshold: quorum}})
+		valid := adversary >= 1 && adversary < quorum && quorum <= 100
+		return (err == nil) == valid
+	}
+	assert.NoError(t, quick.Check(single, nil))
+	// the boundaries are checked exhaustively, as random inputs rarely hit them
+	for adversary := 0; adversary <= 255; adversary++ {
+		for quorum := 0; quorum <= 255; quorum++ {
+			assert.True(t, single(0, uint8(adversary), uint8(quorum)), "adversary %d, quorum %d", adversary, quorum)
+		}
+	}
+
+	// a quorum can't be given twice, even with valid thresholds
+	duplicate := func(quorumID uint8) bool {
+		err := core.ValidateSecurityParams([]*core.SecurityParam{
+			{QuorumID: quorumID, AdversaryThreshold: 50, QuorumThreshold: 80},
+			{QuorumID: quorumID, AdversaryThreshold: 33, QuorumThreshold: 67},
+		})
+		return err != nil
+	}
+	assert.NoError(t, quick.Check(duplicate, nil))
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -37,9 +38,14 @@ func GetBlobLength(blobSize uint) uint {
 	return (blobSize + ScalarSize - 1) / ScalarSize
 }
 
+// GetEncodedLength returns the number of symbols of the encoded matrix of a blob with the given length in symbols
+func GetEncodedLength(blobLength uint) uint {
+	return uint(NextPowerOf2(uint64(blobLength * 2)))
+}
+
 // SplitToMatrix calculate row and column length for encoded blob, try to split it into rows x cols matrix
 func SplitToMatrix(blobLength uint, targetRowNum uint) (uint, uint) {
-	expectedLength := uint(NextPowerOf2(uint64(blobLength * 2)))
+	expectedLength := GetEncodedLength(blobLength)
 	var rows, cols uint
 	if targetRowNum == 0 {
 		// split into maximum rows
@@ -59,6 +65,24 @@ func SplitToMatrix(blobLength uint, targetRowNum uint) (uint, uint) {
 	return rows, cols
 }
 
+// ComputeChunkLength returns the length in symbols of the chunks, i.e. the rows of the encoded matrix, a blob of the
+// given size in bytes is split into by SplitToMatrix. The blob is split into as many chunks as possible if
+// targetNumChunks is 0. An error is returned if the blob is empty or too large, or if it is split into fewer chunks
+// than the number of operators they are distributed to.
+func ComputeChunkLength(blobSizeBytes int, numOperators int, targetNumChunks uint) (uint, error) {
+	if blobSizeBytes <= 0 || blobSizeBytes > MaxBlobSize {
+		return 0, fmt.Errorf("invalid blob size %d, it must be in range [1, %d]", blobSizeBytes, MaxBlobSize)
+	}
+	if numOperators <= 0 {
+		return 0, fmt.Errorf("invalid number of operators %d, it must be positive", numOperators)
+	}
+	rows, cols := SplitToMatrix(GetBlobLength(uint(blobSizeBytes)), targetNumChunks)
+	if rows < uint(numOperators) {
+		return 0, fmt.Errorf("blob of %d bytes is split into %d chunks, fewer than the %d operators", blobSizeBytes, rows, numOperators)
+	}
+	return cols, nil
+}
+
 // GetBlobSize converts from blob length in symbols to blob size in bytes. This is not an exact conversion.
 func GetBlobSize(blobLength uint) uint {
 	return blobLength * ScalarSize
@@ -0,0 +1,55 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeChunkLength(t *testing.T) {
+	for _, tc := range []struct {
+		blobSize        int
+		numOperators    int
+		targetNumChunks uint
+		chunkLength     uint
+	}{
+		{1, 1, 0, 1},
+		{31 * 1024, 1, 0, 1},
+		{31*1024 + 1, 1, 0, 2},
+		{31 * 4096, 1, 0, 4},
+		{31 * 1024, 1, 8, 256},
+		// the target is rounded up to a power of 2
+		{31 * 1024, 8, 5, 256},
+		// the blob is split into the maximum number of chunks if the chunks would be too long
+		{31 * 2048, 1, 1, 2},
+		{core.MaxBlobSize, 2048, 0, 2048},
+	} {
+		chunkLength, err := core.ComputeChunkLength(tc.blobSize, tc.numOperators, tc.targetNumChunks)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.chunkLength, chunkLength, tc)
+
+		// the chunks cover the matrix the encoder is asked for
+		blobLength := core.GetBlobLength(uint(tc.blobSize))
+		rows, cols := core.SplitToMatrix(blobLength, tc.targetNumChunks)
+		assert.Equal(t, cols, chunkLength)
+		assert.Equal(t, core.GetEncodedLength(blobLength), rows*chunkLength)
+	}
+
+	for _, tc := range []struct {
+		blobSize        int
+		numOperators    int
+		targetNumChunks uint
+	}{
+		{0, 1, 0},
+		{-1, 1, 0},
+		{core.MaxBlobSize + 1, 1, 0},
+		{100, 0, 0},
+		// a 1 byte blob is split into 2 chunks
+		{1, 3, 0},
+		{31 * 1024, 16, 8},
+	} {
+		_, err := core.ComputeChunkLength(tc.blobSize, tc.numOperators, tc.targetNumChunks)
+		assert.Error(t, err, tc)
+	}
+}
@@ -3,10 +3,12 @@ package core
 import (
 	"bytes"
 	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	eth_common "github.com/ethereum/go-ethereum/common"
 	"github.com/wealdtech/go-merkletree"
 	"github.com/wealdtech/go-merkletree/keccak256"
 	"golang.org/x/crypto/sha3"
@@ -16,22 +18,152 @@ var ErrInvalidCommitment = errors.New("invalid commitment")
 
 // SetBatchRoot sets the BatchRoot field of the BatchHeader to the Merkle root of the blob headers in the batch (i.e. the root of the Merkle tree whose leaves are the blob headers)
 func (h *BatchHeader) SetBatchRoot(blobHeaders []*BlobHeader) (*merkletree.MerkleTree, error) {
+	tree, err := blobHeaderTree(blobHeaders, BlobHeader.GetBlobHeaderHash)
+	if err != nil {
+		return nil, err
+	}
+
+	copy(h.BatchRoot[:], tree.Root())
+	return tree, nil
+}
+
+// SetBatchRootWithProof sets the BatchRoot field like SetBatchRoot and additionally returns the Merkle proof path
+// of every blob header in the batch, keyed by blob index, so the tree doesn't have to be walked again by the caller
+func (h *BatchHeader) SetBatchRootWithProof(blobHeaders []*BlobHeader) (*merkletree.MerkleTree, map[int][][]byte, error) {
+	return h.setBatchRootWithProof(blobHeaders, BlobHeader.GetBlobHeaderHash)
+}
+
+// SetBatchRootWithProofV2 is SetBatchRootWithProof with the leaves hashed by GetBlobHeaderHashV2, so the batch root
+// commits to the quorum parameters of the blobs
+func (h *BatchHeader) SetBatchRootWithProofV2(blobHeaders []*BlobHeader) (*merkletree.MerkleTree, map[int][][]byte, error) {
+	return h.setBatchRootWithProof(blobHeaders, BlobHeader.GetBlobHeaderHashV2)
+}
+
+func (h *BatchHeader) setBatchRootWithProof(blobHeaders []*BlobHeader, hash func(BlobHeader) ([32]byte, error)) (*merkletree.MerkleTree, map[int][][]byte, error) {
+	tree, err := blobHeaderTree(blobHeaders, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	copy(h.BatchRoot[:], tree.Root())
+	return tree, blobInclusionProofs(tree, len(blobHeaders)), nil
+}
+
+// blobHeaderTree builds the Merkle tree of the blob headers, with the leaves hashed with the given function
+func blobHeaderTree(blobHeaders []*BlobHeader, hash func(BlobHeader) ([32]byte, error)) (*merkletree.MerkleTree, error) {
+	leafs, err := blobHeaderLeafs(blobHeaders, hash)
+	if err != nil {
+		return nil, err
+	}
+	return merkletree.NewTree(merkletree.WithData(leafs), merkletree.WithHashType(keccak256.New()))
+}
+
+// blobInclusionProofs returns the proof path of every one of the numLeafs leaves of the tree, keyed by leaf index.
+// The proofs are built by index rather than by merkletree.GenerateProof, which looks the leaf up by value and so
+// returns the proof of the first of identical leaves, e.g. of the same blob dispersed twice in a batch.
+func blobInclusionProofs(tree *merkletree.MerkleTree, numLeafs int) map[int][][]byte {
+	// the nodes are laid out with the root at 1 and the children of node i at 2i and 2i+1, with the leaves padded
+	// with zero hashes up to a power of 2. The pollard of the full height is all the nodes from the root on.
+	width, height := 1, 0
+	for width < numLeafs {
+		width *= 2
+		height++
+	}
+	nodes := append([][]byte{nil}, tree.Pollard(height)...)
+
+	proofs := make(map[int][][]byte, numLeafs)
+	for i := 0; i < numLeafs; i++ {
+		proof := make([][]byte, 0, height)
+		for node := width + i; node > 1; node /= 2 {
+			proof = append(proof, nodes[node^1])
+		}
+		proofs[i] = proof
+	}
+	return proofs
+}
+
+// blobHeaderLeafs returns the leaves of the Merkle tree of the blob headers, hashed with the given function
+func blobHeaderLeafs(blobHeaders []*BlobHeader, hash func(BlobHeader) ([32]byte, error)) ([][]byte, error) {
 	leafs := make([][]byte, len(blobHeaders))
 	for i, header := range blobHeaders {
-		leaf, err := header.GetBlobHeaderHash()
+		leaf, err := hash(*header)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compute blob header hash: %w", err)
 		}
 		leafs[i] = leaf[:]
 	}
+	return leafs, nil
+}
 
-	tree, err := merkletree.NewTree(merkletree.WithData(leafs), merkletree.WithHashType(keccak256.New()))
+// ValidateBatchRoot checks that the BatchRoot of the header is the Merkle root of the given blob headers, in the order
+// of the blobs in the batch. It returns false without an error if the root doesn't match.
+func (h *BatchHeader) ValidateBatchRoot(blobHeaders []*BlobHeader) (bool, error) {
+	return h.validateBatchRoot(blobHeaders, BlobHeader.GetBlobHeaderHash)
+}
+
+// ValidateBatchRootV2 is ValidateBatchRoot for a batch root built by SetBatchRootWithProofV2, of the blob headers with
+// their quorum parameters
+func (h *BatchHeader) ValidateBatchRootV2(blobHeaders []*BlobHeader) (bool, error) {
+	return h.validateBatchRoot(blobHeaders, BlobHeader.GetBlobHeaderHashV2)
+}
+
+func (h *BatchHeader) validateBatchRoot(blobHeaders []*BlobHeader, hash func(BlobHeader) ([32]byte, error)) (bool, error) {
+	if len(blobHeaders) == 0 {
+		return false, errors.New("no blob headers to compute the batch root from")
+	}
+
+	tree, err := blobHeaderTree(blobHeaders, hash)
 	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("failed to compute batch root: %w", err)
 	}
+	return bytes.Equal(tree.Root(), h.BatchRoot[:]), nil
+}
 
-	copy(h.BatchRoot[:], tree.Root())
-	return tree, nil
+// VerifyBlobInclusionProof checks that the blob header is the leaf at blobIndex of the batch Merkle tree with the given
+// root, as built by SetBatchRoot, using the proof path returned by SetBatchRootWithProof: the hashes of the siblings of
+// the blob on the path from its leaf to the root. It returns false without an error if the proof is well-formed but
+// doesn't lead to the root, and an error if the blob header or the proof is malformed.
+func VerifyBlobInclusionProof(batchRoot [32]byte, blobHeader *BlobHeader, proof [][]byte, blobIndex uint) (bool, error) {
+	return verifyBlobInclusionProof(batchRoot, blobHeader, proof, blobIndex, BlobHeader.GetBlobHeaderHash)
+}
+
+// VerifyBlobInclusionProofV2 is VerifyBlobInclusionProof for a batch built by SetBatchRootWithProofV2: the leaf of the
+// blob is the hash of the blob header with its quorum parameters, so the QuorumInfos of the blob header must be set.
+func VerifyBlobInclusionProofV2(batchRoot [32]byte, blobHeader *BlobHeader, proof [][]byte, blobIndex uint) (bool, error) {
+	return verifyBlobInclusionProof(batchRoot, blobHeader, proof, blobIndex, BlobHeader.GetBlobHeaderHashV2)
+}
+
+func verifyBlobInclusionProof(batchRoot [32]byte, blobHeader *BlobHeader, proof [][]byte, blobIndex uint, hash func(BlobHeader) ([32]byte, error)) (bool, error) {
+	if blobHeader == nil {
+		return false, errors.New("blob header is nil")
+	}
+	// the depth of the tree is the length of the proof, which has to be short enough for the leaf index to fit
+	if len(proof) >= 64 || uint64(blobIndex)>>uint(len(proof)) != 0 {
+		return false, fmt.Errorf("blob index %d out of range of a proof of %d hashes", blobIndex, len(proof))
+	}
+	for i, hash := range proof {
+		if len(hash) != 32 {
+			return false, fmt.Errorf("hash %d of the proof is %d bytes long, expected 32", i, len(hash))
+		}
+	}
+	leaf, err := hash(*blobHeader)
+	if err != nil {
+		return false, fmt.Errorf("failed to compute blob header hash: %w", err)
+	}
+
+	// the leaves of the tree are the hashes of the blob header hashes
+	hashType := keccak256.New()
+	node := hashType.Hash(leaf[:])
+	index := uint64(blobIndex)
+	for _, sibling := range proof {
+		if index%2 == 0 {
+			node = hashType.Hash(node, sibling)
+		} else {
+			node = hashType.Hash(sibling, node)
+		}
+		index >>= 1
+	}
+	return bytes.Equal(node, batchRoot[:]), nil
 }
 
 func (h *BatchHeader) Encode() ([]byte, error) {
@@ -105,6 +237,29 @@ func (h *BlobHeader) SetCommitmentRoot(commitments []Commitment) error {
 	return nil
 }
 
+// GetChunkMerkleRoot returns the Merkle root of the encoded rows of the matrix, each leaf is the keccak256 hash of
+// the row coefficients followed by the row commitment
+func (m *ExtendedMatrix) GetChunkMerkleRoot() ([32]byte, error) {
+	if len(m.Rows) != len(m.Commitments) {
+		return [32]byte{}, fmt.Errorf("number of rows %v mismatch with number of commitments %v", len(m.Rows), len(m.Commitments))
+	}
+	leafs := make([][]byte, len(m.Rows))
+	for i := range m.Rows {
+		hasher := sha3.NewLegacyKeccak256()
+		hasher.Write(m.GetRowInBytes(i))
+		hasher.Write(m.Commitments[i][:])
+		leafs[i] = hasher.Sum(nil)
+	}
+
+	var root [32]byte
+	tree, err := merkletree.NewTree(merkletree.WithData(leafs), merkletree.WithHashType(keccak256.New()))
+	if err != nil {
+		return root, err
+	}
+	copy(root[:], tree.Root())
+	return root, nil
+}
+
 func GetCommitmentHash(commitment Commitment) [32]byte {
 	var commitmentHash [32]byte
 	hasher := sha3.NewLegacyKeccak256()
@@ -129,6 +284,93 @@ func (h BlobHeader) GetBlobHeaderHash() ([32]byte, error) {
 	return headerHash, nil
 }
 
+// GetBlobHeaderHashV2 returns the hash of the BlobHeader including the parameters of its quorums, so that the same
+// commitment can't be reused for different quorums. It is the keccak256 hash of the ABI encoding of
+// {bytes32 commitmentRoot, QuorumBlobParams[] quorumBlobParams}.
+func (h BlobHeader) GetBlobHeaderHashV2() ([32]byte, error) {
+	headerByte, err := h.EncodeV2()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	var headerHash [32]byte
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(headerByte)
+	copy(headerHash[:], hasher.Sum(nil)[:32])
+
+	return headerHash, nil
+}
+
+// EncodeV2 returns the ABI encoding of the commitment root and the quorum parameters of the BlobHeader
+func (h *BlobHeader) EncodeV2() ([]byte, error) {
+	if len(h.CommitmentRoot) != 32 {
+		return nil, ErrInvalidCommitment
+	}
+
+	blobHeaderType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{
+			Name: "commitmentRoot",
+			Type: "bytes32",
+		},
+		{
+			Name: "quorumBlobParams",
+			Type: "tuple[]",
+			Components: []abi.ArgumentMarshaling{
+				{
+					Name: "quorumNumber",
+					Type: "uint8",
+				},
+				{
+					Name: "adversaryThresholdPercentage",
+					Type: "uint8",
+				},
+				{
+					Name: "quorumThresholdPercentage",
+					Type: "uint8",
+				},
+				{
+					Name: "chunkLength",
+					Type: "uint32",
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := abi.Arguments{
+		{
+			Type: blobHeaderType,
+		},
+	}
+
+	type quorumBlobParams struct {
+		QuorumNumber                 uint8
+		AdversaryThresholdPercentage uint8
+		QuorumThresholdPercentage    uint8
+		ChunkLength                  uint32
+	}
+
+	s := struct {
+		CommitmentRoot   [32]byte
+		QuorumBlobParams []quorumBlobParams
+	}{
+		QuorumBlobParams: make([]quorumBlobParams, len(h.QuorumInfos)),
+	}
+	copy(s.CommitmentRoot[:], h.CommitmentRoot)
+	for i, info := range h.QuorumInfos {
+		s.QuorumBlobParams[i] = quorumBlobParams{
+			QuorumNumber:                 info.QuorumID,
+			AdversaryThresholdPercentage: info.AdversaryThreshold,
+			QuorumThresholdPercentage:    info.QuorumThreshold,
+			ChunkLength:                  uint32(info.ChunkLength),
+		}
+	}
+
+	return arguments.Pack(s)
+}
+
 func (h *BlobHeader) GetQuorumBlobParamsHash() ([32]byte, error) {
 	quorumBlobParamsType, err := abi.NewType("tuple[]", "", []abi.ArgumentMarshaling{
 		{
@@ -207,6 +449,96 @@ func (h *BlobHeader) Deserialize(data []byte) (*BlobHeader, error) {
 	return h, err
 }
 
+// batchHeaderJSON is the JSON representation of a BatchHeader, the one of the batch info of the KV stream:
+//
+//	{
+//	  "batch_root": [32 numbers, the bytes of the batch root],
+//	  "data_root": "0x-prefixed hex of the data root"
+//	}
+type batchHeaderJSON struct {
+	BatchRoot [32]byte        `json:"batch_root"`
+	DataRoot  eth_common.Hash `json:"data_root"`
+}
+
+// MarshalJSON encodes the batch header in the schema of batchHeaderJSON. The fields are always in the same order, so the
+// encoding is deterministic. The hash of the header is still the one of its ABI encoding, see GetBatchHeaderHash.
+func (h BatchHeader) MarshalJSON() ([]byte, error) {
+	return json.Marshal(batchHeaderJSON{BatchRoot: h.BatchRoot, DataRoot: h.DataRoot})
+}
+
+// UnmarshalJSON decodes a batch header encoded by MarshalJSON
+func (h *BatchHeader) UnmarshalJSON(data []byte) error {
+	var header batchHeaderJSON
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	*h = BatchHeader{BatchRoot: header.BatchRoot, DataRoot: header.DataRoot}
+	return nil
+}
+
+// blobHeaderJSON is the JSON representation of a BlobHeader, the one of the blob info of the KV stream:
+//
+//	{
+//	  "commitment_root": "base64 of the commitment root",
+//	  "length": number,
+//	  "quorum_infos": [
+//	    {"quorum_id": number, "adversary_threshold": number, "quorum_threshold": number, "quorum_rate": number, "ChunkLength": number}
+//	  ]
+//	}
+type blobHeaderJSON struct {
+	CommitmentRoot []byte            `json:"commitment_root"`
+	Length         uint              `json:"length"`
+	QuorumInfos    []*BlobQuorumInfo `json:"quorum_infos"`
+}
+
+// MarshalJSON encodes the blob header in the schema of blobHeaderJSON. The fields are always in the same order, so the
+// encoding is deterministic. The hash of the header is still the one of its ABI encoding, see GetBlobHeaderHash.
+func (h BlobHeader) MarshalJSON() ([]byte, error) {
+	return json.Marshal(blobHeaderJSON{CommitmentRoot: h.CommitmentRoot, Length: h.Length, QuorumInfos: h.QuorumInfos})
+}
+
+// UnmarshalJSON decodes a blob header encoded by MarshalJSON
+func (h *BlobHeader) UnmarshalJSON(data []byte) error {
+	var header blobHeaderJSON
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	*h = BlobHeader{CommitmentRoot: header.CommitmentRoot, Length: header.Length, QuorumInfos: header.QuorumInfos}
+	return nil
+}
+
+// SerializationFormat is the format objects are serialized in by EncodeWithFormat
+type SerializationFormat string
+
+const (
+	GobSerialization  SerializationFormat = "gob"
+	JSONSerialization SerializationFormat = "json"
+)
+
+// ParseSerializationFormat returns the serialization format of its name, gob if the name is empty
+func ParseSerializationFormat(name string) (SerializationFormat, error) {
+	switch format := SerializationFormat(name); format {
+	case "":
+		return GobSerialization, nil
+	case GobSerialization, JSONSerialization:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown serialization format %q, must be gob or json", name)
+	}
+}
+
+// EncodeWithFormat encodes the object in the given format, Decode reads both formats
+func EncodeWithFormat(obj any, format SerializationFormat) ([]byte, error) {
+	switch format {
+	case JSONSerialization:
+		return json.Marshal(obj)
+	case GobSerialization, "":
+		return Encode(obj)
+	default:
+		return nil, fmt.Errorf("unknown serialization format %q", format)
+	}
+}
+
 func Encode(obj any) ([]byte, error) {
 	var buf bytes.Buffer
 	enc := gob.NewEncoder(&buf)
@@ -217,7 +549,12 @@ func Encode(obj any) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Decode decodes data encoded by Encode or EncodeWithFormat. The data is decoded as JSON if it's a JSON object, which a
+// gob stream can't be as it holds binary type definitions.
 func Decode(data []byte, obj any) error {
+	if len(data) > 0 && data[0] == '{' && json.Valid(data) {
+		return json.Unmarshal(data, obj)
+	}
 	buf := bytes.NewBuffer(data)
 	dec := gob.NewDecoder(buf)
 	err := dec.Decode(obj)
@@ -0,0 +1,314 @@
+package core_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/core"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-merkletree"
+	"github.com/wealdtech/go-merkletree/keccak256"
+)
+
+func makeBlobHeaders(n int) []*core.BlobHeader {
+	blobHeaders := make([]*core.BlobHeader, n)
+	for i := range blobHeaders {
+		blobHeaders[i] = &core.BlobHeader{
+			CommitmentRoot: []byte{byte(i), byte(i >> 8), 0xda},
+			Length:         uint(i + 1),
+		}
+	}
+	return blobHeaders
+}
+
+func TestSetBatchRootWithProof(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 8} {
+		blobHeaders := makeBlobHeaders(n)
+
+		header := &core.BatchHeader{}
+		tree, proofs, err := header.SetBatchRootWithProof(blobHeaders)
+		assert.NoError(t, err)
+		assert.Equal(t, tree.Root(), header.BatchRoot[:])
+		assert.Len(t, proofs, n)
+
+		// the root must match the one computed by SetBatchRoot
+		expected := &core.BatchHeader{}
+		_, err = expected.SetBatchRoot(blobHeaders)
+		assert.NoError(t, err)
+		assert.Equal(t, expected.BatchRoot, header.BatchRoot)
+
+		for i, blobHeader := range blobHeaders {
+			leaf, err := blobHeader.GetBlobHeaderHash()
+			assert.NoError(t, err)
+			proof := &merkletree.Proof{Hashes: proofs[i], Index: uint64(i)}
+			ok, err := merkletree.VerifyProofUsing(leaf[:], false, proof, [][]byte{header.BatchRoot[:]}, keccak256.New())
+			assert.NoError(t, err)
+			assert.True(t, ok, "invalid proof for blob %d of %d", i, n)
+		}
+	}
+}
+
+func TestSetBatchRootWithProofDuplicateHeaders(t *testing.T) {
+	// the same blob dispersed twice in the batch
+	blobHeaders := makeBlobHeaders(4)
+	blobHeaders[3] = blobHeaders[1]
+
+	header := &core.BatchHeader{}
+	_, proofs, err := header.SetBatchRootWithProof(blobHeaders)
+	require.NoError(t, err)
+	assert.NotEqual(t, proofs[1], proofs[3])
+	for i, blobHeader := range blobHeaders {
+		ok, err := core.VerifyBlobInclusionProof(header.BatchRoot, blobHeader, proofs[i], uint(i))
+		assert.NoError(t, err)
+		assert.True(t, ok, "invalid proof for blob %d", i)
+	}
+}
+
+func TestValidateBatchRoot(t *testing.T) {
+	for _, n := range []int{1, 2, 5} {
+		blobHeaders := makeBlobHeaders(n)
+		header := &core.BatchHeader{}
+		_, err := header.SetBatchRoot(blobHeaders)
+		assert.NoError(t, err)
+		root := header.BatchRoot
+
+		ok, err := header.ValidateBatchRoot(blobHeaders)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, root, header.BatchRoot)
+
+		// a single tampered blob header changes the root
+		tampered := makeBlobHeaders(n)
+		tampered[n-1].CommitmentRoot = []byte{0xff}
+		ok, err = header.ValidateBatchRoot(tampered)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		// the header being validated is left as is
+		assert.Equal(t, root, header.BatchRoot)
+	}
+
+	_, err := (&core.BatchHeader{}).ValidateBatchRoot(nil)
+	assert.Error(t, err)
+	_, err = (&core.BatchHeader{}).ValidateBatchRoot([]*core.BlobHeader{})
+	assert.Error(t, err)
+}
+
+func TestGetBlobHeaderHashV2(t *testing.T) {
+	commitmentRoot := make([]byte, 32)
+	commitmentRoot[0] = 0xda
+	newBlobHeader := func(quorumInfos ...*core.BlobQuorumInfo) core.BlobHeader {
+		return core.BlobHeader{CommitmentRoot: commitmentRoot, Length: 10, QuorumInfos: quorumInfos}
+	}
+	quorum0 := &core.BlobQuorumInfo{SecurityParam: core.SecurityParam{QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 80}, ChunkLength: 64}
+	quorum1 := &core.BlobQuorumInfo{SecurityParam: core.SecurityParam{QuorumID: 1, AdversaryThreshold: 50, QuorumThreshold: 80}, ChunkLength: 64}
+
+	header0 := newBlobHeader(quorum0)
+	header1 := newBlobHeader(quorum1)
+
+	// v1 only commits to the commitment root
+	v1Hash0, err := header0.GetBlobHeaderHash()
+	assert.NoError(t, err)
+	v1Hash1, err := header1.GetBlobHeaderHash()
+	assert.NoError(t, err)
+	assert.Equal(t, v1Hash0, v1Hash1)
+
+	v2Hash0, err := header0.GetBlobHeaderHashV2()
+	assert.NoError(t, err)
+	v2Hash1, err := header1.GetBlobHeaderHashV2()
+	assert.NoError(t, err)
+	assert.NotEqual(t, v2Hash0, v2Hash1)
+	assert.NotEqual(t, v1Hash0, v2Hash0)
+
+	// all the quorum parameters are committed to
+	for _, modified := range []*core.BlobQuorumInfo{
+		{SecurityParam: core.SecurityParam{QuorumID: 0, AdversaryThreshold: 40, QuorumThreshold: 80}, ChunkLength: 64},
+		{SecurityParam: core.SecurityParam{QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 90}, ChunkLength: 64},
+		{SecurityParam: core.SecurityParam{QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 80}, ChunkLength: 32},
+	} {
+		hash, err := newBlobHeader(modified).GetBlobHeaderHashV2()
+		assert.NoError(t, err)
+		assert.NotEqual(t, v2Hash0, hash)
+	}
+	bothHash, err := newBlobHeader(quorum0, quorum1).GetBlobHeaderHashV2()
+	assert.NoError(t, err)
+	assert.NotEqual(t, v2Hash0, bothHash)
+	again, err := newBlobHeader(quorum0).GetBlobHeaderHashV2()
+	assert.NoError(t, err)
+	assert.Equal(t, v2Hash0, again)
+
+	_, err = core.BlobHeader{CommitmentRoot: []byte{0xda}}.GetBlobHeaderHashV2()
+	assert.ErrorIs(t, err, core.ErrInvalidCommitment)
+}
+
+func TestSetBatchRootWithProofV2(t *testing.T) {
+	blobHeaders := make([]*core.BlobHeader, 3)
+	for i := range blobHeaders {
+		commitmentRoot := make([]byte, 32)
+		commitmentRoot[0] = byte(i)
+		blobHeaders[i] = &core.BlobHeader{
+			CommitmentRoot: commitmentRoot,
+			QuorumInfos:    []*core.BlobQuorumInfo{{SecurityParam: core.SecurityParam{QuorumID: 0}, ChunkLength: 64}},
+		}
+	}
+
+	header := &core.BatchHeader{}
+	_, proofs, err := header.SetBatchRootWithProofV2(blobHeaders)
+	assert.NoError(t, err)
+	v1Header := &core.BatchHeader{}
+	_, err = v1Header.SetBatchRoot(blobHeaders)
+	assert.NoError(t, err)
+	assert.NotEqual(t, v1Header.BatchRoot, header.BatchRoot)
+
+	for i, blobHeader := range blobHeaders {
+		leaf, err := blobHeader.GetBlobHeaderHashV2()
+		assert.NoError(t, err)
+		proof := &merkletree.Proof{Hashes: proofs[i], Index: uint64(i)}
+		ok, err := merkletree.VerifyProofUsing(leaf[:], false, proof, [][]byte{header.BatchRoot[:]}, keccak256.New())
+		assert.NoError(t, err)
+		assert.True(t, ok, "invalid proof for blob %d", i)
+
+		ok, err = core.VerifyBlobInclusionProofV2(header.BatchRoot, blobHeader, proofs[i], uint(i))
+		assert.NoError(t, err)
+		assert.True(t, ok, "invalid proof for blob %d", i)
+		// the v1 leaf isn't in the batch
+		ok, err = core.VerifyBlobInclusionProof(header.BatchRoot, blobHeader, proofs[i], uint(i))
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	// the proof doesn't hold for other quorum parameters
+	modified := *blobHeaders[1]
+	modified.QuorumInfos = []*core.BlobQuorumInfo{{SecurityParam: core.SecurityParam{QuorumID: 0}, ChunkLength: 32}}
+	ok, err := core.VerifyBlobInclusionProofV2(header.BatchRoot, &modified, proofs[1], 1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = header.ValidateBatchRootV2(blobHeaders)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = header.ValidateBatchRoot(blobHeaders)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = header.ValidateBatchRootV2([]*core.BlobHeader{blobHeaders[0], &modified, blobHeaders[2]})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHeadersJSON(t *testing.T) {
+	blobHeader := &core.BlobHeader{
+		CommitmentRoot: []byte{1, 2, 3},
+		Length:         10,
+		QuorumInfos: []*core.BlobQuorumInfo{{
+			SecurityParam: core.SecurityParam{QuorumID: 1, AdversaryThreshold: 50, QuorumThreshold: 80, QuorumRate: 32},
+			ChunkLength:   4,
+		}},
+	}
+	batchHeader := &core.BatchHeader{BatchRoot: [32]byte{1, 2}, DataRoot: eth_common.Hash{3, 4}}
+
+	data, err := json.Marshal(blobHeader)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"commitment_root":"AQID","length":10,"quorum_infos":[{"quorum_id":1,"adversary_threshold":50,"quorum_threshold":80,"quorum_rate":32,"ChunkLength":4}]}`, string(data))
+	decodedBlobHeader := new(core.BlobHeader)
+	require.NoError(t, json.Unmarshal(data, decodedBlobHeader))
+	assert.Equal(t, blobHeader, decodedBlobHeader)
+	// the encoding is deterministic
+	again, err := blobHeader.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+
+	data, err = json.Marshal(batchHeader)
+	require.NoError(t, err)
+	decodedBatchHeader := new(core.BatchHeader)
+	require.NoError(t, json.Unmarshal(data, decodedBatchHeader))
+	assert.Equal(t, batchHeader, decodedBatchHeader)
+
+	// the KV stream infos keep their format
+	data, err = json.Marshal(core.KVBatchInfo{BatchHeader: batchHeader})
+	require.NoError(t, err)
+	var kvBatchInfo map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &kvBatchInfo))
+	assert.JSONEq(t, `{"batch_root":[1,2,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0],"data_root":"0x0304000000000000000000000000000000000000000000000000000000000000"}`, string(kvBatchInfo["batch_header"]))
+
+	// the hashes don't depend on the serialization
+	hash, err := decodedBatchHeader.GetBatchHeaderHash()
+	require.NoError(t, err)
+	expected, err := batchHeader.GetBatchHeaderHash()
+	require.NoError(t, err)
+	assert.Equal(t, expected, hash)
+}
+
+func TestDecodeSerializationFormats(t *testing.T) {
+	header := &core.BlobHeader{CommitmentRoot: []byte{1, 2, 3}, Length: 10}
+	for _, name := range []string{"", "gob", "json"} {
+		format, err := core.ParseSerializationFormat(name)
+		require.NoError(t, err)
+		data, err := core.EncodeWithFormat(header, format)
+		require.NoError(t, err)
+		decoded, err := new(core.BlobHeader).Deserialize(data)
+		require.NoError(t, err)
+		assert.Equal(t, header, decoded)
+	}
+	_, err := core.ParseSerializationFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestVerifyBlobInclusionProof(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		blobHeaders := makeBlobHeaders(n)
+		header := &core.BatchHeader{}
+		_, proofs, err := header.SetBatchRootWithProof(blobHeaders)
+		require.NoError(t, err)
+
+		for i, blobHeader := range blobHeaders {
+			ok, err := core.VerifyBlobInclusionProof(header.BatchRoot, blobHeader, proofs[i], uint(i))
+			assert.NoError(t, err)
+			assert.True(t, ok, "invalid proof for blob %d of %d", i, n)
+		}
+	}
+
+	blobHeaders := makeBlobHeaders(5)
+	header := &core.BatchHeader{}
+	_, proofs, err := header.SetBatchRootWithProof(blobHeaders)
+	require.NoError(t, err)
+	tamperedProof := make([][]byte, len(proofs[2]))
+	for i, hash := range proofs[2] {
+		tamperedProof[i] = append([]byte(nil), hash...)
+	}
+	tamperedProof[0][0] ^= 1
+
+	testCases := []struct {
+		name       string
+		batchRoot  [32]byte
+		blobHeader *core.BlobHeader
+		proof      [][]byte
+		blobIndex  uint
+		ok         bool
+		err        bool
+	}{
+		{name: "valid proof", batchRoot: header.BatchRoot, blobHeader: blobHeaders[2], proof: proofs[2], blobIndex: 2, ok: true},
+		{name: "other batch root", batchRoot: [32]byte{1}, blobHeader: blobHeaders[2], proof: proofs[2], blobIndex: 2},
+		{name: "other blob header", batchRoot: header.BatchRoot, blobHeader: blobHeaders[3], proof: proofs[2], blobIndex: 2},
+		{name: "other blob index", batchRoot: header.BatchRoot, blobHeader: blobHeaders[2], proof: proofs[2], blobIndex: 3},
+		{name: "proof of another blob", batchRoot: header.BatchRoot, blobHeader: blobHeaders[2], proof: proofs[1], blobIndex: 2},
+		{name: "tampered proof", batchRoot: header.BatchRoot, blobHeader: blobHeaders[2], proof: tamperedProof, blobIndex: 2},
+		{name: "truncated proof", batchRoot: header.BatchRoot, blobHeader: blobHeaders[2], proof: proofs[2][:2], blobIndex: 2},
+		{name: "nil blob header", batchRoot: header.BatchRoot, proof: proofs[2], blobIndex: 2, err: true},
+		{name: "invalid commitment root", batchRoot: header.BatchRoot, blobHeader: &core.BlobHeader{}, proof: proofs[2], blobIndex: 2, err: true},
+		{name: "blob index out of range", batchRoot: header.BatchRoot, blobHeader: blobHeaders[2], proof: proofs[2], blobIndex: 8, err: true},
+		{name: "short hash", batchRoot: header.BatchRoot, blobHeader: blobHeaders[2], proof: [][]byte{proofs[2][0], {1, 2}, proofs[2][2]}, blobIndex: 2, err: true},
+		{name: "no proof for a multi-blob batch", batchRoot: header.BatchRoot, blobHeader: blobHeaders[0], blobIndex: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, err := core.VerifyBlobInclusionProof(tc.batchRoot, tc.blobHeader, tc.proof, tc.blobIndex)
+			if tc.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
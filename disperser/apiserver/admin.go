@@ -0,0 +1,227 @@
+package apiserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	adminpb "github.com/0glabs/0g-data-avail/api/grpc/admin"
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const bearerPrefix = "Bearer "
+
+var errAdminUnauthenticated = status.Error(codes.Unauthenticated, "invalid admin token")
+
+// authorizeAdmin checks the caller passed the configured admin token as "authorization: Bearer <token>"
+func (s *DispersalServer) authorizeAdmin(ctx context.Context) error {
+	if s.config.AdminToken == "" {
+		return errAdminUnauthenticated
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || !s.validAdminToken(md.Get("authorization")) {
+		return errAdminUnauthenticated
+	}
+	return nil
+}
+
+// validAdminToken checks one of the authorization values is "Bearer <token>" with the configured admin token
+func (s *DispersalServer) validAdminToken(authorization []string) bool {
+	if s.config.AdminToken == "" {
+		return false
+	}
+	for _, value := range authorization {
+		token, found := strings.CutPrefix(value, bearerPrefix)
+		if found && subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AdminToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRawBlobMetadata returns the metadata of a blob as the DynamoDB attributes it is stored as, alongside the decoded metadata
+func (s *DispersalServer) GetRawBlobMetadata(ctx context.Context, req *adminpb.RawMetadataRequest) (*adminpb.RawMetadataReply, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	requestID := req.GetRequestId()
+	if len(requestID) == 0 {
+		return nil, fmt.Errorf("invalid request: request_id must not be empty")
+	}
+	s.logger.Info("[apiserver] received a raw blob metadata request", "requestID", string(requestID))
+	metadataKey, err := disperser.ParseBlobKey(string(requestID))
+	if err != nil {
+		return nil, err
+	}
+
+	blobMetadata, err := s.blobStore.GetBlobMetadata(ctx, metadataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := blobstore.MarshalBlobMetadata(blobMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal blob metadata to attributes: %w", err)
+	}
+	rawAttributes, err := commondynamodb.MarshalItemJSON(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode blob metadata attributes: %w", err)
+	}
+	decoded, err := json.Marshal(blobMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode blob metadata: %w", err)
+	}
+
+	return &adminpb.RawMetadataReply{
+		RawAttributes: string(rawAttributes),
+		Metadata:      string(decoded),
+	}, nil
+}
+
+const (
+	defaultHashPrefixLimit = 100
+	maxHashPrefixLimit     = 1000
+)
+
+// FindBlobsByHashPrefix returns the IDs of the blobs whose blob hash or metadata hash starts with the given prefix.
+// It scans all the blobs, so it is disabled unless the prefix scan is allowed in the server config.
+func (s *DispersalServer) FindBlobsByHashPrefix(ctx context.Context, req *adminpb.HashPrefixRequest) (*adminpb.HashPrefixReply, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !s.config.AllowPrefixScan {
+		return nil, status.Error(codes.FailedPrecondition, "blob lookup by hash prefix is disabled")
+	}
+
+	hashPrefix := req.GetHashPrefix()
+	if len(hashPrefix) < disperser.MinHashPrefixLength {
+		return nil, status.Error(codes.InvalidArgument, disperser.ErrHashPrefixTooShort.Error())
+	}
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultHashPrefixLimit
+	}
+	if limit > maxHashPrefixLimit {
+		limit = maxHashPrefixLimit
+	}
+	s.logger.Info("[apiserver] received a blob lookup by hash prefix", "hashPrefix", hashPrefix, "limit", limit)
+
+	metadatas, err := s.blobStore.GetBlobMetadataByHashPrefix(ctx, hashPrefix, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	requestIDs := make([][]byte, len(metadatas))
+	for i, blobMetadata := range metadatas {
+		requestIDs[i] = []byte(blobMetadata.GetBlobKey().String())
+	}
+	return &adminpb.HashPrefixReply{RequestIds: requestIDs}, nil
+}
+
+// QueryBlobs returns the blobs of a status requested within a time range, sorted by request time
+func (s *DispersalServer) QueryBlobs(ctx context.Context, req *adminpb.QueryBlobsRequest) (*adminpb.QueryBlobsReply, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	blobStatus, err := disperser.FromBlobStatusProto(req.GetStatus())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	from := time.Unix(0, int64(req.GetFromRequestedAt()))
+	to := time.Now()
+	if req.GetToRequestedAt() != 0 {
+		to = time.Unix(0, int64(req.GetToRequestedAt()))
+	}
+	if from.After(to) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid time range: from_requested_at is after to_requested_at")
+	}
+	s.logger.Info("[apiserver] received a blob query", "status", blobStatus.String(), "from", from, "to", to)
+
+	metadatas, err := s.blobStore.GetBlobMetadataByStatusAndTimeRange(ctx, *blobStatus, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := make([]*adminpb.QueriedBlob, len(metadatas))
+	for i, blobMetadata := range metadatas {
+		blobs[i] = &adminpb.QueriedBlob{
+			RequestId:   []byte(blobMetadata.GetBlobKey().String()),
+			RequestedAt: blobMetadata.RequestMetadata.RequestedAt,
+			BlobSize:    uint64(blobMetadata.RequestMetadata.BlobSize),
+		}
+	}
+	return &adminpb.QueryBlobsReply{Blobs: blobs}, nil
+}
+
+// AdminOverrideBlobStatus sets the status of a blob to recover it when it is stuck, recording the reason in its status
+// history. Any blob can be failed and a confirmed blob can be finalized, the other transitions are rejected.
+func (s *DispersalServer) AdminOverrideBlobStatus(ctx context.Context, req *adminpb.AdminOverrideBlobStatusRequest) (*adminpb.AdminOverrideBlobStatusReply, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	requestID := req.GetRequestId()
+	if len(requestID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "request_id must not be empty")
+	}
+	if req.GetReason() == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason must not be empty")
+	}
+	targetStatus, err := disperser.FromBlobStatusProto(req.GetTargetStatus())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	blobKey, err := disperser.ParseBlobKey(string(requestID))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request_id: %v", err)
+	}
+
+	metadata, err := s.blobStore.GetBlobMetadata(ctx, blobKey)
+	if errors.Is(err, disperser.ErrBlobNotFound) || (err == nil && metadata == nil) {
+		return nil, status.Error(codes.NotFound, "blob not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	previousStatus := metadata.BlobStatus
+	if !isAllowedStatusOverride(previousStatus, *targetStatus) {
+		return nil, status.Errorf(codes.FailedPrecondition, "blob status can't be overridden from %s to %s", previousStatus, *targetStatus)
+	}
+
+	// the status is only overridden if it wasn't changed since it was checked, e.g. by the batcher
+	reason := "admin override: " + req.GetReason()
+	err = s.blobStore.TransitionBlobStatus(ctx, blobKey, previousStatus, *targetStatus, reason)
+	if errors.Is(err, disperser.ErrUnexpectedBlobStatus) {
+		return nil, status.Errorf(codes.FailedPrecondition, "blob status changed from %s, try again", previousStatus)
+	}
+	if err != nil {
+		s.logger.Error("[apiserver] failed to override blob status", "blobKey", blobKey.String(), "err", err)
+		return nil, status.Error(codes.Internal, "failed to override blob status")
+	}
+	s.logger.Warn("[apiserver] blob status overridden by admin", "blobKey", blobKey.String(), "previousStatus", previousStatus, "status", *targetStatus, "reason", req.GetReason())
+	return &adminpb.AdminOverrideBlobStatusReply{PreviousStatus: getResponseStatus(previousStatus)}, nil
+}
+
+// isAllowedStatusOverride returns whether an operator may move a blob from the status to the target status:
+// any blob which isn't failed yet may be failed, and a confirmed blob may be finalized
+func isAllowedStatusOverride(from, to disperser.BlobStatus) bool {
+	switch to {
+	case disperser.Failed:
+		return from != disperser.Failed
+	case disperser.Finalized:
+		return from == disperser.Confirmed
+	default:
+		return false
+	}
+}
@@ -0,0 +1,225 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// adminRateConfigPath is the path of the endpoint replacing the rate config
+	adminRateConfigPath = "/admin/rateconfig"
+	// adminBlocklistPath is the path of the endpoint replacing the blocklist of the rate limiter
+	adminBlocklistPath = "/admin/blocklist"
+	// maxRateConfigSize bounds the size of a posted rate config
+	maxRateConfigSize = 1 << 20
+)
+
+// ReloadRateConfig replaces the rate config of the server. The config is validated first and left unchanged if it is
+// invalid. The requests in flight complete under the previous config.
+func (s *DispersalServer) ReloadRateConfig(newConfig RateConfig) error {
+	if err := ValidateRateConfig(newConfig); err != nil {
+		return err
+	}
+	cidrRates, err := newCIDRRateTable(newConfig.CIDRRates)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.rateConfig = newConfig
+	s.cidrRates = cidrRates
+	s.mu.Unlock()
+	s.logger.Info("[apiserver] rate config reloaded", "quorums", len(newConfig.QuorumRateInfos), "cidrRates", len(newConfig.CIDRRates))
+	return nil
+}
+
+// ReloadBlocklist replaces the requester IDs the rate limiter always rejects
+func (s *DispersalServer) ReloadBlocklist(ids []string) error {
+	setter, ok := s.ratelimiter.(common.BlocklistSetter)
+	if !ok {
+		return errors.New("the rate limiter is not enabled or has no blocklist")
+	}
+	setter.SetBlocklist(ids)
+	s.logger.Info("[apiserver] blocklist reloaded", "ids", len(ids))
+	return nil
+}
+
+// AdminServer serves the operator endpoints on an internal port, separate from the public APIs:
+//   - POST /admin/rateconfig: replaces the rate config with the posted RateConfigFile
+//   - POST /admin/blocklist: replaces the blocklist of the rate limiter with the posted JSON array of requester IDs
+//
+// The requests must carry the admin token as "Authorization: Bearer <token>". The admin server also reloads the rate
+// config whenever the rate config file changes, if one is given.
+type AdminServer struct {
+	server         *DispersalServer
+	port           string
+	rateConfigFile string
+}
+
+// NewAdminServer creates an admin server listening on port, the http endpoints are not served if it is empty.
+// The rate config file is not watched if it is empty.
+func NewAdminServer(server *DispersalServer, port string, rateConfigFile string) *AdminServer {
+	return &AdminServer{
+		server:         server,
+		port:           port,
+		rateConfigFile: rateConfigFile,
+	}
+}
+
+// Start loads and watches the rate config file, and serves the http requests, until the context is done
+func (a *AdminServer) Start(ctx context.Context) error {
+	if a.rateConfigFile != "" {
+		if err := a.reloadRateConfigFile(); err != nil {
+			return err
+		}
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed to watch rate config file: %w", err)
+		}
+		// the directory is watched as editors and config maps replace the file rather than write it
+		if err := watcher.Add(filepath.Dir(a.rateConfigFile)); err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch rate config file: %w", err)
+		}
+		go a.watchRateConfigFile(ctx, watcher)
+	}
+	if a.port == "" {
+		<-ctx.Done()
+		return nil
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf("%s:%s", disperser.Localhost, a.port),
+		Handler:           a.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpGatewayShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			a.server.logger.Warn("[apiserver] failed to shut down the admin server", "err", err)
+		}
+	}()
+
+	a.server.logger.Info("[apiserver] admin server listening", "port", a.port)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("could not start admin server: %w", err)
+	}
+	return nil
+}
+
+// Handler returns the handler of the admin endpoints
+func (a *AdminServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(adminRateConfigPath, a.handleRateConfig)
+	mux.HandleFunc(adminBlocklistPath, a.handleBlocklist)
+	return mux
+}
+
+func (a *AdminServer) handleRateConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !a.server.validAdminToken(r.Header.Values("Authorization")) {
+		http.Error(w, errAdminUnauthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRateConfigSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+	config, err := ParseRateConfigJSON(body)
+	if err == nil {
+		err = a.server.ReloadRateConfig(config)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *AdminServer) handleBlocklist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !a.server.validAdminToken(r.Header.Values("Authorization")) {
+		http.Error(w, errAdminUnauthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRateConfigSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+	var ids []string
+	if err := json.Unmarshal(body, &ids); err != nil {
+		http.Error(w, fmt.Sprintf("invalid blocklist: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := a.server.ReloadBlocklist(ids); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *AdminServer) watchRateConfigFile(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			// kubernetes config maps update the files of a directory at once, by swapping its ..data symlink
+			name := filepath.Clean(event.Name)
+			if name != filepath.Clean(a.rateConfigFile) && filepath.Base(name) != "..data" {
+				continue
+			}
+			// the previous config is kept if the new one is invalid, e.g. because it is only partially written
+			if err := a.reloadRateConfigFile(); err != nil {
+				a.server.logger.Error("[apiserver] failed to reload rate config file", "file", a.rateConfigFile, "err", err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			a.server.logger.Warn("[apiserver] rate config file watcher error", "err", err)
+		}
+	}
+}
+
+func (a *AdminServer) reloadRateConfigFile() error {
+	data, err := os.ReadFile(a.rateConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to read rate config file: %w", err)
+	}
+	config, err := ParseRateConfigJSON(data)
+	if err != nil {
+		return err
+	}
+	return a.server.ReloadRateConfig(config)
+}
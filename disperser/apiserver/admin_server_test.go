@@ -0,0 +1,152 @@
+package apiserver_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/peer"
+)
+
+func TestParseRateConfigJSON(t *testing.T) {
+	config, err := apiserver.ParseRateConfigJSON([]byte(`{
+		"quorums": [{"quorum_id": 1, "total_unauth_byte_rate": 1000, "per_user_unauth_byte_rate": 10, "total_unauth_blob_rate": 2, "per_user_unauth_blob_rate": 0.5}],
+		"client_ip_header": "X-Real-IP",
+		"cidr_rates": ["10.0.0.0/24=100:1"]
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, apiserver.QuorumRateInfo{
+		TotalUnauthThroughput:   1000,
+		PerUserUnauthThroughput: 10,
+		TotalUnauthBlobRate:     2_000_000,
+		PerUserUnauthBlobRate:   500_000,
+	}, config.QuorumRateInfos[1])
+	assert.Equal(t, "X-Real-IP", config.ClientIPHeader)
+	assert.Len(t, config.CIDRRates, 1)
+
+	for _, invalid := range []string{
+		`not json`,
+		`{"quorums": []}`,
+		`{"quorums": [{"quorum_id": 0, "total_unauth_byte_rate": -1}]}`,
+		`{"quorums": [{"quorum_id": 0, "per_user_unauth_blob_rate": -0.5}]}`,
+		`{"quorums": [{"quorum_id": 0}, {"quorum_id": 0}]}`,
+		`{"quorums": [{"quorum_id": 0}], "cidr_rates": ["10.0.0.0=100:1"]}`,
+	} {
+		_, err := apiserver.ParseRateConfigJSON([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestAdminServerReloadRateConfig(t *testing.T) {
+	logger := &mock.Logger{}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+	assert.NoError(t, err)
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{10 * time.Second},
+		Multipliers: []float32{1},
+	}, bucketStore, nil, nil, nil, logger)
+	rateConfig := apiserver.RateConfig{
+		QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+			0: {TotalUnauthThroughput: 1_000_000, TotalUnauthBlobRate: 1000 * 1e6, PerUserUnauthThroughput: 10, PerUserUnauthBlobRate: 1e6},
+		},
+	}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{AdminToken: "secret"}, blobStore, logger, disperser.NewMetrics("9100", logger), ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+	admin := apiserver.NewAdminServer(server, "0", "")
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 51001}})
+	accountRate := func() float64 {
+		reply, err := server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{})
+		assert.NoError(t, err)
+		return reply.GetBuckets()[0].GetRate()
+	}
+	postRateConfig := func(token string, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/rateconfig", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		admin.Handler().ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	assert.Equal(t, float64(10), accountRate())
+
+	newConfig := `{"quorums": [{"quorum_id": 0, "total_unauth_byte_rate": 1000000, "per_user_unauth_byte_rate": 5000, "total_unauth_blob_rate": 1000, "per_user_unauth_blob_rate": 1}]}`
+	assert.Equal(t, http.StatusUnauthorized, postRateConfig("", newConfig))
+	assert.Equal(t, http.StatusUnauthorized, postRateConfig("wrong", newConfig))
+	assert.Equal(t, float64(10), accountRate())
+
+	// an invalid config leaves the current one in place
+	assert.Equal(t, http.StatusBadRequest, postRateConfig("secret", `{"quorums": [{"quorum_id": 0, "per_user_unauth_byte_rate": -1}]}`))
+	assert.Equal(t, float64(10), accountRate())
+
+	assert.Equal(t, http.StatusNoContent, postRateConfig("secret", newConfig))
+	assert.Equal(t, float64(5000), accountRate())
+
+	assert.Error(t, server.ReloadRateConfig(apiserver.RateConfig{}))
+	assert.Equal(t, float64(5000), accountRate())
+}
+
+func TestAdminServerReloadBlocklist(t *testing.T) {
+	logger := &mock.Logger{}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+	assert.NoError(t, err)
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{10 * time.Second},
+		Multipliers: []float32{1},
+	}, bucketStore, nil, nil, nil, logger)
+	rateConfig := apiserver.RateConfig{
+		QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+			0: {TotalUnauthThroughput: 1_000_000, TotalUnauthBlobRate: 1000 * 1e6, PerUserUnauthThroughput: 1_000_000, PerUserUnauthBlobRate: 1000 * 1e6},
+		},
+	}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{AdminToken: "secret"}, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+	admin := apiserver.NewAdminServer(server, "0", "")
+
+	postBlocklist := func(token string, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/blocklist", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		admin.Handler().ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	disperse := func() error {
+		ctx, _ := newTestContext()
+		_, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+			Data:           []byte("blocklisted blob"),
+			SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 100}},
+		})
+		return err
+	}
+	assert.NoError(t, disperse())
+
+	assert.Equal(t, http.StatusUnauthorized, postBlocklist("", `["127.0.0.1"]`))
+	assert.Equal(t, http.StatusBadRequest, postBlocklist("secret", `"127.0.0.1"`))
+	assert.NoError(t, disperse())
+
+	assert.Equal(t, http.StatusNoContent, postBlocklist("secret", `["127.0.0.1"]`))
+	assert.ErrorContains(t, disperse(), "account limit")
+
+	assert.Equal(t, http.StatusNoContent, postBlocklist("secret", `[]`))
+	assert.NoError(t, disperse())
+
+	// the blocklist can't be set without a rate limiter
+	assert.Error(t, newTestServer(disperser.ServerConfig{}).ReloadBlocklist([]string{"127.0.0.1"}))
+}
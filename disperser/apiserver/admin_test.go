@@ -0,0 +1,265 @@
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	adminpb "github.com/0glabs/0g-data-avail/api/grpc/admin"
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetRawBlobMetadata(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{AdminToken: "secret"}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+
+	ctx, _ := newTestContext()
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+		Data:           []byte("raw metadata"),
+		SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+	})
+	assert.NoError(t, err)
+	key, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+	assert.NoError(t, err)
+	stored, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	_, err = blobStore.MarkBlobConfirmed(ctx, stored, &disperser.ConfirmationInfo{
+		BatchHeaderHash:         [32]byte{1, 2, 3},
+		BlobIndex:               7,
+		ConfirmationBlockNumber: 100,
+	})
+	assert.NoError(t, err)
+	expected, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+
+	// the token is required
+	request := &adminpb.RawMetadataRequest{RequestId: reply.GetRequestId()}
+	for _, md := range []metadata.MD{nil, metadata.Pairs("authorization", "Bearer wrong"), metadata.Pairs("authorization", "secret")} {
+		_, err = server.GetRawBlobMetadata(metadata.NewIncomingContext(ctx, md), request)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	adminCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer secret"))
+	raw, err := server.GetRawBlobMetadata(adminCtx, request)
+	assert.NoError(t, err)
+
+	// the raw attributes keep their dynamodb types and decode to the same metadata
+	item, err := commondynamodb.UnmarshalItemJSON([]byte(raw.GetRawAttributes()))
+	assert.NoError(t, err)
+	assert.IsType(t, &types.AttributeValueMemberN{}, item["BlobStatus"])
+	assert.IsType(t, &types.AttributeValueMemberB{}, item["BatchHeaderHash"])
+	decoded, err := blobstore.UnmarshalBlobMetadata(item)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, decoded)
+
+	var metadataReply disperser.BlobMetadata
+	assert.NoError(t, json.Unmarshal([]byte(raw.GetMetadata()), &metadataReply))
+	assert.Equal(t, expected, &metadataReply)
+
+	_, err = server.GetRawBlobMetadata(adminCtx, &adminpb.RawMetadataRequest{})
+	assert.Error(t, err)
+}
+
+func TestFindBlobsByHashPrefix(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	newServer := func(allowPrefixScan bool) *apiserver.DispersalServer {
+		return apiserver.NewDispersalServer(disperser.ServerConfig{AdminToken: "secret", AllowPrefixScan: allowPrefixScan}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+	}
+	server := newServer(true)
+
+	ctx, _ := newTestContext()
+	keys := make([]disperser.BlobKey, 3)
+	for i := range keys {
+		reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+			Data:           []byte(fmt.Sprintf("prefix scan %d", i)),
+			SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+		})
+		assert.NoError(t, err)
+		keys[i], err = disperser.ParseBlobKey(string(reply.GetRequestId()))
+		assert.NoError(t, err)
+	}
+
+	adminCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer secret"))
+	_, err := server.FindBlobsByHashPrefix(ctx, &adminpb.HashPrefixRequest{HashPrefix: keys[0].BlobHash[:8]})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the blobs are found by a prefix of their blob hash and by their metadata hash,
+	// whose prefix is the request time shared by the blobs
+	for _, key := range keys {
+		for _, prefix := range []string{key.BlobHash[:12], key.MetadataHash} {
+			reply, err := server.FindBlobsByHashPrefix(adminCtx, &adminpb.HashPrefixRequest{HashPrefix: prefix})
+			assert.NoError(t, err)
+			assert.Equal(t, [][]byte{[]byte(key.String())}, reply.GetRequestIds())
+		}
+	}
+
+	reply, err := server.FindBlobsByHashPrefix(adminCtx, &adminpb.HashPrefixRequest{HashPrefix: "zzzzzzzz"})
+	assert.NoError(t, err)
+	assert.Empty(t, reply.GetRequestIds())
+
+	reply, err = server.FindBlobsByHashPrefix(adminCtx, &adminpb.HashPrefixRequest{HashPrefix: keys[0].MetadataHash[:8], Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, reply.GetRequestIds(), 2)
+
+	_, err = server.FindBlobsByHashPrefix(adminCtx, &adminpb.HashPrefixRequest{HashPrefix: keys[0].BlobHash[:7]})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// the scan must be allowed
+	_, err = newServer(false).FindBlobsByHashPrefix(adminCtx, &adminpb.HashPrefixRequest{HashPrefix: keys[0].BlobHash[:8]})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestQueryBlobs(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{AdminToken: "secret"}, blobStore, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+
+	ctx, _ := newTestContext()
+	keys := make([]disperser.BlobKey, 3)
+	for i := range keys {
+		reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+			Data:           []byte(fmt.Sprintf("queried blob %d", i)),
+			SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+		})
+		assert.NoError(t, err)
+		keys[i], err = disperser.ParseBlobKey(string(reply.GetRequestId()))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, blobStore.MarkBlobFailed(ctx, keys[1]))
+
+	adminCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer secret"))
+	query := func(req *adminpb.QueryBlobsRequest) []*adminpb.QueriedBlob {
+		reply, err := server.QueryBlobs(adminCtx, req)
+		assert.NoError(t, err)
+		return reply.GetBlobs()
+	}
+
+	_, err := server.QueryBlobs(ctx, &adminpb.QueryBlobsRequest{Status: pb.BlobStatus_PROCESSING})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the blobs of the status up to now, sorted by request time
+	processing := query(&adminpb.QueryBlobsRequest{Status: pb.BlobStatus_PROCESSING})
+	assert.Len(t, processing, 2)
+	assert.Equal(t, []byte(keys[0].String()), processing[0].GetRequestId())
+	assert.Equal(t, []byte(keys[2].String()), processing[1].GetRequestId())
+	assert.LessOrEqual(t, processing[0].GetRequestedAt(), processing[1].GetRequestedAt())
+	assert.Equal(t, uint64(len("queried blob 0")), processing[0].GetBlobSize())
+
+	failed := query(&adminpb.QueryBlobsRequest{Status: pb.BlobStatus_FAILED})
+	assert.Len(t, failed, 1)
+	assert.Equal(t, []byte(keys[1].String()), failed[0].GetRequestId())
+
+	// the range bounds are inclusive
+	requestedAt := processing[1].GetRequestedAt()
+	assert.Len(t, query(&adminpb.QueryBlobsRequest{Status: pb.BlobStatus_PROCESSING, FromRequestedAt: requestedAt, ToRequestedAt: requestedAt}), 1)
+	assert.Empty(t, query(&adminpb.QueryBlobsRequest{Status: pb.BlobStatus_PROCESSING, FromRequestedAt: 1, ToRequestedAt: processing[0].GetRequestedAt() - 1}))
+	assert.Empty(t, query(&adminpb.QueryBlobsRequest{Status: pb.BlobStatus_CONFIRMED}))
+
+	_, err = server.QueryBlobs(adminCtx, &adminpb.QueryBlobsRequest{Status: pb.BlobStatus_PROCESSING, FromRequestedAt: 2, ToRequestedAt: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.QueryBlobs(adminCtx, &adminpb.QueryBlobsRequest{Status: pb.BlobStatus_UNKNOWN})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAdminOverrideBlobStatus(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{AdminToken: "secret"}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+
+	ctx, _ := newTestContext()
+	disperse := func(data string) ([]byte, disperser.BlobKey) {
+		reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte(data), SecurityParams: testSecurityParams})
+		assert.NoError(t, err)
+		key, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+		assert.NoError(t, err)
+		return reply.GetRequestId(), key
+	}
+	stuckID, stuckKey := disperse("stuck blob")
+	confirmedID, confirmedKey := disperse("confirmed blob")
+	stored, err := blobStore.GetBlobMetadata(ctx, confirmedKey)
+	assert.NoError(t, err)
+	_, err = blobStore.MarkBlobConfirmed(ctx, stored, &disperser.ConfirmationInfo{BatchHeaderHash: [32]byte{1}})
+	assert.NoError(t, err)
+
+	request := &adminpb.AdminOverrideBlobStatusRequest{RequestId: stuckID, TargetStatus: pb.BlobStatus_FAILED, Reason: "encoder crashed"}
+	_, err = server.AdminOverrideBlobStatus(ctx, request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	adminCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer secret"))
+	for _, invalid := range []*adminpb.AdminOverrideBlobStatusRequest{
+		{TargetStatus: pb.BlobStatus_FAILED, Reason: "no request id"},
+		{RequestId: stuckID, TargetStatus: pb.BlobStatus_FAILED},
+		{RequestId: stuckID, TargetStatus: pb.BlobStatus_UNKNOWN, Reason: "unknown status"},
+	} {
+		_, err = server.AdminOverrideBlobStatus(adminCtx, invalid)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	// a processing blob can be failed, with the reason in its history
+	reply, err := server.AdminOverrideBlobStatus(adminCtx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetPreviousStatus())
+	blobMetadata, err := blobStore.GetBlobMetadata(ctx, stuckKey)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Failed, blobMetadata.BlobStatus)
+	lastEvent := blobMetadata.BlobStatusHistory[len(blobMetadata.BlobStatusHistory)-1]
+	assert.Equal(t, disperser.Failed, lastEvent.Status)
+	assert.Equal(t, "admin override: encoder crashed", lastEvent.Reason)
+
+	// a confirmed blob can be finalized, while a failed blob can't be finalized or failed again
+	reply, err = server.AdminOverrideBlobStatus(adminCtx, &adminpb.AdminOverrideBlobStatusRequest{RequestId: confirmedID, TargetStatus: pb.BlobStatus_FINALIZED, Reason: "finalizer stuck"})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_CONFIRMED, reply.GetPreviousStatus())
+	blobMetadata, err = blobStore.GetBlobMetadata(ctx, confirmedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Finalized, blobMetadata.BlobStatus)
+
+	for _, target := range []pb.BlobStatus{pb.BlobStatus_FINALIZED, pb.BlobStatus_FAILED, pb.BlobStatus_PROCESSING} {
+		_, err = server.AdminOverrideBlobStatus(adminCtx, &adminpb.AdminOverrideBlobStatusRequest{RequestId: stuckID, TargetStatus: target, Reason: "invalid transition"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err), target)
+	}
+	_, err = server.AdminOverrideBlobStatus(adminCtx, &adminpb.AdminOverrideBlobStatusRequest{RequestId: confirmedID, TargetStatus: pb.BlobStatus_PROCESSING, Reason: "invalid transition"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestAdminOverrideBlobStatusChangedConcurrently(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := &staleBlobStore{BlobStore: memorydb.NewBlobStore(1024*1024, logger), stale: make(map[disperser.BlobKey]*disperser.BlobMetadata)}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{AdminToken: "secret"}, blobStore, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+	ctx, _ := newTestContext()
+	adminCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer secret"))
+
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("finalized blob"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	blobKey, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+	assert.NoError(t, err)
+	processing, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	// the blob is confirmed after the server read it as processing
+	_, err = blobStore.MarkBlobConfirmed(ctx, processing, &disperser.ConfirmationInfo{BatchHeaderHash: [32]byte{1}})
+	assert.NoError(t, err)
+	blobStore.stale[blobKey] = processing
+
+	_, err = server.AdminOverrideBlobStatus(adminCtx, &adminpb.AdminOverrideBlobStatusRequest{RequestId: reply.GetRequestId(), TargetStatus: pb.BlobStatus_FAILED, Reason: "encoder crashed"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	blobMetadata, err := blobStore.BlobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, blobMetadata.BlobStatus)
+}
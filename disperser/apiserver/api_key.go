@@ -0,0 +1,197 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyHeader is the metadata header, or the http header of the gateway, carrying the API key of the caller
+const apiKeyHeader = "x-api-key"
+
+// apiKeyExemptServices are the grpc services which don't require an API key: the health checks of the orchestrators
+// and the admin APIs, which are authenticated by the admin token
+var apiKeyExemptServices = []string{"/grpc.health.v1.Health/", "/admin.Admin/"}
+
+var errInvalidAPIKey = status.Error(codes.Unauthenticated, "missing or invalid API key")
+
+type accountIDContextKey struct{}
+
+// APIKeyAuth authenticates the callers by the API key of their requests, and maps them to their account ID.
+// The keys are loaded from a JSON file mapping each key to its account ID, e.g. {"3f9a...": "rollup-a"}, which can be
+// reloaded without a restart.
+type APIKeyAuth struct {
+	path string
+
+	mu   sync.RWMutex
+	keys map[string]string
+
+	logger common.Logger
+}
+
+// NewAPIKeyAuth creates an API key authentication with a fixed map of the API keys to their account IDs
+func NewAPIKeyAuth(validKeys map[string]string) *APIKeyAuth {
+	return &APIKeyAuth{keys: validKeys}
+}
+
+// LoadAPIKeyAuth creates an API key authentication with the keys of the file at path, see Reload
+func LoadAPIKeyAuth(path string, logger common.Logger) (*APIKeyAuth, error) {
+	auth := &APIKeyAuth{path: path, logger: logger}
+	if err := auth.Reload(); err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// APIKeyAuthInterceptor rejects the requests without one of the valid API keys with codes.Unauthenticated, and passes
+// the account ID of the key of the accepted requests to the rate limiter
+func APIKeyAuthInterceptor(validKeys map[string]string) grpc.UnaryServerInterceptor {
+	return NewAPIKeyAuth(validKeys).UnaryInterceptor()
+}
+
+// WithAPIKeyAuth requires the callers of the dispersal APIs to present an API key, of the grpc server and of the
+// http gateway. The requests are rate limited by the account ID of their key.
+func WithAPIKeyAuth(auth *APIKeyAuth) ServerOption {
+	return func(s *DispersalServer) {
+		s.apiKeyAuth = auth
+	}
+}
+
+// Reload replaces the keys with the keys of the file, it's a no-op if the keys are fixed. The keys are left unchanged
+// if the file is invalid.
+func (a *APIKeyAuth) Reload() error {
+	if a.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		return fmt.Errorf("failed to read API key file: %w", err)
+	}
+	keys := make(map[string]string)
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("failed to parse API key file: %w", err)
+	}
+	for key, accountID := range keys {
+		if key == "" || accountID == "" {
+			return fmt.Errorf("invalid API key file: keys and account IDs must not be empty")
+		}
+	}
+
+	a.mu.Lock()
+	a.keys = keys
+	a.mu.Unlock()
+	a.logger.Info("[apiserver] API keys loaded", "path", a.path, "keys", len(keys))
+	return nil
+}
+
+// ReloadOnSignal reloads the keys whenever the process receives a SIGHUP, until the context is done
+func (a *APIKeyAuth) ReloadOnSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				if err := a.Reload(); err != nil {
+					a.logger.Error("[apiserver] failed to reload API keys, keeping the previous keys", "err", err)
+				}
+			}
+		}
+	}()
+}
+
+// accountID returns the account ID of the API key
+func (a *APIKeyAuth) accountID(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	accountID, ok := a.keys[key]
+	return accountID, ok
+}
+
+// authenticate returns the context of the request with the account ID of its API key
+func (a *APIKeyAuth) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	for _, service := range apiKeyExemptServices {
+		if strings.HasPrefix(fullMethod, service) {
+			return ctx, nil
+		}
+	}
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(apiKeyHeader); len(values) > 0 {
+			key = values[0]
+		}
+	}
+	accountID, ok := a.accountID(key)
+	if !ok {
+		return nil, errInvalidAPIKey
+	}
+	return context.WithValue(ctx, accountIDContextKey{}, accountID), nil
+}
+
+// UnaryInterceptor authenticates the unary requests
+func (a *APIKeyAuth) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authenticates the streams, e.g. DisperseBlobStream
+func (a *APIKeyAuth) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// httpHandler authenticates the http gateway requests, by their X-Api-Key header
+func (a *APIKeyAuth) httpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID, ok := a.accountID(r.Header.Get(apiKeyHeader))
+		if !ok {
+			writeHTTPError(w, errInvalidAPIKey)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), accountIDContextKey{}, accountID)))
+	})
+}
+
+// authenticatedStream is a server stream with the context of its authenticated caller
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticatedAccountID returns the account ID of the API key of the request, or "" if it wasn't authenticated
+func authenticatedAccountID(ctx context.Context) string {
+	accountID, _ := ctx.Value(accountIDContextKey{}).(string)
+	return accountID
+}
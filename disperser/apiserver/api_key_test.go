@@ -0,0 +1,136 @@
+package apiserver_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newAPIKeyTestServer(t *testing.T, auth *apiserver.APIKeyAuth, auditLogger common.AuditLogger) (*apiserver.DispersalServer, *grpc.ClientConn) {
+	logger := &mock.Logger{}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithAPIKeyAuth(auth), apiserver.WithAuditLogger(auditLogger))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Shutdown)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return server, conn
+}
+
+func withAPIKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	auditLogger := &recordingAuditLogger{}
+	_, conn := newAPIKeyTestServer(t, apiserver.NewAPIKeyAuth(map[string]string{"key-a": "rollup-a"}), auditLogger)
+	client := pb.NewDisperserClient(conn)
+
+	request := &pb.DisperseBlobRequest{Data: []byte("authenticated blob"), SecurityParams: testSecurityParams}
+	_, err := client.DisperseBlob(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.DisperseBlob(withAPIKey("key-b"), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	reply, err := client.DisperseBlob(withAPIKey("key-a"), request)
+	require.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetResult())
+	// the blob is accounted to the account of the key
+	require.Len(t, auditLogger.events, 1)
+	assert.Equal(t, "rollup-a", auditLogger.events[0].RequesterID)
+
+	// the streams are authenticated too
+	stream, err := client.DisperseBlobStream(context.Background())
+	require.NoError(t, err)
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the health checks don't need a key
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestAPIKeyAuthHTTPGateway(t *testing.T) {
+	server, _ := newAPIKeyTestServer(t, apiserver.NewAPIKeyAuth(map[string]string{"key-a": "rollup-a"}), &recordingAuditLogger{})
+	gateway := httptest.NewServer(apiserver.NewHTTPGatewayServer(server, "").Handler())
+	defer gateway.Close()
+
+	post := func(key string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, gateway.URL+"/v1/blob", strings.NewReader(`{"data": "aGVsbG8=", "security_params": [{"quorum_id": 0, "adversary_threshold": 50, "quorum_threshold": 80}]}`))
+		require.NoError(t, err)
+		if key != "" {
+			req.Header.Set("X-Api-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+	assert.Equal(t, http.StatusUnauthorized, post("").StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, post("key-b").StatusCode)
+	assert.Equal(t, http.StatusOK, post("key-a").StatusCode)
+}
+
+func TestAPIKeyFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"key-a": "rollup-a"}`), 0600))
+	auth, err := apiserver.LoadAPIKeyAuth(path, &mock.Logger{})
+	require.NoError(t, err)
+	_, conn := newAPIKeyTestServer(t, auth, &recordingAuditLogger{})
+	client := pb.NewDisperserClient(conn)
+
+	_, err = client.DisperseBlob(withAPIKey("key-a"), &pb.DisperseBlobRequest{Data: []byte("first blob"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+
+	// the keys are rotated on SIGHUP
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auth.ReloadOnSignal(ctx)
+	require.NoError(t, os.WriteFile(path, []byte(`{"key-b": "rollup-a"}`), 0600))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		_, err := client.DisperseBlob(withAPIKey("key-a"), &pb.DisperseBlobRequest{Data: []byte("rotated blob"), SecurityParams: testSecurityParams})
+		return status.Code(err) == codes.Unauthenticated
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = client.DisperseBlob(withAPIKey("key-b"), &pb.DisperseBlobRequest{Data: []byte("second blob"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+
+	// an invalid file keeps the previous keys
+	require.NoError(t, os.WriteFile(path, []byte(`{"key-c": ""}`), 0600))
+	assert.Error(t, auth.Reload())
+	_, err = client.DisperseBlob(withAPIKey("key-b"), &pb.DisperseBlobRequest{Data: []byte("third blob"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+
+	_, err = apiserver.LoadAPIKeyAuth(filepath.Join(t.TempDir(), "missing.json"), &mock.Logger{})
+	assert.Error(t, err)
+}
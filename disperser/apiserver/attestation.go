@@ -0,0 +1,55 @@
+package apiserver
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// WithAttestationKey makes DisperseBlob attest the time each blob was received at, signed with the given key
+func WithAttestationKey(key *ecdsa.PrivateKey) ServerOption {
+	return func(s *DispersalServer) {
+		s.attestationKey = key
+	}
+}
+
+// attestSubmission adds the submission attestation to the reply, it's a no-op if the server has no attestation key
+func (s *DispersalServer) attestSubmission(reply *pb.DisperseBlobReply, requestedAt uint64) error {
+	if s.attestationKey == nil {
+		return nil
+	}
+	signature, err := disperser.SignSubmissionAttestation(reply.GetRequestId(), requestedAt, s.attestationKey)
+	if err != nil {
+		return err
+	}
+	reply.AttestationSignature = signature
+	reply.AttestationPubkey = crypto.CompressPubkey(&s.attestationKey.PublicKey)
+	reply.RequestedAt = requestedAt
+	return nil
+}
+
+// signReceipt adds the signed receipt of the stored blob to the reply, it's a no-op if the server has no attestation key
+func (s *DispersalServer) signReceipt(reply *pb.DisperseBlobReply, blobKey disperser.BlobKey, requestedAt uint64, securityParams []*core.SecurityParam) error {
+	if s.attestationKey == nil {
+		return nil
+	}
+	receipt, err := disperser.NewDisperserReceipt(blobKey, requestedAt, securityParams)
+	if err != nil {
+		return err
+	}
+	encoded, err := receipt.Encode()
+	if err != nil {
+		return fmt.Errorf("failed to encode disperser receipt: %w", err)
+	}
+	signature, err := disperser.SignDisperserReceipt(encoded, s.attestationKey)
+	if err != nil {
+		return err
+	}
+	reply.Receipt = encoded
+	reply.ReceiptSignature = signature
+	return nil
+}
@@ -0,0 +1,121 @@
+package apiserver_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	clients "github.com/0glabs/0g-data-avail/clients/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDisperseBlobSubmissionAttestation(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	logger := &mock.Logger{}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithAttestationKey(key))
+
+	ctx, _ := newTestContext()
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("attested blob"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	assert.NotZero(t, reply.GetRequestedAt())
+	assert.Equal(t, crypto.CompressPubkey(&key.PublicKey), reply.GetAttestationPubkey())
+
+	ok, err := clients.VerifySubmissionAttestation(reply)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// tampered timestamp
+	tampered := proto.Clone(reply).(*pb.DisperseBlobReply)
+	tampered.RequestedAt--
+	ok, err = clients.VerifySubmissionAttestation(tampered)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// tampered request id
+	tampered = proto.Clone(reply).(*pb.DisperseBlobReply)
+	tampered.RequestId = append([]byte{}, reply.GetRequestId()...)
+	tampered.RequestId[0] ^= 0xff
+	ok, err = clients.VerifySubmissionAttestation(tampered)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// signed by another key
+	otherKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	tampered = proto.Clone(reply).(*pb.DisperseBlobReply)
+	tampered.AttestationPubkey = crypto.CompressPubkey(&otherKey.PublicKey)
+	ok, err = clients.VerifySubmissionAttestation(tampered)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestDisperseBlobWithoutAttestationKey(t *testing.T) {
+	server := newTestServer(disperser.ServerConfig{})
+
+	ctx, _ := newTestContext()
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("unattested blob"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	assert.Empty(t, reply.GetAttestationSignature())
+
+	_, err = clients.VerifySubmissionAttestation(reply)
+	assert.Error(t, err)
+	assert.Empty(t, reply.GetReceipt())
+	_, err = clients.VerifyDisperserReceipt(reply)
+	assert.Error(t, err)
+}
+
+func TestDisperseBlobReceipt(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithAttestationKey(key))
+
+	ctx, _ := newTestContext()
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+		Data: []byte("blob with receipt"),
+		SecurityParams: []*pb.SecurityParams{
+			{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80},
+			{QuorumId: 3, AdversaryThreshold: 50, QuorumThreshold: 80},
+		},
+	})
+	assert.NoError(t, err)
+
+	// the receipt is a struct with a dynamic field, so it starts with the offset of the struct
+	assert.Equal(t, byte(0x20), reply.GetReceipt()[31])
+	receipt, err := clients.VerifyDisperserReceipt(reply)
+	assert.NoError(t, err)
+
+	blobKey, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256Hash([]byte(metadata.MetadataHash)), eth_common.Hash(receipt.MetadataHash))
+	assert.Equal(t, metadata.BlobHash, hex.EncodeToString(receipt.BlobHash[:]))
+	assert.Equal(t, metadata.RequestMetadata.RequestedAt, receipt.RequestedAt)
+	assert.Equal(t, []uint8{0, 3}, receipt.QuorumIds)
+
+	// tampered receipt
+	tampered := proto.Clone(reply).(*pb.DisperseBlobReply)
+	tampered.Receipt[len(tampered.Receipt)-1] ^= 0xff
+	_, err = clients.VerifyDisperserReceipt(tampered)
+	assert.Error(t, err)
+
+	// signed by another key
+	otherKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	tampered = proto.Clone(reply).(*pb.DisperseBlobReply)
+	tampered.AttestationPubkey = crypto.CompressPubkey(&otherKey.PublicKey)
+	_, err = clients.VerifyDisperserReceipt(tampered)
+	assert.Error(t, err)
+}
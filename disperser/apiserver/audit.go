@@ -0,0 +1,54 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"google.golang.org/grpc/status"
+)
+
+// WithAuditLogger records the dispersals, status checks and retrievals of the requesters to the audit logger
+func WithAuditLogger(logger common.AuditLogger) ServerOption {
+	return func(s *DispersalServer) {
+		s.auditLogger = logger
+	}
+}
+
+// audit records the event of an operation which ended with err, it's a no-op if the server has no audit logger
+func (s *DispersalServer) audit(event common.AuditEvent, err error) {
+	if s.auditLogger == nil {
+		return
+	}
+	event.Timestamp = time.Now().UTC()
+	switch {
+	case err == nil:
+		event.Outcome = common.AuditOutcomeSuccess
+	case errors.Is(err, errSystemRateLimit) || errors.Is(err, errAccountRateLimit):
+		event.Outcome = common.AuditOutcomeRateLimited
+	default:
+		event.Outcome = common.AuditOutcomeError
+	}
+	if err != nil {
+		event.ErrorCode = status.Code(err).String()
+	}
+	s.auditLogger.Log(event)
+}
+
+// auditRequesterID is the requester of the operations which are not about an account: the account of the API key of
+// the caller, the common name of its mTLS certificate or its address
+func (s *DispersalServer) auditRequesterID(ctx context.Context) common.RequesterID {
+	if accountID := authenticatedAccountID(ctx); accountID != "" {
+		return accountID
+	}
+	if commonName := getPeerCertFields(ctx)["commonName"]; commonName != "" {
+		return commonName
+	}
+	rateConfig, _ := s.getRateConfig()
+	origin, err := common.GetClientAddress(ctx, rateConfig.ClientIPHeader, 2, true)
+	if err != nil {
+		return ""
+	}
+	return origin
+}
@@ -0,0 +1,71 @@
+package apiserver_test
+
+import (
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuditLogger struct {
+	events []common.AuditEvent
+}
+
+func (l *recordingAuditLogger) Log(event common.AuditEvent) {
+	l.events = append(l.events, event)
+}
+
+func TestAuditLog(t *testing.T) {
+	auditLogger := &recordingAuditLogger{}
+	logger := &mock.Logger{}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{AdmissionBackpressureThreshold: 0.5}, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithAuditLogger(auditLogger))
+	queue := &mockEncodingQueue{capacity: 10}
+	server.EncodingQueue = queue
+
+	ctx, _ := newTestContext()
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("audited blob"), SecurityParams: testSecurityParams})
+	require.NoError(t, err)
+	_, err = server.GetBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: reply.GetRequestId()})
+	require.NoError(t, err)
+
+	// rejected by the admission gate
+	queue.length = 5
+	ctx, _ = newTestContext()
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("rejected blob"), SecurityParams: testSecurityParams})
+	assert.Error(t, err)
+
+	_, err = server.GetBlobStatus(ctx, &pb.BlobStatusRequest{})
+	assert.Error(t, err)
+
+	require.Len(t, auditLogger.events, 4)
+	dispersed := auditLogger.events[0]
+	assert.Equal(t, common.AuditOperationDisperseBlob, dispersed.Operation)
+	assert.Equal(t, "127.0.0.1", dispersed.RequesterID)
+	assert.Equal(t, string(reply.GetRequestId()), dispersed.BlobKey)
+	assert.Equal(t, len("audited blob"), dispersed.BlobSize)
+	assert.Equal(t, common.AuditOutcomeSuccess, dispersed.Outcome)
+	assert.Empty(t, dispersed.ErrorCode)
+	assert.False(t, dispersed.Timestamp.IsZero())
+
+	status := auditLogger.events[1]
+	assert.Equal(t, common.AuditOperationGetBlobStatus, status.Operation)
+	assert.Equal(t, "127.0.0.1", status.RequesterID)
+	assert.Equal(t, string(reply.GetRequestId()), status.BlobKey)
+	assert.Equal(t, common.AuditOutcomeSuccess, status.Outcome)
+
+	rejected := auditLogger.events[2]
+	assert.Equal(t, "127.0.0.1", rejected.RequesterID)
+	assert.Empty(t, rejected.BlobKey)
+	assert.Equal(t, common.AuditOutcomeRateLimited, rejected.Outcome)
+	assert.NotEmpty(t, rejected.ErrorCode)
+
+	assert.Equal(t, common.AuditOutcomeError, auditLogger.events[3].Outcome)
+}
@@ -0,0 +1,113 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/gammazero/workerpool"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxBatchRequestBlobs is the maximum number of blobs of a DisperseBlobBatch request
+	maxBatchRequestBlobs = 100
+	// batchDispersalWorkers is the number of blobs of a DisperseBlobBatch request stored in parallel
+	batchDispersalWorkers = 8
+)
+
+// DisperseBlobBatch disperses several blobs the same way as DisperseBlob, in parallel.
+// The blobs fail independently, their errors are reported in their results. Only the total size of the blobs
+// exceeding the system throughput limit fails the whole request, before any blob is stored.
+func (s *DispersalServer) DisperseBlobBatch(ctx context.Context, req *pb.DisperseBlobBatchRequest) (*pb.DisperseBlobBatchReply, error) {
+	start := time.Now()
+	blobRequests := req.GetBlobs()
+	if len(blobRequests) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "the batch must contain at least one blob")
+	}
+	if len(blobRequests) > maxBatchRequestBlobs {
+		return nil, status.Errorf(codes.InvalidArgument, "the batch cannot contain more than %d blobs", maxBatchRequestBlobs)
+	}
+
+	blobs := make([]*core.Blob, len(blobRequests))
+	for i, blobRequest := range blobRequests {
+		blob, err := getBlobFromRequest(blobRequest)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "blob %d: %s", i, status.Convert(err).Message())
+		}
+		blobs[i] = blob
+	}
+	if err := s.checkBatchSystemRateLimits(ctx, blobs); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pb.DisperseBlobBatchResult, len(blobs))
+	latencies := make([]time.Duration, len(blobs))
+	pool := workerpool.New(batchDispersalWorkers)
+	for i, blob := range blobs {
+		i, blob := i, blob
+		pool.Submit(func() {
+			blobStart := time.Now()
+			reply, err := s.disperseBlob(ctx, blob, blobRequests[i].GetIdempotencyKey(), "DisperseBlobBatch", true)
+			latencies[i] = time.Since(blobStart)
+			if err != nil {
+				results[i] = &pb.DisperseBlobBatchResult{
+					Result: pb.BlobStatus_FAILED,
+					Error:  err.Error(),
+				}
+				return
+			}
+			results[i] = &pb.DisperseBlobBatchResult{
+				Result:    reply.GetResult(),
+				RequestId: reply.GetRequestId(),
+			}
+		})
+	}
+	pool.StopWait()
+
+	s.metrics.HandleBatchRequest("DisperseBlobBatch", time.Since(start), latencies)
+	return &pb.DisperseBlobBatchReply{Results: results}, nil
+}
+
+// checkBatchSystemRateLimits checks the total size of the blobs against the system throughput limit of each quorum,
+// so that a batch is either entirely within the limit or rejected. The blobs which are obviously invalid aren't accounted.
+func (s *DispersalServer) checkBatchSystemRateLimits(ctx context.Context, blobs []*core.Blob) error {
+	if s.ratelimiter == nil {
+		return nil
+	}
+
+	totalSize := 0
+	quorumSizes := make(map[core.QuorumID]uint)
+	for _, blob := range blobs {
+		blobSize := len(blob.Data)
+		if blobSize == 0 || blobSize > core.MaxBlobSize {
+			continue
+		}
+		totalSize += blobSize
+		for _, param := range blob.RequestHeader.SecurityParams {
+			quorumSizes[param.QuorumID] += uint(blobSize)
+		}
+	}
+
+	rateConfig, _ := s.getRateConfig()
+	for quorumID, size := range quorumSizes {
+		rates, ok := rateConfig.QuorumRateInfos[quorumID]
+		if !ok || rates.TotalUnauthThroughput == 0 {
+			continue
+		}
+		allowed, _, err := s.ratelimiter.AllowRequest(ctx, systemBytesRequesterID(quorumID), size, rates.TotalUnauthThroughput)
+		if err != nil {
+			s.metrics.HandleFailedRequest(totalSize, "DisperseBlobBatch")
+			return fmt.Errorf("failed to check rate limit: %w", err)
+		}
+		if !allowed {
+			s.metrics.HandleRateLimitRejectedRequest(rateLimitReasonSystemBytes, true, totalSize, "DisperseBlobBatch")
+			s.logger.Debug("[apiserver] blob batch rejected by rate limit", "quorumID", quorumID, "size", size)
+			return errSystemRateLimit
+		}
+	}
+	return nil
+}
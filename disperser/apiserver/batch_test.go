@@ -0,0 +1,111 @@
+package apiserver_test
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDisperseBlobBatch(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	reply, err := server.DisperseBlobBatch(ctx, &pb.DisperseBlobBatchRequest{
+		Blobs: []*pb.DisperseBlobRequest{
+			{Data: []byte("first blob"), SecurityParams: testSecurityParams},
+			{Data: []byte{}, SecurityParams: testSecurityParams},
+			{Data: make([]byte, core.MaxBlobSize+1), SecurityParams: testSecurityParams},
+			{Data: []byte("last blob"), SecurityParams: testSecurityParams},
+		},
+	})
+	assert.NoError(t, err)
+	results := reply.GetResults()
+	assert.Len(t, results, 4)
+
+	for i, data := range map[int]string{0: "first blob", 3: "last blob"} {
+		assert.Equal(t, pb.BlobStatus_PROCESSING, results[i].GetResult())
+		assert.Empty(t, results[i].GetError())
+		blobKey, err := disperser.ParseBlobKey(string(results[i].GetRequestId()))
+		assert.NoError(t, err)
+		metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+		assert.NoError(t, err)
+		content, err := blobStore.GetBlobContent(ctx, metadata)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(data), content)
+	}
+	for _, i := range []int{1, 2} {
+		assert.Equal(t, pb.BlobStatus_FAILED, results[i].GetResult())
+		assert.NotEmpty(t, results[i].GetError())
+		assert.Empty(t, results[i].GetRequestId())
+	}
+
+	_, err = server.DisperseBlobBatch(ctx, &pb.DisperseBlobBatchRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.DisperseBlobBatch(ctx, &pb.DisperseBlobBatchRequest{Blobs: make([]*pb.DisperseBlobRequest, 101)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDisperseBlobBatchSystemRateLimit(t *testing.T) {
+	const generous = 1_000_000_000
+
+	logger := &mock.Logger{}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+	assert.NoError(t, err)
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{10 * time.Second},
+		Multipliers: []float32{1},
+	}, bucketStore, nil, nil, nil, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	// each blob of 100 bytes consumes 1s of the 10s system bucket
+	rateConfig := apiserver.RateConfig{QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+		0: {TotalUnauthThroughput: 100, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: generous, PerUserUnauthBlobRate: generous},
+	}}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, metrics, ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+
+	ctx, _ := newTestContext()
+	batch := func(numBlobs int) *pb.DisperseBlobBatchRequest {
+		req := &pb.DisperseBlobBatchRequest{}
+		for i := 0; i < numBlobs; i++ {
+			req.Blobs = append(req.Blobs, &pb.DisperseBlobRequest{
+				Data:           []byte{byte(i), 99: 1},
+				SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+			})
+		}
+		return req
+	}
+
+	// the whole batch exceeds the system limit, nothing is stored
+	_, err = server.DisperseBlobBatch(ctx, batch(11))
+	assert.ErrorContains(t, err, "system limit")
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RateLimitRejections.WithLabelValues("system_bytes")))
+	metadatas, err := blobStore.GetBlobMetadataByStatus(ctx, disperser.Processing)
+	assert.NoError(t, err)
+	assert.Len(t, metadatas, 0)
+
+	// the system bytes of the blobs are only counted once, by the batch
+	for _, numBlobs := range []int{4, 5} {
+		reply, err := server.DisperseBlobBatch(ctx, batch(numBlobs))
+		assert.NoError(t, err)
+		for _, result := range reply.GetResults() {
+			assert.Equal(t, pb.BlobStatus_PROCESSING, result.GetResult(), result.GetError())
+		}
+	}
+	metadatas, err = blobStore.GetBlobMetadataByStatus(ctx, disperser.Processing)
+	assert.NoError(t, err)
+	assert.Len(t, metadatas, 9)
+}
@@ -0,0 +1,65 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultBatchPageSize = 100
+	maxBatchPageSize     = 1000
+)
+
+// GetAllBatches returns a page of the confirmed batches ordered by confirmation block. The next page starts after the
+// last batch of the page, which is passed as last_batch_header_hash.
+func (s *DispersalServer) GetAllBatches(ctx context.Context, req *pb.GetAllBatchesRequest) (*pb.GetAllBatchesReply, error) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("GetAllBatches", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultBatchPageSize
+	}
+	if pageSize > maxBatchPageSize {
+		pageSize = maxBatchPageSize
+	}
+	var lastBatchHeaderHash *[32]byte
+	if last := req.GetLastBatchHeaderHash(); len(last) > 0 {
+		if len(last) != 32 {
+			return nil, status.Errorf(codes.InvalidArgument, "last_batch_header_hash must be 32 bytes, got %d", len(last))
+		}
+		lastBatchHeaderHash = new([32]byte)
+		copy(lastBatchHeaderHash[:], last)
+	}
+
+	batches, err := s.blobStore.GetAllBatches(ctx, pageSize, lastBatchHeaderHash)
+	if errors.Is(err, disperser.ErrBatchNotFound) {
+		return nil, status.Error(codes.NotFound, "last batch not found")
+	}
+	if errors.Is(err, disperser.ErrBatchListingUnavailable) {
+		return nil, status.Error(codes.FailedPrecondition, "batch listing is unavailable")
+	}
+	if err != nil {
+		s.logger.Error("[apiserver] failed to list batches", "err", err)
+		return nil, status.Error(codes.Internal, "failed to list batches")
+	}
+
+	reply := &pb.GetAllBatchesReply{Batches: make([]*pb.BatchSummary, len(batches))}
+	for i, batch := range batches {
+		reply.Batches[i] = &pb.BatchSummary{
+			BatchHeaderHash:         batch.BatchHeaderHash[:],
+			ConfirmationBlockNumber: batch.ConfirmationBlockNumber,
+			BlobCount:               batch.BlobCount,
+			TotalBytes:              batch.TotalBytes,
+		}
+	}
+	return reply, nil
+}
@@ -0,0 +1,60 @@
+package apiserver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetAllBatches(t *testing.T) {
+	ctx := context.Background()
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+
+	// three batches confirmed at decreasing blocks, of one, two and three blobs
+	for batch := 0; batch < 3; batch++ {
+		for blobIndex := 0; blobIndex <= batch; blobIndex++ {
+			data := make([]byte, 100*(batch+1))
+			data[0], data[1] = byte(batch), byte(blobIndex)
+			key, err := blobStore.StoreBlob(ctx, &core.Blob{
+				RequestHeader: core.BlobRequestHeader{SecurityParams: []*core.SecurityParam{{QuorumID: 0}}},
+				Data:          data,
+			}, uint64(time.Now().UnixNano()))
+			require.NoError(t, err)
+			metadata, err := blobStore.GetBlobMetadata(ctx, key)
+			require.NoError(t, err)
+			_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{
+				BatchHeaderHash:         [32]byte{byte(batch + 1)},
+				BlobIndex:               uint32(blobIndex),
+				ConfirmationBlockNumber: uint32(30 - batch),
+			})
+			require.NoError(t, err)
+		}
+	}
+	// the blobs which aren't confirmed are not in any batch
+	_, err := blobStore.StoreBlob(ctx, &core.Blob{Data: []byte("processing")}, uint64(time.Now().UnixNano()))
+	require.NoError(t, err)
+
+	reply, err := server.GetAllBatches(ctx, &pb.GetAllBatchesRequest{PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, reply.GetBatches(), 2)
+	assert.Equal(t, &pb.BatchSummary{BatchHeaderHash: []byte{3, 31: 0}, ConfirmationBlockNumber: 28, BlobCount: 3, TotalBytes: 900}, reply.GetBatches()[0])
+	assert.Equal(t, &pb.BatchSummary{BatchHeaderHash: []byte{2, 31: 0}, ConfirmationBlockNumber: 29, BlobCount: 2, TotalBytes: 400}, reply.GetBatches()[1])
+
+	reply, err = server.GetAllBatches(ctx, &pb.GetAllBatchesRequest{PageSize: 2, LastBatchHeaderHash: reply.GetBatches()[1].GetBatchHeaderHash()})
+	require.NoError(t, err)
+	require.Len(t, reply.GetBatches(), 1)
+	assert.Equal(t, &pb.BatchSummary{BatchHeaderHash: []byte{1, 31: 0}, ConfirmationBlockNumber: 30, BlobCount: 1, TotalBytes: 100}, reply.GetBatches()[0])
+
+	_, err = server.GetAllBatches(ctx, &pb.GetAllBatchesRequest{LastBatchHeaderHash: []byte{1, 2, 3}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.GetAllBatches(ctx, &pb.GetAllBatchesRequest{LastBatchHeaderHash: make([]byte, 32)})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,112 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// opaRequestTimeout is the timeout of the requests to the Open Policy Agent
+const opaRequestTimeout = 2 * time.Second
+
+// BlobSizeValidator checks whether the size of a new blob is acceptable, origin is the address of the client
+type BlobSizeValidator interface {
+	ValidateSize(ctx context.Context, blob *core.Blob, origin string) error
+}
+
+// WithBlobSizeValidator replaces the default size checks of the blobs, which only enforce core.MaxBlobSize
+func WithBlobSizeValidator(v BlobSizeValidator) ServerOption {
+	return func(s *DispersalServer) {
+		s.blobSizeValidator = v
+	}
+}
+
+// DefaultBlobSizeValidator accepts the blobs whose size is in [1, core.MaxBlobSize]
+type DefaultBlobSizeValidator struct{}
+
+func (DefaultBlobSizeValidator) ValidateSize(ctx context.Context, blob *core.Blob, origin string) error {
+	blobSize := len(blob.Data)
+	if blobSize > core.MaxBlobSize {
+		return fmt.Errorf("blob size cannot exceed %v KiB", core.MaxBlobSize/1024)
+	}
+	if blobSize == 0 {
+		return fmt.Errorf("blob size must be greater than 0")
+	}
+	return nil
+}
+
+// OPABlobSizeValidator asks an Open Policy Agent whether to accept a blob, e.g. to apply per account or per quorum limits.
+// The blobs must pass the default checks first. The policy is queried with the data API, e.g. at
+// http://opa:8181/v1/data/disperser/blob_size/allow, and must evaluate to true for the blob to be accepted.
+type OPABlobSizeValidator struct {
+	url        string
+	httpClient *http.Client
+}
+
+// opaBlobSizeInput is the input of the blob size policy
+type opaBlobSizeInput struct {
+	BlobSize  int     `json:"blob_size"`
+	AccountID string  `json:"account_id"`
+	Origin    string  `json:"origin"`
+	QuorumIDs []uint8 `json:"quorum_ids"`
+}
+
+func NewOPABlobSizeValidator(url string) *OPABlobSizeValidator {
+	return &OPABlobSizeValidator{
+		url:        url,
+		httpClient: &http.Client{Timeout: opaRequestTimeout},
+	}
+}
+
+func (v *OPABlobSizeValidator) ValidateSize(ctx context.Context, blob *core.Blob, origin string) error {
+	if err := (DefaultBlobSizeValidator{}).ValidateSize(ctx, blob, origin); err != nil {
+		return err
+	}
+
+	input := opaBlobSizeInput{
+		BlobSize:  len(blob.Data),
+		AccountID: blob.RequestHeader.AccountID,
+		Origin:    origin,
+		QuorumIDs: make([]uint8, len(blob.RequestHeader.SecurityParams)),
+	}
+	for i, param := range blob.RequestHeader.SecurityParams {
+		input.QuorumIDs[i] = param.QuorumID
+	}
+	body, err := json.Marshal(map[string]any{"input": input})
+	if err != nil {
+		return fmt.Errorf("failed to encode blob size policy input: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create blob size policy request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to query blob size policy: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return status.Errorf(codes.Unavailable, "failed to query blob size policy: status %d", resp.StatusCode)
+	}
+
+	// the result is missing if the policy is undefined for the input, which denies the blob
+	var decision struct {
+		Result *bool `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return status.Errorf(codes.Unavailable, "failed to decode blob size policy decision: %v", err)
+	}
+	if decision.Result == nil || !*decision.Result {
+		return status.Errorf(codes.InvalidArgument, "blob size of %d bytes is not allowed by policy", len(blob.Data))
+	}
+	return nil
+}
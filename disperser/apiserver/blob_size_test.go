@@ -0,0 +1,159 @@
+package apiserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newSizeTestBlob(size int, accountID string) *core.Blob {
+	return &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			AccountID:      accountID,
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}, {QuorumID: 2}},
+		},
+		Data: make([]byte, size),
+	}
+}
+
+func TestDefaultBlobSizeValidator(t *testing.T) {
+	validator := apiserver.DefaultBlobSizeValidator{}
+	ctx := context.Background()
+
+	assert.NoError(t, validator.ValidateSize(ctx, newSizeTestBlob(1, ""), "127.0.0.1"))
+	assert.NoError(t, validator.ValidateSize(ctx, newSizeTestBlob(core.MaxBlobSize, ""), "127.0.0.1"))
+	assert.ErrorContains(t, validator.ValidateSize(ctx, newSizeTestBlob(0, ""), "127.0.0.1"), "greater than 0")
+	assert.ErrorContains(t, validator.ValidateSize(ctx, newSizeTestBlob(core.MaxBlobSize+1, ""), "127.0.0.1"), "cannot exceed")
+}
+
+func TestOPABlobSizeValidator(t *testing.T) {
+	// the policy allows up to 1000 bytes, and up to 10000 bytes for the "big" account
+	policy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Input struct {
+				BlobSize  int     `json:"blob_size"`
+				AccountID string  `json:"account_id"`
+				Origin    string  `json:"origin"`
+				QuorumIDs []uint8 `json:"quorum_ids"`
+			} `json:"input"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "10.0.0.1", request.Input.Origin)
+		assert.Equal(t, []uint8{0, 2}, request.Input.QuorumIDs)
+
+		switch request.Input.AccountID {
+		case "undefined":
+			_, _ = w.Write([]byte(`{}`))
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			limit := 1000
+			if request.Input.AccountID == "big" {
+				limit = 10000
+			}
+			_ = json.NewEncoder(w).Encode(map[string]bool{"result": request.Input.BlobSize <= limit})
+		}
+	}))
+	defer policy.Close()
+
+	validator := apiserver.NewOPABlobSizeValidator(policy.URL + "/v1/data/disperser/blob_size/allow")
+	ctx := context.Background()
+
+	assert.NoError(t, validator.ValidateSize(ctx, newSizeTestBlob(1000, "small"), "10.0.0.1"))
+	assert.NoError(t, validator.ValidateSize(ctx, newSizeTestBlob(5000, "big"), "10.0.0.1"))
+
+	err := validator.ValidateSize(ctx, newSizeTestBlob(5000, "small"), "10.0.0.1")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// an undefined decision denies the blob
+	err = validator.ValidateSize(ctx, newSizeTestBlob(10, "undefined"), "10.0.0.1")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = validator.ValidateSize(ctx, newSizeTestBlob(10, "broken"), "10.0.0.1")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	// the protocol limit is enforced without querying the policy
+	err = validator.ValidateSize(ctx, newSizeTestBlob(core.MaxBlobSize+1, "big"), "10.0.0.1")
+	assert.ErrorContains(t, err, "cannot exceed")
+}
+
+type rejectingSizeValidator struct {
+	origins []string
+}
+
+func (v *rejectingSizeValidator) ValidateSize(ctx context.Context, blob *core.Blob, origin string) error {
+	v.origins = append(v.origins, origin)
+	if len(blob.Data) > 10 {
+		return errors.New("too big for this test")
+	}
+	return nil
+}
+
+func TestDisperseBlobWithBlobSizeValidator(t *testing.T) {
+	validator := &rejectingSizeValidator{}
+	logger := &mock.Logger{}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil, apiserver.WithBlobSizeValidator(validator))
+	ctx, _ := newTestContext()
+
+	securityParams := []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}}
+	_, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("small"), SecurityParams: securityParams})
+	assert.NoError(t, err)
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("larger than 10 bytes"), SecurityParams: securityParams})
+	assert.ErrorContains(t, err, "too big for this test")
+	assert.Equal(t, []string{"127.0.0.1", "127.0.0.1"}, validator.origins)
+}
+
+func TestDisperseBlobMinSize(t *testing.T) {
+	logger := &mock.Logger{}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{MinBlobSize: 32}, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+	ctx, _ := newTestContext()
+
+	_, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: make([]byte, 31), SecurityParams: testSecurityParams})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "at least 32 bytes")
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: make([]byte, 32), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+}
+
+func TestDisperseBlobWithoutSecurityParams(t *testing.T) {
+	server, _ := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	_, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("no quorum")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.DisperseBlobBatch(ctx, &pb.DisperseBlobBatchRequest{Blobs: []*pb.DisperseBlobRequest{
+		{Data: []byte("with quorum"), SecurityParams: testSecurityParams},
+		{Data: []byte("no quorum")},
+	}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "blob 1")
+}
+
+func TestDisperseBlobInvalidSecurityParams(t *testing.T) {
+	server, _ := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	for _, params := range [][]*pb.SecurityParams{
+		{{QuorumId: 0, AdversaryThreshold: 100, QuorumThreshold: 50}},
+		{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}, {QuorumId: 0, AdversaryThreshold: 33, QuorumThreshold: 67}},
+		// would be truncated to 50 and 80
+		{{QuorumId: 0, AdversaryThreshold: 256 + 50, QuorumThreshold: 256 + 80}},
+	} {
+		_, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("invalid params"), SecurityParams: params})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), params)
+	}
+}
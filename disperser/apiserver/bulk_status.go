@@ -0,0 +1,59 @@
+package apiserver
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/gammazero/workerpool"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxBulkStatusRequestIDs is the maximum number of request IDs of a BulkGetBlobStatus request
+	maxBulkStatusRequestIDs = 100
+	// bulkStatusWorkers is the number of blob statuses of a BulkGetBlobStatus request looked up in parallel
+	bulkStatusWorkers = 8
+)
+
+// BulkGetBlobStatus looks up the status of several blobs the same way as GetBlobStatus, in parallel.
+// The blobs which can't be looked up have the UNKNOWN status, they don't fail the whole request.
+func (s *DispersalServer) BulkGetBlobStatus(ctx context.Context, req *pb.BulkBlobStatusRequest) (*pb.BulkBlobStatusReply, error) {
+	start := time.Now()
+	requestIDs := req.GetRequestIds()
+	if len(requestIDs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "request_ids must not be empty")
+	}
+	if len(requestIDs) > maxBulkStatusRequestIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot query more than %d request IDs", maxBulkStatusRequestIDs)
+	}
+	s.metrics.BulkGetBlobStatusSize.Observe(float64(len(requestIDs)))
+
+	statuses := make([]*pb.BlobStatusReply, len(requestIDs))
+	pool := workerpool.New(bulkStatusWorkers)
+	for i, requestID := range requestIDs {
+		i, requestID := i, requestID
+		pool.Submit(func() {
+			reply, err := s.getBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: requestID})
+			s.audit(common.AuditEvent{
+				Operation:   common.AuditOperationGetBlobStatus,
+				RequesterID: s.auditRequesterID(ctx),
+				BlobKey:     string(requestID),
+			}, err)
+			if err != nil {
+				s.logger.Debug("[apiserver] failed to get blob status", "requestID", string(requestID), "err", err)
+				reply = &pb.BlobStatusReply{
+					Status: pb.BlobStatus_UNKNOWN,
+					Info:   &pb.BlobInfo{},
+				}
+			}
+			statuses[i] = reply
+		})
+	}
+	pool.StopWait()
+
+	s.metrics.ObserveLatency("BulkGetBlobStatus", float64(time.Since(start).Microseconds())/1000)
+	return &pb.BulkBlobStatusReply{Statuses: statuses}, nil
+}
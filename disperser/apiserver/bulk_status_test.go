@@ -0,0 +1,52 @@
+package apiserver_test
+
+import (
+	"fmt"
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBulkGetBlobStatus(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{}, nil)
+	ctx, _ := newTestContext()
+
+	requestIDs := make([][]byte, 0)
+	for i := 0; i < 3; i++ {
+		reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte(fmt.Sprintf("bulk status blob %d", i)), SecurityParams: testSecurityParams})
+		require.NoError(t, err)
+		requestIDs = append(requestIDs, reply.GetRequestId())
+	}
+	blobKey, err := disperser.ParseBlobKey(string(requestIDs[1]))
+	require.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	require.NoError(t, err)
+	require.NoError(t, blobStore.MarkBlobFailed(ctx, metadata.GetBlobKey()))
+
+	// the unknown and malformed request IDs don't fail the other blobs
+	unknownKey := disperser.BlobKey{BlobHash: blobKey.BlobHash, MetadataHash: "unknown"}
+	requestIDs = append(requestIDs, []byte(unknownKey.String()), []byte("malformed"))
+	reply, err := server.BulkGetBlobStatus(ctx, &pb.BulkBlobStatusRequest{RequestIds: requestIDs})
+	require.NoError(t, err)
+	statuses := make([]pb.BlobStatus, len(reply.GetStatuses()))
+	for i, blobStatus := range reply.GetStatuses() {
+		statuses[i] = blobStatus.GetStatus()
+	}
+	assert.Equal(t, []pb.BlobStatus{pb.BlobStatus_PROCESSING, pb.BlobStatus_FAILED, pb.BlobStatus_PROCESSING, pb.BlobStatus_UNKNOWN, pb.BlobStatus_UNKNOWN}, statuses)
+
+	_, err = server.BulkGetBlobStatus(ctx, &pb.BulkBlobStatusRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.BulkGetBlobStatus(ctx, &pb.BulkBlobStatusRequest{RequestIds: make([][]byte, 101)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
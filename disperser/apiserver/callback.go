@@ -0,0 +1,238 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// callbackRequestTimeout is the timeout of a single POST to a callback URL
+	callbackRequestTimeout = 10 * time.Second
+	// DefaultCallbackInitialBackoff is the delay before the first retry of a failed callback, doubled at each retry
+	DefaultCallbackInitialBackoff = time.Second
+)
+
+// WithBlobCallbacks serves RegisterBlobCallback, the final status of the blobs is POSTed by the notifier
+func WithBlobCallbacks(notifier *BlobCallbackNotifier) ServerOption {
+	return func(s *DispersalServer) {
+		s.callbacks = notifier
+	}
+}
+
+// BlobCallbackNotifier keeps the callback URLs registered for the blobs which are not finalized or failed yet,
+// and POSTs the final status of the blobs to them. The callbacks are kept in memory, so they are lost on restart.
+type BlobCallbackNotifier struct {
+	httpClient     *http.Client
+	allowlist      []string
+	maxRetries     int
+	initialBackoff time.Duration
+
+	mu sync.Mutex
+	// pending maps the request IDs of the blobs to the callback URLs registered for them
+	pending map[string][]string
+}
+
+// NewBlobCallbackNotifier creates a BlobCallbackNotifier accepting the callback URLs starting with one of the prefixes
+// of the allowlist. A failed callback is retried up to maxRetries times, after initialBackoff then twice as long at
+// each retry.
+func NewBlobCallbackNotifier(allowlist []string, maxRetries int, initialBackoff time.Duration) *BlobCallbackNotifier {
+	return &BlobCallbackNotifier{
+		httpClient:     &http.Client{Timeout: callbackRequestTimeout},
+		allowlist:      allowlist,
+		maxRetries:     maxRetries,
+		initialBackoff: initialBackoff,
+		pending:        make(map[string][]string),
+	}
+}
+
+// ValidateURL checks the callback URL is an http or https URL starting with an allowed prefix.
+// The prefixes should end with a "/", otherwise a prefix also allows the hosts it is the beginning of.
+func (n *BlobCallbackNotifier) ValidateURL(callbackURL string) error {
+	parsed, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid callback URL: %w", err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("callback URL must be an http or https URL")
+	}
+	for _, prefix := range n.allowlist {
+		if prefix != "" && strings.HasPrefix(callbackURL, prefix) {
+			return nil
+		}
+	}
+	return errors.New("callback URL is not allowed")
+}
+
+// Notify POSTs the payload to the callback URL, the POST is retried when it fails or gets a non-2xx response
+func (n *BlobCallbackNotifier) Notify(ctx context.Context, callbackURL string, payload []byte) error {
+	backoff := n.initialBackoff
+	for attempt := 0; ; attempt++ {
+		err := n.post(ctx, callbackURL, payload)
+		if err == nil {
+			return nil
+		}
+		if attempt >= n.maxRetries {
+			return fmt.Errorf("callback failed after %d attempts: %w", attempt+1, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (n *BlobCallbackNotifier) post(ctx context.Context, callbackURL string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body, so that the connection is reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// add registers the callback URL of the blob, it returns whether the blob had no pending callback,
+// i.e. whether its status has to be awaited
+func (n *BlobCallbackNotifier) add(requestID string, callbackURL string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	urls, ok := n.pending[requestID]
+	n.pending[requestID] = append(urls, callbackURL)
+	return !ok
+}
+
+// take removes and returns the callback URLs of the blob
+func (n *BlobCallbackNotifier) take(requestID string) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	urls := n.pending[requestID]
+	delete(n.pending, requestID)
+	return urls
+}
+
+// RegisterBlobCallback registers a URL the GetBlobStatus reply of the blob is POSTed to, JSON encoded, once the blob is
+// finalized or failed. The callback is called right away if the blob is already finalized or failed.
+func (s *DispersalServer) RegisterBlobCallback(ctx context.Context, req *pb.RegisterBlobCallbackRequest) (*pb.RegisterBlobCallbackReply, error) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("RegisterBlobCallback", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+
+	if s.callbacks == nil {
+		return nil, status.Error(codes.Unimplemented, "blob callbacks are disabled")
+	}
+	requestID := req.GetRequestId()
+	if len(requestID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "request_id must not be empty")
+	}
+	if err := s.callbacks.ValidateURL(req.GetCallbackUrl()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// only the callbacks of known blobs are registered
+	if _, err := s.getBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: requestID}); err != nil {
+		return nil, err
+	}
+
+	if s.callbacks.add(string(requestID), req.GetCallbackUrl()) {
+		go s.awaitBlobCallbacks(requestID)
+	}
+	s.logger.Info("[apiserver] registered a blob callback", "requestID", string(requestID), "callbackURL", req.GetCallbackUrl())
+	return &pb.RegisterBlobCallbackReply{}, nil
+}
+
+// awaitBlobCallbacks waits for the blob to be finalized or failed, then notifies the callbacks registered for it.
+// The callbacks are dropped if the blob is removed or the server shuts down.
+func (s *DispersalServer) awaitBlobCallbacks(requestID []byte) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopWatches:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var updated <-chan struct{}
+	if s.statusHub != nil {
+		if key, err := disperser.ParseBlobKey(string(requestID)); err == nil {
+			var unsubscribe func()
+			updated, unsubscribe = s.statusHub.Subscribe(key)
+			defer unsubscribe()
+		}
+	}
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		reply, err := s.getBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: requestID})
+		if errors.Is(err, disperser.ErrBlobNotFound) {
+			s.logger.Warn("[apiserver] dropping the callbacks of a removed blob", "requestID", string(requestID))
+			s.callbacks.take(string(requestID))
+			return
+		}
+		if err != nil {
+			s.logger.Warn("[apiserver] failed to get the status of a blob with callbacks", "requestID", string(requestID), "err", err)
+		} else if isFinalStatus(reply.GetStatus()) {
+			s.notifyBlobCallbacks(ctx, requestID, reply)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			s.callbacks.take(string(requestID))
+			return
+		case <-updated:
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyBlobCallbacks POSTs the final status reply of the blob to its callbacks, concurrently
+func (s *DispersalServer) notifyBlobCallbacks(ctx context.Context, requestID []byte, reply *pb.BlobStatusReply) {
+	payload, err := httpGatewayMarshalOptions.Marshal(reply)
+	if err != nil {
+		s.logger.Error("[apiserver] failed to encode the blob status of the callbacks", "requestID", string(requestID), "err", err)
+		s.callbacks.take(string(requestID))
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, callbackURL := range s.callbacks.take(string(requestID)) {
+		wg.Add(1)
+		go func(callbackURL string) {
+			defer wg.Done()
+			if err := s.callbacks.Notify(ctx, callbackURL, payload); err != nil {
+				s.logger.Error("[apiserver] failed to notify blob callback", "requestID", string(requestID), "callbackURL", callbackURL, "err", err)
+				return
+			}
+			s.logger.Info("[apiserver] notified blob callback", "requestID", string(requestID), "callbackURL", callbackURL, "status", reply.GetStatus())
+		}(callbackURL)
+	}
+	wg.Wait()
+}
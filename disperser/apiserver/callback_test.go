@@ -0,0 +1,96 @@
+package apiserver_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestRegisterBlobCallback(t *testing.T) {
+	// the callback fails once, then succeeds when it is retried
+	var attempts atomic.Int32
+	bodies := make(chan []byte, 1)
+	callbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer callbackServer.Close()
+
+	logger := &mock.Logger{}
+	hub := disperser.NewBlobStatusHub()
+	blobStore := disperser.NewNotifyingBlobStore(memorydb.NewBlobStore(1024*1024, logger), hub)
+	notifier := apiserver.NewBlobCallbackNotifier([]string{callbackServer.URL + "/hooks/"}, 3, 10*time.Millisecond)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithBlobStatusHub(hub), apiserver.WithBlobCallbacks(notifier))
+	defer server.Shutdown()
+
+	ctx, _ := newTestContext()
+	disperseReply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("blob with a callback"), SecurityParams: testSecurityParams})
+	require.NoError(t, err)
+	requestID := disperseReply.GetRequestId()
+
+	for _, callbackURL := range []string{callbackServer.URL + "/other", "ftp://hooks.example.com/hooks/", "not a url"} {
+		_, err = server.RegisterBlobCallback(ctx, &pb.RegisterBlobCallbackRequest{RequestId: requestID, CallbackUrl: callbackURL})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), callbackURL)
+	}
+	_, err = server.RegisterBlobCallback(ctx, &pb.RegisterBlobCallbackRequest{CallbackUrl: callbackServer.URL + "/hooks/blob"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.RegisterBlobCallback(ctx, &pb.RegisterBlobCallbackRequest{RequestId: requestID, CallbackUrl: callbackServer.URL + "/hooks/blob"})
+	require.NoError(t, err)
+
+	// the callback is called once the blob fails
+	key, err := disperser.ParseBlobKey(string(requestID))
+	require.NoError(t, err)
+	require.NoError(t, blobStore.MarkBlobFailed(ctx, key))
+	select {
+	case body := <-bodies:
+		var reply pb.BlobStatusReply
+		require.NoError(t, protojson.Unmarshal(body, &reply))
+		assert.Equal(t, pb.BlobStatus_FAILED, reply.GetStatus())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the callback was not called")
+	}
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestRegisterBlobCallbackDisabled(t *testing.T) {
+	server, _, _ := newWatchTestServer(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+	_, err := server.RegisterBlobCallback(ctx, &pb.RegisterBlobCallbackRequest{RequestId: []byte("request id"), CallbackUrl: "https://hooks.example.com/"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestBlobCallbackNotifierRetries(t *testing.T) {
+	var attempts atomic.Int32
+	callbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer callbackServer.Close()
+
+	notifier := apiserver.NewBlobCallbackNotifier([]string{callbackServer.URL}, 2, time.Millisecond)
+	err := notifier.Notify(context.Background(), callbackServer.URL, []byte("{}"))
+	assert.ErrorContains(t, err, "after 3 attempts")
+	assert.Equal(t, int32(3), attempts.Load())
+}
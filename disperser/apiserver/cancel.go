@@ -0,0 +1,90 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CancelBlob marks a processing blob as failed, so that it isn't dispersed, and removes it if DeleteOnCancel is set.
+// Cancelling a blob which already failed is a no-op. The blobs already confirmed or finalized can't be cancelled.
+// When the callers are authenticated by API key, they can only cancel the blobs of their account.
+func (s *DispersalServer) CancelBlob(ctx context.Context, req *pb.CancelBlobRequest) (*pb.CancelBlobReply, error) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("CancelBlob", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+
+	requestID := req.GetRequestId()
+	if len(requestID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "request_id must not be empty")
+	}
+	s.logger.Info("[apiserver] received a blob cancellation request", "requestID", string(requestID))
+	blobKey, err := disperser.ParseBlobKey(string(requestID))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request_id: %v", err)
+	}
+
+	metadata, err := s.blobStore.GetBlobMetadata(ctx, blobKey)
+	if errors.Is(err, disperser.ErrBlobNotFound) || (err == nil && metadata == nil) {
+		if s.metadataHashAsBlobKey && s.KVNode != nil {
+			// the metadata of confirmed blobs is moved to the kv node
+			if metadataInKV, _ := s.getMetadataFromKv(ctx, requestID); metadataInKV != nil {
+				if err := s.authorizeBlobAccount(ctx, metadataInKV); err != nil {
+					return nil, err
+				}
+				return nil, status.Error(codes.FailedPrecondition, "blob is already confirmed")
+			}
+		}
+		return nil, status.Error(codes.NotFound, "blob not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeBlobAccount(ctx, metadata); err != nil {
+		return nil, err
+	}
+
+	previousStatus, cancelledStatus := metadata.BlobStatus, metadata.BlobStatus
+	switch previousStatus {
+	case disperser.Confirmed, disperser.Finalized:
+		return nil, status.Errorf(codes.FailedPrecondition, "blob is already %s", previousStatus)
+	case disperser.Processing:
+		cancelledStatus = disperser.Failed
+		// the blob may be confirmed by the batcher in the meantime, it is only failed if it is still processing
+		err := s.blobStore.TransitionBlobStatus(ctx, blobKey, disperser.Processing, disperser.Failed, "cancelled")
+		if errors.Is(err, disperser.ErrUnexpectedBlobStatus) {
+			return nil, status.Error(codes.FailedPrecondition, "blob is no longer processing")
+		}
+		if err != nil {
+			s.logger.Error("[apiserver] failed to cancel blob", "blobKey", blobKey.String(), "err", err)
+			return nil, status.Error(codes.Internal, "failed to cancel blob")
+		}
+	}
+
+	if s.config.DeleteOnCancel {
+		if err := s.blobStore.RemoveBlob(ctx, metadata); err != nil {
+			s.logger.Error("[apiserver] failed to remove cancelled blob", "blobKey", blobKey.String(), "err", err)
+			return nil, status.Error(codes.Internal, "failed to remove cancelled blob")
+		}
+	}
+	s.logger.Info("[apiserver] blob cancelled", "blobKey", blobKey.String(), "previousStatus", previousStatus)
+	return &pb.CancelBlobReply{Status: getResponseStatus(cancelledStatus)}, nil
+}
+
+// authorizeBlobAccount checks the caller authenticated by API key is the account which dispersed the blob, the callers
+// aren't checked if the APIs are open
+func (s *DispersalServer) authorizeBlobAccount(ctx context.Context, metadata *disperser.BlobMetadata) error {
+	if s.apiKeyAuth == nil {
+		return nil
+	}
+	if accountID := authenticatedAccountID(ctx); accountID == "" || accountID != metadata.RequestMetadata.AccountID {
+		return status.Error(codes.PermissionDenied, "blob was dispersed by another account")
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package apiserver_test
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCancelBlob(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	disperse := func(data string) []byte {
+		reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte(data), SecurityParams: testSecurityParams})
+		assert.NoError(t, err)
+		return reply.GetRequestId()
+	}
+
+	// processing blobs are marked as failed
+	requestID := disperse("cancelled blob")
+	reply, err := server.CancelBlob(ctx, &pb.CancelBlobRequest{RequestId: requestID})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_FAILED, reply.GetStatus())
+	statusReply, err := server.GetBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: requestID})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_FAILED, statusReply.GetStatus())
+
+	// cancelling again is a no-op
+	reply, err = server.CancelBlob(ctx, &pb.CancelBlobRequest{RequestId: requestID})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_FAILED, reply.GetStatus())
+
+	// confirmed blobs can't be cancelled
+	requestID = disperse("confirmed blob")
+	blobKey, err := disperser.ParseBlobKey(string(requestID))
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{BatchHeaderHash: [32]byte{1}})
+	assert.NoError(t, err)
+	_, err = server.CancelBlob(ctx, &pb.CancelBlobRequest{RequestId: requestID})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	metadata, err = blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, metadata.BlobStatus)
+
+	// unknown blobs
+	_, err = server.CancelBlob(ctx, &pb.CancelBlobRequest{RequestId: []byte("0000-0000")})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = server.CancelBlob(ctx, &pb.CancelBlobRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCancelBlobDeleteOnCancel(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{DeleteOnCancel: true})
+	ctx, _ := newTestContext()
+
+	disperseReply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("deleted blob"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	reply, err := server.CancelBlob(ctx, &pb.CancelBlobRequest{RequestId: disperseReply.GetRequestId()})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_FAILED, reply.GetStatus())
+
+	blobKey, err := disperser.ParseBlobKey(string(disperseReply.GetRequestId()))
+	assert.NoError(t, err)
+	_, err = blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+}
+
+// staleBlobStore returns the metadata of the blobs as they were before the last update, like a read racing with it
+type staleBlobStore struct {
+	disperser.BlobStore
+	stale map[disperser.BlobKey]*disperser.BlobMetadata
+}
+
+func (s *staleBlobStore) GetBlobMetadata(ctx context.Context, blobKey disperser.BlobKey) (*disperser.BlobMetadata, error) {
+	if metadata, ok := s.stale[blobKey]; ok {
+		return metadata, nil
+	}
+	return s.BlobStore.GetBlobMetadata(ctx, blobKey)
+}
+
+func TestCancelBlobConfirmedConcurrently(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := &staleBlobStore{BlobStore: memorydb.NewBlobStore(1024*1024, logger), stale: make(map[disperser.BlobKey]*disperser.BlobMetadata)}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{DeleteOnCancel: true}, blobStore, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+	ctx, _ := newTestContext()
+
+	disperseReply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("confirmed blob"), SecurityParams: testSecurityParams})
+	require.NoError(t, err)
+	blobKey, err := disperser.ParseBlobKey(string(disperseReply.GetRequestId()))
+	require.NoError(t, err)
+	processing, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	require.NoError(t, err)
+	// the blob is confirmed by the batcher after the server read it as processing
+	_, err = blobStore.MarkBlobConfirmed(ctx, processing, &disperser.ConfirmationInfo{BatchHeaderHash: [32]byte{1}})
+	require.NoError(t, err)
+	blobStore.stale[blobKey] = processing
+
+	_, err = server.CancelBlob(ctx, &pb.CancelBlobRequest{RequestId: disperseReply.GetRequestId()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	metadata, err := blobStore.BlobStore.GetBlobMetadata(ctx, blobKey)
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, metadata.BlobStatus)
+}
+
+func TestCancelBlobOfAnotherAccount(t *testing.T) {
+	_, conn := newAPIKeyTestServer(t, apiserver.NewAPIKeyAuth(map[string]string{"key-a": "rollup-a", "key-b": "rollup-b"}), nil)
+	client := pb.NewDisperserClient(conn)
+
+	reply, err := client.DisperseBlob(withAPIKey("key-a"), &pb.DisperseBlobRequest{Data: []byte("blob of rollup a"), SecurityParams: testSecurityParams})
+	require.NoError(t, err)
+
+	_, err = client.CancelBlob(withAPIKey("key-b"), &pb.CancelBlobRequest{RequestId: reply.GetRequestId()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	statusReply, err := client.GetBlobStatus(withAPIKey("key-a"), &pb.BlobStatusRequest{RequestId: reply.GetRequestId()})
+	require.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, statusReply.GetStatus())
+
+	cancelReply, err := client.CancelBlob(withAPIKey("key-a"), &pb.CancelBlobRequest{RequestId: reply.GetRequestId()})
+	require.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_FAILED, cancelReply.GetStatus())
+}
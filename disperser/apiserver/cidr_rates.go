@@ -0,0 +1,113 @@
+package apiserver
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/0glabs/0g-data-avail/common"
+)
+
+// CIDRRateEntry overrides the per account rates of the requesters whose IP address is in a CIDR block, e.g. a subnet
+// managed by a single customer. Each address of the block still has its own buckets.
+type CIDRRateEntry struct {
+	CIDR string
+	// AccountRate is the per account throughput (Bytes/sec)
+	AccountRate common.RateParam
+	// BlobRate is the per account blob rate, in blobs/sec * blobRateMultiplier like QuorumRateInfo.PerUserUnauthBlobRate
+	BlobRate common.RateParam
+}
+
+// ParseCIDRRates parses the CIDR rates given in the form of cidr=bytesPerSec:blobsPerSec
+func ParseCIDRRates(rates []string) ([]CIDRRateEntry, error) {
+	entries := make([]CIDRRateEntry, 0, len(rates))
+	for _, rate := range rates {
+		cidr, values, ok := strings.Cut(rate, "=")
+		parts := strings.Split(values, ":")
+		if !ok || len(parts) != 2 {
+			return nil, fmt.Errorf("invalid CIDR rate %q, expected cidr=bytesPerSec:blobsPerSec", rate)
+		}
+		accountRate, err := strconv.ParseUint(parts[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid byte rate in CIDR rate %q: %w", rate, err)
+		}
+		blobRate, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || blobRate < 0 {
+			return nil, fmt.Errorf("invalid blob rate in CIDR rate %q", rate)
+		}
+		entries = append(entries, CIDRRateEntry{
+			CIDR:        cidr,
+			AccountRate: common.RateParam(accountRate),
+			BlobRate:    common.RateParam(blobRate * blobRateMultiplier),
+		})
+	}
+	if _, err := newCIDRRateTable(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// cidrRateTable is the CIDR rates sorted by the first address of their block, the blocks can't overlap so that the
+// block containing an address is found by a binary search
+type cidrRateTable struct {
+	blocks []cidrRateBlock
+}
+
+type cidrRateBlock struct {
+	// first and last are the 16 byte form of the first and last addresses of the block
+	first, last net.IP
+	entry       CIDRRateEntry
+}
+
+func newCIDRRateTable(entries []CIDRRateEntry) (*cidrRateTable, error) {
+	blocks := make([]cidrRateBlock, 0, len(entries))
+	for _, entry := range entries {
+		_, ipNet, err := net.ParseCIDR(entry.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR block %q: %w", entry.CIDR, err)
+		}
+		first := ipNet.IP.To16()
+		last := make(net.IP, len(first))
+		copy(last, first)
+		// the mask has the length of ipNet.IP, which is 4 bytes for IPv4 blocks, it covers the last bytes of the 16 byte form
+		offset := len(last) - len(ipNet.Mask)
+		for i, b := range ipNet.Mask {
+			last[offset+i] |= ^b
+		}
+		blocks = append(blocks, cidrRateBlock{first: first, last: last, entry: entry})
+	}
+
+	sort.Slice(blocks, func(i, j int) bool { return bytes.Compare(blocks[i].first, blocks[j].first) < 0 })
+	for i := 1; i < len(blocks); i++ {
+		if bytes.Compare(blocks[i].first, blocks[i-1].last) <= 0 {
+			return nil, fmt.Errorf("CIDR blocks %s and %s overlap", blocks[i-1].entry.CIDR, blocks[i].entry.CIDR)
+		}
+	}
+	return &cidrRateTable{blocks: blocks}, nil
+}
+
+// lookup returns the rates of the block containing the address, if any
+func (t *cidrRateTable) lookup(address string) (CIDRRateEntry, bool) {
+	ip := net.ParseIP(address).To16()
+	if t == nil || ip == nil {
+		return CIDRRateEntry{}, false
+	}
+	// the first block starting after the address, the address can only be in the block before it
+	i := sort.Search(len(t.blocks), func(i int) bool { return bytes.Compare(t.blocks[i].first, ip) > 0 })
+	if i == 0 || bytes.Compare(ip, t.blocks[i-1].last) > 0 {
+		return CIDRRateEntry{}, false
+	}
+	return t.blocks[i-1].entry, true
+}
+
+// accountRates returns the rates of the quorum with the per account rates of the CIDR block of the account, if any
+func (t *cidrRateTable) accountRates(accountID string, rates QuorumRateInfo) QuorumRateInfo {
+	if entry, ok := t.lookup(accountID); ok {
+		rates.PerUserUnauthThroughput = entry.AccountRate
+		rates.PerUserUnauthBlobRate = entry.BlobRate
+	}
+	return rates
+}
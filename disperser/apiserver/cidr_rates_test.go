@@ -0,0 +1,89 @@
+package apiserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/peer"
+)
+
+func TestParseCIDRRates(t *testing.T) {
+	entries, err := apiserver.ParseCIDRRates([]string{"10.0.0.0/24=1000:0.5", "2001:db8::/32=2000:2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []apiserver.CIDRRateEntry{
+		{CIDR: "10.0.0.0/24", AccountRate: 1000, BlobRate: 500_000},
+		{CIDR: "2001:db8::/32", AccountRate: 2000, BlobRate: 2_000_000},
+	}, entries)
+
+	for _, invalid := range [][]string{
+		{"10.0.0.0/24"},
+		{"10.0.0.0/24=1000"},
+		{"10.0.0.0=1000:1"},
+		{"10.0.0.0/24=-1:1"},
+		{"10.0.0.0/24=1000:x"},
+		// overlapping blocks
+		{"10.0.0.0/16=1000:1", "10.0.3.0/24=2000:1"},
+	} {
+		_, err := apiserver.ParseCIDRRates(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCIDRRates(t *testing.T) {
+	logger := &mock.Logger{}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+	assert.NoError(t, err)
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{10 * time.Second},
+		Multipliers: []float32{1},
+	}, bucketStore, nil, nil, nil, logger)
+	cidrRates, err := apiserver.ParseCIDRRates([]string{"10.0.0.0/24=1000:2", "10.0.2.0/24=5000:3", "2001:db8::/32=2000:4"})
+	assert.NoError(t, err)
+	rateConfig := apiserver.RateConfig{
+		QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+			0: {TotalUnauthThroughput: 1_000_000, TotalUnauthBlobRate: 1000 * 1e6, PerUserUnauthThroughput: 10, PerUserUnauthBlobRate: 1e6},
+		},
+		CIDRRates: cidrRates,
+	}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, disperser.NewMetrics("9100", logger), ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+
+	contextFrom := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 51001}})
+	}
+	for ip, rates := range map[string][2]float64{
+		"10.0.0.0":    {1000, 2},
+		"10.0.0.255":  {1000, 2},
+		"10.0.1.1":    {10, 1},
+		"10.0.2.17":   {5000, 3},
+		"2001:db8::1": {2000, 4},
+		"2001:db9::1": {10, 1},
+		"127.0.0.1":   {10, 1},
+	} {
+		reply, err := server.GetRateLimitStatus(contextFrom(ip), &pb.GetRateLimitStatusRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, reply.GetBuckets(), 2)
+		assert.Equal(t, rates[0], reply.GetBuckets()[0].GetRate(), ip)
+		assert.Equal(t, rates[1], reply.GetBuckets()[1].GetRate(), ip)
+	}
+
+	// a blob of 1000 bytes is only allowed within the block
+	securityParams := []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}}
+	_, err = server.DisperseBlob(contextFrom("10.0.0.7"), &pb.DisperseBlobRequest{Data: make([]byte, 1000), SecurityParams: securityParams})
+	assert.NoError(t, err)
+	_, err = server.DisperseBlob(contextFrom("10.0.1.7"), &pb.DisperseBlobRequest{Data: make([]byte, 1000), SecurityParams: securityParams})
+	assert.ErrorContains(t, err, "account limit")
+}
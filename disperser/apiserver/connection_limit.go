@@ -0,0 +1,107 @@
+package apiserver
+
+import (
+	"context"
+	"math"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// removedCounter is set on the counter of an IP once it has no active request left, so that a request racing with its
+// removal from the map retries with a new counter
+const removedCounter = math.MinInt64 / 2
+
+// connectionLimiter counts the active requests of each client IP
+type connectionLimiter struct {
+	maxPerIP int
+	// clientIP returns the IP of the client of a request, the requests without one are not limited
+	clientIP func(ctx context.Context) (string, bool)
+	// counters maps the client IPs to their *atomic.Int64 count of active requests
+	counters sync.Map
+	// onReject is called when a request is rejected, it may be nil
+	onReject func()
+}
+
+// ConnectionLimitInterceptor rejects the requests of a client IP with ResourceExhausted while it already has maxPerIP
+// active requests, so that a single client cannot hold all the handlers of the server. The IP is resolved like the
+// rate limiter does, from the clientIPHeader set by the proxies in front of the server if not empty, or else from the
+// direct connection. The requests without a peer address are not limited.
+func ConnectionLimitInterceptor(maxPerIP int, clientIPHeader string) grpc.UnaryServerInterceptor {
+	return newConnectionLimiter(maxPerIP, func(ctx context.Context) (string, bool) {
+		return clientAddress(ctx, clientIPHeader)
+	}, nil).intercept
+}
+
+func newConnectionLimiter(maxPerIP int, clientIP func(ctx context.Context) (string, bool), onReject func()) *connectionLimiter {
+	return &connectionLimiter{maxPerIP: maxPerIP, clientIP: clientIP, onReject: onReject}
+}
+
+func (l *connectionLimiter) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ip, ok := l.clientIP(ctx)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	counter, active := l.acquire(ip)
+	// released even if the handler panics
+	defer l.release(ip, counter)
+	if active > int64(l.maxPerIP) {
+		if l.onReject != nil {
+			l.onReject()
+		}
+		return nil, status.Errorf(codes.ResourceExhausted, "too many concurrent requests from %s", ip)
+	}
+	return handler(ctx, req)
+}
+
+// acquire counts a new active request of the IP, it returns the counter of the IP and its number of active requests
+func (l *connectionLimiter) acquire(ip string) (*atomic.Int64, int64) {
+	for {
+		value, ok := l.counters.Load(ip)
+		if !ok {
+			value, _ = l.counters.LoadOrStore(ip, new(atomic.Int64))
+		}
+		counter := value.(*atomic.Int64)
+		if active := counter.Add(1); active > 0 {
+			return counter, active
+		}
+		// the counter is being removed
+		l.counters.CompareAndDelete(ip, counter)
+	}
+}
+
+// release counts the end of a request of the IP, the counter is removed once the IP has no active request left
+func (l *connectionLimiter) release(ip string, counter *atomic.Int64) {
+	if counter.Add(-1) == 0 && counter.CompareAndSwap(0, removedCounter) {
+		l.counters.CompareAndDelete(ip, counter)
+	}
+}
+
+// clientAddress returns the IP of the client of the request like the rate limiter, see common.GetClientAddress
+func clientAddress(ctx context.Context, clientIPHeader string) (string, bool) {
+	if _, ok := peerIP(ctx); !ok {
+		return "", false
+	}
+	ip, err := common.GetClientAddress(ctx, clientIPHeader, 2, true)
+	return ip, err == nil
+}
+
+// peerIP returns the IP of the direct connection of the request
+func peerIP(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "", false
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String(), true
+	}
+	return host, true
+}
@@ -0,0 +1,102 @@
+package apiserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func peerContext(ip string, port int) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: port}})
+}
+
+func TestConnectionLimitInterceptor(t *testing.T) {
+	interceptor := apiserver.ConnectionLimitInterceptor(2, "")
+	info := &grpc.UnaryServerInfo{FullMethod: "/disperser.Disperser/DisperseBlob"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blocking := func(ctx context.Context, req interface{}) (interface{}, error) {
+		started <- struct{}{}
+		<-release
+		return "ok", nil
+	}
+	done := make(chan error, 2)
+	for port := 1000; port < 1002; port++ {
+		ctx := peerContext("10.0.0.1", port)
+		go func() {
+			_, err := interceptor(ctx, nil, info, blocking)
+			done <- err
+		}()
+		<-started
+	}
+
+	immediate := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	// the IP is limited across its connections
+	_, err := interceptor(peerContext("10.0.0.1", 1002), nil, info, immediate)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	// the other IPs are not
+	reply, err := interceptor(peerContext("10.0.0.2", 1000), nil, info, immediate)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+	_, err = interceptor(peerContext("10.0.0.1", 1002), nil, info, immediate)
+	assert.NoError(t, err)
+}
+
+func TestConnectionLimitInterceptorReleasesOnPanic(t *testing.T) {
+	interceptor := apiserver.ConnectionLimitInterceptor(1, "")
+	info := &grpc.UnaryServerInfo{FullMethod: "/disperser.Disperser/DisperseBlob"}
+	ctx := peerContext("10.0.0.1", 1000)
+
+	assert.Panics(t, func() {
+		_, _ = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("handler failed")
+		})
+	})
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil })
+	assert.NoError(t, err)
+}
+
+func TestConnectionLimitInterceptorBehindProxy(t *testing.T) {
+	interceptor := apiserver.ConnectionLimitInterceptor(1, "X-Forwarded-For")
+	info := &grpc.UnaryServerInfo{FullMethod: "/disperser.Disperser/DisperseBlob"}
+	// all the requests come through the same proxy, the client IP is the one before the last proxy
+	proxiedContext := func(clientIP string) context.Context {
+		return metadata.NewIncomingContext(peerContext("10.0.0.254", 1000), metadata.Pairs("X-Forwarded-For", clientIP+", 10.0.0.253"))
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := interceptor(proxiedContext("192.168.0.1"), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			started <- struct{}{}
+			<-release
+			return "ok", nil
+		})
+		done <- err
+	}()
+	<-started
+
+	immediate := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	_, err := interceptor(proxiedContext("192.168.0.1"), nil, info, immediate)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	// the other clients behind the proxy are not limited
+	_, err = interceptor(proxiedContext("192.168.0.2"), nil, info, immediate)
+	assert.NoError(t, err)
+
+	close(release)
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,307 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// httpGatewayPathPrefix is the path prefix of the blob endpoints of the http gateway
+	httpGatewayPathPrefix = "/v1/blob"
+	// httpGatewayShutdownTimeout is the time the in-flight http requests are given to finish on shutdown
+	httpGatewayShutdownTimeout = 10 * time.Second
+	// octetStreamContentType is the content type of the raw blob data
+	octetStreamContentType = "application/octet-stream"
+	// eventStreamContentType is the content type of the server-sent events
+	eventStreamContentType = "text/event-stream"
+)
+
+var httpGatewayMarshalOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// HTTPGatewayServer serves the dispersal APIs as HTTP/1.1 JSON endpoints, for clients which can't use gRPC:
+//   - POST /v1/blob: DisperseBlob, the body is the JSON mapping of DisperseBlobRequest
+//   - GET /v1/blob/{requestId}/status: GetBlobStatus
+//   - GET /v1/blob/{requestId}/events: WatchBlobStatus, as server-sent events
+//   - GET /v1/blob/{batchHeaderHash}/{blobIndex}: RetrieveBlob, the batch header hash is hex encoded
+//
+// The replies are the JSON mappings of the gRPC replies, so the blob data is base64 encoded. RetrieveBlob returns
+// the raw blob data instead if the request accepts application/octet-stream. The requests go through the same checks,
+// rate limits and metrics as the gRPC requests.
+type HTTPGatewayServer struct {
+	server *DispersalServer
+	port   string
+}
+
+func NewHTTPGatewayServer(server *DispersalServer, port string) *HTTPGatewayServer {
+	return &HTTPGatewayServer{
+		server: server,
+		port:   port,
+	}
+}
+
+// Start serves the http requests until the context is done
+func (g *HTTPGatewayServer) Start(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf("%s:%s", disperser.Localhost, g.port),
+		Handler:           g.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpGatewayShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			g.server.logger.Warn("[apiserver] failed to shut down the http gateway", "err", err)
+		}
+	}()
+
+	g.server.logger.Info("[apiserver] http gateway listening", "port", g.port)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("could not start http gateway: %w", err)
+	}
+	return nil
+}
+
+// Handler returns the handler of the blob endpoints
+func (g *HTTPGatewayServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(httpGatewayPathPrefix, g.handleDisperseBlob)
+	mux.HandleFunc(httpGatewayPathPrefix+"/", g.handleGetBlob)
+	if g.server.apiKeyAuth != nil {
+		return g.server.apiKeyAuth.httpHandler(mux)
+	}
+	return mux
+}
+
+func (g *HTTPGatewayServer) handleDisperseBlob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// the body is bounded by the gRPC max message size, the blob size is checked by DisperseBlob
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(g.server.maxRecvMsgSize())))
+	if err != nil {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "failed to read request: %v", err))
+		return
+	}
+	req := &pb.DisperseBlobRequest{}
+	if err := protojson.Unmarshal(body, req); err != nil {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "invalid request: %v", err))
+		return
+	}
+
+	ctx, stream := newHTTPGatewayContext(r)
+	reply, err := g.server.DisperseBlob(ctx, req)
+	stream.copyHeader(w)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	writeHTTPReply(w, reply)
+}
+
+func (g *HTTPGatewayServer) handleGetBlob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, httpGatewayPathPrefix+"/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	ctx, stream := newHTTPGatewayContext(r)
+	if parts[1] == "events" {
+		g.handleWatchBlobStatus(ctx, w, &pb.BlobStatusRequest{RequestId: []byte(parts[0])})
+		return
+	}
+	if parts[1] == "status" {
+		reply, err := g.server.GetBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: []byte(parts[0])})
+		stream.copyHeader(w)
+		if err != nil {
+			writeHTTPError(w, err)
+			return
+		}
+		writeHTTPReply(w, reply)
+		return
+	}
+
+	batchHeaderHash, err := hex.DecodeString(strings.TrimPrefix(parts[0], "0x"))
+	if err != nil || len(batchHeaderHash) != 32 {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "invalid batch header hash: %s", parts[0]))
+		return
+	}
+	blobIndex, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "invalid blob index: %s", parts[1]))
+		return
+	}
+	reply, err := g.server.RetrieveBlob(ctx, &pb.RetrieveBlobRequest{
+		BatchHeaderHash: batchHeaderHash,
+		BlobIndex:       uint32(blobIndex),
+		IncludeProof:    r.URL.Query().Get("include_proof") == "true",
+	})
+	stream.copyHeader(w)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	if strings.Contains(r.Header.Get("Accept"), octetStreamContentType) {
+		w.Header().Set("Content-Type", octetStreamContentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(reply.GetData())
+		return
+	}
+	writeHTTPReply(w, reply)
+}
+
+// handleWatchBlobStatus sends the blob status replies as "status" server-sent events. The errors occurring once
+// the events have started are sent as an "error" event, as the http status has already been written.
+func (g *HTTPGatewayServer) handleWatchBlobStatus(ctx context.Context, w http.ResponseWriter, req *pb.BlobStatusRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	started := false
+	err := g.server.watchBlobStatus(ctx, req, func(reply *pb.BlobStatusReply) error {
+		data, err := httpGatewayMarshalOptions.Marshal(reply)
+		if err != nil {
+			return err
+		}
+		if !started {
+			w.Header().Set("Content-Type", eventStreamContentType)
+			w.Header().Set("Cache-Control", "no-cache")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err == nil || ctx.Err() != nil {
+		// the watch is over, or the client is gone
+		return
+	}
+	if !started {
+		writeHTTPError(w, err)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+	flusher.Flush()
+}
+
+// newHTTPGatewayContext returns the context of a gRPC request equivalent to the http request, so that the client
+// address is extracted the same way, e.g. from the client IP header, and the gRPC headers set by the handlers are captured
+func newHTTPGatewayContext(r *http.Request) (context.Context, *httpGatewayTransportStream) {
+	ctx := r.Context()
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+	md := metadata.MD{}
+	for name, values := range r.Header {
+		md.Append(strings.ToLower(name), values...)
+	}
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	stream := &httpGatewayTransportStream{method: r.URL.Path}
+	return grpc.NewContextWithServerTransportStream(ctx, stream), stream
+}
+
+// httpGatewayTransportStream captures the gRPC headers set while handling an http request
+type httpGatewayTransportStream struct {
+	method string
+	header metadata.MD
+}
+
+func (s *httpGatewayTransportStream) Method() string {
+	return s.method
+}
+
+func (s *httpGatewayTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *httpGatewayTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *httpGatewayTransportStream) SetTrailer(md metadata.MD) error {
+	return nil
+}
+
+// copyHeader sets the captured gRPC headers, e.g. retry-after, as http headers
+func (s *httpGatewayTransportStream) copyHeader(w http.ResponseWriter) {
+	for name, values := range s.header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+}
+
+func writeHTTPReply(w http.ResponseWriter, reply proto.Message) {
+	data, err := httpGatewayMarshalOptions.Marshal(reply)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func writeHTTPError(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), httpStatusFromError(err))
+}
+
+// httpStatusFromError maps the errors of the dispersal APIs to http status codes
+func httpStatusFromError(err error) int {
+	if errors.Is(err, errSystemRateLimit) || errors.Is(err, errAccountRateLimit) {
+		return http.StatusTooManyRequests
+	}
+	if errors.Is(err, disperser.ErrBlobNotFound) {
+		return http.StatusNotFound
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
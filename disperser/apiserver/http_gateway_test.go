@@ -0,0 +1,96 @@
+package apiserver_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveHTTPGateway(gateway *apiserver.HTTPGatewayServer, req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	gateway.Handler().ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestHTTPGateway(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	gateway := apiserver.NewHTTPGatewayServer(server, "0")
+	ctx, _ := newTestContext()
+
+	data := []byte("blob over http")
+	body := `{"data": "` + base64.StdEncoding.EncodeToString(data) + `", "security_params": [{"quorum_id": 0, "adversary_threshold": 50, "quorum_threshold": 100}]}`
+	recorder := serveHTTPGateway(gateway, httptest.NewRequest(http.MethodPost, "/v1/blob", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var disperseReply struct {
+		Result    string `json:"result"`
+		RequestID []byte `json:"request_id"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &disperseReply))
+	assert.Equal(t, "PROCESSING", disperseReply.Result)
+
+	requestID := string(disperseReply.RequestID)
+	recorder = serveHTTPGateway(gateway, httptest.NewRequest(http.MethodGet, "/v1/blob/"+requestID+"/status", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var statusReply struct {
+		Status string `json:"status"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &statusReply))
+	assert.Equal(t, "PROCESSING", statusReply.Status)
+
+	// confirm the blob so it can be retrieved
+	blobKey, err := disperser.ParseBlobKey(requestID)
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	batchHeaderHash := [32]byte{0xab}
+	_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{BatchHeaderHash: batchHeaderHash, BlobIndex: 0})
+	assert.NoError(t, err)
+
+	retrievePath := "/v1/blob/0x" + hex.EncodeToString(batchHeaderHash[:]) + "/0"
+	recorder = serveHTTPGateway(gateway, httptest.NewRequest(http.MethodGet, retrievePath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var retrieveReply struct {
+		Data []byte `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &retrieveReply))
+	assert.Equal(t, data, retrieveReply.Data)
+
+	// binary mode
+	req := httptest.NewRequest(http.MethodGet, retrievePath, nil)
+	req.Header.Set("Accept", "application/octet-stream")
+	recorder = serveHTTPGateway(gateway, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/octet-stream", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, data, recorder.Body.Bytes())
+}
+
+func TestHTTPGatewayErrors(t *testing.T) {
+	server := newTestServer(disperser.ServerConfig{})
+	gateway := apiserver.NewHTTPGatewayServer(server, "0")
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{http.MethodGet, "/v1/blob", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/v1/blob", "not json", http.StatusBadRequest},
+		{http.MethodPost, "/v1/blob/abc/status", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/v1/blob/abc", "", http.StatusNotFound},
+		{http.MethodGet, "/v1/blob/not-hex/0", "", http.StatusBadRequest},
+		{http.MethodGet, "/v1/blob/" + strings.Repeat("ab", 32) + "/index", "", http.StatusBadRequest},
+	} {
+		recorder := serveHTTPGateway(gateway, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+		assert.Equal(t, tc.code, recorder.Code, "%s %s", tc.method, tc.path)
+	}
+}
@@ -0,0 +1,130 @@
+package apiserver
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxIdempotencyKeyLength is the maximum number of characters of the idempotency keys of the requests
+const maxIdempotencyKeyLength = 256
+
+type idempotentReply struct {
+	reply     *pb.DisperseBlobReply
+	expiresAt time.Time
+}
+
+// IdempotencyCache remembers the replies of the dispersal requests by the idempotency key of the requests and
+// their account, so that a retried request returns the reply of the original request instead of storing the blob
+// again. Concurrent requests with the same key are dispersed once. The replies are forgotten after the window.
+type IdempotencyCache struct {
+	window time.Duration
+
+	// replies maps the hash of the account ID and the idempotency key to an idempotentReply
+	replies sync.Map
+	group   singleflight.Group
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewIdempotencyCache creates an IdempotencyCache which forgets the replies after the window, along with the
+// goroutine sweeping the expired replies until Close
+func NewIdempotencyCache(window time.Duration) *IdempotencyCache {
+	c := &IdempotencyCache{
+		window: window,
+		stop:   make(chan struct{}),
+	}
+	go c.sweepExpired()
+	return c
+}
+
+// Do returns the reply of the request of the account with the idempotency key within the window, or disperses the
+// blob and remembers its reply if there is none. Failed dispersals are not remembered, so that they can be retried.
+func (c *IdempotencyCache) Do(accountID, idempotencyKey string, disperse func() (*pb.DisperseBlobReply, error)) (*pb.DisperseBlobReply, error) {
+	cacheKey := idempotencyCacheKey(accountID, idempotencyKey)
+	if reply, ok := c.get(cacheKey); ok {
+		return reply, nil
+	}
+
+	// the requests with the same key wait for the dispersal of the first one and share its reply
+	result, err, _ := c.group.Do(string(cacheKey[:]), func() (interface{}, error) {
+		// the reply may have been stored since the lookup, by a dispersal which just finished
+		if reply, ok := c.get(cacheKey); ok {
+			return reply, nil
+		}
+		reply, err := disperse()
+		if err != nil {
+			return nil, err
+		}
+		c.replies.Store(cacheKey, idempotentReply{reply: reply, expiresAt: time.Now().Add(c.window)})
+		return reply, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(result.(*pb.DisperseBlobReply)).(*pb.DisperseBlobReply), nil
+}
+
+// Close stops sweeping the expired replies
+func (c *IdempotencyCache) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+// get returns a copy of the unexpired reply of the key
+func (c *IdempotencyCache) get(cacheKey [sha256.Size]byte) (*pb.DisperseBlobReply, bool) {
+	value, ok := c.replies.Load(cacheKey)
+	if !ok {
+		return nil, false
+	}
+	entry := value.(idempotentReply)
+	if time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return proto.Clone(entry.reply).(*pb.DisperseBlobReply), true
+}
+
+// sweepExpired deletes the expired replies every window until the cache is closed
+func (c *IdempotencyCache) sweepExpired() {
+	ticker := time.NewTicker(c.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-ticker.C:
+			c.replies.Range(func(key, value interface{}) bool {
+				if now.After(value.(idempotentReply).expiresAt) {
+					c.replies.Delete(key)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// idempotencyCacheKey is the sha256 of the account ID and the idempotency key, the keys of different accounts
+// don't collide
+func idempotencyCacheKey(accountID, idempotencyKey string) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte(accountID))
+	h.Write([]byte{0})
+	h.Write([]byte(idempotencyKey))
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// validateIdempotencyKey rejects the idempotency keys which are too long to be remembered
+func validateIdempotencyKey(idempotencyKey string) error {
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return status.Errorf(codes.InvalidArgument, "the idempotency key cannot be longer than %d characters", maxIdempotencyKeyLength)
+	}
+	return nil
+}
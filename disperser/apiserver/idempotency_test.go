@@ -0,0 +1,130 @@
+package apiserver_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// slowBlobStore counts the stored blobs, which take delay to store
+type slowBlobStore struct {
+	disperser.BlobStore
+	delay  time.Duration
+	stored atomic.Int32
+}
+
+func (s *slowBlobStore) StoreBlob(ctx context.Context, blob *core.Blob, requestedAt uint64) (disperser.BlobKey, error) {
+	time.Sleep(s.delay)
+	s.stored.Add(1)
+	return s.BlobStore.StoreBlob(ctx, blob, requestedAt)
+}
+
+func newIdempotencyTestServer(window time.Duration) (*apiserver.DispersalServer, *slowBlobStore) {
+	logger := &mock.Logger{}
+	blobStore := &slowBlobStore{BlobStore: memorydb.NewBlobStore(1024*1024, logger), delay: 50 * time.Millisecond}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{IdempotencyWindowDuration: window, DedupWindow: time.Minute}, blobStore, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+	return server, blobStore
+}
+
+func TestDisperseBlobIdempotencyKey(t *testing.T) {
+	const window = 200 * time.Millisecond
+	server, blobStore := newIdempotencyTestServer(window)
+	defer server.Shutdown()
+	request := &pb.DisperseBlobRequest{Data: []byte("idempotent blob"), IdempotencyKey: "request-1", SecurityParams: testSecurityParams}
+
+	ctx, _ := newTestContext()
+	first, err := server.DisperseBlob(ctx, request)
+	require.NoError(t, err)
+
+	// a retry returns the original request id, without being rejected as a duplicate or stored again
+	retry, err := server.DisperseBlob(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, first.GetRequestId(), retry.GetRequestId())
+	assert.Equal(t, pb.BlobStatus_PROCESSING, retry.GetResult())
+	assert.Equal(t, int32(1), blobStore.stored.Load())
+
+	// the blobs without a key or with another key are checked for duplicates
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: request.Data, SecurityParams: testSecurityParams})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: request.Data, IdempotencyKey: "request-2", SecurityParams: testSecurityParams})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	// the key is forgotten after the window
+	time.Sleep(window + 50*time.Millisecond)
+	_, err = server.DisperseBlob(ctx, request)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("long key"), IdempotencyKey: strings.Repeat("k", 257), SecurityParams: testSecurityParams})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDisperseBlobIdempotencyKeyConcurrentRequests(t *testing.T) {
+	server, blobStore := newIdempotencyTestServer(time.Minute)
+	defer server.Shutdown()
+	request := &pb.DisperseBlobRequest{Data: []byte("concurrent blob"), IdempotencyKey: "request-1", SecurityParams: testSecurityParams}
+
+	const requests = 10
+	replies := make([]*pb.DisperseBlobReply, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, _ := newTestContext()
+			reply, err := server.DisperseBlob(ctx, request)
+			assert.NoError(t, err)
+			replies[i] = reply
+		}(i)
+	}
+	wg.Wait()
+
+	// the concurrent requests share the dispersal of the first one
+	assert.Equal(t, int32(1), blobStore.stored.Load())
+	for _, reply := range replies {
+		assert.Equal(t, replies[0].GetRequestId(), reply.GetRequestId())
+	}
+}
+
+func TestIdempotencyCache(t *testing.T) {
+	cache := apiserver.NewIdempotencyCache(time.Minute)
+	defer cache.Close()
+
+	calls := 0
+	disperse := func() (*pb.DisperseBlobReply, error) {
+		calls++
+		if calls == 1 {
+			return nil, status.Error(codes.Unavailable, "blob store is unavailable")
+		}
+		return &pb.DisperseBlobReply{RequestId: []byte("request id")}, nil
+	}
+
+	// the failed dispersals are not remembered
+	_, err := cache.Do("account-a", "key", disperse)
+	assert.Error(t, err)
+	reply, err := cache.Do("account-a", "key", disperse)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("request id"), reply.GetRequestId())
+	_, err = cache.Do("account-a", "key", disperse)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// the keys of other accounts are separate
+	_, err = cache.Do("account-b", "key", disperse)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,268 @@
+//go:build integration
+
+package apiserver_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/aws"
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	test_utils "github.com/0glabs/0g-data-avail/common/aws/dynamodb/utils"
+	"github.com/0glabs/0g-data-avail/common/aws/s3"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/0glabs/0g-data-avail/inabox/deploy"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// The integration tests run the DispersalServer on top of S3 and DynamoDB provided by localstack, which is started
+// in docker unless DEPLOY_LOCALSTACK=false, in which case an instance must be listening on LOCALSTACK_PORT.
+//
+//	go test -tags integration -run TestDispersalServerIntegration ./disperser/apiserver/
+
+const integrationBucketName = "test-apiserver-integration"
+
+var integrationSecurityParams = []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}}
+
+type integrationEnv struct {
+	awsConfig    aws.ClientConfig
+	dynamoClient *commondynamodb.Client
+	s3Client     *s3.Client
+	logger       common.Logger
+}
+
+type integrationServerConfig struct {
+	tableName             string
+	ttl                   time.Duration
+	metadataHashAsBlobKey bool
+	ratelimiter           common.RateLimiter
+	rateConfig            apiserver.RateConfig
+}
+
+func setupIntegrationEnv(t *testing.T) *integrationEnv {
+	localStackPort := "4566"
+	deployLocalStack := os.Getenv("DEPLOY_LOCALSTACK") != "false"
+	if !deployLocalStack {
+		localStackPort = os.Getenv("LOCALSTACK_PORT")
+	}
+	if deployLocalStack {
+		pool, resource, err := deploy.StartDockertestWithLocalstackContainer(localStackPort)
+		require.NoError(t, err, "failed to start localstack container")
+		t.Cleanup(func() {
+			deploy.PurgeDockertestResources(pool, resource)
+		})
+	}
+
+	env := &integrationEnv{
+		awsConfig: aws.ClientConfig{
+			Region:          "us-east-1",
+			AccessKey:       "localstack",
+			SecretAccessKey: "localstack",
+			EndpointURL:     fmt.Sprintf("http://0.0.0.0:%s", localStackPort),
+		},
+		logger: &mock.Logger{},
+	}
+	var err error
+	env.dynamoClient, err = commondynamodb.NewClient(env.awsConfig, env.logger)
+	require.NoError(t, err)
+	env.s3Client, err = s3.NewClient(env.awsConfig, env.logger)
+	require.NoError(t, err)
+	require.NoError(t, env.s3Client.CreateBucket(context.Background(), integrationBucketName, "us-west-2"))
+	return env
+}
+
+// createTable creates a table with the given schema, named after the test so that the tests don't share state
+func (e *integrationEnv) createTable(t *testing.T, name string, schema func(name string) error) string {
+	tableName := "test-" + strings.NewReplacer("/", "-", "_", "-").Replace(t.Name()) + "-" + name
+	require.NoError(t, schema(tableName))
+	return tableName
+}
+
+// newServer serves a DispersalServer backed by S3 and DynamoDB, and returns a client of it and its blob store
+func (e *integrationEnv) newServer(t *testing.T, config integrationServerConfig) (pb.DisperserClient, *blobstore.SharedBlobStore) {
+	ctx := context.Background()
+	tableName := e.createTable(t, "BlobMetadata", func(name string) error {
+		_, err := test_utils.CreateTable(ctx, e.awsConfig, name, blobstore.GenerateTableSchema(name, 10, 10))
+		return err
+	})
+	metadataStore := blobstore.NewBlobMetadataStore(e.dynamoClient, e.logger, tableName, config.ttl)
+	blobStore := blobstore.NewSharedStorage(integrationBucketName, e.s3Client, config.metadataHashAsBlobKey, nil, metadataStore, nil, 0, "", nil, e.logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, e.logger, disperser.NewMetrics("9100", e.logger), config.ratelimiter, config.rateConfig, config.metadataHashAsBlobKey, nil, eth_common.Hash{}, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Shutdown)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return pb.NewDisperserClient(conn), blobStore
+}
+
+// confirmBatch marks the blobs confirmed in a batch, like the batcher does once the batch is confirmed on chain
+func confirmBatch(t *testing.T, blobStore *blobstore.SharedBlobStore, batchHeaderHash [32]byte, keys []disperser.BlobKey) {
+	ctx := context.Background()
+	blobHeaders := make([]*core.BlobHeader, len(keys))
+	for i := range keys {
+		commitmentRoot := make([]byte, 32)
+		commitmentRoot[0] = byte(i)
+		blobHeaders[i] = &core.BlobHeader{CommitmentRoot: commitmentRoot}
+	}
+	batchHeader := &core.BatchHeader{}
+	_, err := batchHeader.SetBatchRoot(blobHeaders)
+	require.NoError(t, err)
+
+	for i, key := range keys {
+		metadata, err := blobStore.GetBlobMetadata(ctx, key)
+		require.NoError(t, err)
+		_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{
+			BatchHeaderHash:         batchHeaderHash,
+			BlobIndex:               uint32(i),
+			BatchRoot:               batchHeader.BatchRoot[:],
+			CommitmentRoot:          blobHeaders[i].CommitmentRoot,
+			Length:                  uint32(core.GetBlobLength(metadata.RequestMetadata.BlobSize)),
+			ConfirmationBlockNumber: 100,
+		})
+		require.NoError(t, err)
+	}
+}
+
+// waitForStatus polls the status of the blob until it is the expected one
+func waitForStatus(t *testing.T, client pb.DisperserClient, requestID []byte, expected pb.BlobStatus) *pb.BlobStatusReply {
+	var reply *pb.BlobStatusReply
+	require.Eventually(t, func() bool {
+		var err error
+		reply, err = client.GetBlobStatus(context.Background(), &pb.BlobStatusRequest{RequestId: requestID})
+		return err == nil && reply.GetStatus() == expected
+	}, 10*time.Second, 100*time.Millisecond, "blob %s didn't reach status %s", requestID, expected)
+	return reply
+}
+
+func testDispersalLifecycle(t *testing.T, env *integrationEnv, metadataHashAsBlobKey bool) {
+	ctx := context.Background()
+	client, blobStore := env.newServer(t, integrationServerConfig{metadataHashAsBlobKey: metadataHashAsBlobKey})
+
+	blobs := [][]byte{[]byte("first blob of the integration test"), []byte("second blob")}
+	requestIDs := make([][]byte, len(blobs))
+	keys := make([]disperser.BlobKey, len(blobs))
+	for i, data := range blobs {
+		reply, err := client.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: data, SecurityParams: integrationSecurityParams})
+		require.NoError(t, err)
+		assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetResult())
+		requestIDs[i] = reply.GetRequestId()
+		keys[i], err = disperser.ParseBlobKey(string(requestIDs[i]))
+		require.NoError(t, err)
+
+		// the content is stored in S3 under the key of the mode
+		objectKey := fmt.Sprintf("blob/%s.json", keys[i].BlobHash)
+		if metadataHashAsBlobKey {
+			objectKey = keys[i].MetadataHash
+		}
+		stored, err := env.s3Client.DownloadObject(ctx, integrationBucketName, objectKey)
+		require.NoError(t, err)
+		assert.Equal(t, data, stored)
+
+		waitForStatus(t, client, requestIDs[i], pb.BlobStatus_PROCESSING)
+	}
+
+	batchHeaderHash := [32]byte{0xba, 0x7c}
+	confirmBatch(t, blobStore, batchHeaderHash, keys)
+
+	for i, data := range blobs {
+		status := waitForStatus(t, client, requestIDs[i], pb.BlobStatus_CONFIRMED)
+		proof := status.GetInfo().GetBlobVerificationProof()
+		assert.Equal(t, batchHeaderHash[:], proof.GetBatchMetadata().GetBatchHeaderHash())
+		assert.Equal(t, uint32(i), proof.GetBlobIndex())
+
+		reply, err := client.RetrieveBlob(ctx, &pb.RetrieveBlobRequest{
+			BatchHeaderHash: batchHeaderHash[:],
+			BlobIndex:       uint32(i),
+			IncludeProof:    true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, data, reply.GetData())
+		assert.NotEmpty(t, reply.GetInclusionProof())
+	}
+}
+
+func TestDispersalServerIntegration(t *testing.T) {
+	env := setupIntegrationEnv(t)
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		testDispersalLifecycle(t, env, false)
+	})
+
+	t.Run("MetadataHashAsBlobKey", func(t *testing.T) {
+		testDispersalLifecycle(t, env, true)
+	})
+
+	t.Run("RateLimit", func(t *testing.T) {
+		ctx := context.Background()
+		// the rate buckets are kept in DynamoDB, like in production
+		bucketTableName := env.createTable(t, "RateBuckets", func(name string) error {
+			_, err := test_utils.CreateTable(ctx, env.awsConfig, name, store.GenerateTableSchema(10, 10, name))
+			return err
+		})
+		ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+			BucketSizes: []time.Duration{time.Minute},
+			Multipliers: []float32{1},
+		}, store.NewDynamoParamStore[common.RateBucketParams](env.dynamoClient, bucketTableName), nil, nil, nil, env.logger)
+		// a single blob per minute per account
+		const generous = 1_000_000_000
+		rateConfig := apiserver.RateConfig{QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+			0: {TotalUnauthThroughput: generous, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: generous, PerUserUnauthBlobRate: 1},
+		}}
+		client, _ := env.newServer(t, integrationServerConfig{ratelimiter: ratelimiter, rateConfig: rateConfig})
+
+		_, err := client.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("allowed"), SecurityParams: integrationSecurityParams})
+		require.NoError(t, err)
+		_, err = client.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("rate limited"), SecurityParams: integrationSecurityParams})
+		assert.ErrorContains(t, err, "account limit")
+	})
+
+	t.Run("TTLExpiry", func(t *testing.T) {
+		ctx := context.Background()
+		ttl := 2 * time.Second
+		client, blobStore := env.newServer(t, integrationServerConfig{ttl: ttl})
+
+		start := time.Now()
+		reply, err := client.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("expiring blob"), SecurityParams: integrationSecurityParams})
+		require.NoError(t, err)
+		key, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+		require.NoError(t, err)
+
+		// the expiry of the metadata, used as the DynamoDB TTL attribute, is set from the TTL of the store
+		metadata, err := blobStore.GetBlobMetadata(ctx, key)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, metadata.Expiry, uint64(start.Add(ttl).Unix()))
+		assert.LessOrEqual(t, metadata.Expiry, uint64(time.Now().Add(ttl).Unix()))
+
+		// a blob which expired before it was confirmed isn't confirmed
+		time.Sleep(ttl + time.Second)
+		_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{BatchHeaderHash: [32]byte{1}})
+		assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+		waitForStatus(t, client, reply.GetRequestId(), pb.BlobStatus_PROCESSING)
+	})
+}
@@ -0,0 +1,80 @@
+package apiserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// InterceptorPlugin allows operators to add custom unary interceptors (e.g. WAF, custom auth)
+// to the dispersal server without modifying it.
+type InterceptorPlugin interface {
+	UnaryInterceptor() grpc.UnaryServerInterceptor
+}
+
+// ServerOption configures optional behaviors of the DispersalServer
+type ServerOption func(*DispersalServer)
+
+// WithInterceptors registers interceptor plugins on the DispersalServer.
+//
+// Ordering guarantees:
+//   - plugins are invoked in the order they are registered, across all WithInterceptors options
+//   - all plugins are invoked before the built-in interceptors of the server (e.g. rate limiting and auth),
+//     so a plugin can reject a request before any built-in check runs
+//   - the requests are counted as in flight for the whole chain, plugins included
+func WithInterceptors(plugins ...InterceptorPlugin) ServerOption {
+	return func(s *DispersalServer) {
+		s.interceptorPlugins = append(s.interceptorPlugins, plugins...)
+	}
+}
+
+// unaryInterceptors returns the chain of unary interceptors of the server, plugins first
+func (s *DispersalServer) unaryInterceptors() []grpc.UnaryServerInterceptor {
+	interceptors := make([]grpc.UnaryServerInterceptor, 0, len(s.interceptorPlugins)+4)
+	interceptors = append(interceptors, s.inFlightInterceptor)
+	for _, plugin := range s.interceptorPlugins {
+		interceptors = append(interceptors, plugin.UnaryInterceptor())
+	}
+	if s.config.MaxConnectionsPerIP > 0 {
+		// the header is read on each request, as the rate config is reloadable
+		clientIP := func(ctx context.Context) (string, bool) {
+			rateConfig, _ := s.getRateConfig()
+			return clientAddress(ctx, rateConfig.ClientIPHeader)
+		}
+		interceptors = append(interceptors, newConnectionLimiter(s.config.MaxConnectionsPerIP, clientIP, s.metrics.IncrementConnectionsRejectedPerIP).intercept)
+	}
+	if s.config.DefaultRequestTimeout > 0 || len(s.config.PerMethodTimeouts) > 0 {
+		interceptors = append(interceptors, newRequestTimeouts(s.config.DefaultRequestTimeout, s.config.PerMethodTimeouts, s.logger).intercept)
+	}
+	if s.apiKeyAuth != nil {
+		interceptors = append(interceptors, s.apiKeyAuth.UnaryInterceptor())
+	}
+	return interceptors
+}
+
+// streamInterceptors returns the chain of stream interceptors of the server
+func (s *DispersalServer) streamInterceptors() []grpc.StreamServerInterceptor {
+	interceptors := []grpc.StreamServerInterceptor{s.inFlightStreamInterceptor}
+	if s.apiKeyAuth != nil {
+		interceptors = append(interceptors, s.apiKeyAuth.StreamInterceptor())
+	}
+	return interceptors
+}
+
+// inFlightInterceptor counts the requests being handled
+func (s *DispersalServer) inFlightInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s.metrics.InFlightRequests.Set(float64(s.inFlight.Add(1)))
+	defer func() {
+		s.metrics.InFlightRequests.Set(float64(s.inFlight.Add(-1)))
+	}()
+	return handler(ctx, req)
+}
+
+// inFlightStreamInterceptor counts the streams being handled, like inFlightInterceptor
+func (s *DispersalServer) inFlightStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	s.metrics.InFlightRequests.Set(float64(s.inFlight.Add(1)))
+	defer func() {
+		s.metrics.InFlightRequests.Set(float64(s.inFlight.Add(-1)))
+	}()
+	return handler(srv, ss)
+}
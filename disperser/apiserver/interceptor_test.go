@@ -0,0 +1,338 @@
+package apiserver_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type recordingInterceptor struct {
+	name  string
+	mu    sync.Mutex
+	calls *[]string
+	reqs  []interface{}
+}
+
+func (r *recordingInterceptor) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		r.mu.Lock()
+		*r.calls = append(*r.calls, r.name+":"+info.FullMethod)
+		r.reqs = append(r.reqs, req)
+		r.mu.Unlock()
+		return handler(ctx, req)
+	}
+}
+
+func TestInterceptorPlugins(t *testing.T) {
+	calls := make([]string, 0)
+	first := &recordingInterceptor{name: "first", calls: &calls}
+	second := &recordingInterceptor{name: "second", calls: &calls}
+
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithInterceptors(first), apiserver.WithInterceptors(second))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer listener.Close()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	request := &pb.DisperseBlobRequest{Data: []byte("intercepted"), SecurityParams: testSecurityParams}
+	reply, err := pb.NewDisperserClient(conn).DisperseBlob(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetResult())
+
+	// plugins are invoked in registration order
+	assert.Equal(t, []string{
+		"first:/disperser.Disperser/DisperseBlob",
+		"second:/disperser.Disperser/DisperseBlob",
+	}, calls)
+	assert.Len(t, first.reqs, 1)
+	assert.True(t, proto.Equal(request, first.reqs[0].(*pb.DisperseBlobRequest)))
+}
+
+func TestServeMaxRecvMsgSize(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(4*1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{MaxRecvMsgSizeMiB: 1}, blobStore, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer listener.Close()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewDisperserClient(conn)
+
+	_, err = client.DisperseBlob(context.Background(), &pb.DisperseBlobRequest{Data: make([]byte, 2*1024*1024), SecurityParams: testSecurityParams})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, err = client.DisperseBlob(context.Background(), &pb.DisperseBlobRequest{Data: make([]byte, 512*1024), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+}
+
+func TestServeKeepalive(t *testing.T) {
+	logger := &mock.Logger{}
+	config := disperser.ServerConfig{
+		KeepaliveConfig: disperser.KeepaliveConfig{
+			ServerKeepaliveTime:    time.Second,
+			ServerKeepaliveTimeout: time.Second,
+		},
+	}
+	server := apiserver.NewDispersalServer(config, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer listener.Close()
+
+	// an idle client connection, which only acks the frames of the server
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte(http2.ClientPreface))
+	assert.NoError(t, err)
+	framer := http2.NewFramer(conn, conn)
+	assert.NoError(t, framer.WriteSettings())
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	for {
+		frame, err := framer.ReadFrame()
+		if !assert.NoError(t, err, "no keepalive ping received") {
+			return
+		}
+		switch frame := frame.(type) {
+		case *http2.SettingsFrame:
+			if !frame.IsAck() {
+				assert.NoError(t, framer.WriteSettingsAck())
+			}
+		case *http2.PingFrame:
+			if !frame.IsAck() {
+				assert.NoError(t, framer.WritePing(true, frame.Data))
+				return
+			}
+		}
+	}
+}
+
+// blockingInterceptor holds the calls until it is released
+type blockingInterceptor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingInterceptor) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		b.started <- struct{}{}
+		<-b.release
+		return handler(ctx, req)
+	}
+}
+
+func TestServeMaxConcurrentStreams(t *testing.T) {
+	const maxStreams = 2
+	blocking := &blockingInterceptor{started: make(chan struct{}, maxStreams+1), release: make(chan struct{})}
+
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{MaxConcurrentStreams: maxStreams}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithInterceptors(blocking))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer listener.Close()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewDisperserClient(conn)
+
+	// occupy all the streams of the connection
+	var wg sync.WaitGroup
+	for i := 0; i < maxStreams; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.DisperseBlob(context.Background(), &pb.DisperseBlobRequest{Data: []byte("concurrent"), SecurityParams: testSecurityParams})
+			assert.NoError(t, err)
+		}()
+	}
+	for i := 0; i < maxStreams; i++ {
+		<-blocking.started
+	}
+
+	// the stream above the limit is not served and fails once its deadline is reached
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = client.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("over the limit"), SecurityParams: testSecurityParams})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Len(t, blocking.started, 0)
+
+	close(blocking.release)
+	wg.Wait()
+	_, err = client.DisperseBlob(context.Background(), &pb.DisperseBlobRequest{Data: []byte("after release"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(maxStreams), testutil.ToFloat64(metrics.GrpcMaxConcurrentStreams))
+}
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	blocking := &blockingInterceptor{started: make(chan struct{}, 1), release: make(chan struct{})}
+
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithInterceptors(blocking))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewDisperserClient(conn)
+
+	// a slow request is in flight when the server is shut down
+	replied := make(chan error, 1)
+	go func() {
+		_, err := client.DisperseBlob(context.Background(), &pb.DisperseBlobRequest{Data: []byte("slow"), SecurityParams: testSecurityParams})
+		replied <- err
+	}()
+	<-blocking.started
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.InFlightRequests))
+
+	stopped := make(chan struct{})
+	go func() {
+		server.Shutdown()
+		close(stopped)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-stopped:
+		t.Fatal("the server was shut down with a request in flight")
+	default:
+	}
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.InFlightRequests))
+
+	close(blocking.release)
+	<-stopped
+	assert.NoError(t, <-replied)
+	assert.NoError(t, <-served)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.InFlightRequests))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.ShutdownDrainDuration))
+}
+
+func TestStartDrainsInFlightRequestsOnCancel(t *testing.T) {
+	blocking := &blockingInterceptor{started: make(chan struct{}, 1), release: make(chan struct{})}
+
+	// Start listens on the configured port, which is picked free
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	assert.NoError(t, listener.Close())
+
+	logger := &mock.Logger{}
+	config := disperser.ServerConfig{GrpcPort: strconv.Itoa(port), ShutdownTimeout: 5 * time.Second}
+	server := apiserver.NewDispersalServer(config, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{}, nil,
+		apiserver.WithInterceptors(blocking))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan error, 1)
+	go func() {
+		started <- server.Start(ctx)
+	}()
+
+	conn, err := grpc.Dial("127.0.0.1:"+strconv.Itoa(port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewDisperserClient(conn)
+	replied := make(chan error, 1)
+	go func() {
+		_, err := client.DisperseBlob(context.Background(), &pb.DisperseBlobRequest{Data: []byte("in flight"), SecurityParams: testSecurityParams}, grpc.WaitForReady(true))
+		replied <- err
+	}()
+	<-blocking.started
+
+	// Start doesn't return while the request is in flight
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-started:
+		t.Fatal("the server stopped with a request in flight")
+	default:
+	}
+
+	close(blocking.release)
+	assert.NoError(t, <-replied)
+	assert.NoError(t, <-started)
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	blocking := &blockingInterceptor{started: make(chan struct{}, 1), release: make(chan struct{})}
+	defer close(blocking.release)
+
+	logger := &mock.Logger{}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{ShutdownTimeout: 100 * time.Millisecond}, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithInterceptors(blocking))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	replied := make(chan error, 1)
+	go func() {
+		_, err := pb.NewDisperserClient(conn).DisperseBlob(context.Background(), &pb.DisperseBlobRequest{Data: []byte("stuck"), SecurityParams: testSecurityParams})
+		replied <- err
+	}()
+	<-blocking.started
+
+	// the request which is still in flight after the timeout is cancelled
+	start := time.Now()
+	server.Shutdown()
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, codes.Unavailable, status.Code(<-replied))
+}
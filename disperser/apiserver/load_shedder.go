@@ -0,0 +1,135 @@
+package apiserver
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+)
+
+// loadSampleInterval is the interval the load of the process is sampled at
+const loadSampleInterval = time.Second
+
+// LoadSample is a reading of the memory and GC pressure of the process
+type LoadSample struct {
+	// HeapPct is the percentage of the memory limit used by heap objects
+	HeapPct float64
+	// GCPause is the longest GC pause since the previous sample
+	GCPause time.Duration
+}
+
+// LoadSampler reads the load of the process
+type LoadSampler interface {
+	Sample() LoadSample
+}
+
+type runtimeLoadSampler struct {
+	numGC   uint32
+	samples []metrics.Sample
+}
+
+// NewRuntimeLoadSampler creates a LoadSampler reading the load of the process from the runtime. The heap usage is
+// relative to the memory limit of the runtime (GOMEMLIMIT), it's reported as 0 if no memory limit is set.
+func NewRuntimeLoadSampler() LoadSampler {
+	return &runtimeLoadSampler{
+		samples: []metrics.Sample{
+			{Name: "/memory/classes/heap/objects:bytes"},
+			{Name: "/gc/gomemlimit:bytes"},
+		},
+	}
+}
+
+func (s *runtimeLoadSampler) Sample() LoadSample {
+	var sample LoadSample
+
+	metrics.Read(s.samples)
+	if s.samples[0].Value.Kind() == metrics.KindUint64 && s.samples[1].Value.Kind() == metrics.KindUint64 {
+		limit := s.samples[1].Value.Uint64()
+		if limit > 0 && limit < math.MaxInt64 {
+			sample.HeapPct = float64(s.samples[0].Value.Uint64()) / float64(limit) * 100
+		}
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	// PauseNs keeps the pauses of the last 256 GCs
+	newGCs := memStats.NumGC - s.numGC
+	if newGCs > uint32(len(memStats.PauseNs)) {
+		newGCs = uint32(len(memStats.PauseNs))
+	}
+	for i := uint32(0); i < newGCs; i++ {
+		pause := time.Duration(memStats.PauseNs[(memStats.NumGC-i+255)%256])
+		if pause > sample.GCPause {
+			sample.GCPause = pause
+		}
+	}
+	s.numGC = memStats.NumGC
+	return sample
+}
+
+// LoadShedder rejects new blobs while the process is under memory or GC pressure, as accepting them would make it worse
+type LoadShedder struct {
+	sampler LoadSampler
+	// heapPct is the heap usage above which blobs are rejected, the heap usage is not checked if it is 0
+	heapPct float64
+	// gcPause is the GC pause above which blobs are rejected, GC pauses are not checked if it is 0
+	gcPause time.Duration
+
+	shedding atomic.Bool
+	logger   common.Logger
+}
+
+func NewLoadShedder(sampler LoadSampler, heapPct float64, gcPause time.Duration, logger common.Logger) *LoadShedder {
+	return &LoadShedder{
+		sampler: sampler,
+		heapPct: heapPct,
+		gcPause: gcPause,
+		logger:  logger,
+	}
+}
+
+// WithLoadShedder makes DisperseBlob reject new blobs while the load shedder is shedding
+func WithLoadShedder(shedder *LoadShedder) ServerOption {
+	return func(s *DispersalServer) {
+		s.loadShedder = shedder
+	}
+}
+
+// Start samples the load periodically until the context is done
+func (l *LoadShedder) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(loadSampleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.Update()
+			}
+		}
+	}()
+}
+
+// Update samples the load once and starts or stops shedding accordingly
+func (l *LoadShedder) Update() {
+	sample := l.sampler.Sample()
+	shedding := (l.heapPct > 0 && sample.HeapPct > l.heapPct) || (l.gcPause > 0 && sample.GCPause > l.gcPause)
+	if l.shedding.Swap(shedding) != shedding {
+		if shedding {
+			l.logger.Warn("[apiserver] process is under pressure, rejecting new blobs", "heapPct", sample.HeapPct, "gcPause", sample.GCPause)
+		} else {
+			l.logger.Info("[apiserver] process pressure relieved, accepting new blobs", "heapPct", sample.HeapPct, "gcPause", sample.GCPause)
+		}
+	}
+}
+
+// Shedding returns whether new blobs should be rejected
+func (l *LoadShedder) Shedding() bool {
+	return l.shedding.Load()
+}
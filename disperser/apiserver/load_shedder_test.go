@@ -0,0 +1,95 @@
+package apiserver_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// syntheticLoadSampler returns the readings set by the test
+type syntheticLoadSampler struct {
+	sample apiserver.LoadSample
+}
+
+func (s *syntheticLoadSampler) Sample() apiserver.LoadSample {
+	return s.sample
+}
+
+func TestLoadShedder(t *testing.T) {
+	sampler := &syntheticLoadSampler{}
+	shedder := apiserver.NewLoadShedder(sampler, 85, 500*time.Millisecond, &mock.Logger{})
+
+	for _, tc := range []struct {
+		sample   apiserver.LoadSample
+		shedding bool
+	}{
+		{apiserver.LoadSample{HeapPct: 50, GCPause: time.Millisecond}, false},
+		{apiserver.LoadSample{HeapPct: 85, GCPause: 500 * time.Millisecond}, false},
+		{apiserver.LoadSample{HeapPct: 90, GCPause: time.Millisecond}, true},
+		{apiserver.LoadSample{HeapPct: 60, GCPause: time.Millisecond}, false},
+		{apiserver.LoadSample{HeapPct: 60, GCPause: 800 * time.Millisecond}, true},
+		{apiserver.LoadSample{HeapPct: 95, GCPause: 800 * time.Millisecond}, true},
+		{apiserver.LoadSample{}, false},
+	} {
+		sampler.sample = tc.sample
+		shedder.Update()
+		assert.Equal(t, tc.shedding, shedder.Shedding(), "sample %+v", tc.sample)
+	}
+
+	// a threshold of 0 disables the check
+	disabled := apiserver.NewLoadShedder(sampler, 0, 0, &mock.Logger{})
+	sampler.sample = apiserver.LoadSample{HeapPct: 100, GCPause: time.Minute}
+	disabled.Update()
+	assert.False(t, disabled.Shedding())
+}
+
+func TestDisperseBlobLoadShedding(t *testing.T) {
+	logger := &mock.Logger{}
+	metrics := disperser.NewMetrics("9100", logger)
+	sampler := &syntheticLoadSampler{}
+	shedder := apiserver.NewLoadShedder(sampler, 85, 500*time.Millisecond, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithLoadShedder(shedder))
+
+	ctx, _ := newTestContext()
+	_, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("no pressure"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+
+	sampler.sample = apiserver.LoadSample{HeapPct: 92}
+	shedder.Update()
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("memory pressure"), SecurityParams: testSecurityParams})
+	assert.ErrorContains(t, err, "system limit")
+
+	sampler.sample = apiserver.LoadSample{GCPause: time.Second}
+	shedder.Update()
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("gc pressure"), SecurityParams: testSecurityParams})
+	assert.ErrorContains(t, err, "system limit")
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.LoadShedRejections))
+
+	// blobs are accepted again once the pressure is relieved
+	sampler.sample = apiserver.LoadSample{HeapPct: 40, GCPause: time.Millisecond}
+	shedder.Update()
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("pressure relieved"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.LoadShedRejections))
+}
+
+func TestRuntimeLoadSampler(t *testing.T) {
+	sampler := apiserver.NewRuntimeLoadSampler()
+	sampler.Sample()
+	runtime.GC()
+
+	// the pause of the GC since the previous sample is reported
+	sample := sampler.Sample()
+	assert.GreaterOrEqual(t, sample.HeapPct, 0.0)
+	assert.Greater(t, sample.GCPause, time.Duration(0))
+}
@@ -0,0 +1,174 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	eth_common "github.com/ethereum/go-ethereum/common"
+)
+
+// blobConfirmationABI is the view function of the confirmation contract used by the on-chain fallback
+const blobConfirmationABI = `[{
+	"name": "getBlobConfirmation",
+	"type": "function",
+	"stateMutability": "view",
+	"inputs": [
+		{"name": "batchHeaderHash", "type": "bytes32"},
+		{"name": "blobIndex", "type": "uint32"}
+	],
+	"outputs": [
+		{"name": "confirmed", "type": "bool"},
+		{"name": "batchId", "type": "uint32"},
+		{"name": "blobCount", "type": "uint32"},
+		{"name": "batchRoot", "type": "bytes32"},
+		{"name": "referenceBlockNumber", "type": "uint32"},
+		{"name": "confirmationBlockNumber", "type": "uint32"},
+		{"name": "confirmationTxnHash", "type": "bytes32"},
+		{"name": "signatoryRecordHash", "type": "bytes32"},
+		{"name": "commitmentRoot", "type": "bytes"},
+		{"name": "dataLength", "type": "uint32"},
+		{"name": "inclusionProof", "type": "bytes"},
+		{"name": "fee", "type": "bytes"},
+		{"name": "quorumNumbers", "type": "bytes"},
+		{"name": "adversaryThresholds", "type": "bytes"},
+		{"name": "quorumThresholds", "type": "bytes"},
+		{"name": "quorumSignedPercentages", "type": "bytes"}
+	]
+}]`
+
+// OnchainBlobConfirmation is the blob confirmation returned by the getBlobConfirmation view function
+type OnchainBlobConfirmation struct {
+	Confirmed               bool
+	BatchId                 uint32
+	BlobCount               uint32
+	BatchRoot               [32]byte
+	ReferenceBlockNumber    uint32
+	ConfirmationBlockNumber uint32
+	ConfirmationTxnHash     [32]byte
+	SignatoryRecordHash     [32]byte
+	CommitmentRoot          []byte
+	DataLength              uint32
+	InclusionProof          []byte
+	Fee                     []byte
+	QuorumNumbers           []byte
+	AdversaryThresholds     []byte
+	QuorumThresholds        []byte
+	QuorumSignedPercentages []byte
+}
+
+// BlobConfirmationReader reads blob confirmations from chain. It returns nil if the blob is not confirmed.
+type BlobConfirmationReader interface {
+	GetBlobConfirmation(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*OnchainBlobConfirmation, error)
+}
+
+type contractBlobConfirmationReader struct {
+	caller  bind.ContractCaller
+	address eth_common.Address
+	abi     abi.ABI
+}
+
+var _ BlobConfirmationReader = (*contractBlobConfirmationReader)(nil)
+
+// NewBlobConfirmationReader creates a BlobConfirmationReader calling the contract at the given address
+func NewBlobConfirmationReader(caller bind.ContractCaller, address eth_common.Address) (BlobConfirmationReader, error) {
+	parsed, err := abi.JSON(strings.NewReader(blobConfirmationABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse blob confirmation abi: %w", err)
+	}
+	return &contractBlobConfirmationReader{
+		caller:  caller,
+		address: address,
+		abi:     parsed,
+	}, nil
+}
+
+func (r *contractBlobConfirmationReader) GetBlobConfirmation(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*OnchainBlobConfirmation, error) {
+	input, err := r.abi.Pack("getBlobConfirmation", batchHeaderHash, blobIndex)
+	if err != nil {
+		return nil, err
+	}
+	output, err := r.caller.CallContract(ctx, ethereum.CallMsg{To: &r.address, Data: input}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getBlobConfirmation: %w", err)
+	}
+
+	confirmation := new(OnchainBlobConfirmation)
+	if err := r.abi.UnpackIntoInterface(confirmation, "getBlobConfirmation", output); err != nil {
+		return nil, fmt.Errorf("failed to unpack blob confirmation: %w", err)
+	}
+	if !confirmation.Confirmed {
+		return nil, nil
+	}
+	return confirmation, nil
+}
+
+// WithOnchainFallback makes GetBlobStatus read the blob confirmation from chain when the blob is not found locally
+func WithOnchainFallback(reader BlobConfirmationReader) ServerOption {
+	return func(s *DispersalServer) {
+		s.confirmationReader = reader
+	}
+}
+
+// getMetadataFromChain reconstructs the metadata of a confirmed blob from its on-chain confirmation
+func (s *DispersalServer) getMetadataFromChain(ctx context.Context, metadataKey disperser.BlobKey, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, error) {
+	confirmation, err := s.confirmationReader.GetBlobConfirmation(ctx, batchHeaderHash, blobIndex)
+	if err != nil || confirmation == nil {
+		return nil, err
+	}
+
+	numQuorums := len(confirmation.QuorumNumbers)
+	if len(confirmation.AdversaryThresholds) != numQuorums || len(confirmation.QuorumThresholds) != numQuorums || len(confirmation.QuorumSignedPercentages) != numQuorums {
+		return nil, fmt.Errorf("invalid on-chain confirmation: quorum fields have mismatched lengths")
+	}
+	quorumResults := make(map[core.QuorumID]*core.QuorumResult, numQuorums)
+	blobQuorumInfos := make([]*core.BlobQuorumInfo, numQuorums)
+	for i, quorumID := range confirmation.QuorumNumbers {
+		quorumResults[quorumID] = &core.QuorumResult{
+			QuorumID:      quorumID,
+			PercentSigned: confirmation.QuorumSignedPercentages[i],
+		}
+		blobQuorumInfos[i] = &core.BlobQuorumInfo{
+			SecurityParam: core.SecurityParam{
+				QuorumID:           quorumID,
+				AdversaryThreshold: confirmation.AdversaryThresholds[i],
+				QuorumThreshold:    confirmation.QuorumThresholds[i],
+			},
+		}
+	}
+
+	metadata := &disperser.BlobMetadata{
+		BlobHash:     metadataKey.BlobHash,
+		MetadataHash: metadataKey.MetadataHash,
+		BlobStatus:   disperser.Confirmed,
+		ConfirmationInfo: &disperser.ConfirmationInfo{
+			BatchHeaderHash:         batchHeaderHash,
+			BlobIndex:               blobIndex,
+			BlobCount:               confirmation.BlobCount,
+			SignatoryRecordHash:     confirmation.SignatoryRecordHash,
+			ReferenceBlockNumber:    confirmation.ReferenceBlockNumber,
+			BatchRoot:               confirmation.BatchRoot[:],
+			BlobInclusionProof:      confirmation.InclusionProof,
+			CommitmentRoot:          confirmation.CommitmentRoot,
+			Length:                  confirmation.DataLength,
+			BatchID:                 confirmation.BatchId,
+			ConfirmationTxnHash:     confirmation.ConfirmationTxnHash,
+			ConfirmationBlockNumber: confirmation.ConfirmationBlockNumber,
+			Fee:                     confirmation.Fee,
+			QuorumResults:           quorumResults,
+			BlobQuorumInfos:         blobQuorumInfos,
+		},
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if metadata.ConfirmationInfo.ConfirmationBlockNumber <= s.latestFinalizedBlock {
+		metadata.BlobStatus = disperser.Finalized
+	}
+	return metadata, nil
+}
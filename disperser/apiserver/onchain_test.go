@@ -0,0 +1,148 @@
+package apiserver_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const getBlobConfirmationABI = `[{"name":"getBlobConfirmation","type":"function","stateMutability":"view",
+	"inputs":[{"name":"batchHeaderHash","type":"bytes32"},{"name":"blobIndex","type":"uint32"}],
+	"outputs":[{"name":"confirmed","type":"bool"},{"name":"batchId","type":"uint32"},{"name":"blobCount","type":"uint32"},
+	{"name":"batchRoot","type":"bytes32"},{"name":"referenceBlockNumber","type":"uint32"},{"name":"confirmationBlockNumber","type":"uint32"},
+	{"name":"confirmationTxnHash","type":"bytes32"},{"name":"signatoryRecordHash","type":"bytes32"},{"name":"commitmentRoot","type":"bytes"},
+	{"name":"dataLength","type":"uint32"},{"name":"inclusionProof","type":"bytes"},{"name":"fee","type":"bytes"},{"name":"quorumNumbers","type":"bytes"},
+	{"name":"adversaryThresholds","type":"bytes"},{"name":"quorumThresholds","type":"bytes"},{"name":"quorumSignedPercentages","type":"bytes"}]}]`
+
+// mockConfirmationContract serves getBlobConfirmation for the blobs it knows about
+type mockConfirmationContract struct {
+	t             *testing.T
+	abi           abi.ABI
+	confirmations map[[32]byte]map[uint32]*disperser.ConfirmationInfo
+	calls         int
+}
+
+func newMockConfirmationContract(t *testing.T) *mockConfirmationContract {
+	parsed, err := abi.JSON(strings.NewReader(getBlobConfirmationABI))
+	assert.NoError(t, err)
+	return &mockConfirmationContract{
+		t:             t,
+		abi:           parsed,
+		confirmations: make(map[[32]byte]map[uint32]*disperser.ConfirmationInfo),
+	}
+}
+
+func (c *mockConfirmationContract) CodeAt(ctx context.Context, contract eth_common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *mockConfirmationContract) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	method, err := c.abi.MethodById(call.Data[:4])
+	assert.NoError(c.t, err)
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	assert.NoError(c.t, err)
+	batchHeaderHash := args[0].([32]byte)
+	blobIndex := args[1].(uint32)
+
+	info, ok := c.confirmations[batchHeaderHash][blobIndex]
+	if !ok {
+		return method.Outputs.Pack(false, uint32(0), uint32(0), [32]byte{}, uint32(0), uint32(0), [32]byte{}, [32]byte{}, []byte{}, uint32(0), []byte{}, []byte{}, []byte{}, []byte{}, []byte{}, []byte{})
+	}
+	var quorumNumbers, adversaryThresholds, quorumThresholds, signedPercentages []byte
+	for _, quorumInfo := range info.BlobQuorumInfos {
+		quorumNumbers = append(quorumNumbers, quorumInfo.QuorumID)
+		adversaryThresholds = append(adversaryThresholds, quorumInfo.AdversaryThreshold)
+		quorumThresholds = append(quorumThresholds, quorumInfo.QuorumThreshold)
+		signedPercentages = append(signedPercentages, info.QuorumResults[quorumInfo.QuorumID].PercentSigned)
+	}
+	return method.Outputs.Pack(true, info.BatchID, info.BlobCount, [32]byte(info.BatchRoot), info.ReferenceBlockNumber, info.ConfirmationBlockNumber,
+		[32]byte(info.ConfirmationTxnHash), info.SignatoryRecordHash, info.CommitmentRoot, info.Length, info.BlobInclusionProof, info.Fee,
+		quorumNumbers, adversaryThresholds, quorumThresholds, signedPercentages)
+}
+
+func TestGetBlobStatusOnchainFallback(t *testing.T) {
+	localServer, localStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	// the blob is dispersed and confirmed through the local disperser
+	key, err := localStore.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 25, QuorumThreshold: 50}},
+		},
+		Data: []byte("dispersed elsewhere"),
+	}, 0)
+	assert.NoError(t, err)
+	metadata, err := localStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	batchHeaderHash := [32]byte{7}
+	confirmationInfo := &disperser.ConfirmationInfo{
+		BatchHeaderHash:         batchHeaderHash,
+		BlobIndex:               2,
+		BlobCount:               3,
+		SignatoryRecordHash:     [32]byte{8},
+		ReferenceBlockNumber:    90,
+		BatchRoot:               eth_common.Hash{9}.Bytes(),
+		BlobInclusionProof:      []byte{1, 2, 3},
+		CommitmentRoot:          []byte{4, 5},
+		Length:                  1,
+		BatchID:                 11,
+		ConfirmationTxnHash:     eth_common.Hash{12},
+		ConfirmationBlockNumber: 100,
+		Fee:                     []byte{0},
+		QuorumResults:           map[core.QuorumID]*core.QuorumResult{0: {QuorumID: 0, PercentSigned: 80}},
+		BlobQuorumInfos: []*core.BlobQuorumInfo{{
+			SecurityParam: core.SecurityParam{QuorumID: 0, AdversaryThreshold: 25, QuorumThreshold: 50},
+		}},
+	}
+	_, err = localStore.MarkBlobConfirmed(ctx, metadata, confirmationInfo)
+	assert.NoError(t, err)
+
+	contract := newMockConfirmationContract(t)
+	contract.confirmations[batchHeaderHash] = map[uint32]*disperser.ConfirmationInfo{2: confirmationInfo}
+	reader, err := apiserver.NewBlobConfirmationReader(contract, eth_common.HexToAddress("0x1"))
+	assert.NoError(t, err)
+
+	// another disperser without the blob in its store
+	logger := &mock.Logger{}
+	metrics := disperser.NewMetrics("9100", logger)
+	remoteServer := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{}, nil,
+		apiserver.WithOnchainFallback(reader))
+
+	request := &pb.BlobStatusRequest{RequestId: []byte(key.String()), BatchHeaderHash: batchHeaderHash[:], BlobIndex: 2}
+	expected, err := localServer.GetBlobStatus(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_CONFIRMED, expected.GetStatus())
+	// the status history is only kept in the store of the disperser, not on chain
+	expected.History = nil
+
+	reply, err := remoteServer.GetBlobStatus(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.String(), reply.String())
+	assert.Equal(t, 1, contract.calls)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.OnchainFallbackHits))
+
+	// blobs which are not confirmed on chain are still not found
+	_, err = remoteServer.GetBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: []byte(key.String()), BatchHeaderHash: batchHeaderHash[:], BlobIndex: 1})
+	assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+	assert.Equal(t, 2, contract.calls)
+
+	// the contract is not called without the location of the blob
+	_, err = remoteServer.GetBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: []byte(key.String())})
+	assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+	assert.Equal(t, 2, contract.calls)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.OnchainFallbackHits))
+}
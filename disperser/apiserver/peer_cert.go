@@ -0,0 +1,43 @@
+package apiserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// getPeerCertFields returns the audit fields of the certificate presented by the caller.
+// It returns nil if the caller didn't present a certificate, e.g. when mTLS is not enabled.
+func getPeerCertFields(ctx context.Context) map[string]string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil
+	}
+
+	cert := tlsInfo.State.PeerCertificates[0]
+	fields := map[string]string{
+		"commonName": cert.Subject.CommonName,
+	}
+	if len(cert.Subject.Organization) > 0 {
+		fields["organization"] = strings.Join(cert.Subject.Organization, ",")
+	}
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses)+len(cert.URIs))
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	if len(sans) > 0 {
+		fields["sans"] = strings.Join(sans, ",")
+	}
+	return fields
+}
@@ -0,0 +1,94 @@
+package apiserver_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/url"
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// recordingLogger records the context of the info logs
+type recordingLogger struct {
+	mock.Logger
+	infos [][]interface{}
+}
+
+// WithField returns the logger itself, the context of the fields isn't recorded
+func (l *recordingLogger) WithField(key string, value interface{}) common.Logger {
+	return l
+}
+
+func (l *recordingLogger) WithFields(fields map[string]interface{}) common.Logger {
+	return l
+}
+
+func (l *recordingLogger) Info(msg string, ctx ...interface{}) {
+	l.infos = append(l.infos, ctx)
+}
+
+func TestDisperseBlobPeerCertFields(t *testing.T) {
+	logger := &recordingLogger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, &mock.Logger{})
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+
+	uri, err := url.Parse("spiffe://example.org/rollup")
+	assert.NoError(t, err)
+	cert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "rollup-1",
+			Organization: []string{"Example", "Rollups"},
+		},
+		DNSNames:       []string{"rollup.example.org"},
+		EmailAddresses: []string{"ops@example.org"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{uri},
+	}
+	ctx, _ := newTestContext()
+	ctx = peer.NewContext(ctx, &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 51001},
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+		},
+	})
+
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("with certificate"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	key, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "rollup-1", metadata.RequestMetadata.AccountID)
+
+	assert.Len(t, logger.infos, 1)
+	assert.Equal(t, []interface{}{"key", key.String(), "peerCert", map[string]string{
+		"commonName":   "rollup-1",
+		"organization": "Example,Rollups",
+		"sans":         "rollup.example.org,ops@example.org,10.0.0.1,spiffe://example.org/rollup",
+	}}, logger.infos[0])
+
+	// callers without a certificate are logged and accounted as before
+	logger.infos = nil
+	ctx, _ = newTestContext()
+	reply, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("without certificate"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	key, err = disperser.ParseBlobKey(string(reply.GetRequestId()))
+	assert.NoError(t, err)
+	metadata, err = blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Empty(t, metadata.RequestMetadata.AccountID)
+	assert.Equal(t, [][]interface{}{{"key", key.String()}}, logger.infos)
+}
@@ -1,7 +1,10 @@
 package apiserver
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
 
 	"github.com/0glabs/0g-data-avail/common"
@@ -32,6 +35,8 @@ type QuorumRateInfo struct {
 type RateConfig struct {
 	QuorumRateInfos map[core.QuorumID]QuorumRateInfo
 	ClientIPHeader  string
+	// CIDRRates override the per account rates of all the quorums for the requesters in their blocks, see ParseCIDRRates
+	CIDRRates []CIDRRateEntry
 }
 
 func CLIFlags(envPrefix string) []cli.Flag {
@@ -117,3 +122,70 @@ func ReadCLIConfig(c *cli.Context) (RateConfig, error) {
 		ClientIPHeader:  c.String(ClientIPHeaderFlagName),
 	}, nil
 }
+
+// RateConfigFile is the JSON form of a RateConfig, as posted to the admin server or written to the rate config file.
+// The blob rates are in blobs/sec and the CIDR rates in the form of the cidr-rates flag.
+type RateConfigFile struct {
+	Quorums        []QuorumRateFile `json:"quorums"`
+	ClientIPHeader string           `json:"client_ip_header"`
+	CIDRRates      []string         `json:"cidr_rates"`
+}
+
+type QuorumRateFile struct {
+	QuorumID              core.QuorumID `json:"quorum_id"`
+	TotalUnauthByteRate   int64         `json:"total_unauth_byte_rate"`
+	PerUserUnauthByteRate int64         `json:"per_user_unauth_byte_rate"`
+	TotalUnauthBlobRate   float64       `json:"total_unauth_blob_rate"`
+	PerUserUnauthBlobRate float64       `json:"per_user_unauth_blob_rate"`
+}
+
+// ParseRateConfigJSON parses and validates a RateConfigFile
+func ParseRateConfigJSON(data []byte) (RateConfig, error) {
+	var file RateConfigFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return RateConfig{}, fmt.Errorf("invalid rate config: %w", err)
+	}
+
+	quorumRateInfos := make(map[core.QuorumID]QuorumRateInfo, len(file.Quorums))
+	for _, quorum := range file.Quorums {
+		if _, ok := quorumRateInfos[quorum.QuorumID]; ok {
+			return RateConfig{}, fmt.Errorf("duplicate rates of quorum %d", quorum.QuorumID)
+		}
+		for _, byteRate := range []int64{quorum.TotalUnauthByteRate, quorum.PerUserUnauthByteRate} {
+			if byteRate < 0 || byteRate > math.MaxUint32 {
+				return RateConfig{}, fmt.Errorf("byte rate of quorum %d out of range: %d", quorum.QuorumID, byteRate)
+			}
+		}
+		for _, blobRate := range []float64{quorum.TotalUnauthBlobRate, quorum.PerUserUnauthBlobRate} {
+			if blobRate < 0 || blobRate*blobRateMultiplier > math.MaxUint32 {
+				return RateConfig{}, fmt.Errorf("blob rate of quorum %d out of range: %v", quorum.QuorumID, blobRate)
+			}
+		}
+		quorumRateInfos[quorum.QuorumID] = QuorumRateInfo{
+			TotalUnauthThroughput:   common.RateParam(quorum.TotalUnauthByteRate),
+			PerUserUnauthThroughput: common.RateParam(quorum.PerUserUnauthByteRate),
+			TotalUnauthBlobRate:     common.RateParam(quorum.TotalUnauthBlobRate * blobRateMultiplier),
+			PerUserUnauthBlobRate:   common.RateParam(quorum.PerUserUnauthBlobRate * blobRateMultiplier),
+		}
+	}
+	cidrRates, err := ParseCIDRRates(file.CIDRRates)
+	if err != nil {
+		return RateConfig{}, err
+	}
+
+	config := RateConfig{
+		QuorumRateInfos: quorumRateInfos,
+		ClientIPHeader:  file.ClientIPHeader,
+		CIDRRates:       cidrRates,
+	}
+	return config, ValidateRateConfig(config)
+}
+
+// ValidateRateConfig checks the config has the rates of at least one quorum and its CIDR blocks are valid
+func ValidateRateConfig(config RateConfig) error {
+	if len(config.QuorumRateInfos) == 0 {
+		return errors.New("rate config has no quorum")
+	}
+	_, err := newCIDRRateTable(config.CIDRRates)
+	return err
+}
@@ -0,0 +1,131 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetRateLimitStatus returns the account buckets of the caller for the requested quorums, or for all the rate limited
+// quorums. The buckets are inspected without being consumed, but the calls are limited to RateLimitStatusRate per second
+// so that the endpoint can't be used to scrape the rate limiter.
+func (s *DispersalServer) GetRateLimitStatus(ctx context.Context, req *pb.GetRateLimitStatusRequest) (*pb.GetRateLimitStatusReply, error) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("GetRateLimitStatus", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+
+	if s.ratelimiter == nil {
+		return nil, status.Error(codes.Unimplemented, "rate limiting is disabled")
+	}
+
+	rateConfig, cidrRates := s.getRateConfig()
+	origin, err := common.GetClientAddress(ctx, rateConfig.ClientIPHeader, 2, true)
+	if err != nil {
+		return nil, err
+	}
+	accountID := getPeerCertFields(ctx)["commonName"]
+	if accountID == "" {
+		accountID = origin
+	}
+
+	if s.config.RateLimitStatusRate > 0 {
+		rate := common.RateParam(s.config.RateLimitStatusRate * blobRateMultiplier)
+		allowed, _, err := s.ratelimiter.AllowRequest(ctx, fmt.Sprintf("%s:rate-limit-status", accountID), blobRateMultiplier, rate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check rate limit: %w", err)
+		}
+		if !allowed {
+			s.metrics.HandleRateLimitRejectedRequest(rateLimitReasonAccountRequests, false, 0, "GetRateLimitStatus")
+			return nil, errAccountRateLimit
+		}
+	}
+
+	quorumIDs := make([]core.QuorumID, 0, len(rateConfig.QuorumRateInfos))
+	if len(req.GetQuorumIds()) == 0 {
+		for quorumID := range rateConfig.QuorumRateInfos {
+			quorumIDs = append(quorumIDs, quorumID)
+		}
+		sort.Slice(quorumIDs, func(i, j int) bool { return quorumIDs[i] < quorumIDs[j] })
+	} else {
+		for _, quorumID := range req.GetQuorumIds() {
+			if quorumID > math.MaxUint8 {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid quorum_id: %d", quorumID)
+			}
+			quorumIDs = append(quorumIDs, core.QuorumID(quorumID))
+		}
+	}
+
+	reply := &pb.GetRateLimitStatusReply{}
+	for _, quorumID := range quorumIDs {
+		rates, ok := rateConfig.QuorumRateInfos[quorumID]
+		if !ok {
+			continue
+		}
+		rates = cidrRates.accountRates(accountID, rates)
+
+		buckets := []struct {
+			requesterID string
+			kind        string
+			rate        float64
+		}{
+			{fmt.Sprintf("%s:%d-bytes", accountID, quorumID), "bytes", float64(rates.PerUserUnauthThroughput)},
+			{fmt.Sprintf("%s:%d-blobs", accountID, quorumID), "blobs", float64(rates.PerUserUnauthBlobRate) / blobRateMultiplier},
+		}
+		for _, bucket := range buckets {
+			if bucket.rate == 0 {
+				continue
+			}
+			bucketStatus, err := s.ratelimiter.InspectBucket(ctx, bucket.requesterID)
+			if err != nil {
+				s.logger.Error("[apiserver] failed to inspect rate bucket", "requesterID", bucket.requesterID, "err", err)
+				return nil, status.Error(codes.Internal, "failed to get rate limit status")
+			}
+			reply.Buckets = append(reply.Buckets, getRateBucketStatus(quorumID, bucket.kind, bucket.rate, bucketStatus))
+			setGlobalRateParams(reply, bucketStatus.GlobalRateParams)
+		}
+	}
+	return reply, nil
+}
+
+func getRateBucketStatus(quorumID core.QuorumID, kind string, rate float64, bucketStatus *common.RateBucketStatus) *pb.RateBucketStatus {
+	reply := &pb.RateBucketStatus{
+		QuorumId:              uint32(quorumID),
+		Kind:                  kind,
+		Rate:                  rate,
+		BucketLevelsMs:        durationsToMs(bucketStatus.BucketLevels),
+		RequestBucketLevelsMs: durationsToMs(bucketStatus.RequestBucketLevels),
+		FillPercentages:       bucketStatus.FillPercentages(),
+	}
+	if !bucketStatus.LastRequestTime.IsZero() {
+		reply.LastRequestTimeMs = uint64(bucketStatus.LastRequestTime.UnixMilli())
+	}
+	return reply
+}
+
+func setGlobalRateParams(reply *pb.GetRateLimitStatusReply, params common.GlobalRateParams) {
+	reply.BucketSizesMs = durationsToMs(params.BucketSizes)
+	reply.Multipliers = params.Multipliers
+	reply.CountFailed = params.CountFailed
+	reply.RequestsPerSecond = params.RequestsPerSecond
+}
+
+func durationsToMs(durations []time.Duration) []uint64 {
+	if len(durations) == 0 {
+		return nil
+	}
+	ms := make([]uint64, len(durations))
+	for i, d := range durations {
+		ms[i] = uint64(d.Milliseconds())
+	}
+	return ms
+}
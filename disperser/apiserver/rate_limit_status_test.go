@@ -0,0 +1,110 @@
+package apiserver_test
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newRateLimitStatusTestServer(t *testing.T, config disperser.ServerConfig) *apiserver.DispersalServer {
+	logger := &mock.Logger{}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+	assert.NoError(t, err)
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{10 * time.Second, time.Minute},
+		Multipliers: []float32{1, 2},
+	}, bucketStore, nil, nil, nil, logger)
+	rateConfig := apiserver.RateConfig{QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+		0: {TotalUnauthThroughput: 1_000_000, TotalUnauthBlobRate: 1000 * 1e6, PerUserUnauthThroughput: 100, PerUserUnauthBlobRate: 1e6},
+		1: {TotalUnauthThroughput: 1_000_000, TotalUnauthBlobRate: 1000 * 1e6, PerUserUnauthThroughput: 100},
+	}}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	return apiserver.NewDispersalServer(config, blobStore, logger, disperser.NewMetrics("9100", logger), ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+}
+
+func TestGetRateLimitStatus(t *testing.T) {
+	server := newRateLimitStatusTestServer(t, disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	reply, err := server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{10_000, 60_000}, reply.GetBucketSizesMs())
+	assert.Equal(t, []float32{1, 2}, reply.GetMultipliers())
+	// quorum 1 has no per user blob rate
+	assert.Len(t, reply.GetBuckets(), 3)
+	for _, bucket := range reply.GetBuckets() {
+		assert.Equal(t, []uint64{10_000, 60_000}, bucket.GetBucketLevelsMs())
+		assert.Equal(t, []float64{100, 100}, bucket.GetFillPercentages())
+		assert.Equal(t, uint64(0), bucket.GetLastRequestTimeMs())
+	}
+	assert.Equal(t, "bytes", reply.GetBuckets()[0].GetKind())
+	assert.Equal(t, float64(100), reply.GetBuckets()[0].GetRate())
+	assert.Equal(t, "blobs", reply.GetBuckets()[1].GetKind())
+	assert.Equal(t, float64(1), reply.GetBuckets()[1].GetRate())
+	assert.Equal(t, uint32(1), reply.GetBuckets()[2].GetQuorumId())
+
+	// a blob of 500 bytes consumes 5s of the bytes bucket
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+		Data:           make([]byte, 500),
+		SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+	})
+	assert.NoError(t, err)
+	reply, err = server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{QuorumIds: []uint32{0}})
+	assert.NoError(t, err)
+	assert.Len(t, reply.GetBuckets(), 2)
+	bytesBucket := reply.GetBuckets()[0]
+	assert.InDelta(t, 50, bytesBucket.GetFillPercentages()[0], 1)
+	assert.InDelta(t, 5_000, bytesBucket.GetBucketLevelsMs()[0], 100)
+	assert.NotZero(t, bytesBucket.GetLastRequestTimeMs())
+
+	// inspecting the buckets doesn't consume them
+	for i := 0; i < 5; i++ {
+		_, err = server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{})
+		assert.NoError(t, err)
+	}
+	reply, err = server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{QuorumIds: []uint32{0}})
+	assert.NoError(t, err)
+	assert.InDelta(t, bytesBucket.GetFillPercentages()[0], reply.GetBuckets()[0].GetFillPercentages()[0], 1)
+
+	_, err = server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{QuorumIds: []uint32{256}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetRateLimitStatusIsRateLimited(t *testing.T) {
+	// each call consumes 6.67s of the 10s bucket
+	server := newRateLimitStatusTestServer(t, disperser.ServerConfig{RateLimitStatusRate: 0.15})
+	ctx, _ := newTestContext()
+
+	_, err := server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{})
+	assert.NoError(t, err)
+	_, err = server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{})
+	assert.ErrorContains(t, err, "account limit")
+
+	// the dispersals are not affected
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+		Data:           make([]byte, 100),
+		SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+	})
+	assert.NoError(t, err)
+}
+
+func TestGetRateLimitStatusDisabled(t *testing.T) {
+	server := newTestServer(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	_, err := server.GetRateLimitStatus(ctx, &pb.GetRateLimitStatusRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,77 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// readinessRetryInterval is the interval to re-run a failed readiness check at startup
+const readinessRetryInterval = 10 * time.Second
+
+var errReadinessNotChecked = errors.New("readiness not checked yet")
+
+// ReadinessCheck checks whether the dependencies of the server (e.g. the schema of the blob metadata table) are
+// ready to serve requests
+type ReadinessCheck func(ctx context.Context) error
+
+// WithReadinessCheck sets the readiness check of the DispersalServer, which gates serving at startup and is
+// reported by the readiness endpoint. It is skipped if SkipSchemaValidation is set in the server config.
+func WithReadinessCheck(check ReadinessCheck) ServerOption {
+	return func(s *DispersalServer) {
+		s.readinessCheck = check
+		s.readinessErr = errReadinessNotChecked
+	}
+}
+
+// CheckReadiness runs the readiness check and records its result for the readiness endpoint
+func (s *DispersalServer) CheckReadiness(ctx context.Context) error {
+	if s.readinessCheck == nil || s.config.SkipSchemaValidation {
+		return nil
+	}
+	err := s.readinessCheck(ctx)
+
+	s.readinessMu.Lock()
+	defer s.readinessMu.Unlock()
+	s.readinessErr = err
+	return err
+}
+
+// ReadyzHandler returns the handler of the readiness endpoint, which responds with 503 until the readiness check passes
+func (s *DispersalServer) ReadyzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := s.readiness(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+func (s *DispersalServer) readiness() error {
+	if s.readinessCheck == nil || s.config.SkipSchemaValidation {
+		return nil
+	}
+	s.readinessMu.RLock()
+	defer s.readinessMu.RUnlock()
+	return s.readinessErr
+}
+
+// waitUntilReady blocks until the readiness check passes or the context is done
+func (s *DispersalServer) waitUntilReady(ctx context.Context) error {
+	for {
+		err := s.CheckReadiness(ctx)
+		if err == nil {
+			return nil
+		}
+		s.logger.Error("[apiserver] readiness check failed, not serving requests until it passes", "err", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(readinessRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package apiserver_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTableName = "test-BlobMetadata"
+
+type mockTableDescriber struct {
+	indexes []types.GlobalSecondaryIndexDescription
+}
+
+func (d *mockTableDescriber) DescribeTable(ctx context.Context, tableName string) (*types.TableDescription, error) {
+	return &types.TableDescription{GlobalSecondaryIndexes: d.indexes}, nil
+}
+
+// newMockTableDescriber describes a table with the expected schema, without the given indexes
+func newMockTableDescriber(missingIndexes ...string) *mockTableDescriber {
+	missing := make(map[string]bool)
+	for _, index := range missingIndexes {
+		missing[index] = true
+	}
+	describer := &mockTableDescriber{}
+	for _, index := range blobstore.GenerateTableSchema(testTableName, 10, 10).GlobalSecondaryIndexes {
+		if missing[*index.IndexName] {
+			continue
+		}
+		describer.indexes = append(describer.indexes, types.GlobalSecondaryIndexDescription{
+			IndexName: index.IndexName,
+			KeySchema: index.KeySchema,
+		})
+	}
+	return describer
+}
+
+func newTestServerWithTableDescriber(config disperser.ServerConfig, describer blobstore.TableDescriber) *apiserver.DispersalServer {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	return apiserver.NewDispersalServer(config, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil,
+		apiserver.WithReadinessCheck(func(ctx context.Context) error {
+			return blobstore.ValidateTableSchema(ctx, describer, testTableName)
+		}))
+}
+
+func getReadyz(server *apiserver.DispersalServer) int {
+	recorder := httptest.NewRecorder()
+	server.ReadyzHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	return recorder.Code
+}
+
+func TestReadyzMissingIndex(t *testing.T) {
+	describer := newMockTableDescriber("UploadIndex")
+	server := newTestServerWithTableDescriber(disperser.ServerConfig{}, describer)
+
+	// not ready before the first check
+	assert.Equal(t, http.StatusServiceUnavailable, getReadyz(server))
+
+	err := server.CheckReadiness(context.Background())
+	assert.ErrorContains(t, err, "UploadIndex")
+	assert.Equal(t, http.StatusServiceUnavailable, getReadyz(server))
+
+	// the index is created
+	*describer = *newMockTableDescriber()
+	assert.NoError(t, server.CheckReadiness(context.Background()))
+	assert.Equal(t, http.StatusOK, getReadyz(server))
+}
+
+func TestReadyzUnexpectedKeySchema(t *testing.T) {
+	describer := newMockTableDescriber()
+	describer.indexes[0].KeySchema = describer.indexes[0].KeySchema[:1]
+	server := newTestServerWithTableDescriber(disperser.ServerConfig{}, describer)
+
+	assert.ErrorContains(t, server.CheckReadiness(context.Background()), "unexpected key schema")
+	assert.Equal(t, http.StatusServiceUnavailable, getReadyz(server))
+}
+
+func TestReadyzSkipSchemaValidation(t *testing.T) {
+	server := newTestServerWithTableDescriber(disperser.ServerConfig{SkipSchemaValidation: true}, newMockTableDescriber("StatusIndex", "BatchIndex"))
+
+	assert.NoError(t, server.CheckReadiness(context.Background()))
+	assert.Equal(t, http.StatusOK, getReadyz(server))
+
+	// no readiness check configured
+	assert.Equal(t, http.StatusOK, getReadyz(newTestServer(disperser.ServerConfig{})))
+}
@@ -0,0 +1,48 @@
+package apiserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// recentBlobCacheSize is the maximum number of blobs tracked by the RecentBlobCache
+const recentBlobCacheSize = 100_000
+
+type recentBlob struct {
+	accountID string
+	blobHash  disperser.BlobHash
+}
+
+// RecentBlobCache tracks the blobs submitted by each account within the dedup window, so that the same
+// account submitting the same content again can be rejected without storing it.
+// Different accounts submitting the same content are tracked separately.
+type RecentBlobCache struct {
+	blobs *expirable.LRU[recentBlob, disperser.BlobKey]
+}
+
+// NewRecentBlobCache creates a RecentBlobCache which forgets the blobs after the window
+func NewRecentBlobCache(window time.Duration) *RecentBlobCache {
+	return &RecentBlobCache{
+		blobs: expirable.NewLRU[recentBlob, disperser.BlobKey](recentBlobCacheSize, nil, window),
+	}
+}
+
+// Get returns the key of the blob with the given data submitted by the account within the window, if any
+func (c *RecentBlobCache) Get(accountID string, data []byte) (disperser.BlobKey, bool) {
+	return c.blobs.Get(recentBlob{accountID: accountID, blobHash: recentBlobHash(data)})
+}
+
+// Add records the key of the blob with the given data submitted by the account
+func (c *RecentBlobCache) Add(accountID string, data []byte, key disperser.BlobKey) {
+	c.blobs.Add(recentBlob{accountID: accountID, blobHash: recentBlobHash(data)}, key)
+}
+
+// recentBlobHash is the blob hash of the blob store, i.e. the hex encoded sha256 of the data
+func recentBlobHash(data []byte) disperser.BlobHash {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
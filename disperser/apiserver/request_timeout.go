@@ -0,0 +1,84 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"google.golang.org/grpc"
+)
+
+// deadlineCappedMethods are the methods whose timeout also applies to the requests with a later deadline,
+// so that a slow upload to the storage doesn't hold the request for as long as the caller allows
+var deadlineCappedMethods = map[string]bool{
+	"DisperseBlob": true,
+}
+
+// requestTimeouts bounds the time the requests are handled for
+type requestTimeouts struct {
+	defaultTimeout time.Duration
+	// perMethod maps the method names, e.g. DisperseBlob, to their timeout, which overrides the default timeout
+	perMethod map[string]time.Duration
+	// logger logs the requests without a deadline, it may be nil
+	logger common.Logger
+}
+
+// TimeoutInterceptor handles the requests without a deadline with a deadline of defaultTimeout, so that they can't hold
+// a handler forever. DisperseBlob is handled with the earlier of the deadline of the caller and defaultTimeout.
+func TimeoutInterceptor(defaultTimeout time.Duration) grpc.UnaryServerInterceptor {
+	return newRequestTimeouts(defaultTimeout, nil, nil).intercept
+}
+
+func newRequestTimeouts(defaultTimeout time.Duration, perMethod map[string]time.Duration, logger common.Logger) *requestTimeouts {
+	return &requestTimeouts{defaultTimeout: defaultTimeout, perMethod: perMethod, logger: logger}
+}
+
+// timeout returns the timeout of the method, the requests are not bounded if 0
+func (t *requestTimeouts) timeout(method string) time.Duration {
+	if timeout, ok := t.perMethod[method]; ok {
+		return timeout
+	}
+	return t.defaultTimeout
+}
+
+func (t *requestTimeouts) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method := path.Base(info.FullMethod)
+	timeout := t.timeout(method)
+	if timeout <= 0 {
+		return handler(ctx, req)
+	}
+
+	if _, ok := ctx.Deadline(); ok {
+		if !deadlineCappedMethods[method] {
+			return handler(ctx, req)
+		}
+	} else if t.logger != nil {
+		ip, _ := peerIP(ctx)
+		t.logger.Warn("[apiserver] applying the default timeout to a request without deadline", "method", method, "timeout", timeout, "ip", ip)
+	}
+
+	// the earlier deadline is kept if the request already has one
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return handler(ctx, req)
+}
+
+// ParseMethodTimeouts parses the timeouts of the methods given in the form of method=duration, e.g. DisperseBlob=10s
+func ParseMethodTimeouts(timeouts []string) (map[string]time.Duration, error) {
+	perMethod := make(map[string]time.Duration, len(timeouts))
+	for _, entry := range timeouts {
+		method, value, ok := strings.Cut(entry, "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid method timeout %q, expected method=duration", entry)
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid duration in method timeout %q", entry)
+		}
+		perMethod[method] = timeout
+	}
+	return perMethod, nil
+}
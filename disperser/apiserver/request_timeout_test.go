@@ -0,0 +1,60 @@
+package apiserver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// deadlineHandler returns the time left until the deadline of the request, 0 if it has none
+func deadlineHandler(ctx context.Context, req interface{}) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return time.Duration(0), nil
+	}
+	return time.Until(deadline), nil
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	interceptor := apiserver.TimeoutInterceptor(time.Minute)
+	disperseInfo := &grpc.UnaryServerInfo{FullMethod: "/disperser.Disperser/DisperseBlob"}
+	statusInfo := &grpc.UnaryServerInfo{FullMethod: "/disperser.Disperser/GetBlobStatus"}
+
+	// the requests without a deadline get the default timeout
+	left, err := interceptor(context.Background(), nil, statusInfo, deadlineHandler)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, left.(time.Duration), float64(time.Second))
+
+	// the deadline of the caller is kept
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	left, err = interceptor(ctx, nil, statusInfo, deadlineHandler)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour, left.(time.Duration), float64(time.Second))
+
+	// except for DisperseBlob, which is bounded by the earlier deadline
+	left, err = interceptor(ctx, nil, disperseInfo, deadlineHandler)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, left.(time.Duration), float64(time.Second))
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), time.Second)
+	defer shortCancel()
+	left, err = interceptor(shortCtx, nil, disperseInfo, deadlineHandler)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, left.(time.Duration), time.Second)
+}
+
+func TestParseMethodTimeouts(t *testing.T) {
+	timeouts, err := apiserver.ParseMethodTimeouts([]string{"DisperseBlob=10s", "RetrieveBlob=2m"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"DisperseBlob": 10 * time.Second, "RetrieveBlob": 2 * time.Minute}, timeouts)
+
+	for _, invalid := range []string{"DisperseBlob", "=10s", "DisperseBlob=ten", "DisperseBlob=-1s"} {
+		_, err = apiserver.ParseMethodTimeouts([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
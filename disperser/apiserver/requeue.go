@@ -0,0 +1,72 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/aws/s3"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RequeueBlob disperses a failed blob again from its stored content, with the request header of the original dispersal,
+// so that the client doesn't have to keep the blob to submit it again. The failed blob is left as it is.
+func (s *DispersalServer) RequeueBlob(ctx context.Context, req *pb.RequeueBlobRequest) (*pb.RequeueBlobReply, error) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("RequeueBlob", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+
+	requestID := req.GetRequestId()
+	if len(requestID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "request_id must not be empty")
+	}
+	s.logger.Info("[apiserver] received a blob requeue request", "requestID", string(requestID))
+	blobKey, err := disperser.ParseBlobKey(string(requestID))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request_id: %v", err)
+	}
+
+	metadata, err := s.blobStore.GetBlobMetadata(ctx, blobKey)
+	if errors.Is(err, disperser.ErrBlobNotFound) || (err == nil && metadata == nil) {
+		return nil, status.Error(codes.NotFound, "blob not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if metadata.BlobStatus != disperser.Failed {
+		return nil, status.Errorf(codes.FailedPrecondition, "only failed blobs can be requeued, blob is %s", metadata.BlobStatus)
+	}
+
+	data, err := s.blobStore.GetBlobContent(ctx, metadata)
+	if errors.Is(err, s3.ErrObjectNotFound) || errors.Is(err, disperser.ErrBlobNotFound) {
+		return nil, status.Error(codes.NotFound, "blob content was already deleted")
+	}
+	if err != nil {
+		s.logger.Error("[apiserver] failed to get the content of the requeued blob", "blobKey", blobKey.String(), "err", err)
+		return nil, status.Error(codes.Internal, "failed to get blob content")
+	}
+
+	blob := &core.Blob{
+		RequestHeader: metadata.RequestMetadata.BlobRequestHeader,
+		Data:          data,
+	}
+	newKey, err := s.blobStore.StoreBlob(ctx, blob, uint64(time.Now().UnixNano()))
+	if err != nil {
+		s.logger.Error("[apiserver] failed to store the requeued blob", "blobKey", blobKey.String(), "err", err)
+		return nil, status.Error(codes.Internal, "failed to store blob")
+	}
+	if err := s.blobStore.MarkBlobProcessing(ctx, newKey); err != nil {
+		s.logger.Error("[apiserver] failed to mark the requeued blob as processing", "blobKey", newKey.String(), "err", err)
+		return nil, status.Error(codes.Internal, "failed to requeue blob")
+	}
+
+	s.metrics.IncrementRequeuedBlobs()
+	s.logger.Info("[apiserver] blob requeued", "blobKey", blobKey.String(), "newBlobKey", newKey.String())
+	return &pb.RequeueBlobReply{RequestId: []byte(newKey.String())}, nil
+}
@@ -0,0 +1,88 @@
+package apiserver_test
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/aws/s3"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// expiredContentBlobStore is a blob store whose blob contents were deleted, e.g. by the bucket lifecycle
+type expiredContentBlobStore struct {
+	disperser.BlobStore
+}
+
+func (s *expiredContentBlobStore) GetBlobContent(ctx context.Context, metadata *disperser.BlobMetadata) ([]byte, error) {
+	return nil, s3.ErrObjectNotFound
+}
+
+func TestRequeueBlob(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{}, nil)
+	ctx, _ := newTestContext()
+
+	data := []byte("requeued blob")
+	disperseReply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: data, SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}}})
+	require.NoError(t, err)
+	requestID := disperseReply.GetRequestId()
+
+	// processing blobs can't be requeued
+	_, err = server.RequeueBlob(ctx, &pb.RequeueBlobRequest{RequestId: requestID})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = server.CancelBlob(ctx, &pb.CancelBlobRequest{RequestId: requestID})
+	require.NoError(t, err)
+	reply, err := server.RequeueBlob(ctx, &pb.RequeueBlobRequest{RequestId: requestID})
+	require.NoError(t, err)
+	assert.NotEqual(t, requestID, reply.GetRequestId())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RequeuedBlobs))
+
+	// the new blob has the content and the request header of the failed one, which is left failed
+	originalKey, err := disperser.ParseBlobKey(string(requestID))
+	require.NoError(t, err)
+	original, err := blobStore.GetBlobMetadata(ctx, originalKey)
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Failed, original.BlobStatus)
+	newKey, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+	require.NoError(t, err)
+	requeued, err := blobStore.GetBlobMetadata(ctx, newKey)
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Processing, requeued.BlobStatus)
+	assert.Equal(t, original.RequestMetadata.BlobRequestHeader, requeued.RequestMetadata.BlobRequestHeader)
+	content, err := blobStore.GetBlobContent(ctx, requeued)
+	require.NoError(t, err)
+	assert.Equal(t, data, content)
+
+	_, err = server.RequeueBlob(ctx, &pb.RequeueBlobRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.RequeueBlob(ctx, &pb.RequeueBlobRequest{RequestId: []byte("0000-0000")})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestRequeueBlobContentDeleted(t *testing.T) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, &expiredContentBlobStore{BlobStore: blobStore}, logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{}, nil)
+	ctx, _ := newTestContext()
+
+	disperseReply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("expired blob"), SecurityParams: testSecurityParams})
+	require.NoError(t, err)
+	_, err = server.CancelBlob(ctx, &pb.CancelBlobRequest{RequestId: disperseReply.GetRequestId()})
+	require.NoError(t, err)
+
+	_, err = server.RequeueBlob(ctx, &pb.RequeueBlobRequest{RequestId: disperseReply.GetRequestId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
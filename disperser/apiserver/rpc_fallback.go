@@ -0,0 +1,26 @@
+package apiserver
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// WithFallbackRPCClients sets the RPC clients the latest finalized block is fetched from, in order,
+// when the primary RPC client fails
+func WithFallbackRPCClients(clients ...*rpc.Client) ServerOption {
+	return func(s *DispersalServer) {
+		s.fallbackRPCClients = append(s.fallbackRPCClients, clients...)
+	}
+}
+
+// ParseRPCEndpoints splits the comma-separated RPC endpoints, ignoring empty entries
+func ParseRPCEndpoints(endpoints string) []string {
+	result := make([]string, 0)
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		if endpoint = strings.TrimSpace(endpoint); len(endpoint) > 0 {
+			result = append(result, endpoint)
+		}
+	}
+	return result
+}
@@ -0,0 +1,187 @@
+package apiserver_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// finalizedBlockService serves eth_getBlockByNumber with a finalized block of the given number, or fails
+type finalizedBlockService struct {
+	number uint64
+	// extra changes the hash of the blocks by number
+	extra map[uint64][]byte
+	err   error
+}
+
+func (s *finalizedBlockService) GetBlockByNumber(tag string, fullTx bool) (*types.Header, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	number := s.number
+	if tag != "finalized" {
+		n, err := hexutil.DecodeUint64(tag)
+		if err != nil {
+			return nil, err
+		}
+		number = n
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(0), Extra: s.extra[number]}, nil
+}
+
+func (s *finalizedBlockService) BlockNumber() (hexutil.Uint64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	return hexutil.Uint64(s.number), nil
+}
+
+func newFinalizedBlockClient(t *testing.T, service *finalizedBlockService) *rpc.Client {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+	return rpc.DialInProc(server)
+}
+
+func TestUpdateLatestFinalizedBlockFallback(t *testing.T) {
+	primary := &finalizedBlockService{number: 100}
+	first := &finalizedBlockService{err: errors.New("fallback down")}
+	second := &finalizedBlockService{number: 120}
+
+	logger := &mock.Logger{}
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{},
+		newFinalizedBlockClient(t, primary),
+		apiserver.WithFallbackRPCClients(newFinalizedBlockClient(t, first), newFinalizedBlockClient(t, second)))
+	ctx := context.Background()
+
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(100), server.LatestFinalizedBlock())
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.RPCFailures))
+
+	// the fallbacks are tried in order once the primary fails
+	primary.err = errors.New("primary down")
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(120), server.LatestFinalizedBlock())
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.RPCFailures))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RPCFallbackUsed.WithLabelValues("1")))
+
+	// a lagging fallback doesn't make the finalized block regress
+	second.number = 110
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(120), server.LatestFinalizedBlock())
+
+	// it fails once all the endpoints fail
+	second.err = errors.New("fallback down")
+	assert.Error(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(120), server.LatestFinalizedBlock())
+	assert.Equal(t, 7.0, testutil.ToFloat64(metrics.RPCFailures))
+}
+
+func TestUpdateLatestFinalizedBlockReorg(t *testing.T) {
+	primary := &finalizedBlockService{number: 110, extra: make(map[uint64][]byte)}
+	logger := &mock.Logger{}
+	metrics := disperser.NewMetrics("9100", logger)
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, blobStore, logger, metrics, nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{},
+		newFinalizedBlockClient(t, primary))
+	ctx := context.Background()
+
+	// blobs finalized at the blocks 95 and 105
+	keys := make(map[uint32]disperser.BlobKey)
+	for _, confirmationBlock := range []uint32{95, 105} {
+		key, err := blobStore.StoreBlob(ctx, &core.Blob{Data: []byte{byte(confirmationBlock)}}, uint64(confirmationBlock))
+		require.NoError(t, err)
+		metadata, err := blobStore.GetBlobMetadata(ctx, key)
+		require.NoError(t, err)
+		_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{ConfirmationBlockNumber: confirmationBlock})
+		require.NoError(t, err)
+		require.NoError(t, blobStore.MarkBlobFinalized(ctx, key))
+		keys[confirmationBlock] = key
+	}
+	blobStatus := func(confirmationBlock uint32) disperser.BlobStatus {
+		metadata, err := blobStore.GetBlobMetadata(ctx, keys[confirmationBlock])
+		require.NoError(t, err)
+		return metadata.BlobStatus
+	}
+
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(110), server.LatestFinalizedBlock())
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.FinalizationReorgs))
+
+	// a lower finalized block number is discarded while the latest finalized block is unchanged
+	primary.number = 100
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(110), server.LatestFinalizedBlock())
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.FinalizationReorgs))
+	assert.Equal(t, disperser.Finalized, blobStatus(105))
+
+	// the finalized block moves back once the latest finalized block is reorged
+	primary.extra[110] = []byte("reorged")
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(100), server.LatestFinalizedBlock())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.FinalizationReorgs))
+	assert.Equal(t, disperser.Finalized, blobStatus(95))
+	assert.Equal(t, disperser.Confirmed, blobStatus(105))
+
+	// the finalized block changes hash
+	primary.extra[100] = []byte("reorged")
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(100), server.LatestFinalizedBlock())
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.FinalizationReorgs))
+	assert.Equal(t, disperser.Finalized, blobStatus(95))
+}
+
+func TestUpdateLatestFinalizedBlockWithDepth(t *testing.T) {
+	// the service serves the latest block number
+	latest := &finalizedBlockService{number: 100, extra: make(map[uint64][]byte)}
+	logger := &mock.Logger{}
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{FinalizationDepth: 10}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{},
+		newFinalizedBlockClient(t, latest))
+	ctx := context.Background()
+
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(90), server.LatestFinalizedBlock())
+	latest.number = 105
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(95), server.LatestFinalizedBlock())
+
+	// a lagging endpoint doesn't make the finalized block regress
+	latest.number = 100
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(95), server.LatestFinalizedBlock())
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.FinalizationReorgs))
+
+	// the block at the depth is reorged
+	latest.extra[95] = []byte("reorged")
+	require.NoError(t, server.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(90), server.LatestFinalizedBlock())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.FinalizationReorgs))
+
+	// the chain is shorter than the depth
+	short := apiserver.NewDispersalServer(disperser.ServerConfig{FinalizationDepth: 10}, memorydb.NewBlobStore(1024*1024, logger), logger, disperser.NewMetrics("9100", logger), nil, apiserver.RateConfig{}, false, nil, eth_common.Hash{},
+		newFinalizedBlockClient(t, &finalizedBlockService{number: 5}))
+	require.NoError(t, short.UpdateLatestFinalizedBlock(ctx))
+	assert.Equal(t, uint32(0), short.LatestFinalizedBlock())
+}
+
+func TestParseRPCEndpoints(t *testing.T) {
+	assert.Equal(t, []string{"http://a:8545", "http://b:8545"}, apiserver.ParseRPCEndpoints(" http://a:8545,,http://b:8545 "))
+	assert.Empty(t, apiserver.ParseRPCEndpoints(""))
+}
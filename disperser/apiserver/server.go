@@ -1,24 +1,42 @@
 package apiserver
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"math"
 	"net"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	adminpb "github.com/0glabs/0g-data-avail/api/grpc/admin"
 	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
 	"github.com/0glabs/0g-data-avail/common"
 	healthcheck "github.com/0glabs/0g-data-avail/common/healthcheck"
+	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/core"
 	"github.com/0glabs/0g-data-avail/disperser"
-	"github.com/0glabs/0g-storage-client/kv"
+	"github.com/0glabs/0g-storage-client/node"
 	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/openweb3/web3go/types"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 var errSystemRateLimit = fmt.Errorf("request ratelimited: system limit")
@@ -26,25 +44,122 @@ var errAccountRateLimit = fmt.Errorf("request ratelimited: account limit")
 
 const systemAccountKey = "system"
 
+// The reasons blobs are rejected by the rate limits, i.e. the values of the reason label of rate_limit_rejections_total
+const (
+	rateLimitReasonSystemBytes     = "system_bytes"
+	rateLimitReasonAccountBytes    = "account_bytes"
+	rateLimitReasonAccountRequests = "account_requests"
+	rateLimitReasonQuotaBlobs      = "quota_blobs"
+	rateLimitReasonBlocked         = "blocked"
+)
+
+// The labels of the rate limit check counters. The account type is whether the bucket is shared by all the accounts
+// or is the bucket of an individual account, the limit type is whether the limit is a system or an account limit.
+const (
+	rateLimitAccountTypeSystem     = "system"
+	rateLimitAccountTypeIndividual = "individual"
+	rateLimitLimitTypeSystem       = "system"
+	rateLimitLimitTypeAccount      = "account"
+)
+
+// requesterHashLength is the number of hex characters of the requester hashes labeling rate_bucket_fill_ratio,
+// it bounds the number of series to 16^requesterHashLength
+const requesterHashLength = 2
+
+const (
+	// kvReadAttempts is the number of times the blob metadata is read from the kv node before giving up
+	kvReadAttempts = 3
+	// kvReadRetryDelay is the delay before the first retry of a kv read, it doubles on every retry
+	kvReadRetryDelay = 100 * time.Millisecond
+)
+
+// drainLogInterval is the interval the number of in-flight requests is logged at while shutting down
+const drainLogInterval = time.Second
+
+// defaultShutdownTimeout is the time the in-flight requests are waited for on shutdown if not configured
+const defaultShutdownTimeout = 30 * time.Second
+
+// defaultMaxRecvMsgSize is the grpc default maximum size of the received messages, used if not configured
+const defaultMaxRecvMsgSize = 1024 * 1024 * 4 // 4 MiB
+
+// retryAfterHeader is the grpc header used to tell clients when to retry a rejected request
+const retryAfterHeader = "retry-after"
+
 type DispersalServer struct {
 	pb.UnimplementedDisperserServer
+	adminpb.UnimplementedAdminServer
 	mu *sync.RWMutex
 
 	config disperser.ServerConfig
 
 	blobStore disperser.BlobStore
 
+	// rateConfig and cidrRates are replaced by ReloadRateConfig, they are read under mu with getRateConfig
 	rateConfig  RateConfig
 	ratelimiter common.RateLimiter
+	// cidrRates is rateConfig.CIDRRates compiled for lookups
+	cidrRates *cidrRateTable
 
 	metrics *disperser.Metrics
 
 	metadataHashAsBlobKey bool
-	KVNode                *kv.Client
+	KVNode                storage_node.KVClient
 	StreamId              eth_common.Hash
 
-	rpcClient            *rpc.Client
+	rpcClient *rpc.Client
+	// fallbackRPCClients are tried in order when rpcClient fails to return the latest finalized block
+	fallbackRPCClients   []*rpc.Client
 	latestFinalizedBlock uint32
+	// latestFinalizedHash is the hash of latestFinalizedBlock, to detect the finalization reorgs
+	latestFinalizedHash eth_common.Hash
+
+	// EncodingQueue is used to apply backpressure on new blobs, the admission gate is disabled if it is nil
+	EncodingQueue disperser.EncodingQueue
+
+	// blobSizeValidator checks the size of new blobs
+	blobSizeValidator BlobSizeValidator
+
+	// loadShedder rejects new blobs under memory or GC pressure, load shedding is disabled if it is nil
+	loadShedder *LoadShedder
+
+	interceptorPlugins []InterceptorPlugin
+
+	// confirmationReader is used to look up blobs on chain when they are not found locally, the fallback is disabled if it is nil
+	confirmationReader BlobConfirmationReader
+
+	// recentBlobs tracks the blobs recently submitted by each account, duplicate blobs are accepted if it is nil
+	recentBlobs *RecentBlobCache
+	// idempotentReplies remembers the replies of the requests with an idempotency key, the keys are ignored if it is nil
+	idempotentReplies *IdempotencyCache
+
+	// attestationKey signs the submission attestations of new blobs, blobs are not attested if it is nil
+	attestationKey *ecdsa.PrivateKey
+
+	// statusHub notifies the blob status watches of the status changes, the watches only poll the blob store if it is nil
+	statusHub *disperser.BlobStatusHub
+	// callbacks notifies the callbacks registered for the blobs, RegisterBlobCallback is disabled if it is nil
+	callbacks *BlobCallbackNotifier
+	// stopWatches is closed by Shutdown to end the blob status watches and the pending blob callbacks
+	stopWatches     chan struct{}
+	stopWatchesOnce sync.Once
+
+	// grpcServer is the server started by Serve, stopped by Shutdown
+	grpcServer   *grpc.Server
+	grpcServerMu sync.Mutex
+	inFlight     atomic.Int64
+
+	// serverLoadPercent is the consumed percentage of the system rate limit, updated by UpdateServerLoad
+	serverLoadPercent atomic.Uint32
+
+	readinessCheck ReadinessCheck
+	readinessMu    sync.RWMutex
+	readinessErr   error
+
+	// auditLogger records the operations of the requesters, they are not audited if nil
+	auditLogger common.AuditLogger
+
+	// apiKeyAuth authenticates the callers by their API key, the APIs are open if nil
+	apiKeyAuth *APIKeyAuth
 
 	logger common.Logger
 }
@@ -60,11 +175,13 @@ func NewDispersalServer(
 	ratelimiter common.RateLimiter,
 	rateConfig RateConfig,
 	metadataHashAsBlobKey bool,
-	kvClient *kv.Client,
+	kvClient storage_node.KVClient,
 	streamId eth_common.Hash,
 	rpcClient *rpc.Client,
+	opts ...ServerOption,
 ) *DispersalServer {
-	return &DispersalServer{
+	logger = logger.WithField("component", "apiserver")
+	server := &DispersalServer{
 		config:                config,
 		blobStore:             store,
 		metrics:               metrics,
@@ -76,7 +193,24 @@ func NewDispersalServer(
 		KVNode:                kvClient,
 		StreamId:              streamId,
 		rpcClient:             rpcClient,
+		blobSizeValidator:     DefaultBlobSizeValidator{},
+		stopWatches:           make(chan struct{}),
+	}
+	cidrRates, err := newCIDRRateTable(rateConfig.CIDRRates)
+	if err != nil {
+		logger.Error("[apiserver] ignoring invalid CIDR rates", "err", err)
+	}
+	server.cidrRates = cidrRates
+	if config.DedupWindow > 0 {
+		server.recentBlobs = NewRecentBlobCache(config.DedupWindow)
+	}
+	if config.IdempotencyWindowDuration > 0 {
+		server.idempotentReplies = NewIdempotencyCache(config.IdempotencyWindowDuration)
 	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
 }
 
 func (s *DispersalServer) DisperseBlob(ctx context.Context, req *pb.DisperseBlobRequest) (*pb.DisperseBlobReply, error) {
@@ -85,45 +219,331 @@ func (s *DispersalServer) DisperseBlob(ctx context.Context, req *pb.DisperseBlob
 	}))
 	defer timer.ObserveDuration()
 
-	securityParams := req.GetSecurityParams()
-
-	blobSize := len(req.GetData())
-	// The blob size in bytes must be in range [1, maxBlobSize].
-	if blobSize > core.MaxBlobSize {
-		return nil, fmt.Errorf("blob size cannot exceed %v KiB", core.MaxBlobSize/1024)
+	blob, err := getBlobFromRequest(req)
+	if err != nil {
+		return nil, err
 	}
-	if blobSize == 0 {
-		return nil, fmt.Errorf("blob size must be greater than 0")
+	ctx, span := tracing.StartSpan(tracing.ContextFromIncomingGRPC(ctx), "DisperseBlob", attribute.Int("blob.size", len(blob.Data)))
+	reply, err := s.disperseBlob(ctx, blob, req.GetIdempotencyKey(), "DisperseBlob", false)
+	if err == nil {
+		span.SetAttributes(attribute.String("blob.request_id", string(reply.GetRequestId())))
 	}
+	tracing.EndSpan(span, err)
+	return reply, err
+}
 
-	blob := getBlobFromRequest(req)
+// disperseBlob checks and stores a new blob, method is the name of the dispersal API the blob was received by.
+// The system throughput limits are not checked if systemBytesChecked, e.g. because the caller checked them for several blobs at once.
+// A blob with an idempotency key already seen from the same account within the idempotency window is not stored again,
+// the reply of the original request is returned instead.
+func (s *DispersalServer) disperseBlob(ctx context.Context, blob *core.Blob, idempotencyKey string, method string, systemBytesChecked bool) (_ *pb.DisperseBlobReply, err error) {
+	securityParams := blob.RequestHeader.SecurityParams
+	blobSize := len(blob.Data)
+	auditEvent := common.AuditEvent{Operation: common.AuditOperation(method), RequesterID: blob.RequestHeader.AccountID, BlobSize: blobSize}
+	defer func() { s.audit(auditEvent, err) }()
 
-	origin, err := common.GetClientAddress(ctx, s.rateConfig.ClientIPHeader, 2, true)
+	rateConfig, _ := s.getRateConfig()
+	origin, err := common.GetClientAddress(ctx, rateConfig.ClientIPHeader, 2, true)
 	if err != nil {
-		s.metrics.HandleFailedRequest(blobSize, "DisperseBlob")
+		s.metrics.HandleFailedRequest(blobSize, method)
 		return nil, err
 	}
 
-	s.logger.Debug("[apiserver] received a new blob request", "origin", origin, "securityParams", securityParams)
+	// callers authenticated with an API key or mTLS are accounted by the account of their key or the common name
+	// of their certificate
+	peerCertFields := getPeerCertFields(ctx)
+	if accountID := authenticatedAccountID(ctx); accountID != "" {
+		blob.RequestHeader.AccountID = accountID
+	} else if blob.RequestHeader.AccountID == "" && peerCertFields["commonName"] != "" {
+		blob.RequestHeader.AccountID = peerCertFields["commonName"]
+	}
+	auditEvent.RequesterID = blob.RequestHeader.AccountID
+	if auditEvent.RequesterID == "" {
+		auditEvent.RequesterID = origin
+	}
+
+	if err := validateIdempotencyKey(idempotencyKey); err != nil {
+		return nil, err
+	}
+	if blobSize < s.config.MinBlobSize {
+		return nil, status.Errorf(codes.InvalidArgument, "blob size must be at least %d bytes, got %d", s.config.MinBlobSize, blobSize)
+	}
+	if err := s.blobSizeValidator.ValidateSize(ctx, blob, origin); err != nil {
+		return nil, err
+	}
+	if err := core.ValidateSecurityParams(blob.RequestHeader.SecurityParams); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid security params: %v", strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
 
-	requestedAt := uint64(time.Now().UnixNano())
-	metadataKey, err := s.blobStore.StoreBlob(ctx, blob, requestedAt)
+	if err := s.checkAdmission(ctx); err != nil {
+		s.metrics.HandleAdmissionGateRejectedRequest(blobSize, method)
+		return nil, err
+	}
+
+	if s.loadShedder != nil && s.loadShedder.Shedding() {
+		s.metrics.HandleLoadShedRequest(blobSize, method)
+		s.logger.Warn("[apiserver] process is under pressure, rejecting blob")
+		return nil, errSystemRateLimit
+	}
+
+	logger := s.logger.WithField("origin", origin)
+	logger.Debug("[apiserver] received a new blob request", "securityParams", securityParams)
+
+	// unauthenticated callers are accounted by their address
+	accountID := blob.RequestHeader.AccountID
+	if accountID == "" {
+		accountID = origin
+	}
+	storeBlob := func() (*pb.DisperseBlobReply, error) {
+		if err := s.checkDuplicate(accountID, blob.Data); err != nil {
+			s.metrics.HandleDuplicateRejectedRequest(accountID, blobSize, method)
+			logger.Debug("[apiserver] duplicate blob rejected", "accountID", accountID)
+			return nil, err
+		}
+		if err := s.checkRateLimits(ctx, accountID, blobSize, blob.RequestHeader.SecurityParams, method, systemBytesChecked); err != nil {
+			return nil, err
+		}
+
+		requestedAt := uint64(time.Now().UnixNano())
+		metadataKey, err := s.blobStore.StoreBlob(ctx, blob, requestedAt)
+		if err != nil {
+			s.metrics.HandleFailedRequest(blobSize, method)
+			return nil, err
+		}
+
+		reply := &pb.DisperseBlobReply{
+			Result:            pb.BlobStatus_PROCESSING,
+			RequestId:         []byte(metadataKey.String()),
+			ServerLoadPercent: s.serverLoadPercent.Load(),
+		}
+		if err := s.attestSubmission(reply, requestedAt); err != nil {
+			s.metrics.HandleFailedRequest(blobSize, method)
+			return nil, err
+		}
+		if err := s.signReceipt(reply, metadataKey, requestedAt, blob.RequestHeader.SecurityParams); err != nil {
+			s.metrics.HandleFailedRequest(blobSize, method)
+			return nil, err
+		}
+
+		if s.recentBlobs != nil {
+			s.recentBlobs.Add(accountID, blob.Data, metadataKey)
+		}
+		s.metrics.HandleSuccessfulRequest(blobSize, method)
+
+		logCtx := []interface{}{"key", metadataKey.String()}
+		if peerCertFields != nil {
+			logCtx = append(logCtx, "peerCert", peerCertFields)
+		}
+		logger.Info("[apiserver] received a new blob: ", logCtx...)
+		return reply, nil
+	}
+
+	var reply *pb.DisperseBlobReply
+	if idempotencyKey != "" && s.idempotentReplies != nil {
+		reply, err = s.idempotentReplies.Do(accountID, idempotencyKey, storeBlob)
+	} else {
+		reply, err = storeBlob()
+	}
 	if err != nil {
-		s.metrics.HandleFailedRequest(blobSize, "DisperseBlob")
 		return nil, err
 	}
+	auditEvent.BlobKey = string(reply.GetRequestId())
+	return reply, nil
+}
 
-	s.metrics.HandleSuccessfulRequest(blobSize, "DisperseBlob")
+// checkDuplicate rejects a blob the account already submitted within the dedup window, returning the request id
+// of the original submission.
+func (s *DispersalServer) checkDuplicate(accountID string, data []byte) error {
+	if s.recentBlobs == nil {
+		return nil
+	}
+	if key, ok := s.recentBlobs.Get(accountID, data); ok {
+		return status.Errorf(codes.AlreadyExists, "blob was already submitted within the last %v, request_id: %s", s.config.DedupWindow, key.String())
+	}
+	return nil
+}
 
-	s.logger.Info("[apiserver] received a new blob: ", "key", metadataKey.String())
-	return &pb.DisperseBlobReply{
-		Result:    pb.BlobStatus_PROCESSING,
-		RequestId: []byte(metadataKey.String()),
-	}, nil
+// checkAdmission rejects new blobs once the encoding queue is filled beyond the backpressure threshold.
+// Rejected requests carry a retry-after header with the estimated queue drain time in seconds.
+func (s *DispersalServer) checkAdmission(ctx context.Context) error {
+	if s.EncodingQueue == nil || s.config.AdmissionBackpressureThreshold <= 0 {
+		return nil
+	}
+	queueCapacity := s.EncodingQueue.QueueCapacity()
+	if queueCapacity <= 0 {
+		return nil
+	}
+	queueLength := s.EncodingQueue.QueueLength()
+	if float64(queueLength) < float64(queueCapacity)*s.config.AdmissionBackpressureThreshold {
+		return nil
+	}
+
+	retryAfter := int64(math.Ceil(s.EncodingQueue.EstimatedDrainTime().Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(retryAfterHeader, strconv.FormatInt(retryAfter, 10))); err != nil {
+		s.logger.Debug("[apiserver] failed to set retry-after header", "err", err)
+	}
+	s.logger.Warn("[apiserver] encoding queue is backed up, rejecting blob", "queueLength", queueLength, "queueCapacity", queueCapacity, "retryAfter", retryAfter)
+	return errSystemRateLimit
+}
+
+// checkRateLimits checks the blob against the system and account rate limits of each of its quorums.
+// Quorums without rate limits and zero rates are not limited. The system throughput limits are skipped if skipSystemBytes.
+func (s *DispersalServer) checkRateLimits(ctx context.Context, accountID string, blobSize int, securityParams []*core.SecurityParam, method string, skipSystemBytes bool) error {
+	if s.ratelimiter == nil {
+		return nil
+	}
+
+	rateConfig, cidrRates := s.getRateConfig()
+	for _, param := range securityParams {
+		rates, ok := rateConfig.QuorumRateInfos[param.QuorumID]
+		if !ok {
+			continue
+		}
+		rates = cidrRates.accountRates(accountID, rates)
+
+		checks := []struct {
+			requesterID string
+			size        uint
+			rate        common.RateParam
+			system      bool
+			reason      string
+		}{
+			{systemBytesRequesterID(param.QuorumID), uint(blobSize), rates.TotalUnauthThroughput, true, rateLimitReasonSystemBytes},
+			{systemBlobsRequesterID(param.QuorumID), blobRateMultiplier, rates.TotalUnauthBlobRate, true, rateLimitReasonQuotaBlobs},
+			{fmt.Sprintf("%s:%d-bytes", accountID, param.QuorumID), uint(blobSize), rates.PerUserUnauthThroughput, false, rateLimitReasonAccountBytes},
+			{fmt.Sprintf("%s:%d-blobs", accountID, param.QuorumID), blobRateMultiplier, rates.PerUserUnauthBlobRate, false, rateLimitReasonQuotaBlobs},
+		}
+		for _, check := range checks {
+			// zero byte requests, e.g. TTL extensions, are only limited by the blob rates
+			if check.rate == 0 || check.size == 0 || (skipSystemBytes && check.reason == rateLimitReasonSystemBytes) {
+				continue
+			}
+			accountType, limitType := rateLimitAccountTypeIndividual, rateLimitLimitTypeAccount
+			if check.system {
+				accountType, limitType = rateLimitAccountTypeSystem, rateLimitLimitTypeSystem
+			}
+			allowed, limitedBy, err := s.ratelimiter.AllowRequest(ctx, check.requesterID, check.size, check.rate)
+			if err != nil {
+				s.metrics.HandleRateLimitError(accountType, limitType)
+				s.metrics.HandleFailedRequest(blobSize, method)
+				return fmt.Errorf("failed to check rate limit: %w", err)
+			}
+			s.metrics.HandleRateLimitCheck(accountType, limitType, allowed)
+			s.recordBucketFillRatio(ctx, check.requesterID)
+			if allowed {
+				continue
+			}
+
+			reason := check.reason
+			if !check.system && limitedBy == common.RateLimitedByRequestCount {
+				reason = rateLimitReasonAccountRequests
+			}
+			if limitedBy == common.RateLimitedByBlocklist {
+				reason = rateLimitReasonBlocked
+			}
+			s.metrics.HandleRateLimitRejectedRequest(reason, check.system, blobSize, method)
+			s.logger.Debug("[apiserver] blob rejected by rate limit", "accountID", accountID, "quorumID", param.QuorumID, "reason", reason)
+			if check.system {
+				return errSystemRateLimit
+			}
+			return errAccountRateLimit
+		}
+	}
+	return nil
 }
 
-func (s *DispersalServer) getMetadataFromKv(key []byte) (*disperser.BlobMetadata, error) {
-	val, err := s.KVNode.GetValue(s.StreamId, key)
+// recordBucketFillRatio records the fill ratio of the least filled bucket of the requester, under a truncated hash of
+// the requester ID
+func (s *DispersalServer) recordBucketFillRatio(ctx context.Context, requesterID string) {
+	usage, err := s.ratelimiter.GetBucketUsage(ctx, requesterID)
+	if err != nil {
+		s.logger.Debug("[apiserver] failed to get rate bucket usage", "requesterID", requesterID, "err", err)
+		return
+	}
+	hash := sha256.Sum256([]byte(requesterID))
+	s.metrics.SetRateBucketFillRatio(hex.EncodeToString(hash[:])[:requesterHashLength], 1-usage)
+}
+
+// getRateConfig returns the current rate config and its compiled CIDR rates. A request uses the config it read
+// until it completes, the configs reloaded in the meantime apply to the next requests.
+func (s *DispersalServer) getRateConfig() (RateConfig, *cidrRateTable) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rateConfig, s.cidrRates
+}
+
+// systemBytesRequesterID is the requester ID of the bucket limiting the total throughput of a quorum
+func systemBytesRequesterID(quorumID core.QuorumID) string {
+	return fmt.Sprintf("%s%d-bytes", common.SystemRequesterPrefix, quorumID)
+}
+
+// systemBlobsRequesterID is the requester ID of the bucket limiting the total blob rate of a quorum
+func systemBlobsRequesterID(quorumID core.QuorumID) string {
+	return fmt.Sprintf("%s%d-blobs", common.SystemRequesterPrefix, quorumID)
+}
+
+// UpdateServerLoad updates the load returned to the clients by DisperseBlob, which is the consumed percentage of the
+// most consumed system rate bucket of all quorums
+func (s *DispersalServer) UpdateServerLoad(ctx context.Context) uint32 {
+	if s.ratelimiter == nil {
+		return 0
+	}
+
+	usage := 0.0
+	rateConfig, _ := s.getRateConfig()
+	for quorumID, rates := range rateConfig.QuorumRateInfos {
+		buckets := []struct {
+			requesterID string
+			rate        common.RateParam
+		}{
+			{systemBytesRequesterID(quorumID), rates.TotalUnauthThroughput},
+			{systemBlobsRequesterID(quorumID), rates.TotalUnauthBlobRate},
+		}
+		for _, bucket := range buckets {
+			if bucket.rate == 0 {
+				continue
+			}
+			bucketUsage, err := s.ratelimiter.GetBucketUsage(ctx, bucket.requesterID)
+			if err != nil {
+				s.logger.Debug("[apiserver] failed to get system rate bucket usage", "requesterID", bucket.requesterID, "err", err)
+				continue
+			}
+			usage = math.Max(usage, bucketUsage)
+		}
+	}
+
+	loadPercent := uint32(math.Round(math.Min(math.Max(usage, 0), 1) * 100))
+	s.serverLoadPercent.Store(loadPercent)
+	s.metrics.ServerLoadPercent.Set(float64(loadPercent))
+	return loadPercent
+}
+
+// getMetadataFromKv reads the blob metadata from the kv node, retrying with exponential backoff
+// until the context is done
+func (s *DispersalServer) getMetadataFromKv(ctx context.Context, key []byte) (*disperser.BlobMetadata, error) {
+	var val *node.Value
+	var err error
+	delay := kvReadRetryDelay
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		val, err = s.KVNode.GetValue(s.StreamId, key)
+		s.metrics.ObserveKVReadLatency(time.Since(start))
+		if err == nil || attempt == kvReadAttempts {
+			break
+		}
+
+		s.logger.Debug("[apiserver] failed to get blob metadata from kv node, retrying", "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to get blob metadata from kv node: %v, %v", err, ctx.Err())
+		case <-time.After(delay):
+		}
+		s.metrics.IncrementKVReadRetries()
+		delay *= 2
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get blob metadata from kv node: %v", err)
 	}
@@ -143,39 +563,81 @@ func (s *DispersalServer) GetBlobStatus(ctx context.Context, req *pb.BlobStatusR
 	}))
 	defer timer.ObserveDuration()
 
+	s.logger.Info("[apiserver] received a new blob status request", "requestID", string(req.GetRequestId()))
+	ctx, span := tracing.StartSpan(tracing.ContextFromIncomingGRPC(ctx), "GetBlobStatus", attribute.String("blob.request_id", string(req.GetRequestId())))
+	reply, err := s.getBlobStatus(ctx, req)
+	tracing.EndSpan(span, err)
+	s.audit(common.AuditEvent{
+		Operation:   common.AuditOperationGetBlobStatus,
+		RequesterID: s.auditRequesterID(ctx),
+		BlobKey:     string(req.GetRequestId()),
+	}, err)
+	return reply, err
+}
+
+// getMetadataByRequestID looks up the metadata of the blob in the blob store, then on the kv nodes if the metadata
+// hash is the blob key. It returns whether the blob was found, and the error of the blob store lookup if any.
+func (s *DispersalServer) getMetadataByRequestID(ctx context.Context, requestID []byte, metadataKey disperser.BlobKey) (*disperser.BlobMetadata, bool, error) {
+	metadata, lookupErr := s.blobStore.GetBlobMetadata(ctx, metadataKey)
+	if lookupErr != nil && !s.metadataHashAsBlobKey && !errors.Is(lookupErr, disperser.ErrBlobNotFound) {
+		return nil, false, lookupErr
+	}
+	found := lookupErr == nil && metadata != nil && metadata.GetBlobKey().String() == string(requestID)
+	if !found && s.metadataHashAsBlobKey {
+		// check on kv
+		metadataInKV, err := s.getMetadataFromKv(ctx, requestID)
+		if err != nil {
+			s.logger.Warn("get metadata from kv", err)
+		}
+		if metadataInKV != nil {
+			metadata = metadataInKV
+			found = true
+			s.mu.RLock()
+			if metadata.ConfirmationInfo.ConfirmationBlockNumber <= s.latestFinalizedBlock {
+				metadata.BlobStatus = disperser.Finalized
+			}
+			s.mu.RUnlock()
+		}
+	}
+	return metadata, found, lookupErr
+}
+
+// getBlobStatus looks up the status of the blob, locally first, then on the kv nodes and on chain if enabled
+func (s *DispersalServer) getBlobStatus(ctx context.Context, req *pb.BlobStatusRequest) (*pb.BlobStatusReply, error) {
 	requestID := req.GetRequestId()
 	if len(requestID) == 0 {
 		return nil, fmt.Errorf("invalid request: request_id must not be empty")
 	}
 
-	s.logger.Info("[apiserver] received a new blob status request", "requestID", string(requestID))
 	metadataKey, err := disperser.ParseBlobKey(string(requestID))
 	if err != nil {
 		return nil, err
 	}
 
-	metadata, err := s.blobStore.GetBlobMetadata(ctx, metadataKey)
-	if err != nil && !s.metadataHashAsBlobKey {
-		return nil, err
+	metadata, found, lookupErr := s.getMetadataByRequestID(ctx, requestID, metadataKey)
+	if lookupErr != nil && !s.metadataHashAsBlobKey && !errors.Is(lookupErr, disperser.ErrBlobNotFound) {
+		return nil, lookupErr
 	}
-	if (metadata == nil || metadata.GetBlobKey().String() != string(requestID)) && s.metadataHashAsBlobKey {
-		// check on kv
-		metadataInKV, err := s.getMetadataFromKv(requestID)
+	if !found && s.confirmationReader != nil && len(req.GetBatchHeaderHash()) == 32 {
+		// the blob may have been dispersed through another disperser, check on chain
+		metadataOnChain, err := s.getMetadataFromChain(ctx, metadataKey, [32]byte(req.GetBatchHeaderHash()), req.GetBlobIndex())
 		if err != nil {
-			s.logger.Warn("get metadata from kv", err)
+			s.logger.Warn("[apiserver] failed to get blob confirmation from chain", "err", err)
 		}
-		if metadataInKV != nil {
-			metadata = metadataInKV
-			s.mu.RLock()
-			defer s.mu.RUnlock()
-			if metadata.ConfirmationInfo.ConfirmationBlockNumber <= s.latestFinalizedBlock {
-				metadata.BlobStatus = disperser.Finalized
-			}
-		} else {
+		if metadataOnChain != nil {
+			metadata = metadataOnChain
+			found = true
+			s.metrics.IncrementOnchainFallbackHits()
+		}
+	}
+	if !found {
+		if s.metadataHashAsBlobKey {
 			// behavior align with aws dynamodb
 			metadata = &disperser.BlobMetadata{
 				BlobStatus: disperser.Processing,
 			}
+		} else if lookupErr != nil {
+			return nil, lookupErr
 		}
 	}
 
@@ -206,6 +668,14 @@ func (s *DispersalServer) GetBlobStatus(ctx context.Context, req *pb.BlobStatusR
 			quorumPercentSigned[i] = confirmationInfo.QuorumResults[quorumInfo.QuorumID].PercentSigned
 			quorumIndexes[i] = byte(i)
 		}
+		var storageNodeReceipts []*pb.StorageNodeReceipt
+		for _, receipt := range confirmationInfo.StorageNodeReceipts {
+			storageNodeReceipts = append(storageNodeReceipts, &pb.StorageNodeReceipt{
+				NodeId:    receipt.NodeID,
+				Signature: receipt.Signature,
+				Timestamp: receipt.Timestamp,
+			})
+		}
 
 		return &pb.BlobStatusReply{
 			Status: getResponseStatus(metadata.BlobStatus),
@@ -235,20 +705,41 @@ func (s *DispersalServer) GetBlobStatus(ctx context.Context, req *pb.BlobStatusR
 					QuorumIndexes: quorumIndexes,
 				},
 			},
+			StorageNodeReceipts: storageNodeReceipts,
+			History:             getStatusHistory(metadata),
 		}, nil
 	}
 
 	return &pb.BlobStatusReply{
-		Status: getResponseStatus(metadata.BlobStatus),
-		Info:   &pb.BlobInfo{},
+		Status:  getResponseStatus(metadata.BlobStatus),
+		Info:    &pb.BlobInfo{},
+		History: getStatusHistory(metadata),
 	}, nil
 }
 
-func (s *DispersalServer) RetrieveBlob(ctx context.Context, req *pb.RetrieveBlobRequest) (*pb.RetrieveBlobReply, error) {
+// getStatusHistory converts the status history of the blob to its protobuf representation
+func getStatusHistory(metadata *disperser.BlobMetadata) []*pb.BlobStatusEvent {
+	if len(metadata.BlobStatusHistory) == 0 {
+		return nil
+	}
+	history := make([]*pb.BlobStatusEvent, len(metadata.BlobStatusHistory))
+	for i, event := range metadata.BlobStatusHistory {
+		history[i] = &pb.BlobStatusEvent{
+			Status:    getResponseStatus(event.Status),
+			Timestamp: uint64(event.Timestamp.UnixNano()),
+			Reason:    event.Reason,
+		}
+	}
+	return history
+}
+
+func (s *DispersalServer) RetrieveBlob(ctx context.Context, req *pb.RetrieveBlobRequest) (_ *pb.RetrieveBlobReply, err error) {
 	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
 		s.metrics.ObserveLatency("RetrieveBlob", f*1000) // make milliseconds
 	}))
 	defer timer.ObserveDuration()
+	auditEvent := common.AuditEvent{Operation: common.AuditOperationRetrieveBlob, RequesterID: s.auditRequesterID(ctx)}
+	defer func() { s.audit(auditEvent, err) }()
 
 	s.logger.Info("[apiserver] received a new blob retrieval request", "batchHeaderHash", req.BatchHeaderHash, "blobIndex", req.BlobIndex)
 
@@ -259,47 +750,329 @@ func (s *DispersalServer) RetrieveBlob(ctx context.Context, req *pb.RetrieveBlob
 
 	blobIndex := req.GetBlobIndex()
 
-	blobMetadata, err := s.blobStore.GetMetadataInBatch(ctx, batchHeaderHash32, blobIndex)
+	blobMetadata, data, err := s.blobStore.GetBlobMetadataAndContent(ctx, batchHeaderHash32, blobIndex)
 	if err != nil {
-		s.logger.Error("Failed to retrieve blob metadata", "err", err)
+		s.logger.Error("Failed to retrieve blob", "err", err)
 		s.metrics.IncrementFailedBlobRequestNum("RetrieveBlob")
 
 		return nil, err
 	}
+	auditEvent.BlobKey = blobMetadata.GetBlobKey().String()
+	auditEvent.BlobSize = len(data)
+
+	return s.newRetrieveBlobReply(ctx, blobMetadata, data, req.GetIncludeProof(), "RetrieveBlob")
+}
+
+// RetrieveBlobByRequestId retrieves a confirmed blob by its request ID, resolving the metadata the same way as
+// GetBlobStatus, so that clients don't need the location of the blob in its batch
+func (s *DispersalServer) RetrieveBlobByRequestId(ctx context.Context, req *pb.RetrieveBlobByRequestIdRequest) (_ *pb.RetrieveBlobReply, err error) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("RetrieveBlobByRequestId", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+	requestID := req.GetRequestId()
+	auditEvent := common.AuditEvent{Operation: common.AuditOperationRetrieveBlob, RequesterID: s.auditRequesterID(ctx), BlobKey: string(requestID)}
+	defer func() { s.audit(auditEvent, err) }()
+
+	s.logger.Info("[apiserver] received a new blob retrieval request by request id", "requestID", string(requestID))
+
+	if len(requestID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "request_id must not be empty")
+	}
+	metadataKey, err := disperser.ParseBlobKey(string(requestID))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request_id: %v", err)
+	}
+
+	blobMetadata, found, lookupErr := s.getMetadataByRequestID(ctx, requestID, metadataKey)
+	if !found {
+		if lookupErr != nil && !errors.Is(lookupErr, disperser.ErrBlobNotFound) {
+			s.logger.Error("Failed to retrieve blob", "err", lookupErr)
+			s.metrics.IncrementFailedBlobRequestNum("RetrieveBlobByRequestId")
+			return nil, lookupErr
+		}
+		return nil, status.Error(codes.NotFound, "blob not found")
+	}
+	isConfirmed, err := blobMetadata.IsConfirmed()
+	if err != nil {
+		return nil, err
+	}
+	if !isConfirmed {
+		return nil, status.Errorf(codes.FailedPrecondition, "blob is %s", blobMetadata.BlobStatus)
+	}
 
 	data, err := s.blobStore.GetBlobContent(ctx, blobMetadata)
 	if err != nil {
 		s.logger.Error("Failed to retrieve blob", "err", err)
-		s.metrics.HandleFailedRequest(len(data), "RetrieveBlob")
+		s.metrics.IncrementFailedBlobRequestNum("RetrieveBlobByRequestId")
 
 		return nil, err
 	}
+	auditEvent.BlobSize = len(data)
 
-	s.metrics.HandleSuccessfulRequest(len(data), "RetrieveBlob")
+	return s.newRetrieveBlobReply(ctx, blobMetadata, data, req.GetIncludeProof(), "RetrieveBlobByRequestId")
+}
 
-	return &pb.RetrieveBlobReply{
+// newRetrieveBlobReply returns the retrieved blob data, along with its inclusion proof if requested
+func (s *DispersalServer) newRetrieveBlobReply(ctx context.Context, blobMetadata *disperser.BlobMetadata, data []byte, includeProof bool, method string) (*pb.RetrieveBlobReply, error) {
+	reply := &pb.RetrieveBlobReply{
 		Data: data,
-	}, nil
+	}
+	if includeProof {
+		proof, err := s.getInclusionProof(ctx, blobMetadata)
+		if err != nil {
+			s.logger.Error("Failed to generate blob inclusion proof", "err", err)
+			s.metrics.HandleFailedRequest(len(data), method)
+
+			return nil, err
+		}
+		reply.InclusionProof = proof
+		reply.BatchRoot = blobMetadata.ConfirmationInfo.BatchRoot
+		reply.CommitmentRoot = blobMetadata.ConfirmationInfo.CommitmentRoot
+	}
+
+	s.metrics.HandleSuccessfulRequest(len(data), method)
+
+	return reply, nil
 }
 
+// getInclusionProof rebuilds the Merkle tree of the blob headers in the batch of the blob, and returns the inclusion proof of the blob
+func (s *DispersalServer) getInclusionProof(ctx context.Context, blobMetadata *disperser.BlobMetadata) ([][]byte, error) {
+	confirmationInfo := blobMetadata.ConfirmationInfo
+	if confirmationInfo == nil {
+		return nil, fmt.Errorf("blob is not confirmed")
+	}
+
+	metadatas, err := s.blobStore.GetAllBlobMetadataByBatch(ctx, confirmationInfo.BatchHeaderHash)
+	if err != nil {
+		return nil, err
+	}
+	blobHeaders := make([]*core.BlobHeader, len(metadatas))
+	for _, metadata := range metadatas {
+		info := metadata.ConfirmationInfo
+		if info == nil || int(info.BlobIndex) >= len(blobHeaders) {
+			return nil, fmt.Errorf("incomplete blob headers of batch %x", confirmationInfo.BatchHeaderHash)
+		}
+		blobHeaders[info.BlobIndex] = &core.BlobHeader{
+			CommitmentRoot: info.CommitmentRoot,
+			Length:         uint(info.Length),
+			QuorumInfos:    info.BlobQuorumInfos,
+		}
+	}
+	for _, blobHeader := range blobHeaders {
+		if blobHeader == nil {
+			return nil, fmt.Errorf("incomplete blob headers of batch %x", confirmationInfo.BatchHeaderHash)
+		}
+	}
+
+	batchHeader := &core.BatchHeader{}
+	_, proofs, err := batchHeader.SetBatchRootWithProof(blobHeaders)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(batchHeader.BatchRoot[:], confirmationInfo.BatchRoot) {
+		// the batcher may hash the blob headers with their quorum parameters, see core.BlobHeader.GetBlobHeaderHashV2
+		_, proofs, err = batchHeader.SetBatchRootWithProofV2(blobHeaders)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !bytes.Equal(batchHeader.BatchRoot[:], confirmationInfo.BatchRoot) {
+		return nil, fmt.Errorf("batch root mismatch for batch %x", confirmationInfo.BatchHeaderHash)
+	}
+	return proofs[int(confirmationInfo.BlobIndex)], nil
+}
+
+// UpdateLatestFinalizedBlock fetches the latest finalized block from the RPC endpoint, or from the fallback endpoints
+// in order if it fails. It only fails if all the endpoints fail. Another hash of the latest finalized block is a
+// finalization reorg: the latest finalized block is reset, and the blobs finalized after it are moved back to
+// confirmed. A lower block number is only a reorg if the hash of the latest finalized block changed too, it is
+// discarded otherwise, e.g. from a lagging endpoint.
 func (s *DispersalServer) UpdateLatestFinalizedBlock(ctx context.Context) error {
+	client := s.rpcClient
+	block, err := s.fetchFinalizedBlock(ctx, client)
+	if err != nil {
+		s.metrics.IncrementRPCFailures()
+		for i, fallback := range s.fallbackRPCClients {
+			s.logger.Warn("[apiserver] fetch latest finalized block number failed, trying fallback", "fallback", i, "error", err)
+			block, err = s.fetchFinalizedBlock(ctx, fallback)
+			if err == nil {
+				s.metrics.IncrementRPCFallbackUsed(i)
+				client = fallback
+				break
+			}
+			s.metrics.IncrementRPCFailures()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	number := uint32(block.Number.ToInt().Uint64())
+
+	s.mu.RLock()
+	previous, previousHash := s.latestFinalizedBlock, s.latestFinalizedHash
+	s.mu.RUnlock()
+	if number < previous {
+		reorged, err := s.blockHashChanged(ctx, client, previous, previousHash)
+		if err != nil {
+			return err
+		}
+		if !reorged {
+			s.logger.Debug("[apiserver] discarding a finalized block number lower than the latest", "number", number, "latest", previous)
+			return nil
+		}
+	}
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	if s.latestFinalizedBlock != previous || s.latestFinalizedHash != previousHash {
+		// updated concurrently, the next update catches up
+		s.mu.Unlock()
+		return nil
+	}
+	reorg := number < previous || (number == previous && previousHash != (eth_common.Hash{}) && block.Hash != previousHash)
+	s.latestFinalizedBlock, s.latestFinalizedHash = number, block.Hash
+	s.mu.Unlock()
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Second*5)
-	defer cancel()
+	if reorg {
+		s.metrics.IncrementFinalizationReorgs()
+		s.logger.Warn(fmt.Sprintf("[apiserver] detected finalization reorg from block %d to %d", previous, number), "previousHash", previousHash.Hex(), "hash", block.Hash.Hex())
+		return s.unfinalizeBlobsAfter(ctx, number)
+	}
+	return nil
+}
 
-	var header = types.Header{}
-	err := s.rpcClient.CallContext(ctxWithTimeout, &header, "eth_getBlockByNumber", "finalized", false)
+// blockHashChanged returns whether the block of the given number no longer has the given hash. It can't tell without
+// the hash, so it returns false if it is empty.
+func (s *DispersalServer) blockHashChanged(ctx context.Context, client *rpc.Client, number uint32, hash eth_common.Hash) (bool, error) {
+	if hash == (eth_common.Hash{}) {
+		return false, nil
+	}
+	block, err := s.fetchBlock(ctx, client, hexutil.EncodeUint64(uint64(number)))
 	if err != nil {
-		return err
+		return false, fmt.Errorf("failed to get the header of the finalized block %d: %w", number, err)
+	}
+	return block.Hash != hash, nil
+}
+
+// unfinalizeBlobsAfter moves the finalized blobs confirmed after the finalized block back to confirmed. It pages
+// through the batches confirmed after the block, or through the finalized blobs if the store doesn't list batches.
+func (s *DispersalServer) unfinalizeBlobsAfter(ctx context.Context, finalizedBlock uint32) error {
+	reason := fmt.Sprintf("finalization reorg to block %d", finalizedBlock)
+	var lastBatchHeaderHash *[32]byte
+	for {
+		batches, err := s.blobStore.GetBatchesConfirmedAfter(ctx, finalizedBlock, defaultBatchPageSize, lastBatchHeaderHash)
+		if errors.Is(err, disperser.ErrBatchListingUnavailable) {
+			return s.unfinalizeBlobsByStatusAfter(ctx, finalizedBlock, reason)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get the batches confirmed after a reorg: %w", err)
+		}
+		for _, batch := range batches {
+			metadatas, err := s.blobStore.GetAllBlobMetadataByBatch(ctx, batch.BatchHeaderHash)
+			if err != nil {
+				return fmt.Errorf("failed to get the blobs of batch %x after a reorg: %w", batch.BatchHeaderHash, err)
+			}
+			if err := s.unfinalizeBlobs(ctx, metadatas, finalizedBlock, reason); err != nil {
+				return err
+			}
+		}
+		if len(batches) < defaultBatchPageSize {
+			return nil
+		}
+		lastBatchHeaderHash = &batches[len(batches)-1].BatchHeaderHash
 	}
-	if uint32(header.Number.Uint64()) > s.latestFinalizedBlock {
-		s.latestFinalizedBlock = uint32(header.Number.Uint64())
+}
+
+// unfinalizeBlobsByStatusAfter is unfinalizeBlobsAfter paging through all the finalized blobs
+func (s *DispersalServer) unfinalizeBlobsByStatusAfter(ctx context.Context, finalizedBlock uint32, reason string) error {
+	var exclusiveStartKey *disperser.BlobStoreExclusiveStartKey
+	for {
+		metadatas, nextKey, err := s.blobStore.GetBlobMetadataByStatusPaginated(ctx, disperser.Finalized, defaultBatchPageSize, exclusiveStartKey)
+		if err != nil {
+			return fmt.Errorf("failed to get the finalized blobs after a reorg: %w", err)
+		}
+		if err := s.unfinalizeBlobs(ctx, metadatas, finalizedBlock, reason); err != nil {
+			return err
+		}
+		if nextKey == nil {
+			return nil
+		}
+		exclusiveStartKey = nextKey
+	}
+}
+
+// unfinalizeBlobs moves the finalized blobs among the metadata confirmed after the finalized block back to confirmed
+func (s *DispersalServer) unfinalizeBlobs(ctx context.Context, metadatas []*disperser.BlobMetadata, finalizedBlock uint32, reason string) error {
+	for _, metadata := range metadatas {
+		if metadata.BlobStatus != disperser.Finalized || metadata.ConfirmationInfo == nil || metadata.ConfirmationInfo.ConfirmationBlockNumber <= finalizedBlock {
+			continue
+		}
+		blobKey := metadata.GetBlobKey()
+		err := s.blobStore.TransitionBlobStatus(ctx, blobKey, disperser.Finalized, disperser.Confirmed, reason)
+		if errors.Is(err, disperser.ErrUnexpectedBlobStatus) {
+			// no longer finalized
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to move blob %s back to confirmed: %w", blobKey.String(), err)
+		}
+		s.logger.Warn("[apiserver] moved a blob back to confirmed after a finalization reorg", "blobKey", blobKey.String(), "confirmationBlock", metadata.ConfirmationInfo.ConfirmationBlockNumber)
 	}
 	return nil
 }
 
+// LatestFinalizedBlock returns the latest finalized block number known to the server
+func (s *DispersalServer) LatestFinalizedBlock() uint32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latestFinalizedBlock
+}
+
+// finalizedBlock is the part of the finalized block used to track the finalization
+type finalizedBlock struct {
+	Number *hexutil.Big    `json:"number"`
+	Hash   eth_common.Hash `json:"hash"`
+}
+
+// fetchFinalizedBlock returns the finalized block of the RPC endpoint: the block of the finalized tag, or the block
+// FinalizationDepth blocks below the latest one if it is set.
+func (s *DispersalServer) fetchFinalizedBlock(ctx context.Context, client *rpc.Client) (*finalizedBlock, error) {
+	if client == nil {
+		return nil, errors.New("no rpc client")
+	}
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	if depth := s.config.FinalizationDepth; depth > 0 {
+		var latest hexutil.Uint64
+		if err := client.CallContext(ctxWithTimeout, &latest, "eth_blockNumber"); err != nil {
+			return nil, err
+		}
+		number := uint64(0)
+		if uint64(latest) > uint64(depth) {
+			number = uint64(latest) - uint64(depth)
+		}
+		return s.fetchBlock(ctxWithTimeout, client, hexutil.EncodeUint64(number))
+	}
+
+	return s.fetchBlock(ctxWithTimeout, client, "finalized")
+}
+
+// fetchBlock returns the number and the hash of the block of the given number or tag
+func (s *DispersalServer) fetchBlock(ctx context.Context, client *rpc.Client, numberOrTag string) (*finalizedBlock, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	var block *finalizedBlock
+	if err := client.CallContext(ctxWithTimeout, &block, "eth_getBlockByNumber", numberOrTag, false); err != nil {
+		return nil, err
+	}
+	if block == nil || block.Number == nil {
+		return nil, fmt.Errorf("block %s not found", numberOrTag)
+	}
+	return block, nil
+}
+
 func (s *DispersalServer) Start(ctx context.Context) error {
 	s.logger.Trace("Entering Start function...")
 	defer s.logger.Trace("Exiting Start function...")
@@ -307,18 +1080,51 @@ func (s *DispersalServer) Start(ctx context.Context) error {
 	// fetch latest finalized block number
 	if s.metadataHashAsBlobKey {
 		go func() {
+			ticker := time.NewTicker(time.Second * 5)
+			defer ticker.Stop()
+
 			for {
 				err := s.UpdateLatestFinalizedBlock(ctx)
 				if err != nil {
-					s.logger.Warn("[apiserver] fetch latest finalized block number failed", "error", err)
+					s.logger.Error("[apiserver] fetch latest finalized block number failed on all the rpc endpoints", "error", err)
 				} else {
-					s.logger.Info("[apiserver] latest finalized block number updated", "number", s.latestFinalizedBlock)
+					s.logger.Info("[apiserver] latest finalized block number updated", "number", s.LatestFinalizedBlock())
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+
+	if s.loadShedder != nil {
+		s.loadShedder.Start(ctx)
+	}
+
+	if s.ratelimiter != nil {
+		go func() {
+			ticker := time.NewTicker(loadSampleInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.UpdateServerLoad(ctx)
 				}
-				time.Sleep(time.Second * 5)
 			}
 		}()
 	}
 
+	// Don't serve grpc requests until the dependencies are ready
+	if err := s.waitUntilReady(ctx); err != nil {
+		return err
+	}
+
 	// Serve grpc requests
 	addr := fmt.Sprintf("%s:%s", disperser.Localhost, s.config.GrpcPort)
 	listener, err := net.Listen("tcp", addr)
@@ -326,10 +1132,74 @@ func (s *DispersalServer) Start(ctx context.Context) error {
 		return fmt.Errorf("could not start tcp listener")
 	}
 
-	opt := grpc.MaxRecvMsgSize(1024 * 1024 * 300) // 300 MiB
-	gs := grpc.NewServer(opt)
+	// Serve returns as soon as the shutdown starts, the in-flight requests are waited for before returning
+	shutdownDone := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		s.Shutdown()
+		close(shutdownDone)
+	}()
+	err = s.Serve(listener)
+	if ctx.Err() != nil {
+		<-shutdownDone
+	}
+	return err
+}
+
+// maxRecvMsgSize returns the maximum size of the received messages in bytes
+func (s *DispersalServer) maxRecvMsgSize() int {
+	if s.config.MaxRecvMsgSizeMiB <= 0 {
+		return defaultMaxRecvMsgSize
+	}
+	return s.config.MaxRecvMsgSizeMiB * 1024 * 1024
+}
+
+// Serve serves grpc requests on the given listener until it fails
+func (s *DispersalServer) Serve(listener net.Listener) error {
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(s.maxRecvMsgSize()),
+		grpc.ChainUnaryInterceptor(s.unaryInterceptors()...),
+		grpc.ChainStreamInterceptor(s.streamInterceptors()...),
+	}
+	keepaliveConfig := s.config.KeepaliveConfig
+	opts = append(opts,
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  keepaliveConfig.ServerKeepaliveTime,
+			Timeout:               keepaliveConfig.ServerKeepaliveTimeout,
+			MaxConnectionIdle:     keepaliveConfig.MaxConnectionIdle,
+			MaxConnectionAge:      keepaliveConfig.MaxConnectionAge,
+			MaxConnectionAgeGrace: keepaliveConfig.MaxConnectionAgeGrace,
+		}),
+		// the clients may ping while they have no stream, e.g. between the polls of a long-polling client
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             keepaliveConfig.MinClientKeepaliveTime,
+			PermitWithoutStream: true,
+		}),
+	)
+	if s.config.MaxSendMsgSizeMiB > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(s.config.MaxSendMsgSizeMiB*1024*1024))
+	}
+	if s.config.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(s.config.MaxConcurrentStreams))
+		s.metrics.GrpcMaxConcurrentStreams.Set(float64(s.config.MaxConcurrentStreams))
+	}
+	tlsConfig, err := loadTLSConfig(s.config.TLSConfig)
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		s.logger.Info("[apiserver] serving grpc over TLS", "clientCA", s.config.TLSConfig.ClientCAFile, "requireClientCert", s.config.TLSConfig.RequireClientCert)
+	}
+	gs := grpc.NewServer(opts...)
+	s.grpcServerMu.Lock()
+	s.grpcServer = gs
+	s.grpcServerMu.Unlock()
 	reflection.Register(gs)
 	pb.RegisterDisperserServer(gs, s)
+	if s.config.AdminToken != "" {
+		adminpb.RegisterAdminServer(gs, s)
+	}
 
 	// Register Server for Health Checks
 	healthcheck.RegisterHealthServer(gs)
@@ -342,6 +1212,56 @@ func (s *DispersalServer) Start(ctx context.Context) error {
 	return nil
 }
 
+// Shutdown stops the grpc server gracefully: new requests are refused and the in-flight requests are waited for,
+// their number is logged every second until they are all done. The requests still in flight after the shutdown
+// timeout are cancelled.
+func (s *DispersalServer) Shutdown() {
+	// the watches would otherwise hold the graceful stop until they time out
+	s.stopWatchesOnce.Do(func() { close(s.stopWatches) })
+	if s.idempotentReplies != nil {
+		s.idempotentReplies.Close()
+	}
+
+	s.grpcServerMu.Lock()
+	gs := s.grpcServer
+	s.grpcServerMu.Unlock()
+	if gs == nil {
+		return
+	}
+
+	timeout := s.config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	s.logger.Info("[apiserver] initiating graceful shutdown", "inFlight", s.inFlight.Load(), "timeout", timeout)
+	start := time.Now()
+	stopped := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(stopped)
+	}()
+
+	ticker := time.NewTicker(drainLogInterval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		select {
+		case <-stopped:
+			duration := time.Since(start)
+			s.metrics.ShutdownDrainDuration.Observe(float64(duration.Milliseconds()))
+			s.logger.Info("[apiserver] shutdown complete", "duration", duration)
+			return
+		case <-ticker.C:
+			s.logger.Info("[apiserver] draining in-flight requests", "inFlight", s.inFlight.Load())
+		case <-deadline.C:
+			// the graceful stop returns once the connections are closed
+			s.logger.Warn("[apiserver] shutdown timed out, cancelling in-flight requests", "inFlight", s.inFlight.Load())
+			gs.Stop()
+		}
+	}
+}
+
 func getResponseStatus(status disperser.BlobStatus) pb.BlobStatus {
 	switch status {
 	case disperser.Processing:
@@ -359,26 +1279,60 @@ func getResponseStatus(status disperser.BlobStatus) pb.BlobStatus {
 	}
 }
 
-func getBlobFromRequest(req *pb.DisperseBlobRequest) *core.Blob {
-	params := make([]*core.SecurityParam, len(req.SecurityParams))
-
-	for i, param := range req.GetSecurityParams() {
-		params[i] = &core.SecurityParam{
-			QuorumID:           core.QuorumID(param.QuorumId),
-			AdversaryThreshold: uint8(param.AdversaryThreshold),
-			QuorumThreshold:    uint8(param.QuorumThreshold),
-		}
+func getBlobFromRequest(req *pb.DisperseBlobRequest) (*core.Blob, error) {
+	if err := validateSecurityParams(req.GetSecurityParams()); err != nil {
+		return nil, err
+	}
+	if err := validatePriority(req.GetPriority()); err != nil {
+		return nil, err
 	}
-
 	data := req.GetData()
 
 	blob := &core.Blob{
 		RequestHeader: core.BlobRequestHeader{
-			SecurityParams: params,
+			SecurityParams: getSecurityParams(req.GetSecurityParams()),
 			TargetRowNum:   req.GetTargetRowNum(),
+			Priority:       uint8(req.GetPriority()),
 		},
 		Data: data,
 	}
 
-	return blob
+	return blob, nil
+}
+
+// validateSecurityParams checks the security parameters of a blob, a blob without any would be dispersed without
+// quorum requirements nor rate limits. The thresholds must fit in a byte so that they are not truncated,
+// the other bounds are checked by core.ValidateSecurityParams.
+func validateSecurityParams(securityParams []*pb.SecurityParams) error {
+	if len(securityParams) == 0 {
+		return status.Error(codes.InvalidArgument, "security_params must not be empty")
+	}
+	for _, param := range securityParams {
+		if param.GetQuorumId() > math.MaxUint8 || param.GetAdversaryThreshold() > math.MaxUint8 || param.GetQuorumThreshold() > math.MaxUint8 {
+			return status.Errorf(codes.InvalidArgument, "invalid security params of quorum %d: values must be at most %d", param.GetQuorumId(), math.MaxUint8)
+		}
+	}
+	return nil
+}
+
+// validatePriority checks the priority of a blob fits in a byte, so that it is not truncated
+func validatePriority(priority uint32) error {
+	if priority > math.MaxUint8 {
+		return status.Errorf(codes.InvalidArgument, "priority must be at most %d", math.MaxUint8)
+	}
+	return nil
+}
+
+func getSecurityParams(securityParams []*pb.SecurityParams) []*core.SecurityParam {
+	params := make([]*core.SecurityParam, len(securityParams))
+
+	for i, param := range securityParams {
+		params[i] = &core.SecurityParam{
+			QuorumID:           core.QuorumID(param.QuorumId),
+			AdversaryThreshold: uint8(param.AdversaryThreshold),
+			QuorumThreshold:    uint8(param.QuorumThreshold),
+		}
+	}
+
+	return params
 }
@@ -0,0 +1,692 @@
+package apiserver_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	clients "github.com/0glabs/0g-data-avail/clients/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	"github.com/0glabs/0g-storage-client/kv"
+	"github.com/0glabs/0g-storage-client/node"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type mockEncodingQueue struct {
+	length    int
+	capacity  int
+	drainTime time.Duration
+}
+
+func (q *mockEncodingQueue) QueueLength() int {
+	return q.length
+}
+
+func (q *mockEncodingQueue) QueueCapacity() int {
+	return q.capacity
+}
+
+func (q *mockEncodingQueue) EstimatedDrainTime() time.Duration {
+	return q.drainTime
+}
+
+type mockServerTransportStream struct {
+	header metadata.MD
+}
+
+func (s *mockServerTransportStream) Method() string {
+	return "/disperser.Disperser/DisperseBlob"
+}
+
+func (s *mockServerTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *mockServerTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *mockServerTransportStream) SetTrailer(md metadata.MD) error {
+	return nil
+}
+
+func newTestServer(config disperser.ServerConfig) *apiserver.DispersalServer {
+	server, _ := newTestServerWithBlobStore(config)
+	return server
+}
+
+func newTestServerWithBlobStore(config disperser.ServerConfig) (*apiserver.DispersalServer, disperser.BlobStore) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	return apiserver.NewDispersalServer(config, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil), blobStore
+}
+
+// testSecurityParams are the security parameters of the blobs dispersed by the tests
+var testSecurityParams = []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}}
+
+func newTestContext() (context.Context, *mockServerTransportStream) {
+	stream := &mockServerTransportStream{}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 51001},
+	})
+	return grpc.NewContextWithServerTransportStream(ctx, stream), stream
+}
+
+func TestDisperseBlobAdmissionGate(t *testing.T) {
+	server := newTestServer(disperser.ServerConfig{AdmissionBackpressureThreshold: 0.8})
+	queue := &mockEncodingQueue{capacity: 10, drainTime: 1500 * time.Millisecond}
+	server.EncodingQueue = queue
+
+	// below the threshold
+	queue.length = 7
+	ctx, stream := newTestContext()
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("below threshold"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetResult())
+	assert.Empty(t, stream.header.Get("retry-after"))
+
+	// at the threshold
+	queue.length = 8
+	ctx, stream = newTestContext()
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("at threshold"), SecurityParams: testSecurityParams})
+	assert.ErrorContains(t, err, "system limit")
+	assert.Equal(t, []string{"2"}, stream.header.Get("retry-after"))
+
+	// queue is full
+	queue.length = 10
+	queue.drainTime = 0
+	ctx, stream = newTestContext()
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("queue full"), SecurityParams: testSecurityParams})
+	assert.ErrorContains(t, err, "system limit")
+	assert.Equal(t, []string{"1"}, stream.header.Get("retry-after"))
+}
+
+func TestDisperseBlobAdmissionGateDisabled(t *testing.T) {
+	server := newTestServer(disperser.ServerConfig{AdmissionBackpressureThreshold: 0})
+	server.EncodingQueue = &mockEncodingQueue{length: 10, capacity: 10}
+
+	ctx, _ := newTestContext()
+	_, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("gate disabled"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+
+	// no encoding queue attached
+	server = newTestServer(disperser.ServerConfig{AdmissionBackpressureThreshold: 0.8})
+	ctx, _ = newTestContext()
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("no queue"), SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+}
+
+func TestDisperseBlobRateLimitReasons(t *testing.T) {
+	// a single 100 byte blob exceeds a rate of 1, while the other limits are far from being reached
+	const generous = 1_000_000_000
+	for _, tc := range []struct {
+		reason            string
+		rates             apiserver.QuorumRateInfo
+		requestsPerSecond float64
+		err               string
+	}{
+		{"", apiserver.QuorumRateInfo{TotalUnauthThroughput: generous, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: generous, PerUserUnauthBlobRate: generous}, 0, ""},
+		{"system_bytes", apiserver.QuorumRateInfo{TotalUnauthThroughput: 1, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: generous, PerUserUnauthBlobRate: generous}, 0, "system limit"},
+		{"account_bytes", apiserver.QuorumRateInfo{TotalUnauthThroughput: generous, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: 1, PerUserUnauthBlobRate: generous}, 0, "account limit"},
+		{"account_requests", apiserver.QuorumRateInfo{TotalUnauthThroughput: generous, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: generous, PerUserUnauthBlobRate: generous}, 0.01, "account limit"},
+		{"quota_blobs", apiserver.QuorumRateInfo{TotalUnauthThroughput: generous, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: generous, PerUserUnauthBlobRate: 1}, 0, "account limit"},
+		{"quota_blobs", apiserver.QuorumRateInfo{TotalUnauthThroughput: generous, TotalUnauthBlobRate: 1, PerUserUnauthThroughput: generous, PerUserUnauthBlobRate: generous}, 0, "system limit"},
+	} {
+		logger := &mock.Logger{}
+		bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+		assert.NoError(t, err)
+		ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+			BucketSizes:       []time.Duration{time.Minute},
+			Multipliers:       []float32{1},
+			RequestsPerSecond: tc.requestsPerSecond,
+		}, bucketStore, nil, nil, nil, logger)
+		metrics := disperser.NewMetrics("9100", logger)
+		rateConfig := apiserver.RateConfig{QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{0: tc.rates}}
+		server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+
+		ctx, _ := newTestContext()
+		_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+			Data:           make([]byte, 100),
+			SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+		})
+		if tc.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.err)
+		}
+
+		// only the counter of the exceeded limit is incremented
+		for _, reason := range []string{"system_bytes", "account_bytes", "account_requests", "quota_blobs"} {
+			expected := 0.0
+			if reason == tc.reason {
+				expected = 1
+			}
+			assert.Equal(t, expected, testutil.ToFloat64(metrics.RateLimitRejections.WithLabelValues(reason)), "reason %s when limited by %s", reason, tc.reason)
+		}
+	}
+}
+
+func TestDisperseBlobRateLimitCheckMetrics(t *testing.T) {
+	const generous = 1_000_000_000
+
+	logger := &mock.Logger{}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+	assert.NoError(t, err)
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{time.Minute},
+		Multipliers: []float32{1},
+	}, bucketStore, nil, nil, nil, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	// the system limits allow the blob, the account throughput denies it
+	rateConfig := apiserver.RateConfig{QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+		0: {TotalUnauthThroughput: generous, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: 1, PerUserUnauthBlobRate: generous},
+	}}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+
+	ctx, _ := newTestContext()
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+		Data:           make([]byte, 100),
+		SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+	})
+	assert.ErrorContains(t, err, "account limit")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.RateLimitAllowed.WithLabelValues("system", "system")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.RateLimitAllowed.WithLabelValues("individual", "account")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RateLimitDenied.WithLabelValues("individual", "account")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.RateLimitDenied.WithLabelValues("system", "system")))
+	// a series per checked bucket, unless their truncated hashes collide
+	fillRatios := testutil.CollectAndCount(metrics.RateBucketFillRatio)
+	assert.True(t, fillRatios >= 1 && fillRatios <= 3, fillRatios)
+}
+
+func TestDisperseBlobServerLoad(t *testing.T) {
+	const generous = 1_000_000_000
+
+	logger := &mock.Logger{}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+	assert.NoError(t, err)
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{10 * time.Second},
+		Multipliers: []float32{1},
+	}, bucketStore, nil, nil, nil, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	// each blob of 100 bytes consumes 1s of the 10s system bucket
+	rateConfig := apiserver.RateConfig{QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+		0: {TotalUnauthThroughput: 100, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: generous, PerUserUnauthBlobRate: generous},
+	}}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+
+	ctx, _ := newTestContext()
+	disperse := func() (*pb.DisperseBlobReply, error) {
+		return server.DisperseBlob(ctx, &pb.DisperseBlobRequest{
+			Data:           make([]byte, 100),
+			SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+		})
+	}
+
+	// idle
+	assert.Equal(t, uint32(0), server.UpdateServerLoad(ctx))
+	reply, err := disperse()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), reply.GetServerLoadPercent())
+
+	// under load
+	for i := 0; i < 4; i++ {
+		_, err = disperse()
+		assert.NoError(t, err)
+	}
+	load := server.UpdateServerLoad(ctx)
+	assert.InDelta(t, 50, load, 5)
+	assert.Equal(t, float64(load), testutil.ToFloat64(metrics.ServerLoadPercent))
+	reply, err = disperse()
+	assert.NoError(t, err)
+	assert.Equal(t, load, reply.GetServerLoadPercent())
+
+	// the load is bounded when the system limit is exceeded
+	for i := 0; i < 10; i++ {
+		_, _ = disperse()
+	}
+	assert.Equal(t, uint32(100), server.UpdateServerLoad(ctx))
+}
+
+// commitTestBlob stands in for the KZG commitments of the encoded blob data, with a commitment per 31-byte symbol
+func commitTestBlob(data []byte) ([]core.Commitment, error) {
+	commitments := make([]core.Commitment, 0)
+	for start := 0; start < len(data); start += 31 {
+		var commitment core.Commitment
+		symbol := sha256.Sum256(data[start:min(start+31, len(data))])
+		copy(commitment[:], symbol[:])
+		commitments = append(commitments, commitment)
+	}
+	return commitments, nil
+}
+
+func TestRetrieveBlobWithInclusionProof(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	// confirm a batch of blobs
+	blobs := [][]byte{[]byte("first blob"), []byte("second blob of the batch"), []byte("third")}
+	metadatas := make([]*disperser.BlobMetadata, len(blobs))
+	blobHeaders := make([]*core.BlobHeader, len(blobs))
+	for i, data := range blobs {
+		key, err := blobStore.StoreBlob(ctx, &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: data,
+		}, uint64(i))
+		assert.NoError(t, err)
+		metadatas[i], err = blobStore.GetBlobMetadata(ctx, key)
+		assert.NoError(t, err)
+		blobHeaders[i] = &core.BlobHeader{Length: core.GetBlobLength(uint(len(data)))}
+		commitments, err := commitTestBlob(data)
+		assert.NoError(t, err)
+		assert.NoError(t, blobHeaders[i].SetCommitmentRoot(commitments))
+	}
+	batchHeader := &core.BatchHeader{}
+	_, err := batchHeader.SetBatchRoot(blobHeaders)
+	assert.NoError(t, err)
+	batchHeaderHash := [32]byte{1}
+	for i, metadata := range metadatas {
+		_, err := blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{
+			BatchHeaderHash: batchHeaderHash,
+			BlobIndex:       uint32(i),
+			BatchRoot:       batchHeader.BatchRoot[:],
+			CommitmentRoot:  blobHeaders[i].CommitmentRoot,
+			Length:          uint32(blobHeaders[i].Length),
+		})
+		assert.NoError(t, err)
+	}
+
+	// no proof unless requested
+	reply, err := server.RetrieveBlob(ctx, &pb.RetrieveBlobRequest{BatchHeaderHash: batchHeaderHash[:], BlobIndex: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, blobs[1], reply.GetData())
+	assert.Empty(t, reply.GetInclusionProof())
+
+	commitmentRoots := make([][]byte, len(blobs))
+	for i := range blobs {
+		index := uint32(i)
+		reply, err := server.RetrieveBlob(ctx, &pb.RetrieveBlobRequest{BatchHeaderHash: batchHeaderHash[:], BlobIndex: index, IncludeProof: true})
+		assert.NoError(t, err)
+		commitmentRoots[i] = reply.GetCommitmentRoot()
+		assert.Equal(t, blobs[i], reply.GetData())
+		assert.Equal(t, batchHeader.BatchRoot[:], reply.GetBatchRoot())
+		proof := reply.GetInclusionProof()
+		verify := func(data []byte, commitmentRoot []byte, proof [][]byte, batchRoot [32]byte, index uint32) bool {
+			ok, err := clients.VerifyRetrievedBlob(data, commitTestBlob, commitmentRoot, nil, proof, batchRoot, index)
+			assert.NoError(t, err)
+			return ok
+		}
+		assert.True(t, verify(reply.GetData(), reply.GetCommitmentRoot(), proof, batchHeader.BatchRoot, index))
+
+		// tampered data
+		tamperedData := append([]byte{}, reply.GetData()...)
+		tamperedData[0] ^= 0xff
+		assert.False(t, verify(tamperedData, reply.GetCommitmentRoot(), proof, batchHeader.BatchRoot, index))
+		// data of another blob of the batch, with its commitment root
+		assert.False(t, verify(blobs[(i+1)%len(blobs)], blobHeaders[(i+1)%len(blobs)].CommitmentRoot, proof, batchHeader.BatchRoot, index))
+		// tampered commitment root
+		assert.False(t, verify(reply.GetData(), []byte{0xff}, proof, batchHeader.BatchRoot, index))
+		// tampered proof
+		tamperedProof := make([][]byte, len(proof))
+		for j := range proof {
+			tamperedProof[j] = append([]byte{}, proof[j]...)
+		}
+		tamperedProof[0][0] ^= 0xff
+		assert.False(t, verify(reply.GetData(), reply.GetCommitmentRoot(), tamperedProof, batchHeader.BatchRoot, index))
+		// wrong index
+		assert.False(t, verify(reply.GetData(), reply.GetCommitmentRoot(), proof, batchHeader.BatchRoot, (index+1)%uint32(len(blobs))))
+		// wrong batch root
+		assert.False(t, verify(reply.GetData(), reply.GetCommitmentRoot(), proof, [32]byte{0xff}, index))
+	}
+
+	// the batch root can be checked against all the blobs of the batch
+	ok, err := clients.VerifyBatchRoot(batchHeader.BatchRoot[:], commitmentRoots, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = clients.VerifyBatchRoot(batchHeader.BatchRoot[:], commitmentRoots[1:], nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = clients.VerifyBatchRoot([]byte{0xff}, commitmentRoots, nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRetrieveBlobWithInclusionProofV2(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	// confirm a batch of blobs whose leaves are hashed with their quorum parameters
+	blobs := [][]byte{[]byte("first blob"), []byte("second blob of the batch")}
+	metadatas := make([]*disperser.BlobMetadata, len(blobs))
+	blobHeaders := make([]*core.BlobHeader, len(blobs))
+	for i, data := range blobs {
+		key, err := blobStore.StoreBlob(ctx, &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: data,
+		}, uint64(i))
+		assert.NoError(t, err)
+		metadatas[i], err = blobStore.GetBlobMetadata(ctx, key)
+		assert.NoError(t, err)
+		blobHeaders[i] = &core.BlobHeader{
+			Length: core.GetBlobLength(uint(len(data))),
+			QuorumInfos: []*core.BlobQuorumInfo{{
+				SecurityParam: core.SecurityParam{QuorumID: 0, AdversaryThreshold: 50, QuorumThreshold: 80},
+				ChunkLength:   uint(i + 1),
+			}},
+		}
+		commitments, err := commitTestBlob(data)
+		assert.NoError(t, err)
+		assert.NoError(t, blobHeaders[i].SetCommitmentRoot(commitments))
+	}
+	batchHeader := &core.BatchHeader{}
+	_, _, err := batchHeader.SetBatchRootWithProofV2(blobHeaders)
+	assert.NoError(t, err)
+	batchHeaderHash := [32]byte{2}
+	for i, metadata := range metadatas {
+		_, err := blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{
+			BatchHeaderHash: batchHeaderHash,
+			BlobIndex:       uint32(i),
+			BatchRoot:       batchHeader.BatchRoot[:],
+			CommitmentRoot:  blobHeaders[i].CommitmentRoot,
+			Length:          uint32(blobHeaders[i].Length),
+			BlobQuorumInfos: blobHeaders[i].QuorumInfos,
+		})
+		assert.NoError(t, err)
+	}
+
+	commitmentRoots := make([][]byte, len(blobs))
+	quorumInfos := make([][]*core.BlobQuorumInfo, len(blobs))
+	for i := range blobs {
+		index := uint32(i)
+		reply, err := server.RetrieveBlob(ctx, &pb.RetrieveBlobRequest{BatchHeaderHash: batchHeaderHash[:], BlobIndex: index, IncludeProof: true})
+		assert.NoError(t, err)
+		commitmentRoots[i] = reply.GetCommitmentRoot()
+		quorumInfos[i] = blobHeaders[i].QuorumInfos
+		verify := func(data []byte, quorumInfos []*core.BlobQuorumInfo) bool {
+			ok, err := clients.VerifyRetrievedBlob(data, commitTestBlob, reply.GetCommitmentRoot(), quorumInfos, reply.GetInclusionProof(), batchHeader.BatchRoot, index)
+			assert.NoError(t, err)
+			return ok
+		}
+		assert.True(t, verify(reply.GetData(), blobHeaders[i].QuorumInfos))
+
+		// tampered data
+		tamperedData := append([]byte{}, reply.GetData()...)
+		tamperedData[0] ^= 0xff
+		assert.False(t, verify(tamperedData, blobHeaders[i].QuorumInfos))
+		// the quorum parameters of the other blob
+		assert.False(t, verify(reply.GetData(), blobHeaders[(i+1)%len(blobs)].QuorumInfos))
+		// the leaf without the quorum parameters
+		assert.False(t, verify(reply.GetData(), nil))
+	}
+
+	ok, err := clients.VerifyBatchRoot(batchHeader.BatchRoot[:], commitmentRoots, quorumInfos)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = clients.VerifyBatchRoot(batchHeader.BatchRoot[:], commitmentRoots, nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, err = clients.VerifyBatchRoot(batchHeader.BatchRoot[:], commitmentRoots, quorumInfos[1:])
+	assert.Error(t, err)
+}
+
+func TestRetrieveBlobByRequestId(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	data := []byte("blob retrieved by request id")
+	disperseReply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: data, SecurityParams: testSecurityParams})
+	assert.NoError(t, err)
+	requestID := disperseReply.GetRequestId()
+
+	// the blob can't be retrieved before it is confirmed
+	_, err = server.RetrieveBlobByRequestId(ctx, &pb.RetrieveBlobByRequestIdRequest{RequestId: requestID})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	blobKey, err := disperser.ParseBlobKey(string(requestID))
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	blobHeader := &core.BlobHeader{Length: core.GetBlobLength(uint(len(data)))}
+	commitments, err := commitTestBlob(data)
+	assert.NoError(t, err)
+	assert.NoError(t, blobHeader.SetCommitmentRoot(commitments))
+	batchHeader := &core.BatchHeader{}
+	_, err = batchHeader.SetBatchRoot([]*core.BlobHeader{blobHeader})
+	assert.NoError(t, err)
+	_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{
+		BatchHeaderHash: [32]byte{1},
+		BatchRoot:       batchHeader.BatchRoot[:],
+		CommitmentRoot:  blobHeader.CommitmentRoot,
+		Length:          uint32(blobHeader.Length),
+	})
+	assert.NoError(t, err)
+
+	reply, err := server.RetrieveBlobByRequestId(ctx, &pb.RetrieveBlobByRequestIdRequest{RequestId: requestID, IncludeProof: true})
+	assert.NoError(t, err)
+	assert.Equal(t, data, reply.GetData())
+	ok, err := clients.VerifyRetrievedBlob(reply.GetData(), commitTestBlob, reply.GetCommitmentRoot(), nil, reply.GetInclusionProof(), batchHeader.BatchRoot, 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = server.RetrieveBlobByRequestId(ctx, &pb.RetrieveBlobByRequestIdRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetBlobStatusStorageNodeReceipts(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	key, err := blobStore.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: []byte("stored by the storage nodes"),
+	}, 0)
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{BatchHeaderHash: [32]byte{1}})
+	assert.NoError(t, err)
+
+	// no receipts until they are collected
+	reply, err := server.GetBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: []byte(key.String())})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_CONFIRMED, reply.GetStatus())
+	assert.Empty(t, reply.GetStorageNodeReceipts())
+
+	err = blobStore.SetStorageNodeReceipts(ctx, key, []disperser.StorageNodeReceipt{
+		{NodeID: "node1", Signature: []byte{1}, Timestamp: 100},
+		{NodeID: "node2", Signature: []byte{2}, Timestamp: 101},
+	})
+	assert.NoError(t, err)
+	reply, err = server.GetBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: []byte(key.String())})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_CONFIRMED, reply.GetStatus())
+	receipts := reply.GetStorageNodeReceipts()
+	assert.Len(t, receipts, 2)
+	assert.Equal(t, "node1", receipts[0].GetNodeId())
+	assert.Equal(t, []byte{1}, receipts[0].GetSignature())
+	assert.Equal(t, uint64(100), receipts[0].GetTimestamp())
+	assert.Equal(t, "node2", receipts[1].GetNodeId())
+	assert.Equal(t, []byte{2}, receipts[1].GetSignature())
+	assert.Equal(t, uint64(101), receipts[1].GetTimestamp())
+}
+
+func TestDisperseBlobDedupWindow(t *testing.T) {
+	const window = 200 * time.Millisecond
+	logger := &mock.Logger{}
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{DedupWindow: window}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+	request := &pb.DisperseBlobRequest{
+		Data:           []byte("duplicate blob"),
+		SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}},
+	}
+
+	ctx, _ := newTestContext()
+	first, err := server.DisperseBlob(ctx, request)
+	assert.NoError(t, err)
+
+	// the same account submitting the same blob within the window is told the original request id
+	_, err = server.DisperseBlob(ctx, request)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Contains(t, err.Error(), string(first.GetRequestId()))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.DedupWindowRejections.WithLabelValues("127.0.0.1")))
+
+	// other content and other accounts are accepted
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("other blob"), SecurityParams: request.SecurityParams})
+	assert.NoError(t, err)
+	otherCtx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.2"), Port: 51001},
+	})
+	_, err = server.DisperseBlob(otherCtx, request)
+	assert.NoError(t, err)
+
+	// the blob is accepted again after the window
+	time.Sleep(window + 50*time.Millisecond)
+	second, err := server.DisperseBlob(ctx, request)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.GetRequestId(), second.GetRequestId())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.DedupWindowRejections.WithLabelValues("127.0.0.1")))
+}
+
+// flakyKVClient fails the first reads before serving its value
+type flakyKVClient struct {
+	failures int
+	value    []byte
+	calls    int
+}
+
+func (c *flakyKVClient) GetValue(streamId eth_common.Hash, key []byte, version ...uint64) (*node.Value, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, errors.New("kv node is unavailable")
+	}
+	return &node.Value{Data: c.value, Size: uint64(len(c.value))}, nil
+}
+
+func (c *flakyKVClient) NewIterator(streamId eth_common.Hash, version ...uint64) *kv.Iterator {
+	return nil
+}
+
+func TestGetBlobStatusKVRetries(t *testing.T) {
+	key := disperser.BlobKey{BlobHash: "blob", MetadataHash: "metadata"}
+	metadata := &disperser.BlobMetadata{
+		BlobHash:     key.BlobHash,
+		MetadataHash: key.MetadataHash,
+		BlobStatus:   disperser.Confirmed,
+		ConfirmationInfo: &disperser.ConfirmationInfo{
+			BatchHeaderHash:         [32]byte{1},
+			BlobIndex:               2,
+			BatchRoot:               eth_common.Hash{9}.Bytes(),
+			ConfirmationBlockNumber: 100,
+			QuorumResults:           map[core.QuorumID]*core.QuorumResult{0: {QuorumID: 0, PercentSigned: 80}},
+			BlobQuorumInfos: []*core.BlobQuorumInfo{{
+				SecurityParam: core.SecurityParam{QuorumID: 0, AdversaryThreshold: 25, QuorumThreshold: 50},
+			}},
+		},
+	}
+	value, err := metadata.Serialize()
+	assert.NoError(t, err)
+	request := &pb.BlobStatusRequest{RequestId: []byte(key.String())}
+
+	// the metadata is found once the kv node recovers
+	logger := &mock.Logger{}
+	metrics := disperser.NewMetrics("9100", logger)
+	kvClient := &flakyKVClient{failures: 2, value: value}
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, nil, apiserver.RateConfig{}, true, kvClient, eth_common.Hash{}, nil)
+	start := time.Now()
+	reply, err := server.GetBlobStatus(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_CONFIRMED, reply.GetStatus())
+	assert.Equal(t, uint32(2), reply.GetInfo().GetBlobVerificationProof().GetBlobIndex())
+	assert.Equal(t, 3, kvClient.calls)
+	// 100ms and 200ms backoff
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.KVReadRetries))
+
+	// the retries stop with the request context
+	metrics = disperser.NewMetrics("9100", logger)
+	kvClient = &flakyKVClient{failures: 3, value: value}
+	server = apiserver.NewDispersalServer(disperser.ServerConfig{}, memorydb.NewBlobStore(1024*1024, logger), logger, metrics, nil, apiserver.RateConfig{}, true, kvClient, eth_common.Hash{}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	reply, err = server.GetBlobStatus(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetStatus())
+	assert.Equal(t, 1, kvClient.calls)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.KVReadRetries))
+}
+
+func TestGetBlobStatusHistory(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	key, err := blobStore.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: []byte("retried until it fails"),
+	}, 0)
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	err = blobStore.HandleBlobFailure(ctx, metadata, 1)
+	assert.NoError(t, err)
+	metadata, err = blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	err = blobStore.HandleBlobFailure(ctx, metadata, 1)
+	assert.NoError(t, err)
+
+	reply, err := server.GetBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: []byte(key.String())})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_FAILED, reply.GetStatus())
+	history := reply.GetHistory()
+	assert.Len(t, history, 2)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, history[0].GetStatus())
+	assert.Equal(t, "retry 1 of 1", history[0].GetReason())
+	assert.Equal(t, pb.BlobStatus_FAILED, history[1].GetStatus())
+	assert.Equal(t, disperser.ErrMaxRetriesReached.Error(), history[1].GetReason())
+	assert.LessOrEqual(t, history[0].GetTimestamp(), history[1].GetTimestamp())
+}
+
+func TestDisperseBlobPriority(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{})
+	ctx, _ := newTestContext()
+
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("urgent blob"), SecurityParams: testSecurityParams, Priority: 200})
+	assert.NoError(t, err)
+	key, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(200), metadata.RequestMetadata.Priority)
+
+	// would be truncated to 0
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("urgent blob"), SecurityParams: testSecurityParams, Priority: 256})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,79 @@
+package apiserver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DisperseBlobStream disperses a blob received in chunks, the same way as DisperseBlob.
+// The chunks are buffered in memory until the flush chunk, so nothing is stored if the stream ends before it.
+func (s *DispersalServer) DisperseBlobStream(stream pb.Disperser_DisperseBlobStreamServer) error {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("DisperseBlobStream", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+
+	blob, err := s.receiveBlob(stream)
+	if err != nil {
+		s.metrics.HandleFailedRequest(len(blob.Data), "DisperseBlobStream")
+		s.logger.Debug("[apiserver] failed to receive a streamed blob", "err", err)
+		return err
+	}
+
+	reply, err := s.disperseBlob(stream.Context(), blob, "", "DisperseBlobStream", false)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(reply)
+}
+
+// receiveBlob reads the chunks of a blob until the flush chunk. The blob is returned along with the error, with the
+// data received so far, so that the failure can be accounted for.
+func (s *DispersalServer) receiveBlob(stream pb.Disperser_DisperseBlobStreamServer) (*core.Blob, error) {
+	maxSize := s.config.MaxStreamBufferSize
+	if maxSize <= 0 {
+		maxSize = core.MaxBlobSize
+	}
+
+	blob := &core.Blob{}
+	var data bytes.Buffer
+	for first := true; ; first = false {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return blob, status.Error(codes.InvalidArgument, "stream ended before the flush chunk")
+		}
+		if err != nil {
+			return blob, err
+		}
+
+		if first {
+			if err := validateSecurityParams(chunk.GetSecurityParams()); err != nil {
+				return blob, err
+			}
+			if err := validatePriority(chunk.GetPriority()); err != nil {
+				return blob, err
+			}
+			blob.RequestHeader = core.BlobRequestHeader{
+				SecurityParams: getSecurityParams(chunk.GetSecurityParams()),
+				TargetRowNum:   chunk.GetTargetRowNum(),
+				Priority:       uint8(chunk.GetPriority()),
+			}
+		}
+		if data.Len()+len(chunk.GetData()) > maxSize {
+			return blob, status.Errorf(codes.InvalidArgument, "blob size cannot exceed %v KiB", maxSize/1024)
+		}
+		data.Write(chunk.GetData())
+		blob.Data = data.Bytes()
+
+		if chunk.GetFlush() {
+			return blob, nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package apiserver_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func newStreamTestClient(t *testing.T, config disperser.ServerConfig) (pb.DisperserClient, disperser.BlobStore) {
+	logger := &mock.Logger{}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(config, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Shutdown)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewDisperserClient(conn), blobStore
+}
+
+func TestDisperseBlobStream(t *testing.T) {
+	client, blobStore := newStreamTestClient(t, disperser.ServerConfig{})
+	ctx := context.Background()
+
+	data := bytes.Repeat([]byte("streamed blob "), 1000)
+	stream, err := client.DisperseBlobStream(ctx)
+	assert.NoError(t, err)
+	chunkSize := 4096
+	for start := 0; start < len(data); start += chunkSize {
+		end := start + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := &pb.DisperseBlobChunk{Data: data[start:end], Flush: end == len(data)}
+		if start == 0 {
+			chunk.SecurityParams = []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 100}}
+			chunk.TargetRowNum = 4
+		}
+		assert.NoError(t, stream.Send(chunk))
+	}
+	reply, err := stream.CloseAndRecv()
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetResult())
+
+	blobKey, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(len(data)), metadata.RequestMetadata.BlobSize)
+	assert.Equal(t, uint32(4), metadata.RequestMetadata.TargetRowNum)
+	assert.Len(t, metadata.RequestMetadata.SecurityParams, 1)
+	content, err := blobStore.GetBlobContent(ctx, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, data, content)
+}
+
+func TestDisperseBlobStreamWithoutFlush(t *testing.T) {
+	client, blobStore := newStreamTestClient(t, disperser.ServerConfig{})
+	ctx := context.Background()
+
+	stream, err := client.DisperseBlobStream(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&pb.DisperseBlobChunk{Data: []byte("partial"), SecurityParams: testSecurityParams}))
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// nothing is stored for the partial blob
+	metadatas, err := blobStore.GetBlobMetadataByStatus(ctx, disperser.Processing)
+	assert.NoError(t, err)
+	assert.Len(t, metadatas, 0)
+}
+
+func TestDisperseBlobStreamTooLarge(t *testing.T) {
+	client, blobStore := newStreamTestClient(t, disperser.ServerConfig{MaxStreamBufferSize: 10})
+	ctx := context.Background()
+
+	stream, err := client.DisperseBlobStream(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&pb.DisperseBlobChunk{Data: []byte("0123456"), SecurityParams: testSecurityParams}))
+	assert.NoError(t, stream.Send(&pb.DisperseBlobChunk{Data: []byte("789ab"), Flush: true}))
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	metadatas, err := blobStore.GetBlobMetadataByStatus(ctx, disperser.Processing)
+	assert.NoError(t, err)
+	assert.Len(t, metadatas, 0)
+}
@@ -0,0 +1,48 @@
+package apiserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+)
+
+// loadTLSConfig loads the server certificate and the client CA of the TLS config. It returns nil if the server
+// certificate is not set, i.e. the server is plaintext.
+func loadTLSConfig(config disperser.TLSConfig) (*tls.Config, error) {
+	if config.CertFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if config.ClientCAFile == "" {
+		if config.RequireClientCert {
+			return nil, fmt.Errorf("a client CA is required to verify the client certificates")
+		}
+		return tlsConfig, nil
+	}
+	caPEM, err := os.ReadFile(config.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the client CA: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in the client CA file %s", config.ClientCAFile)
+	}
+	tlsConfig.ClientCAs = clientCAs
+	// the certificates the clients present are always verified, even if they are not required
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if config.RequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
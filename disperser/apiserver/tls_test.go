@@ -0,0 +1,147 @@
+package apiserver_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert creates a certificate signed by the parent, or a self-signed CA if parent is nil
+func newTestCert(t *testing.T, commonName string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signerCert, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeTestFile(t *testing.T, dir string, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+// startTLSTestServer serves a test server with mutual TLS, and returns its address and the CA of its certificates
+func startTLSTestServer(t *testing.T, requireClientCert bool) (string, *testCert) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "test-ca", nil)
+	serverCert := newTestCert(t, "disperser", ca)
+	server := newTestServer(disperser.ServerConfig{TLSConfig: disperser.TLSConfig{
+		CertFile:          writeTestFile(t, dir, "server.crt", serverCert.certPEM),
+		KeyFile:           writeTestFile(t, dir, "server.key", serverCert.keyPEM),
+		ClientCAFile:      writeTestFile(t, dir, "ca.crt", ca.certPEM),
+		RequireClientCert: requireClientCert,
+	}})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Shutdown)
+	return listener.Addr().String(), ca
+}
+
+// disperseOverTLS disperses a blob to the server with the given client certificate, if any
+func disperseOverTLS(t *testing.T, addr string, ca *testCert, clientCert *testCert) error {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	tlsConfig := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	if clientCert != nil {
+		cert, err := tls.X509KeyPair(clientCert.certPEM, clientCert.keyPEM)
+		require.NoError(t, err)
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = pb.NewDisperserClient(conn).DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("mtls blob"), SecurityParams: testSecurityParams})
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	addr, ca := startTLSTestServer(t, true)
+
+	// a client with a certificate from the CA
+	assert.NoError(t, disperseOverTLS(t, addr, ca, newTestCert(t, "rollup", ca)))
+
+	// a client without a certificate
+	assert.Error(t, disperseOverTLS(t, addr, ca, nil))
+
+	// a client with a certificate from another CA
+	otherCA := newTestCert(t, "other-ca", nil)
+	assert.Error(t, disperseOverTLS(t, addr, ca, newTestCert(t, "rollup", otherCA)))
+
+	// a plaintext client
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = pb.NewDisperserClient(conn).DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte("plaintext blob"), SecurityParams: testSecurityParams})
+	assert.Error(t, err)
+}
+
+func TestOptionalClientCert(t *testing.T) {
+	addr, ca := startTLSTestServer(t, false)
+
+	assert.NoError(t, disperseOverTLS(t, addr, ca, nil))
+	assert.NoError(t, disperseOverTLS(t, addr, ca, newTestCert(t, "rollup", ca)))
+}
@@ -0,0 +1,82 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ExtendBlobTTL adds the requested duration, capped at MaxTTLExtension, to the expiry of the blob. The blobs which never
+// expire are left as they are. The extension is rate limited as a request of zero bytes, so it doesn't consume data quota.
+func (s *DispersalServer) ExtendBlobTTL(ctx context.Context, req *pb.ExtendBlobTTLRequest) (*pb.ExtendBlobTTLReply, error) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("ExtendBlobTTL", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+
+	if s.config.MaxTTLExtension <= 0 {
+		return nil, status.Error(codes.Unimplemented, "blob TTL extension is disabled")
+	}
+	requestID := req.GetRequestId()
+	if len(requestID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "request_id must not be empty")
+	}
+	if req.GetExtensionSeconds() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "extension_seconds must be greater than 0")
+	}
+	blobKey, err := disperser.ParseBlobKey(string(requestID))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request_id: %v", err)
+	}
+
+	metadata, err := s.blobStore.GetBlobMetadata(ctx, blobKey)
+	if errors.Is(err, disperser.ErrBlobNotFound) || (err == nil && metadata == nil) {
+		return nil, status.Error(codes.NotFound, "blob not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if metadata.BlobStatus == disperser.Failed || metadata.BlobStatus == disperser.InsufficientSignatures {
+		return nil, status.Errorf(codes.FailedPrecondition, "blob is %s", metadata.BlobStatus)
+	}
+	if metadata.Expiry == 0 {
+		return &pb.ExtendBlobTTLReply{Expiry: 0}, nil
+	}
+
+	rateConfig, _ := s.getRateConfig()
+	origin, err := common.GetClientAddress(ctx, rateConfig.ClientIPHeader, 2, true)
+	if err != nil {
+		return nil, err
+	}
+	accountID := getPeerCertFields(ctx)["commonName"]
+	if accountID == "" {
+		accountID = origin
+	}
+	if metadata.RequestMetadata != nil {
+		if err := s.checkRateLimits(ctx, accountID, 0, metadata.RequestMetadata.SecurityParams, "ExtendBlobTTL", false); err != nil {
+			return nil, err
+		}
+	}
+
+	extensionSeconds := req.GetExtensionSeconds()
+	if maxSeconds := uint64(s.config.MaxTTLExtension / time.Second); extensionSeconds > maxSeconds {
+		extensionSeconds = maxSeconds
+	}
+	newExpiry := metadata.Expiry + extensionSeconds
+	if err := s.blobStore.UpdateBlobExpiry(ctx, metadata, newExpiry); err != nil {
+		if errors.Is(err, disperser.ErrBlobNotFound) {
+			return nil, status.Error(codes.NotFound, "blob not found")
+		}
+		s.logger.Error("[apiserver] failed to extend blob TTL", "blobKey", blobKey.String(), "err", err)
+		return nil, status.Error(codes.Internal, "failed to extend blob TTL")
+	}
+	s.logger.Info("[apiserver] blob TTL extended", "blobKey", blobKey.String(), "accountID", accountID, "expiry", newExpiry)
+	return &pb.ExtendBlobTTLReply{Expiry: newExpiry}, nil
+}
@@ -0,0 +1,106 @@
+package apiserver_test
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExtendBlobTTL(t *testing.T) {
+	server, blobStore := newTestServerWithBlobStore(disperser.ServerConfig{MaxTTLExtension: time.Hour})
+	ctx, _ := newTestContext()
+
+	disperse := func(data string, expiry uint64) (*disperser.BlobMetadata, []byte) {
+		reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: []byte(data), SecurityParams: testSecurityParams})
+		assert.NoError(t, err)
+		blobKey, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+		assert.NoError(t, err)
+		metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+		assert.NoError(t, err)
+		assert.NoError(t, blobStore.UpdateBlobExpiry(ctx, metadata, expiry))
+		return metadata, reply.GetRequestId()
+	}
+	metadata, requestID := disperse("extended blob", 1000)
+
+	reply, err := server.ExtendBlobTTL(ctx, &pb.ExtendBlobTTLRequest{RequestId: requestID, ExtensionSeconds: 600})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1600), reply.GetExpiry())
+	metadata, err = blobStore.GetBlobMetadata(ctx, metadata.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1600), metadata.Expiry)
+
+	// capped at the max extension
+	reply, err = server.ExtendBlobTTL(ctx, &pb.ExtendBlobTTLRequest{RequestId: requestID, ExtensionSeconds: 10 * 3600})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1600+3600), reply.GetExpiry())
+
+	// blobs which never expire are left as they are
+	_, requestID = disperse("kept blob", 0)
+	reply, err = server.ExtendBlobTTL(ctx, &pb.ExtendBlobTTLRequest{RequestId: requestID, ExtensionSeconds: 600})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), reply.GetExpiry())
+
+	// failed blobs can't be extended
+	metadata, requestID = disperse("failed blob", 1000)
+	assert.NoError(t, blobStore.MarkBlobFailed(ctx, metadata.GetBlobKey()))
+	_, err = server.ExtendBlobTTL(ctx, &pb.ExtendBlobTTLRequest{RequestId: requestID, ExtensionSeconds: 600})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = server.ExtendBlobTTL(ctx, &pb.ExtendBlobTTLRequest{RequestId: []byte("0000-0000"), ExtensionSeconds: 600})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = server.ExtendBlobTTL(ctx, &pb.ExtendBlobTTLRequest{RequestId: requestID})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// disabled
+	server = newTestServer(disperser.ServerConfig{})
+	_, err = server.ExtendBlobTTL(ctx, &pb.ExtendBlobTTLRequest{RequestId: requestID, ExtensionSeconds: 600})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestExtendBlobTTLDoesNotConsumeDataQuota(t *testing.T) {
+	const generous = 1_000_000_000
+
+	logger := &mock.Logger{}
+	bucketStore, err := store.NewLocalParamStore[common.RateBucketParams](100)
+	assert.NoError(t, err)
+	ratelimiter := ratelimit.NewRateLimiter(common.GlobalRateParams{
+		BucketSizes: []time.Duration{10 * time.Second},
+		Multipliers: []float32{1},
+	}, bucketStore, nil, nil, nil, logger)
+	// a blob of 1000 bytes consumes the whole 10s account bucket
+	rateConfig := apiserver.RateConfig{QuorumRateInfos: map[core.QuorumID]apiserver.QuorumRateInfo{
+		0: {TotalUnauthThroughput: generous, TotalUnauthBlobRate: generous, PerUserUnauthThroughput: 100, PerUserUnauthBlobRate: generous},
+	}}
+	blobStore := memorydb.NewBlobStore(1024*1024, logger)
+	server := apiserver.NewDispersalServer(disperser.ServerConfig{MaxTTLExtension: time.Hour}, blobStore, logger, disperser.NewMetrics("9100", logger), ratelimiter, rateConfig, true, nil, eth_common.Hash{}, nil)
+	ctx, _ := newTestContext()
+
+	securityParams := []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 80}}
+	reply, err := server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: make([]byte, 1000), SecurityParams: securityParams})
+	assert.NoError(t, err)
+	_, err = server.DisperseBlob(ctx, &pb.DisperseBlobRequest{Data: make([]byte, 1001), SecurityParams: securityParams})
+	assert.ErrorContains(t, err, "account")
+
+	blobKey, err := disperser.ParseBlobKey(string(reply.GetRequestId()))
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, blobKey)
+	assert.NoError(t, err)
+	assert.NoError(t, blobStore.UpdateBlobExpiry(ctx, metadata, 1000))
+	for i := 0; i < 3; i++ {
+		_, err = server.ExtendBlobTTL(ctx, &pb.ExtendBlobTTLRequest{RequestId: reply.GetRequestId(), ExtensionSeconds: 60})
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,105 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultWatchTimeout is the duration of the blob status watches if not configured
+	defaultWatchTimeout = 10 * time.Minute
+	// watchPollInterval is the interval the watched blob status is re-read at, in case it's changed by another process
+	watchPollInterval = 5 * time.Second
+)
+
+var errWatchTimeout = status.Error(codes.DeadlineExceeded, "blob status watch timed out")
+
+// WithBlobStatusHub makes the blob status watches notified by the hub, so that they see the status changes
+// published to it without waiting for the next poll
+func WithBlobStatusHub(hub *disperser.BlobStatusHub) ServerOption {
+	return func(s *DispersalServer) {
+		s.statusHub = hub
+	}
+}
+
+// WatchBlobStatus streams the blob status replies, the current one first, then one each time the status changes
+func (s *DispersalServer) WatchBlobStatus(req *pb.BlobStatusRequest, stream pb.Disperser_WatchBlobStatusServer) error {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
+		s.metrics.ObserveLatency("WatchBlobStatus", f*1000) // make milliseconds
+	}))
+	defer timer.ObserveDuration()
+
+	return s.watchBlobStatus(stream.Context(), req, stream.Send)
+}
+
+// watchBlobStatus calls send with the current blob status reply, then with a new reply each time the status changes.
+// It returns nil once the status is final or the server shuts down, errWatchTimeout if the watch times out
+// and the error of the context if it's done otherwise.
+func (s *DispersalServer) watchBlobStatus(ctx context.Context, req *pb.BlobStatusRequest, send func(*pb.BlobStatusReply) error) error {
+	timeout := s.config.WatchTimeout
+	if timeout <= 0 {
+		timeout = defaultWatchTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	s.logger.Info("[apiserver] received a new blob status watch", "requestID", string(req.GetRequestId()))
+	var updated <-chan struct{}
+	if s.statusHub != nil {
+		key, err := disperser.ParseBlobKey(string(req.GetRequestId()))
+		if err != nil {
+			return err
+		}
+		var unsubscribe func()
+		updated, unsubscribe = s.statusHub.Subscribe(key)
+		defer unsubscribe()
+	}
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	lastStatus := pb.BlobStatus_UNKNOWN
+	for {
+		reply, err := s.getBlobStatus(ctx, req)
+		if err != nil {
+			return err
+		}
+		if reply.GetStatus() != lastStatus {
+			if err := send(reply); err != nil {
+				return err
+			}
+			lastStatus = reply.GetStatus()
+		}
+		if isFinalStatus(lastStatus) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errWatchTimeout
+			}
+			return ctx.Err()
+		case <-s.stopWatches:
+			return nil
+		case <-updated:
+		case <-ticker.C:
+		}
+	}
+}
+
+// isFinalStatus returns whether the blob status can't change anymore
+func isFinalStatus(status pb.BlobStatus) bool {
+	switch status {
+	case pb.BlobStatus_FINALIZED, pb.BlobStatus_FAILED, pb.BlobStatus_INSUFFICIENT_SIGNATURES:
+		return true
+	default:
+		return false
+	}
+}
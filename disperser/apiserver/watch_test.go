@@ -0,0 +1,173 @@
+package apiserver_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/0glabs/0g-data-avail/api/grpc/disperser"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/apiserver"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// newWatchTestServer returns a server whose blob status changes made through the returned blob store are
+// published to the returned hub, as in the combined server
+func newWatchTestServer(config disperser.ServerConfig) (*apiserver.DispersalServer, disperser.BlobStore, *disperser.BlobStatusHub) {
+	logger := &mock.Logger{}
+	hub := disperser.NewBlobStatusHub()
+	blobStore := disperser.NewNotifyingBlobStore(memorydb.NewBlobStore(1024*1024, logger), hub)
+	metrics := disperser.NewMetrics("9100", logger)
+	server := apiserver.NewDispersalServer(config, blobStore, logger, metrics, nil, apiserver.RateConfig{}, true, nil, eth_common.Hash{}, nil, apiserver.WithBlobStatusHub(hub))
+	return server, blobStore, hub
+}
+
+func newWatchTestClient(t *testing.T, server *apiserver.DispersalServer) pb.DisperserClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Shutdown)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewDisperserClient(conn)
+}
+
+func disperseWatchedBlob(t *testing.T, client pb.DisperserClient, blobStore disperser.BlobStore) (string, *disperser.BlobMetadata) {
+	reply, err := client.DisperseBlob(context.Background(), &pb.DisperseBlobRequest{
+		Data:           []byte("watched blob"),
+		SecurityParams: []*pb.SecurityParams{{QuorumId: 0, AdversaryThreshold: 50, QuorumThreshold: 100}},
+	})
+	assert.NoError(t, err)
+	requestID := string(reply.GetRequestId())
+	blobKey, err := disperser.ParseBlobKey(requestID)
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(context.Background(), blobKey)
+	assert.NoError(t, err)
+	return requestID, metadata
+}
+
+func TestWatchBlobStatus(t *testing.T) {
+	server, blobStore, _ := newWatchTestServer(disperser.ServerConfig{})
+	client := newWatchTestClient(t, server)
+	ctx := context.Background()
+	requestID, metadata := disperseWatchedBlob(t, client, blobStore)
+
+	stream, err := client.WatchBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: []byte(requestID)})
+	assert.NoError(t, err)
+	reply, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetStatus())
+
+	// the changes are pushed well before the next poll
+	start := time.Now()
+	_, err = blobStore.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{BatchHeaderHash: [32]byte{1}, BlobIndex: 2, CommitmentRoot: []byte{0xc0}})
+	assert.NoError(t, err)
+	reply, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_CONFIRMED, reply.GetStatus())
+	assert.Equal(t, uint32(2), reply.GetInfo().GetBlobVerificationProof().GetBlobIndex())
+	assert.Equal(t, []byte{0xc0}, reply.GetInfo().GetBlobHeader().GetCommitmentRoot())
+
+	assert.NoError(t, blobStore.MarkBlobFinalized(ctx, metadata.GetBlobKey()))
+	reply, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_FINALIZED, reply.GetStatus())
+	assert.Less(t, time.Since(start), time.Second)
+
+	// the stream ends once the status is final
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestWatchBlobStatusTimeout(t *testing.T) {
+	server, blobStore, _ := newWatchTestServer(disperser.ServerConfig{WatchTimeout: 200 * time.Millisecond})
+	client := newWatchTestClient(t, server)
+	requestID, _ := disperseWatchedBlob(t, client, blobStore)
+
+	stream, err := client.WatchBlobStatus(context.Background(), &pb.BlobStatusRequest{RequestId: []byte(requestID)})
+	assert.NoError(t, err)
+	reply, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, pb.BlobStatus_PROCESSING, reply.GetStatus())
+	_, err = stream.Recv()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestWatchBlobStatusClientGone(t *testing.T) {
+	server, blobStore, hub := newWatchTestServer(disperser.ServerConfig{})
+	client := newWatchTestClient(t, server)
+	requestID, metadata := disperseWatchedBlob(t, client, blobStore)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.WatchBlobStatus(ctx, &pb.BlobStatusRequest{RequestId: []byte(requestID)})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, hub.NumSubscribers(metadata.GetBlobKey()))
+
+	// the subscription is released once the client disconnects
+	cancel()
+	assert.Eventually(t, func() bool {
+		return hub.NumSubscribers(metadata.GetBlobKey()) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWatchBlobStatusEvents(t *testing.T) {
+	server, blobStore, _ := newWatchTestServer(disperser.ServerConfig{})
+	httpServer := httptest.NewServer(apiserver.NewHTTPGatewayServer(server, "0").Handler())
+	defer httpServer.Close()
+	ctx := context.Background()
+	requestID, metadata := disperseWatchedBlob(t, newWatchTestClient(t, server), blobStore)
+
+	resp, err := http.Get(httpServer.URL + "/v1/blob/" + requestID + "/events")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := bufio.NewScanner(resp.Body)
+	nextStatus := func() string {
+		var event, data string
+		for events.Scan() {
+			line := events.Text()
+			if line == "" {
+				break
+			}
+			if strings.HasPrefix(line, "event: ") {
+				event = strings.TrimPrefix(line, "event: ")
+			}
+			if strings.HasPrefix(line, "data: ") {
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+		assert.Equal(t, "status", event)
+		var reply struct {
+			Status string `json:"status"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(data), &reply))
+		return reply.Status
+	}
+
+	assert.Equal(t, "PROCESSING", nextStatus())
+	assert.NoError(t, blobStore.MarkBlobFailed(ctx, metadata.GetBlobKey()))
+	assert.Equal(t, "FAILED", nextStatus())
+	assert.False(t, events.Scan())
+}
@@ -0,0 +1,45 @@
+package disperser
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SubmissionAttestationHash returns sha3(requestID || requestedAt), the message signed by the disperser to attest
+// it received the blob with the given request ID at requestedAt (in nanoseconds). requestedAt is encoded as a
+// big endian uint64.
+func SubmissionAttestationHash(requestID []byte, requestedAt uint64) [32]byte {
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], requestedAt)
+	return crypto.Keccak256Hash(requestID, timestamp[:])
+}
+
+// SignSubmissionAttestation signs the submission attestation of the blob with the disperser key
+func SignSubmissionAttestation(requestID []byte, requestedAt uint64, key *ecdsa.PrivateKey) ([]byte, error) {
+	hash := SubmissionAttestationHash(requestID, requestedAt)
+	signature, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign submission attestation: %w", err)
+	}
+	return signature, nil
+}
+
+// VerifySubmissionAttestation checks the signature of the submission attestation against the disperser public key,
+// either compressed or uncompressed
+func VerifySubmissionAttestation(requestID []byte, requestedAt uint64, signature []byte, pubkey []byte) error {
+	// drop the recovery id, if any
+	if len(signature) != crypto.SignatureLength && len(signature) != crypto.SignatureLength-1 {
+		return fmt.Errorf("invalid attestation signature length %v", len(signature))
+	}
+	if len(pubkey) == 0 {
+		return fmt.Errorf("missing attestation public key")
+	}
+	hash := SubmissionAttestationHash(requestID, requestedAt)
+	if !crypto.VerifySignature(pubkey, hash[:], signature[:crypto.SignatureLength-1]) {
+		return fmt.Errorf("invalid attestation signature")
+	}
+	return nil
+}
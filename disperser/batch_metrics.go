@@ -0,0 +1,92 @@
+package disperser
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// BatchMetricsEvent is the outcome of a batch at the end of its cycle, once it was confirmed or failed
+type BatchMetricsEvent struct {
+	// BlobCount is the number of blobs of the batch
+	BlobCount int
+	// TotalBytes is the unencoded size of the blobs of the batch
+	TotalBytes uint64
+	// EncodingDuration is the time it took to assemble the encoded blobs into a batch and disperse it
+	EncodingDuration time.Duration
+	// ConfirmationDuration is the time from the dispersal of the batch until it was confirmed or failed, 0 if it
+	// failed before it was dispersed
+	ConfirmationDuration time.Duration
+	Success              bool
+}
+
+// BatchMetrics are the per-batch aggregates of the batches processed by the batcher
+type BatchMetrics struct {
+	BlobCount            prometheus.Histogram
+	TotalBytes           prometheus.Histogram
+	EncodingDuration     prometheus.Histogram
+	ConfirmationDuration prometheus.Histogram
+	// BatchTotal is the number of processed batches, labeled by status (success or failure)
+	BatchTotal *prometheus.CounterVec
+}
+
+func NewBatchMetrics(reg *prometheus.Registry, namespace string) *BatchMetrics {
+	return &BatchMetrics{
+		BlobCount: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "batch_blob_count",
+				Help:      "the number of blobs of the processed batches",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+			},
+		),
+		TotalBytes: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "batch_total_bytes",
+				Help:      "the unencoded size in bytes of the blobs of the processed batches",
+				Buckets:   prometheus.ExponentialBuckets(1024, 4, 12),
+			},
+		),
+		EncodingDuration: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "batch_encoding_duration_seconds",
+				Help:      "the time it took to assemble the processed batches and disperse them",
+				Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+			},
+		),
+		ConfirmationDuration: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "batch_confirmation_duration_seconds",
+				Help:      "the time from the dispersal of the processed batches until they were confirmed or failed",
+				Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12),
+			},
+		),
+		BatchTotal: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "batch_total_count",
+				Help:      "the number of processed batches, by status",
+			},
+			[]string{"status"},
+		),
+	}
+}
+
+// RecordBatchProcessed records the outcome of a batch at the end of its cycle
+func (m *BatchMetrics) RecordBatchProcessed(event BatchMetricsEvent) {
+	status := "failure"
+	if event.Success {
+		status = "success"
+	}
+	m.BatchTotal.WithLabelValues(status).Inc()
+	m.BlobCount.Observe(float64(event.BlobCount))
+	m.TotalBytes.Observe(float64(event.TotalBytes))
+	m.EncodingDuration.Observe(event.EncodingDuration.Seconds())
+	if event.ConfirmationDuration > 0 {
+		m.ConfirmationDuration.Observe(event.ConfirmationDuration.Seconds())
+	}
+}
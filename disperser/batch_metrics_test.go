@@ -0,0 +1,36 @@
+package disperser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveBatch(t *testing.T) {
+	metrics := disperser.NewMetrics("9100", &mock.Logger{})
+	metrics.ObserveBatch(disperser.BatchMetricsEvent{
+		BlobCount:            3,
+		TotalBytes:           3000,
+		EncodingDuration:     time.Second,
+		ConfirmationDuration: 10 * time.Second,
+		Success:              true,
+	})
+	// a batch failing before it's dispersed has no confirmation duration
+	metrics.ObserveBatch(disperser.BatchMetricsEvent{BlobCount: 1, TotalBytes: 100, EncodingDuration: time.Second})
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.BatchTotal.WithLabelValues("success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.BatchTotal.WithLabelValues("failure")))
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.BatchTotal))
+	count, err := testutil.GatherAndCount(metrics.Registry(),
+		"zgda_disperser_batch_blob_count",
+		"zgda_disperser_batch_total_bytes",
+		"zgda_disperser_batch_encoding_duration_seconds",
+		"zgda_disperser_batch_confirmation_duration_seconds",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
@@ -0,0 +1,165 @@
+package batcher
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+)
+
+const (
+	FIFOBatchFormation             = "fifo"
+	PriorityWeightedBatchFormation = "priority-weighted"
+	PriorityBatchFormation         = "priority"
+)
+
+// BatchFormationStrategy selects the blobs of the next batch among the encoded blobs.
+// The blobs which are not selected are left for the following batches.
+type BatchFormationStrategy interface {
+	// SelectBlobs selects at most maxCount blobs whose total size doesn't exceed maxBytes, a limit of 0 means no limit.
+	// At least one blob is selected if there are any, even if it exceeds maxBytes, so that large blobs are not starved.
+	SelectBlobs(all []*disperser.BlobMetadata, maxBytes uint, maxCount int) []*disperser.BlobMetadata
+}
+
+// NewBatchFormationStrategy returns the strategy with the given name, either fifo, priority-weighted or priority.
+// The blobs waiting for longer than maxStarvationAge are batched first by the priority strategy.
+func NewBatchFormationStrategy(name string, maxStarvationAge time.Duration) (BatchFormationStrategy, error) {
+	switch name {
+	case FIFOBatchFormation:
+		return FIFOStrategy{}, nil
+	case PriorityWeightedBatchFormation:
+		return NewPriorityWeightedStrategy(RetryPriority, time.Now().UnixNano()), nil
+	case PriorityBatchFormation:
+		return PriorityStrategy{MaxStarvationAge: maxStarvationAge}, nil
+	default:
+		return nil, fmt.Errorf("unknown batch formation strategy: %s", name)
+	}
+}
+
+// FIFOStrategy selects the blobs in the order they were requested in
+type FIFOStrategy struct{}
+
+func (FIFOStrategy) SelectBlobs(all []*disperser.BlobMetadata, maxBytes uint, maxCount int) []*disperser.BlobMetadata {
+	sorted := make([]*disperser.BlobMetadata, len(all))
+	copy(sorted, all)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].RequestMetadata.RequestedAt < sorted[j].RequestMetadata.RequestedAt
+	})
+
+	limits := batchLimits{maxBytes: maxBytes, maxCount: maxCount}
+	selected := make([]*disperser.BlobMetadata, 0, len(sorted))
+	for _, metadata := range sorted {
+		// the blobs are not reordered to fill the batch
+		if !limits.fits(metadata) {
+			break
+		}
+		limits.add(metadata)
+		selected = append(selected, metadata)
+	}
+	return selected
+}
+
+// PriorityStrategy selects the blobs by the priority of their request, highest first, then in the order they were
+// requested in. The blobs waiting for longer than MaxStarvationAge are selected first regardless of their priority,
+// they are not promoted if it is 0.
+type PriorityStrategy struct {
+	MaxStarvationAge time.Duration
+}
+
+func (s PriorityStrategy) SelectBlobs(all []*disperser.BlobMetadata, maxBytes uint, maxCount int) []*disperser.BlobMetadata {
+	queue := newBlobQueue(all, s.MaxStarvationAge, time.Now())
+	limits := batchLimits{maxBytes: maxBytes, maxCount: maxCount}
+	selected := make([]*disperser.BlobMetadata, 0, len(all))
+	for queue.Len() > 0 {
+		metadata := queue.next()
+		// the lower priority blobs don't skip the blob which doesn't fit
+		if !limits.fits(metadata) {
+			break
+		}
+		limits.add(metadata)
+		selected = append(selected, metadata)
+	}
+	return selected
+}
+
+// RetryPriority is the priority of a blob growing with the number of times it has been retried,
+// as retried blobs have already been delayed
+func RetryPriority(metadata *disperser.BlobMetadata) float64 {
+	return float64(metadata.NumRetries + 1)
+}
+
+// PriorityWeightedStrategy selects the blobs in a random order, weighted by their priority,
+// so higher priority blobs are more likely to be batched first while lower priority ones are not starved
+type PriorityWeightedStrategy struct {
+	// Priority returns the positive priority of a blob
+	Priority func(metadata *disperser.BlobMetadata) float64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewPriorityWeightedStrategy creates a PriorityWeightedStrategy with the given priority and random seed
+func NewPriorityWeightedStrategy(priority func(metadata *disperser.BlobMetadata) float64, seed int64) *PriorityWeightedStrategy {
+	return &PriorityWeightedStrategy{
+		Priority: priority,
+		rand:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *PriorityWeightedStrategy) SelectBlobs(all []*disperser.BlobMetadata, maxBytes uint, maxCount int) []*disperser.BlobMetadata {
+	remaining := make([]*disperser.BlobMetadata, len(all))
+	copy(remaining, all)
+	weights := make([]float64, len(remaining))
+	totalWeight := 0.0
+	for i, metadata := range remaining {
+		weights[i] = s.Priority(metadata)
+		totalWeight += weights[i]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limits := batchLimits{maxBytes: maxBytes, maxCount: maxCount}
+	selected := make([]*disperser.BlobMetadata, 0, len(remaining))
+	for len(remaining) > 0 && (maxCount <= 0 || len(selected) < maxCount) {
+		// draw a blob with a probability proportional to its priority
+		i := 0
+		for target := s.rand.Float64() * totalWeight; i < len(remaining)-1 && target >= weights[i]; i++ {
+			target -= weights[i]
+		}
+		metadata := remaining[i]
+		if limits.fits(metadata) {
+			limits.add(metadata)
+			selected = append(selected, metadata)
+		}
+		totalWeight -= weights[i]
+		remaining = append(remaining[:i], remaining[i+1:]...)
+		weights = append(weights[:i], weights[i+1:]...)
+	}
+	return selected
+}
+
+// batchLimits tracks the size of the batch being formed
+type batchLimits struct {
+	maxBytes uint
+	maxCount int
+	bytes    uint
+	count    int
+}
+
+func (l *batchLimits) fits(metadata *disperser.BlobMetadata) bool {
+	if l.count == 0 {
+		return true
+	}
+	if l.maxCount > 0 && l.count >= l.maxCount {
+		return false
+	}
+	return l.maxBytes == 0 || l.bytes+metadata.RequestMetadata.BlobSize <= l.maxBytes
+}
+
+func (l *batchLimits) add(metadata *disperser.BlobMetadata) {
+	l.bytes += metadata.RequestMetadata.BlobSize
+	l.count++
+}
@@ -0,0 +1,168 @@
+package batcher_test
+
+import (
+	"testing"
+	"time"
+
+	cmock "github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/batcher"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFormationMetadata(hash string, requestedAt uint64, blobSize uint, numRetries uint) *disperser.BlobMetadata {
+	return newPriorityMetadata(hash, requestedAt, blobSize, numRetries, 0)
+}
+
+func newPriorityMetadata(hash string, requestedAt uint64, blobSize uint, numRetries uint, priority uint8) *disperser.BlobMetadata {
+	return &disperser.BlobMetadata{
+		BlobHash:     hash,
+		MetadataHash: hash,
+		NumRetries:   numRetries,
+		RequestMetadata: &disperser.RequestMetadata{
+			BlobRequestHeader: core.BlobRequestHeader{Priority: priority},
+			BlobSize:          blobSize,
+			RequestedAt:       requestedAt,
+		},
+	}
+}
+
+func TestFIFOStrategy(t *testing.T) {
+	first := newFormationMetadata("first", 1, 100, 0)
+	second := newFormationMetadata("second", 2, 100, 0)
+	third := newFormationMetadata("third", 3, 100, 0)
+	all := []*disperser.BlobMetadata{third, first, second}
+
+	strategy := batcher.FIFOStrategy{}
+	assert.Equal(t, []*disperser.BlobMetadata{first, second, third}, strategy.SelectBlobs(all, 0, 0))
+	assert.Equal(t, []*disperser.BlobMetadata{first, second}, strategy.SelectBlobs(all, 0, 2))
+	assert.Equal(t, []*disperser.BlobMetadata{first, second}, strategy.SelectBlobs(all, 250, 0))
+	// the order is kept even if a later blob would fit
+	small := newFormationMetadata("small", 4, 10, 0)
+	assert.Equal(t, []*disperser.BlobMetadata{first, second}, strategy.SelectBlobs(append(all, small), 250, 0))
+	// a blob larger than the limit is still batched alone
+	assert.Equal(t, []*disperser.BlobMetadata{first}, strategy.SelectBlobs(all, 50, 0))
+	assert.Empty(t, strategy.SelectBlobs(nil, 0, 0))
+	// the input is not modified
+	assert.Equal(t, []*disperser.BlobMetadata{third, first, second}, all)
+}
+
+func TestPriorityWeightedStrategy(t *testing.T) {
+	low := newFormationMetadata("low", 1, 100, 0)
+	high := newFormationMetadata("high", 2, 100, 3)
+	strategy := batcher.NewPriorityWeightedStrategy(batcher.RetryPriority, 42)
+
+	// the blob with 4 times the priority is selected first about 80% of the time
+	const trials = 1000
+	highFirst := 0
+	for i := 0; i < trials; i++ {
+		selected := strategy.SelectBlobs([]*disperser.BlobMetadata{low, high}, 0, 1)
+		assert.Len(t, selected, 1)
+		if selected[0] == high {
+			highFirst++
+		}
+	}
+	assert.Greater(t, highFirst, 700)
+	assert.Less(t, highFirst, 900)
+
+	// all the blobs are selected without limits, and the limits are respected otherwise
+	assert.ElementsMatch(t, []*disperser.BlobMetadata{low, high}, strategy.SelectBlobs([]*disperser.BlobMetadata{low, high}, 0, 0))
+	assert.Len(t, strategy.SelectBlobs([]*disperser.BlobMetadata{low, high}, 150, 0), 1)
+}
+
+func TestPriorityStrategy(t *testing.T) {
+	now := uint64(time.Now().UnixNano())
+	low := newPriorityMetadata("low", now-3, 100, 0, 0)
+	high := newPriorityMetadata("high", now-1, 100, 0, 255)
+	firstMedium := newPriorityMetadata("first medium", now-2, 100, 0, 100)
+	secondMedium := newPriorityMetadata("second medium", now, 100, 0, 100)
+	all := []*disperser.BlobMetadata{low, secondMedium, high, firstMedium}
+
+	strategy := batcher.PriorityStrategy{MaxStarvationAge: time.Minute}
+	assert.Equal(t, []*disperser.BlobMetadata{high, firstMedium, secondMedium, low}, strategy.SelectBlobs(all, 0, 0))
+	assert.Equal(t, []*disperser.BlobMetadata{high, firstMedium}, strategy.SelectBlobs(all, 0, 2))
+	assert.Equal(t, []*disperser.BlobMetadata{high, firstMedium}, strategy.SelectBlobs(all, 250, 0))
+	// a blob larger than the limit is still batched alone
+	assert.Equal(t, []*disperser.BlobMetadata{high}, strategy.SelectBlobs(all, 50, 0))
+	assert.Empty(t, strategy.SelectBlobs(nil, 0, 0))
+	// the input is not modified
+	assert.Equal(t, []*disperser.BlobMetadata{low, secondMedium, high, firstMedium}, all)
+
+	// the starved blobs go first regardless of their priority, in the order they were requested in
+	starved := newPriorityMetadata("starved", now-uint64(2*time.Minute), 100, 0, 0)
+	starvedHigh := newPriorityMetadata("starved high", now-uint64(time.Hour), 100, 0, 200)
+	assert.Equal(t, []*disperser.BlobMetadata{starvedHigh, starved, high, firstMedium, secondMedium, low}, strategy.SelectBlobs(append(all, starved, starvedHigh), 0, 0))
+	// the blobs are not promoted without a starvation age
+	strategy = batcher.PriorityStrategy{}
+	assert.Equal(t, []*disperser.BlobMetadata{high, starvedHigh, firstMedium, secondMedium, starved, low}, strategy.SelectBlobs(append(all, starved, starvedHigh), 0, 0))
+}
+
+func TestSortByPriority(t *testing.T) {
+	now := time.Unix(1000, 0)
+	var blobs []*disperser.BlobMetadata
+	for i := 0; i < 100; i++ {
+		blobs = append(blobs, newPriorityMetadata(string(rune('a'+i)), uint64(now.Add(-time.Duration(i*7%100)*time.Second).UnixNano()), 1, 0, uint8(i*37)))
+	}
+
+	sorted := batcher.SortByPriority(blobs, 90*time.Second, now)
+	assert.Len(t, sorted, len(blobs))
+	starvedBefore := uint64(now.Add(-90 * time.Second).UnixNano())
+	starved := func(m *disperser.BlobMetadata) bool { return m.RequestMetadata.RequestedAt < starvedBefore }
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1], sorted[i]
+		switch {
+		case starved(prev) && starved(cur):
+			assert.LessOrEqual(t, prev.RequestMetadata.RequestedAt, cur.RequestMetadata.RequestedAt)
+		case starved(prev):
+		case starved(cur):
+			t.Fatalf("starved blob %s is after blob %s", cur.BlobHash, prev.BlobHash)
+		case prev.RequestMetadata.Priority == cur.RequestMetadata.Priority:
+			assert.LessOrEqual(t, prev.RequestMetadata.RequestedAt, cur.RequestMetadata.RequestedAt)
+		default:
+			assert.Greater(t, prev.RequestMetadata.Priority, cur.RequestMetadata.Priority)
+		}
+	}
+}
+
+func TestNewBatchFormationStrategy(t *testing.T) {
+	strategy, err := batcher.NewBatchFormationStrategy("fifo", 0)
+	assert.NoError(t, err)
+	assert.IsType(t, batcher.FIFOStrategy{}, strategy)
+	strategy, err = batcher.NewBatchFormationStrategy("priority-weighted", 0)
+	assert.NoError(t, err)
+	assert.IsType(t, &batcher.PriorityWeightedStrategy{}, strategy)
+	strategy, err = batcher.NewBatchFormationStrategy("priority", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, batcher.PriorityStrategy{MaxStarvationAge: time.Minute}, strategy)
+	_, err = batcher.NewBatchFormationStrategy("lifo", 0)
+	assert.Error(t, err)
+}
+
+func TestCreateBatchFormation(t *testing.T) {
+	logger := &cmock.Logger{}
+	queue := memorydb.NewBlobStore(1024*1024, logger)
+	b, err := batcher.NewBatcher(batcher.Config{
+		NumConnections:           1,
+		EncodingRequestQueueSize: 10,
+		BatchFormationStrategy:   "fifo",
+		MaxBlobsPerBatch:         1,
+	}, batcher.TimeoutConfig{}, queue, &countingDispatcher{}, nil, nil, nil, logger, batcher.NewMetrics("9100", logger))
+	assert.NoError(t, err)
+
+	putEncodedBlob(t, b, []byte("first blob"))
+	putEncodedBlob(t, b, []byte("second blob"))
+
+	// the blobs which are not selected are left for the next batch
+	batch, _, err := b.EncodingStreamer.CreateBatch()
+	assert.NoError(t, err)
+	assert.Len(t, batch.BlobMetadata, 1)
+	first := batch.BlobMetadata[0]
+	batch, _, err = b.EncodingStreamer.CreateBatch()
+	assert.NoError(t, err)
+	assert.Len(t, batch.BlobMetadata, 1)
+	assert.Less(t, first.RequestMetadata.RequestedAt, batch.BlobMetadata[0].RequestMetadata.RequestedAt)
+	_, _, err = b.EncodingStreamer.CreateBatch()
+	assert.Error(t, err)
+}
@@ -4,20 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
-	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/gammazero/workerpool"
 	"github.com/hashicorp/go-multierror"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/wealdtech/go-merkletree"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
 	QuantizationFactor = uint(1)
 	indexerWarmupDelay = 2 * time.Second
+	// metadataPageSize is the page size the blobs of a status are read with
+	metadataPageSize = int32(1000)
+	// defaultShutdownTimeout is the time the batches in flight are waited for on shutdown if not configured
+	defaultShutdownTimeout = 30 * time.Second
 )
 
 type TimeoutConfig struct {
@@ -30,6 +36,9 @@ type Config struct {
 	PullInterval             time.Duration
 	FinalizerInterval        time.Duration
 	EncoderSocket            string
+	EncoderSockets           []string
+	EncoderPool              EncoderPoolConfig
+	EncoderPublicKey         string
 	SRSOrder                 int
 	NumConnections           int
 	EncodingRequestQueueSize int
@@ -37,6 +46,26 @@ type Config struct {
 	BatchSizeMBLimit     uint
 	MaxNumRetriesPerBlob uint
 	ConfirmerNum         uint
+	MinStorageReceipts   uint
+	FinalizerBatchSize   int
+	// FinalizationDepth is the number of blocks below the latest block a block is considered finalized at, for the chains
+	// without the finalized block tag. The finalized block tag is used if 0.
+	FinalizationDepth uint32
+	// MaxBatchesInFlight is the maximum number of batches assembled or waiting for confirmation at the same time,
+	// it bounds the memory held by the encoded blobs of the batches. Batches are not limited if 0.
+	MaxBatchesInFlight uint
+	// BatchFormationStrategy is the strategy selecting the blobs of each batch, either fifo, priority-weighted or priority.
+	// All the encoded blobs are batched if empty.
+	BatchFormationStrategy string
+	// MaxStarvationAge is the time after which a blob is encoded and batched first regardless of its priority,
+	// the blobs are not promoted if 0
+	MaxStarvationAge time.Duration
+	// MaxBlobsPerBatch is the maximum number of blobs in a batch, blobs are not limited if 0
+	MaxBlobsPerBatch int
+	// UseBlobHeaderHashV2 makes the batch root commit to the quorum parameters of the blobs
+	UseBlobHeaderHashV2 bool
+	// ShutdownTimeout is the time the batches in flight are waited for by Shutdown, 30 seconds if 0
+	ShutdownTimeout time.Duration
 }
 
 type Batcher struct {
@@ -50,6 +79,15 @@ type Batcher struct {
 	EncodingStreamer *EncodingStreamer
 	Metrics          *Metrics
 
+	// BatchSemaphore holds a token for each batch in flight, it is nil if the batches are not limited
+	BatchSemaphore chan struct{}
+	// batchesInFlight counts the batches assembled or waiting for confirmation, they are waited for by Shutdown
+	batchesInFlight sync.WaitGroup
+	// pullLoopDone is closed once the pull loop started by Start returns
+	pullLoopDone chan struct{}
+	// cancelBatches cancels the batches in flight, which are carried on after the context of Start is done
+	cancelBatches context.CancelFunc
+
 	finalizer Finalizer
 	confirmer *Confirmer
 	logger    common.Logger
@@ -74,6 +112,24 @@ func NewBatcher(
 		SRSOrder:               config.SRSOrder,
 		EncodingRequestTimeout: timeoutConfig.EncodingTimeout,
 		EncodingQueueLimit:     config.EncodingRequestQueueSize,
+		MaxBatchBytes:          config.BatchSizeMBLimit * 1024 * 1024,
+		MaxBlobsPerBatch:       config.MaxBlobsPerBatch,
+		UseBlobHeaderHashV2:    config.UseBlobHeaderHashV2,
+		MaxStarvationAge:       config.MaxStarvationAge,
+	}
+	if config.BatchFormationStrategy != "" {
+		strategy, err := NewBatchFormationStrategy(config.BatchFormationStrategy, config.MaxStarvationAge)
+		if err != nil {
+			return nil, err
+		}
+		streamerConfig.BatchFormation = strategy
+	}
+	if len(config.EncoderPublicKey) > 0 {
+		encoderPublicKey, err := disperser.ParseEncoderPublicKey(config.EncoderPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		streamerConfig.EncoderPublicKey = encoderPublicKey
 	}
 	encodingWorkerPool := workerpool.New(config.NumConnections)
 	encodingStreamer, err := NewEncodingStreamer(streamerConfig, queue, encoderClient, batchTrigger, encodingWorkerPool, metrics.EncodingStreamerMetrics, logger)
@@ -81,6 +137,11 @@ func NewBatcher(
 		return nil, err
 	}
 
+	var batchSemaphore chan struct{}
+	if config.MaxBatchesInFlight > 0 {
+		batchSemaphore = make(chan struct{}, config.MaxBatchesInFlight)
+	}
+
 	return &Batcher{
 		Config:        config,
 		TimeoutConfig: timeoutConfig,
@@ -92,6 +153,8 @@ func NewBatcher(
 		EncodingStreamer: encodingStreamer,
 		Metrics:          metrics,
 
+		BatchSemaphore: batchSemaphore,
+
 		finalizer: finalizer,
 		confirmer: confirmer,
 		logger:    logger,
@@ -101,21 +164,33 @@ func NewBatcher(
 func (b *Batcher) Start(ctx context.Context) error {
 	// Wait for few seconds for indexer to index blockchain
 	// This won't be needed when we switch to using Graph node
-	time.Sleep(indexerWarmupDelay)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(indexerWarmupDelay):
+	}
 	err := b.EncodingStreamer.Start(ctx)
 	if err != nil {
 		return err
 	}
 	batchTrigger := b.EncodingStreamer.EncodedSizeNotifier
+	// no new batch is started once ctx is done, while the batches in flight are carried on until Shutdown
+	batchCtx, cancelBatches := context.WithCancel(context.WithoutCancel(ctx))
+	b.cancelBatches = cancelBatches
+	b.pullLoopDone = make(chan struct{})
 	// confirmer
 	b.confirmer.EncodingStreamer = b.EncodingStreamer
-	b.confirmer.Start(ctx)
+	b.confirmer.Start(batchCtx)
+	if b.confirmer.ReceiptCollector != nil {
+		b.confirmer.ReceiptCollector.Start(ctx)
+	}
 	// finalizer
 	if !b.Queue.MetadataHashAsBlobKey() {
 		b.finalizer.Start(ctx)
 	}
 
 	go func() {
+		defer close(b.pullLoopDone)
 		ticker := time.NewTicker(b.PullInterval)
 		defer ticker.Stop()
 
@@ -124,7 +199,7 @@ func (b *Batcher) Start(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				if ts, err := b.HandleSingleBatch(ctx); err != nil {
+				if ts, err := b.HandleSingleBatch(batchCtx); err != nil {
 					b.EncodingStreamer.RemoveBatchingStatus(ts)
 					if errors.Is(err, errNoEncodedResults) {
 						b.logger.Debug("[batcher] no encoded results to make a batch with")
@@ -134,7 +209,7 @@ func (b *Batcher) Start(ctx context.Context) error {
 				}
 			case <-batchTrigger.Notify:
 				ticker.Stop()
-				if ts, err := b.HandleSingleBatch(ctx); err != nil {
+				if ts, err := b.HandleSingleBatch(batchCtx); err != nil {
 					b.EncodingStreamer.RemoveBatchingStatus(ts)
 					if errors.Is(err, errNoEncodedResults) {
 						b.logger.Debug("[batcher] no encoded results to make a batch with(Notified)")
@@ -150,6 +225,35 @@ func (b *Batcher) Start(ctx context.Context) error {
 	return nil
 }
 
+// Shutdown waits for the batches in flight to be confirmed, up to ShutdownTimeout, then cancels the ones left.
+// It is called once the context of Start is done, so that no new batch is started.
+func (b *Batcher) Shutdown() {
+	timeout := b.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	b.logger.Info("[batcher] initiating graceful shutdown", "timeout", timeout)
+	drained := make(chan struct{})
+	go func() {
+		// no batch is added once the pull loop returned
+		if b.pullLoopDone != nil {
+			<-b.pullLoopDone
+		}
+		b.batchesInFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(timeout):
+		b.logger.Warn("[batcher] shutdown timed out, cancelling the batches in flight")
+	}
+	if b.cancelBatches != nil {
+		b.cancelBatches()
+	}
+	b.logger.Info("[batcher] shutdown complete")
+}
+
 func serializeProof(proof *merkletree.Proof) []byte {
 	proofBytes := make([]byte, 0)
 	for _, hash := range proof.Hashes {
@@ -175,21 +279,75 @@ func (b *Batcher) handleFailure(ctx context.Context, blobMetadatas []*disperser.
 	return result.ErrorOrNil()
 }
 
-func (b *Batcher) HandleSingleBatch(ctx context.Context) (uint64, error) {
+// acquireBatchSlot waits until fewer than MaxBatchesInFlight batches are in flight.
+// It returns the function releasing the slot, which may be called more than once.
+func (b *Batcher) acquireBatchSlot(ctx context.Context) (func(), error) {
+	if b.BatchSemaphore != nil {
+		select {
+		case b.BatchSemaphore <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	b.Metrics.BatchesInFlight.Inc()
+	b.batchesInFlight.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.batchesInFlight.Done()
+			b.Metrics.BatchesInFlight.Dec()
+			if b.BatchSemaphore != nil {
+				<-b.BatchSemaphore
+			}
+		})
+	}, nil
+}
+
+func (b *Batcher) HandleSingleBatch(ctx context.Context) (ts uint64, err error) {
+	ctx, span := tracing.StartSpan(ctx, "HandleSingleBatch")
+	defer func() {
+		// there is no batch to process most of the times the batcher looks for one, it's not an error of the span
+		spanErr := err
+		if errors.Is(spanErr, errNoEncodedResults) {
+			spanErr = nil
+		}
+		tracing.EndSpan(span, spanErr)
+	}()
 	log := b.logger
+	// the slot is held until the confirmer is done with the batch
+	release, err := b.acquireBatchSlot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	confirming := false
+	defer func() {
+		if !confirming {
+			release()
+		}
+	}()
+
 	// start a timer
 	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(f float64) {
 		b.Metrics.ObserveLatency("total", f*1000) // make milliseconds
 	}))
 	defer timer.ObserveDuration()
 
-	stageTimer := time.Now()
+	startedAt := time.Now()
+	stageTimer := startedAt
 	log.Info("[batcher] Creating batch", "ts", stageTimer)
 	batch, ts, err := b.EncodingStreamer.CreateBatch()
 	if err != nil {
 		return ts, err
 	}
+	// the batches which fail before they are handed to the confirmer end their cycle here
+	defer func() {
+		if !confirming {
+			b.Metrics.RecordBatchProcessed(newBatchMetricsEvent(batch, startedAt, time.Time{}, false))
+		}
+	}()
 	log.Info("[batcher] CreateBatch took", "duration", time.Since(stageTimer), "blobNum", len(batch.ExtendedMatrix))
+	span.SetAttributes(attribute.Int("batch.blob_count", len(batch.BlobMetadata)))
 
 	// Get the batch header hash
 	log.Trace("[batcher] Getting batch header hash...")
@@ -202,38 +360,37 @@ func (b *Batcher) HandleSingleBatch(ctx context.Context) (uint64, error) {
 	proofs := make([]*merkletree.Proof, 0)
 	// Prepare data writes to kv stream
 	for blobIndex := range batch.BlobMetadata {
-		var blobHeader *core.BlobHeader
-		// generate inclusion proof
+		// inclusion proofs are generated along with the batch root
 		if blobIndex >= len(batch.BlobHeaders) {
 			return ts, fmt.Errorf("HandleSingleBatch: error preparing kv data: blob header at index %d not found in batch", blobIndex)
 		}
-		blobHeader = batch.BlobHeaders[blobIndex]
-
-		blobHeaderHash, err := blobHeader.GetBlobHeaderHash()
-		if err != nil {
-			return ts, fmt.Errorf("HandleSingleBatch: failed to get blob header hash: %w", err)
-		}
-		merkleProof, err := batch.MerkleTree.GenerateProof(blobHeaderHash[:], 0)
-		if err != nil {
-			return ts, fmt.Errorf("HandleSingleBatch: failed to generate blob header inclusion proof: %w", err)
+		proofHashes, ok := batch.Proofs[blobIndex]
+		if !ok {
+			return ts, fmt.Errorf("HandleSingleBatch: blob header inclusion proof at index %d not found in batch", blobIndex)
 		}
-		proofs = append(proofs, merkleProof)
+		proofs = append(proofs, &merkletree.Proof{Hashes: proofHashes, Index: uint64(blobIndex)})
 	}
 
 	// Dispatch encoded batch
 	log.Info("[batcher] Dispatching encoded batch...")
 	stageTimer = time.Now()
-	batch.TxHash, err = b.Dispatcher.DisperseBatch(ctx, headerHash, batch.BatchHeader, batch.ExtendedMatrix, batch.BlobHeaders, proofs)
+	disperseCtx, disperseSpan := tracing.StartSpan(ctx, "DisperseBatch")
+	batch.TxHash, err = b.Dispatcher.DisperseBatch(disperseCtx, headerHash, batch.BatchHeader, batch.ExtendedMatrix, batch.BlobHeaders, proofs)
+	tracing.EndSpan(disperseSpan, err)
 	if err != nil {
 		return ts, err
 	}
 	log.Info("[batcher] DisperseBatch took", "duration", time.Since(stageTimer))
 
 	b.confirmer.ConfirmChan <- &BatchInfo{
-		headerHash: headerHash,
-		batch:      batch,
-		proofs:     proofs,
-		ts:         ts,
+		headerHash:   headerHash,
+		batch:        batch,
+		proofs:       proofs,
+		ts:           ts,
+		release:      release,
+		startedAt:    startedAt,
+		dispatchedAt: time.Now(),
 	}
+	confirming = true
 	return ts, nil
 }
@@ -0,0 +1,199 @@
+package batcher_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cmock "github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser/batcher"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/wealdtech/go-merkletree"
+)
+
+// countingDispatcher counts the dispersed batches
+type countingDispatcher struct {
+	mu      sync.Mutex
+	batches int
+}
+
+func (d *countingDispatcher) DisperseBatch(ctx context.Context, batchHeaderHash [32]byte, batchHeader *core.BatchHeader, extendedMatrix []*core.ExtendedMatrix, blobHeaders []*core.BlobHeader, proofs []*merkletree.Proof) (eth_common.Hash, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.batches++
+	return eth_common.Hash{byte(d.batches)}, nil
+}
+
+func (d *countingDispatcher) numBatches() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.batches
+}
+
+func putEncodedBlob(t *testing.T, b *batcher.Batcher, data []byte) {
+	ctx := context.Background()
+	key, err := b.Queue.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: data,
+	}, uint64(time.Now().UnixNano()))
+	assert.NoError(t, err)
+	metadata, err := b.Queue.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	b.EncodingStreamer.EncodedBlobstore.PutEncodingRequest(key)
+	assert.NoError(t, b.EncodingStreamer.EncodedBlobstore.PutEncodingResult(&batcher.EncodingResult{
+		BlobMetadata: metadata,
+		ExtendedMatrix: &core.ExtendedMatrix{
+			Length:      uint(len(data)),
+			Rows:        []core.EncodedRow{make(core.EncodedRow, 1)},
+			Commitments: []core.Commitment{{data[0]}},
+		},
+	}))
+}
+
+func TestMaxBatchesInFlight(t *testing.T) {
+	ctx := context.Background()
+	logger := &cmock.Logger{}
+	queue := memorydb.NewBlobStore(1024*1024, logger)
+	dispatcher := &countingDispatcher{}
+	// the batches are confirmed by the test
+	confirmer := &batcher.Confirmer{ConfirmChan: make(chan *batcher.BatchInfo, 2)}
+	metrics := batcher.NewMetrics("9100", logger)
+	b, err := batcher.NewBatcher(batcher.Config{
+		NumConnections:           1,
+		EncodingRequestQueueSize: 10,
+		MaxBatchesInFlight:       1,
+	}, batcher.TimeoutConfig{}, queue, dispatcher, nil, nil, confirmer, logger, metrics)
+	assert.NoError(t, err)
+
+	putEncodedBlob(t, b, []byte("first batch"))
+	_, err = b.HandleSingleBatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatcher.numBatches())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.BatchesInFlight))
+
+	// the second batch doesn't start until the first is confirmed
+	putEncodedBlob(t, b, []byte("second batch"))
+	done := make(chan error)
+	go func() {
+		_, err := b.HandleSingleBatch(ctx)
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, dispatcher.numBatches())
+	assert.Len(t, confirmer.ConfirmChan, 1)
+
+	first := <-confirmer.ConfirmChan
+	first.Release()
+	assert.NoError(t, <-done)
+	assert.Equal(t, 2, dispatcher.numBatches())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.BatchesInFlight))
+
+	// the wait for a slot is given up with the context
+	putEncodedBlob(t, b, []byte("third batch"))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = b.HandleSingleBatch(timeoutCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, dispatcher.numBatches())
+
+	// releasing a batch more than once frees a single slot
+	second := <-confirmer.ConfirmChan
+	second.Release()
+	second.Release()
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.BatchesInFlight))
+
+	// batches failing before they are dispersed free their slot
+	_, err = b.HandleSingleBatch(ctx)
+	assert.NoError(t, err)
+	third := <-confirmer.ConfirmChan
+	third.Release()
+	_, err = b.HandleSingleBatch(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.BatchesInFlight))
+	assert.Len(t, b.BatchSemaphore, 0)
+}
+
+func TestShutdownWaitsForBatchesInFlight(t *testing.T) {
+	ctx := context.Background()
+	logger := &cmock.Logger{}
+	confirmer := &batcher.Confirmer{ConfirmChan: make(chan *batcher.BatchInfo, 2)}
+	b, err := batcher.NewBatcher(batcher.Config{
+		NumConnections:           1,
+		EncodingRequestQueueSize: 10,
+		ShutdownTimeout:          time.Minute,
+	}, batcher.TimeoutConfig{}, memorydb.NewBlobStore(1024*1024, logger), &countingDispatcher{}, nil, nil, confirmer, logger, batcher.NewMetrics("9100", logger))
+	assert.NoError(t, err)
+
+	putEncodedBlob(t, b, []byte("batch in flight"))
+	_, err = b.HandleSingleBatch(ctx)
+	assert.NoError(t, err)
+
+	stopped := make(chan struct{})
+	go func() {
+		b.Shutdown()
+		close(stopped)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-stopped:
+		t.Fatal("the batcher was shut down with a batch in flight")
+	default:
+	}
+
+	// the shutdown completes once the batch is confirmed
+	(<-confirmer.ConfirmChan).Release()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the batcher was not shut down once the batch was confirmed")
+	}
+
+	// the batches which are not confirmed within the timeout are left
+	b.ShutdownTimeout = 50 * time.Millisecond
+	putEncodedBlob(t, b, []byte("stuck batch"))
+	_, err = b.HandleSingleBatch(ctx)
+	assert.NoError(t, err)
+	start := time.Now()
+	b.Shutdown()
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestCreateBatchBlobHeaderHashV2(t *testing.T) {
+	logger := &cmock.Logger{}
+	for _, useV2 := range []bool{false, true} {
+		queue := memorydb.NewBlobStore(1024*1024, logger)
+		b, err := batcher.NewBatcher(batcher.Config{
+			NumConnections:           1,
+			EncodingRequestQueueSize: 10,
+			UseBlobHeaderHashV2:      useV2,
+		}, batcher.TimeoutConfig{}, queue, &countingDispatcher{}, nil, nil, nil, logger, batcher.NewMetrics("9100", logger))
+		assert.NoError(t, err)
+		putEncodedBlob(t, b, []byte("first blob"))
+		putEncodedBlob(t, b, []byte("second blob"))
+
+		batch, _, err := b.EncodingStreamer.CreateBatch()
+		assert.NoError(t, err)
+		for _, blobHeader := range batch.BlobHeaders {
+			assert.Equal(t, []*core.BlobQuorumInfo{{SecurityParam: core.SecurityParam{QuorumID: 0}, ChunkLength: 1}}, blobHeader.QuorumInfos)
+		}
+
+		v1Header := &core.BatchHeader{}
+		_, err = v1Header.SetBatchRoot(batch.BlobHeaders)
+		assert.NoError(t, err)
+		v2Header := &core.BatchHeader{}
+		_, _, err = v2Header.SetBatchRootWithProofV2(batch.BlobHeaders)
+		assert.NoError(t, err)
+		if useV2 {
+			assert.Equal(t, v2Header.BatchRoot, batch.BatchHeader.BatchRoot)
+		} else {
+			assert.Equal(t, v1Header.BatchRoot, batch.BatchHeader.BatchRoot)
+		}
+	}
+}
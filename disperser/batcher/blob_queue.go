@@ -0,0 +1,151 @@
+package batcher
+
+import (
+	"container/heap"
+	"math"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+)
+
+// starvedPriority is above the priority of any blob, the starved blobs are promoted to it
+const starvedPriority = math.MaxUint8 + 1
+
+// blobQueue is a heap of blobs ordered by priority, highest first, then by request time, oldest first.
+// The blobs requested before starvedBefore are promoted above all priorities, so that the low priority blobs
+// are not starved by a steady flow of higher priority ones.
+type blobQueue struct {
+	blobs []*disperser.BlobMetadata
+	// starvedBefore is the request time in nanoseconds before which the blobs are starved, none is if 0
+	starvedBefore uint64
+}
+
+var _ heap.Interface = (*blobQueue)(nil)
+
+// newBlobQueue creates a queue of the blobs, the blobs waiting for longer than maxStarvationAge at now are promoted.
+// The blobs are not promoted if maxStarvationAge is 0.
+func newBlobQueue(blobs []*disperser.BlobMetadata, maxStarvationAge time.Duration, now time.Time) *blobQueue {
+	q := &blobQueue{
+		blobs: make([]*disperser.BlobMetadata, len(blobs)),
+	}
+	copy(q.blobs, blobs)
+	if maxStarvationAge > 0 && now.Add(-maxStarvationAge).UnixNano() > 0 {
+		q.starvedBefore = uint64(now.Add(-maxStarvationAge).UnixNano())
+	}
+	heap.Init(q)
+	return q
+}
+
+// priority returns the priority the blob is ordered by, the priority of its request unless it is starved
+func (q *blobQueue) priority(metadata *disperser.BlobMetadata) int {
+	if metadata.RequestMetadata.RequestedAt < q.starvedBefore {
+		return starvedPriority
+	}
+	return int(metadata.RequestMetadata.Priority)
+}
+
+func (q *blobQueue) Len() int {
+	return len(q.blobs)
+}
+
+func (q *blobQueue) Less(i, j int) bool {
+	return q.before(q.blobs[i], q.blobs[j])
+}
+
+// before returns whether the blob a is processed before the blob b
+func (q *blobQueue) before(a, b *disperser.BlobMetadata) bool {
+	pa, pb := q.priority(a), q.priority(b)
+	if pa != pb {
+		return pa > pb
+	}
+	return a.RequestMetadata.RequestedAt < b.RequestMetadata.RequestedAt
+}
+
+func (q *blobQueue) Swap(i, j int) {
+	q.blobs[i], q.blobs[j] = q.blobs[j], q.blobs[i]
+}
+
+func (q *blobQueue) Push(x any) {
+	q.blobs = append(q.blobs, x.(*disperser.BlobMetadata))
+}
+
+func (q *blobQueue) Pop() any {
+	last := q.blobs[len(q.blobs)-1]
+	q.blobs[len(q.blobs)-1] = nil
+	q.blobs = q.blobs[:len(q.blobs)-1]
+	return last
+}
+
+// next removes and returns the blob to process first
+func (q *blobQueue) next() *disperser.BlobMetadata {
+	return heap.Pop(q).(*disperser.BlobMetadata)
+}
+
+// SortByPriority returns the blobs in the order they are processed in: by priority, highest first, then by request
+// time, oldest first. The blobs waiting for longer than maxStarvationAge go first regardless of their priority,
+// the blobs are not promoted if it is 0.
+func SortByPriority(blobs []*disperser.BlobMetadata, maxStarvationAge time.Duration, now time.Time) []*disperser.BlobMetadata {
+	q := newBlobQueue(blobs, maxStarvationAge, now)
+	sorted := make([]*disperser.BlobMetadata, 0, len(blobs))
+	for q.Len() > 0 {
+		sorted = append(sorted, q.next())
+	}
+	return sorted
+}
+
+// lastFirstQueue is a blobQueue with the blob to process last on top
+type lastFirstQueue struct {
+	*blobQueue
+}
+
+func (q lastFirstQueue) Less(i, j int) bool {
+	return q.blobQueue.Less(j, i)
+}
+
+// topBlobs keeps the limit blobs to process first among the blobs added to it, so that they are selected among many
+// blobs without holding all of them
+type topBlobs struct {
+	queue            lastFirstQueue
+	limit            int
+	maxStarvationAge time.Duration
+	now              time.Time
+}
+
+// newTopBlobs creates an empty topBlobs, the blobs are ordered as in SortByPriority
+func newTopBlobs(limit int, maxStarvationAge time.Duration, now time.Time) *topBlobs {
+	return &topBlobs{
+		queue:            lastFirstQueue{newBlobQueue(nil, maxStarvationAge, now)},
+		limit:            limit,
+		maxStarvationAge: maxStarvationAge,
+		now:              now,
+	}
+}
+
+// add adds the blob if there are fewer than limit blobs, or in place of the blob to process last if it goes before it
+func (t *topBlobs) add(metadata *disperser.BlobMetadata) {
+	if t.queue.Len() < t.limit {
+		heap.Push(t.queue, metadata)
+		return
+	}
+	if t.limit > 0 && t.queue.before(metadata, t.queue.blobs[0]) {
+		t.queue.blobs[0] = metadata
+		heap.Fix(t.queue, 0)
+	}
+}
+
+// sorted returns the blobs kept in the order they are processed in
+func (t *topBlobs) sorted() []*disperser.BlobMetadata {
+	return SortByPriority(t.queue.blobs, t.maxStarvationAge, t.now)
+}
+
+// priorityBucket returns the bucket of a blob priority the queue wait durations are labeled with
+func priorityBucket(priority uint8) string {
+	switch {
+	case priority >= 170:
+		return "high"
+	case priority >= 85:
+		return "medium"
+	default:
+		return "low"
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/0glabs/0g-data-avail/common"
 	"github.com/0glabs/0g-data-avail/common/geth"
 	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
+	"github.com/0glabs/0g-data-avail/core"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/0glabs/0g-data-avail/disperser/batcher/transactor"
 	"github.com/0glabs/0g-storage-client/common/blockchain"
@@ -21,6 +23,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/wealdtech/go-merkletree"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Confirmer struct {
@@ -45,6 +48,11 @@ type Confirmer struct {
 	UploadTaskSize uint
 	transactor     *transactor.Transactor
 
+	// ReceiptCollector collects the storage node receipts of the confirmed batches if set
+	ReceiptCollector *ReceiptCollector
+	// SerializationFormat is the format of the blob metadata written to the KV stream, gob if unset
+	SerializationFormat core.SerializationFormat
+
 	logger  common.Logger
 	Metrics *Metrics
 }
@@ -54,6 +62,17 @@ type BatchInfo struct {
 	batch      *batch
 	ts         uint64
 	proofs     []*merkletree.Proof
+	release    func()
+	// startedAt is when the batcher started to assemble the batch, dispatchedAt when it was dispersed
+	startedAt    time.Time
+	dispatchedAt time.Time
+}
+
+// Release frees the in-flight slot held by the batch, so the batcher can start another batch
+func (b *BatchInfo) Release() {
+	if b.release != nil {
+		b.release()
+	}
 }
 
 func NewConfirmer(ethConfig geth.EthClientConfig, storageNodeConfig storage_node.ClientConfig, queue disperser.BlobStore, maxNumRetriesPerBlob uint, routines uint, transactor *transactor.Transactor, logger common.Logger, metrics *Metrics) (*Confirmer, error) {
@@ -112,9 +131,12 @@ func (c *Confirmer) Start(ctx context.Context) {
 				case <-ticker.C:
 					batchInfo := c.getPendingBatch()
 					if batchInfo != nil {
-						if err := c.ConfirmBatch(ctx, batchInfo); err != nil {
+						err := c.ConfirmBatch(ctx, batchInfo)
+						if err != nil {
 							c.logger.Error("[confirmer] failed to confirm batch", "err", err)
 						}
+						c.Metrics.RecordBatchProcessed(newBatchMetricsEvent(batchInfo.batch, batchInfo.startedAt, batchInfo.dispatchedAt, err == nil))
+						batchInfo.Release()
 					}
 				}
 			}
@@ -122,6 +144,25 @@ func (c *Confirmer) Start(ctx context.Context) {
 	}
 }
 
+// newBatchMetricsEvent returns the metrics event of a batch at the end of its cycle, the batch wasn't dispersed if
+// dispatchedAt is zero
+func newBatchMetricsEvent(batch *batch, startedAt, dispatchedAt time.Time, success bool) disperser.BatchMetricsEvent {
+	event := disperser.BatchMetricsEvent{
+		BlobCount: len(batch.BlobMetadata),
+		Success:   success,
+	}
+	for _, metadata := range batch.BlobMetadata {
+		event.TotalBytes += uint64(metadata.RequestMetadata.BlobSize)
+	}
+	if dispatchedAt.IsZero() {
+		event.EncodingDuration = time.Since(startedAt)
+	} else {
+		event.EncodingDuration = dispatchedAt.Sub(startedAt)
+		event.ConfirmationDuration = time.Since(dispatchedAt)
+	}
+	return event
+}
+
 func (c *Confirmer) putPendingBatches(info *BatchInfo) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -194,7 +235,7 @@ func (c *Confirmer) PersistConfirmedBlobs(ctx context.Context, metadatas []*disp
 	for _, metadata := range metadatas {
 		blobKey := metadata.GetBlobKey()
 		key := []byte(blobKey.String())
-		value, err := metadata.Serialize()
+		value, err := metadata.SerializeWithFormat(c.SerializationFormat)
 		if err != nil {
 			return errors.WithMessage(err, "Failed to serialize blob metadata")
 		}
@@ -241,9 +282,11 @@ func (c *Confirmer) PersistConfirmedBlobs(ctx context.Context, metadatas []*disp
 	return nil
 }
 
-func (c *Confirmer) ConfirmBatch(ctx context.Context, batchInfo *BatchInfo) error {
+func (c *Confirmer) ConfirmBatch(ctx context.Context, batchInfo *BatchInfo) (err error) {
 	batch := batchInfo.batch
 	proofs := batchInfo.proofs
+	ctx, span := tracing.StartSpan(ctx, "ConfirmBatch", attribute.String("batch.tx_hash", batch.TxHash.Hex()), attribute.Int("batch.blob_count", len(batch.BlobMetadata)))
+	defer func() { tracing.EndSpan(span, err) }()
 
 	txSeq, blockNumber, err := c.waitForReceipt(batch.TxHash)
 	if err != nil {
@@ -260,6 +303,10 @@ func (c *Confirmer) ConfirmBatch(ctx context.Context, batchInfo *BatchInfo) erro
 	blobsToRetry := make([]*disperser.BlobMetadata, 0)
 	var updateConfirmationInfoErr error
 	confirmedMetadatas := make([]*disperser.BlobMetadata, 0)
+	batchSize := int64(0)
+	for _, blobMeta := range batch.BlobMetadata {
+		batchSize += int64(blobMeta.RequestMetadata.BlobSize)
+	}
 	for blobIndex, metadata := range batch.BlobMetadata {
 		confirmationInfo := &disperser.ConfirmationInfo{
 			BatchHeaderHash:         batchInfo.headerHash,
@@ -269,9 +316,12 @@ func (c *Confirmer) ConfirmBatch(ctx context.Context, batchInfo *BatchInfo) erro
 			BlobInclusionProof:      serializeProof(proofs[blobIndex]),
 			CommitmentRoot:          batch.BlobHeaders[blobIndex].CommitmentRoot,
 			Length:                  uint32(batch.BlobHeaders[blobIndex].Length),
+			BlobQuorumInfos:         batch.BlobHeaders[blobIndex].QuorumInfos,
 			BatchID:                 uint32(batchID),
 			ConfirmationTxnHash:     batch.TxHash,
 			ConfirmationBlockNumber: blockNumber,
+			BlobCount:               uint32(len(batch.BlobMetadata)),
+			BatchSize:               uint64(batchSize),
 		}
 		c.logger.Trace("confirming blob", "blob key", metadata.GetBlobKey())
 		if confirmedMetadata, updateConfirmationInfoErr := c.Queue.MarkBlobConfirmed(ctx, metadata, confirmationInfo); updateConfirmationInfoErr == nil {
@@ -311,10 +361,12 @@ func (c *Confirmer) ConfirmBatch(ctx context.Context, batchInfo *BatchInfo) erro
 		c.logger.Info("[confirmer] Uploaded confirmed metadata on chain", "duration", time.Since(stageTimer))
 	}
 
-	batchSize := int64(0)
-	for _, blobMeta := range batch.BlobMetadata {
-		batchSize += int64(blobMeta.RequestMetadata.BlobSize)
+	// the metadata of the confirmed blobs is removed once persisted on chain
+	if c.ReceiptCollector != nil && !c.Queue.MetadataHashAsBlobKey() {
+		c.ReceiptCollector.Collect(batchInfo.headerHash, confirmedMetadatas)
 	}
+
 	c.Metrics.IncrementBatchCount(batchSize)
+	c.Metrics.IncrementConfirmedBatches()
 	return nil
 }
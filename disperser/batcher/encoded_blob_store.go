@@ -135,8 +135,9 @@ func (e *encodedBlobStore) DeleteEncodingResult(blobKey disperser.BlobKey) {
 	delete(e.batching, requestID)
 }
 
-// GetNewEncodingResults returns all the fresh encoded results
-func (e *encodedBlobStore) GetNewEncodingResults(ts uint64) []*EncodingResult {
+// GetNewEncodingResults returns the fresh encoded results chosen by selectResults, or all of them if it is nil.
+// The results which are not chosen stay fresh.
+func (e *encodedBlobStore) GetNewEncodingResults(ts uint64, selectResults func([]*EncodingResult) []*EncodingResult) []*EncodingResult {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	fetched := make([]*EncodingResult, 0)
@@ -146,10 +147,16 @@ func (e *encodedBlobStore) GetNewEncodingResults(ts uint64) []*EncodingResult {
 	for id, encodedResult := range e.encoded {
 		if _, ok := e.batching[id]; !ok {
 			fetched = append(fetched, encodedResult)
-			e.batching[id] = ts
-			e.batches[ts] = append(e.batches[ts], id)
 		}
 	}
+	if selectResults != nil {
+		fetched = selectResults(fetched)
+	}
+	for _, encodedResult := range fetched {
+		id := getRequestID(encodedResult.BlobMetadata.GetBlobKey())
+		e.batching[id] = ts
+		e.batches[ts] = append(e.batches[ts], id)
+	}
 	e.logger.Trace("consumed encoded results", "fetched", len(fetched), "encodedSize", e.encodedResultSize)
 	return fetched
 }
@@ -0,0 +1,252 @@
+package batcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+)
+
+type LoadBalanceStrategy string
+
+const (
+	RoundRobin       LoadBalanceStrategy = "round-robin"
+	LeastConnections LoadBalanceStrategy = "least-connections"
+	Random           LoadBalanceStrategy = "random"
+)
+
+var ErrNoHealthyEncoder = errors.New("no healthy encoder available")
+
+// ParseLoadBalanceStrategy returns the strategy with the given name, the empty name defaults to round-robin
+func ParseLoadBalanceStrategy(name string) (LoadBalanceStrategy, error) {
+	switch strategy := LoadBalanceStrategy(name); strategy {
+	case "":
+		return RoundRobin, nil
+	case RoundRobin, LeastConnections, Random:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown encoder load balance strategy: %s", name)
+	}
+}
+
+// ParseEncoderAddresses splits the comma-separated encoder addresses, ignoring empty entries
+func ParseEncoderAddresses(addrs string) []string {
+	result := make([]string, 0)
+	for _, addr := range strings.Split(addrs, ",") {
+		if addr = strings.TrimSpace(addr); len(addr) > 0 {
+			result = append(result, addr)
+		}
+	}
+	return result
+}
+
+// EncoderProber checks if the encoder at the given address is able to serve requests
+type EncoderProber func(ctx context.Context, addr string) error
+
+type EncoderPoolConfig struct {
+	Strategy LoadBalanceStrategy
+	// MaxConsecutiveFailures is the number of consecutive failed requests after which an encoder is removed from the pool
+	MaxConsecutiveFailures int
+	// ProbeInterval is the interval to probe the removed encoders, which are added back once a probe succeeds
+	ProbeInterval time.Duration
+}
+
+type pooledEncoder struct {
+	addr   string
+	client disperser.EncoderClient
+
+	inflight            int
+	consecutiveFailures int
+	healthy             bool
+}
+
+// EncoderPool distributes the encoding requests across multiple encoders
+type EncoderPool struct {
+	EncoderPoolConfig
+
+	mu       sync.Mutex
+	encoders []*pooledEncoder
+	next     int
+
+	prober  EncoderProber
+	metrics *EncoderPoolMetrics
+	logger  common.Logger
+}
+
+var _ disperser.EncoderClient = (*EncoderPool)(nil)
+
+func NewEncoderPool(
+	config EncoderPoolConfig,
+	addrs []string,
+	newClient func(addr string) (disperser.EncoderClient, error),
+	prober EncoderProber,
+	metrics *EncoderPoolMetrics,
+	logger common.Logger) (*EncoderPool, error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("at least one encoder address should be specified")
+	}
+	if config.MaxConsecutiveFailures <= 0 {
+		return nil, fmt.Errorf("MaxConsecutiveFailures should be greater than 0")
+	}
+	if _, err := ParseLoadBalanceStrategy(string(config.Strategy)); err != nil {
+		return nil, err
+	}
+	if config.Strategy == "" {
+		config.Strategy = RoundRobin
+	}
+
+	encoders := make([]*pooledEncoder, len(addrs))
+	for i, addr := range addrs {
+		client, err := newClient(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create encoder client for %s: %w", addr, err)
+		}
+		encoders[i] = &pooledEncoder{
+			addr:    addr,
+			client:  client,
+			healthy: true,
+		}
+	}
+	metrics.UpdatePoolSize(len(encoders))
+
+	return &EncoderPool{
+		EncoderPoolConfig: config,
+		encoders:          encoders,
+		prober:            prober,
+		metrics:           metrics,
+		logger:            logger,
+	}, nil
+}
+
+// Start periodically probes the encoders removed from the pool
+func (p *EncoderPool) Start(ctx context.Context) {
+	if p.ProbeInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(p.ProbeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.Probe(ctx)
+			}
+		}
+	}()
+}
+
+// Probe probes all the encoders removed from the pool, and adds back the ones that respond
+func (p *EncoderPool) Probe(ctx context.Context) {
+	p.mu.Lock()
+	unhealthy := make([]*pooledEncoder, 0)
+	for _, encoder := range p.encoders {
+		if !encoder.healthy {
+			unhealthy = append(unhealthy, encoder)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, encoder := range unhealthy {
+		if err := p.prober(ctx, encoder.addr); err != nil {
+			p.logger.Debug("[encoderpool] encoder probe failed", "addr", encoder.addr, "err", err)
+			continue
+		}
+		p.mu.Lock()
+		encoder.healthy = true
+		encoder.consecutiveFailures = 0
+		p.metrics.UpdatePoolSize(p.healthySize())
+		p.mu.Unlock()
+		p.logger.Info("[encoderpool] encoder added back to the pool", "addr", encoder.addr)
+	}
+}
+
+// Size returns the number of healthy encoders in the pool
+func (p *EncoderPool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.healthySize()
+}
+
+func (p *EncoderPool) EncodeBlob(ctx context.Context, data []byte, dims core.MatrixDimsions) (*core.ExtendedMatrix, *disperser.BlobEncodingReceipt, error) {
+	encoder, err := p.acquire()
+	if err != nil {
+		return nil, nil, err
+	}
+	extendedMatrix, receipt, err := encoder.client.EncodeBlob(ctx, data, dims)
+	p.release(encoder, err)
+	return extendedMatrix, receipt, err
+}
+
+func (p *EncoderPool) acquire() (*pooledEncoder, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	healthy := make([]*pooledEncoder, 0, len(p.encoders))
+	for _, encoder := range p.encoders {
+		if encoder.healthy {
+			healthy = append(healthy, encoder)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil, ErrNoHealthyEncoder
+	}
+
+	var selected *pooledEncoder
+	switch p.Strategy {
+	case LeastConnections:
+		for _, encoder := range healthy {
+			if selected == nil || encoder.inflight < selected.inflight {
+				selected = encoder
+			}
+		}
+	case Random:
+		selected = healthy[rand.Intn(len(healthy))]
+	default:
+		selected = healthy[p.next%len(healthy)]
+		p.next = (p.next + 1) % len(healthy)
+	}
+
+	selected.inflight++
+	p.metrics.IncrementSelection(p.Strategy, selected.addr)
+	return selected, nil
+}
+
+func (p *EncoderPool) release(encoder *pooledEncoder, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	encoder.inflight--
+	if err == nil {
+		encoder.consecutiveFailures = 0
+		return
+	}
+	// requests canceled by the caller don't indicate anything about the encoder
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	encoder.consecutiveFailures++
+	if encoder.healthy && encoder.consecutiveFailures >= p.MaxConsecutiveFailures {
+		encoder.healthy = false
+		p.metrics.UpdatePoolSize(p.healthySize())
+		p.logger.Warn("[encoderpool] encoder removed from the pool", "addr", encoder.addr, "consecutiveFailures", encoder.consecutiveFailures, "err", err)
+	}
+}
+
+func (p *EncoderPool) healthySize() int {
+	size := 0
+	for _, encoder := range p.encoders {
+		if encoder.healthy {
+			size++
+		}
+	}
+	return size
+}
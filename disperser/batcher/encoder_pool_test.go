@@ -0,0 +1,168 @@
+package batcher_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	cmock "github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	pb "github.com/0glabs/0g-data-avail/disperser/api/grpc/encoder"
+	"github.com/0glabs/0g-data-avail/disperser/batcher"
+	"github.com/0glabs/0g-data-avail/disperser/encoder"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type mockEncoderServer struct {
+	pb.UnimplementedEncoderServer
+
+	addr     string
+	requests atomic.Int32
+	failing  atomic.Bool
+	health   *health.Server
+}
+
+func (s *mockEncoderServer) EncodeBlob(ctx context.Context, req *pb.EncodeBlobRequest) (*pb.EncodeBlobReply, error) {
+	s.requests.Add(1)
+	if s.failing.Load() {
+		return nil, errors.New("encoder failure")
+	}
+	return &pb.EncodeBlobReply{
+		Rows:       1,
+		Cols:       1,
+		Commitment: make([]byte, core.CommitmentSize),
+		Chunks:     make([]byte, core.CoeffSize),
+	}, nil
+}
+
+// setFailing makes the encoder fail all requests and report itself as not serving
+func (s *mockEncoderServer) setFailing(failing bool) {
+	s.failing.Store(failing)
+	status := grpc_health_v1.HealthCheckResponse_SERVING
+	if failing {
+		status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+	s.health.SetServingStatus("", status)
+}
+
+func startMockEncoderServers(t *testing.T, n int) []*mockEncoderServer {
+	servers := make([]*mockEncoderServer, n)
+	for i := range servers {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+
+		server := &mockEncoderServer{addr: listener.Addr().String(), health: health.NewServer()}
+		gs := grpc.NewServer()
+		pb.RegisterEncoderServer(gs, server)
+		grpc_health_v1.RegisterHealthServer(gs, server.health)
+		go func() {
+			_ = gs.Serve(listener)
+		}()
+		t.Cleanup(gs.Stop)
+		servers[i] = server
+	}
+	return servers
+}
+
+func newTestEncoderPool(t *testing.T, strategy batcher.LoadBalanceStrategy, servers []*mockEncoderServer) *batcher.EncoderPool {
+	logger := &cmock.Logger{}
+	addrs := make([]string, len(servers))
+	for i, server := range servers {
+		addrs[i] = server.addr
+	}
+	pool, err := batcher.NewEncoderPool(batcher.EncoderPoolConfig{
+		Strategy:               strategy,
+		MaxConsecutiveFailures: 2,
+	}, addrs, func(addr string) (disperser.EncoderClient, error) {
+		return encoder.NewEncoderClient(addr, 0)
+	}, encoder.ProbeEncoder, batcher.NewMetrics("9100", logger).EncoderPoolMetrics, logger)
+	assert.NoError(t, err)
+	return pool
+}
+
+func TestEncoderPoolRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	servers := startMockEncoderServers(t, 3)
+	pool := newTestEncoderPool(t, batcher.RoundRobin, servers)
+
+	for i := 0; i < 9; i++ {
+		_, _, err := pool.EncodeBlob(ctx, []byte("round robin"), core.MatrixDimsions{Rows: 1, Cols: 1})
+		assert.NoError(t, err)
+	}
+	for _, server := range servers {
+		assert.Equal(t, int32(3), server.requests.Load())
+	}
+}
+
+func TestEncoderPoolHealthExclusion(t *testing.T) {
+	ctx := context.Background()
+	servers := startMockEncoderServers(t, 3)
+	pool := newTestEncoderPool(t, batcher.RoundRobin, servers)
+	assert.Equal(t, 3, pool.Size())
+
+	servers[1].setFailing(true)
+	failures := 0
+	for i := 0; i < 6; i++ {
+		_, _, err := pool.EncodeBlob(ctx, []byte("health"), core.MatrixDimsions{Rows: 1, Cols: 1})
+		if err != nil {
+			failures++
+		}
+	}
+	// the failing encoder is removed after 2 consecutive failures
+	assert.Equal(t, 2, failures)
+	assert.Equal(t, int32(2), servers[1].requests.Load())
+	assert.Equal(t, 2, pool.Size())
+
+	// the removed encoder receives no requests
+	for i := 0; i < 4; i++ {
+		_, _, err := pool.EncodeBlob(ctx, []byte("health"), core.MatrixDimsions{Rows: 1, Cols: 1})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), servers[1].requests.Load())
+
+	// the probe fails while the encoder isn't serving
+	pool.Probe(ctx)
+	assert.Equal(t, 2, pool.Size())
+
+	// the encoder is added back once the probe succeeds
+	servers[1].setFailing(false)
+	pool.Probe(ctx)
+	assert.Equal(t, 3, pool.Size())
+	for i := 0; i < 3; i++ {
+		_, _, err := pool.EncodeBlob(ctx, []byte("health"), core.MatrixDimsions{Rows: 1, Cols: 1})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), servers[1].requests.Load())
+}
+
+func TestEncoderPoolNoHealthyEncoder(t *testing.T) {
+	ctx := context.Background()
+	servers := startMockEncoderServers(t, 1)
+	pool := newTestEncoderPool(t, batcher.LeastConnections, servers)
+
+	servers[0].setFailing(true)
+	for i := 0; i < 2; i++ {
+		_, _, err := pool.EncodeBlob(ctx, []byte("unhealthy"), core.MatrixDimsions{Rows: 1, Cols: 1})
+		assert.Error(t, err)
+	}
+	_, _, err := pool.EncodeBlob(ctx, []byte("unhealthy"), core.MatrixDimsions{Rows: 1, Cols: 1})
+	assert.ErrorIs(t, err, batcher.ErrNoHealthyEncoder)
+}
+
+func TestParseLoadBalanceStrategy(t *testing.T) {
+	strategy, err := batcher.ParseLoadBalanceStrategy("")
+	assert.NoError(t, err)
+	assert.Equal(t, batcher.RoundRobin, strategy)
+	strategy, err = batcher.ParseLoadBalanceStrategy("least-connections")
+	assert.NoError(t, err)
+	assert.Equal(t, batcher.LeastConnections, strategy)
+	_, err = batcher.ParseLoadBalanceStrategy("weighted")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"a:1", "b:2"}, batcher.ParseEncoderAddresses(" a:1,,b:2 "))
+}
@@ -2,6 +2,7 @@ package batcher
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"sort"
@@ -10,10 +11,12 @@ import (
 	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/core"
 	"github.com/0glabs/0g-data-avail/disperser"
 	eth_common "github.com/ethereum/go-ethereum/common"
 	"github.com/wealdtech/go-merkletree"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const encodingInterval = 2 * time.Second
@@ -40,8 +43,24 @@ type StreamerConfig struct {
 
 	// EncodingQueueLimit is the maximum number of encoding requests that can be queued
 	EncodingQueueLimit int
+
+	// EncoderPublicKey is used to verify the encoding receipts returned by the encoder, verification is skipped if it is nil
+	EncoderPublicKey *ecdsa.PublicKey
+
+	// BatchFormation selects the blobs of each batch, all the encoded blobs are batched if it is nil
+	BatchFormation BatchFormationStrategy
+	// MaxBatchBytes is the maximum total size of the blobs of a batch selected by BatchFormation, not limited if 0
+	MaxBatchBytes uint
+	// MaxBlobsPerBatch is the maximum number of blobs of a batch selected by BatchFormation, not limited if 0
+	MaxBlobsPerBatch int
+	// UseBlobHeaderHashV2 makes the batch root commit to the quorum parameters of the blobs, see core.BlobHeader.GetBlobHeaderHashV2
+	UseBlobHeaderHashV2 bool
+	// MaxStarvationAge is the time after which a blob is encoded first regardless of its priority, not promoted if 0
+	MaxStarvationAge time.Duration
 }
 
+var _ disperser.EncodingQueue = (*EncodingStreamer)(nil)
+
 type EncodingStreamer struct {
 	StreamerConfig
 
@@ -69,6 +88,7 @@ type batch struct {
 	BlobHeaders    []*core.BlobHeader
 	BatchHeader    *core.BatchHeader
 	MerkleTree     *merkletree.MerkleTree
+	Proofs         map[int][][]byte
 	TxHash         eth_common.Hash
 }
 
@@ -105,6 +125,27 @@ func NewEncodingStreamer(
 	}, nil
 }
 
+// QueueLength returns the number of encoding requests waiting in the worker pool
+func (e *EncodingStreamer) QueueLength() int {
+	return e.Pool.WaitingQueueSize()
+}
+
+// QueueCapacity returns the maximum number of encoding requests that can be queued
+func (e *EncodingStreamer) QueueCapacity() int {
+	return e.EncodingQueueLimit
+}
+
+// EstimatedDrainTime estimates the time to drain the waiting queue, assuming each request
+// may take up to the encoding request timeout on every worker
+func (e *EncodingStreamer) EstimatedDrainTime() time.Duration {
+	workers := e.Pool.Size()
+	if workers <= 0 {
+		workers = 1
+	}
+	rounds := (e.QueueLength() + workers - 1) / workers
+	return time.Duration(rounds) * e.EncodingRequestTimeout
+}
+
 func (e *EncodingStreamer) Start(ctx context.Context) error {
 	encoderChan := make(chan EncodingResultOrStatus)
 
@@ -152,19 +193,11 @@ func (e *EncodingStreamer) RequestEncoding(ctx context.Context, encoderChan chan
 	stageTimer := time.Now()
 	// pull new blobs and send to encoder
 	e.logger.Info("[encodingstreamer] requesting processing blobs..")
-	metadatas, err := e.blobStore.GetBlobMetadataByStatus(ctx, disperser.Processing)
+	// enough blobs to fill the encoding queue, and as many to prefetch
+	metadatas, err := e.getBlobsToEncode(ctx, 2*e.EncodingQueueLimit)
 	if err != nil {
 		return fmt.Errorf("error getting blob metadatas: %w", err)
 	}
-	// filter requested/encoded blobs
-	n := 0
-	for _, metadata := range metadatas {
-		if !e.EncodedBlobstore.HasEncodingRequested(metadata.GetBlobKey()) {
-			metadatas[n] = metadata
-			n++
-		}
-	}
-	metadatas = metadatas[:n]
 	if len(metadatas) == 0 {
 		e.logger.Info("[encodingstreamer] no new metadatas to encode")
 		return nil
@@ -182,9 +215,24 @@ func (e *EncodingStreamer) RequestEncoding(ctx context.Context, encoderChan chan
 		e.logger.Warn("[encodingstreamer] worker pool queue is full. skipping this round of encoding requests", "waitingQueueSize", waitingQueueSize, "encodingQueueLimit", e.EncodingQueueLimit)
 		return nil
 	}
+	// the blobs left for the next rounds are prefetched, so they don't wait for the blob store then
+	remaining := metadatas[numMetadatastoProcess:]
+	if len(remaining) > e.EncodingQueueLimit {
+		remaining = remaining[:e.EncodingQueueLimit]
+	}
+	prefetchKeys := make([]disperser.BlobKey, len(remaining))
+	for i, metadata := range remaining {
+		prefetchKeys[i] = metadata.GetBlobKey()
+	}
+	e.blobStore.PrefetchBlobs(ctx, prefetchKeys)
+
 	// only process subset of blobs so it doesn't exceed the EncodingQueueLimit
 	// TODO: this should be done at the request time and keep the cursor so that we don't fetch the same metadata every time
 	metadatas = metadatas[:numMetadatastoProcess]
+	now := time.Now()
+	for _, metadata := range metadatas {
+		e.metrics.ObserveQueueWait(metadata.RequestMetadata.Priority, now.Sub(time.Unix(0, int64(metadata.RequestMetadata.RequestedAt))))
+	}
 
 	e.logger.Trace("[encodingstreamer] new metadatas to encode", "numMetadata", len(metadatas), "duration", time.Since(stageTimer))
 
@@ -206,6 +254,31 @@ func (e *EncodingStreamer) RequestEncoding(ctx context.Context, encoderChan chan
 	return nil
 }
 
+// getBlobsToEncode returns up to limit processing blobs whose encoding hasn't been requested yet, sorted by priority then
+// by request time, see SortByPriority. The processing blobs are read page by page, only the limit blobs to encode first
+// are kept across the pages.
+func (e *EncodingStreamer) getBlobsToEncode(ctx context.Context, limit int) ([]*disperser.BlobMetadata, error) {
+	blobs := newTopBlobs(limit, e.MaxStarvationAge, time.Now())
+	var exclusiveStartKey *disperser.BlobStoreExclusiveStartKey
+	for {
+		page, nextKey, err := e.blobStore.GetBlobMetadataByStatusPaginated(ctx, disperser.Processing, metadataPageSize, exclusiveStartKey)
+		if err != nil {
+			return nil, err
+		}
+		// filter requested/encoded blobs
+		for _, metadata := range page {
+			if !e.EncodedBlobstore.HasEncodingRequested(metadata.GetBlobKey()) {
+				blobs.add(metadata)
+			}
+		}
+		if nextKey == nil {
+			break
+		}
+		exclusiveStartKey = nextKey
+	}
+	return blobs.sorted(), nil
+}
+
 type pendingRequestInfo struct {
 	Dims core.MatrixDimsions
 }
@@ -218,17 +291,34 @@ func (e *EncodingStreamer) RequestEncodingForBlob(ctx context.Context, metadata
 
 	blobLength := core.GetBlobLength(metadata.RequestMetadata.BlobSize)
 
-	rows, cols := core.SplitToMatrix(blobLength, uint(blob.RequestHeader.TargetRowNum))
-
-	dims := core.MatrixDimsions{
-		Rows: rows,
-		Cols: cols,
+	// the encoded matrix is uploaded to the storage nodes as a whole rather than distributed across operators
+	chunkLength, dimsErr := core.ComputeChunkLength(int(metadata.RequestMetadata.BlobSize), 1, uint(blob.RequestHeader.TargetRowNum))
+	var dims core.MatrixDimsions
+	if dimsErr == nil {
+		dims = core.MatrixDimsions{
+			Rows: core.GetEncodedLength(blobLength) / chunkLength,
+			Cols: chunkLength,
+		}
 	}
 
 	encodingCtx, cancel := context.WithTimeout(ctx, e.EncodingRequestTimeout)
 	e.Pool.Submit(func() {
 		defer cancel()
-		extendedMatrix, err := e.encoderClient.EncodeBlob(encodingCtx, blob.Data, dims)
+		if dimsErr != nil {
+			encoderChan <- EncodingResultOrStatus{Err: dimsErr, EncodingResult: EncodingResult{
+				BlobMetadata: metadata,
+			}}
+			return
+		}
+		spanCtx, span := tracing.StartSpan(encodingCtx, "EncodeBlob", attribute.String("blob.key", blobKey.String()), attribute.Int("blob.size", len(blob.Data)))
+		extendedMatrix, receipt, err := e.encoderClient.EncodeBlob(spanCtx, blob.Data, dims)
+		if err == nil && e.EncoderPublicKey != nil {
+			err = receipt.Verify(blob.Data, extendedMatrix, e.EncoderPublicKey)
+			if err != nil {
+				e.metrics.IncrementEncoderSignatureVerificationFailures()
+			}
+		}
+		tracing.EndSpan(span, err)
 		if err != nil {
 			encoderChan <- EncodingResultOrStatus{Err: err, EncodingResult: EncodingResult{
 				BlobMetadata: metadata,
@@ -286,7 +376,7 @@ func (e *EncodingStreamer) ProcessEncodedBlobs(ctx context.Context, result Encod
 func (e *EncodingStreamer) CreateBatch() (*batch, uint64, error) {
 	// Get all encoded blobs
 	ts := uint64(time.Now().Nanosecond())
-	encodedResults := e.EncodedBlobstore.GetNewEncodingResults(ts)
+	encodedResults := e.EncodedBlobstore.GetNewEncodingResults(ts, e.selectBatchResults)
 
 	// Reset the notifier
 	e.EncodedSizeNotifier.mu.Lock()
@@ -311,7 +401,8 @@ func (e *EncodingStreamer) CreateBatch() (*batch, uint64, error) {
 			metadataByKey[blobKey] = result.BlobMetadata
 		}
 		blobHeader := &core.BlobHeader{
-			Length: result.ExtendedMatrix.Length,
+			Length:      result.ExtendedMatrix.Length,
+			QuorumInfos: getBlobQuorumInfos(result.BlobMetadata, result.ExtendedMatrix),
 		}
 		if err := blobHeader.SetCommitmentRoot(result.ExtendedMatrix.Commitments); err != nil {
 			return nil, ts, err
@@ -342,7 +433,11 @@ func (e *EncodingStreamer) CreateBatch() (*batch, uint64, error) {
 		BatchRoot: [32]byte{},
 	}
 
-	tree, err := batchHeader.SetBatchRoot(blobHeaders)
+	setBatchRoot := batchHeader.SetBatchRootWithProof
+	if e.UseBlobHeaderHashV2 {
+		setBatchRoot = batchHeader.SetBatchRootWithProofV2
+	}
+	tree, proofs, err := setBatchRoot(blobHeaders)
 	if err != nil {
 		return nil, ts, err
 	}
@@ -355,9 +450,30 @@ func (e *EncodingStreamer) CreateBatch() (*batch, uint64, error) {
 		BlobHeaders:    blobHeaders,
 		BlobMetadata:   metadatas,
 		MerkleTree:     tree,
+		Proofs:         proofs,
 	}, ts, nil
 }
 
+// selectBatchResults selects the encoded results of the next batch with the batch formation strategy
+func (e *EncodingStreamer) selectBatchResults(results []*EncodingResult) []*EncodingResult {
+	if e.BatchFormation == nil {
+		return results
+	}
+	resultByMetadata := make(map[*disperser.BlobMetadata]*EncodingResult, len(results))
+	metadatas := make([]*disperser.BlobMetadata, len(results))
+	for i, result := range results {
+		resultByMetadata[result.BlobMetadata] = result
+		metadatas[i] = result.BlobMetadata
+	}
+
+	selected := e.BatchFormation.SelectBlobs(metadatas, e.MaxBatchBytes, e.MaxBlobsPerBatch)
+	selectedResults := make([]*EncodingResult, len(selected))
+	for i, metadata := range selected {
+		selectedResults[i] = resultByMetadata[metadata]
+	}
+	return selectedResults
+}
+
 func (e *EncodingStreamer) RemoveEncodedBlob(metadata *disperser.BlobMetadata) {
 	e.EncodedBlobstore.DeleteEncodingResult(metadata.GetBlobKey())
 }
@@ -365,3 +481,20 @@ func (e *EncodingStreamer) RemoveEncodedBlob(metadata *disperser.BlobMetadata) {
 func (e *EncodingStreamer) RemoveBatchingStatus(ts uint64) {
 	e.EncodedBlobstore.DeleteBatchingStatus(ts)
 }
+
+// getBlobQuorumInfos returns the parameters of the quorums of an encoded blob, the chunks are the rows of the matrix
+func getBlobQuorumInfos(metadata *disperser.BlobMetadata, matrix *core.ExtendedMatrix) []*core.BlobQuorumInfo {
+	var chunkLength uint
+	if matrix.GetRows() > 0 {
+		chunkLength = uint(matrix.GetCols())
+	}
+	securityParams := metadata.RequestMetadata.SecurityParams
+	quorumInfos := make([]*core.BlobQuorumInfo, len(securityParams))
+	for i, param := range securityParams {
+		quorumInfos[i] = &core.BlobQuorumInfo{
+			SecurityParam: *param,
+			ChunkLength:   chunkLength,
+		}
+	}
+	return quorumInfos
+}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 
 	gcommon "github.com/ethereum/go-ethereum/common"
@@ -31,10 +33,16 @@ type finalizer struct {
 	ethClient            common.EthClient
 	rpcClient            common.RPCEthClient
 	maxNumRetriesPerBlob uint
-	logger               common.Logger
+	// batchSize is the maximum number of blobs finalized per cycle, there is no limit if it is not positive
+	batchSize int
+	// finalizationDepth is the number of blocks below the latest block a block is finalized at, the finalized block tag
+	// is used if 0. The blocks deepened since the last cycle are finalized at the next one.
+	finalizationDepth uint32
+	metrics           *Metrics
+	logger            common.Logger
 }
 
-func NewFinalizer(timeout time.Duration, loopInterval time.Duration, blobStore disperser.BlobStore, ethClient common.EthClient, rpcClient common.RPCEthClient, maxNumRetriesPerBlob uint, logger common.Logger) Finalizer {
+func NewFinalizer(timeout time.Duration, loopInterval time.Duration, blobStore disperser.BlobStore, ethClient common.EthClient, rpcClient common.RPCEthClient, maxNumRetriesPerBlob uint, batchSize int, finalizationDepth uint32, metrics *Metrics, logger common.Logger) Finalizer {
 	return &finalizer{
 		timeout:              timeout,
 		loopInterval:         loopInterval,
@@ -42,6 +50,9 @@ func NewFinalizer(timeout time.Duration, loopInterval time.Duration, blobStore d
 		ethClient:            ethClient,
 		rpcClient:            rpcClient,
 		maxNumRetriesPerBlob: maxNumRetriesPerBlob,
+		batchSize:            batchSize,
+		finalizationDepth:    finalizationDepth,
+		metrics:              metrics,
 		logger:               logger,
 	}
 }
@@ -73,54 +84,77 @@ func (f *finalizer) FinalizeBlobs(ctx context.Context) error {
 		return fmt.Errorf("FinalizeBlobs: error getting latest finalized block: %w", err)
 	}
 
-	metadatas, err := f.blobStore.GetBlobMetadataByStatus(ctx, disperser.Confirmed)
-	if err != nil {
-		return fmt.Errorf("FinalizeBlobs: error getting blob headers: %w", err)
-	}
+	f.logger.Info("[finalizer] FinalizeBlobs: finalizing blobs", "finalizedBlockNumber", finalizedHeader.Number)
 
-	f.logger.Info("[finalizer] FinalizeBlobs: finalizing blobs", "numBlobs", len(metadatas), "finalizedBlockNumber", finalizedHeader.Number)
-
-	for _, m := range metadatas {
-		blobKey := m.GetBlobKey()
-		confirmationMetadata, err := f.blobStore.GetBlobMetadata(ctx, blobKey)
+	// the confirmed blobs are read page by page, so that they aren't all read when there are many of them
+	finalizedKeys := make([]disperser.BlobKey, 0)
+	numBlobs := 0
+	var exclusiveStartKey *disperser.BlobStoreExclusiveStartKey
+pages:
+	for {
+		metadatas, nextKey, err := f.blobStore.GetBlobMetadataByStatusPaginated(ctx, disperser.Confirmed, metadataPageSize, exclusiveStartKey)
 		if err != nil {
-			f.logger.Error("[finalizer] FinalizeBlobs: error getting confirmed metadata", "blobKey", blobKey.String(), "err", err)
-			continue
+			return fmt.Errorf("FinalizeBlobs: error getting blob headers: %w", err)
 		}
+		numBlobs += len(metadatas)
 
-		// Leave as confirmed if the confirmation block is after the latest finalized block (not yet finalized)
-		if uint64(confirmationMetadata.ConfirmationInfo.ConfirmationBlockNumber) > finalizedHeader.Number.Uint64() {
-			continue
-		}
+		for _, m := range metadatas {
+			if f.batchSize > 0 && len(finalizedKeys) >= f.batchSize {
+				// the remaining blobs are finalized in the next cycles
+				break pages
+			}
+			blobKey := m.GetBlobKey()
+			confirmationMetadata, err := f.blobStore.GetBlobMetadata(ctx, blobKey)
+			if err != nil {
+				f.logger.Error("[finalizer] FinalizeBlobs: error getting confirmed metadata", "blobKey", blobKey.String(), "err", err)
+				continue
+			}
 
-		// confirmation block number may have changed due to reorg
-		confirmationBlockNumber, err := f.getTransactionBlockNumber(ctx, confirmationMetadata.ConfirmationInfo.ConfirmationTxnHash)
-		if errors.Is(err, ethereum.NotFound) {
-			// The confirmed block is finalized, but the transaction is not found. It means the transaction should be considered forked/invalid and the blob should be considered as failed.
-			err := f.blobStore.HandleBlobFailure(ctx, m, f.maxNumRetriesPerBlob)
+			// Leave as confirmed if the confirmation block is after the latest finalized block (not yet finalized)
+			if uint64(confirmationMetadata.ConfirmationInfo.ConfirmationBlockNumber) > finalizedHeader.Number.Uint64() {
+				continue
+			}
+
+			// confirmation block number may have changed due to reorg
+			confirmationBlockNumber, err := f.getTransactionBlockNumber(ctx, confirmationMetadata.ConfirmationInfo.ConfirmationTxnHash)
+			if errors.Is(err, ethereum.NotFound) {
+				// The confirmed block is finalized, but the transaction is not found. It means the transaction should be considered forked/invalid and the blob should be considered as failed.
+				err := f.blobStore.HandleBlobFailure(ctx, m, f.maxNumRetriesPerBlob)
+				if err != nil {
+					f.logger.Error("[finalizer] FinalizeBlobs: error marking blob as failed", "blobKey", blobKey.String(), "err", err)
+				}
+				continue
+			}
 			if err != nil {
-				f.logger.Error("[finalizer] FinalizeBlobs: error marking blob as failed", "blobKey", blobKey.String(), "err", err)
+				f.logger.Error("[finalizer] FinalizeBlobs: error getting transaction block number", "err", err)
+				continue
 			}
-			continue
-		}
-		if err != nil {
-			f.logger.Error("[finalizer] FinalizeBlobs: error getting transaction block number", "err", err)
-			continue
+
+			// Leave as confirmed if the reorged confirmation block is after the latest finalized block (not yet finalized)
+			if uint64(confirmationBlockNumber) > finalizedHeader.Number.Uint64() {
+				continue
+			}
+
+			confirmationMetadata.ConfirmationInfo.ConfirmationBlockNumber = uint32(confirmationBlockNumber)
+			finalizedKeys = append(finalizedKeys, blobKey)
 		}
 
-		// Leave as confirmed if the reorged confirmation block is after the latest finalized block (not yet finalized)
-		if uint64(confirmationBlockNumber) > finalizedHeader.Number.Uint64() {
-			continue
+		if nextKey == nil {
+			break
 		}
+		exclusiveStartKey = nextKey
+	}
 
-		confirmationMetadata.ConfirmationInfo.ConfirmationBlockNumber = uint32(confirmationBlockNumber)
-		err = f.blobStore.MarkBlobFinalized(ctx, blobKey)
+	if len(finalizedKeys) > 0 {
+		stageTimer := time.Now()
+		err = f.blobStore.BatchMarkBlobsFinalized(ctx, finalizedKeys)
+		f.metrics.ObserveFinalizationBatchLatency(float64(time.Since(stageTimer).Milliseconds()))
 		if err != nil {
-			f.logger.Error("[finalizer] FinalizeBlobs: error marking blob as finalized", "blobKey", blobKey.String(), "err", err)
-			continue
+			f.logger.Error("[finalizer] FinalizeBlobs: error marking blobs as finalized", "numBlobs", len(finalizedKeys), "err", err)
+			return nil
 		}
 	}
-	f.logger.Info("[finalizer] FinalizeBlobs: successfully processed all finalized blobs")
+	f.logger.Info("[finalizer] FinalizeBlobs: successfully processed all finalized blobs", "numBlobs", numBlobs, "numFinalized", len(finalizedKeys))
 	return nil
 }
 
@@ -143,7 +177,11 @@ func (f *finalizer) getTransactionBlockNumber(ctx context.Context, hash gcommon.
 
 		retrySec := math.Pow(2, float64(i))
 		f.logger.Error("[finalizer] Finalizer: error getting transaction", "err", err, "retrySec", retrySec, "hash", hash.Hex())
-		time.Sleep(time.Duration(retrySec) * baseDelay)
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Duration(retrySec) * baseDelay):
+		}
 	}
 
 	if err != nil {
@@ -153,6 +191,8 @@ func (f *finalizer) getTransactionBlockNumber(ctx context.Context, hash gcommon.
 	return txReceipt.BlockNumber.Uint64(), nil
 }
 
+// getLatestFinalizedBlock returns the block of the finalized tag, or only the number of the block finalizationDepth
+// blocks below the latest one if the depth is set
 func (f *finalizer) getLatestFinalizedBlock(ctx context.Context) (*types.Header, error) {
 	var ctxWithTimeout context.Context
 	var cancel context.CancelFunc
@@ -161,14 +201,22 @@ func (f *finalizer) getLatestFinalizedBlock(ctx context.Context) (*types.Header,
 	for i := 0; i < maxRetries; i++ {
 		ctxWithTimeout, cancel = context.WithTimeout(ctx, f.timeout)
 		defer cancel()
-		err := f.rpcClient.CallContext(ctxWithTimeout, &header, "eth_getBlockByNumber", "finalized", false)
+		if f.finalizationDepth > 0 {
+			err = f.getDeepenedBlock(ctxWithTimeout, &header)
+		} else {
+			err = f.rpcClient.CallContext(ctxWithTimeout, &header, "eth_getBlockByNumber", "finalized", false)
+		}
 		if err == nil {
 			break
 		}
 
 		retrySec := math.Pow(2, float64(i))
 		f.logger.Error("[finalizer] Finalizer: error getting latest finalized block", "err", err, "retrySec", retrySec)
-		time.Sleep(time.Duration(retrySec) * baseDelay)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(retrySec) * baseDelay):
+		}
 	}
 
 	if err != nil {
@@ -177,3 +225,17 @@ func (f *finalizer) getLatestFinalizedBlock(ctx context.Context) (*types.Header,
 
 	return &header, nil
 }
+
+// getDeepenedBlock sets the number of the header to the number of the block finalizationDepth blocks below the latest one
+func (f *finalizer) getDeepenedBlock(ctx context.Context, header *types.Header) error {
+	var latest hexutil.Uint64
+	if err := f.rpcClient.CallContext(ctx, &latest, "eth_blockNumber"); err != nil {
+		return err
+	}
+	number := uint64(0)
+	if uint64(latest) > uint64(f.finalizationDepth) {
+		number = uint64(latest) - uint64(f.finalizationDepth)
+	}
+	header.Number = new(big.Int).SetUint64(number)
+	return nil
+}
@@ -0,0 +1,142 @@
+package batcher_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	cmock "github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/batcher"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// countingBlobStore counts the calls marking blobs as finalized
+type countingBlobStore struct {
+	disperser.BlobStore
+	singleCalls int
+	batchCalls  []int
+}
+
+func (s *countingBlobStore) MarkBlobFinalized(ctx context.Context, blobKey disperser.BlobKey) error {
+	s.singleCalls++
+	return s.BlobStore.MarkBlobFinalized(ctx, blobKey)
+}
+
+func (s *countingBlobStore) BatchMarkBlobsFinalized(ctx context.Context, blobKeys []disperser.BlobKey) error {
+	s.batchCalls = append(s.batchCalls, len(blobKeys))
+	return s.BlobStore.BatchMarkBlobsFinalized(ctx, blobKeys)
+}
+
+func TestFinalizeBlobsInBatches(t *testing.T) {
+	ctx := context.Background()
+	logger := &cmock.Logger{}
+	queue := &countingBlobStore{BlobStore: memorydb.NewBlobStore(1024*1024, logger)}
+
+	numBlobs := 100
+	for i := 0; i < numBlobs; i++ {
+		key, err := queue.StoreBlob(ctx, &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: []byte{byte(i), byte(i >> 8)},
+		}, uint64(i))
+		assert.NoError(t, err)
+		metadata, err := queue.GetBlobMetadata(ctx, key)
+		assert.NoError(t, err)
+		_, err = queue.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{
+			BlobIndex:               uint32(i),
+			ConfirmationBlockNumber: 10,
+		})
+		assert.NoError(t, err)
+	}
+
+	rpcClient := &cmock.MockRPCEthClient{}
+	rpcClient.On("CallContext", mock.Anything, mock.Anything, "eth_getBlockByNumber", "finalized", false).Run(func(args mock.Arguments) {
+		args.Get(1).(*types.Header).Number = big.NewInt(20)
+	}).Return(nil)
+	ethClient := &cmock.MockEthClient{}
+	ethClient.On("TransactionReceipt").Return(&types.Receipt{BlockNumber: big.NewInt(10)}, nil)
+	metrics := batcher.NewMetrics("9100", logger)
+	finalizer := batcher.NewFinalizer(time.Second, time.Minute, queue, ethClient, rpcClient, 1, 60, 0, metrics, logger)
+
+	// at most the batch size is finalized per cycle
+	assert.NoError(t, finalizer.FinalizeBlobs(ctx))
+	finalized, err := queue.GetBlobMetadataByStatus(ctx, disperser.Finalized)
+	assert.NoError(t, err)
+	assert.Len(t, finalized, 60)
+
+	assert.NoError(t, finalizer.FinalizeBlobs(ctx))
+	finalized, err = queue.GetBlobMetadataByStatus(ctx, disperser.Finalized)
+	assert.NoError(t, err)
+	assert.Len(t, finalized, numBlobs)
+
+	// nothing left to finalize
+	assert.NoError(t, finalizer.FinalizeBlobs(ctx))
+	assert.Equal(t, []int{60, 40}, queue.batchCalls)
+	assert.Equal(t, 0, queue.singleCalls)
+
+	families, err := metrics.Registry().Gather()
+	assert.NoError(t, err)
+	var sampleCount uint64
+	for _, family := range families {
+		if family.GetName() == "zgda_batcher_finalization_batch_latency_ms" {
+			sampleCount = family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	assert.Equal(t, uint64(2), sampleCount)
+}
+
+func TestFinalizeBlobsWithFinalizationDepth(t *testing.T) {
+	ctx := context.Background()
+	logger := &cmock.Logger{}
+	queue := memorydb.NewBlobStore(1024*1024, logger)
+
+	keys := make(map[uint32]disperser.BlobKey)
+	for _, confirmationBlock := range []uint32{90, 95} {
+		key, err := queue.StoreBlob(ctx, &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: []byte{byte(confirmationBlock)},
+		}, uint64(confirmationBlock))
+		assert.NoError(t, err)
+		metadata, err := queue.GetBlobMetadata(ctx, key)
+		assert.NoError(t, err)
+		_, err = queue.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{ConfirmationBlockNumber: confirmationBlock})
+		assert.NoError(t, err)
+		keys[confirmationBlock] = key
+	}
+
+	latest := hexutil.Uint64(100)
+	rpcClient := &cmock.MockRPCEthClient{}
+	rpcClient.On("CallContext", mock.Anything, mock.Anything, "eth_blockNumber").Run(func(args mock.Arguments) {
+		*args.Get(1).(*hexutil.Uint64) = latest
+	}).Return(nil)
+	ethClient := &cmock.MockEthClient{}
+	ethClient.On("TransactionReceipt").Return(&types.Receipt{BlockNumber: big.NewInt(90)}, nil).Once()
+	ethClient.On("TransactionReceipt").Return(&types.Receipt{BlockNumber: big.NewInt(95)}, nil).Once()
+	finalizer := batcher.NewFinalizer(time.Second, time.Minute, queue, ethClient, rpcClient, 1, 0, 10, batcher.NewMetrics("9100", logger), logger)
+
+	// only the blob 10 blocks below the latest block is finalized
+	assert.NoError(t, finalizer.FinalizeBlobs(ctx))
+	status := func(confirmationBlock uint32) disperser.BlobStatus {
+		metadata, err := queue.GetBlobMetadata(ctx, keys[confirmationBlock])
+		assert.NoError(t, err)
+		return metadata.BlobStatus
+	}
+	assert.Equal(t, disperser.Finalized, status(90))
+	assert.Equal(t, disperser.Confirmed, status(95))
+
+	// the other is finalized once it is deep enough
+	latest = 105
+	assert.NoError(t, finalizer.FinalizeBlobs(ctx))
+	assert.Equal(t, disperser.Finalized, status(95))
+	rpcClient.AssertNotCalled(t, "CallContext", mock.Anything, mock.Anything, "eth_getBlockByNumber", "finalized", false)
+}
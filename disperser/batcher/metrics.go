@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
 	"github.com/0glabs/0g-data-avail/disperser"
@@ -30,11 +31,20 @@ type MetricsConfig struct {
 }
 
 type EncodingStreamerMetrics struct {
-	EncodedBlobs *prometheus.GaugeVec
+	EncodedBlobs                         *prometheus.GaugeVec
+	EncoderSignatureVerificationFailures prometheus.Counter
+	BlobQueueWait                        *prometheus.HistogramVec
+}
+
+type EncoderPoolMetrics struct {
+	PoolSize          prometheus.Gauge
+	SelectionStrategy *prometheus.CounterVec
 }
 
 type Metrics struct {
 	*EncodingStreamerMetrics
+	*EncoderPoolMetrics
+	*disperser.BatchMetrics
 
 	registry *prometheus.Registry
 
@@ -44,6 +54,9 @@ type Metrics struct {
 	GasUsed          prometheus.Gauge
 	Attestation      *prometheus.GaugeVec
 	BatchError       *prometheus.CounterVec
+	FinalizeLatency  prometheus.Histogram
+	BatchesInFlight  prometheus.Gauge
+	BatchesConfirmed prometheus.Counter
 
 	httpPort string
 	logger   common.Logger
@@ -64,10 +77,46 @@ func NewMetrics(httpPort string, logger common.Logger) *Metrics {
 			},
 			[]string{"type"},
 		),
+		EncoderSignatureVerificationFailures: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "encoder_signature_verification_failures_total",
+				Help:      "number of encoded blobs rejected because their encoding receipt failed verification",
+			},
+		),
+		BlobQueueWait: promauto.With(reg).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "blob_queue_wait_duration_seconds",
+				Help:      "time from the request of a blob to its encoding request, by priority bucket (low, medium or high)",
+				Buckets:   prometheus.ExponentialBuckets(0.1, 2, 15),
+			},
+			[]string{"priority"},
+		),
+	}
+
+	encoderPoolMetrics := EncoderPoolMetrics{
+		PoolSize: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "encoder_pool_size",
+				Help:      "number of healthy encoders in the encoder pool",
+			},
+		),
+		SelectionStrategy: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "encoder_selection_strategy_total",
+				Help:      "number of encoding requests sent to each encoder of the pool per selection strategy",
+			},
+			[]string{"strategy", "encoder"},
+		),
 	}
 
 	metrics := &Metrics{
 		EncodingStreamerMetrics: &encodingStreamerMetrics,
+		EncoderPoolMetrics:      &encoderPoolMetrics,
+		BatchMetrics:            disperser.NewBatchMetrics(reg, namespace),
 		Blob: promauto.With(reg).NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
@@ -116,6 +165,28 @@ func NewMetrics(httpPort string, logger common.Logger) *Metrics {
 			},
 			[]string{"type"},
 		),
+		FinalizeLatency: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "finalization_batch_latency_ms",
+				Help:      "latency of marking a batch of blobs as finalized in milliseconds",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+			},
+		),
+		BatchesInFlight: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "batches_in_flight",
+				Help:      "number of batches being assembled, dispersed or confirmed",
+			},
+		),
+		BatchesConfirmed: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "batches_confirmed_total",
+				Help:      "number of batches confirmed on chain, the batches confirmed per minute are derived with rate(...[1m]) * 60",
+			},
+		),
 		registry: reg,
 		httpPort: httpPort,
 		logger:   logger,
@@ -123,6 +194,11 @@ func NewMetrics(httpPort string, logger common.Logger) *Metrics {
 	return metrics
 }
 
+// Registry returns the registry the metrics are served from, so other components can register their own collectors
+func (g *Metrics) Registry() *prometheus.Registry {
+	return g.registry
+}
+
 func (g *Metrics) UpdateAttestation(operatorCount, nonSignerCount int) {
 	g.Attestation.WithLabelValues("signers").Set(float64(operatorCount - nonSignerCount))
 	g.Attestation.WithLabelValues("non_signers").Set(float64(nonSignerCount))
@@ -153,6 +229,11 @@ func (g *Metrics) IncrementBatchCount(size int64) {
 	g.Batch.WithLabelValues("size").Add(float64(size))
 }
 
+// IncrementConfirmedBatches counts a batch whose blobs are marked as confirmed
+func (g *Metrics) IncrementConfirmedBatches() {
+	g.BatchesConfirmed.Inc()
+}
+
 func (g *Metrics) UpdateBatchError(errType FailReason, numBlobs int) {
 	g.BatchError.WithLabelValues(string(errType)).Add(float64(numBlobs))
 }
@@ -161,6 +242,10 @@ func (g *Metrics) ObserveLatency(stage string, latencyMs float64) {
 	g.BatchProcLatency.WithLabelValues(stage).Observe(latencyMs)
 }
 
+func (g *Metrics) ObserveFinalizationBatchLatency(latencyMs float64) {
+	g.FinalizeLatency.Observe(latencyMs)
+}
+
 func (g *Metrics) Start(ctx context.Context) {
 	g.logger.Info("starting metrics server at ", "port", g.httpPort)
 	addr := fmt.Sprintf(":%s", g.httpPort)
@@ -180,3 +265,20 @@ func (e *EncodingStreamerMetrics) UpdateEncodedBlobs(count int, size uint64) {
 	e.EncodedBlobs.WithLabelValues("size").Set(float64(size))
 	e.EncodedBlobs.WithLabelValues("number").Set(float64(count))
 }
+
+func (e *EncodingStreamerMetrics) IncrementEncoderSignatureVerificationFailures() {
+	e.EncoderSignatureVerificationFailures.Inc()
+}
+
+// ObserveQueueWait records the time a blob of the given priority waited before its encoding was requested
+func (e *EncodingStreamerMetrics) ObserveQueueWait(priority uint8, wait time.Duration) {
+	e.BlobQueueWait.WithLabelValues(priorityBucket(priority)).Observe(wait.Seconds())
+}
+
+func (e *EncoderPoolMetrics) UpdatePoolSize(size int) {
+	e.PoolSize.Set(float64(size))
+}
+
+func (e *EncoderPoolMetrics) IncrementSelection(strategy LoadBalanceStrategy, encoder string) {
+	e.SelectionStrategy.WithLabelValues(string(strategy), encoder).Inc()
+}
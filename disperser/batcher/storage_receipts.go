@@ -0,0 +1,187 @@
+package batcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/disperser"
+	eth_common "github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// storageReceiptKeyPrefix is the prefix of the kv keys storage nodes write their receipts to:
+	// storage-receipt/<batch header hash>/<node id> -> json encoded StorageNodeReceipt
+	storageReceiptKeyPrefix = "storage-receipt/"
+
+	receiptPollInterval      = 10 * time.Second
+	receiptCollectionTimeout = 30 * time.Minute
+)
+
+// StorageReceiptStore reads the receipts storage nodes wrote for a batch
+type StorageReceiptStore interface {
+	GetStorageReceipts(ctx context.Context, batchHeaderHash [32]byte) ([]disperser.StorageNodeReceipt, error)
+}
+
+type kvStorageReceiptStore struct {
+	kvNode   storage_node.KVClient
+	streamId eth_common.Hash
+}
+
+var _ StorageReceiptStore = (*kvStorageReceiptStore)(nil)
+
+// NewKVStorageReceiptStore creates a StorageReceiptStore reading the receipts from the given kv stream
+func NewKVStorageReceiptStore(kvNode storage_node.KVClient, streamId eth_common.Hash) StorageReceiptStore {
+	return &kvStorageReceiptStore{
+		kvNode:   kvNode,
+		streamId: streamId,
+	}
+}
+
+func (s *kvStorageReceiptStore) GetStorageReceipts(ctx context.Context, batchHeaderHash [32]byte) ([]disperser.StorageNodeReceipt, error) {
+	prefix := []byte(storageReceiptKeyPrefix + hex.EncodeToString(batchHeaderHash[:]) + "/")
+	receipts := make([]disperser.StorageNodeReceipt, 0)
+
+	iter := s.kvNode.NewIterator(s.streamId)
+	err := iter.SeekAfter(prefix)
+	for ; err == nil && iter.Valid(); err = iter.Next() {
+		pair := iter.KeyValue()
+		if !bytes.HasPrefix(pair.Key, prefix) {
+			break
+		}
+		var receipt disperser.StorageNodeReceipt
+		if err := json.Unmarshal(pair.Data, &receipt); err != nil {
+			return nil, fmt.Errorf("failed to decode storage receipt %s: %w", string(pair.Key), err)
+		}
+		receipt.NodeID = string(pair.Key[len(prefix):])
+		receipts = append(receipts, receipt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read storage receipts from kv node: %w", err)
+	}
+	return receipts, nil
+}
+
+type pendingReceipts struct {
+	batchHeaderHash [32]byte
+	blobKeys        []disperser.BlobKey
+	deadline        time.Time
+}
+
+// ReceiptCollector collects the storage node receipts of confirmed batches and adds them to the confirmation info of their blobs
+type ReceiptCollector struct {
+	mu sync.Mutex
+
+	Queue disperser.BlobStore
+	Store StorageReceiptStore
+	// MinReceipts is the number of storage nodes which must acknowledge a batch before its receipts are added
+	MinReceipts uint
+	// Timeout is how long the receipts of a batch are collected before giving up
+	Timeout time.Duration
+
+	pending []*pendingReceipts
+	logger  common.Logger
+}
+
+func NewReceiptCollector(queue disperser.BlobStore, store StorageReceiptStore, minReceipts uint, logger common.Logger) *ReceiptCollector {
+	return &ReceiptCollector{
+		Queue:       queue,
+		Store:       store,
+		MinReceipts: minReceipts,
+		Timeout:     receiptCollectionTimeout,
+		pending:     make([]*pendingReceipts, 0),
+		logger:      logger,
+	}
+}
+
+func (c *ReceiptCollector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(receiptPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.CollectPending(ctx)
+			}
+		}
+	}()
+}
+
+// Collect schedules the collection of the receipts of a confirmed batch
+func (c *ReceiptCollector) Collect(batchHeaderHash [32]byte, metadatas []*disperser.BlobMetadata) {
+	blobKeys := make([]disperser.BlobKey, len(metadatas))
+	for i, metadata := range metadatas {
+		blobKeys[i] = metadata.GetBlobKey()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = append(c.pending, &pendingReceipts{
+		batchHeaderHash: batchHeaderHash,
+		blobKeys:        blobKeys,
+		deadline:        time.Now().Add(c.Timeout),
+	})
+}
+
+// CollectPending checks the receipts of the pending batches once, batches with enough receipts or past their deadline are no longer pending
+func (c *ReceiptCollector) CollectPending(ctx context.Context) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make([]*pendingReceipts, 0)
+	c.mu.Unlock()
+
+	remaining := make([]*pendingReceipts, 0)
+	for _, batch := range pending {
+		if !c.collectBatch(ctx, batch) {
+			if time.Now().After(batch.deadline) {
+				c.logger.Warn("[receipt collector] gave up collecting storage receipts", "batch header hash", hex.EncodeToString(batch.batchHeaderHash[:]))
+				continue
+			}
+			remaining = append(remaining, batch)
+		}
+	}
+
+	c.mu.Lock()
+	c.pending = append(remaining, c.pending...)
+	c.mu.Unlock()
+}
+
+// collectBatch returns whether the receipts of the batch were added to its blobs
+func (c *ReceiptCollector) collectBatch(ctx context.Context, batch *pendingReceipts) bool {
+	receipts, err := c.Store.GetStorageReceipts(ctx, batch.batchHeaderHash)
+	if err != nil {
+		c.logger.Warn("[receipt collector] failed to get storage receipts", "err", err)
+		return false
+	}
+
+	// each node counts once
+	nodes := make(map[string]struct{}, len(receipts))
+	unique := make([]disperser.StorageNodeReceipt, 0, len(receipts))
+	for _, receipt := range receipts {
+		if _, ok := nodes[receipt.NodeID]; ok {
+			continue
+		}
+		nodes[receipt.NodeID] = struct{}{}
+		unique = append(unique, receipt)
+	}
+	if uint(len(unique)) < c.MinReceipts {
+		return false
+	}
+
+	for _, blobKey := range batch.blobKeys {
+		if err := c.Queue.SetStorageNodeReceipts(ctx, blobKey, unique); err != nil {
+			c.logger.Error("[receipt collector] failed to set storage receipts", "blob key", blobKey.String(), "err", err)
+		}
+	}
+	c.logger.Info("[receipt collector] collected storage receipts", "batch header hash", hex.EncodeToString(batch.batchHeaderHash[:]), "receipts", len(unique))
+	return true
+}
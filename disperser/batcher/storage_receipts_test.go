@@ -0,0 +1,114 @@
+package batcher_test
+
+import (
+	"context"
+	"testing"
+
+	cmock "github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/batcher"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockReceiptStore serves the receipts storage nodes wrote to the kv store
+type mockReceiptStore struct {
+	receipts map[[32]byte][]disperser.StorageNodeReceipt
+	calls    int
+}
+
+func (s *mockReceiptStore) GetStorageReceipts(ctx context.Context, batchHeaderHash [32]byte) ([]disperser.StorageNodeReceipt, error) {
+	s.calls++
+	return s.receipts[batchHeaderHash], nil
+}
+
+func confirmTestBlobs(t *testing.T, queue disperser.BlobStore, batchHeaderHash [32]byte, numBlobs int) []*disperser.BlobMetadata {
+	ctx := context.Background()
+	metadatas := make([]*disperser.BlobMetadata, numBlobs)
+	for i := range metadatas {
+		key, err := queue.StoreBlob(ctx, &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: []byte{byte(i + 1)},
+		}, uint64(i))
+		assert.NoError(t, err)
+		metadata, err := queue.GetBlobMetadata(ctx, key)
+		assert.NoError(t, err)
+		metadatas[i], err = queue.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{
+			BatchHeaderHash: batchHeaderHash,
+			BlobIndex:       uint32(i),
+		})
+		assert.NoError(t, err)
+	}
+	return metadatas
+}
+
+func TestReceiptCollector(t *testing.T) {
+	ctx := context.Background()
+	logger := &cmock.Logger{}
+	queue := memorydb.NewBlobStore(1024*1024, logger)
+	store := &mockReceiptStore{receipts: make(map[[32]byte][]disperser.StorageNodeReceipt)}
+	collector := batcher.NewReceiptCollector(queue, store, 2, logger)
+
+	batchHeaderHash := [32]byte{1}
+	metadatas := confirmTestBlobs(t, queue, batchHeaderHash, 2)
+	collector.Collect(batchHeaderHash, metadatas)
+
+	node1 := disperser.StorageNodeReceipt{NodeID: "node1", Signature: []byte{1}, Timestamp: 100}
+	node2 := disperser.StorageNodeReceipt{NodeID: "node2", Signature: []byte{2}, Timestamp: 101}
+
+	// receipts of the same node count once
+	store.receipts[batchHeaderHash] = []disperser.StorageNodeReceipt{node1, node1}
+	collector.CollectPending(ctx)
+	for _, metadata := range metadatas {
+		refreshed, err := queue.GetBlobMetadata(ctx, metadata.GetBlobKey())
+		assert.NoError(t, err)
+		assert.Empty(t, refreshed.ConfirmationInfo.StorageNodeReceipts)
+	}
+
+	store.receipts[batchHeaderHash] = []disperser.StorageNodeReceipt{node1, node2, node1}
+	collector.CollectPending(ctx)
+	assert.Equal(t, 2, store.calls)
+	for i, metadata := range metadatas {
+		refreshed, err := queue.GetBlobMetadata(ctx, metadata.GetBlobKey())
+		assert.NoError(t, err)
+		assert.Equal(t, disperser.Confirmed, refreshed.BlobStatus)
+		assert.Equal(t, uint32(i), refreshed.ConfirmationInfo.BlobIndex)
+		assert.Equal(t, []disperser.StorageNodeReceipt{node1, node2}, refreshed.ConfirmationInfo.StorageNodeReceipts)
+
+		// the receipts are kept when the metadata is serialized, e.g. to be persisted on the kv node
+		data, err := refreshed.Serialize()
+		assert.NoError(t, err)
+		deserialized, err := new(disperser.BlobMetadata).Deserialize(data)
+		assert.NoError(t, err)
+		assert.Equal(t, refreshed.ConfirmationInfo.StorageNodeReceipts, deserialized.ConfirmationInfo.StorageNodeReceipts)
+	}
+
+	// collected batches are no longer pending
+	collector.CollectPending(ctx)
+	assert.Equal(t, 2, store.calls)
+}
+
+func TestReceiptCollectorTimeout(t *testing.T) {
+	ctx := context.Background()
+	logger := &cmock.Logger{}
+	queue := memorydb.NewBlobStore(1024*1024, logger)
+	store := &mockReceiptStore{receipts: make(map[[32]byte][]disperser.StorageNodeReceipt)}
+	collector := batcher.NewReceiptCollector(queue, store, 1, logger)
+	collector.Timeout = 0
+
+	batchHeaderHash := [32]byte{2}
+	metadatas := confirmTestBlobs(t, queue, batchHeaderHash, 1)
+	collector.Collect(batchHeaderHash, metadatas)
+
+	// the batch is given up once its deadline passed without enough receipts
+	collector.CollectPending(ctx)
+	store.receipts[batchHeaderHash] = []disperser.StorageNodeReceipt{{NodeID: "node1"}}
+	collector.CollectPending(ctx)
+	assert.Equal(t, 1, store.calls)
+	refreshed, err := queue.GetBlobMetadata(ctx, metadatas[0].GetBlobKey())
+	assert.NoError(t, err)
+	assert.Empty(t, refreshed.ConfirmationInfo.StorageNodeReceipts)
+}
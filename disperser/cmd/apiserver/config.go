@@ -1,11 +1,17 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/0glabs/0g-data-avail/common/aws"
 	"github.com/0glabs/0g-data-avail/common/geth"
 	"github.com/0glabs/0g-data-avail/common/logging"
 	"github.com/0glabs/0g-data-avail/common/ratelimit"
 	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/0glabs/0g-data-avail/disperser/apiserver"
 	"github.com/0glabs/0g-data-avail/disperser/cmd/apiserver/flags"
@@ -18,6 +24,7 @@ type Config struct {
 	BlobstoreConfig   blobstore.Config
 	ServerConfig      disperser.ServerConfig
 	LoggerConfig      logging.Config
+	TracingConfig     tracing.TracingConfig
 	MetricsConfig     disperser.MetricsConfig
 	RatelimiterConfig ratelimit.Config
 	RateConfig        apiserver.RateConfig
@@ -25,7 +32,6 @@ type Config struct {
 	EthClientConfig   geth.EthClientConfig
 	EnableRatelimiter bool
 	BucketTableName   string
-	BucketStoreSize   int
 }
 
 func NewConfig(ctx *cli.Context) (Config, error) {
@@ -34,24 +40,102 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 	if err != nil {
 		return Config{}, err
 	}
+	ratelimiterConfig.Blocklist = ratelimit.ParseBlocklist(ctx.GlobalString(flags.RatelimitBlocklist.Name))
 
 	rateConfig, err := apiserver.ReadCLIConfig(ctx)
 	if err != nil {
 		return Config{}, err
 	}
+	rateConfig.CIDRRates, err = apiserver.ParseCIDRRates(ctx.GlobalStringSlice(flags.CIDRRates.Name))
+	if err != nil {
+		return Config{}, err
+	}
+
+	quorumRetentionDays, err := blobstore.ParseQuorumRetentionDays(ctx.GlobalStringSlice(flags.QuorumRetentionDays.Name))
+	if err != nil {
+		return Config{}, err
+	}
+
+	methodTimeouts, err := apiserver.ParseMethodTimeouts(ctx.GlobalStringSlice(flags.GrpcMethodTimeouts.Name))
+	if err != nil {
+		return Config{}, err
+	}
 
 	config := Config{
 		AwsClientConfig: aws.ReadClientConfig(ctx, flags.FlagPrefix),
 		ServerConfig: disperser.ServerConfig{
-			GrpcPort: ctx.GlobalString(flags.GrpcPortFlag.Name),
+			GrpcPort:                  ctx.GlobalString(flags.GrpcPortFlag.Name),
+			HttpPort:                  ctx.GlobalString(flags.HttpPortFlag.Name),
+			SkipSchemaValidation:      ctx.GlobalBool(flags.SkipSchemaValidation.Name),
+			EnableOnchainFallback:     ctx.GlobalBool(flags.EnableOnchainFallback.Name),
+			OnchainFallbackContract:   ctx.GlobalString(flags.OnchainFallbackContract.Name),
+			AttestationKeyFile:        ctx.GlobalString(flags.AttestationKeyFile.Name),
+			LoadShedHeapPct:           ctx.GlobalFloat64(flags.LoadShedHeapPct.Name),
+			LoadShedGCPause:           time.Duration(ctx.GlobalUint(flags.LoadShedGCMs.Name)) * time.Millisecond,
+			AdminToken:                ctx.GlobalString(flags.AdminTokenFlag.Name),
+			AllowPrefixScan:           ctx.GlobalBool(flags.AllowPrefixScan.Name),
+			MaxConcurrentStreams:      uint32(ctx.GlobalUint(flags.GrpcMaxConcurrentStreams.Name)),
+			DedupWindow:               ctx.GlobalDuration(flags.DedupWindow.Name),
+			IdempotencyWindowDuration: ctx.GlobalDuration(flags.IdempotencyWindow.Name),
+			BlobSizePolicyURL:         ctx.GlobalString(flags.BlobSizePolicyURL.Name),
+			MaxStreamBufferSize:       int(ctx.GlobalUint(flags.MaxStreamBufferSize.Name)),
+			WatchTimeout:              ctx.GlobalDuration(flags.WatchTimeout.Name),
+			DeleteOnCancel:            ctx.GlobalBool(flags.DeleteOnCancel.Name),
+			MaxTTLExtension:           ctx.GlobalDuration(flags.MaxTTLExtension.Name),
+			RateLimitStatusRate:       ctx.GlobalFloat64(flags.RateLimitStatusRate.Name),
+			AdminPort:                 ctx.GlobalString(flags.AdminPortFlag.Name),
+			RateConfigFile:            ctx.GlobalString(flags.RateConfigFile.Name),
+			AuditLogPath:              ctx.GlobalString(flags.AuditLogPath.Name),
+			APIKeyFile:                ctx.GlobalString(flags.APIKeyFile.Name),
+			EnableBlobCallbacks:       ctx.GlobalBool(flags.EnableBlobCallbacks.Name),
+			CallbackMaxRetries:        ctx.GlobalInt(flags.CallbackMaxRetries.Name),
+			CallbackURLAllowlist:      ctx.GlobalStringSlice(flags.CallbackURLAllowlist.Name),
+			ShutdownTimeout:           ctx.GlobalDuration(flags.ShutdownTimeout.Name),
+			MaxConnectionsPerIP:       ctx.GlobalInt(flags.MaxConnectionsPerIP.Name),
+			MinBlobSize:               ctx.GlobalInt(flags.MinBlobSize.Name),
+			FinalizationDepth:         uint32(ctx.GlobalUint(flags.FinalizationDepth.Name)),
+			FallbackRPCEndpoints:      apiserver.ParseRPCEndpoints(ctx.GlobalString(flags.RPCFallbackEndpoints.Name)),
+			DefaultRequestTimeout:     ctx.GlobalDuration(flags.GrpcDefaultRequestTimeout.Name),
+			PerMethodTimeouts:         methodTimeouts,
+			MaxRecvMsgSizeMiB:         ctx.GlobalInt(flags.GrpcMaxRecvMsgSizeMiB.Name),
+			MaxSendMsgSizeMiB:         ctx.GlobalInt(flags.GrpcMaxSendMsgSizeMiB.Name),
+			TLSConfig: disperser.TLSConfig{
+				CertFile:          ctx.GlobalString(flags.TLSCertFile.Name),
+				KeyFile:           ctx.GlobalString(flags.TLSKeyFile.Name),
+				ClientCAFile:      ctx.GlobalString(flags.TLSClientCAFile.Name),
+				RequireClientCert: ctx.GlobalBool(flags.TLSRequireClientCert.Name),
+			},
+			KeepaliveConfig: disperser.KeepaliveConfig{
+				ServerKeepaliveTime:    ctx.GlobalDuration(flags.GrpcKeepaliveTime.Name),
+				ServerKeepaliveTimeout: ctx.GlobalDuration(flags.GrpcKeepaliveTimeout.Name),
+				MaxConnectionIdle:      ctx.GlobalDuration(flags.GrpcMaxConnectionIdle.Name),
+				MaxConnectionAge:       ctx.GlobalDuration(flags.GrpcMaxConnectionAge.Name),
+				MaxConnectionAgeGrace:  ctx.GlobalDuration(flags.GrpcMaxConnectionAgeGrace.Name),
+				MinClientKeepaliveTime: ctx.GlobalDuration(flags.GrpcKeepaliveMinClientTime.Name),
+			},
 		},
 		EthClientConfig: geth.ReadEthClientConfig(ctx),
 		BlobstoreConfig: blobstore.Config{
-			BucketName:            ctx.GlobalString(flags.S3BucketNameFlag.Name),
-			TableName:             ctx.GlobalString(flags.DynamoDBTableNameFlag.Name),
-			MetadataHashAsBlobKey: ctx.GlobalBool(flags.MetadataHashAsBlobKey.Name),
+			BucketName:              ctx.GlobalString(flags.S3BucketNameFlag.Name),
+			TableName:               ctx.GlobalString(flags.DynamoDBTableNameFlag.Name),
+			BatchHeaderTableName:    ctx.GlobalString(flags.BatchHeaderTableNameFlag.Name),
+			ShadowBucketName:        ctx.GlobalString(flags.ShadowStoreBucketFlag.Name),
+			MetadataHashAsBlobKey:   ctx.GlobalBool(flags.MetadataHashAsBlobKey.Name),
+			QuorumRetentionDays:     quorumRetentionDays,
+			VerifyContentHash:       ctx.GlobalBool(flags.VerifyContentHash.Name),
+			DeduplicateBlobs:        ctx.GlobalBool(flags.DeduplicateBlobs.Name),
+			CompressBlobs:           ctx.GlobalBool(flags.CompressBlobs.Name),
+			MetadataCacheSize:       ctx.GlobalInt(flags.MetadataCacheSize.Name),
+			TimeRangeQueryLimit:     ctx.GlobalInt(flags.TimeRangeQueryLimit.Name),
+			BackfillUploadIndex:     ctx.GlobalBool(flags.BackfillUploadIndex.Name),
+			MultipartThresholdBytes: ctx.GlobalInt64(flags.MultipartThresholdFlag.Name),
+			PartSize:                ctx.GlobalInt64(flags.MultipartPartSizeFlag.Name),
+			MaxConcurrentParts:      ctx.GlobalInt(flags.MultipartMaxConcurrentPartsFlag.Name),
+			MaxRetries:              ctx.GlobalInt(flags.StorageMaxRetriesFlag.Name),
+			InitialBackoff:          ctx.GlobalDuration(flags.StorageInitialBackoffFlag.Name),
 		},
-		LoggerConfig: logging.ReadCLIConfig(ctx, flags.FlagPrefix),
+		LoggerConfig:  logging.ReadCLIConfig(ctx, flags.FlagPrefix),
+		TracingConfig: tracing.ReadCLIConfig(ctx, flags.FlagPrefix),
 		MetricsConfig: disperser.MetricsConfig{
 			HTTPPort:      ctx.GlobalString(flags.MetricsHTTPPort.Name),
 			EnableMetrics: ctx.GlobalBool(flags.EnableMetrics.Name),
@@ -60,8 +144,76 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 		RateConfig:        rateConfig,
 		EnableRatelimiter: ctx.GlobalBool(flags.EnableRatelimiter.Name),
 		BucketTableName:   ctx.GlobalString(flags.BucketTableName.Name),
-		BucketStoreSize:   ctx.GlobalInt(flags.BucketStoreSize.Name),
 		StorageNodeConfig: storage_node.ReadClientConfig(ctx, flags.FlagPrefix),
 	}
 	return config, nil
 }
+
+// Validate checks the config is consistent, so the server fails at startup rather than when serving requests
+func (c Config) Validate() error {
+	if c.BlobstoreConfig.BucketName == "" {
+		return errors.New("s3 bucket name is required")
+	}
+	if c.BlobstoreConfig.TableName == "" {
+		return errors.New("dynamodb table name is required")
+	}
+	if err := validatePort(c.ServerConfig.GrpcPort); err != nil {
+		return fmt.Errorf("invalid grpc port: %w", err)
+	}
+	if c.ServerConfig.HttpPort != "" {
+		if err := validatePort(c.ServerConfig.HttpPort); err != nil {
+			return fmt.Errorf("invalid http gateway port: %w", err)
+		}
+	}
+	if c.ServerConfig.AdminPort != "" {
+		if err := validatePort(c.ServerConfig.AdminPort); err != nil {
+			return fmt.Errorf("invalid admin port: %w", err)
+		}
+	}
+	if err := validatePort(c.MetricsConfig.HTTPPort); err != nil {
+		return fmt.Errorf("invalid metrics http port: %w", err)
+	}
+	if err := ratelimit.ValidateGlobalRateParams(c.RatelimiterConfig.GlobalRateParams); err != nil {
+		return fmt.Errorf("invalid rate limiter config: %w", err)
+	}
+	// the buckets are shared by the apiserver replicas, so that the rate limits apply to all of them
+	if c.EnableRatelimiter && c.BucketTableName == "" {
+		return errors.New("rate bucket table name is required when the rate limiter is enabled")
+	}
+	if c.ServerConfig.EnableBlobCallbacks && len(c.ServerConfig.CallbackURLAllowlist) == 0 {
+		return errors.New("callback url allowlist is required when the blob callbacks are enabled")
+	}
+	if err := validateTLSConfig(c.ServerConfig.TLSConfig); err != nil {
+		return fmt.Errorf("invalid tls config: %w", err)
+	}
+	return nil
+}
+
+// validateTLSConfig checks the files needed by the enabled TLS features are set
+func validateTLSConfig(c disperser.TLSConfig) error {
+	if c.CertFile == "" {
+		if c.KeyFile != "" || c.ClientCAFile != "" || c.RequireClientCert {
+			return errors.New("the server certificate is required to enable TLS")
+		}
+		return nil
+	}
+	if c.KeyFile == "" {
+		return errors.New("the server key is required with the server certificate")
+	}
+	if c.RequireClientCert && c.ClientCAFile == "" {
+		return errors.New("the client CA is required to require client certificates")
+	}
+	return nil
+}
+
+// validatePort checks the port is a number of an unprivileged port
+func validatePort(port string) error {
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", port)
+	}
+	if p < 1024 || p > 65535 {
+		return fmt.Errorf("%d is not in [1024, 65535]", p)
+	}
+	return nil
+}
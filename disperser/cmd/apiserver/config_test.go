@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func newValidConfig() Config {
+	return Config{
+		BlobstoreConfig: blobstore.Config{
+			BucketName: "blobs",
+			TableName:  "blob-metadata",
+		},
+		ServerConfig:  disperser.ServerConfig{GrpcPort: "32001"},
+		MetricsConfig: disperser.MetricsConfig{HTTPPort: "9100"},
+		RatelimiterConfig: ratelimit.Config{
+			GlobalRateParams: common.GlobalRateParams{
+				BucketSizes: []time.Duration{time.Second},
+				Multipliers: []float32{1},
+			},
+		},
+		EnableRatelimiter: true,
+		BucketTableName:   "rate-buckets",
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, newValidConfig().Validate())
+
+	// the bucket table isn't needed without the rate limiter
+	config := newValidConfig()
+	config.EnableRatelimiter = false
+	config.BucketTableName = ""
+	assert.NoError(t, config.Validate())
+
+	for _, tc := range []struct {
+		name   string
+		modify func(c *Config)
+		err    string
+	}{
+		{"missing bucket", func(c *Config) { c.BlobstoreConfig.BucketName = "" }, "s3 bucket name"},
+		{"missing table", func(c *Config) { c.BlobstoreConfig.TableName = "" }, "dynamodb table name"},
+		{"grpc port not a number", func(c *Config) { c.ServerConfig.GrpcPort = "grpc" }, "invalid grpc port"},
+		{"grpc port empty", func(c *Config) { c.ServerConfig.GrpcPort = "" }, "invalid grpc port"},
+		{"grpc port privileged", func(c *Config) { c.ServerConfig.GrpcPort = "443" }, "invalid grpc port"},
+		{"grpc port too large", func(c *Config) { c.ServerConfig.GrpcPort = "65536" }, "invalid grpc port"},
+		{"http gateway port not a number", func(c *Config) { c.ServerConfig.HttpPort = "http" }, "invalid http gateway port"},
+		{"admin port not a number", func(c *Config) { c.ServerConfig.AdminPort = "admin" }, "invalid admin port"},
+		{"metrics port not a number", func(c *Config) { c.MetricsConfig.HTTPPort = "metrics" }, "invalid metrics http port"},
+		{"metrics port out of range", func(c *Config) { c.MetricsConfig.HTTPPort = "0" }, "invalid metrics http port"},
+		{"multipliers mismatch", func(c *Config) { c.RatelimiterConfig.Multipliers = []float32{1, 2} }, "invalid rate limiter config"},
+		{"zero multiplier", func(c *Config) { c.RatelimiterConfig.Multipliers = []float32{0} }, "invalid rate limiter config"},
+		{"zero bucket size", func(c *Config) { c.RatelimiterConfig.BucketSizes = []time.Duration{0} }, "invalid rate limiter config"},
+		{"negative requests per second", func(c *Config) { c.RatelimiterConfig.RequestsPerSecond = -1 }, "invalid rate limiter config"},
+		{"missing bucket table", func(c *Config) { c.BucketTableName = "" }, "rate bucket table name"},
+		{"tls key without certificate", func(c *Config) { c.ServerConfig.TLSConfig.KeyFile = "server.key" }, "invalid tls config"},
+		{"tls certificate without key", func(c *Config) { c.ServerConfig.TLSConfig.CertFile = "server.crt" }, "invalid tls config"},
+		{"client cert required without client CA", func(c *Config) {
+			c.ServerConfig.TLSConfig = disperser.TLSConfig{CertFile: "server.crt", KeyFile: "server.key", RequireClientCert: true}
+		}, "invalid tls config"},
+		{"blob callbacks without allowlist", func(c *Config) { c.ServerConfig.EnableBlobCallbacks = true }, "callback url allowlist"},
+	} {
+		config := newValidConfig()
+		tc.modify(&config)
+		assert.ErrorContains(t, config.Validate(), tc.err, tc.name)
+	}
+}
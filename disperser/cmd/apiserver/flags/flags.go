@@ -1,10 +1,15 @@
 package flags
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/0glabs/0g-data-avail/common"
 	"github.com/0glabs/0g-data-avail/common/aws"
 	"github.com/0glabs/0g-data-avail/common/logging"
 	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/tracing"
+	"github.com/0glabs/0g-data-avail/core"
 	"github.com/urfave/cli"
 )
 
@@ -34,6 +39,18 @@ var (
 		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_PORT"),
 	}
 	/* Optional Flags*/
+	BatchHeaderTableNameFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "batch-header-table-name"),
+		Usage:    "Name of the dynamodb table to store batch headers once per batch. If not provided, batch headers are stored in the metadata of every blob",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "BATCH_HEADER_TABLE_NAME"),
+	}
+	ShadowStoreBucketFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "shadow-store-bucket"),
+		Usage:    "Name of the bucket blobs are additionally copied to for disaster recovery. If not provided, blobs are only stored in the primary bucket",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "SHADOW_STORE_BUCKET"),
+	}
 	MetricsHTTPPort = cli.StringFlag{
 		Name:     common.PrefixFlag(FlagPrefix, "metrics-http-port"),
 		Usage:    "the http port which the metrics prometheus server is listening",
@@ -54,13 +71,13 @@ var (
 	}
 	BucketTableName = cli.StringFlag{
 		Name:   common.PrefixFlag(FlagPrefix, "rate-bucket-table-name"),
-		Usage:  "name of the dynamodb table to store rate limiter buckets. If not provided, a local store will be used",
+		Usage:  "name of the dynamodb table to store rate limiter buckets, shared by all the replicas, required by the apiserver if the rate limiter is enabled. If not provided, the combined server uses a local store",
 		Value:  "",
 		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "RATE_BUCKET_TABLE_NAME"),
 	}
 	BucketStoreSize = cli.UintFlag{
 		Name:     common.PrefixFlag(FlagPrefix, "rate-bucket-store-size"),
-		Usage:    "size (max number of entries) of the local store to use for rate limiting buckets",
+		Usage:    "size (max number of entries) of the local store to use for rate limiting buckets of the combined server",
 		Value:    100_000,
 		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "RATE_BUCKET_STORE_SIZE"),
 		Required: false,
@@ -70,6 +87,383 @@ var (
 		Usage:  "use metadata hash as blob key",
 		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "METADATA_HASH_AS_BLOB_KEY"),
 	}
+	VerifyContentHash = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "verify-content-hash"),
+		Usage:  "verify the blob contents downloaded from S3 against the blob hash of their metadata",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "VERIFY_CONTENT_HASH"),
+	}
+	DeduplicateBlobs = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "deduplicate-blobs"),
+		Usage:  "skip the upload of the blobs whose content is already stored in S3. It doesn't apply if the metadata hash is used as blob key",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "DEDUPLICATE_BLOBS"),
+	}
+	CompressBlobs = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "compress-blobs"),
+		Usage:  "compress the blob data with zstd before it's uploaded to S3",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "COMPRESS_BLOBS"),
+	}
+	MetadataCacheSize = cli.IntFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "metadata-cache-size"),
+		Usage:  "number of blob metadata cached in memory in front of DynamoDB. If 0, the metadata are not cached",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "METADATA_CACHE_SIZE"),
+	}
+	TimeRangeQueryLimit = cli.IntFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "time-range-query-limit"),
+		Usage:  "maximum number of blobs returned by the admin query of the blobs by status and time range",
+		Value:  10000,
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "TIME_RANGE_QUERY_LIMIT"),
+	}
+	BackfillUploadIndex = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "backfill-upload-index"),
+		Usage:  "add the blobs stored before the upload time index was added to the index at startup, in the background, so that they are included in the queries by upload time. It scans the whole metadata table",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "BACKFILL_UPLOAD_INDEX"),
+	}
+	MultipartThresholdFlag = cli.Int64Flag{
+		Name:     common.PrefixFlag(FlagPrefix, "multipart-threshold-bytes"),
+		Usage:    "size in bytes above which blobs are uploaded to S3 in parts. If 0, blobs are uploaded in a single request",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MULTIPART_THRESHOLD_BYTES"),
+	}
+	MultipartPartSizeFlag = cli.Int64Flag{
+		Name:     common.PrefixFlag(FlagPrefix, "multipart-part-size"),
+		Usage:    "size in bytes of the parts of the multipart uploads, at least 5MiB",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MULTIPART_PART_SIZE"),
+		Value:    8 * 1024 * 1024,
+	}
+	MultipartMaxConcurrentPartsFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "multipart-max-concurrent-parts"),
+		Usage:    "maximum number of parts of a multipart upload uploaded in parallel",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MULTIPART_MAX_CONCURRENT_PARTS"),
+		Value:    4,
+	}
+	StorageMaxRetriesFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "storage-max-retries"),
+		Usage:    "number of times the S3 and DynamoDB requests failing with a transient error are retried",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "STORAGE_MAX_RETRIES"),
+		Value:    3,
+	}
+	StorageInitialBackoffFlag = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "storage-initial-backoff"),
+		Usage:    "backoff before the first retry of a storage request, doubled after each retry",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "STORAGE_INITIAL_BACKOFF"),
+		Value:    100 * time.Millisecond,
+	}
+	QuorumRetentionDays = cli.StringSliceFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "quorum-retention-days"),
+		Usage:    "number of days to retain blobs of a quorum, in the form of quorumID:days. Can be repeated for multiple quorums",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "QUORUM_RETENTION_DAYS"),
+		Required: false,
+	}
+	// AdmissionBackpressureThreshold is only registered by the combined server, the standalone server has no encoding
+	// queue to apply backpressure from
+	AdmissionBackpressureThreshold = cli.Float64Flag{
+		Name:     common.PrefixFlag(FlagPrefix, "admission-backpressure-threshold"),
+		Usage:    "fraction of the encoding queue capacity above which new blobs are rejected. Set to 0 to disable",
+		Value:    0.8,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ADMISSION_BACKPRESSURE_THRESHOLD"),
+		Required: false,
+	}
+	SkipSchemaValidation = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "skip-schema-validation"),
+		Usage:  "skip validating the schema of the blob metadata table before serving requests",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "SKIP_SCHEMA_VALIDATION"),
+	}
+	EnableOnchainFallback = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "enable-onchain-fallback"),
+		Usage:  "look up the blob confirmation on chain when the blob status is not found locally",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "ENABLE_ONCHAIN_FALLBACK"),
+	}
+	AttestationKeyFile = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "attestation-key-file"),
+		Usage:    "file of the hex encoded ECDSA private key used to sign the submission attestations of new blobs. Blobs are not attested if not provided",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ATTESTATION_KEY_FILE"),
+		Required: false,
+	}
+	LoadShedHeapPct = cli.Float64Flag{
+		Name:     common.PrefixFlag(FlagPrefix, "load-shed-heap-pct"),
+		Usage:    "percentage of the memory limit (GOMEMLIMIT) used by the heap above which new blobs are rejected. Set to 0 to disable",
+		Value:    85,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "LOAD_SHED_HEAP_PCT"),
+		Required: false,
+	}
+	LoadShedGCMs = cli.UintFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "load-shed-gc-ms"),
+		Usage:    "GC pause in milliseconds above which new blobs are rejected. Set to 0 to disable",
+		Value:    500,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "LOAD_SHED_GC_MS"),
+		Required: false,
+	}
+	GrpcMaxConcurrentStreams = cli.UintFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-max-concurrent-streams"),
+		Usage:    "maximum number of concurrent grpc streams per client connection. Set to 0 to not limit the streams",
+		Value:    1000,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_MAX_CONCURRENT_STREAMS"),
+		Required: false,
+	}
+	AdminTokenFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "admin-token"),
+		Usage:    "bearer token required to call the admin APIs. The admin APIs are not served if not provided",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ADMIN_TOKEN"),
+		Required: false,
+	}
+	BlobSizePolicyURL = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "blob-size-policy-url"),
+		Usage:    "Open Policy Agent data API URL of the policy deciding whether to accept the size of new blobs, e.g. http://opa:8181/v1/data/disperser/blob_size/allow. Only the protocol blob size limit is enforced if not provided",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "BLOB_SIZE_POLICY_URL"),
+		Required: false,
+	}
+	AllowPrefixScan = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "allow-prefix-scan"),
+		Usage:  "enable the admin API looking up blobs by a hash prefix, which scans the whole blob metadata table",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "ALLOW_PREFIX_SCAN"),
+	}
+	DedupWindow = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "dedup-window"),
+		Usage:    "time window within which an account submitting the same blob again is rejected. Set to 0 to accept duplicate blobs",
+		Value:    60 * time.Second,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "DEDUP_WINDOW"),
+		Required: false,
+	}
+	IdempotencyWindow = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "idempotency-window"),
+		Usage:    "time window within which a request retried with the same idempotency key by the same account returns the reply of the original request. Set to 0 to ignore the idempotency keys",
+		Value:    10 * time.Minute,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "IDEMPOTENCY_WINDOW"),
+		Required: false,
+	}
+	HttpPortFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "http-port"),
+		Usage:    "Port of the HTTP/JSON gateway of the dispersal APIs. The gateway is not served if not provided",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "HTTP_PORT"),
+		Required: false,
+	}
+	MaxStreamBufferSize = cli.UintFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "max-stream-buffer-size"),
+		Usage:    "maximum number of bytes of a blob streamed to DisperseBlobStream. Set to 0 to only enforce the protocol blob size limit",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MAX_STREAM_BUFFER_SIZE"),
+		Required: false,
+	}
+	WatchTimeout = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "watch-timeout"),
+		Usage:    "maximum duration of a blob status watch, the client has to watch the blob again after it",
+		Value:    10 * time.Minute,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "WATCH_TIMEOUT"),
+		Required: false,
+	}
+	DeleteOnCancel = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "delete-on-cancel"),
+		Usage:  "remove the blobs cancelled by CancelBlob, instead of only marking them as failed",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "DELETE_ON_CANCEL"),
+	}
+	MaxTTLExtension = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "max-ttl-extension"),
+		Usage:    "maximum duration added to the expiry of a blob by a single ExtendBlobTTL call. Set to 0 to disable the TTL extension",
+		Value:    7 * 24 * time.Hour,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MAX_TTL_EXTENSION"),
+		Required: false,
+	}
+	RateLimitStatusRate = cli.Float64Flag{
+		Name:     common.PrefixFlag(FlagPrefix, "rate-limit-status-rate"),
+		Usage:    "number of GetRateLimitStatus calls per second allowed to each caller. Set to 0 to disable the limit",
+		Value:    10,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "RATE_LIMIT_STATUS_RATE"),
+		Required: false,
+	}
+	CIDRRates = cli.StringSliceFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "cidr-rates"),
+		Usage:    "per account rates of the requesters in a CIDR block, in the form of cidr=bytesPerSec:blobsPerSec. Can be repeated for multiple blocks, which must not overlap",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "CIDR_RATES"),
+		Required: false,
+	}
+	AdminPortFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "admin-port"),
+		Usage:    "internal port of the admin http endpoints, e.g. POST /admin/rateconfig. The endpoints are disabled if empty",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ADMIN_PORT"),
+		Required: false,
+	}
+	RateConfigFile = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "rate-config-file"),
+		Usage:    "JSON file of the rate config, overriding the auth flags. It is reloaded whenever it changes",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "RATE_CONFIG_FILE"),
+		Required: false,
+	}
+	AuditLogPath = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "audit-log-path"),
+		Usage:    "file the audit log of the dispersals, status checks and retrievals is written to, as newline delimited JSON. Auditing is disabled if empty",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "AUDIT_LOG_PATH"),
+		Required: false,
+	}
+	RatelimitBlocklist = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "ratelimit-blocklist"),
+		Usage:    "comma separated list of the requester IDs, e.g. IPs, which are always rejected by the rate limiter. It can be replaced with POST /admin/blocklist",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "RATELIMIT_BLOCKLIST"),
+		Required: false,
+	}
+	APIKeyFile = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "api-key-file"),
+		Usage:    "JSON file mapping the API keys of the callers to their account IDs. The callers must present a key in the x-api-key header if set. The file is reloaded on SIGHUP",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "API_KEY_FILE"),
+		Required: false,
+	}
+	TLSCertFile = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "tls-cert-file"),
+		Usage:    "PEM certificate of the grpc server. The server is plaintext if empty",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "TLS_CERT_FILE"),
+		Required: false,
+	}
+	TLSKeyFile = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "tls-key-file"),
+		Usage:    "PEM private key of the grpc server certificate",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "TLS_KEY_FILE"),
+		Required: false,
+	}
+	TLSClientCAFile = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "tls-client-ca-file"),
+		Usage:    "PEM CA bundle the client certificates are verified with, for mutual TLS",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "TLS_CLIENT_CA_FILE"),
+		Required: false,
+	}
+	TLSRequireClientCert = cli.BoolFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "tls-require-client-cert"),
+		Usage:    "reject the clients which don't present a certificate signed by the client CA",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "TLS_REQUIRE_CLIENT_CERT"),
+		Required: false,
+	}
+	EnableBlobCallbacks = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "enable-blob-callbacks"),
+		Usage:  "serve RegisterBlobCallback, which POSTs the final status of the blobs to the registered callback URLs",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "ENABLE_BLOB_CALLBACKS"),
+	}
+	CallbackMaxRetries = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "callback-max-retries"),
+		Usage:    "number of times a blob callback is retried, with exponential backoff, when it fails or gets a non-2xx response",
+		Value:    3,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "CALLBACK_MAX_RETRIES"),
+		Required: false,
+	}
+	CallbackURLAllowlist = cli.StringSliceFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "callback-url-allowlist"),
+		Usage:    "prefix the blob callback URLs must start with, e.g. https://hooks.example.com/. Can be repeated for multiple prefixes",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "CALLBACK_URL_ALLOWLIST"),
+		Required: false,
+	}
+	ShutdownTimeout = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "shutdown-timeout"),
+		Usage:    "time the in-flight requests are waited for on shutdown before they are cancelled",
+		Value:    30 * time.Second,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "SHUTDOWN_TIMEOUT"),
+		Required: false,
+	}
+	MaxConnectionsPerIP = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "max-connections-per-ip"),
+		Usage:    "maximum number of concurrent requests of a client IP. Set to 0 to not limit the requests",
+		Value:    50,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MAX_CONNECTIONS_PER_IP"),
+		Required: false,
+	}
+	GrpcMaxRecvMsgSizeMiB = cli.IntFlag{
+		Name: common.PrefixFlag(FlagPrefix, "grpc-max-recv-msg-size-mib"),
+		Usage: fmt.Sprintf("maximum size in MiB of the messages received by the grpc server and of the HTTP gateway requests. "+
+			"It should be above the maximum blob size of %d bytes, plus the request overhead, or the largest blobs are rejected. "+
+			"Set to 0 to use the grpc default of 4 MiB", core.MaxBlobSize),
+		Value:    300,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_MAX_RECV_MSG_SIZE_MIB"),
+		Required: false,
+	}
+	GrpcMaxSendMsgSizeMiB = cli.IntFlag{
+		Name: common.PrefixFlag(FlagPrefix, "grpc-max-send-msg-size-mib"),
+		Usage: fmt.Sprintf("maximum size in MiB of the messages sent by the grpc server. "+
+			"It should be above the maximum blob size of %d bytes, plus the reply overhead, or RetrieveBlob fails for the largest blobs. "+
+			"Set to 0 to use the grpc default", core.MaxBlobSize),
+		Value:    0,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_MAX_SEND_MSG_SIZE_MIB"),
+		Required: false,
+	}
+	GrpcKeepaliveTime = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-keepalive-time"),
+		Usage:    "time without activity after which the server pings the client, at least 1s. It should be below the idle timeout of the load balancers in front of the server",
+		Value:    2 * time.Minute,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_KEEPALIVE_TIME"),
+		Required: false,
+	}
+	GrpcKeepaliveTimeout = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-keepalive-timeout"),
+		Usage:    "time the server waits for the keepalive ping ack before closing the connection",
+		Value:    20 * time.Second,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_KEEPALIVE_TIMEOUT"),
+		Required: false,
+	}
+	GrpcMaxConnectionIdle = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-max-connection-idle"),
+		Usage:    "time without any stream after which a connection is closed. Set to 0 to keep the idle connections",
+		Value:    0,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_MAX_CONNECTION_IDLE"),
+		Required: false,
+	}
+	GrpcMaxConnectionAge = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-max-connection-age"),
+		Usage:    "time after which a connection is closed, e.g. to rebalance the clients across the servers. Set to 0 to not limit the age",
+		Value:    0,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_MAX_CONNECTION_AGE"),
+		Required: false,
+	}
+	GrpcMaxConnectionAgeGrace = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-max-connection-age-grace"),
+		Usage:    "time the streams of a connection closed for its age are waited for. Set to 0 to wait forever",
+		Value:    0,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_MAX_CONNECTION_AGE_GRACE"),
+		Required: false,
+	}
+	GrpcKeepaliveMinClientTime = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-keepalive-min-client-time"),
+		Usage:    "minimum time between the keepalive pings of a client, the clients pinging more often are disconnected with GOAWAY. It must be lower than the keepalive time of the clients",
+		Value:    10 * time.Second,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_KEEPALIVE_MIN_CLIENT_TIME"),
+		Required: false,
+	}
+	GrpcDefaultRequestTimeout = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-default-request-timeout"),
+		Usage:    "deadline of the requests without one, and maximum deadline of DisperseBlob. Set to 0 to not bound the requests",
+		Value:    30 * time.Second,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_DEFAULT_REQUEST_TIMEOUT"),
+		Required: false,
+	}
+	GrpcMethodTimeouts = cli.StringSliceFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "grpc-method-timeouts"),
+		Usage:    "timeout of a method overriding the default request timeout, in the form of method=duration e.g. DisperseBlob=10s. Can be repeated for multiple methods",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "GRPC_METHOD_TIMEOUTS"),
+		Required: false,
+	}
+	MinBlobSize = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "min-blob-size"),
+		Usage:    "minimum size of the dispersed blobs in bytes",
+		Value:    1,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MIN_BLOB_SIZE"),
+		Required: false,
+	}
+	RPCFallbackEndpoints = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "rpc-fallback-endpoints"),
+		Usage:    "comma separated list of the RPC endpoints the latest finalized block is fetched from, in order, when the primary RPC endpoint fails",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "RPC_FALLBACK_ENDPOINTS"),
+		Required: false,
+	}
+	FinalizationDepth = cli.UintFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "finalization-depth"),
+		Usage:    "number of blocks below the latest block a block is considered finalized at, for the chains without the finalized block tag. Set to 0 to use the finalized block tag",
+		Value:    0,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "FINALIZATION_DEPTH"),
+		Required: false,
+	}
+	OnchainFallbackContract = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "onchain-fallback-contract"),
+		Usage:    "address of the contract providing getBlobConfirmation, required if the on-chain fallback is enabled",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ONCHAIN_FALLBACK_CONTRACT"),
+		Required: false,
+	}
 )
 
 var RequiredFlags = []cli.Flag{
@@ -85,6 +479,66 @@ var OptionalFlags = []cli.Flag{
 	EnableRatelimiter,
 	BucketStoreSize,
 	MetadataHashAsBlobKey,
+	VerifyContentHash,
+	DeduplicateBlobs,
+	CompressBlobs,
+	MetadataCacheSize,
+	TimeRangeQueryLimit,
+	BackfillUploadIndex,
+	MultipartThresholdFlag,
+	MultipartPartSizeFlag,
+	MultipartMaxConcurrentPartsFlag,
+	StorageMaxRetriesFlag,
+	StorageInitialBackoffFlag,
+	SkipSchemaValidation,
+	QuorumRetentionDays,
+	EnableOnchainFallback,
+	OnchainFallbackContract,
+	BatchHeaderTableNameFlag,
+	AttestationKeyFile,
+	ShadowStoreBucketFlag,
+	LoadShedHeapPct,
+	LoadShedGCMs,
+	AdminTokenFlag,
+	AllowPrefixScan,
+	BlobSizePolicyURL,
+	GrpcMaxConcurrentStreams,
+	DedupWindow,
+	IdempotencyWindow,
+	MaxStreamBufferSize,
+	HttpPortFlag,
+	WatchTimeout,
+	DeleteOnCancel,
+	MaxTTLExtension,
+	RateLimitStatusRate,
+	CIDRRates,
+	AdminPortFlag,
+	RateConfigFile,
+	AuditLogPath,
+	RatelimitBlocklist,
+	APIKeyFile,
+	TLSCertFile,
+	TLSKeyFile,
+	TLSClientCAFile,
+	TLSRequireClientCert,
+	EnableBlobCallbacks,
+	CallbackMaxRetries,
+	CallbackURLAllowlist,
+	ShutdownTimeout,
+	MaxConnectionsPerIP,
+	GrpcMaxRecvMsgSizeMiB,
+	GrpcMaxSendMsgSizeMiB,
+	GrpcKeepaliveTime,
+	GrpcKeepaliveTimeout,
+	GrpcMaxConnectionIdle,
+	GrpcMaxConnectionAge,
+	GrpcMaxConnectionAgeGrace,
+	GrpcKeepaliveMinClientTime,
+	GrpcDefaultRequestTimeout,
+	GrpcMethodTimeouts,
+	MinBlobSize,
+	RPCFallbackEndpoints,
+	FinalizationDepth,
 }
 
 // Flags contains the list of configuration options available to the binary.
@@ -93,6 +547,7 @@ var Flags []cli.Flag
 func init() {
 	Flags = append(RequiredFlags, OptionalFlags...)
 	Flags = append(Flags, logging.CLIFlags(EnvVarPrefix, FlagPrefix)...)
+	Flags = append(Flags, tracing.CLIFlags(EnvVarPrefix, FlagPrefix, "zgda-disperser")...)
 	Flags = append(Flags, ratelimit.RatelimiterCLIFlags(EnvVarPrefix, FlagPrefix)...)
 	Flags = append(Flags, aws.ClientFlags(EnvVarPrefix, FlagPrefix)...)
 }
@@ -5,20 +5,25 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/audit"
 	"github.com/0glabs/0g-data-avail/disperser/apiserver"
 	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
-	"github.com/0glabs/0g-storage-client/kv"
-	"github.com/0glabs/0g-storage-client/node"
 
 	"github.com/0glabs/0g-data-avail/common/aws/dynamodb"
 	"github.com/0glabs/0g-data-avail/common/aws/s3"
 	"github.com/0glabs/0g-data-avail/common/logging"
 	"github.com/0glabs/0g-data-avail/common/ratelimit"
-	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/0glabs/0g-data-avail/disperser/cmd/apiserver/flags"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/urfave/cli"
 )
@@ -52,16 +57,33 @@ func RunDisperserServer(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
 
 	logger, err := logging.GetLogger(config.LoggerConfig)
 	if err != nil {
 		return err
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), config.TracingConfig, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("failed to flush the traces", "err", err)
+		}
+	}()
+
 	var blobStore disperser.BlobStore
 	var ratelimiter common.RateLimiter
 
-	s3Client, err := s3.NewClient(config.AwsClientConfig, logger)
+	s3Client, err := s3.NewClient(config.AwsClientConfig, logger, s3.WithMultipartUpload(s3.MultipartConfig{
+		ThresholdBytes:     config.BlobstoreConfig.MultipartThresholdBytes,
+		PartSize:           config.BlobstoreConfig.PartSize,
+		MaxConcurrentParts: config.BlobstoreConfig.MaxConcurrentParts,
+	}))
 	if err != nil {
 		return err
 	}
@@ -71,44 +93,133 @@ func RunDisperserServer(ctx *cli.Context) error {
 		return err
 	}
 
+	// TODO: create a separate metrics for batcher
+	metrics := disperser.NewMetrics(config.MetricsConfig.HTTPPort, logger)
+
 	bucketName := config.BlobstoreConfig.BucketName
 	logger.Info("Creating blob store", "bucket", bucketName)
-	blobMetadataStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, config.BlobstoreConfig.TableName, 0)
-	blobStore = blobstore.NewSharedStorage(bucketName, s3Client, config.BlobstoreConfig.MetadataHashAsBlobKey, blobMetadataStore, logger)
-
-	if config.EnableRatelimiter {
-		globalParams := config.RatelimiterConfig.GlobalRateParams
-
-		var bucketStore common.KVStore[common.RateBucketParams]
-		if config.BucketTableName != "" {
-			dynamoClient, err := dynamodb.NewClient(config.AwsClientConfig, logger)
-			if err != nil {
-				return err
-			}
-			bucketStore = store.NewDynamoParamStore[common.RateBucketParams](dynamoClient, config.BucketTableName)
-		} else {
-			bucketStore, err = store.NewLocalParamStore[common.RateBucketParams](config.BucketStoreSize)
+	blobstoreMetrics := blobstore.NewMetrics(metrics.Registry(), "zgda_disperser")
+	blobMetadataStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, config.BlobstoreConfig.TableName, 0, blobstore.WithMetadataCache(config.BlobstoreConfig.MetadataCacheSize, blobstoreMetrics),
+		blobstore.WithTimeRangeQueryLimit(config.BlobstoreConfig.TimeRangeQueryLimit))
+	if config.BlobstoreConfig.BackfillUploadIndex {
+		go func() {
+			logger.Info("Backfilling the upload index of the blob metadata")
+			updated, err := blobMetadataStore.BackfillRequestedAtDay(context.Background())
 			if err != nil {
-				return err
+				logger.Error("Failed to backfill the upload index of the blob metadata", "updated", updated, "err", err)
+				return
 			}
+			logger.Info("Backfilled the upload index of the blob metadata", "updated", updated)
+		}()
+	}
+	var batchHeaderStore *blobstore.BatchHeaderStore
+	if config.BlobstoreConfig.BatchHeaderTableName != "" {
+		batchHeaderStore, err = blobstore.NewBatchHeaderStore(dynamoClient, logger, config.BlobstoreConfig.BatchHeaderTableName)
+		if err != nil {
+			return err
 		}
-		ratelimiter = ratelimit.NewRateLimiter(globalParams, bucketStore, config.RatelimiterConfig.Allowlist, logger)
 	}
+	var storageOpts []blobstore.SharedStorageOption
+	if config.BlobstoreConfig.VerifyContentHash {
+		storageOpts = append(storageOpts, blobstore.WithContentHashVerification())
+	}
+	if config.BlobstoreConfig.DeduplicateBlobs {
+		storageOpts = append(storageOpts, blobstore.WithBlobDeduplication())
+	}
+	if config.BlobstoreConfig.CompressBlobs {
+		storageOpts = append(storageOpts, blobstore.WithCompression())
+	}
+	if config.BlobstoreConfig.MaxRetries > 0 {
+		storageOpts = append(storageOpts, blobstore.WithRetry(config.BlobstoreConfig.MaxRetries, config.BlobstoreConfig.InitialBackoff))
+	}
+	blobStore = blobstore.NewSharedStorage(bucketName, s3Client, config.BlobstoreConfig.MetadataHashAsBlobKey, config.BlobstoreConfig.QuorumRetentionDays, blobMetadataStore, batchHeaderStore, config.BlobstoreConfig.MaxConcurrentUploads, config.BlobstoreConfig.ShadowBucketName, blobstoreMetrics, logger, storageOpts...)
 
-	// TODO: create a separate metrics for batcher
-	metrics := disperser.NewMetrics(config.MetricsConfig.HTTPPort, logger)
+	if config.EnableRatelimiter {
+		bucketStore, err := ratelimit.NewDynamoDBBucketStore(dynamoClient, config.BucketTableName)
+		if err != nil {
+			return err
+		}
+		ratelimiter = ratelimit.NewRateLimiterFromConfig(config.RatelimiterConfig, bucketStore, ratelimit.NewMetrics(metrics.Registry(), "zgda_disperser"), logger)
+	}
 
-	var kvClient *kv.Client
+	var kvClient storage_node.KVClient
 	var rpcClient *rpc.Client
+	var fallbackRPCClients []*rpc.Client
 
 	if config.BlobstoreConfig.MetadataHashAsBlobKey {
-		kvClient = kv.NewClient(node.MustNewClient(config.StorageNodeConfig.KVNodeURL), nil)
+		kvClient, err = storage_node.NewKVClient(config.StorageNodeConfig, storage_node.NewPoolMetrics(metrics.Registry(), "zgda_disperser"), logger)
+		if err != nil {
+			return err
+		}
 		rpcClient, err = rpc.Dial(config.EthClientConfig.RPCURL)
 		if err != nil {
 			return err
 		}
+		for _, endpoint := range config.ServerConfig.FallbackRPCEndpoints {
+			client, err := rpc.Dial(endpoint)
+			if err != nil {
+				return fmt.Errorf("failed to dial the fallback rpc endpoint %s: %w", endpoint, err)
+			}
+			fallbackRPCClients = append(fallbackRPCClients, client)
+		}
+	}
+	opts := []apiserver.ServerOption{
+		apiserver.WithReadinessCheck(func(ctx context.Context) error {
+			return blobstore.ValidateTableSchema(ctx, dynamoClient, config.BlobstoreConfig.TableName)
+		}),
+	}
+	if len(fallbackRPCClients) > 0 {
+		opts = append(opts, apiserver.WithFallbackRPCClients(fallbackRPCClients...))
+	}
+	if config.ServerConfig.EnableOnchainFallback {
+		if !eth_common.IsHexAddress(config.ServerConfig.OnchainFallbackContract) {
+			return fmt.Errorf("invalid on-chain fallback contract address: %q", config.ServerConfig.OnchainFallbackContract)
+		}
+		if rpcClient == nil {
+			rpcClient, err = rpc.Dial(config.EthClientConfig.RPCURL)
+			if err != nil {
+				return err
+			}
+		}
+		reader, err := apiserver.NewBlobConfirmationReader(ethclient.NewClient(rpcClient), eth_common.HexToAddress(config.ServerConfig.OnchainFallbackContract))
+		if err != nil {
+			return err
+		}
+		opts = append(opts, apiserver.WithOnchainFallback(reader))
 	}
-	server := apiserver.NewDispersalServer(config.ServerConfig, blobStore, logger, metrics, ratelimiter, config.RateConfig, config.BlobstoreConfig.MetadataHashAsBlobKey, kvClient, config.StorageNodeConfig.KVStreamId, rpcClient)
+	if config.ServerConfig.AttestationKeyFile != "" {
+		attestationKey, err := crypto.LoadECDSA(config.ServerConfig.AttestationKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load attestation key: %w", err)
+		}
+		opts = append(opts, apiserver.WithAttestationKey(attestationKey))
+	}
+	if config.ServerConfig.LoadShedHeapPct > 0 || config.ServerConfig.LoadShedGCPause > 0 {
+		shedder := apiserver.NewLoadShedder(apiserver.NewRuntimeLoadSampler(), config.ServerConfig.LoadShedHeapPct, config.ServerConfig.LoadShedGCPause, logger)
+		opts = append(opts, apiserver.WithLoadShedder(shedder))
+	}
+	if config.ServerConfig.BlobSizePolicyURL != "" {
+		opts = append(opts, apiserver.WithBlobSizeValidator(apiserver.NewOPABlobSizeValidator(config.ServerConfig.BlobSizePolicyURL)))
+	}
+	if config.ServerConfig.EnableBlobCallbacks {
+		notifier := apiserver.NewBlobCallbackNotifier(config.ServerConfig.CallbackURLAllowlist, config.ServerConfig.CallbackMaxRetries, apiserver.DefaultCallbackInitialBackoff)
+		opts = append(opts, apiserver.WithBlobCallbacks(notifier))
+	}
+	if config.ServerConfig.AuditLogPath != "" {
+		auditLogger := audit.NewFileAuditLogger(config.ServerConfig.AuditLogPath, logger)
+		defer auditLogger.Close()
+		opts = append(opts, apiserver.WithAuditLogger(auditLogger))
+	}
+	if config.ServerConfig.APIKeyFile != "" {
+		apiKeyAuth, err := apiserver.LoadAPIKeyAuth(config.ServerConfig.APIKeyFile, logger)
+		if err != nil {
+			return err
+		}
+		apiKeyAuth.ReloadOnSignal(context.Background())
+		opts = append(opts, apiserver.WithAPIKeyAuth(apiKeyAuth))
+	}
+	server := apiserver.NewDispersalServer(config.ServerConfig, blobStore, logger, metrics, ratelimiter, config.RateConfig, config.BlobstoreConfig.MetadataHashAsBlobKey, kvClient, config.StorageNodeConfig.KVStreamId, rpcClient, opts...)
+	metrics.Handle("/readyz", server.ReadyzHandler())
 
 	// Enable Metrics Block
 	if config.MetricsConfig.EnableMetrics {
@@ -117,5 +228,24 @@ func RunDisperserServer(ctx *cli.Context) error {
 		logger.Info("Enabled metrics for Disperser", "socket", httpSocket)
 	}
 
-	return server.Start(context.Background())
+	// the in-flight requests are drained on shutdown
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if config.ServerConfig.HttpPort != "" {
+		gateway := apiserver.NewHTTPGatewayServer(server, config.ServerConfig.HttpPort)
+		go func() {
+			if err := gateway.Start(shutdownCtx); err != nil {
+				logger.Error("http gateway failed", "err", err)
+			}
+		}()
+	}
+	if config.ServerConfig.AdminPort != "" || config.ServerConfig.RateConfigFile != "" {
+		adminServer := apiserver.NewAdminServer(server, config.ServerConfig.AdminPort, config.ServerConfig.RateConfigFile)
+		go func() {
+			if err := adminServer.Start(shutdownCtx); err != nil {
+				logger.Error("admin server failed", "err", err)
+			}
+		}()
+	}
+	return server.Start(shutdownCtx)
 }
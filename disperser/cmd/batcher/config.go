@@ -5,6 +5,7 @@ import (
 	"github.com/0glabs/0g-data-avail/common/geth"
 	"github.com/0glabs/0g-data-avail/common/logging"
 	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/disperser/batcher"
 	"github.com/0glabs/0g-data-avail/disperser/cmd/batcher/flags"
 	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
@@ -18,29 +19,68 @@ type Config struct {
 	EthClientConfig   geth.EthClientConfig
 	AwsClientConfig   aws.ClientConfig
 	LoggerConfig      logging.Config
+	TracingConfig     tracing.TracingConfig
 	MetricsConfig     batcher.MetricsConfig
 	StorageNodeConfig storage_node.ClientConfig
 }
 
-func NewConfig(ctx *cli.Context) Config {
+func NewConfig(ctx *cli.Context) (Config, error) {
+	quorumRetentionDays, err := blobstore.ParseQuorumRetentionDays(ctx.GlobalStringSlice(flags.QuorumRetentionDaysFlag.Name))
+	if err != nil {
+		return Config{}, err
+	}
+
 	config := Config{
 		BlobstoreConfig: blobstore.Config{
-			BucketName:            ctx.GlobalString(flags.S3BucketNameFlag.Name),
-			TableName:             ctx.GlobalString(flags.DynamoDBTableNameFlag.Name),
-			MetadataHashAsBlobKey: ctx.GlobalBool(flags.MetadataHashAsBlobKey.Name),
+			BucketName:                ctx.GlobalString(flags.S3BucketNameFlag.Name),
+			TableName:                 ctx.GlobalString(flags.DynamoDBTableNameFlag.Name),
+			BatchHeaderTableName:      ctx.GlobalString(flags.BatchHeaderTableNameFlag.Name),
+			MetadataHashAsBlobKey:     ctx.GlobalBool(flags.MetadataHashAsBlobKey.Name),
+			QuorumRetentionDays:       quorumRetentionDays,
+			MaxConcurrentUploads:      ctx.GlobalInt(flags.S3MaxConcurrentUploadsFlag.Name),
+			CacheDir:                  ctx.GlobalString(flags.BlobCacheDirFlag.Name),
+			PrefetchConcurrency:       ctx.GlobalInt(flags.PrefetchConcurrencyFlag.Name),
+			StatusResultWarnThreshold: ctx.GlobalInt(flags.StatusResultWarnThresholdFlag.Name),
+			VerifyContentHash:         ctx.GlobalBool(flags.VerifyContentHash.Name),
+			ExpiryCleanupInterval:     ctx.GlobalDuration(flags.ExpiryCleanupIntervalFlag.Name),
+			MaxBatchSize:              ctx.GlobalInt(flags.MetadataMaxBatchSizeFlag.Name),
+			FlushInterval:             ctx.GlobalDuration(flags.MetadataFlushIntervalFlag.Name),
+			MultipartThresholdBytes:   ctx.GlobalInt64(flags.MultipartThresholdFlag.Name),
+			PartSize:                  ctx.GlobalInt64(flags.MultipartPartSizeFlag.Name),
+			MaxConcurrentParts:        ctx.GlobalInt(flags.MultipartMaxConcurrentPartsFlag.Name),
+			MaxRetries:                ctx.GlobalInt(flags.StorageMaxRetriesFlag.Name),
+			InitialBackoff:            ctx.GlobalDuration(flags.StorageInitialBackoffFlag.Name),
+			SerializationFormat:       ctx.GlobalString(flags.SerializationFormatFlag.Name),
 		},
 		EthClientConfig: geth.ReadEthClientConfig(ctx),
 		AwsClientConfig: aws.ReadClientConfig(ctx, flags.FlagPrefix),
 		LoggerConfig:    logging.ReadCLIConfig(ctx, flags.FlagPrefix),
+		TracingConfig:   tracing.ReadCLIConfig(ctx, flags.FlagPrefix),
 		BatcherConfig: batcher.Config{
 			PullInterval:             ctx.GlobalDuration(flags.PullIntervalFlag.Name),
 			FinalizerInterval:        ctx.GlobalDuration(flags.FinalizerIntervalFlag.Name),
 			EncoderSocket:            ctx.GlobalString(flags.EncoderSocket.Name),
+			EncoderSockets:           batcher.ParseEncoderAddresses(ctx.GlobalString(flags.EncoderSocketsFlag.Name)),
+			EncoderPublicKey:         ctx.GlobalString(flags.EncoderPublicKeyFlag.Name),
 			NumConnections:           ctx.GlobalInt(flags.NumConnectionsFlag.Name),
 			EncodingRequestQueueSize: ctx.GlobalInt(flags.EncodingRequestQueueSizeFlag.Name),
 			BatchSizeMBLimit:         ctx.GlobalUint(flags.BatchSizeLimitFlag.Name),
 			MaxNumRetriesPerBlob:     ctx.GlobalUint(flags.MaxNumRetriesPerBlobFlag.Name),
 			ConfirmerNum:             ctx.GlobalUint(flags.ConfirmerNumFlag.Name),
+			MinStorageReceipts:       ctx.GlobalUint(flags.MinStorageReceiptsFlag.Name),
+			FinalizerBatchSize:       ctx.GlobalInt(flags.FinalizerBatchSizeFlag.Name),
+			FinalizationDepth:        uint32(ctx.GlobalUint(flags.FinalizationDepthFlag.Name)),
+			MaxBatchesInFlight:       ctx.GlobalUint(flags.MaxBatchesInFlightFlag.Name),
+			BatchFormationStrategy:   ctx.GlobalString(flags.BatchFormationStrategyFlag.Name),
+			MaxStarvationAge:         ctx.GlobalDuration(flags.MaxStarvationAgeFlag.Name),
+			MaxBlobsPerBatch:         ctx.GlobalInt(flags.MaxBlobsPerBatchFlag.Name),
+			UseBlobHeaderHashV2:      ctx.GlobalBool(flags.UseBlobHeaderHashV2Flag.Name),
+			ShutdownTimeout:          ctx.GlobalDuration(flags.ShutdownTimeoutFlag.Name),
+			EncoderPool: batcher.EncoderPoolConfig{
+				Strategy:               batcher.LoadBalanceStrategy(ctx.GlobalString(flags.EncoderLoadBalanceStrategyFlag.Name)),
+				MaxConsecutiveFailures: ctx.GlobalInt(flags.EncoderMaxConsecutiveFailuresFlag.Name),
+				ProbeInterval:          ctx.GlobalDuration(flags.EncoderProbeIntervalFlag.Name),
+			},
 		},
 		TimeoutConfig: batcher.TimeoutConfig{
 			EncodingTimeout:   ctx.GlobalDuration(flags.EncodingTimeoutFlag.Name),
@@ -53,5 +93,5 @@ func NewConfig(ctx *cli.Context) Config {
 		},
 		StorageNodeConfig: storage_node.ReadClientConfig(ctx, flags.FlagPrefix),
 	}
-	return config
+	return config, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/0glabs/0g-data-avail/common/geth"
 	"github.com/0glabs/0g-data-avail/common/logging"
 	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/urfave/cli"
 )
 
@@ -42,6 +43,39 @@ var (
 		Required: false,
 		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ENCODER_ADDRESS"),
 	}
+	EncoderSocketsFlag = cli.StringFlag{
+		Name:     "encoder-sockets",
+		Usage:    "comma-separated ip:port of multiple encoder servers to distribute the encoding requests across, overrides encoder-socket",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ENCODER_ADDRESSES"),
+	}
+	EncoderLoadBalanceStrategyFlag = cli.StringFlag{
+		Name:     "encoder-load-balance-strategy",
+		Usage:    "strategy to distribute the encoding requests across the encoder servers: round-robin, least-connections or random",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ENCODER_LOAD_BALANCE_STRATEGY"),
+		Value:    "round-robin",
+	}
+	EncoderMaxConsecutiveFailuresFlag = cli.IntFlag{
+		Name:     "encoder-max-consecutive-failures",
+		Usage:    "number of consecutive failed requests after which an encoder server is removed from the pool",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ENCODER_MAX_CONSECUTIVE_FAILURES"),
+		Value:    3,
+	}
+	EncoderProbeIntervalFlag = cli.DurationFlag{
+		Name:     "encoder-probe-interval",
+		Usage:    "interval to probe the encoder servers removed from the pool",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ENCODER_PROBE_INTERVAL"),
+		Value:    10 * time.Second,
+	}
+	EncoderPublicKeyFlag = cli.StringFlag{
+		Name:     "encoder-pubkey",
+		Usage:    "hex encoded public key of the encoder used to verify the encoding receipts, receipts are not verified if empty",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "ENCODER_PUBKEY"),
+	}
 	EnableMetrics = cli.BoolFlag{
 		Name:     common.PrefixFlag(FlagPrefix, "enable-metrics"),
 		Usage:    "start metrics server",
@@ -97,6 +131,20 @@ var (
 		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "FINALIZER_INTERVAL"),
 		Value:    6 * time.Minute,
 	}
+	FinalizationDepthFlag = cli.UintFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "finalization-depth"),
+		Usage:    "Number of blocks below the latest block a block is considered finalized at, for the chains without the finalized block tag. The finalizer interval should then be close to the block time, so that the blobs are finalized as soon as they are deep enough. Set to 0 to use the finalized block tag",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "FINALIZATION_DEPTH"),
+		Value:    0,
+	}
+	FinalizerBatchSizeFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "finalizer-batch-size"),
+		Usage:    "Maximum number of blobs finalized per finalizer cycle",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "FINALIZER_BATCH_SIZE"),
+		Value:    100,
+	}
 	EncodingRequestQueueSizeFlag = cli.IntFlag{
 		Name:     common.PrefixFlag(FlagPrefix, "encoding-request-queue-size"),
 		Usage:    "Size of the encoding request queue",
@@ -133,6 +181,158 @@ var (
 		Usage:  "use metadata hash as blob key",
 		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "METADATA_HASH_AS_BLOB_KEY"),
 	}
+	QuorumRetentionDaysFlag = cli.StringSliceFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "quorum-retention-days"),
+		Usage:    "number of days to retain blobs of a quorum, in the form of quorumID:days, applied when the blobs are confirmed. Can be repeated for multiple quorums, it should match the retentions of the disperser server",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "QUORUM_RETENTION_DAYS"),
+		Required: false,
+	}
+	VerifyContentHash = cli.BoolFlag{
+		Name:   common.PrefixFlag(FlagPrefix, "verify-content-hash"),
+		Usage:  "verify the blob contents downloaded from S3 against the blob hash of their metadata",
+		EnvVar: common.PrefixEnvVar(EnvVarPrefix, "VERIFY_CONTENT_HASH"),
+	}
+	BatchHeaderTableNameFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "batch-header-table-name"),
+		Usage:    "Name of the dynamodb table to store batch headers once per batch. If not provided, batch headers are stored in the metadata of every blob",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "BATCH_HEADER_TABLE_NAME"),
+	}
+	S3MaxConcurrentUploadsFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "s3-max-concurrent-uploads"),
+		Usage:    "maximum number of S3 operations run in parallel when fetching the blobs of a batch",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "S3_MAX_CONCURRENT_UPLOADS"),
+		Value:    64,
+	}
+	BlobCacheDirFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "blob-cache-dir"),
+		Usage:    "directory the contents of the blobs waiting to be encoded are prefetched into. If not provided, blobs are not prefetched",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "BLOB_CACHE_DIR"),
+	}
+	PrefetchConcurrencyFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "prefetch-concurrency"),
+		Usage:    "maximum number of blobs prefetched in parallel into the blob cache directory",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "PREFETCH_CONCURRENCY"),
+		Value:    8,
+	}
+	StatusResultWarnThresholdFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "status-result-warn-threshold"),
+		Usage:    "number of blobs read at once by status above which a warning is logged",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "STATUS_RESULT_WARN_THRESHOLD"),
+		Value:    10000,
+	}
+	ExpiryCleanupIntervalFlag = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "expiry-cleanup-interval"),
+		Usage:    "interval at which the expired blobs are removed from S3 and DynamoDB. If 0, expired blobs are not removed",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "EXPIRY_CLEANUP_INTERVAL"),
+	}
+	MetadataMaxBatchSizeFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "metadata-max-batch-size"),
+		Usage:    "number of buffered blob metadata writes which are flushed to DynamoDB together",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "METADATA_MAX_BATCH_SIZE"),
+		Value:    25,
+	}
+	MetadataFlushIntervalFlag = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "metadata-flush-interval"),
+		Usage:    "interval at which the buffered blob metadata writes are flushed to DynamoDB. If 0, the writes are not buffered",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "METADATA_FLUSH_INTERVAL"),
+	}
+	MultipartThresholdFlag = cli.Int64Flag{
+		Name:     common.PrefixFlag(FlagPrefix, "multipart-threshold-bytes"),
+		Usage:    "size in bytes above which blobs are uploaded to S3 in parts. If 0, blobs are uploaded in a single request",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MULTIPART_THRESHOLD_BYTES"),
+	}
+	MultipartPartSizeFlag = cli.Int64Flag{
+		Name:     common.PrefixFlag(FlagPrefix, "multipart-part-size"),
+		Usage:    "size in bytes of the parts of the multipart uploads, at least 5MiB",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MULTIPART_PART_SIZE"),
+		Value:    8 * 1024 * 1024,
+	}
+	MultipartMaxConcurrentPartsFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "multipart-max-concurrent-parts"),
+		Usage:    "maximum number of parts of a multipart upload uploaded in parallel",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MULTIPART_MAX_CONCURRENT_PARTS"),
+		Value:    4,
+	}
+	StorageMaxRetriesFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "storage-max-retries"),
+		Usage:    "number of times the S3 and DynamoDB requests failing with a transient error are retried",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "STORAGE_MAX_RETRIES"),
+		Value:    3,
+	}
+	StorageInitialBackoffFlag = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "storage-initial-backoff"),
+		Usage:    "backoff before the first retry of a storage request, doubled after each retry",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "STORAGE_INITIAL_BACKOFF"),
+		Value:    100 * time.Millisecond,
+	}
+	MaxBatchesInFlightFlag = cli.UintFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "max-batches-in-flight"),
+		Usage:    "maximum number of batches assembled or waiting for confirmation at the same time. If 0, batches are not limited",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MAX_BATCHES_IN_FLIGHT"),
+		Value:    2,
+	}
+	SerializationFormatFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "serialization-format"),
+		Usage:    "format of the blob metadata written to the KV stream and the local store, gob or json. The metadata written in either format are read",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "SERIALIZATION_FORMAT"),
+		Value:    "gob",
+	}
+	BatchFormationStrategyFlag = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "batch-formation-strategy"),
+		Usage:    "strategy selecting the blobs of each batch, fifo (by request time), priority-weighted (random, weighted toward retried blobs) or priority (by the priority of the requests, then by request time)",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "BATCH_FORMATION_STRATEGY"),
+		Value:    "priority",
+	}
+	MaxStarvationAgeFlag = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "max-starvation-age"),
+		Usage:    "time after which a blob is encoded and batched first regardless of its priority, so that the low priority blobs are not starved. Set to 0 to never promote the blobs",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MAX_STARVATION_AGE"),
+		Value:    5 * time.Minute,
+	}
+	MaxBlobsPerBatchFlag = cli.IntFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "max-blobs-per-batch"),
+		Usage:    "maximum number of blobs in a batch. If 0, blobs are not limited",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MAX_BLOBS_PER_BATCH"),
+		Value:    0,
+	}
+	UseBlobHeaderHashV2Flag = cli.BoolFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "use-blob-header-hash-v2"),
+		Usage:    "hash the blob headers of the batch root with their quorum parameters, so a commitment can't be reused across quorums",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "USE_BLOB_HEADER_HASH_V2"),
+	}
+	ShutdownTimeoutFlag = cli.DurationFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "shutdown-timeout"),
+		Usage:    "time the batches in flight are waited for on shutdown before they are cancelled",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "SHUTDOWN_TIMEOUT"),
+		Value:    30 * time.Second,
+	}
+	MinStorageReceiptsFlag = cli.UintFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "min-storage-receipts"),
+		Usage:    "number of storage node receipts collected for each confirmed batch. If 0, receipts are not collected",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MIN_STORAGE_RECEIPTS"),
+		Value:    0,
+	}
 )
 
 var RequiredFlags = []cli.Flag{
@@ -151,11 +351,41 @@ var OptionalFlags = []cli.Flag{
 	ChainWriteTimeoutFlag,
 	NumConnectionsFlag,
 	FinalizerIntervalFlag,
+	FinalizerBatchSizeFlag,
+	FinalizationDepthFlag,
 	EncodingRequestQueueSizeFlag,
 	MaxNumRetriesPerBlobFlag,
 	ConfirmerNumFlag,
 	TargetNumChunksFlag,
 	MetadataHashAsBlobKey,
+	QuorumRetentionDaysFlag,
+	VerifyContentHash,
+	EncoderPublicKeyFlag,
+	EncoderSocketsFlag,
+	EncoderLoadBalanceStrategyFlag,
+	EncoderMaxConsecutiveFailuresFlag,
+	EncoderProbeIntervalFlag,
+	BatchHeaderTableNameFlag,
+	S3MaxConcurrentUploadsFlag,
+	BlobCacheDirFlag,
+	PrefetchConcurrencyFlag,
+	StatusResultWarnThresholdFlag,
+	ExpiryCleanupIntervalFlag,
+	MetadataMaxBatchSizeFlag,
+	MetadataFlushIntervalFlag,
+	MultipartThresholdFlag,
+	MultipartPartSizeFlag,
+	MultipartMaxConcurrentPartsFlag,
+	StorageMaxRetriesFlag,
+	StorageInitialBackoffFlag,
+	SerializationFormatFlag,
+	MinStorageReceiptsFlag,
+	MaxBatchesInFlightFlag,
+	BatchFormationStrategyFlag,
+	MaxStarvationAgeFlag,
+	MaxBlobsPerBatchFlag,
+	UseBlobHeaderHashV2Flag,
+	ShutdownTimeoutFlag,
 }
 
 // Flags contains the list of configuration options available to the binary.
@@ -165,6 +395,7 @@ func init() {
 	Flags = append(RequiredFlags, OptionalFlags...)
 	Flags = append(Flags, geth.EthClientFlags(EnvVarPrefix)...)
 	Flags = append(Flags, logging.CLIFlags(EnvVarPrefix, FlagPrefix)...)
+	Flags = append(Flags, tracing.CLIFlags(EnvVarPrefix, FlagPrefix, "zgda-batcher")...)
 	Flags = append(Flags, aws.ClientFlags(EnvVarPrefix, FlagPrefix)...)
 	Flags = append(Flags, storage_node.ClientFlags(EnvVarPrefix, FlagPrefix)...)
 }
@@ -5,11 +5,17 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/0glabs/0g-data-avail/common"
 	"github.com/0glabs/0g-data-avail/common/aws/dynamodb"
 	"github.com/0glabs/0g-data-avail/common/aws/s3"
 	"github.com/0glabs/0g-data-avail/common/geth"
 	"github.com/0glabs/0g-data-avail/common/logging"
+	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
+	"github.com/0glabs/0g-data-avail/core"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/0glabs/0g-data-avail/disperser/batcher"
 	"github.com/0glabs/0g-data-avail/disperser/batcher/dispatcher"
@@ -41,18 +47,29 @@ func main() {
 	if err != nil {
 		log.Fatalf("application failed: %v", err)
 	}
-
-	select {}
 }
 
 func RunBatcher(ctx *cli.Context) error {
-	config := NewConfig(ctx)
+	config, err := NewConfig(ctx)
+	if err != nil {
+		return err
+	}
 
 	logger, err := logging.GetLogger(config.LoggerConfig)
 	if err != nil {
 		return err
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), config.TracingConfig, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("failed to flush the traces", "err", err)
+		}
+	}()
+
 	// transactor
 	transactor := transactor.NewTransactor(logger)
 	// dispatcher
@@ -81,7 +98,11 @@ func RunBatcher(ctx *cli.Context) error {
 	var queue disperser.BlobStore
 
 	bucketName := config.BlobstoreConfig.BucketName
-	s3Client, err := s3.NewClient(config.AwsClientConfig, logger)
+	s3Client, err := s3.NewClient(config.AwsClientConfig, logger, s3.WithMultipartUpload(s3.MultipartConfig{
+		ThresholdBytes:     config.BlobstoreConfig.MultipartThresholdBytes,
+		PartSize:           config.BlobstoreConfig.PartSize,
+		MaxConcurrentParts: config.BlobstoreConfig.MaxConcurrentParts,
+	}))
 	if err != nil {
 		return err
 	}
@@ -91,16 +112,44 @@ func RunBatcher(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	blobMetadataStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, config.BlobstoreConfig.TableName, 0)
-	queue = blobstore.NewSharedStorage(bucketName, s3Client, config.BlobstoreConfig.MetadataHashAsBlobKey, blobMetadataStore, logger)
-
 	metrics := batcher.NewMetrics(config.MetricsConfig.HTTPPort, logger)
 
-	// encoder
-	if len(config.BatcherConfig.EncoderSocket) == 0 {
-		return fmt.Errorf("encoder socket must be specified")
+	blobMetadataStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, config.BlobstoreConfig.TableName, 0)
+	var metadataStore blobstore.MetadataStore = blobMetadataStore
+	if config.BlobstoreConfig.FlushInterval > 0 {
+		bufferedStore := blobstore.NewBufferedBlobMetadataStore(blobMetadataStore, config.BlobstoreConfig.MaxBatchSize, config.BlobstoreConfig.FlushInterval)
+		bufferedStore.Start(context.Background())
+		metadataStore = bufferedStore
+	}
+	var batchHeaderStore *blobstore.BatchHeaderStore
+	if config.BlobstoreConfig.BatchHeaderTableName != "" {
+		batchHeaderStore, err = blobstore.NewBatchHeaderStore(dynamoClient, logger, config.BlobstoreConfig.BatchHeaderTableName)
+		if err != nil {
+			return err
+		}
+	}
+	storageOpts := []blobstore.SharedStorageOption{blobstore.WithStatusResultWarnThreshold(config.BlobstoreConfig.StatusResultWarnThreshold)}
+	if config.BlobstoreConfig.CacheDir != "" {
+		diskCache, err := blobstore.NewDiskCache(config.BlobstoreConfig.CacheDir)
+		if err != nil {
+			return err
+		}
+		storageOpts = append(storageOpts, blobstore.WithDiskCache(diskCache, config.BlobstoreConfig.PrefetchConcurrency))
 	}
-	encoderClient, err := encoder.NewEncoderClient(config.BatcherConfig.EncoderSocket, config.TimeoutConfig.EncodingTimeout)
+	if config.BlobstoreConfig.VerifyContentHash {
+		storageOpts = append(storageOpts, blobstore.WithContentHashVerification())
+	}
+	if config.BlobstoreConfig.MaxRetries > 0 {
+		storageOpts = append(storageOpts, blobstore.WithRetry(config.BlobstoreConfig.MaxRetries, config.BlobstoreConfig.InitialBackoff))
+	}
+	sharedStorage := blobstore.NewSharedStorage(bucketName, s3Client, config.BlobstoreConfig.MetadataHashAsBlobKey, config.BlobstoreConfig.QuorumRetentionDays, metadataStore, batchHeaderStore, config.BlobstoreConfig.MaxConcurrentUploads, config.BlobstoreConfig.ShadowBucketName, blobstore.NewMetrics(metrics.Registry(), "zgda_batcher"), logger, storageOpts...)
+	if config.BlobstoreConfig.ExpiryCleanupInterval > 0 {
+		sharedStorage.StartExpiryCleanup(context.Background(), config.BlobstoreConfig.ExpiryCleanupInterval)
+	}
+	queue = sharedStorage
+
+	// encoder
+	encoderClient, err := newEncoderClient(config.BatcherConfig, config.TimeoutConfig, metrics, logger)
 	if err != nil {
 		return err
 	}
@@ -110,9 +159,21 @@ func RunBatcher(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	confirmer.SerializationFormat, err = core.ParseSerializationFormat(config.BlobstoreConfig.SerializationFormat)
+	if err != nil {
+		return err
+	}
+	if config.BatcherConfig.MinStorageReceipts > 0 {
+		kvClient, err := storage_node.NewKVClient(config.StorageNodeConfig, storage_node.NewPoolMetrics(metrics.Registry(), "zgda_batcher"), logger)
+		if err != nil {
+			return err
+		}
+		receiptStore := batcher.NewKVStorageReceiptStore(kvClient, config.StorageNodeConfig.KVStreamId)
+		confirmer.ReceiptCollector = batcher.NewReceiptCollector(queue, receiptStore, config.BatcherConfig.MinStorageReceipts, logger)
+	}
 
 	//finalizer
-	finalizer := batcher.NewFinalizer(config.TimeoutConfig.ChainReadTimeout, config.BatcherConfig.FinalizerInterval, queue, client, rpcClient, config.BatcherConfig.MaxNumRetriesPerBlob, logger)
+	finalizer := batcher.NewFinalizer(config.TimeoutConfig.ChainReadTimeout, config.BatcherConfig.FinalizerInterval, queue, client, rpcClient, config.BatcherConfig.MaxNumRetriesPerBlob, config.BatcherConfig.FinalizerBatchSize, config.BatcherConfig.FinalizationDepth, metrics, logger)
 
 	//batcher
 	batcher, err := batcher.NewBatcher(config.BatcherConfig, config.TimeoutConfig, queue, dispatcher, encoderClient, finalizer, confirmer, logger, metrics)
@@ -127,11 +188,33 @@ func RunBatcher(ctx *cli.Context) error {
 		logger.Info("Enabled metrics for Batcher", "socket", httpSocket)
 	}
 
-	err = batcher.Start(context.Background())
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	err = batcher.Start(shutdownCtx)
 	if err != nil {
 		return err
 	}
 
+	<-shutdownCtx.Done()
+	batcher.Shutdown()
 	return nil
+}
 
+// newEncoderClient creates a pool of encoder clients if multiple encoders are configured, otherwise a single encoder client
+func newEncoderClient(config batcher.Config, timeoutConfig batcher.TimeoutConfig, metrics *batcher.Metrics, logger common.Logger) (disperser.EncoderClient, error) {
+	if len(config.EncoderSockets) == 0 {
+		if len(config.EncoderSocket) == 0 {
+			return nil, fmt.Errorf("encoder socket must be specified")
+		}
+		return encoder.NewEncoderClient(config.EncoderSocket, timeoutConfig.EncodingTimeout)
+	}
+
+	pool, err := batcher.NewEncoderPool(config.EncoderPool, config.EncoderSockets, func(addr string) (disperser.EncoderClient, error) {
+		return encoder.NewEncoderClient(addr, timeoutConfig.EncodingTimeout)
+	}, encoder.ProbeEncoder, metrics.EncoderPoolMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
+	pool.Start(context.Background())
+	return pool, nil
 }
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"time"
+
 	"github.com/0glabs/0g-data-avail/common/aws"
 	"github.com/0glabs/0g-data-avail/common/geth"
 	"github.com/0glabs/0g-data-avail/common/logging"
 	"github.com/0glabs/0g-data-avail/common/ratelimit"
 	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/0glabs/0g-data-avail/disperser/apiserver"
 	"github.com/0glabs/0g-data-avail/disperser/batcher"
@@ -22,6 +25,7 @@ type Config struct {
 	BlobstoreConfig   blobstore.Config
 	ServerConfig      disperser.ServerConfig
 	LoggerConfig      logging.Config
+	TracingConfig     tracing.TracingConfig
 	MetricsConfig     disperser.MetricsConfig
 	RatelimiterConfig ratelimit.Config
 	RateConfig        apiserver.RateConfig
@@ -41,27 +45,116 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 	if err != nil {
 		return Config{}, err
 	}
+	ratelimiterConfig.Blocklist = ratelimit.ParseBlocklist(ctx.GlobalString(server_flags.RatelimitBlocklist.Name))
 
 	rateConfig, err := apiserver.ReadCLIConfig(ctx)
 	if err != nil {
 		return Config{}, err
 	}
+	rateConfig.CIDRRates, err = apiserver.ParseCIDRRates(ctx.GlobalStringSlice(server_flags.CIDRRates.Name))
+	if err != nil {
+		return Config{}, err
+	}
+
+	quorumRetentionDays, err := blobstore.ParseQuorumRetentionDays(ctx.GlobalStringSlice(server_flags.QuorumRetentionDays.Name))
+	if err != nil {
+		return Config{}, err
+	}
+
+	methodTimeouts, err := apiserver.ParseMethodTimeouts(ctx.GlobalStringSlice(server_flags.GrpcMethodTimeouts.Name))
+	if err != nil {
+		return Config{}, err
+	}
 
 	config := Config{
 		// api server
 		AwsClientConfig: aws.ReadClientConfig(ctx, flags.FlagPrefix),
 		ServerConfig: disperser.ServerConfig{
-			GrpcPort: ctx.GlobalString(server_flags.GrpcPortFlag.Name),
+			GrpcPort:                       ctx.GlobalString(server_flags.GrpcPortFlag.Name),
+			HttpPort:                       ctx.GlobalString(server_flags.HttpPortFlag.Name),
+			AdmissionBackpressureThreshold: ctx.GlobalFloat64(server_flags.AdmissionBackpressureThreshold.Name),
+			SkipSchemaValidation:           ctx.GlobalBool(server_flags.SkipSchemaValidation.Name),
+			EnableOnchainFallback:          ctx.GlobalBool(server_flags.EnableOnchainFallback.Name),
+			OnchainFallbackContract:        ctx.GlobalString(server_flags.OnchainFallbackContract.Name),
+			AttestationKeyFile:             ctx.GlobalString(server_flags.AttestationKeyFile.Name),
+			LoadShedHeapPct:                ctx.GlobalFloat64(server_flags.LoadShedHeapPct.Name),
+			LoadShedGCPause:                time.Duration(ctx.GlobalUint(server_flags.LoadShedGCMs.Name)) * time.Millisecond,
+			AdminToken:                     ctx.GlobalString(server_flags.AdminTokenFlag.Name),
+			AllowPrefixScan:                ctx.GlobalBool(server_flags.AllowPrefixScan.Name),
+			MaxConcurrentStreams:           uint32(ctx.GlobalUint(server_flags.GrpcMaxConcurrentStreams.Name)),
+			DedupWindow:                    ctx.GlobalDuration(server_flags.DedupWindow.Name),
+			IdempotencyWindowDuration:      ctx.GlobalDuration(server_flags.IdempotencyWindow.Name),
+			BlobSizePolicyURL:              ctx.GlobalString(server_flags.BlobSizePolicyURL.Name),
+			MaxStreamBufferSize:            int(ctx.GlobalUint(server_flags.MaxStreamBufferSize.Name)),
+			WatchTimeout:                   ctx.GlobalDuration(server_flags.WatchTimeout.Name),
+			DeleteOnCancel:                 ctx.GlobalBool(server_flags.DeleteOnCancel.Name),
+			MaxTTLExtension:                ctx.GlobalDuration(server_flags.MaxTTLExtension.Name),
+			RateLimitStatusRate:            ctx.GlobalFloat64(server_flags.RateLimitStatusRate.Name),
+			AdminPort:                      ctx.GlobalString(server_flags.AdminPortFlag.Name),
+			RateConfigFile:                 ctx.GlobalString(server_flags.RateConfigFile.Name),
+			AuditLogPath:                   ctx.GlobalString(server_flags.AuditLogPath.Name),
+			APIKeyFile:                     ctx.GlobalString(server_flags.APIKeyFile.Name),
+			EnableBlobCallbacks:            ctx.GlobalBool(server_flags.EnableBlobCallbacks.Name),
+			CallbackMaxRetries:             ctx.GlobalInt(server_flags.CallbackMaxRetries.Name),
+			CallbackURLAllowlist:           ctx.GlobalStringSlice(server_flags.CallbackURLAllowlist.Name),
+			ShutdownTimeout:                ctx.GlobalDuration(server_flags.ShutdownTimeout.Name),
+			MaxConnectionsPerIP:            ctx.GlobalInt(server_flags.MaxConnectionsPerIP.Name),
+			MinBlobSize:                    ctx.GlobalInt(server_flags.MinBlobSize.Name),
+			FinalizationDepth:              uint32(ctx.GlobalUint(server_flags.FinalizationDepth.Name)),
+			FallbackRPCEndpoints:           apiserver.ParseRPCEndpoints(ctx.GlobalString(server_flags.RPCFallbackEndpoints.Name)),
+			DefaultRequestTimeout:          ctx.GlobalDuration(server_flags.GrpcDefaultRequestTimeout.Name),
+			PerMethodTimeouts:              methodTimeouts,
+			MaxRecvMsgSizeMiB:              ctx.GlobalInt(server_flags.GrpcMaxRecvMsgSizeMiB.Name),
+			MaxSendMsgSizeMiB:              ctx.GlobalInt(server_flags.GrpcMaxSendMsgSizeMiB.Name),
+			TLSConfig: disperser.TLSConfig{
+				CertFile:          ctx.GlobalString(server_flags.TLSCertFile.Name),
+				KeyFile:           ctx.GlobalString(server_flags.TLSKeyFile.Name),
+				ClientCAFile:      ctx.GlobalString(server_flags.TLSClientCAFile.Name),
+				RequireClientCert: ctx.GlobalBool(server_flags.TLSRequireClientCert.Name),
+			},
+			KeepaliveConfig: disperser.KeepaliveConfig{
+				ServerKeepaliveTime:    ctx.GlobalDuration(server_flags.GrpcKeepaliveTime.Name),
+				ServerKeepaliveTimeout: ctx.GlobalDuration(server_flags.GrpcKeepaliveTimeout.Name),
+				MaxConnectionIdle:      ctx.GlobalDuration(server_flags.GrpcMaxConnectionIdle.Name),
+				MaxConnectionAge:       ctx.GlobalDuration(server_flags.GrpcMaxConnectionAge.Name),
+				MaxConnectionAgeGrace:  ctx.GlobalDuration(server_flags.GrpcMaxConnectionAgeGrace.Name),
+				MinClientKeepaliveTime: ctx.GlobalDuration(server_flags.GrpcKeepaliveMinClientTime.Name),
+			},
 		},
 		EthClientConfig: geth.ReadEthClientConfig(ctx),
 		BlobstoreConfig: blobstore.Config{
-			BucketName:            ctx.GlobalString(server_flags.S3BucketNameFlag.Name),
-			TableName:             ctx.GlobalString(server_flags.DynamoDBTableNameFlag.Name),
-			MetadataHashAsBlobKey: ctx.GlobalBool(server_flags.MetadataHashAsBlobKey.Name),
-			InMemory:              ctx.GlobalBool(flags.UseMemoryDB.Name),
-			MemoryDBSize:          uint64(ctx.GlobalUint(flags.MemoryDBSizeLimit.Name)) * 1024 * 1024,
+			BucketName:                ctx.GlobalString(server_flags.S3BucketNameFlag.Name),
+			TableName:                 ctx.GlobalString(server_flags.DynamoDBTableNameFlag.Name),
+			BatchHeaderTableName:      ctx.GlobalString(server_flags.BatchHeaderTableNameFlag.Name),
+			ShadowBucketName:          ctx.GlobalString(server_flags.ShadowStoreBucketFlag.Name),
+			MetadataHashAsBlobKey:     ctx.GlobalBool(server_flags.MetadataHashAsBlobKey.Name),
+			QuorumRetentionDays:       quorumRetentionDays,
+			MaxConcurrentUploads:      ctx.GlobalInt(batcher_flags.S3MaxConcurrentUploadsFlag.Name),
+			CacheDir:                  ctx.GlobalString(batcher_flags.BlobCacheDirFlag.Name),
+			PrefetchConcurrency:       ctx.GlobalInt(batcher_flags.PrefetchConcurrencyFlag.Name),
+			StatusResultWarnThreshold: ctx.GlobalInt(batcher_flags.StatusResultWarnThresholdFlag.Name),
+			VerifyContentHash:         ctx.GlobalBool(batcher_flags.VerifyContentHash.Name),
+			ExpiryCleanupInterval:     ctx.GlobalDuration(batcher_flags.ExpiryCleanupIntervalFlag.Name),
+			MaxBatchSize:              ctx.GlobalInt(batcher_flags.MetadataMaxBatchSizeFlag.Name),
+			FlushInterval:             ctx.GlobalDuration(batcher_flags.MetadataFlushIntervalFlag.Name),
+			DeduplicateBlobs:          ctx.GlobalBool(server_flags.DeduplicateBlobs.Name),
+			CompressBlobs:             ctx.GlobalBool(server_flags.CompressBlobs.Name),
+			MetadataCacheSize:         ctx.GlobalInt(server_flags.MetadataCacheSize.Name),
+			TimeRangeQueryLimit:       ctx.GlobalInt(server_flags.TimeRangeQueryLimit.Name),
+			BackfillUploadIndex:       ctx.GlobalBool(server_flags.BackfillUploadIndex.Name),
+			MultipartThresholdBytes:   ctx.GlobalInt64(batcher_flags.MultipartThresholdFlag.Name),
+			PartSize:                  ctx.GlobalInt64(batcher_flags.MultipartPartSizeFlag.Name),
+			MaxConcurrentParts:        ctx.GlobalInt(batcher_flags.MultipartMaxConcurrentPartsFlag.Name),
+			MaxRetries:                ctx.GlobalInt(batcher_flags.StorageMaxRetriesFlag.Name),
+			InitialBackoff:            ctx.GlobalDuration(batcher_flags.StorageInitialBackoffFlag.Name),
+			SerializationFormat:       ctx.GlobalString(batcher_flags.SerializationFormatFlag.Name),
+			InMemory:                  ctx.GlobalBool(flags.UseMemoryDB.Name),
+			MemoryDBSize:              uint64(ctx.GlobalUint(flags.MemoryDBSizeLimit.Name)) * 1024 * 1024,
+			Local:                     ctx.GlobalBool(flags.UseLocalDB.Name),
+			DataDir:                   ctx.GlobalString(flags.LocalDataDir.Name),
 		},
-		LoggerConfig: logging.ReadCLIConfig(ctx, flags.FlagPrefix),
+		LoggerConfig:  logging.ReadCLIConfig(ctx, flags.FlagPrefix),
+		TracingConfig: tracing.ReadCLIConfig(ctx, flags.FlagPrefix),
 		MetricsConfig: disperser.MetricsConfig{
 			HTTPPort:      ctx.GlobalString(flags.MetricsHTTPPort.Name),
 			EnableMetrics: ctx.GlobalBool(flags.EnableMetrics.Name),
@@ -77,11 +170,27 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 			PullInterval:             ctx.GlobalDuration(batcher_flags.PullIntervalFlag.Name),
 			FinalizerInterval:        ctx.GlobalDuration(batcher_flags.FinalizerIntervalFlag.Name),
 			EncoderSocket:            ctx.GlobalString(batcher_flags.EncoderSocket.Name),
+			EncoderSockets:           batcher.ParseEncoderAddresses(ctx.GlobalString(batcher_flags.EncoderSocketsFlag.Name)),
+			EncoderPublicKey:         ctx.GlobalString(batcher_flags.EncoderPublicKeyFlag.Name),
 			NumConnections:           ctx.GlobalInt(batcher_flags.NumConnectionsFlag.Name),
 			EncodingRequestQueueSize: ctx.GlobalInt(batcher_flags.EncodingRequestQueueSizeFlag.Name),
 			BatchSizeMBLimit:         ctx.GlobalUint(batcher_flags.BatchSizeLimitFlag.Name),
 			MaxNumRetriesPerBlob:     ctx.GlobalUint(batcher_flags.MaxNumRetriesPerBlobFlag.Name),
 			ConfirmerNum:             ctx.GlobalUint(batcher_flags.ConfirmerNumFlag.Name),
+			MinStorageReceipts:       ctx.GlobalUint(batcher_flags.MinStorageReceiptsFlag.Name),
+			FinalizerBatchSize:       ctx.GlobalInt(batcher_flags.FinalizerBatchSizeFlag.Name),
+			FinalizationDepth:        uint32(ctx.GlobalUint(batcher_flags.FinalizationDepthFlag.Name)),
+			MaxBatchesInFlight:       ctx.GlobalUint(batcher_flags.MaxBatchesInFlightFlag.Name),
+			BatchFormationStrategy:   ctx.GlobalString(batcher_flags.BatchFormationStrategyFlag.Name),
+			MaxStarvationAge:         ctx.GlobalDuration(batcher_flags.MaxStarvationAgeFlag.Name),
+			MaxBlobsPerBatch:         ctx.GlobalInt(batcher_flags.MaxBlobsPerBatchFlag.Name),
+			UseBlobHeaderHashV2:      ctx.GlobalBool(batcher_flags.UseBlobHeaderHashV2Flag.Name),
+			ShutdownTimeout:          ctx.GlobalDuration(batcher_flags.ShutdownTimeoutFlag.Name),
+			EncoderPool: batcher.EncoderPoolConfig{
+				Strategy:               batcher.LoadBalanceStrategy(ctx.GlobalString(batcher_flags.EncoderLoadBalanceStrategyFlag.Name)),
+				MaxConsecutiveFailures: ctx.GlobalInt(batcher_flags.EncoderMaxConsecutiveFailuresFlag.Name),
+				ProbeInterval:          ctx.GlobalDuration(batcher_flags.EncoderProbeIntervalFlag.Name),
+			},
 		},
 		TimeoutConfig: batcher.TimeoutConfig{
 			EncodingTimeout:   ctx.GlobalDuration(batcher_flags.EncodingTimeoutFlag.Name),
@@ -7,6 +7,7 @@ import (
 	"github.com/0glabs/0g-data-avail/common/logging"
 	"github.com/0glabs/0g-data-avail/common/ratelimit"
 	"github.com/0glabs/0g-data-avail/common/storage_node"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	server_flags "github.com/0glabs/0g-data-avail/disperser/cmd/apiserver/flags"
 	batcher_flags "github.com/0glabs/0g-data-avail/disperser/cmd/batcher/flags"
 	"github.com/urfave/cli"
@@ -44,6 +45,19 @@ var (
 		Value:    2048, // 2G
 		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "MEMORY_DB_SIZE_LIMIT"),
 	}
+	UseLocalDB = cli.BoolFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "use-local-db"),
+		Usage:    "store the blobs on the local filesystem instead of S3 and DynamoDB, for development",
+		Required: false,
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "USE_LOCAL_DB"),
+	}
+	LocalDataDir = cli.StringFlag{
+		Name:     common.PrefixFlag(FlagPrefix, "local-data-dir"),
+		Usage:    "the directory of the blobs and their metadata when the local db is used",
+		Required: false,
+		Value:    "./data",
+		EnvVar:   common.PrefixEnvVar(EnvVarPrefix, "LOCAL_DATA_DIR"),
+	}
 )
 
 var RequiredFlags = []cli.Flag{}
@@ -53,6 +67,8 @@ var OptionalFlags = []cli.Flag{
 	EnableMetrics,
 	UseMemoryDB,
 	MemoryDBSizeLimit,
+	UseLocalDB,
+	LocalDataDir,
 }
 
 // Flags contains the list of configuration options available to the binary.
@@ -62,6 +78,7 @@ func init() {
 	// combined
 	Flags = append(RequiredFlags, OptionalFlags...)
 	Flags = append(Flags, logging.CLIFlags(EnvVarPrefix, FlagPrefix)...)
+	Flags = append(Flags, tracing.CLIFlags(EnvVarPrefix, FlagPrefix, "zgda-combined")...)
 	Flags = append(Flags, geth.EthClientFlags(EnvVarPrefix)...)
 	Flags = append(Flags, aws.ClientFlags(EnvVarPrefix, FlagPrefix)...)
 	Flags = append(Flags, storage_node.ClientFlags(EnvVarPrefix, FlagPrefix)...)
@@ -69,6 +86,8 @@ func init() {
 	// api server
 	Flags = append(Flags, server_flags.RequiredFlags...)
 	Flags = append(Flags, server_flags.OptionalFlags...)
+	// the encoding queue of the batcher is only available to the api server in the combined server
+	Flags = append(Flags, server_flags.AdmissionBackpressureThreshold)
 	Flags = append(Flags, ratelimit.RatelimiterCLIFlags(server_flags.EnvVarPrefix, server_flags.FlagPrefix)...)
 
 	// batcher
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/common/audit"
 	"github.com/0glabs/0g-data-avail/disperser/apiserver"
 	"github.com/0glabs/0g-data-avail/disperser/batcher"
 	"github.com/0glabs/0g-data-avail/disperser/batcher/dispatcher"
@@ -14,8 +15,9 @@ import (
 	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
 	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
 	"github.com/0glabs/0g-data-avail/disperser/encoder"
-	"github.com/0glabs/0g-storage-client/kv"
-	"github.com/0glabs/0g-storage-client/node"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/0glabs/0g-data-avail/common/aws/dynamodb"
@@ -23,7 +25,10 @@ import (
 	"github.com/0glabs/0g-data-avail/common/geth"
 	"github.com/0glabs/0g-data-avail/common/logging"
 	"github.com/0glabs/0g-data-avail/common/ratelimit"
+	"github.com/0glabs/0g-data-avail/common/storage_node"
 	"github.com/0glabs/0g-data-avail/common/store"
+	"github.com/0glabs/0g-data-avail/common/tracing"
+	"github.com/0glabs/0g-data-avail/core"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/0glabs/0g-data-avail/disperser/cmd/combined_server/flags"
 	"github.com/urfave/cli"
@@ -53,18 +58,21 @@ func main() {
 	select {}
 }
 
-func RunDisperserServer(config Config, blobStore disperser.BlobStore, logger common.Logger) error {
+func RunDisperserServer(config Config, blobStore disperser.BlobStore, encodingQueue disperser.EncodingQueue, statusHub *disperser.BlobStatusHub, logger common.Logger) error {
+	metrics := disperser.NewMetrics(config.MetricsConfig.HTTPPort, logger)
+
 	var ratelimiter common.RateLimiter
 	if config.EnableRatelimiter {
-		globalParams := config.RatelimiterConfig.GlobalRateParams
-
 		var bucketStore common.KVStore[common.RateBucketParams]
 		if config.BucketTableName != "" {
 			dynamoClient, err := dynamodb.NewClient(config.AwsClientConfig, logger)
 			if err != nil {
 				return err
 			}
-			bucketStore = store.NewDynamoParamStore[common.RateBucketParams](dynamoClient, config.BucketTableName)
+			bucketStore, err = ratelimit.NewDynamoDBBucketStore(dynamoClient, config.BucketTableName)
+			if err != nil {
+				return err
+			}
 		} else {
 			var err error
 			bucketStore, err = store.NewLocalParamStore[common.RateBucketParams](config.BucketStoreSize)
@@ -72,23 +80,95 @@ func RunDisperserServer(config Config, blobStore disperser.BlobStore, logger com
 				return err
 			}
 		}
-		ratelimiter = ratelimit.NewRateLimiter(globalParams, bucketStore, config.RatelimiterConfig.Allowlist, logger)
+		ratelimiter = ratelimit.NewRateLimiterFromConfig(config.RatelimiterConfig, bucketStore, ratelimit.NewMetrics(metrics.Registry(), "zgda_disperser"), logger)
 	}
 
-	metrics := disperser.NewMetrics(config.MetricsConfig.HTTPPort, logger)
-
-	var kvClient *kv.Client
+	var kvClient storage_node.KVClient
 	var rpcClient *rpc.Client
+	var fallbackRPCClients []*rpc.Client
 
 	if config.BlobstoreConfig.MetadataHashAsBlobKey {
-		kvClient = kv.NewClient(node.MustNewClient(config.StorageNodeConfig.KVNodeURL), nil)
 		var err error
+		kvClient, err = storage_node.NewKVClient(config.StorageNodeConfig, storage_node.NewPoolMetrics(metrics.Registry(), "zgda_disperser"), logger)
+		if err != nil {
+			return err
+		}
 		rpcClient, err = rpc.Dial(config.EthClientConfig.RPCURL)
 		if err != nil {
 			return err
 		}
+		for _, endpoint := range config.ServerConfig.FallbackRPCEndpoints {
+			client, err := rpc.Dial(endpoint)
+			if err != nil {
+				return fmt.Errorf("failed to dial the fallback rpc endpoint %s: %w", endpoint, err)
+			}
+			fallbackRPCClients = append(fallbackRPCClients, client)
+		}
+	}
+	opts := []apiserver.ServerOption{apiserver.WithBlobStatusHub(statusHub)}
+	if len(fallbackRPCClients) > 0 {
+		opts = append(opts, apiserver.WithFallbackRPCClients(fallbackRPCClients...))
+	}
+	if !config.BlobstoreConfig.InMemory && !config.BlobstoreConfig.Local {
+		dynamoClient, err := dynamodb.NewClient(config.AwsClientConfig, logger)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, apiserver.WithReadinessCheck(func(ctx context.Context) error {
+			return blobstore.ValidateTableSchema(ctx, dynamoClient, config.BlobstoreConfig.TableName)
+		}))
 	}
-	server := apiserver.NewDispersalServer(config.ServerConfig, blobStore, logger, metrics, ratelimiter, config.RateConfig, config.BlobstoreConfig.MetadataHashAsBlobKey, kvClient, config.StorageNodeConfig.KVStreamId, rpcClient)
+	if config.ServerConfig.EnableOnchainFallback {
+		if !eth_common.IsHexAddress(config.ServerConfig.OnchainFallbackContract) {
+			return fmt.Errorf("invalid on-chain fallback contract address: %q", config.ServerConfig.OnchainFallbackContract)
+		}
+		if rpcClient == nil {
+			var err error
+			rpcClient, err = rpc.Dial(config.EthClientConfig.RPCURL)
+			if err != nil {
+				return err
+			}
+		}
+		reader, err := apiserver.NewBlobConfirmationReader(ethclient.NewClient(rpcClient), eth_common.HexToAddress(config.ServerConfig.OnchainFallbackContract))
+		if err != nil {
+			return err
+		}
+		opts = append(opts, apiserver.WithOnchainFallback(reader))
+	}
+	if config.ServerConfig.AttestationKeyFile != "" {
+		attestationKey, err := crypto.LoadECDSA(config.ServerConfig.AttestationKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load attestation key: %w", err)
+		}
+		opts = append(opts, apiserver.WithAttestationKey(attestationKey))
+	}
+	if config.ServerConfig.LoadShedHeapPct > 0 || config.ServerConfig.LoadShedGCPause > 0 {
+		shedder := apiserver.NewLoadShedder(apiserver.NewRuntimeLoadSampler(), config.ServerConfig.LoadShedHeapPct, config.ServerConfig.LoadShedGCPause, logger)
+		opts = append(opts, apiserver.WithLoadShedder(shedder))
+	}
+	if config.ServerConfig.BlobSizePolicyURL != "" {
+		opts = append(opts, apiserver.WithBlobSizeValidator(apiserver.NewOPABlobSizeValidator(config.ServerConfig.BlobSizePolicyURL)))
+	}
+	if config.ServerConfig.EnableBlobCallbacks {
+		notifier := apiserver.NewBlobCallbackNotifier(config.ServerConfig.CallbackURLAllowlist, config.ServerConfig.CallbackMaxRetries, apiserver.DefaultCallbackInitialBackoff)
+		opts = append(opts, apiserver.WithBlobCallbacks(notifier))
+	}
+	if config.ServerConfig.AuditLogPath != "" {
+		auditLogger := audit.NewFileAuditLogger(config.ServerConfig.AuditLogPath, logger)
+		defer auditLogger.Close()
+		opts = append(opts, apiserver.WithAuditLogger(auditLogger))
+	}
+	if config.ServerConfig.APIKeyFile != "" {
+		apiKeyAuth, err := apiserver.LoadAPIKeyAuth(config.ServerConfig.APIKeyFile, logger)
+		if err != nil {
+			return err
+		}
+		apiKeyAuth.ReloadOnSignal(context.Background())
+		opts = append(opts, apiserver.WithAPIKeyAuth(apiKeyAuth))
+	}
+	server := apiserver.NewDispersalServer(config.ServerConfig, blobStore, logger, metrics, ratelimiter, config.RateConfig, config.BlobstoreConfig.MetadataHashAsBlobKey, kvClient, config.StorageNodeConfig.KVStreamId, rpcClient, opts...)
+	server.EncodingQueue = encodingQueue
+	metrics.Handle("/readyz", server.ReadyzHandler())
 
 	// Enable Metrics Block
 	if config.MetricsConfig.EnableMetrics {
@@ -97,10 +177,27 @@ func RunDisperserServer(config Config, blobStore disperser.BlobStore, logger com
 		logger.Info("Enabled metrics for Disperser", "socket", httpSocket)
 	}
 
+	if config.ServerConfig.HttpPort != "" {
+		gateway := apiserver.NewHTTPGatewayServer(server, config.ServerConfig.HttpPort)
+		go func() {
+			if err := gateway.Start(context.Background()); err != nil {
+				logger.Error("http gateway failed", "err", err)
+			}
+		}()
+	}
+	if config.ServerConfig.AdminPort != "" || config.ServerConfig.RateConfigFile != "" {
+		adminServer := apiserver.NewAdminServer(server, config.ServerConfig.AdminPort, config.ServerConfig.RateConfigFile)
+		go func() {
+			if err := adminServer.Start(context.Background()); err != nil {
+				logger.Error("admin server failed", "err", err)
+			}
+		}()
+	}
+
 	return server.Start(context.Background())
 }
 
-func RunBatcher(config Config, queue disperser.BlobStore, logger common.Logger) error {
+func NewBatcher(config Config, queue disperser.BlobStore, metrics *batcher.Metrics, logger common.Logger) (*batcher.Batcher, error) {
 	// transactor
 	transactor := transactor.NewTransactor(logger)
 	// dispatcher
@@ -110,45 +207,52 @@ func RunBatcher(config Config, queue disperser.BlobStore, logger common.Logger)
 		StorageNodeConfig: config.StorageNodeConfig,
 	}, transactor, logger)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// eth clients
 	client, err := geth.NewClient(config.EthClientConfig, logger)
 	if err != nil {
 		logger.Error("Cannot create chain.Client", "err", err)
-		return err
+		return nil, err
 	}
 
 	rpcClient, err := rpc.Dial(config.EthClientConfig.RPCURL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	metrics := batcher.NewMetrics(config.MetricsConfig.HTTPPort, logger)
-
 	// encoder
-	if len(config.BatcherConfig.EncoderSocket) == 0 {
-		return fmt.Errorf("encoder socket must be specified")
-	}
-	encoderClient, err := encoder.NewEncoderClient(config.BatcherConfig.EncoderSocket, config.TimeoutConfig.EncodingTimeout)
+	encoderClient, err := newEncoderClient(config.BatcherConfig, config.TimeoutConfig, metrics, logger)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// confirmer
 	confirmer, err := batcher.NewConfirmer(config.EthClientConfig, config.StorageNodeConfig, queue, config.BatcherConfig.MaxNumRetriesPerBlob, config.BatcherConfig.ConfirmerNum, transactor, logger, metrics)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	confirmer.SerializationFormat, err = core.ParseSerializationFormat(config.BlobstoreConfig.SerializationFormat)
+	if err != nil {
+		return nil, err
+	}
+	if config.BatcherConfig.MinStorageReceipts > 0 {
+		kvClient, err := storage_node.NewKVClient(config.StorageNodeConfig, storage_node.NewPoolMetrics(metrics.Registry(), "zgda_batcher"), logger)
+		if err != nil {
+			return nil, err
+		}
+		receiptStore := batcher.NewKVStorageReceiptStore(kvClient, config.StorageNodeConfig.KVStreamId)
+		confirmer.ReceiptCollector = batcher.NewReceiptCollector(queue, receiptStore, config.BatcherConfig.MinStorageReceipts, logger)
 	}
 
 	//finalizer
-	finalizer := batcher.NewFinalizer(config.TimeoutConfig.ChainReadTimeout, config.BatcherConfig.FinalizerInterval, queue, client, rpcClient, config.BatcherConfig.MaxNumRetriesPerBlob, logger)
+	finalizer := batcher.NewFinalizer(config.TimeoutConfig.ChainReadTimeout, config.BatcherConfig.FinalizerInterval, queue, client, rpcClient, config.BatcherConfig.MaxNumRetriesPerBlob, config.BatcherConfig.FinalizerBatchSize, config.BatcherConfig.FinalizationDepth, metrics, logger)
 
 	//batcher
 	batcher, err := batcher.NewBatcher(config.BatcherConfig, config.TimeoutConfig, queue, dispatcher, encoderClient, finalizer, confirmer, logger, metrics)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Enable Metrics Block
@@ -158,7 +262,7 @@ func RunBatcher(config Config, queue disperser.BlobStore, logger common.Logger)
 		logger.Info("Enabled metrics for Batcher", "socket", httpSocket)
 	}
 
-	return batcher.Start(context.Background())
+	return batcher, nil
 }
 
 func RunCombinedServer(ctx *cli.Context) error {
@@ -172,10 +276,42 @@ func RunCombinedServer(ctx *cli.Context) error {
 		return err
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), config.TracingConfig, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("failed to flush the traces", "err", err)
+		}
+	}()
+
+	batcherMetrics := batcher.NewMetrics(config.MetricsConfig.HTTPPort, logger)
+
 	var blobStore disperser.BlobStore
 
-	if !config.BlobstoreConfig.InMemory {
-		s3Client, err := s3.NewClient(config.AwsClientConfig, logger)
+	switch {
+	case config.BlobstoreConfig.InMemory:
+		config.BlobstoreConfig.MetadataHashAsBlobKey = true
+		blobStore = memorydb.NewBlobStore(config.BlobstoreConfig.MemoryDBSize, logger)
+	case config.BlobstoreConfig.Local:
+		logger.Info("Creating local blob store", "dataDir", config.BlobstoreConfig.DataDir)
+		serializationFormat, err := core.ParseSerializationFormat(config.BlobstoreConfig.SerializationFormat)
+		if err != nil {
+			return err
+		}
+		localStore, err := blobstore.NewLocalBlobStore(config.BlobstoreConfig.DataDir, config.BlobstoreConfig.MetadataHashAsBlobKey, config.BlobstoreConfig.QuorumRetentionDays, logger, blobstore.WithLocalSerializationFormat(serializationFormat))
+		if err != nil {
+			return err
+		}
+		defer localStore.Close()
+		blobStore = localStore
+	default:
+		s3Client, err := s3.NewClient(config.AwsClientConfig, logger, s3.WithMultipartUpload(s3.MultipartConfig{
+			ThresholdBytes:     config.BlobstoreConfig.MultipartThresholdBytes,
+			PartSize:           config.BlobstoreConfig.PartSize,
+			MaxConcurrentParts: config.BlobstoreConfig.MaxConcurrentParts,
+		}))
 		if err != nil {
 			return err
 		}
@@ -187,21 +323,95 @@ func RunCombinedServer(ctx *cli.Context) error {
 
 		bucketName := config.BlobstoreConfig.BucketName
 		logger.Info("Creating blob store", "bucket", bucketName)
-		blobMetadataStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, config.BlobstoreConfig.TableName, 0)
-		blobStore = blobstore.NewSharedStorage(bucketName, s3Client, config.BlobstoreConfig.MetadataHashAsBlobKey, blobMetadataStore, logger)
-	} else {
-		config.BlobstoreConfig.MetadataHashAsBlobKey = true
-		blobStore = memorydb.NewBlobStore(config.BlobstoreConfig.MemoryDBSize, logger)
+		blobstoreMetrics := blobstore.NewMetrics(batcherMetrics.Registry(), "zgda_batcher")
+		blobMetadataStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, config.BlobstoreConfig.TableName, 0, blobstore.WithMetadataCache(config.BlobstoreConfig.MetadataCacheSize, blobstoreMetrics),
+			blobstore.WithTimeRangeQueryLimit(config.BlobstoreConfig.TimeRangeQueryLimit))
+		if config.BlobstoreConfig.BackfillUploadIndex {
+			go func() {
+				logger.Info("Backfilling the upload index of the blob metadata")
+				updated, err := blobMetadataStore.BackfillRequestedAtDay(context.Background())
+				if err != nil {
+					logger.Error("Failed to backfill the upload index of the blob metadata", "updated", updated, "err", err)
+					return
+				}
+				logger.Info("Backfilled the upload index of the blob metadata", "updated", updated)
+			}()
+		}
+		var metadataStore blobstore.MetadataStore = blobMetadataStore
+		if config.BlobstoreConfig.FlushInterval > 0 {
+			bufferedStore := blobstore.NewBufferedBlobMetadataStore(blobMetadataStore, config.BlobstoreConfig.MaxBatchSize, config.BlobstoreConfig.FlushInterval)
+			bufferedStore.Start(context.Background())
+			metadataStore = bufferedStore
+		}
+		var batchHeaderStore *blobstore.BatchHeaderStore
+		if config.BlobstoreConfig.BatchHeaderTableName != "" {
+			batchHeaderStore, err = blobstore.NewBatchHeaderStore(dynamoClient, logger, config.BlobstoreConfig.BatchHeaderTableName)
+			if err != nil {
+				return err
+			}
+		}
+		storageOpts := []blobstore.SharedStorageOption{blobstore.WithStatusResultWarnThreshold(config.BlobstoreConfig.StatusResultWarnThreshold)}
+		if config.BlobstoreConfig.CacheDir != "" {
+			diskCache, err := blobstore.NewDiskCache(config.BlobstoreConfig.CacheDir)
+			if err != nil {
+				return err
+			}
+			storageOpts = append(storageOpts, blobstore.WithDiskCache(diskCache, config.BlobstoreConfig.PrefetchConcurrency))
+		}
+		if config.BlobstoreConfig.VerifyContentHash {
+			storageOpts = append(storageOpts, blobstore.WithContentHashVerification())
+		}
+		if config.BlobstoreConfig.DeduplicateBlobs {
+			storageOpts = append(storageOpts, blobstore.WithBlobDeduplication())
+		}
+		if config.BlobstoreConfig.CompressBlobs {
+			storageOpts = append(storageOpts, blobstore.WithCompression())
+		}
+		if config.BlobstoreConfig.MaxRetries > 0 {
+			storageOpts = append(storageOpts, blobstore.WithRetry(config.BlobstoreConfig.MaxRetries, config.BlobstoreConfig.InitialBackoff))
+		}
+		sharedStorage := blobstore.NewSharedStorage(bucketName, s3Client, config.BlobstoreConfig.MetadataHashAsBlobKey, config.BlobstoreConfig.QuorumRetentionDays, metadataStore, batchHeaderStore, config.BlobstoreConfig.MaxConcurrentUploads, config.BlobstoreConfig.ShadowBucketName, blobstoreMetrics, logger, storageOpts...)
+		if config.BlobstoreConfig.ExpiryCleanupInterval > 0 {
+			sharedStorage.StartExpiryCleanup(context.Background(), config.BlobstoreConfig.ExpiryCleanupInterval)
+		}
+		blobStore = sharedStorage
+	}
+	// the blob status changes made by the batcher are pushed to the blob status watches of the api server
+	statusHub := disperser.NewBlobStatusHub()
+	blobStore = disperser.NewNotifyingBlobStore(blobStore, statusHub)
+	batcher, err := NewBatcher(config, blobStore, batcherMetrics, logger)
+	if err != nil {
+		return err
 	}
+
 	errChan := make(chan error)
 	go func() {
-		err := RunDisperserServer(config, blobStore, logger)
+		err := RunDisperserServer(config, blobStore, batcher.EncodingStreamer, statusHub, logger)
 		errChan <- err
 	}()
 	go func() {
-		err := RunBatcher(config, blobStore, logger)
+		err := batcher.Start(context.Background())
 		errChan <- err
 	}()
 	err = <-errChan
 	return err
 }
+
+// newEncoderClient creates a pool of encoder clients if multiple encoders are configured, otherwise a single encoder client
+func newEncoderClient(config batcher.Config, timeoutConfig batcher.TimeoutConfig, metrics *batcher.Metrics, logger common.Logger) (disperser.EncoderClient, error) {
+	if len(config.EncoderSockets) == 0 {
+		if len(config.EncoderSocket) == 0 {
+			return nil, fmt.Errorf("encoder socket must be specified")
+		}
+		return encoder.NewEncoderClient(config.EncoderSocket, timeoutConfig.EncodingTimeout)
+	}
+
+	pool, err := batcher.NewEncoderPool(config.EncoderPool, config.EncoderSockets, func(addr string) (disperser.EncoderClient, error) {
+		return encoder.NewEncoderClient(addr, timeoutConfig.EncodingTimeout)
+	}, encoder.ProbeEncoder, metrics.EncoderPoolMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
+	pool.Start(context.Background())
+	return pool, nil
+}
@@ -0,0 +1,81 @@
+package blobstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+)
+
+// restoreBatchSize is the number of blob metadata restored in a single call to PutBlobMetadatas
+const restoreBatchSize = 100
+
+// MetadataStreamer streams the metadata of all the blobs, it is implemented by BlobMetadataStore
+type MetadataStreamer interface {
+	StreamAllMetadata(ctx context.Context, fn func(*disperser.BlobMetadata) error) error
+}
+
+// MetadataPutter stores blob metadata as is, it is implemented by BlobMetadataStore
+type MetadataPutter interface {
+	PutBlobMetadatas(ctx context.Context, blobMetadatas []*disperser.BlobMetadata) error
+}
+
+// BackupBlobMetadata writes the metadata of all the blobs to w as JSON Lines, one metadata per line.
+// It returns the number of metadata written.
+func BackupBlobMetadata(ctx context.Context, streamer MetadataStreamer, w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	count := 0
+	err := streamer.StreamAllMetadata(ctx, func(metadata *disperser.BlobMetadata) error {
+		if err := encoder.Encode(metadata); err != nil {
+			return fmt.Errorf("failed to encode metadata of blob %s: %w", metadata.GetBlobKey(), err)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, bw.Flush()
+}
+
+// RestoreBlobMetadata stores the metadata read from r, in the format written by BackupBlobMetadata.
+// The metadata already stored for the same blob keys are overwritten. It returns the number of metadata restored.
+func RestoreBlobMetadata(ctx context.Context, putter MetadataPutter, r io.Reader) (int, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	batch := make([]*disperser.BlobMetadata, 0, restoreBatchSize)
+	count := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := putter.PutBlobMetadatas(ctx, batch); err != nil {
+			return err
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		var metadata disperser.BlobMetadata
+		err := decoder.Decode(&metadata)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to decode metadata %d: %w", count+len(batch), err)
+		}
+		batch = append(batch, &metadata)
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, flush()
+}
@@ -0,0 +1,331 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/0glabs/0g-data-avail/common"
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	gcommon "github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// batchHeaderCacheSize is the number of batch headers kept in memory by the BatchHeaderStore
+const batchHeaderCacheSize = 1024
+
+const (
+	confirmationBlockIndexName = "ConfirmationBlockIndex"
+
+	// batchListAttribute is the partition key of the confirmation block index, the same for all the batch headers
+	// so that they are all sorted by confirmation block number. Batches are few enough for a single partition.
+	batchListAttribute = "BatchList"
+	batchListPartition = "0"
+)
+
+// BatchHeaderInfo is the part of the confirmation info shared by all the blobs of a batch
+type BatchHeaderInfo struct {
+	BatchHeaderHash         [32]byte
+	BatchID                 uint32
+	BlobCount               uint32
+	BatchSize               uint64
+	SignatoryRecordHash     [32]byte
+	ReferenceBlockNumber    uint32
+	BatchRoot               []byte
+	ConfirmationTxnHash     gcommon.Hash
+	ConfirmationBlockNumber uint32
+	Fee                     []byte
+	QuorumResults           map[core.QuorumID]*core.QuorumResult
+}
+
+// batchHeaderAttributes are the attributes of the blob metadata items which are stored in the BatchHeaderStore instead
+var batchHeaderAttributes = []string{
+	"BatchID",
+	"BlobCount",
+	"BatchSize",
+	"SignatoryRecordHash",
+	"ReferenceBlockNumber",
+	"BatchRoot",
+	"ConfirmationTxnHash",
+	"ConfirmationBlockNumber",
+	"Fee",
+	"QuorumResults",
+}
+
+// NewBatchHeaderInfo extracts the batch header info from the confirmation info of a blob in the batch
+func NewBatchHeaderInfo(confirmationInfo *disperser.ConfirmationInfo) *BatchHeaderInfo {
+	return &BatchHeaderInfo{
+		BatchHeaderHash:         confirmationInfo.BatchHeaderHash,
+		BatchID:                 confirmationInfo.BatchID,
+		BlobCount:               confirmationInfo.BlobCount,
+		BatchSize:               confirmationInfo.BatchSize,
+		SignatoryRecordHash:     confirmationInfo.SignatoryRecordHash,
+		ReferenceBlockNumber:    confirmationInfo.ReferenceBlockNumber,
+		BatchRoot:               confirmationInfo.BatchRoot,
+		ConfirmationTxnHash:     confirmationInfo.ConfirmationTxnHash,
+		ConfirmationBlockNumber: confirmationInfo.ConfirmationBlockNumber,
+		Fee:                     confirmationInfo.Fee,
+		QuorumResults:           confirmationInfo.QuorumResults,
+	}
+}
+
+// Apply fills the batch level fields of the confirmation info of a blob in the batch
+func (h *BatchHeaderInfo) Apply(confirmationInfo *disperser.ConfirmationInfo) {
+	confirmationInfo.BatchID = h.BatchID
+	confirmationInfo.BlobCount = h.BlobCount
+	confirmationInfo.BatchSize = h.BatchSize
+	confirmationInfo.SignatoryRecordHash = h.SignatoryRecordHash
+	confirmationInfo.ReferenceBlockNumber = h.ReferenceBlockNumber
+	confirmationInfo.BatchRoot = h.BatchRoot
+	confirmationInfo.ConfirmationTxnHash = h.ConfirmationTxnHash
+	confirmationInfo.ConfirmationBlockNumber = h.ConfirmationBlockNumber
+	confirmationInfo.Fee = h.Fee
+	confirmationInfo.QuorumResults = h.QuorumResults
+}
+
+// BatchHeaderStore stores the batch header info once per batch, instead of in the metadata of every blob in the batch
+// - BatchHeaders: (Partition Key: BatchHeaderHash) -> BatchHeaderInfo
+// - Indexes
+//   - ConfirmationBlockIndex: (Partition Key: BatchList, Sort Key: ConfirmationBlockNumber) -> BatchHeaderInfo
+type BatchHeaderStore struct {
+	dynamoDBClient *commondynamodb.Client
+	logger         common.Logger
+	tableName      string
+	// cache holds the batch headers recently written or read, batch headers are immutable once written
+	cache *lru.Cache[[32]byte, *BatchHeaderInfo]
+}
+
+func NewBatchHeaderStore(dynamoDBClient *commondynamodb.Client, logger common.Logger, tableName string) (*BatchHeaderStore, error) {
+	cache, err := lru.New[[32]byte, *BatchHeaderInfo](batchHeaderCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchHeaderStore{
+		dynamoDBClient: dynamoDBClient,
+		logger:         logger,
+		tableName:      tableName,
+		cache:          cache,
+	}, nil
+}
+
+// PutBatchHeaderIfNotExists writes the batch header unless it has already been written
+func (s *BatchHeaderStore) PutBatchHeaderIfNotExists(ctx context.Context, header *BatchHeaderInfo) error {
+	if s.cache.Contains(header.BatchHeaderHash) {
+		return nil
+	}
+	existing, err := s.getBatchHeader(ctx, header.BatchHeaderHash)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		s.cache.Add(header.BatchHeaderHash, existing)
+		return nil
+	}
+
+	item, err := MarshalBatchHeaderInfo(header)
+	if err != nil {
+		return err
+	}
+	if err := s.dynamoDBClient.PutItem(ctx, s.tableName, item); err != nil {
+		return err
+	}
+	s.cache.Add(header.BatchHeaderHash, header)
+	return nil
+}
+
+// GetBatchHeader returns the batch header with the given hash, or an error if it doesn't exist
+func (s *BatchHeaderStore) GetBatchHeader(ctx context.Context, batchHeaderHash [32]byte) (*BatchHeaderInfo, error) {
+	if header, ok := s.cache.Get(batchHeaderHash); ok {
+		return header, nil
+	}
+	header, err := s.getBatchHeader(ctx, batchHeaderHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("there is no batch header %x", batchHeaderHash)
+	}
+	s.cache.Add(batchHeaderHash, header)
+	return header, nil
+}
+
+// GetBatchHeaders returns up to pageSize batch headers ordered by confirmation block number, starting after the batch
+// lastBatchHeaderHash, or from the first one if it is nil. There are no more batch headers if fewer are returned.
+// The batch headers written before the confirmation block index was added aren't listed.
+func (s *BatchHeaderStore) GetBatchHeaders(ctx context.Context, pageSize int, lastBatchHeaderHash *[32]byte) ([]*BatchHeaderInfo, error) {
+	return s.queryBatchHeaders(ctx, "BatchList = :list", commondynamodb.ExpresseionValues{
+		":list": &types.AttributeValueMemberN{
+			Value: batchListPartition,
+		},
+	}, pageSize, lastBatchHeaderHash)
+}
+
+// GetBatchHeadersConfirmedAfter is GetBatchHeaders for the batches confirmed after the given block only
+func (s *BatchHeaderStore) GetBatchHeadersConfirmedAfter(ctx context.Context, blockNumber uint32, pageSize int, lastBatchHeaderHash *[32]byte) ([]*BatchHeaderInfo, error) {
+	return s.queryBatchHeaders(ctx, "BatchList = :list AND ConfirmationBlockNumber > :block", commondynamodb.ExpresseionValues{
+		":list": &types.AttributeValueMemberN{
+			Value: batchListPartition,
+		},
+		":block": &types.AttributeValueMemberN{
+			Value: strconv.FormatUint(uint64(blockNumber), 10),
+		},
+	}, pageSize, lastBatchHeaderHash)
+}
+
+// queryBatchHeaders returns up to pageSize batch headers of the confirmation block index matching the key condition,
+// starting after the batch lastBatchHeaderHash, or from the first one if it is nil
+func (s *BatchHeaderStore) queryBatchHeaders(ctx context.Context, keyCondition string, expressionValues commondynamodb.ExpresseionValues, pageSize int, lastBatchHeaderHash *[32]byte) ([]*BatchHeaderInfo, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be greater than 0")
+	}
+	var exclusiveStartKey commondynamodb.Key
+	if lastBatchHeaderHash != nil {
+		last, err := s.getBatchHeader(ctx, *lastBatchHeaderHash)
+		if err != nil {
+			return nil, err
+		}
+		if last == nil {
+			return nil, disperser.ErrBatchNotFound
+		}
+		exclusiveStartKey = confirmationBlockIndexKey(last)
+	}
+
+	headers := make([]*BatchHeaderInfo, 0, pageSize)
+	for len(headers) < pageSize {
+		items, lastEvaluatedKey, err := s.dynamoDBClient.QueryIndexWithPagination(ctx, s.tableName, confirmationBlockIndexName, keyCondition, expressionValues, int32(pageSize-len(headers)), exclusiveStartKey)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			header := BatchHeaderInfo{}
+			if err := attributevalue.UnmarshalMap(item, &header); err != nil {
+				return nil, err
+			}
+			headers = append(headers, &header)
+		}
+		// the query stops early once the response size limit is reached
+		if len(lastEvaluatedKey) == 0 {
+			break
+		}
+		exclusiveStartKey = lastEvaluatedKey
+	}
+	return headers, nil
+}
+
+// confirmationBlockIndexKey returns the key of the batch header in the confirmation block index
+func confirmationBlockIndexKey(header *BatchHeaderInfo) commondynamodb.Key {
+	return commondynamodb.Key{
+		"BatchHeaderHash": &types.AttributeValueMemberB{
+			Value: header.BatchHeaderHash[:],
+		},
+		batchListAttribute: &types.AttributeValueMemberN{
+			Value: batchListPartition,
+		},
+		"ConfirmationBlockNumber": &types.AttributeValueMemberN{
+			Value: strconv.FormatUint(uint64(header.ConfirmationBlockNumber), 10),
+		},
+	}
+}
+
+func (s *BatchHeaderStore) getBatchHeader(ctx context.Context, batchHeaderHash [32]byte) (*BatchHeaderInfo, error) {
+	item, err := s.dynamoDBClient.GetItem(ctx, s.tableName, map[string]types.AttributeValue{
+		"BatchHeaderHash": &types.AttributeValueMemberB{
+			Value: batchHeaderHash[:],
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(item) == 0 {
+		return nil, nil
+	}
+
+	header := BatchHeaderInfo{}
+	err = attributevalue.UnmarshalMap(item, &header)
+	if err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+// MarshalBatchHeaderInfo marshals the batch header, along with the partition key of the confirmation block index
+func MarshalBatchHeaderInfo(header *BatchHeaderInfo) (commondynamodb.Item, error) {
+	item, err := attributevalue.MarshalMap(header)
+	if err != nil {
+		return nil, err
+	}
+	item[batchListAttribute] = &types.AttributeValueMemberN{
+		Value: batchListPartition,
+	}
+	return item, nil
+}
+
+// MarshalBlobMetadataWithoutBatchHeader marshals the blob metadata without the batch level fields of the confirmation info
+func MarshalBlobMetadataWithoutBatchHeader(metadata *disperser.BlobMetadata) (commondynamodb.Item, error) {
+	item, err := MarshalBlobMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range batchHeaderAttributes {
+		delete(item, attribute)
+	}
+	return item, nil
+}
+
+func GenerateBatchHeaderTableSchema(tableName string, readCapacityUnits int64, writeCapacityUnits int64) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("BatchHeaderHash"),
+				AttributeType: types.ScalarAttributeTypeB,
+			},
+			{
+				AttributeName: aws.String(batchListAttribute),
+				AttributeType: types.ScalarAttributeTypeN,
+			},
+			{
+				AttributeName: aws.String("ConfirmationBlockNumber"),
+				AttributeType: types.ScalarAttributeTypeN,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("BatchHeaderHash"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		TableName: aws.String(tableName),
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String(confirmationBlockIndexName),
+				KeySchema: []types.KeySchemaElement{
+					{
+						AttributeName: aws.String(batchListAttribute),
+						KeyType:       types.KeyTypeHash,
+					},
+					{
+						AttributeName: aws.String("ConfirmationBlockNumber"),
+						KeyType:       types.KeyTypeRange,
+					},
+				},
+				Projection: &types.Projection{
+					ProjectionType: types.ProjectionTypeAll,
+				},
+				ProvisionedThroughput: &types.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(readCapacityUnits),
+					WriteCapacityUnits: aws.Int64(writeCapacityUnits),
+				},
+			},
+		},
+		ProvisionedThroughput: &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(readCapacityUnits),
+			WriteCapacityUnits: aws.Int64(writeCapacityUnits),
+		},
+	}
+}
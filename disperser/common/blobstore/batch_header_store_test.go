@@ -0,0 +1,227 @@
+package blobstore_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	gcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConfirmationInfo(batchHeaderHash [32]byte, blobIndex uint32, blobCount uint32) *disperser.ConfirmationInfo {
+	return &disperser.ConfirmationInfo{
+		BatchHeaderHash:         batchHeaderHash,
+		BlobIndex:               blobIndex,
+		BlobCount:               blobCount,
+		SignatoryRecordHash:     [32]byte{1},
+		ReferenceBlockNumber:    132,
+		BatchRoot:               gcommon.Hash{2}.Bytes(),
+		BlobInclusionProof:      gcommon.Hash{byte(blobIndex)}.Bytes(),
+		CommitmentRoot:          gcommon.Hash{3, byte(blobIndex)}.Bytes(),
+		Length:                  32,
+		BatchID:                 99,
+		ConfirmationTxnHash:     gcommon.HexToHash("0x123"),
+		ConfirmationBlockNumber: 150,
+		Fee:                     []byte{0},
+		QuorumResults: map[core.QuorumID]*core.QuorumResult{
+			0: {QuorumID: 0, PercentSigned: 100},
+			1: {QuorumID: 1, PercentSigned: 80},
+		},
+		BlobQuorumInfos: []*core.BlobQuorumInfo{{
+			SecurityParam: core.SecurityParam{QuorumID: 0, AdversaryThreshold: 80, QuorumThreshold: 100},
+			ChunkLength:   10,
+		}},
+	}
+}
+
+func TestMarkBlobConfirmedWithBatchHeaderStore(t *testing.T) {
+	ctx := context.Background()
+	batchHeaderStore, err := blobstore.NewBatchHeaderStore(dynamoClient, logger, batchHeaderTableName)
+	assert.NoError(t, err)
+	sharedStorage := blobstore.NewSharedStorage(bucketName, s3Client, false, nil, blobMetadataStore, batchHeaderStore, 0, "", nil, logger)
+
+	batchHeaderHash := [32]byte{7, 7}
+	numBlobs := 3
+	keys := make([]disperser.BlobKey, numBlobs)
+	for i := 0; i < numBlobs; i++ {
+		keys[i], err = sharedStorage.StoreBlob(ctx, &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: []byte(fmt.Sprintf("batch header store blob %d", i)),
+		}, uint64(time.Now().UnixNano()))
+		assert.NoError(t, err)
+		metadata, err := sharedStorage.GetBlobMetadata(ctx, keys[i])
+		assert.NoError(t, err)
+		_, err = sharedStorage.MarkBlobConfirmed(ctx, metadata, newTestConfirmationInfo(batchHeaderHash, uint32(i), uint32(numBlobs)))
+		assert.NoError(t, err)
+	}
+
+	// the batch level fields are only stored in the batch header table
+	item, err := dynamoClient.GetItem(ctx, metadataTableName, commondynamodb.Key{
+		"BlobHash":     &types.AttributeValueMemberS{Value: keys[0].BlobHash},
+		"MetadataHash": &types.AttributeValueMemberS{Value: keys[0].MetadataHash},
+	})
+	assert.NoError(t, err)
+	assert.NotContains(t, item, "BatchRoot")
+	assert.NotContains(t, item, "QuorumResults")
+	assert.Contains(t, item, "BatchHeaderHash")
+	header, err := batchHeaderStore.GetBatchHeader(ctx, batchHeaderHash)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(99), header.BatchID)
+
+	// and read back transparently
+	for i, key := range keys {
+		metadata, err := sharedStorage.GetBlobMetadata(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, disperser.Confirmed, metadata.BlobStatus)
+		assert.Equal(t, newTestConfirmationInfo(batchHeaderHash, uint32(i), uint32(numBlobs)), metadata.ConfirmationInfo)
+	}
+	metadatas, err := sharedStorage.GetAllBlobMetadataByBatch(ctx, batchHeaderHash)
+	assert.NoError(t, err)
+	assert.Len(t, metadatas, numBlobs)
+	for _, metadata := range metadatas {
+		assert.Equal(t, newTestConfirmationInfo(batchHeaderHash, metadata.ConfirmationInfo.BlobIndex, uint32(numBlobs)), metadata.ConfirmationInfo)
+	}
+}
+
+// attributeSize approximates the size of an attribute value as accounted by DynamoDB
+func attributeSize(value types.AttributeValue) int {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return len(v.Value)
+	case *types.AttributeValueMemberN:
+		return len(v.Value)/2 + 1
+	case *types.AttributeValueMemberB:
+		return len(v.Value)
+	case *types.AttributeValueMemberBOOL, *types.AttributeValueMemberNULL:
+		return 1
+	case *types.AttributeValueMemberM:
+		return 3 + itemSize(v.Value)
+	case *types.AttributeValueMemberL:
+		size := 3
+		for _, element := range v.Value {
+			size += 1 + attributeSize(element)
+		}
+		return size
+	}
+	return 0
+}
+
+func itemSize(item map[string]types.AttributeValue) int {
+	size := 0
+	for name, value := range item {
+		size += len(name) + attributeSize(value)
+	}
+	return size
+}
+
+func TestBatchHeaderStoreStorageReduction(t *testing.T) {
+	batchHeaderHash := [32]byte{8}
+	for _, numBlobs := range []int{10, 100, 1000} {
+		embedded := 0
+		separate := 0
+		for i := 0; i < numBlobs; i++ {
+			metadata := &disperser.BlobMetadata{
+				BlobHash:     fmt.Sprintf("blob-hash-%d", i),
+				MetadataHash: fmt.Sprintf("metadata-hash-%d", i),
+				BlobStatus:   disperser.Confirmed,
+				RequestMetadata: &disperser.RequestMetadata{
+					BlobRequestHeader: core.BlobRequestHeader{
+						SecurityParams: []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 80, QuorumThreshold: 100}},
+					},
+					BlobSize:    1024,
+					RequestedAt: uint64(time.Now().UnixNano()),
+				},
+				ConfirmationInfo: newTestConfirmationInfo(batchHeaderHash, uint32(i), uint32(numBlobs)),
+			}
+			item, err := blobstore.MarshalBlobMetadata(metadata)
+			assert.NoError(t, err)
+			embedded += itemSize(item)
+
+			item, err = blobstore.MarshalBlobMetadataWithoutBatchHeader(metadata)
+			assert.NoError(t, err)
+			separate += itemSize(item)
+		}
+		header, err := blobstore.MarshalBatchHeaderInfo(blobstore.NewBatchHeaderInfo(newTestConfirmationInfo(batchHeaderHash, 0, uint32(numBlobs))))
+		assert.NoError(t, err)
+		separate += itemSize(header)
+
+		reduction := 1 - float64(separate)/float64(embedded)
+		t.Logf("batch of %d blobs: %d bytes with embedded batch headers, %d bytes with a batch header table (%.1f%% less)", numBlobs, embedded, separate, 100*reduction)
+		assert.Less(t, separate, embedded)
+		if numBlobs >= 100 {
+			assert.Greater(t, reduction, 0.2)
+		}
+	}
+}
+
+func TestGetBatchHeaders(t *testing.T) {
+	ctx := context.Background()
+	batchHeaderStore, err := blobstore.NewBatchHeaderStore(dynamoClient, logger, batchHeaderTableName)
+	assert.NoError(t, err)
+
+	// the confirmation blocks are later than the ones of the other tests, so these batches are listed last
+	numBatches := 5
+	hashes := make([][32]byte, numBatches)
+	for i := 0; i < numBatches; i++ {
+		hashes[i] = [32]byte{9, byte(i)}
+		confirmationInfo := newTestConfirmationInfo(hashes[i], 0, uint32(i+1))
+		confirmationInfo.ConfirmationBlockNumber = uint32(100000 - i)
+		confirmationInfo.BatchSize = uint64(1024 * (i + 1))
+		assert.NoError(t, batchHeaderStore.PutBatchHeaderIfNotExists(ctx, blobstore.NewBatchHeaderInfo(confirmationInfo)))
+	}
+
+	headers := make([]*blobstore.BatchHeaderInfo, 0)
+	var last *[32]byte
+	for {
+		page, err := batchHeaderStore.GetBatchHeaders(ctx, 2, last)
+		assert.NoError(t, err)
+		headers = append(headers, page...)
+		if len(page) < 2 {
+			break
+		}
+		last = &page[len(page)-1].BatchHeaderHash
+	}
+	assert.GreaterOrEqual(t, len(headers), numBatches)
+	listed := headers[len(headers)-numBatches:]
+	for i, header := range listed {
+		// sorted by confirmation block, which decreases with the index of the batch
+		batch := numBatches - 1 - i
+		assert.Equal(t, hashes[batch], header.BatchHeaderHash)
+		assert.Equal(t, uint32(batch+1), header.BlobCount)
+		assert.Equal(t, uint64(1024*(batch+1)), header.BatchSize)
+	}
+
+	_, err = batchHeaderStore.GetBatchHeaders(ctx, 2, &[32]byte{9, 9, 9})
+	assert.ErrorIs(t, err, disperser.ErrBatchNotFound)
+}
+
+func TestGetBatchHeadersConfirmedAfter(t *testing.T) {
+	ctx := context.Background()
+	batchHeaderStore, err := blobstore.NewBatchHeaderStore(dynamoClient, logger, batchHeaderTableName)
+	assert.NoError(t, err)
+
+	// the confirmation blocks are later than the ones of the other tests
+	for i := 0; i < 3; i++ {
+		confirmationInfo := newTestConfirmationInfo([32]byte{8, byte(i)}, 0, 1)
+		confirmationInfo.ConfirmationBlockNumber = uint32(200000 + i)
+		assert.NoError(t, batchHeaderStore.PutBatchHeaderIfNotExists(ctx, blobstore.NewBatchHeaderInfo(confirmationInfo)))
+	}
+
+	headers, err := batchHeaderStore.GetBatchHeadersConfirmedAfter(ctx, 200000, 1, nil)
+	assert.NoError(t, err)
+	assert.Len(t, headers, 1)
+	assert.Equal(t, [32]byte{8, 1}, headers[0].BatchHeaderHash)
+	headers, err = batchHeaderStore.GetBatchHeadersConfirmedAfter(ctx, 200000, 10, &headers[0].BatchHeaderHash)
+	assert.NoError(t, err)
+	assert.Len(t, headers, 1)
+	assert.Equal(t, [32]byte{8, 2}, headers[0].BatchHeaderHash)
+}
@@ -2,48 +2,112 @@ package blobstore
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
 	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
 	statusIndexName = "StatusIndex"
 	batchIndexName  = "BatchIndex"
+	uploadIndexName = "UploadIndex"
+
+	// requestedAtDayAttribute buckets blobs by the day they were requested at, since a GSI needs a partition key
+	requestedAtDayAttribute = "RequestedAtDay"
+	requestedAtDayDuration  = uint64(24 * time.Hour)
+
+	// statusHistoryAttribute is the list of the status events of a blob, see disperser.BlobMetadata.BlobStatusHistory
+	statusHistoryAttribute = "BlobStatusHistory"
+
+	// statusTransactionSize is the number of blobs whose status is updated in a single transaction
+	statusTransactionSize = 25
+
+	// scanSegments is the number of segments of the table scanned in parallel when streaming all the metadata
+	scanSegments = 4
+
+	// statusQueryPageSize is the page size GetBlobMetadataByStatus reads the status index with
+	statusQueryPageSize = 10000
+
+	// defaultTimeRangeQueryLimit is the maximum number of metadata returned by GetBlobMetadataByStatusAndTimeRange,
+	// unless set with WithTimeRangeQueryLimit
+	defaultTimeRangeQueryLimit = 10000
 )
 
+// BlobPageInfo is the pagination metadata returned along with a page of blob metadata
+type BlobPageInfo struct {
+	// NextPageToken is used to fetch the next page, it is empty when there are no more pages
+	NextPageToken string
+	// PageBytesTotal is the total size in bytes of the blobs in the page
+	PageBytesTotal uint64
+}
+
+// uploadPageToken is the decoded page token of GetBlobMetadataUploadedBetween
+type uploadPageToken struct {
+	Day          uint64 `json:"day"`
+	BlobHash     string `json:"blobHash,omitempty"`
+	MetadataHash string `json:"metadataHash,omitempty"`
+	RequestedAt  uint64 `json:"requestedAt,omitempty"`
+}
+
 // BlobMetadataStore is a blob metadata storage backed by DynamoDB
 // The blob metadata is stored in a single table and replicated in several indexes.
 // - Metadata: (Partition Key: BlobKey, Sort Key: MetadataHash) -> Metadata
 // - Indexes
-//   - StatusIndex: (Partition Key: Status, Sort Key: RequestedAt) -> Metadata
+//   - StatusIndex: (Partition Key: Status, Sort Key: RequestedAt) -> Metadata, also queried by time range
 //   - BatchIndex: (Partition Key: BatchHeaderHash, Sort Key: BlobIndex) -> Metadata
+//   - UploadIndex: (Partition Key: RequestedAtDay, Sort Key: RequestedAt) -> Metadata
 type BlobMetadataStore struct {
 	dynamoDBClient *commondynamodb.Client
 	logger         common.Logger
 	tableName      string
 	ttl            time.Duration
+	// cache holds the metadata read by GetBlobMetadata and GetBlobMetadataInBatch, it is disabled if nil
+	cache *metadataCache
+	// timeRangeQueryLimit is the maximum number of metadata returned by GetBlobMetadataByStatusAndTimeRange
+	timeRangeQueryLimit int
 }
 
-func NewBlobMetadataStore(dynamoDBClient *commondynamodb.Client, logger common.Logger, tableName string, ttl time.Duration) *BlobMetadataStore {
+func NewBlobMetadataStore(dynamoDBClient *commondynamodb.Client, logger common.Logger, tableName string, ttl time.Duration, opts ...BlobMetadataStoreOption) *BlobMetadataStore {
 	logger.Debugf("creating blob metadata store with table %s with TTL: %s", tableName, ttl)
-	return &BlobMetadataStore{
-		dynamoDBClient: dynamoDBClient,
-		logger:         logger,
-		tableName:      tableName,
-		ttl:            ttl,
+	s := &BlobMetadataStore{
+		dynamoDBClient:      dynamoDBClient,
+		logger:              logger,
+		tableName:           tableName,
+		ttl:                 ttl,
+		timeRangeQueryLimit: defaultTimeRangeQueryLimit,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// TTL is the retention of the blobs in quorums without a retention
+func (s *BlobMetadataStore) TTL() time.Duration {
+	return s.ttl
 }
 
-func (s *BlobMetadataStore) QueueNewBlobMetadata(ctx context.Context, blobMetadata *disperser.BlobMetadata) error {
+func (s *BlobMetadataStore) QueueNewBlobMetadata(ctx context.Context, blobMetadata *disperser.BlobMetadata) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "BlobMetadataStore.QueueNewBlobMetadata", attribute.String("blob.key", blobMetadata.GetBlobKey().String()))
+	defer func() { tracing.EndSpan(span, err) }()
+	defer s.cache.invalidate(blobMetadata.GetBlobKey())
 	item, err := MarshalBlobMetadata(blobMetadata)
 	if err != nil {
 		return err
@@ -52,7 +116,74 @@ func (s *BlobMetadataStore) QueueNewBlobMetadata(ctx context.Context, blobMetada
 	return s.dynamoDBClient.PutItem(ctx, s.tableName, item)
 }
 
+// QueueNewBlobMetadataConditional stores the metadata of a new blob unless the metadata of the same blob key is already stored.
+// It returns whether the metadata was stored.
+func (s *BlobMetadataStore) QueueNewBlobMetadataConditional(ctx context.Context, blobMetadata *disperser.BlobMetadata) (stored bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "BlobMetadataStore.QueueNewBlobMetadata", attribute.String("blob.key", blobMetadata.GetBlobKey().String()))
+	defer func() {
+		span.SetAttributes(attribute.Bool("stored", stored))
+		tracing.EndSpan(span, err)
+	}()
+	defer s.cache.invalidate(blobMetadata.GetBlobKey())
+	item, err := MarshalBlobMetadata(blobMetadata)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.dynamoDBClient.PutItemWithCondition(ctx, s.tableName, item, expression.AttributeNotExists(expression.Name("MetadataHash")))
+	if errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PutBlobMetadatas stores the metadata of the blobs in batches, overwriting the metadata already stored for the same blob keys.
+// It is meant to restore a backup, new blobs should be queued with QueueNewBlobMetadata.
+func (s *BlobMetadataStore) PutBlobMetadatas(ctx context.Context, blobMetadatas []*disperser.BlobMetadata) error {
+	defer func() {
+		for _, blobMetadata := range blobMetadatas {
+			s.cache.invalidate(blobMetadata.GetBlobKey())
+		}
+	}()
+	items := make([]commondynamodb.Item, len(blobMetadatas))
+	for i, blobMetadata := range blobMetadatas {
+		item, err := MarshalBlobMetadata(blobMetadata)
+		if err != nil {
+			return err
+		}
+		items[i] = item
+	}
+
+	failedItems, err := s.dynamoDBClient.PutItems(ctx, s.tableName, items)
+	if err != nil {
+		return err
+	}
+	if len(failedItems) > 0 {
+		return fmt.Errorf("failed to put %d of %d blob metadata", len(failedItems), len(items))
+	}
+	return nil
+}
+
+// PutExistingBlobMetadata overwrites the stored metadata of the blob, only if it is still stored so that a blob removed
+// meanwhile isn't recreated. It returns disperser.ErrBlobNotFound if the blob isn't stored.
+func (s *BlobMetadataStore) PutExistingBlobMetadata(ctx context.Context, blobMetadata *disperser.BlobMetadata) error {
+	defer s.cache.invalidate(blobMetadata.GetBlobKey())
+	item, err := MarshalBlobMetadata(blobMetadata)
+	if err != nil {
+		return err
+	}
+	err = s.dynamoDBClient.PutItemWithCondition(ctx, s.tableName, item, expression.AttributeExists(expression.Name("BlobHash")))
+	if errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return disperser.ErrBlobNotFound
+	}
+	return err
+}
+
 func (s *BlobMetadataStore) RemoveBlobMetadata(ctx context.Context, blobMetadata *disperser.BlobMetadata) error {
+	defer s.cache.invalidate(blobMetadata.GetBlobKey())
 	return s.dynamoDBClient.DeleteItem(ctx, s.tableName, map[string]types.AttributeValue{
 		"BlobHash": &types.AttributeValueMemberS{
 			Value: blobMetadata.BlobHash,
@@ -64,6 +195,9 @@ func (s *BlobMetadataStore) RemoveBlobMetadata(ctx context.Context, blobMetadata
 }
 
 func (s *BlobMetadataStore) GetBlobMetadata(ctx context.Context, metadataKey disperser.BlobKey) (*disperser.BlobMetadata, error) {
+	if metadata, ok := s.cache.get(metadataKey); ok {
+		return metadata, nil
+	}
 	item, err := s.dynamoDBClient.GetItem(ctx, s.tableName, map[string]types.AttributeValue{
 		"BlobHash": &types.AttributeValueMemberS{
 			Value: metadataKey.BlobHash,
@@ -80,17 +214,16 @@ func (s *BlobMetadataStore) GetBlobMetadata(ctx context.Context, metadataKey dis
 	if err != nil {
 		return nil, err
 	}
+	s.cache.add(metadata)
 
 	return metadata, nil
 }
 
-// GetBlobMetadataByStatus returns all the metadata with the given status
-// Because this function scans the entire index, it should only be used for status with a limited number of items.
-// It should only be used to filter "Processing" status. To support other status, a streaming version should be implemented.
-func (s *BlobMetadataStore) GetBlobMetadataByStatus(ctx context.Context, status disperser.BlobStatus) ([]*disperser.BlobMetadata, error) {
-	items, err := s.dynamoDBClient.QueryIndex(ctx, s.tableName, statusIndexName, "BlobStatus = :status", commondynamodb.ExpresseionValues{
-		":status": &types.AttributeValueMemberN{
-			Value: strconv.Itoa(int(status)),
+// GetBlobMetadataByBlobHash returns the metadata of all the dispersals of the blob with the given hash
+func (s *BlobMetadataStore) GetBlobMetadataByBlobHash(ctx context.Context, blobHash disperser.BlobHash) ([]*disperser.BlobMetadata, error) {
+	items, err := s.dynamoDBClient.Query(ctx, s.tableName, "BlobHash = :blob_hash", commondynamodb.ExpresseionValues{
+		":blob_hash": &types.AttributeValueMemberS{
+			Value: blobHash,
 		}})
 	if err != nil {
 		return nil, err
@@ -107,6 +240,358 @@ func (s *BlobMetadataStore) GetBlobMetadataByStatus(ctx context.Context, status
 	return metadata, nil
 }
 
+// GetBlobMetadataByHashPrefix returns the metadata of up to limit blobs whose blob hash or metadata hash, which is the
+// blob key when the metadata hash is used as the blob key, starts with the given prefix.
+// It scans the entire table, so it should only be used for infrequent operator lookups.
+func (s *BlobMetadataStore) GetBlobMetadataByHashPrefix(ctx context.Context, hashPrefix string, limit int) ([]*disperser.BlobMetadata, error) {
+	if len(hashPrefix) < disperser.MinHashPrefixLength {
+		return nil, disperser.ErrHashPrefixTooShort
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+
+	items, err := s.dynamoDBClient.Scan(ctx, s.tableName, "begins_with(BlobHash, :prefix) OR begins_with(MetadataHash, :prefix)", commondynamodb.ExpresseionValues{
+		":prefix": &types.AttributeValueMemberS{
+			Value: hashPrefix,
+		}}, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make([]*disperser.BlobMetadata, len(items))
+	for i, item := range items {
+		metadata[i], err = UnmarshalBlobMetadata(item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return metadata, nil
+}
+
+// StreamAllMetadata calls fn with the metadata of every blob in the table, e.g. to back up the table.
+// The table is read with consistent reads by several segments in parallel, but fn is never called concurrently.
+// The metadata are passed in no particular order, and the scan stops at the first error returned by fn.
+func (s *BlobMetadataStore) StreamAllMetadata(ctx context.Context, fn func(*disperser.BlobMetadata) error) error {
+	var (
+		mu      sync.Mutex
+		stopErr error
+	)
+	return s.dynamoDBClient.ParallelScan(ctx, s.tableName, scanSegments, func(items []commondynamodb.Item) error {
+		metadatas := make([]*disperser.BlobMetadata, len(items))
+		for i, item := range items {
+			metadata, err := UnmarshalBlobMetadata(item)
+			if err != nil {
+				return err
+			}
+			metadatas[i] = metadata
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		// the other segments may still be delivering pages after the scan was stopped
+		for _, metadata := range metadatas {
+			if stopErr != nil {
+				return stopErr
+			}
+			if stopErr = ctx.Err(); stopErr != nil {
+				return stopErr
+			}
+			stopErr = fn(metadata)
+		}
+		return stopErr
+	})
+}
+
+// GetBlobMetadataByStatus returns all the metadata with the given status, read in pages of statusQueryPageSize.
+// Because this function reads the entire status index partition, it should only be used for status with a limited number of items,
+// GetBlobMetadataByStatusPaginated should be used otherwise.
+func (s *BlobMetadataStore) GetBlobMetadataByStatus(ctx context.Context, status disperser.BlobStatus) ([]*disperser.BlobMetadata, error) {
+	metadatas := make([]*disperser.BlobMetadata, 0)
+	var exclusiveStartKey *disperser.BlobStoreExclusiveStartKey
+	for {
+		page, nextKey, err := s.GetBlobMetadataByStatusPaginated(ctx, status, statusQueryPageSize, exclusiveStartKey)
+		if err != nil {
+			return nil, err
+		}
+		metadatas = append(metadatas, page...)
+		if nextKey == nil {
+			return metadatas, nil
+		}
+		exclusiveStartKey = nextKey
+	}
+}
+
+// GetBlobMetadataByStatusPaginated returns up to pageSize metadata with the given status, sorted by RequestedAt, starting
+// after exclusiveStartKey, or from the first one if it is nil. The returned key is nil if there are no more metadata.
+// The page may be shorter than pageSize even if there are more metadata, e.g. when the query reaches the DynamoDB response size limit.
+func (s *BlobMetadataStore) GetBlobMetadataByStatusPaginated(ctx context.Context, status disperser.BlobStatus, pageSize int32, exclusiveStartKey *disperser.BlobStoreExclusiveStartKey) ([]*disperser.BlobMetadata, *disperser.BlobStoreExclusiveStartKey, error) {
+	if pageSize <= 0 {
+		return nil, nil, fmt.Errorf("page size must be greater than 0")
+	}
+	var startKey commondynamodb.Key
+	if exclusiveStartKey != nil {
+		startKey = statusIndexKey(exclusiveStartKey)
+	}
+	items, lastEvaluatedKey, err := s.dynamoDBClient.QueryIndexWithPagination(ctx, s.tableName, statusIndexName, "BlobStatus = :status", commondynamodb.ExpresseionValues{
+		":status": &types.AttributeValueMemberN{
+			Value: strconv.Itoa(int(status)),
+		}}, pageSize, startKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadatas := make([]*disperser.BlobMetadata, len(items))
+	for i, item := range items {
+		metadatas[i], err = UnmarshalBlobMetadata(item)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(lastEvaluatedKey) == 0 {
+		return metadatas, nil, nil
+	}
+
+	nextKey := &disperser.BlobStoreExclusiveStartKey{}
+	if err := attributevalue.UnmarshalMap(lastEvaluatedKey, nextKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal last evaluated key: %w", err)
+	}
+	return metadatas, nextKey, nil
+}
+
+// GetBlobMetadataByStatusAndTimeRange returns the metadata with the given status requested within [from, to], sorted by
+// RequestedAt in ascending order. The status index is read in pages until the time range is exhausted or the time range
+// query limit is reached, the later metadata are not returned.
+func (s *BlobMetadataStore) GetBlobMetadataByStatusAndTimeRange(ctx context.Context, status disperser.BlobStatus, from, to time.Time) ([]*disperser.BlobMetadata, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("invalid time range: from %v is after to %v", from, to)
+	}
+
+	metadatas := make([]*disperser.BlobMetadata, 0)
+	var exclusiveStartKey commondynamodb.Key
+	for len(metadatas) < s.timeRangeQueryLimit {
+		pageSize := s.timeRangeQueryLimit - len(metadatas)
+		if pageSize > statusQueryPageSize {
+			pageSize = statusQueryPageSize
+		}
+		items, lastEvaluatedKey, err := s.dynamoDBClient.QueryIndexWithPagination(ctx, s.tableName, statusIndexName, "BlobStatus = :status AND RequestedAt BETWEEN :from AND :to", commondynamodb.ExpresseionValues{
+			":status": &types.AttributeValueMemberN{
+				Value: strconv.Itoa(int(status)),
+			},
+			":from": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(from.UnixNano(), 10),
+			},
+			":to": &types.AttributeValueMemberN{
+				Value: strconv.FormatInt(to.UnixNano(), 10),
+			},
+		}, int32(pageSize), exclusiveStartKey)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			metadata, err := UnmarshalBlobMetadata(item)
+			if err != nil {
+				return nil, err
+			}
+			metadatas = append(metadatas, metadata)
+		}
+		if len(lastEvaluatedKey) == 0 {
+			break
+		}
+		exclusiveStartKey = lastEvaluatedKey
+	}
+	return metadatas, nil
+}
+
+// statusIndexKey returns the key of the blob in the status index
+func statusIndexKey(key *disperser.BlobStoreExclusiveStartKey) commondynamodb.Key {
+	return commondynamodb.Key{
+		"BlobHash": &types.AttributeValueMemberS{
+			Value: key.BlobHash,
+		},
+		"MetadataHash": &types.AttributeValueMemberS{
+			Value: key.MetadataHash,
+		},
+		"BlobStatus": &types.AttributeValueMemberN{
+			Value: strconv.Itoa(int(key.BlobStatus)),
+		},
+		"RequestedAt": &types.AttributeValueMemberN{
+			Value: strconv.FormatUint(key.RequestedAt, 10),
+		},
+	}
+}
+
+// GetBlobMetadataUploadedBetween returns a page of the metadata requested within [since, until] (in nanoseconds), regardless of their status.
+// The metadata are sorted by RequestedAt in ascending order. An empty pageToken fetches the first page.
+// The metadata written before the UploadIndex was added are only returned once BackfillRequestedAtDay was run on the table.
+func (s *BlobMetadataStore) GetBlobMetadataUploadedBetween(ctx context.Context, since uint64, until uint64, pageSize int, pageToken string) ([]*disperser.BlobMetadata, *BlobPageInfo, error) {
+	if pageSize <= 0 {
+		return nil, nil, fmt.Errorf("page size must be greater than 0")
+	}
+	if since > until {
+		return nil, nil, fmt.Errorf("invalid time range: since %d is after until %d", since, until)
+	}
+
+	token := uploadPageToken{Day: since / requestedAtDayDuration}
+	if pageToken != "" {
+		var err error
+		token, err = decodeUploadPageToken(pageToken)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	lastDay := until / requestedAtDayDuration
+	var exclusiveStartKey commondynamodb.Key
+	if token.BlobHash != "" {
+		exclusiveStartKey = token.key()
+	}
+
+	metadatas := make([]*disperser.BlobMetadata, 0, pageSize)
+	pageInfo := &BlobPageInfo{}
+	for day := token.Day; day <= lastDay; day++ {
+		items, lastEvaluatedKey, err := s.dynamoDBClient.QueryIndexWithPagination(ctx, s.tableName, uploadIndexName, "RequestedAtDay = :day AND RequestedAt BETWEEN :since AND :until", commondynamodb.ExpresseionValues{
+			":day": &types.AttributeValueMemberN{
+				Value: strconv.FormatUint(day, 10),
+			},
+			":since": &types.AttributeValueMemberN{
+				Value: strconv.FormatUint(since, 10),
+			},
+			":until": &types.AttributeValueMemberN{
+				Value: strconv.FormatUint(until, 10),
+			},
+		}, int32(pageSize-len(metadatas)), exclusiveStartKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		exclusiveStartKey = nil
+
+		for _, item := range items {
+			metadata, err := UnmarshalBlobMetadata(item)
+			if err != nil {
+				return nil, nil, err
+			}
+			metadatas = append(metadatas, metadata)
+			pageInfo.PageBytesTotal += uint64(metadata.RequestMetadata.BlobSize)
+		}
+
+		if len(metadatas) < pageSize {
+			if len(lastEvaluatedKey) > 0 {
+				// the query stopped early (e.g. response size limit), keep reading the same day
+				exclusiveStartKey = lastEvaluatedKey
+				day--
+			}
+			continue
+		}
+
+		// the page is full, remember where to continue from
+		next := uploadPageToken{Day: day + 1}
+		if len(lastEvaluatedKey) > 0 {
+			next, err = newUploadPageToken(day, lastEvaluatedKey)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if next.Day <= lastDay {
+			pageInfo.NextPageToken, err = next.encode()
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		break
+	}
+
+	return metadatas, pageInfo, nil
+}
+
+func newUploadPageToken(day uint64, lastEvaluatedKey commondynamodb.Key) (uploadPageToken, error) {
+	token := uploadPageToken{Day: day}
+	err := attributevalue.UnmarshalMap(lastEvaluatedKey, &token)
+	if err != nil {
+		return uploadPageToken{}, fmt.Errorf("failed to unmarshal last evaluated key: %w", err)
+	}
+	return token, nil
+}
+
+func decodeUploadPageToken(pageToken string) (uploadPageToken, error) {
+	data, err := base64.URLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return uploadPageToken{}, fmt.Errorf("invalid page token: %w", err)
+	}
+	var token uploadPageToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return uploadPageToken{}, fmt.Errorf("invalid page token: %w", err)
+	}
+	return token, nil
+}
+
+func (t uploadPageToken) encode() (string, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// BackfillRequestedAtDay sets the RequestedAtDay attribute of the metadata written before the UploadIndex was added,
+// which are left out of GetBlobMetadataUploadedBetween otherwise. It scans the whole table, the metadata which already
+// have the attribute are skipped, so it can be run again, e.g. after being interrupted. It returns the number of
+// metadata updated.
+func (s *BlobMetadataStore) BackfillRequestedAtDay(ctx context.Context) (int, error) {
+	var updated atomic.Int64
+	err := s.dynamoDBClient.ParallelScan(ctx, s.tableName, scanSegments, func(items []commondynamodb.Item) error {
+		for _, item := range items {
+			if _, ok := item[requestedAtDayAttribute]; ok {
+				continue
+			}
+			requestedAt, ok := item["RequestedAt"].(*types.AttributeValueMemberN)
+			if !ok {
+				continue
+			}
+			day, err := strconv.ParseUint(requestedAt.Value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid RequestedAt %q: %w", requestedAt.Value, err)
+			}
+			key := commondynamodb.Key{
+				"BlobHash":     item["BlobHash"],
+				"MetadataHash": item["MetadataHash"],
+			}
+			update := expression.Set(expression.Name(requestedAtDayAttribute), expression.Value(day/requestedAtDayDuration))
+			// the metadata removed in the meantime are not brought back
+			condition := expression.AttributeExists(expression.Name("BlobHash"))
+			_, err = s.dynamoDBClient.UpdateItemWithCondition(ctx, s.tableName, key, update, condition)
+			if errors.Is(err, commondynamodb.ErrConditionFailed) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			updated.Add(1)
+		}
+		return nil
+	})
+	return int(updated.Load()), err
+}
+
+func (t uploadPageToken) key() commondynamodb.Key {
+	return commondynamodb.Key{
+		"BlobHash": &types.AttributeValueMemberS{
+			Value: t.BlobHash,
+		},
+		"MetadataHash": &types.AttributeValueMemberS{
+			Value: t.MetadataHash,
+		},
+		"RequestedAt": &types.AttributeValueMemberN{
+			Value: strconv.FormatUint(t.RequestedAt, 10),
+		},
+		requestedAtDayAttribute: &types.AttributeValueMemberN{
+			Value: strconv.FormatUint(t.Day, 10),
+		},
+	}
+}
+
 func (s *BlobMetadataStore) GetAllBlobMetadataByBatch(ctx context.Context, batchHeaderHash [32]byte) ([]*disperser.BlobMetadata, error) {
 	items, err := s.dynamoDBClient.QueryIndex(ctx, s.tableName, batchIndexName, "BatchHeaderHash = :batch_header_hash", commondynamodb.ExpresseionValues{
 		":batch_header_hash": &types.AttributeValueMemberB{
@@ -133,6 +618,9 @@ func (s *BlobMetadataStore) GetAllBlobMetadataByBatch(ctx context.Context, batch
 }
 
 func (s *BlobMetadataStore) GetBlobMetadataInBatch(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, error) {
+	if metadata, ok := s.cache.getInBatch(batchHeaderHash, blobIndex); ok {
+		return metadata, nil
+	}
 	items, err := s.dynamoDBClient.QueryIndex(ctx, s.tableName, batchIndexName, "BatchHeaderHash = :batch_header_hash AND BlobIndex = :blob_index", commondynamodb.ExpresseionValues{
 		":batch_header_hash": &types.AttributeValueMemberB{
 			Value: batchHeaderHash[:],
@@ -156,33 +644,95 @@ func (s *BlobMetadataStore) GetBlobMetadataInBatch(ctx context.Context, batchHea
 	if err != nil {
 		return nil, err
 	}
+	s.cache.add(metadata)
 	return metadata, nil
 }
 
-func (s *BlobMetadataStore) IncrementNumRetries(ctx context.Context, existingMetadata *disperser.BlobMetadata) error {
-	_, err := s.dynamoDBClient.UpdateItem(ctx, s.tableName, map[string]types.AttributeValue{
+// IncrementNumRetries atomically increments the retry count of the blob only if it's below maxRetry,
+// so that concurrent batchers can't push it past maxRetry. It returns disperser.ErrMaxRetriesReached otherwise.
+func (s *BlobMetadataStore) IncrementNumRetries(ctx context.Context, existingMetadata *disperser.BlobMetadata, maxRetry uint) error {
+	defer s.cache.invalidate(existingMetadata.GetBlobKey())
+	update := expression.Set(expression.Name("NumRetries"), expression.Name("NumRetries").Plus(expression.Value(1)))
+	condition := expression.Name("NumRetries").LessThan(expression.Value(maxRetry))
+	// the retry count of the event assumes the existing metadata is up to date, it is only informative
+	event := disperser.NewBlobStatusEvent(existingMetadata.BlobStatus, disperser.RetryReason(existingMetadata.NumRetries+1, maxRetry))
+	err := s.updateWithStatusEvent(ctx, existingMetadata.GetBlobKey(), update, &condition, event)
+	if errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return disperser.ErrMaxRetriesReached
+	}
+
+	return err
+}
+
+// ConditionalUpdateExpiry sets the expiry of the blob to newExpiry only if the blob still exists and doesn't expire
+// before minCurrentExpiry, so that a blob which expired, e.g. was just removed by the TTL reaper, isn't extended.
+// It returns whether the expiry was updated.
+func (s *BlobMetadataStore) ConditionalUpdateExpiry(ctx context.Context, metadataKey disperser.BlobKey, minCurrentExpiry, newExpiry uint64) (bool, error) {
+	defer s.cache.invalidate(metadataKey)
+	update := expression.Set(expression.Name("Expiry"), expression.Value(newExpiry))
+	condition := expression.AttributeExists(expression.Name("MetadataHash")).And(expression.Name("Expiry").GreaterThanEqual(expression.Value(minCurrentExpiry)))
+	_, err := s.dynamoDBClient.UpdateItemWithCondition(ctx, s.tableName, map[string]types.AttributeValue{
 		"BlobHash": &types.AttributeValueMemberS{
-			Value: existingMetadata.BlobHash,
+			Value: metadataKey.BlobHash,
 		},
 		"MetadataHash": &types.AttributeValueMemberS{
-			Value: existingMetadata.MetadataHash,
-		},
-	}, commondynamodb.Item{
-		"NumRetries": &types.AttributeValueMemberN{
-			Value: strconv.Itoa(int(existingMetadata.NumRetries + 1)),
+			Value: metadataKey.MetadataHash,
 		},
-	})
+	}, update, condition)
+	if errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
 
+// UpdateExpiry sets the expiry of the blob to newExpiry, it returns disperser.ErrBlobNotFound if the blob doesn't exist,
+// e.g. because it was removed by the TTL reaper meanwhile
+func (s *BlobMetadataStore) UpdateExpiry(ctx context.Context, metadataKey disperser.BlobKey, newExpiry uint64) error {
+	defer s.cache.invalidate(metadataKey)
+	update := expression.Set(expression.Name("Expiry"), expression.Value(newExpiry))
+	condition := expression.AttributeExists(expression.Name("MetadataHash"))
+	_, err := s.dynamoDBClient.UpdateItemWithCondition(ctx, s.tableName, map[string]types.AttributeValue{
+		"BlobHash": &types.AttributeValueMemberS{
+			Value: metadataKey.BlobHash,
+		},
+		"MetadataHash": &types.AttributeValueMemberS{
+			Value: metadataKey.MetadataHash,
+		},
+	}, update, condition)
+	if errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return disperser.ErrBlobNotFound
+	}
 	return err
 }
 
 func (s *BlobMetadataStore) UpdateBlobMetadata(ctx context.Context, metadataKey disperser.BlobKey, updated *disperser.BlobMetadata) error {
+	defer s.cache.invalidate(metadataKey)
 	item, err := MarshalBlobMetadata(updated)
 	if err != nil {
 		return err
 	}
 
-	_, err = s.dynamoDBClient.UpdateItem(ctx, s.tableName, map[string]types.AttributeValue{
+	return s.updateBlobMetadataItem(ctx, metadataKey, item)
+}
+
+// UpdateBlobMetadataWithoutBatchHeader updates the blob metadata except the batch level fields of the confirmation info,
+// which are stored in the BatchHeaderStore
+func (s *BlobMetadataStore) UpdateBlobMetadataWithoutBatchHeader(ctx context.Context, metadataKey disperser.BlobKey, updated *disperser.BlobMetadata) error {
+	defer s.cache.invalidate(metadataKey)
+	item, err := MarshalBlobMetadataWithoutBatchHeader(updated)
+	if err != nil {
+		return err
+	}
+
+	return s.updateBlobMetadataItem(ctx, metadataKey, item)
+}
+
+func (s *BlobMetadataStore) updateBlobMetadataItem(ctx context.Context, metadataKey disperser.BlobKey, item commondynamodb.Item) error {
+	_, err := s.dynamoDBClient.UpdateItem(ctx, s.tableName, map[string]types.AttributeValue{
 		"BlobHash": &types.AttributeValueMemberS{
 			Value: metadataKey.BlobHash,
 		},
@@ -194,18 +744,136 @@ func (s *BlobMetadataStore) UpdateBlobMetadata(ctx context.Context, metadataKey
 	return err
 }
 
-func (s *BlobMetadataStore) SetBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, status disperser.BlobStatus) error {
-	_, err := s.dynamoDBClient.UpdateItem(ctx, s.tableName, map[string]types.AttributeValue{
+// SetBlobStatus sets the status of the blob and records the transition in its history with the reason
+func (s *BlobMetadataStore) SetBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, status disperser.BlobStatus, reason string) error {
+	defer s.cache.invalidate(metadataKey)
+	update := expression.Set(expression.Name("BlobStatus"), expression.Value(status))
+	return s.updateWithStatusEvent(ctx, metadataKey, update, nil, disperser.NewBlobStatusEvent(status, reason))
+}
+
+// TransitionBlobStatus sets the status of the blob if it has the status from, it returns disperser.ErrUnexpectedBlobStatus
+// if it has another status or doesn't exist
+func (s *BlobMetadataStore) TransitionBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, from, to disperser.BlobStatus, reason string) error {
+	defer s.cache.invalidate(metadataKey)
+	update := expression.Set(expression.Name("BlobStatus"), expression.Value(to))
+	condition := expression.Name("BlobStatus").Equal(expression.Value(from))
+	err := s.updateWithStatusEvent(ctx, metadataKey, update, &condition, disperser.NewBlobStatusEvent(to, reason))
+	if errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return disperser.ErrUnexpectedBlobStatus
+	}
+	return err
+}
+
+// updateWithStatusEvent applies the update to the blob along with appending the event to its status history, if the
+// condition holds when not nil. The history is capped at disperser.MaxBlobStatusHistoryLength events like
+// BlobMetadata.AppendStatusEvent: the last event of a full history is replaced.
+// It returns commondynamodb.ErrConditionFailed if the condition doesn't hold.
+func (s *BlobMetadataStore) updateWithStatusEvent(ctx context.Context, metadataKey disperser.BlobKey, update expression.UpdateBuilder, condition *expression.ConditionBuilder, event disperser.BlobStatusEvent) error {
+	av, err := attributevalue.Marshal(event)
+	if err != nil {
+		return err
+	}
+	key := map[string]types.AttributeValue{
 		"BlobHash": &types.AttributeValueMemberS{
 			Value: metadataKey.BlobHash,
 		},
 		"MetadataHash": &types.AttributeValueMemberS{
 			Value: metadataKey.MetadataHash,
 		},
-	}, commondynamodb.Item{
-		"BlobStatus": &types.AttributeValueMemberN{
-			Value: strconv.Itoa(int(status)),
+	}
+
+	history := expression.Name(statusHistoryAttribute)
+	notFull := expression.Or(expression.AttributeNotExists(history), history.Size().LessThan(expression.Value(disperser.MaxBlobStatusHistoryLength)))
+	appendCondition := notFull
+	if condition != nil {
+		appendCondition = condition.And(notFull)
+	}
+	_, err = s.dynamoDBClient.UpdateItemWithCondition(ctx, s.tableName, key, appendStatusEvent(update, av), appendCondition)
+	if !errors.Is(err, commondynamodb.ErrConditionFailed) {
+		return err
+	}
+
+	// the history may be full, or the condition may not hold
+	replaceCondition := expression.AttributeExists(history)
+	if condition != nil {
+		replaceCondition = condition.And(replaceCondition)
+	}
+	lastEvent := expression.Name(fmt.Sprintf("%s[%d]", statusHistoryAttribute, disperser.MaxBlobStatusHistoryLength-1))
+	_, err = s.dynamoDBClient.UpdateItemWithCondition(ctx, s.tableName, key, update.Set(lastEvent, expression.Value(av)), replaceCondition)
+	return err
+}
+
+// appendStatusEvent adds appending the marshalled status event to the status history of the blob to the update
+func appendStatusEvent(update expression.UpdateBuilder, event types.AttributeValue) expression.UpdateBuilder {
+	history := expression.Name(statusHistoryAttribute)
+	return update.Set(history, expression.ListAppend(
+		expression.IfNotExists(history, expression.Value(&types.AttributeValueMemberL{Value: []types.AttributeValue{}})),
+		expression.Value(&types.AttributeValueMemberL{Value: []types.AttributeValue{event}}),
+	))
+}
+
+// SetBlobStatuses sets the status of all the blobs, see SetBlobStatusInTransactions
+func (s *BlobMetadataStore) SetBlobStatuses(ctx context.Context, metadataKeys []disperser.BlobKey, status disperser.BlobStatus) error {
+	defer s.cache.invalidate(metadataKeys...)
+	return SetBlobStatusInTransactions(ctx, s.dynamoDBClient, s.tableName, metadataKeys, status)
+}
+
+// TransactionWriter updates items in DynamoDB transactions, it is implemented by commondynamodb.Client
+type TransactionWriter interface {
+	TransactUpdateItemsWithExpression(ctx context.Context, tableName string, keys []commondynamodb.Key, update expression.UpdateBuilder) error
+}
+
+// SetBlobStatusInTransactions sets the status of the blobs in transactions of 25 blobs which are written in parallel.
+// The blobs of a failed transaction keep their status, while the other transactions may have succeeded.
+// The transition is appended to the status history of the blobs without checking the history length, as a full history
+// would fail the whole transaction. The statuses set in bulk are final, so they add a single event to the history.
+func SetBlobStatusInTransactions(ctx context.Context, writer TransactionWriter, tableName string, metadataKeys []disperser.BlobKey, status disperser.BlobStatus) error {
+	event, err := attributevalue.Marshal(disperser.NewBlobStatusEvent(status, ""))
+	if err != nil {
+		return err
+	}
+	update := appendStatusEvent(expression.Set(expression.Name("BlobStatus"), expression.Value(status)), event)
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for start := 0; start < len(metadataKeys); start += statusTransactionSize {
+		end := start + statusTransactionSize
+		if end > len(metadataKeys) {
+			end = len(metadataKeys)
+		}
+		keys := make([]commondynamodb.Key, 0, end-start)
+		for _, metadataKey := range metadataKeys[start:end] {
+			keys = append(keys, commondynamodb.Key{
+				"BlobHash": &types.AttributeValueMemberS{
+					Value: metadataKey.BlobHash,
+				},
+				"MetadataHash": &types.AttributeValueMemberS{
+					Value: metadataKey.MetadataHash,
+				},
+			})
+		}
+		group.Go(func() error {
+			return writer.TransactUpdateItemsWithExpression(groupCtx, tableName, keys, update)
+		})
+	}
+	return group.Wait()
+}
+
+// SetStorageNodeReceipts sets the storage node receipts of the confirmation info without updating the other attributes
+func (s *BlobMetadataStore) SetStorageNodeReceipts(ctx context.Context, metadataKey disperser.BlobKey, receipts []disperser.StorageNodeReceipt) error {
+	defer s.cache.invalidate(metadataKey)
+	av, err := attributevalue.Marshal(receipts)
+	if err != nil {
+		return err
+	}
+	_, err = s.dynamoDBClient.UpdateItem(ctx, s.tableName, map[string]types.AttributeValue{
+		"BlobHash": &types.AttributeValueMemberS{
+			Value: metadataKey.BlobHash,
 		},
+		"MetadataHash": &types.AttributeValueMemberS{
+			Value: metadataKey.MetadataHash,
+		},
+	}, commondynamodb.Item{
+		"StorageNodeReceipts": av,
 	})
 
 	return err
@@ -234,6 +902,10 @@ func GenerateTableSchema(metadataTableName string, readCapacityUnits int64, writ
 				AttributeName: aws.String("BatchHeaderHash"),
 				AttributeType: types.ScalarAttributeTypeB,
 			},
+			{
+				AttributeName: aws.String(requestedAtDayAttribute),
+				AttributeType: types.ScalarAttributeTypeN,
+			},
 			{
 				AttributeName: aws.String("BlobIndex"),
 				AttributeType: types.ScalarAttributeTypeN,
@@ -291,6 +963,26 @@ func GenerateTableSchema(metadataTableName string, readCapacityUnits int64, writ
 					WriteCapacityUnits: aws.Int64(writeCapacityUnits),
 				},
 			},
+			{
+				IndexName: aws.String(uploadIndexName),
+				KeySchema: []types.KeySchemaElement{
+					{
+						AttributeName: aws.String(requestedAtDayAttribute),
+						KeyType:       types.KeyTypeHash,
+					},
+					{
+						AttributeName: aws.String("RequestedAt"),
+						KeyType:       types.KeyTypeRange,
+					},
+				},
+				Projection: &types.Projection{
+					ProjectionType: types.ProjectionTypeAll,
+				},
+				ProvisionedThroughput: &types.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(readCapacityUnits),
+					WriteCapacityUnits: aws.Int64(writeCapacityUnits),
+				},
+			},
 		},
 		ProvisionedThroughput: &types.ProvisionedThroughput{
 			ReadCapacityUnits:  aws.Int64(readCapacityUnits),
@@ -299,6 +991,48 @@ func GenerateTableSchema(metadataTableName string, readCapacityUnits int64, writ
 	}
 }
 
+// TableDescriber describes dynamodb tables
+type TableDescriber interface {
+	DescribeTable(ctx context.Context, tableName string) (*types.TableDescription, error)
+}
+
+// ValidateTableSchema checks the table has all the global secondary indexes required by the blob metadata store,
+// with the expected key schemas
+func ValidateTableSchema(ctx context.Context, describer TableDescriber, tableName string) error {
+	table, err := describer.DescribeTable(ctx, tableName)
+	if err != nil {
+		return fmt.Errorf("failed to describe table %s: %w", tableName, err)
+	}
+
+	indexes := make(map[string][]types.KeySchemaElement, len(table.GlobalSecondaryIndexes))
+	for _, index := range table.GlobalSecondaryIndexes {
+		indexes[aws.ToString(index.IndexName)] = index.KeySchema
+	}
+	for _, expected := range GenerateTableSchema(tableName, 0, 0).GlobalSecondaryIndexes {
+		indexName := aws.ToString(expected.IndexName)
+		keySchema, ok := indexes[indexName]
+		if !ok {
+			return fmt.Errorf("table %s is missing global secondary index %s", tableName, indexName)
+		}
+		if !equalKeySchema(keySchema, expected.KeySchema) {
+			return fmt.Errorf("global secondary index %s of table %s has unexpected key schema", indexName, tableName)
+		}
+	}
+	return nil
+}
+
+func equalKeySchema(a, b []types.KeySchemaElement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if aws.ToString(a[i].AttributeName) != aws.ToString(b[i].AttributeName) || a[i].KeyType != b[i].KeyType {
+			return false
+		}
+	}
+	return true
+}
+
 func MarshalBlobMetadata(metadata *disperser.BlobMetadata) (commondynamodb.Item, error) {
 	basicFields, err := attributevalue.MarshalMap(metadata)
 	if err != nil {
@@ -318,6 +1052,9 @@ func MarshalBlobMetadata(metadata *disperser.BlobMetadata) (commondynamodb.Item,
 	for k, v := range requestMetadata {
 		basicFields[k] = v
 	}
+	basicFields[requestedAtDayAttribute] = &types.AttributeValueMemberN{
+		Value: strconv.FormatUint(metadata.RequestMetadata.RequestedAt/requestedAtDayDuration, 10),
+	}
 
 	if metadata.ConfirmationInfo == nil {
 		return basicFields, nil
@@ -0,0 +1,658 @@
+package blobstore_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBlobMetadataUploadedBetween(t *testing.T) {
+	ctx := context.Background()
+
+	day := uint64(24 * time.Hour)
+	start := uint64(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	// 12 blobs spread every 8 hours over 4 days, with different statuses
+	statuses := []disperser.BlobStatus{disperser.Processing, disperser.Confirmed, disperser.Failed, disperser.Finalized}
+	for i := 0; i < 12; i++ {
+		requestedAt := start + uint64(i)*day/3
+		metadata := &disperser.BlobMetadata{
+			BlobHash:     fmt.Sprintf("uploaded-blob-%d", i),
+			MetadataHash: fmt.Sprintf("uploaded-metadata-%d", i),
+			BlobStatus:   statuses[i%len(statuses)],
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobSize:    uint(100 + i),
+				RequestedAt: requestedAt,
+			},
+			ConfirmationInfo: &disperser.ConfirmationInfo{},
+		}
+		err := blobMetadataStore.QueueNewBlobMetadata(ctx, metadata)
+		assert.NoError(t, err)
+	}
+
+	// [second blob, tenth blob]
+	since := start + day/3
+	until := start + 9*day/3
+
+	seen := make(map[string]struct{})
+	var lastRequestedAt uint64
+	var totalBytes uint64
+	pageToken := ""
+	numPages := 0
+	for {
+		metadatas, pageInfo, err := blobMetadataStore.GetBlobMetadataUploadedBetween(ctx, since, until, 2, pageToken)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(metadatas), 2)
+		numPages++
+
+		pageBytes := uint64(0)
+		for _, metadata := range metadatas {
+			requestedAt := metadata.RequestMetadata.RequestedAt
+			assert.GreaterOrEqual(t, requestedAt, since)
+			assert.LessOrEqual(t, requestedAt, until)
+			assert.GreaterOrEqual(t, requestedAt, lastRequestedAt)
+			lastRequestedAt = requestedAt
+
+			_, ok := seen[metadata.BlobHash]
+			assert.False(t, ok, "duplicate blob %s", metadata.BlobHash)
+			seen[metadata.BlobHash] = struct{}{}
+			pageBytes += uint64(metadata.RequestMetadata.BlobSize)
+		}
+		assert.Equal(t, pageBytes, pageInfo.PageBytesTotal)
+		totalBytes += pageBytes
+
+		pageToken = pageInfo.NextPageToken
+		if pageToken == "" {
+			break
+		}
+		assert.Less(t, numPages, 10)
+	}
+
+	assert.Len(t, seen, 9)
+	for i := 1; i <= 9; i++ {
+		_, ok := seen[fmt.Sprintf("uploaded-blob-%d", i)]
+		assert.True(t, ok, "missing blob %d", i)
+	}
+	assert.Equal(t, uint64(101+102+103+104+105+106+107+108+109), totalBytes)
+
+	_, _, err := blobMetadataStore.GetBlobMetadataUploadedBetween(ctx, until, since, 2, "")
+	assert.Error(t, err)
+	_, _, err = blobMetadataStore.GetBlobMetadataUploadedBetween(ctx, since, until, 2, "not a token")
+	assert.Error(t, err)
+}
+
+func TestBackfillRequestedAtDay(t *testing.T) {
+	ctx := context.Background()
+
+	// blobs written before the UploadIndex was added, without the RequestedAtDay attribute
+	start := uint64(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	for i := 0; i < 3; i++ {
+		metadata := &disperser.BlobMetadata{
+			BlobHash:     fmt.Sprintf("legacy-blob-%d", i),
+			MetadataHash: fmt.Sprintf("legacy-metadata-%d", i),
+			BlobStatus:   disperser.Finalized,
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobSize:    100,
+				RequestedAt: start + uint64(i)*uint64(time.Hour),
+			},
+		}
+		item, err := blobstore.MarshalBlobMetadata(metadata)
+		assert.NoError(t, err)
+		delete(item, "RequestedAtDay")
+		assert.NoError(t, dynamoClient.PutItem(ctx, metadataTableName, item))
+	}
+
+	until := start + uint64(24*time.Hour)
+	metadatas, _, err := blobMetadataStore.GetBlobMetadataUploadedBetween(ctx, start, until, 10, "")
+	assert.NoError(t, err)
+	assert.Empty(t, metadatas)
+
+	updated, err := blobMetadataStore.BackfillRequestedAtDay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, updated)
+	metadatas, _, err = blobMetadataStore.GetBlobMetadataUploadedBetween(ctx, start, until, 10, "")
+	assert.NoError(t, err)
+	assert.Len(t, metadatas, 3)
+
+	// the backfilled metadata are skipped when it is run again
+	updated, err = blobMetadataStore.BackfillRequestedAtDay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, updated)
+}
+
+func TestGetBlobMetadataByHashPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	for _, hashes := range [][2]string{
+		{"a1b2c3d4-prefix-blob-0", "prefix-metadata-0"},
+		{"a1b2c3d4-prefix-blob-1", "prefix-metadata-1"},
+		{"a1b2c3d4-prefix-blob-2", "prefix-metadata-2"},
+		{"a1b2c3d5-prefix-blob-3", "prefix-metadata-3"},
+		{"ffb2c3d4-prefix-blob-4", "prefix-metadata-4"},
+		// matched by the metadata hash
+		{"prefix-blob-5", "a1b2c3d4-prefix-metadata-5"},
+	} {
+		err := blobMetadataStore.QueueNewBlobMetadata(ctx, &disperser.BlobMetadata{
+			BlobHash:     hashes[0],
+			MetadataHash: hashes[1],
+			BlobStatus:   disperser.Processing,
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobSize:    100,
+				RequestedAt: uint64(time.Now().UnixNano()),
+			},
+		})
+		assert.NoError(t, err)
+	}
+
+	metadatas, err := blobMetadataStore.GetBlobMetadataByHashPrefix(ctx, "a1b2c3d4", 10)
+	assert.NoError(t, err)
+	keys := make([]string, len(metadatas))
+	for i, metadata := range metadatas {
+		keys[i] = metadata.MetadataHash
+	}
+	assert.ElementsMatch(t, []string{"prefix-metadata-0", "prefix-metadata-1", "prefix-metadata-2", "a1b2c3d4-prefix-metadata-5"}, keys)
+
+	metadatas, err = blobMetadataStore.GetBlobMetadataByHashPrefix(ctx, "a1b2c3d4-prefix-blob", 2)
+	assert.NoError(t, err)
+	assert.Len(t, metadatas, 2)
+
+	metadatas, err = blobMetadataStore.GetBlobMetadataByHashPrefix(ctx, "00000000", 10)
+	assert.NoError(t, err)
+	assert.Len(t, metadatas, 0)
+
+	_, err = blobMetadataStore.GetBlobMetadataByHashPrefix(ctx, "a1b2c3d", 10)
+	assert.ErrorIs(t, err, disperser.ErrHashPrefixTooShort)
+}
+
+func TestGetBlobMetadataByStatusPaginated(t *testing.T) {
+	ctx := context.Background()
+
+	numBlobs := 25
+	requestedAt := uint64(time.Now().UnixNano())
+	for i := 0; i < numBlobs; i++ {
+		err := blobMetadataStore.QueueNewBlobMetadata(ctx, &disperser.BlobMetadata{
+			BlobHash:     fmt.Sprintf("paginated-blob-%d", i),
+			MetadataHash: fmt.Sprintf("paginated-metadata-%d", i),
+			BlobStatus:   disperser.InsufficientSignatures,
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobSize:    100,
+				RequestedAt: requestedAt + uint64(i),
+			},
+		})
+		assert.NoError(t, err)
+	}
+
+	// other tests may add blobs of the same status, only the ones of this test are checked
+	paginatedKeys := func(metadatas []*disperser.BlobMetadata) []string {
+		keys := make([]string, 0)
+		for _, metadata := range metadatas {
+			if strings.HasPrefix(metadata.BlobHash, "paginated-blob-") {
+				keys = append(keys, metadata.BlobHash)
+			}
+		}
+		return keys
+	}
+
+	keys := make([]string, 0)
+	numPages := 0
+	var exclusiveStartKey *disperser.BlobStoreExclusiveStartKey
+	for {
+		metadatas, nextKey, err := blobMetadataStore.GetBlobMetadataByStatusPaginated(ctx, disperser.InsufficientSignatures, 10, exclusiveStartKey)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(metadatas), 10)
+		keys = append(keys, paginatedKeys(metadatas)...)
+		numPages++
+		if nextKey == nil {
+			break
+		}
+		assert.Equal(t, disperser.InsufficientSignatures, nextKey.BlobStatus)
+		exclusiveStartKey = nextKey
+	}
+	assert.GreaterOrEqual(t, numPages, 3)
+	expected := make([]string, numBlobs)
+	for i := range expected {
+		expected[i] = fmt.Sprintf("paginated-blob-%d", i)
+	}
+	assert.Equal(t, expected, keys)
+
+	metadatas, err := blobMetadataStore.GetBlobMetadataByStatus(ctx, disperser.InsufficientSignatures)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, paginatedKeys(metadatas))
+
+	_, _, err = blobMetadataStore.GetBlobMetadataByStatusPaginated(ctx, disperser.InsufficientSignatures, 0, nil)
+	assert.Error(t, err)
+}
+
+func TestGetBlobMetadataByStatusAndTimeRange(t *testing.T) {
+	ctx := context.Background()
+
+	// 30 failed blobs, one every minute, and a processing blob in the middle of them, in a year the other tests don't use
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	numBlobs := 30
+	for i := 0; i < numBlobs; i++ {
+		err := blobMetadataStore.QueueNewBlobMetadata(ctx, &disperser.BlobMetadata{
+			BlobHash:     fmt.Sprintf("time-range-blob-%d", i),
+			MetadataHash: fmt.Sprintf("time-range-metadata-%d", i),
+			BlobStatus:   disperser.Failed,
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobSize:    100,
+				RequestedAt: uint64(start.Add(time.Duration(i) * time.Minute).UnixNano()),
+			},
+		})
+		assert.NoError(t, err)
+	}
+	err := blobMetadataStore.QueueNewBlobMetadata(ctx, &disperser.BlobMetadata{
+		BlobHash:     "time-range-processing-blob",
+		MetadataHash: "time-range-processing-metadata",
+		BlobStatus:   disperser.Processing,
+		RequestMetadata: &disperser.RequestMetadata{
+			BlobSize:    100,
+			RequestedAt: uint64(start.Add(10 * time.Minute).UnixNano()),
+		},
+	})
+	assert.NoError(t, err)
+
+	blobHashes := func(metadatas []*disperser.BlobMetadata) []string {
+		hashes := make([]string, len(metadatas))
+		for i, metadata := range metadatas {
+			hashes[i] = metadata.BlobHash
+		}
+		return hashes
+	}
+	expectedHashes := func(first, last int) []string {
+		hashes := make([]string, 0)
+		for i := first; i <= last; i++ {
+			hashes = append(hashes, fmt.Sprintf("time-range-blob-%d", i))
+		}
+		return hashes
+	}
+
+	// the whole range, sorted by request time
+	metadatas, err := blobMetadataStore.GetBlobMetadataByStatusAndTimeRange(ctx, disperser.Failed, start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedHashes(0, numBlobs-1), blobHashes(metadatas))
+
+	// the range overlapping the first blobs, with inclusive bounds
+	metadatas, err = blobMetadataStore.GetBlobMetadataByStatusAndTimeRange(ctx, disperser.Failed, start.Add(-time.Hour), start.Add(4*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedHashes(0, 4), blobHashes(metadatas))
+
+	// a range without blobs, and a single instant
+	metadatas, err = blobMetadataStore.GetBlobMetadataByStatusAndTimeRange(ctx, disperser.Failed, start.Add(-2*time.Hour), start.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, metadatas)
+	metadatas, err = blobMetadataStore.GetBlobMetadataByStatusAndTimeRange(ctx, disperser.Failed, start.Add(7*time.Minute), start.Add(7*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedHashes(7, 7), blobHashes(metadatas))
+
+	// the other statuses aren't returned
+	metadatas, err = blobMetadataStore.GetBlobMetadataByStatusAndTimeRange(ctx, disperser.Processing, start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"time-range-processing-blob"}, blobHashes(metadatas))
+
+	// the results are limited to the earliest blobs
+	limitedStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, metadataTableName, 0, blobstore.WithTimeRangeQueryLimit(12))
+	metadatas, err = limitedStore.GetBlobMetadataByStatusAndTimeRange(ctx, disperser.Failed, start.Add(5*time.Minute), start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedHashes(5, 16), blobHashes(metadatas))
+
+	_, err = blobMetadataStore.GetBlobMetadataByStatusAndTimeRange(ctx, disperser.Failed, start.Add(time.Hour), start)
+	assert.Error(t, err)
+}
+
+func TestIncrementNumRetriesConcurrently(t *testing.T) {
+	ctx := context.Background()
+	maxRetry := uint(3)
+
+	metadata := &disperser.BlobMetadata{
+		BlobHash:     "retried-blob",
+		MetadataHash: "retried-metadata",
+		BlobStatus:   disperser.Processing,
+		NumRetries:   0,
+		RequestMetadata: &disperser.RequestMetadata{
+			BlobSize:    100,
+			RequestedAt: uint64(time.Now().UnixNano()),
+		},
+	}
+	err := blobMetadataStore.QueueNewBlobMetadata(ctx, metadata)
+	assert.NoError(t, err)
+
+	numWorkers := 10
+	errs := make(chan error, numWorkers)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- blobMetadataStore.IncrementNumRetries(ctx, metadata, maxRetry)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	numIncremented := 0
+	for err := range errs {
+		if err == nil {
+			numIncremented++
+			continue
+		}
+		assert.ErrorIs(t, err, disperser.ErrMaxRetriesReached)
+	}
+	assert.Equal(t, int(maxRetry), numIncremented)
+
+	fetched, err := blobMetadataStore.GetBlobMetadata(ctx, metadata.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, maxRetry, fetched.NumRetries)
+}
+
+func TestConditionalUpdateExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := uint64(time.Now().Unix())
+
+	queue := func(name string, expiry uint64) *disperser.BlobMetadata {
+		metadata := &disperser.BlobMetadata{
+			BlobHash:     name + "-blob",
+			MetadataHash: name + "-metadata",
+			BlobStatus:   disperser.Processing,
+			Expiry:       expiry,
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobSize:    100,
+				RequestedAt: uint64(time.Now().UnixNano()),
+			},
+		}
+		assert.NoError(t, blobMetadataStore.QueueNewBlobMetadata(ctx, metadata))
+		return metadata
+	}
+
+	// a blob which didn't expire yet is extended
+	alive := queue("alive", now+60)
+	extended, err := blobMetadataStore.ConditionalUpdateExpiry(ctx, alive.GetBlobKey(), now, now+3600)
+	assert.NoError(t, err)
+	assert.True(t, extended)
+	fetched, err := blobMetadataStore.GetBlobMetadata(ctx, alive.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, now+3600, fetched.Expiry)
+
+	// a blob which expired but wasn't removed by the reaper yet keeps its expiry
+	expired := queue("expired", now-60)
+	extended, err = blobMetadataStore.ConditionalUpdateExpiry(ctx, expired.GetBlobKey(), now, now+3600)
+	assert.NoError(t, err)
+	assert.False(t, extended)
+	fetched, err = blobMetadataStore.GetBlobMetadata(ctx, expired.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, now-60, fetched.Expiry)
+
+	// a blob which was removed isn't recreated
+	removed := disperser.BlobKey{BlobHash: "removed-blob", MetadataHash: "removed-metadata"}
+	extended, err = blobMetadataStore.ConditionalUpdateExpiry(ctx, removed, now, now+3600)
+	assert.NoError(t, err)
+	assert.False(t, extended)
+	item, err := dynamoClient.GetItem(ctx, metadataTableName, commondynamodb.Key{
+		"BlobHash":     &types.AttributeValueMemberS{Value: removed.BlobHash},
+		"MetadataHash": &types.AttributeValueMemberS{Value: removed.MetadataHash},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, item)
+}
+
+func TestTransitionBlobStatus(t *testing.T) {
+	ctx := context.Background()
+	metadata := &disperser.BlobMetadata{
+		BlobHash:     "transitioned-blob",
+		MetadataHash: "transitioned-metadata",
+		BlobStatus:   disperser.Processing,
+		RequestMetadata: &disperser.RequestMetadata{
+			BlobSize:    100,
+			RequestedAt: uint64(time.Now().UnixNano()),
+		},
+	}
+	assert.NoError(t, blobMetadataStore.QueueNewBlobMetadata(ctx, metadata))
+
+	assert.NoError(t, blobMetadataStore.TransitionBlobStatus(ctx, metadata.GetBlobKey(), disperser.Processing, disperser.Failed, "cancelled"))
+	fetched, err := blobMetadataStore.GetBlobMetadata(ctx, metadata.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Failed, fetched.BlobStatus)
+	assert.Equal(t, "cancelled", fetched.BlobStatusHistory[len(fetched.BlobStatusHistory)-1].Reason)
+
+	// the blob is no longer processing
+	err = blobMetadataStore.TransitionBlobStatus(ctx, metadata.GetBlobKey(), disperser.Processing, disperser.Confirmed, "")
+	assert.ErrorIs(t, err, disperser.ErrUnexpectedBlobStatus)
+	fetched, err = blobMetadataStore.GetBlobMetadata(ctx, metadata.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Failed, fetched.BlobStatus)
+
+	// a missing blob isn't created
+	missing := disperser.BlobKey{BlobHash: "missing-blob", MetadataHash: "missing-metadata"}
+	err = blobMetadataStore.TransitionBlobStatus(ctx, missing, disperser.Processing, disperser.Failed, "")
+	assert.ErrorIs(t, err, disperser.ErrUnexpectedBlobStatus)
+	fetched, err = blobMetadataStore.GetBlobMetadata(ctx, missing)
+	assert.NoError(t, err)
+	assert.Empty(t, fetched.MetadataHash)
+}
+
+// mockTransactionWriter records the keys of each transaction
+type mockTransactionWriter struct {
+	mu           sync.Mutex
+	transactions [][]commondynamodb.Key
+	updates      []expression.UpdateBuilder
+}
+
+func (w *mockTransactionWriter) TransactUpdateItemsWithExpression(ctx context.Context, tableName string, keys []commondynamodb.Key, update expression.UpdateBuilder) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.transactions = append(w.transactions, keys)
+	w.updates = append(w.updates, update)
+	return nil
+}
+
+func TestSetBlobStatusInTransactions(t *testing.T) {
+	ctx := context.Background()
+	writer := &mockTransactionWriter{}
+
+	numBlobs := 100
+	metadataKeys := make([]disperser.BlobKey, numBlobs)
+	for i := range metadataKeys {
+		metadataKeys[i] = disperser.BlobKey{
+			BlobHash:     fmt.Sprintf("finalized-blob-%d", i),
+			MetadataHash: fmt.Sprintf("finalized-metadata-%d", i),
+		}
+	}
+	err := blobstore.SetBlobStatusInTransactions(ctx, writer, metadataTableName, metadataKeys, disperser.Finalized)
+	assert.NoError(t, err)
+
+	// ceiling(100/25) transactions instead of 100 updates
+	assert.Len(t, writer.transactions, 4)
+	updated := make(map[string]bool)
+	for i, keys := range writer.transactions {
+		assert.Len(t, keys, 25)
+		// the status is set and appended to the status history
+		expr, err := expression.NewBuilder().WithUpdate(writer.updates[i]).Build()
+		assert.NoError(t, err)
+		assert.Contains(t, *expr.Update(), "list_append")
+		names := make([]string, 0)
+		for _, name := range expr.Names() {
+			names = append(names, name)
+		}
+		assert.ElementsMatch(t, []string{"BlobStatus", "BlobStatusHistory"}, names)
+		values := make([]types.AttributeValue, 0)
+		for _, value := range expr.Values() {
+			values = append(values, value)
+		}
+		assert.Contains(t, values, &types.AttributeValueMemberN{Value: fmt.Sprint(int(disperser.Finalized))})
+		for _, key := range keys {
+			blobHash := key["BlobHash"].(*types.AttributeValueMemberS).Value
+			metadataHash := key["MetadataHash"].(*types.AttributeValueMemberS).Value
+			updated[blobHash+"/"+metadataHash] = true
+		}
+	}
+	assert.Len(t, updated, numBlobs)
+	for _, metadataKey := range metadataKeys {
+		assert.True(t, updated[metadataKey.BlobHash+"/"+metadataKey.MetadataHash])
+	}
+
+	// the last transaction holds the remaining blobs
+	writer = &mockTransactionWriter{}
+	err = blobstore.SetBlobStatusInTransactions(ctx, writer, metadataTableName, metadataKeys[:30], disperser.Finalized)
+	assert.NoError(t, err)
+	assert.Len(t, writer.transactions, 2)
+	assert.Equal(t, 30, len(writer.transactions[0])+len(writer.transactions[1]))
+}
+
+func TestBackupAndRestoreBlobMetadata(t *testing.T) {
+	ctx := context.Background()
+
+	numBlobs := 1000
+	expected := make(map[disperser.BlobKey]*disperser.BlobMetadata, numBlobs)
+	metadatas := make([]*disperser.BlobMetadata, 0, numBlobs)
+	for i := 0; i < numBlobs; i++ {
+		metadata := &disperser.BlobMetadata{
+			BlobHash:     fmt.Sprintf("backup-blob-%d", i),
+			MetadataHash: fmt.Sprintf("backup-metadata-%d", i),
+			BlobStatus:   disperser.Processing,
+			Expiry:       uint64(1_700_000_000 + i),
+			NumRetries:   uint(i % 3),
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobSize:    uint(100 + i),
+				RequestedAt: uint64(time.Now().UnixNano()),
+			},
+		}
+		if i%2 == 0 {
+			metadata.BlobStatus = disperser.Confirmed
+			metadata.ConfirmationInfo = &disperser.ConfirmationInfo{
+				BatchHeaderHash:         [32]byte{byte(i), byte(i >> 8)},
+				BlobIndex:               uint32(i),
+				BlobCount:               uint32(numBlobs),
+				ReferenceBlockNumber:    uint32(10 + i),
+				BatchRoot:               []byte{1, 2, 3},
+				CommitmentRoot:          []byte{4, 5, 6},
+				Length:                  uint32(i),
+				BatchID:                 uint32(i / 10),
+				ConfirmationBlockNumber: uint32(20 + i),
+			}
+		}
+		metadatas = append(metadatas, metadata)
+		expected[metadata.GetBlobKey()] = metadata
+	}
+	err := blobMetadataStore.PutBlobMetadatas(ctx, metadatas)
+	assert.NoError(t, err)
+
+	var backup bytes.Buffer
+	count, err := blobstore.BackupBlobMetadata(ctx, blobMetadataStore, &backup)
+	assert.NoError(t, err)
+	// the table is shared with the other tests
+	assert.GreaterOrEqual(t, count, numBlobs)
+
+	keys := make([]commondynamodb.Key, 0, numBlobs)
+	for key := range expected {
+		keys = append(keys, commondynamodb.Key{
+			"BlobHash":     &types.AttributeValueMemberS{Value: key.BlobHash},
+			"MetadataHash": &types.AttributeValueMemberS{Value: key.MetadataHash},
+		})
+	}
+	failed, err := dynamoClient.DeleteItems(ctx, metadataTableName, keys)
+	assert.NoError(t, err)
+	assert.Len(t, failed, 0)
+
+	restored, err := blobstore.RestoreBlobMetadata(ctx, blobMetadataStore, &backup)
+	assert.NoError(t, err)
+	assert.Equal(t, count, restored)
+
+	seen := 0
+	err = blobMetadataStore.StreamAllMetadata(ctx, func(metadata *disperser.BlobMetadata) error {
+		if want, ok := expected[metadata.GetBlobKey()]; ok {
+			assert.Equal(t, want, metadata)
+			seen++
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, numBlobs, seen)
+
+	// the stream stops at the first error
+	stopErr := errors.New("stop")
+	calls := 0
+	err = blobMetadataStore.StreamAllMetadata(ctx, func(*disperser.BlobMetadata) error {
+		calls++
+		return stopErr
+	})
+	assert.ErrorIs(t, err, stopErr)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBlobMetadataCache(t *testing.T) {
+	ctx := context.Background()
+	metrics := blobstore.NewMetrics(prometheus.NewRegistry(), "test")
+	cachedStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, metadataTableName, 0, blobstore.WithMetadataCache(10, metrics))
+
+	batchHeaderHash := [32]byte{19}
+	confirmed := &disperser.BlobMetadata{
+		BlobHash:        "cached-blob",
+		MetadataHash:    "confirmed",
+		BlobStatus:      disperser.Confirmed,
+		RequestMetadata: &disperser.RequestMetadata{RequestedAt: uint64(time.Now().UnixNano())},
+		ConfirmationInfo: &disperser.ConfirmationInfo{
+			BatchHeaderHash: batchHeaderHash,
+			BlobIndex:       2,
+		},
+	}
+	processing := &disperser.BlobMetadata{
+		BlobHash:        "cached-blob",
+		MetadataHash:    "processing",
+		BlobStatus:      disperser.Processing,
+		RequestMetadata: &disperser.RequestMetadata{RequestedAt: uint64(time.Now().UnixNano())},
+	}
+	assert.NoError(t, cachedStore.QueueNewBlobMetadata(ctx, confirmed))
+	assert.NoError(t, cachedStore.QueueNewBlobMetadata(ctx, processing))
+
+	for _, metadata := range []*disperser.BlobMetadata{confirmed, processing} {
+		read, err := cachedStore.GetBlobMetadata(ctx, metadata.GetBlobKey())
+		assert.NoError(t, err)
+		assert.Equal(t, metadata.BlobStatus, read.BlobStatus)
+	}
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.MetadataCacheMisses))
+
+	// the writes made by another store aren't seen until the cached metadata expire
+	assert.NoError(t, blobMetadataStore.SetBlobStatus(ctx, confirmed.GetBlobKey(), disperser.Finalized, ""))
+	assert.NoError(t, blobMetadataStore.SetBlobStatus(ctx, processing.GetBlobKey(), disperser.Failed, ""))
+	read, err := cachedStore.GetBlobMetadata(ctx, confirmed.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, read.BlobStatus)
+	read, err = cachedStore.GetBlobMetadataInBatch(ctx, batchHeaderHash, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, confirmed.GetBlobKey(), read.GetBlobKey())
+	read, err = cachedStore.GetBlobMetadata(ctx, processing.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Processing, read.BlobStatus)
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.MetadataCacheHits))
+
+	// the metadata of the blobs which aren't finalized expire quickly, confirmed ones included
+	time.Sleep(1100 * time.Millisecond)
+	read, err = cachedStore.GetBlobMetadata(ctx, processing.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Failed, read.BlobStatus)
+	read, err = cachedStore.GetBlobMetadata(ctx, confirmed.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Finalized, read.BlobStatus)
+	read, err = cachedStore.GetBlobMetadataInBatch(ctx, batchHeaderHash, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Finalized, read.BlobStatus)
+
+	// the writes of the store invalidate the cached metadata, e.g. of a blob whose finalization was reorged
+	assert.NoError(t, cachedStore.SetBlobStatus(ctx, confirmed.GetBlobKey(), disperser.Confirmed, ""))
+	read, err = cachedStore.GetBlobMetadata(ctx, confirmed.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, read.BlobStatus)
+}
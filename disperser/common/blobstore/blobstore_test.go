@@ -0,0 +1,104 @@
+package blobstore_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/aws"
+	"github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	test_utils "github.com/0glabs/0g-data-avail/common/aws/dynamodb/utils"
+	"github.com/0glabs/0g-data-avail/common/aws/s3"
+	cmock "github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/0glabs/0g-data-avail/inabox/deploy"
+	"github.com/ory/dockertest/v3"
+)
+
+var (
+	logger = &cmock.Logger{}
+
+	dockertestPool     *dockertest.Pool
+	dockertestResource *dockertest.Resource
+
+	deployLocalStack bool
+	localStackPort   = "4566"
+
+	dynamoClient      *dynamodb.Client
+	s3Client          *s3.Client
+	blobMetadataStore *blobstore.BlobMetadataStore
+
+	metadataTableName    = "test-BlobMetadata"
+	batchHeaderTableName = "test-BatchHeaders"
+	bucketName           = "test-blobstore"
+)
+
+func TestMain(m *testing.M) {
+	setup(m)
+	code := m.Run()
+	teardown()
+	os.Exit(code)
+}
+
+func setup(m *testing.M) {
+
+	deployLocalStack = !(os.Getenv("DEPLOY_LOCALSTACK") == "false")
+	if !deployLocalStack {
+		localStackPort = os.Getenv("LOCALSTACK_PORT")
+	}
+
+	if deployLocalStack {
+		var err error
+		dockertestPool, dockertestResource, err = deploy.StartDockertestWithLocalstackContainer(localStackPort)
+		if err != nil {
+			teardown()
+			panic("failed to start localstack container")
+		}
+	}
+
+	cfg := aws.ClientConfig{
+		Region:          "us-east-1",
+		AccessKey:       "localstack",
+		SecretAccessKey: "localstack",
+		EndpointURL:     fmt.Sprintf("http://0.0.0.0:%s", localStackPort),
+	}
+
+	_, err := test_utils.CreateTable(context.Background(), cfg, metadataTableName, blobstore.GenerateTableSchema(metadataTableName, 10, 10))
+	if err != nil {
+		teardown()
+		panic("failed to create dynamodb table: " + err.Error())
+	}
+
+	_, err = test_utils.CreateTable(context.Background(), cfg, batchHeaderTableName, blobstore.GenerateBatchHeaderTableSchema(batchHeaderTableName, 10, 10))
+	if err != nil {
+		teardown()
+		panic("failed to create dynamodb table: " + err.Error())
+	}
+
+	dynamoClient, err = dynamodb.NewClient(cfg, logger)
+	if err != nil {
+		teardown()
+		panic("failed to create dynamodb client: " + err.Error())
+	}
+
+	blobMetadataStore = blobstore.NewBlobMetadataStore(dynamoClient, logger, metadataTableName, 0)
+
+	s3Client, err = s3.NewClient(cfg, logger)
+	if err != nil {
+		teardown()
+		panic("failed to create s3 client: " + err.Error())
+	}
+
+	err = s3Client.CreateBucket(context.Background(), bucketName, "us-west-2")
+	if err != nil {
+		teardown()
+		panic("failed to create s3 bucket: " + err.Error())
+	}
+}
+
+func teardown() {
+	if deployLocalStack {
+		deploy.PurgeDockertestResources(dockertestPool, dockertestResource)
+	}
+}
@@ -0,0 +1,327 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// defaultMaxBatchSize is the number of buffered metadata writes which triggers a flush, a BatchWriteItem holds up to 25 items
+	defaultMaxBatchSize  = 25
+	defaultFlushInterval = time.Second
+)
+
+// BufferedBlobMetadataStore buffers the writes of whole blob metadata, i.e. QueueNewBlobMetadata, UpdateBlobMetadata and
+// SetBlobStatus of a buffered blob, and writes them once maxBatchSize writes are buffered or every flushInterval. The new
+// blobs are written with BatchWriteItem, while the updates only overwrite the blobs which are still stored, so that a
+// blob removed meanwhile isn't recreated. The other writes are made directly, after the buffered writes of the same
+// blobs are flushed.
+//
+// GetBlobMetadata, GetBlobMetadataByStatus and GetBlobMetadataByStatusPaginated reflect the buffered writes, the other
+// reads only do once they are flushed.
+type BufferedBlobMetadataStore struct {
+	*BlobMetadataStore
+	maxBatchSize  int
+	flushInterval time.Duration
+
+	mu sync.Mutex
+	// pending are the metadata to write of each blob, a buffered write replaces the metadata rather than modifying it
+	pending map[disperser.BlobKey]*pendingMetadata
+	// flushMu serializes the flushes, so the writes of a blob are applied in order
+	flushMu sync.Mutex
+}
+
+// pendingMetadata is the buffered metadata of a blob
+type pendingMetadata struct {
+	metadata *disperser.BlobMetadata
+	// isNew is whether the blob was queued by QueueNewBlobMetadata and isn't stored yet, the other blobs are only
+	// overwritten if they are still stored
+	isNew bool
+}
+
+var _ MetadataStore = (*BufferedBlobMetadataStore)(nil)
+
+// NewBufferedBlobMetadataStore buffers the writes to the store, maxBatchSize defaults to 25 and flushInterval to 1s if not positive.
+// The buffered writes are only flushed periodically once Start is called.
+func NewBufferedBlobMetadataStore(store *BlobMetadataStore, maxBatchSize int, flushInterval time.Duration) *BufferedBlobMetadataStore {
+	if maxBatchSize <= 0 {
+		maxBatchSize = defaultMaxBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+	return &BufferedBlobMetadataStore{
+		BlobMetadataStore: store,
+		maxBatchSize:      maxBatchSize,
+		flushInterval:     flushInterval,
+		pending:           make(map[disperser.BlobKey]*pendingMetadata),
+	}
+}
+
+// Start flushes the buffered writes every flush interval in the background. When the context is done, the buffered
+// writes are flushed a last time and the returned channel is closed.
+func (s *BufferedBlobMetadataStore) Start(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// the context of the writes is done, the remaining writes are drained with their own
+				if err := s.Flush(context.Background()); err != nil {
+					s.logger.Error("[bufferedmetadatastore] failed to drain the buffered writes", "err", err)
+				}
+				return
+			case <-ticker.C:
+				if err := s.Flush(ctx); err != nil {
+					s.logger.Error("[bufferedmetadatastore] failed to flush the buffered writes", "err", err)
+				}
+			}
+		}
+	}()
+	return done
+}
+
+// Flush writes the buffered metadata, the new blobs in batches and the updates one by one in parallel. The metadata
+// which fail to be written stay buffered, while the updates of the blobs which were removed are dropped.
+func (s *BufferedBlobMetadataStore) Flush(ctx context.Context) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	flushing := make(map[disperser.BlobKey]*pendingMetadata, len(s.pending))
+	newMetadatas := make([]*disperser.BlobMetadata, 0, len(s.pending))
+	updates := make([]*disperser.BlobMetadata, 0, len(s.pending))
+	for key, pending := range s.pending {
+		flushing[key] = pending
+		if pending.isNew {
+			newMetadatas = append(newMetadatas, pending.metadata)
+		} else {
+			updates = append(updates, pending.metadata)
+		}
+	}
+	s.mu.Unlock()
+	if len(flushing) == 0 {
+		return nil
+	}
+
+	flushed := make(map[disperser.BlobKey]bool, len(flushing))
+	var flushedMu sync.Mutex
+	group, groupCtx := errgroup.WithContext(ctx)
+	if len(newMetadatas) > 0 {
+		group.Go(func() error {
+			if err := s.BlobMetadataStore.PutBlobMetadatas(groupCtx, newMetadatas); err != nil {
+				return fmt.Errorf("failed to flush %d new blob metadata: %w", len(newMetadatas), err)
+			}
+			flushedMu.Lock()
+			defer flushedMu.Unlock()
+			for _, metadata := range newMetadatas {
+				flushed[metadata.GetBlobKey()] = true
+			}
+			return nil
+		})
+	}
+	for _, metadata := range updates {
+		metadata := metadata
+		group.Go(func() error {
+			err := s.BlobMetadataStore.PutExistingBlobMetadata(groupCtx, metadata)
+			if errors.Is(err, disperser.ErrBlobNotFound) {
+				s.logger.Debug("[bufferedmetadatastore] dropped the buffered update of a removed blob", "key", metadata.GetBlobKey().String())
+				err = nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to flush the metadata of blob %s: %w", metadata.GetBlobKey().String(), err)
+			}
+			flushedMu.Lock()
+			defer flushedMu.Unlock()
+			flushed[metadata.GetBlobKey()] = true
+			return nil
+		})
+	}
+	err := group.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, pending := range flushing {
+		// the blobs written again during the flush stay buffered
+		if flushed[key] && s.pending[key] == pending {
+			delete(s.pending, key)
+		}
+	}
+	return err
+}
+
+// buffer buffers the metadata of the blob and flushes the buffered writes if there are maxBatchSize of them.
+// A blob which isn't stored yet stays new until it is flushed.
+func (s *BufferedBlobMetadataStore) buffer(ctx context.Context, metadata *disperser.BlobMetadata, isNew bool) error {
+	s.mu.Lock()
+	if pending, ok := s.pending[metadata.GetBlobKey()]; ok && pending.isNew {
+		isNew = true
+	}
+	s.pending[metadata.GetBlobKey()] = &pendingMetadata{metadata: metadata, isNew: isNew}
+	full := len(s.pending) >= s.maxBatchSize
+	s.mu.Unlock()
+	if full {
+		return s.Flush(ctx)
+	}
+	return nil
+}
+
+// getPending returns a copy of the buffered metadata of the blob, or nil if it has none
+func (s *BufferedBlobMetadataStore) getPending(key disperser.BlobKey) *disperser.BlobMetadata {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pending, ok := s.pending[key]; ok {
+		return pending.metadata.Clone()
+	}
+	return nil
+}
+
+// flushPending flushes the buffered writes if any of the blobs has one, before a direct write to them
+func (s *BufferedBlobMetadataStore) flushPending(ctx context.Context, keys ...disperser.BlobKey) error {
+	s.mu.Lock()
+	hasPending := false
+	for _, key := range keys {
+		if _, ok := s.pending[key]; ok {
+			hasPending = true
+			break
+		}
+	}
+	s.mu.Unlock()
+	if !hasPending {
+		return nil
+	}
+	return s.Flush(ctx)
+}
+
+func (s *BufferedBlobMetadataStore) QueueNewBlobMetadata(ctx context.Context, blobMetadata *disperser.BlobMetadata) error {
+	return s.buffer(ctx, blobMetadata.Clone(), true)
+}
+
+func (s *BufferedBlobMetadataStore) QueueNewBlobMetadataConditional(ctx context.Context, blobMetadata *disperser.BlobMetadata) (bool, error) {
+	if s.getPending(blobMetadata.GetBlobKey()) != nil {
+		return false, nil
+	}
+	return s.BlobMetadataStore.QueueNewBlobMetadataConditional(ctx, blobMetadata)
+}
+
+func (s *BufferedBlobMetadataStore) UpdateBlobMetadata(ctx context.Context, metadataKey disperser.BlobKey, updated *disperser.BlobMetadata) error {
+	metadata := updated.Clone()
+	metadata.BlobHash = metadataKey.BlobHash
+	metadata.MetadataHash = metadataKey.MetadataHash
+	return s.buffer(ctx, metadata, false)
+}
+
+// SetBlobStatus buffers the status of a buffered blob, the status of the other blobs is updated directly
+func (s *BufferedBlobMetadataStore) SetBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, status disperser.BlobStatus, reason string) error {
+	if metadata := s.getPending(metadataKey); metadata != nil {
+		metadata.BlobStatus = status
+		metadata.AppendStatusEvent(status, reason)
+		return s.buffer(ctx, metadata, false)
+	}
+	return s.BlobMetadataStore.SetBlobStatus(ctx, metadataKey, status, reason)
+}
+
+func (s *BufferedBlobMetadataStore) TransitionBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, from, to disperser.BlobStatus, reason string) error {
+	if err := s.flushPending(ctx, metadataKey); err != nil {
+		return err
+	}
+	return s.BlobMetadataStore.TransitionBlobStatus(ctx, metadataKey, from, to, reason)
+}
+
+func (s *BufferedBlobMetadataStore) GetBlobMetadata(ctx context.Context, metadataKey disperser.BlobKey) (*disperser.BlobMetadata, error) {
+	if metadata := s.getPending(metadataKey); metadata != nil {
+		return metadata, nil
+	}
+	return s.BlobMetadataStore.GetBlobMetadata(ctx, metadataKey)
+}
+
+// GetBlobMetadataByStatus returns the stored metadata with the given status, with the buffered writes applied
+func (s *BufferedBlobMetadataStore) GetBlobMetadataByStatus(ctx context.Context, status disperser.BlobStatus) ([]*disperser.BlobMetadata, error) {
+	stored, err := s.BlobMetadataStore.GetBlobMetadataByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	metadatas := make([]*disperser.BlobMetadata, 0, len(stored))
+	for _, metadata := range stored {
+		// the buffered metadata are added below if they still have the status
+		if _, ok := s.pending[metadata.GetBlobKey()]; !ok {
+			metadatas = append(metadatas, metadata)
+		}
+	}
+	for _, pending := range s.pending {
+		if pending.metadata.BlobStatus == status {
+			metadatas = append(metadatas, pending.metadata.Clone())
+		}
+	}
+	return metadatas, nil
+}
+
+// GetBlobMetadataByStatusPaginated flushes the buffered writes before reading the page, as the position of the buffered
+// blobs in the pages of the stored ones is only known once they are written
+func (s *BufferedBlobMetadataStore) GetBlobMetadataByStatusPaginated(ctx context.Context, status disperser.BlobStatus, pageSize int32, exclusiveStartKey *disperser.BlobStoreExclusiveStartKey) ([]*disperser.BlobMetadata, *disperser.BlobStoreExclusiveStartKey, error) {
+	if err := s.Flush(ctx); err != nil {
+		return nil, nil, err
+	}
+	return s.BlobMetadataStore.GetBlobMetadataByStatusPaginated(ctx, status, pageSize, exclusiveStartKey)
+}
+
+func (s *BufferedBlobMetadataStore) RemoveBlobMetadata(ctx context.Context, blobMetadata *disperser.BlobMetadata) error {
+	if err := s.flushPending(ctx, blobMetadata.GetBlobKey()); err != nil {
+		return err
+	}
+	return s.BlobMetadataStore.RemoveBlobMetadata(ctx, blobMetadata)
+}
+
+func (s *BufferedBlobMetadataStore) SetBlobStatuses(ctx context.Context, metadataKeys []disperser.BlobKey, status disperser.BlobStatus) error {
+	if err := s.flushPending(ctx, metadataKeys...); err != nil {
+		return err
+	}
+	return s.BlobMetadataStore.SetBlobStatuses(ctx, metadataKeys, status)
+}
+
+func (s *BufferedBlobMetadataStore) SetStorageNodeReceipts(ctx context.Context, metadataKey disperser.BlobKey, receipts []disperser.StorageNodeReceipt) error {
+	if err := s.flushPending(ctx, metadataKey); err != nil {
+		return err
+	}
+	return s.BlobMetadataStore.SetStorageNodeReceipts(ctx, metadataKey, receipts)
+}
+
+func (s *BufferedBlobMetadataStore) IncrementNumRetries(ctx context.Context, existingMetadata *disperser.BlobMetadata, maxRetry uint) error {
+	if err := s.flushPending(ctx, existingMetadata.GetBlobKey()); err != nil {
+		return err
+	}
+	return s.BlobMetadataStore.IncrementNumRetries(ctx, existingMetadata, maxRetry)
+}
+
+func (s *BufferedBlobMetadataStore) UpdateBlobMetadataWithoutBatchHeader(ctx context.Context, metadataKey disperser.BlobKey, updated *disperser.BlobMetadata) error {
+	if err := s.flushPending(ctx, metadataKey); err != nil {
+		return err
+	}
+	return s.BlobMetadataStore.UpdateBlobMetadataWithoutBatchHeader(ctx, metadataKey, updated)
+}
+
+func (s *BufferedBlobMetadataStore) UpdateExpiry(ctx context.Context, metadataKey disperser.BlobKey, newExpiry uint64) error {
+	if err := s.flushPending(ctx, metadataKey); err != nil {
+		return err
+	}
+	return s.BlobMetadataStore.UpdateExpiry(ctx, metadataKey, newExpiry)
+}
+
+func (s *BufferedBlobMetadataStore) ConditionalUpdateExpiry(ctx context.Context, metadataKey disperser.BlobKey, minCurrentExpiry, newExpiry uint64) (bool, error) {
+	if err := s.flushPending(ctx, metadataKey); err != nil {
+		return false, err
+	}
+	return s.BlobMetadataStore.ConditionalUpdateExpiry(ctx, metadataKey, minCurrentExpiry, newExpiry)
+}
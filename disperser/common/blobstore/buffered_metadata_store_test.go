@@ -0,0 +1,143 @@
+package blobstore_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBufferedTestMetadata(name string, status disperser.BlobStatus) *disperser.BlobMetadata {
+	return &disperser.BlobMetadata{
+		BlobHash:        "buffered-blob",
+		MetadataHash:    fmt.Sprintf("%s-%d", name, time.Now().UnixNano()),
+		BlobStatus:      status,
+		RequestMetadata: &disperser.RequestMetadata{RequestedAt: uint64(time.Now().UnixNano())},
+	}
+}
+
+func TestBufferedBlobMetadataStore(t *testing.T) {
+	ctx := context.Background()
+	bufferedStore := blobstore.NewBufferedBlobMetadataStore(blobMetadataStore, 3, time.Hour)
+
+	metadata := newBufferedTestMetadata("buffered", disperser.Processing)
+	key := metadata.GetBlobKey()
+	require.NoError(t, bufferedStore.QueueNewBlobMetadata(ctx, metadata))
+	// the write is only buffered, but the reads reflect it
+	stored, err := blobMetadataStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Empty(t, stored.MetadataHash)
+	read, err := bufferedStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Processing, read.BlobStatus)
+
+	assert.NoError(t, bufferedStore.SetBlobStatus(ctx, key, disperser.Confirmed, ""))
+	confirmed, err := bufferedStore.GetBlobMetadataByStatus(ctx, disperser.Confirmed)
+	assert.NoError(t, err)
+	assert.Contains(t, blobKeys(confirmed), key)
+	processing, err := bufferedStore.GetBlobMetadataByStatus(ctx, disperser.Processing)
+	assert.NoError(t, err)
+	assert.NotContains(t, blobKeys(processing), key)
+	ok, err := bufferedStore.QueueNewBlobMetadataConditional(ctx, metadata)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, bufferedStore.Flush(ctx))
+	stored, err = blobMetadataStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, stored.BlobStatus)
+
+	// the writes are flushed once the batch is full
+	keys := make([]disperser.BlobKey, 3)
+	for i := range keys {
+		metadata := newBufferedTestMetadata(fmt.Sprintf("batch%d", i), disperser.Processing)
+		keys[i] = metadata.GetBlobKey()
+		require.NoError(t, bufferedStore.QueueNewBlobMetadata(ctx, metadata))
+	}
+	for _, key := range keys {
+		stored, err := blobMetadataStore.GetBlobMetadata(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, key.MetadataHash, stored.MetadataHash)
+	}
+
+	// the direct writes are made after the buffered writes of the blob
+	metadata = newBufferedTestMetadata("direct", disperser.Processing)
+	key = metadata.GetBlobKey()
+	require.NoError(t, bufferedStore.QueueNewBlobMetadata(ctx, metadata))
+	assert.NoError(t, bufferedStore.SetStorageNodeReceipts(ctx, key, []disperser.StorageNodeReceipt{{NodeID: "node"}}))
+	stored, err = blobMetadataStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, key.MetadataHash, stored.MetadataHash)
+
+	// the buffered writes are drained when the store stops
+	metadata = newBufferedTestMetadata("drained", disperser.Processing)
+	key = metadata.GetBlobKey()
+	startCtx, cancel := context.WithCancel(ctx)
+	done := bufferedStore.Start(startCtx)
+	require.NoError(t, bufferedStore.QueueNewBlobMetadata(ctx, metadata))
+	cancel()
+	<-done
+	stored, err = blobMetadataStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, key.MetadataHash, stored.MetadataHash)
+}
+
+func TestBufferedBlobMetadataStorePaginatedAndRemoved(t *testing.T) {
+	ctx := context.Background()
+	bufferedStore := blobstore.NewBufferedBlobMetadataStore(blobMetadataStore, 25, time.Hour)
+
+	// the pages reflect the buffered writes
+	metadata := newBufferedTestMetadata("paginated", disperser.InsufficientSignatures)
+	key := metadata.GetBlobKey()
+	require.NoError(t, bufferedStore.QueueNewBlobMetadata(ctx, metadata))
+	found := false
+	var startKey *disperser.BlobStoreExclusiveStartKey
+	for {
+		page, nextKey, err := bufferedStore.GetBlobMetadataByStatusPaginated(ctx, disperser.InsufficientSignatures, 10, startKey)
+		require.NoError(t, err)
+		found = found || containsKey(page, key)
+		if nextKey == nil {
+			break
+		}
+		startKey = nextKey
+	}
+	assert.True(t, found)
+
+	// a buffered update of a blob removed meanwhile doesn't recreate it
+	stored, err := blobMetadataStore.GetBlobMetadata(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, key.MetadataHash, stored.MetadataHash)
+	stored.BlobStatus = disperser.Failed
+	require.NoError(t, bufferedStore.UpdateBlobMetadata(ctx, key, stored))
+	require.NoError(t, blobMetadataStore.RemoveBlobMetadata(ctx, stored))
+	assert.NoError(t, bufferedStore.Flush(ctx))
+	removed, err := blobMetadataStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Empty(t, removed.MetadataHash)
+	// the dropped update isn't buffered anymore
+	read, err := bufferedStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Empty(t, read.MetadataHash)
+}
+
+func containsKey(metadatas []*disperser.BlobMetadata, key disperser.BlobKey) bool {
+	for _, metadata := range metadatas {
+		if metadata.GetBlobKey() == key {
+			return true
+		}
+	}
+	return false
+}
+
+func blobKeys(metadatas []*disperser.BlobMetadata) []disperser.BlobKey {
+	keys := make([]disperser.BlobKey, len(metadatas))
+	for i, metadata := range metadatas {
+		keys[i] = metadata.GetBlobKey()
+	}
+	return keys
+}
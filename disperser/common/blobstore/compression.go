@@ -0,0 +1,63 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// ContentEncodingMetadataKey is the user metadata key (x-amz-meta-content-encoding) holding the encoding of the
+	// compressed blob objects
+	ContentEncodingMetadataKey = "content-encoding"
+	zstdEncoding               = "zstd"
+)
+
+var (
+	// zstdMagic is the magic number the zstd frames start with
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	// the encoder and the decoder are safe for concurrent use with EncodeAll and DecodeAll
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// WithCompression compresses the blob data with zstd before it's uploaded. The compressed objects are tagged with the
+// zstd content encoding and decompressed when they are read, the objects stored without compression are still read as
+// they are.
+func WithCompression() SharedStorageOption {
+	return func(s *SharedBlobStore) {
+		s.compressBlobs = true
+	}
+}
+
+// compressObject returns the data to upload and its object metadata. The data is only compressed if compression is
+// enabled and it makes the object smaller.
+func (s *SharedBlobStore) compressObject(data []byte) ([]byte, map[string]string) {
+	if !s.compressBlobs {
+		return data, nil
+	}
+	compressed := zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)))
+	if len(compressed) >= len(data) {
+		return data, nil
+	}
+	s.metrics.observeCompression(len(data) - len(compressed))
+	return compressed, map[string]string{ContentEncodingMetadataKey: zstdEncoding}
+}
+
+// decompressObject decompresses the data of the object if it's tagged with the zstd content encoding. Only the objects
+// starting with the zstd magic number are checked, so the plain objects don't cost an extra request.
+func (s *SharedBlobStore) decompressObject(ctx context.Context, key string, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, zstdMagic) {
+		return data, nil
+	}
+	attributes, err := s.s3Client.HeadObject(ctx, s.bucketName, key)
+	if err != nil {
+		return nil, err
+	}
+	if attributes.MetadataValue(ContentEncodingMetadataKey) != zstdEncoding {
+		return data, nil
+	}
+	return zstdDecoder.DecodeAll(data, nil)
+}
@@ -0,0 +1,65 @@
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DiskCache stores blob contents in files of a local directory, keyed by their S3 object key
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a DiskCache in the given directory, which is created if it doesn't exist
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the blob cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get returns the cached content of the object, and whether it is cached
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Put caches the content of the object. The content is written to a temporary file first, so that readers never
+// see a partially written object.
+func (c *DiskCache) Put(key string, data []byte) error {
+	file, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), c.path(key))
+}
+
+// Delete removes the object from the cache
+func (c *DiskCache) Delete(key string) error {
+	err := os.Remove(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path returns the file of the object, the object keys are hashed as they may contain path separators
+func (c *DiskCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:]))
+}
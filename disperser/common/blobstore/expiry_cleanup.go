@@ -0,0 +1,76 @@
+package blobstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+)
+
+// expiryCleanupPageSize is the number of metadata read at once when looking for the expired blobs
+const expiryCleanupPageSize = 100
+
+// expirableStatuses are the statuses whose index is looked for expired blobs
+var expirableStatuses = []disperser.BlobStatus{
+	disperser.Processing,
+	disperser.Confirmed,
+	disperser.Failed,
+	disperser.Finalized,
+	disperser.InsufficientSignatures,
+}
+
+// StartExpiryCleanup removes the expired blobs every interval in the background, until the context is done
+func (s *SharedBlobStore) StartExpiryCleanup(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				removed, err := s.RemoveExpiredBlobs(ctx, time.Now())
+				if err != nil && ctx.Err() == nil {
+					s.logger.Error("[sharedstorage] failed to clean up the expired blobs", "removed", removed, "err", err)
+					continue
+				}
+				if removed > 0 {
+					s.logger.Info("[sharedstorage] cleaned up the expired blobs", "removed", removed)
+				}
+			}
+		}
+	}()
+}
+
+// RemoveExpiredBlobs removes the blobs with an expiry before now and returns how many were removed. The metadata are
+// read in pages of each status index. A blob which fails to be removed is logged and skipped, the error returned is
+// that of reading the metadata.
+func (s *SharedBlobStore) RemoveExpiredBlobs(ctx context.Context, now time.Time) (int, error) {
+	expiredBefore := uint64(now.Unix())
+	removed := 0
+	for _, status := range expirableStatuses {
+		var startKey *disperser.BlobStoreExclusiveStartKey
+		for {
+			metadatas, nextKey, err := s.blobMetadataStore.GetBlobMetadataByStatusPaginated(ctx, status, expiryCleanupPageSize, startKey)
+			if err != nil {
+				return removed, err
+			}
+			for _, metadata := range metadatas {
+				// blobs without expiry are kept forever
+				if metadata.Expiry == 0 || metadata.Expiry >= expiredBefore {
+					continue
+				}
+				if err := s.RemoveBlob(ctx, metadata); err != nil {
+					s.logger.Warn("[sharedstorage] failed to remove expired blob", "key", metadata.GetBlobKey().String(), "err", err)
+					continue
+				}
+				removed++
+			}
+			if nextKey == nil {
+				break
+			}
+			startKey = nextKey
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,77 @@
+package blobstore_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common/aws"
+	test_utils "github.com/0glabs/0g-data-avail/common/aws/dynamodb/utils"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveExpiredBlobs(t *testing.T) {
+	ctx := context.Background()
+	// the blobs of the other tests must not be cleaned up
+	tableName := "test-ExpiryCleanup"
+	_, err := test_utils.CreateTable(ctx, aws.ClientConfig{
+		Region:          "us-east-1",
+		AccessKey:       "localstack",
+		SecretAccessKey: "localstack",
+		EndpointURL:     fmt.Sprintf("http://0.0.0.0:%s", localStackPort),
+	}, tableName, blobstore.GenerateTableSchema(tableName, 10, 10))
+	require.NoError(t, err)
+	metadataStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, tableName, 0)
+	objects := mock.NewS3Client()
+	sharedStorage := blobstore.NewSharedStorage(bucketName, objects, true, nil, metadataStore, nil, 0, "", nil, logger)
+
+	now := time.Now()
+	expiries := map[string]uint64{
+		"expired":       uint64(now.Add(-time.Hour).Unix()),
+		"not-expired":   uint64(now.Add(time.Hour).Unix()),
+		"never-expires": 0,
+	}
+	statuses := []disperser.BlobStatus{disperser.Processing, disperser.Confirmed, disperser.Finalized}
+	for _, status := range statuses {
+		for name, expiry := range expiries {
+			metadataHash := fmt.Sprintf("%s-%d", name, status)
+			require.NoError(t, metadataStore.QueueNewBlobMetadata(ctx, &disperser.BlobMetadata{
+				BlobHash:        "blob",
+				MetadataHash:    metadataHash,
+				BlobStatus:      status,
+				Expiry:          expiry,
+				RequestMetadata: &disperser.RequestMetadata{RequestedAt: uint64(now.UnixNano())},
+			}))
+			require.NoError(t, objects.UploadObject(ctx, bucketName, metadataHash, []byte("data")))
+		}
+	}
+
+	removed, err := sharedStorage.RemoveExpiredBlobs(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, len(statuses), removed)
+	for _, status := range statuses {
+		for name := range expiries {
+			key := disperser.BlobKey{BlobHash: "blob", MetadataHash: fmt.Sprintf("%s-%d", name, status)}
+			metadata, err := metadataStore.GetBlobMetadata(ctx, key)
+			assert.NoError(t, err)
+			_, downloadErr := objects.DownloadObject(ctx, bucketName, key.MetadataHash)
+			if name == "expired" {
+				assert.Empty(t, metadata.MetadataHash)
+				assert.Error(t, downloadErr)
+			} else {
+				assert.Equal(t, key.MetadataHash, metadata.MetadataHash)
+				assert.NoError(t, downloadErr)
+			}
+		}
+	}
+
+	// the cleanup stops with the context
+	cleanupCtx, cancel := context.WithCancel(ctx)
+	sharedStorage.StartExpiryCleanup(cleanupCtx, time.Millisecond)
+	cancel()
+}
@@ -0,0 +1,550 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// localMetadataFile is the BoltDB file of the metadata in the data directory
+	localMetadataFile = "metadata.db"
+	// localObjectsDir is the directory of the blob contents in the data directory
+	localObjectsDir = "objects"
+)
+
+// localMetadataBucket is the BoltDB bucket of the metadata, keyed by blob key
+var localMetadataBucket = []byte("BlobMetadata")
+
+// LocalBlobStore is a blob store on the local filesystem, an alternative to the SharedBlobStore for development and
+// tests without S3 and DynamoDB. It is not meant to be shared by several processes.
+//
+// The blob contents are files under the data directory, named after the S3 object keys of the SharedBlobStore, and the
+// metadata is stored in a BoltDB file of the data directory. Every metadata update is a BoltDB transaction, so the
+// read-modify-write updates, e.g. IncrementBlobRetryCount, are atomic.
+type LocalBlobStore struct {
+	dataDir               string
+	db                    *bolt.DB
+	metadataHashAsBlobKey bool
+	quorumRetentionDays   map[core.QuorumID]int
+	serializationFormat   core.SerializationFormat
+	logger                common.Logger
+}
+
+var _ disperser.BlobStore = (*LocalBlobStore)(nil)
+
+// LocalBlobStoreOption configures optional features of the LocalBlobStore
+type LocalBlobStoreOption func(*LocalBlobStore)
+
+// WithLocalSerializationFormat writes the blob metadata in the given format, the metadata written in either format are
+// read
+func WithLocalSerializationFormat(format core.SerializationFormat) LocalBlobStoreOption {
+	return func(s *LocalBlobStore) {
+		s.serializationFormat = format
+	}
+}
+
+// NewLocalBlobStore opens the blob store of the data directory, it is created if it doesn't exist.
+// The blobs of quorums without a retention in quorumRetentionDays don't expire.
+func NewLocalBlobStore(dataDir string, metadataHashAsBlobKey bool, quorumRetentionDays map[core.QuorumID]int, logger common.Logger, opts ...LocalBlobStoreOption) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(filepath.Join(dataDir, localObjectsDir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the data directory: %w", err)
+	}
+	db, err := bolt.Open(filepath.Join(dataDir, localMetadataFile), 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the metadata db: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(localMetadataBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the metadata bucket: %w", err)
+	}
+
+	s := &LocalBlobStore{
+		dataDir:               dataDir,
+		db:                    db,
+		metadataHashAsBlobKey: metadataHashAsBlobKey,
+		quorumRetentionDays:   quorumRetentionDays,
+		logger:                logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Close closes the metadata db
+func (s *LocalBlobStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *LocalBlobStore) MetadataHashAsBlobKey() bool {
+	return s.metadataHashAsBlobKey
+}
+
+func (s *LocalBlobStore) StoreBlob(ctx context.Context, blob *core.Blob, requestedAt uint64) (disperser.BlobKey, error) {
+	metadataKey := disperser.BlobKey{}
+	if blob == nil {
+		return metadataKey, errors.New("blob is nil")
+	}
+
+	blobHash := getBlobHash(blob)
+	metadataHash, err := getMetadataHash(requestedAt, blob.RequestHeader.SecurityParams)
+	if err != nil {
+		return metadataKey, err
+	}
+	metadataKey.BlobHash = blobHash
+	metadataKey.MetadataHash = metadataHash
+
+	expiry := uint64(0)
+	if retention := ResolveRetention(s.quorumRetentionDays, blob.RequestHeader.SecurityParams, 0); retention > 0 {
+		expiry = uint64(time.Now().Add(retention).Unix())
+	}
+	metadata := &disperser.BlobMetadata{
+		BlobHash:     blobHash,
+		MetadataHash: metadataHash,
+		BlobStatus:   disperser.Processing,
+		Expiry:       expiry,
+		RequestMetadata: &disperser.RequestMetadata{
+			BlobRequestHeader: blob.RequestHeader,
+			BlobSize:          uint(len(blob.Data)),
+			RequestedAt:       requestedAt,
+		},
+	}
+
+	// the content is written first, so the metadata of a blob always has a content
+	if err := s.writeObject(s.blobKeyObjectKey(metadataKey), blob.Data); err != nil {
+		s.logger.Error("[localstorage] error writing blob", "err", err)
+		return metadataKey, err
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(localMetadataBucket)
+		if bucket.Get([]byte(metadataKey.String())) != nil {
+			s.logger.Debug("[localstorage] blob already stored, skip", "key", metadataKey.String())
+			return nil
+		}
+		return s.putLocalMetadata(bucket, metadata)
+	})
+	return metadataKey, err
+}
+
+func (s *LocalBlobStore) RemoveBlob(ctx context.Context, metadata *disperser.BlobMetadata) error {
+	if err := os.Remove(s.objectPath(s.objectKey(metadata))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(localMetadataBucket).Delete([]byte(metadata.GetBlobKey().String()))
+	})
+}
+
+func (s *LocalBlobStore) GetBlobContent(ctx context.Context, metadata *disperser.BlobMetadata) ([]byte, error) {
+	return s.readObject(s.objectKey(metadata))
+}
+
+// MarkBlobConfirmed sets the confirmation info of the blob and extends its expiry to the retention from now, like
+// SharedBlobStore.MarkBlobConfirmed. A blob which is already confirmed is left as is.
+func (s *LocalBlobStore) MarkBlobConfirmed(ctx context.Context, existingMetadata *disperser.BlobMetadata, confirmationInfo *disperser.ConfirmationInfo) (*disperser.BlobMetadata, error) {
+	var confirmed *disperser.BlobMetadata
+	err := s.update(existingMetadata.GetBlobKey(), func(metadata *disperser.BlobMetadata) error {
+		if alreadyConfirmed, _ := metadata.IsConfirmed(); alreadyConfirmed {
+			confirmed = metadata.Clone()
+			return nil
+		}
+
+		now := time.Now()
+		var retention time.Duration
+		if metadata.RequestMetadata != nil {
+			retention = ResolveRetention(s.quorumRetentionDays, metadata.RequestMetadata.SecurityParams, 0)
+		}
+		ttlFromNow := uint64(now.Add(retention).Unix())
+		if retention > 0 && metadata.Expiry != 0 && metadata.Expiry < ttlFromNow {
+			if metadata.Expiry <= uint64(now.Unix()) {
+				return fmt.Errorf("blob %s expired before it was confirmed: %w", metadata.GetBlobKey().String(), disperser.ErrBlobNotFound)
+			}
+			metadata.Expiry = ttlFromNow
+		}
+		metadata.BlobStatus = disperser.Confirmed
+		metadata.ConfirmationInfo = confirmationInfo.Clone()
+		metadata.AppendStatusEvent(disperser.Confirmed, "")
+		confirmed = metadata.Clone()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return confirmed, nil
+}
+
+func (s *LocalBlobStore) SetStorageNodeReceipts(ctx context.Context, blobKey disperser.BlobKey, receipts []disperser.StorageNodeReceipt) error {
+	return s.update(blobKey, func(metadata *disperser.BlobMetadata) error {
+		if metadata.ConfirmationInfo == nil {
+			return fmt.Errorf("blob %s is not confirmed", blobKey.String())
+		}
+		metadata.ConfirmationInfo.StorageNodeReceipts = receipts
+		return nil
+	})
+}
+
+func (s *LocalBlobStore) MarkBlobFinalized(ctx context.Context, blobKey disperser.BlobKey) error {
+	return s.setBlobStatus(blobKey, disperser.Finalized, "")
+}
+
+// BatchMarkBlobsFinalized marks all the blobs as finalized in a single transaction, none of them is if one isn't found
+func (s *LocalBlobStore) BatchMarkBlobsFinalized(ctx context.Context, blobKeys []disperser.BlobKey) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(localMetadataBucket)
+		for _, blobKey := range blobKeys {
+			err := s.updateLocalMetadata(bucket, blobKey, func(metadata *disperser.BlobMetadata) error {
+				metadata.BlobStatus = disperser.Finalized
+				metadata.AppendStatusEvent(disperser.Finalized, "")
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *LocalBlobStore) UpdateBlobExpiry(ctx context.Context, metadata *disperser.BlobMetadata, newExpiry uint64) error {
+	return s.update(metadata.GetBlobKey(), func(metadata *disperser.BlobMetadata) error {
+		metadata.Expiry = newExpiry
+		return nil
+	})
+}
+
+func (s *LocalBlobStore) MarkBlobProcessing(ctx context.Context, blobKey disperser.BlobKey) error {
+	return s.setBlobStatus(blobKey, disperser.Processing, "")
+}
+
+func (s *LocalBlobStore) MarkBlobFailed(ctx context.Context, blobKey disperser.BlobKey) error {
+	return s.setBlobStatus(blobKey, disperser.Failed, "")
+}
+
+func (s *LocalBlobStore) OverrideBlobStatus(ctx context.Context, blobKey disperser.BlobKey, status disperser.BlobStatus, reason string) error {
+	return s.setBlobStatus(blobKey, status, reason)
+}
+
+func (s *LocalBlobStore) TransitionBlobStatus(ctx context.Context, blobKey disperser.BlobKey, from, to disperser.BlobStatus, reason string) error {
+	return s.update(blobKey, func(metadata *disperser.BlobMetadata) error {
+		if metadata.BlobStatus != from {
+			return disperser.ErrUnexpectedBlobStatus
+		}
+		metadata.BlobStatus = to
+		metadata.AppendStatusEvent(to, reason)
+		return nil
+	})
+}
+
+func (s *LocalBlobStore) IncrementBlobRetryCount(ctx context.Context, existingMetadata *disperser.BlobMetadata, maxRetry uint) error {
+	return s.update(existingMetadata.GetBlobKey(), func(metadata *disperser.BlobMetadata) error {
+		if metadata.NumRetries >= maxRetry {
+			return disperser.ErrMaxRetriesReached
+		}
+		metadata.NumRetries++
+		metadata.AppendStatusEvent(metadata.BlobStatus, disperser.RetryReason(metadata.NumRetries, maxRetry))
+		return nil
+	})
+}
+
+func (s *LocalBlobStore) GetBlobsByMetadata(ctx context.Context, metadata []*disperser.BlobMetadata) (map[disperser.BlobKey]*core.Blob, error) {
+	blobs := make(map[disperser.BlobKey]*core.Blob, len(metadata))
+	for _, m := range metadata {
+		data, err := s.readObject(s.objectKey(m))
+		if err != nil {
+			return nil, err
+		}
+		blobs[m.GetBlobKey()] = &core.Blob{
+			RequestHeader: *m.RequestMetadata.BlobRequestHeader.Clone(),
+			Data:          data,
+		}
+	}
+	return blobs, nil
+}
+
+// PrefetchBlobs is a no-op, the blob contents are already on the local disk
+func (s *LocalBlobStore) PrefetchBlobs(ctx context.Context, keys []disperser.BlobKey) {}
+
+func (s *LocalBlobStore) GetBlobMetadataByStatus(ctx context.Context, blobStatus disperser.BlobStatus) ([]*disperser.BlobMetadata, error) {
+	return s.filter(func(metadata *disperser.BlobMetadata) bool {
+		return metadata.BlobStatus == blobStatus
+	}, 0)
+}
+
+// GetBlobMetadataByStatusPaginated returns the blobs of the status in the order of the status index of the
+// BlobMetadataStore, i.e. by request time, blob hash and metadata hash
+func (s *LocalBlobStore) GetBlobMetadataByStatusPaginated(ctx context.Context, blobStatus disperser.BlobStatus, pageSize int32, exclusiveStartKey *disperser.BlobStoreExclusiveStartKey) ([]*disperser.BlobMetadata, *disperser.BlobStoreExclusiveStartKey, error) {
+	if pageSize <= 0 {
+		return nil, nil, fmt.Errorf("page size must be greater than 0")
+	}
+	metadatas, err := s.GetBlobMetadataByStatus(ctx, blobStatus)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(metadatas, func(i, j int) bool {
+		return compareStatusIndexKeys(localStatusIndexKey(metadatas[i]), localStatusIndexKey(metadatas[j])) < 0
+	})
+
+	start := 0
+	if exclusiveStartKey != nil {
+		start = sort.Search(len(metadatas), func(i int) bool {
+			return compareStatusIndexKeys(localStatusIndexKey(metadatas[i]), exclusiveStartKey) > 0
+		})
+	}
+	end := start + int(pageSize)
+	if end >= len(metadatas) {
+		return metadatas[start:], nil, nil
+	}
+	return metadatas[start:end], localStatusIndexKey(metadatas[end-1]), nil
+}
+
+func (s *LocalBlobStore) GetBlobMetadataByHashPrefix(ctx context.Context, hashPrefix string, limit int) ([]*disperser.BlobMetadata, error) {
+	if len(hashPrefix) < disperser.MinHashPrefixLength {
+		return nil, disperser.ErrHashPrefixTooShort
+	}
+	return s.filter(func(metadata *disperser.BlobMetadata) bool {
+		return strings.HasPrefix(metadata.BlobHash, hashPrefix) || strings.HasPrefix(metadata.MetadataHash, hashPrefix)
+	}, limit)
+}
+
+// GetBlobMetadataByStatusAndTimeRange returns the blobs of the status requested within [from, to], in the order of the
+// status index of the BlobMetadataStore
+func (s *LocalBlobStore) GetBlobMetadataByStatusAndTimeRange(ctx context.Context, blobStatus disperser.BlobStatus, from, to time.Time) ([]*disperser.BlobMetadata, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("invalid time range: from %v is after to %v", from, to)
+	}
+	metadatas, err := s.filter(func(metadata *disperser.BlobMetadata) bool {
+		requestedAt := metadata.RequestMetadata.RequestedAt
+		return metadata.BlobStatus == blobStatus && requestedAt >= uint64(from.UnixNano()) && requestedAt <= uint64(to.UnixNano())
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(metadatas, func(i, j int) bool {
+		return compareStatusIndexKeys(localStatusIndexKey(metadatas[i]), localStatusIndexKey(metadatas[j])) < 0
+	})
+	return metadatas, nil
+}
+
+// GetAllBatches returns the batches of the confirmed blobs by confirmation block, see disperser.PageBatchSummaries
+func (s *LocalBlobStore) GetAllBatches(ctx context.Context, pageSize int, lastBatchHeaderHash *[32]byte) ([]*disperser.BatchSummary, error) {
+	metadatas, err := s.filter(func(metadata *disperser.BlobMetadata) bool {
+		return metadata.ConfirmationInfo != nil
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return disperser.PageBatchSummaries(metadatas, pageSize, lastBatchHeaderHash)
+}
+
+// GetBatchesConfirmedAfter is GetAllBatches for the batches confirmed after the given block only
+func (s *LocalBlobStore) GetBatchesConfirmedAfter(ctx context.Context, blockNumber uint32, pageSize int, lastBatchHeaderHash *[32]byte) ([]*disperser.BatchSummary, error) {
+	metadatas, err := s.filter(func(metadata *disperser.BlobMetadata) bool {
+		return metadata.ConfirmationInfo != nil && metadata.ConfirmationInfo.ConfirmationBlockNumber > blockNumber
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return disperser.PageBatchSummaries(metadatas, pageSize, lastBatchHeaderHash)
+}
+
+func (s *LocalBlobStore) GetMetadataInBatch(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, error) {
+	metadatas, err := s.filter(func(metadata *disperser.BlobMetadata) bool {
+		return metadata.ConfirmationInfo != nil && metadata.ConfirmationInfo.BatchHeaderHash == batchHeaderHash && metadata.ConfirmationInfo.BlobIndex == blobIndex
+	}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadatas) == 0 {
+		return nil, disperser.ErrBlobNotFound
+	}
+	return metadatas[0], nil
+}
+
+func (s *LocalBlobStore) GetBlobMetadataAndContent(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, []byte, error) {
+	metadata, err := s.GetMetadataInBatch(ctx, batchHeaderHash, blobIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := s.GetBlobContent(ctx, metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return metadata, data, nil
+}
+
+func (s *LocalBlobStore) GetAllBlobMetadataByBatch(ctx context.Context, batchHeaderHash [32]byte) ([]*disperser.BlobMetadata, error) {
+	return s.filter(func(metadata *disperser.BlobMetadata) bool {
+		return metadata.ConfirmationInfo != nil && metadata.ConfirmationInfo.BatchHeaderHash == batchHeaderHash
+	}, 0)
+}
+
+func (s *LocalBlobStore) GetBlobMetadata(ctx context.Context, blobKey disperser.BlobKey) (*disperser.BlobMetadata, error) {
+	var metadata *disperser.BlobMetadata
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		metadata, err = getLocalMetadata(tx.Bucket(localMetadataBucket), blobKey)
+		return err
+	})
+	return metadata, err
+}
+
+func (s *LocalBlobStore) HandleBlobFailure(ctx context.Context, metadata *disperser.BlobMetadata, maxRetry uint) error {
+	if metadata.NumRetries < maxRetry {
+		err := s.IncrementBlobRetryCount(ctx, metadata, maxRetry)
+		if !errors.Is(err, disperser.ErrMaxRetriesReached) {
+			return err
+		}
+	}
+	return s.setBlobStatus(metadata.GetBlobKey(), disperser.Failed, disperser.ErrMaxRetriesReached.Error())
+}
+
+func (s *LocalBlobStore) setBlobStatus(blobKey disperser.BlobKey, status disperser.BlobStatus, reason string) error {
+	return s.update(blobKey, func(metadata *disperser.BlobMetadata) error {
+		metadata.BlobStatus = status
+		metadata.AppendStatusEvent(status, reason)
+		return nil
+	})
+}
+
+// update applies fn to the metadata of the blob and stores it, in a single transaction.
+// The metadata is left unchanged if fn returns an error.
+func (s *LocalBlobStore) update(blobKey disperser.BlobKey, fn func(metadata *disperser.BlobMetadata) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return s.updateLocalMetadata(tx.Bucket(localMetadataBucket), blobKey, fn)
+	})
+}
+
+// filter returns the metadata of up to limit blobs matching the predicate, of all of them if limit isn't positive
+func (s *LocalBlobStore) filter(match func(metadata *disperser.BlobMetadata) bool, limit int) ([]*disperser.BlobMetadata, error) {
+	metadatas := make([]*disperser.BlobMetadata, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(localMetadataBucket).Cursor()
+		for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+			metadata, err := new(disperser.BlobMetadata).Deserialize(value)
+			if err != nil {
+				return fmt.Errorf("failed to decode the metadata of blob %s: %w", key, err)
+			}
+			if !match(metadata) {
+				continue
+			}
+			metadatas = append(metadatas, metadata)
+			if limit > 0 && len(metadatas) >= limit {
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metadatas, nil
+}
+
+// objectKey returns the key of the object of the blob, it is the S3 object key of the SharedBlobStore
+func (s *LocalBlobStore) objectKey(metadata *disperser.BlobMetadata) string {
+	return s.blobKeyObjectKey(metadata.GetBlobKey())
+}
+
+func (s *LocalBlobStore) blobKeyObjectKey(blobKey disperser.BlobKey) string {
+	if s.metadataHashAsBlobKey {
+		return blobKey.MetadataHash
+	}
+	return blobObjectKey(blobKey.BlobHash)
+}
+
+func (s *LocalBlobStore) objectPath(key string) string {
+	return filepath.Join(s.dataDir, localObjectsDir, filepath.FromSlash(key))
+}
+
+// writeObject writes the content of an object through a temporary file, so a partially written object is never read
+func (s *LocalBlobStore) writeObject(key string, data []byte) error {
+	path := s.objectPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalBlobStore) readObject(key string) ([]byte, error) {
+	data, err := os.ReadFile(s.objectPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, disperser.ErrBlobNotFound
+	}
+	return data, err
+}
+
+func getLocalMetadata(bucket *bolt.Bucket, blobKey disperser.BlobKey) (*disperser.BlobMetadata, error) {
+	value := bucket.Get([]byte(blobKey.String()))
+	if value == nil {
+		return nil, disperser.ErrBlobNotFound
+	}
+	return new(disperser.BlobMetadata).Deserialize(value)
+}
+
+func (s *LocalBlobStore) putLocalMetadata(bucket *bolt.Bucket, metadata *disperser.BlobMetadata) error {
+	value, err := metadata.SerializeWithFormat(s.serializationFormat)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(metadata.GetBlobKey().String()), value)
+}
+
+func (s *LocalBlobStore) updateLocalMetadata(bucket *bolt.Bucket, blobKey disperser.BlobKey, fn func(metadata *disperser.BlobMetadata) error) error {
+	metadata, err := getLocalMetadata(bucket, blobKey)
+	if err != nil {
+		return err
+	}
+	if err := fn(metadata); err != nil {
+		return err
+	}
+	return s.putLocalMetadata(bucket, metadata)
+}
+
+func localStatusIndexKey(metadata *disperser.BlobMetadata) *disperser.BlobStoreExclusiveStartKey {
+	return &disperser.BlobStoreExclusiveStartKey{
+		BlobHash:     metadata.BlobHash,
+		MetadataHash: metadata.MetadataHash,
+		BlobStatus:   metadata.BlobStatus,
+		RequestedAt:  metadata.RequestMetadata.RequestedAt,
+	}
+}
+
+func compareStatusIndexKeys(a, b *disperser.BlobStoreExclusiveStartKey) int {
+	if a.RequestedAt != b.RequestedAt {
+		if a.RequestedAt < b.RequestedAt {
+			return -1
+		}
+		return 1
+	}
+	if c := strings.Compare(a.BlobHash, b.BlobHash); c != 0 {
+		return c
+	}
+	return strings.Compare(a.MetadataHash, b.MetadataHash)
+}
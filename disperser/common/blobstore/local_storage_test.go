@@ -0,0 +1,166 @@
+package blobstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLocalTestBlob(data string) *core.Blob {
+	return &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0, AdversaryThreshold: 80, QuorumThreshold: 100}},
+		},
+		Data: []byte(data),
+	}
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	store, err := blobstore.NewLocalBlobStore(dataDir, false, map[core.QuorumID]int{0: 1}, logger)
+	require.NoError(t, err)
+
+	blob := newLocalTestBlob("local blob")
+	requestedAt := uint64(time.Now().UnixNano())
+	blobKey, err := store.StoreBlob(ctx, blob, requestedAt)
+	require.NoError(t, err)
+	// storing the same request again is a no-op
+	sameKey, err := store.StoreBlob(ctx, blob, requestedAt)
+	require.NoError(t, err)
+	assert.Equal(t, blobKey, sameKey)
+
+	metadata, err := store.GetBlobMetadata(ctx, blobKey)
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Processing, metadata.BlobStatus)
+	assert.Equal(t, uint(len(blob.Data)), metadata.RequestMetadata.BlobSize)
+	assert.InDelta(t, time.Now().Add(24*time.Hour).Unix(), int64(metadata.Expiry), 5)
+	data, err := store.GetBlobContent(ctx, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, blob.Data, data)
+	blobs, err := store.GetBlobsByMetadata(ctx, []*disperser.BlobMetadata{metadata})
+	require.NoError(t, err)
+	assert.Equal(t, blob.Data, blobs[blobKey].Data)
+	assert.Equal(t, blob.RequestHeader, blobs[blobKey].RequestHeader)
+
+	processing, err := store.GetBlobMetadataByStatus(ctx, disperser.Processing)
+	require.NoError(t, err)
+	assert.Len(t, processing, 1)
+	found, err := store.GetBlobMetadataByHashPrefix(ctx, blobKey.BlobHash[:8], 10)
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// retries are bounded
+	assert.NoError(t, store.IncrementBlobRetryCount(ctx, metadata, 1))
+	assert.ErrorIs(t, store.IncrementBlobRetryCount(ctx, metadata, 1), disperser.ErrMaxRetriesReached)
+
+	batchHeaderHash := [32]byte{1}
+	confirmed, err := store.MarkBlobConfirmed(ctx, metadata, &disperser.ConfirmationInfo{BatchHeaderHash: batchHeaderHash, BlobIndex: 3})
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, confirmed.BlobStatus)
+	assert.Equal(t, uint(1), confirmed.NumRetries)
+	assert.NoError(t, store.SetStorageNodeReceipts(ctx, blobKey, []disperser.StorageNodeReceipt{{NodeID: "node"}}))
+	inBatch, content, err := store.GetBlobMetadataAndContent(ctx, batchHeaderHash, 3)
+	require.NoError(t, err)
+	assert.Equal(t, blobKey, inBatch.GetBlobKey())
+	assert.Equal(t, "node", inBatch.ConfirmationInfo.StorageNodeReceipts[0].NodeID)
+	assert.Equal(t, blob.Data, content)
+	_, err = store.GetMetadataInBatch(ctx, batchHeaderHash, 4)
+	assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+
+	assert.NoError(t, store.BatchMarkBlobsFinalized(ctx, []disperser.BlobKey{blobKey}))
+	assert.ErrorIs(t, store.BatchMarkBlobsFinalized(ctx, []disperser.BlobKey{blobKey, {BlobHash: "missing"}}), disperser.ErrBlobNotFound)
+	metadata, err = store.GetBlobMetadata(ctx, blobKey)
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Finalized, metadata.BlobStatus)
+
+	// the blobs are persisted
+	require.NoError(t, store.Close())
+	store, err = blobstore.NewLocalBlobStore(dataDir, false, nil, logger)
+	require.NoError(t, err)
+	defer store.Close()
+	metadata, err = store.GetBlobMetadata(ctx, blobKey)
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Finalized, metadata.BlobStatus)
+
+	assert.NoError(t, store.RemoveBlob(ctx, metadata))
+	_, err = store.GetBlobMetadata(ctx, blobKey)
+	assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+	_, err = store.GetBlobContent(ctx, metadata)
+	assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+}
+
+func TestLocalBlobStoreStatusPages(t *testing.T) {
+	ctx := context.Background()
+	store, err := blobstore.NewLocalBlobStore(t.TempDir(), true, nil, logger)
+	require.NoError(t, err)
+	defer store.Close()
+
+	keys := make([]disperser.BlobKey, 5)
+	for i := range keys {
+		keys[i], err = store.StoreBlob(ctx, newLocalTestBlob(string(rune('a'+i))), uint64(i+1))
+		require.NoError(t, err)
+	}
+
+	var read []disperser.BlobKey
+	var startKey *disperser.BlobStoreExclusiveStartKey
+	for {
+		page, nextKey, err := store.GetBlobMetadataByStatusPaginated(ctx, disperser.Processing, 2, startKey)
+		require.NoError(t, err)
+		for _, metadata := range page {
+			read = append(read, metadata.GetBlobKey())
+		}
+		if nextKey == nil {
+			break
+		}
+		startKey = nextKey
+	}
+	// in the order of the requests
+	assert.Equal(t, keys, read)
+
+	// the blobs requested within the time range, bounds included
+	inRange, err := store.GetBlobMetadataByStatusAndTimeRange(ctx, disperser.Processing, time.Unix(0, 2), time.Unix(0, 4))
+	require.NoError(t, err)
+	read = read[:0]
+	for _, metadata := range inRange {
+		read = append(read, metadata.GetBlobKey())
+	}
+	assert.Equal(t, keys[1:4], read)
+}
+
+func TestLocalBlobStoreSerializationFormat(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	store, err := blobstore.NewLocalBlobStore(dataDir, false, nil, logger)
+	require.NoError(t, err)
+	gobKey, err := store.StoreBlob(ctx, newLocalTestBlob("gob blob"), 1)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// the metadata written in gob are still read once the format is changed
+	store, err = blobstore.NewLocalBlobStore(dataDir, false, nil, logger, blobstore.WithLocalSerializationFormat(core.JSONSerialization))
+	require.NoError(t, err)
+	defer store.Close()
+	jsonKey, err := store.StoreBlob(ctx, newLocalTestBlob("json blob"), 2)
+	require.NoError(t, err)
+	metadata, err := store.GetBlobMetadata(ctx, jsonKey)
+	require.NoError(t, err)
+	confirmationInfo := &disperser.ConfirmationInfo{BatchHeaderHash: [32]byte{1}, BlobIndex: 1, QuorumResults: map[core.QuorumID]*core.QuorumResult{0: {PercentSigned: 100}}}
+	_, err = store.MarkBlobConfirmed(ctx, metadata, confirmationInfo)
+	require.NoError(t, err)
+	metadata, err = store.GetBlobMetadata(ctx, jsonKey)
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, metadata.BlobStatus)
+	assert.Equal(t, confirmationInfo, metadata.ConfirmationInfo)
+
+	metadata, err = store.GetBlobMetadata(ctx, gobKey)
+	require.NoError(t, err)
+	assert.Equal(t, disperser.Processing, metadata.BlobStatus)
+	assert.NoError(t, store.MarkBlobFinalized(ctx, gobKey))
+}
@@ -0,0 +1,129 @@
+package blobstore
+
+import (
+	"time"
+
+	"github.com/0glabs/0g-data-avail/disperser"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	// mutableMetadataCacheTTL is how long the metadata of blobs which aren't finalized yet are cached
+	mutableMetadataCacheTTL = time.Second
+	// finalizedMetadataCacheTTL is how long the metadata of finalized blobs are cached, they are still written by the
+	// other processes, e.g. when their expiry is extended or their finalization is reorged
+	finalizedMetadataCacheTTL = time.Minute
+)
+
+// BlobMetadataStoreOption configures optional features of the BlobMetadataStore
+type BlobMetadataStoreOption func(*BlobMetadataStore)
+
+// WithMetadataCache caches up to size blob metadata read by GetBlobMetadata and GetBlobMetadataInBatch. The metadata of
+// finalized blobs are cached for a minute, the others for a second, or until they are written by the store. The writes
+// of the other processes are seen once the cached metadata expire. The cache is disabled if size isn't positive.
+func WithMetadataCache(size int, metrics *Metrics) BlobMetadataStoreOption {
+	return func(s *BlobMetadataStore) {
+		if size <= 0 {
+			return
+		}
+		s.cache = newMetadataCache(size, metrics)
+	}
+}
+
+// WithTimeRangeQueryLimit sets the maximum number of metadata returned by GetBlobMetadataByStatusAndTimeRange, the
+// default limit is kept if limit isn't positive
+func WithTimeRangeQueryLimit(limit int) BlobMetadataStoreOption {
+	return func(s *BlobMetadataStore) {
+		if limit <= 0 {
+			return
+		}
+		s.timeRangeQueryLimit = limit
+	}
+}
+
+// metadataCache is an LRU cache of blob metadata, its methods are no-ops on a nil cache
+type metadataCache struct {
+	metadata *lru.Cache[disperser.BlobKey, cachedMetadata]
+	// batchBlobs are the keys of the blobs at each index of the batches, which never change
+	batchBlobs *lru.Cache[batchBlobIndex, disperser.BlobKey]
+	metrics    *Metrics
+}
+
+type cachedMetadata struct {
+	metadata  *disperser.BlobMetadata
+	expiresAt time.Time
+}
+
+type batchBlobIndex struct {
+	batchHeaderHash [32]byte
+	blobIndex       uint32
+}
+
+func newMetadataCache(size int, metrics *Metrics) *metadataCache {
+	// the sizes are positive, so creating the caches can't fail
+	metadata, _ := lru.New[disperser.BlobKey, cachedMetadata](size)
+	batchBlobs, _ := lru.New[batchBlobIndex, disperser.BlobKey](size)
+	return &metadataCache{
+		metadata:   metadata,
+		batchBlobs: batchBlobs,
+		metrics:    metrics,
+	}
+}
+
+// get returns a copy of the cached metadata of the blob
+func (c *metadataCache) get(key disperser.BlobKey) (*disperser.BlobMetadata, bool) {
+	if c == nil {
+		return nil, false
+	}
+	cached, ok := c.metadata.Get(key)
+	if ok && time.Now().After(cached.expiresAt) {
+		c.metadata.Remove(key)
+		ok = false
+	}
+	c.metrics.observeMetadataCache(ok)
+	if !ok {
+		return nil, false
+	}
+	return cached.metadata.Clone(), true
+}
+
+// getInBatch returns a copy of the cached metadata of the blob at the index of the batch
+func (c *metadataCache) getInBatch(batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, bool) {
+	if c == nil {
+		return nil, false
+	}
+	key, ok := c.batchBlobs.Get(batchBlobIndex{batchHeaderHash: batchHeaderHash, blobIndex: blobIndex})
+	if !ok {
+		c.metrics.observeMetadataCache(false)
+		return nil, false
+	}
+	return c.get(key)
+}
+
+func (c *metadataCache) add(metadata *disperser.BlobMetadata) {
+	// an empty metadata is read for a blob which doesn't exist
+	if c == nil || metadata == nil || metadata.MetadataHash == "" {
+		return
+	}
+	ttl := mutableMetadataCacheTTL
+	if metadata.BlobStatus == disperser.Finalized {
+		ttl = finalizedMetadataCacheTTL
+	}
+	cached := cachedMetadata{metadata: metadata.Clone(), expiresAt: time.Now().Add(ttl)}
+	c.metadata.Add(metadata.GetBlobKey(), cached)
+	if metadata.ConfirmationInfo != nil {
+		c.batchBlobs.Add(batchBlobIndex{
+			batchHeaderHash: metadata.ConfirmationInfo.BatchHeaderHash,
+			blobIndex:       metadata.ConfirmationInfo.BlobIndex,
+		}, metadata.GetBlobKey())
+	}
+}
+
+func (c *metadataCache) invalidate(keys ...disperser.BlobKey) {
+	if c == nil {
+		return
+	}
+	for _, key := range keys {
+		c.metadata.Remove(key)
+	}
+}
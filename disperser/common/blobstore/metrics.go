@@ -0,0 +1,190 @@
+package blobstore
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics are the metrics of the SharedBlobStore
+type Metrics struct {
+	S3ConcurrentOperations prometheus.Gauge
+	ShadowUploadFailures   prometheus.Counter
+	ShadowUploadLatency    prometheus.Histogram
+	PrefetchHits           prometheus.Counter
+	PrefetchWasted         prometheus.Counter
+	IntegrityFailures      prometheus.Counter
+	BlobsDeduplicated      prometheus.Counter
+	BytesSavedByDedup      prometheus.Counter
+	MetadataCacheHits      prometheus.Counter
+	MetadataCacheMisses    prometheus.Counter
+	StorageRetries         *prometheus.CounterVec
+	BytesSavedCompression  prometheus.Counter
+}
+
+func NewMetrics(reg prometheus.Registerer, namespace string) *Metrics {
+	return &Metrics{
+		S3ConcurrentOperations: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "s3_concurrent_operations",
+				Help:      "the number of S3 operations in flight fetching blobs",
+			},
+		),
+		ShadowUploadFailures: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "shadow_upload_failures_total",
+				Help:      "the number of blobs which failed to be copied to the shadow bucket",
+			},
+		),
+		ShadowUploadLatency: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "shadow_upload_latency_ms",
+				Help:      "the latency of copying blobs to the shadow bucket in milliseconds",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+			},
+		),
+		PrefetchHits: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "prefetch_hits_total",
+				Help:      "the number of blob contents served from the disk cache after being prefetched",
+			},
+		),
+		PrefetchWasted: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "prefetch_wasted_total",
+				Help:      "the number of prefetched blob contents which were evicted before being read",
+			},
+		),
+		IntegrityFailures: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "blobstore_integrity_failures_total",
+				Help:      "the number of downloaded blob contents which didn't match the blob hash of their metadata",
+			},
+		),
+		BlobsDeduplicated: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "blobs_deduplicated_total",
+				Help:      "the number of stored blobs which weren't uploaded because their content was already stored",
+			},
+		),
+		BytesSavedByDedup: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bytes_saved_by_dedup_total",
+				Help:      "the number of bytes of the stored blobs which weren't uploaded because their content was already stored",
+			},
+		),
+		MetadataCacheHits: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "blobmetadata_cache_hits_total",
+				Help:      "the number of blob metadata reads served from the metadata cache",
+			},
+		),
+		MetadataCacheMisses: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "blobmetadata_cache_misses_total",
+				Help:      "the number of blob metadata reads which weren't in the metadata cache",
+			},
+		),
+		StorageRetries: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "storage_retries_total",
+				Help:      "the number of S3 and DynamoDB requests retried after a transient error, by the attempt which failed",
+			},
+			[]string{"operation", "attempt"},
+		),
+		BytesSavedCompression: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bytes_saved_compression_total",
+				Help:      "the number of bytes not uploaded thanks to the compression of the blobs",
+			},
+		),
+	}
+}
+
+// startS3Operation tracks an S3 operation until the returned function is called, it is a no-op without metrics
+func (m *Metrics) startS3Operation() func() {
+	if m == nil {
+		return func() {}
+	}
+	m.S3ConcurrentOperations.Inc()
+	return m.S3ConcurrentOperations.Dec
+}
+
+// observeShadowUpload records an upload to the shadow bucket, it is a no-op without metrics
+func (m *Metrics) observeShadowUpload(latency time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.ShadowUploadLatency.Observe(float64(latency.Milliseconds()))
+	if err != nil {
+		m.ShadowUploadFailures.Inc()
+	}
+}
+
+// observePrefetch records prefetched blobs being read or wasted, it is a no-op without metrics
+func (m *Metrics) observePrefetch(hits, wasted int) {
+	if m == nil {
+		return
+	}
+	m.PrefetchHits.Add(float64(hits))
+	m.PrefetchWasted.Add(float64(wasted))
+}
+
+// observeIntegrityFailure records a blob content not matching its hash, it is a no-op without metrics
+func (m *Metrics) observeIntegrityFailure() {
+	if m == nil {
+		return
+	}
+	m.IntegrityFailures.Inc()
+}
+
+// observeDeduplicatedBlob records a blob of the given size not being uploaded, it is a no-op without metrics
+func (m *Metrics) observeDeduplicatedBlob(size int) {
+	if m == nil {
+		return
+	}
+	m.BlobsDeduplicated.Inc()
+	m.BytesSavedByDedup.Add(float64(size))
+}
+
+// observeMetadataCache records a blob metadata read hitting the cache or not, it is a no-op without metrics
+func (m *Metrics) observeMetadataCache(hit bool) {
+	if m == nil {
+		return
+	}
+	if hit {
+		m.MetadataCacheHits.Inc()
+	} else {
+		m.MetadataCacheMisses.Inc()
+	}
+}
+
+// observeRetry records the failed attempt of the operation being retried, it is a no-op without metrics
+func (m *Metrics) observeRetry(operation string, attempt int) {
+	if m == nil {
+		return
+	}
+	m.StorageRetries.WithLabelValues(operation, strconv.Itoa(attempt)).Inc()
+}
+
+// observeCompression records the bytes saved by compressing a blob, it is a no-op without metrics
+func (m *Metrics) observeCompression(saved int) {
+	if m == nil {
+		return
+	}
+	m.BytesSavedCompression.Add(float64(saved))
+}
@@ -0,0 +1,82 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const defaultInitialBackoff = 100 * time.Millisecond
+
+// retryableErrorCodes are the codes of the AWS errors returned when a request is throttled or the service is
+// temporarily unavailable
+var retryableErrorCodes = map[string]bool{
+	"RequestTimeout":                         true,
+	"RequestTimeoutException":                true,
+	"ServiceUnavailable":                     true,
+	"InternalError":                          true,
+	"InternalServerError":                    true,
+	"SlowDown":                               true,
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"RequestLimitExceeded":                   true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+}
+
+// IsRetryable returns whether the error of an S3 or DynamoDB request is transient: a 5xx or 429 response, or a
+// throttling error. Client errors, e.g. a failed validation, aren't retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr interface{ ErrorCode() string }
+	if errors.As(err, &apiErr) && retryableErrorCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) {
+		status := responseErr.HTTPStatusCode()
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// WithRetry retries the retryable S3 and DynamoDB requests of the blob store up to maxRetries times, with an
+// exponential backoff starting at initialBackoff, which defaults to 100ms if not positive
+func WithRetry(maxRetries int, initialBackoff time.Duration) SharedStorageOption {
+	return func(s *SharedBlobStore) {
+		if initialBackoff <= 0 {
+			initialBackoff = defaultInitialBackoff
+		}
+		s.maxRetries = maxRetries
+		s.initialBackoff = initialBackoff
+	}
+}
+
+// withRetry calls fn until it succeeds, it fails with an error which isn't retryable or it was retried maxRetries times.
+// The backoff doubles after each attempt, with up to 50% of jitter added.
+func (s *SharedBlobStore) withRetry(ctx context.Context, operation string, fn func() error) error {
+	backoff := s.initialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > s.maxRetries || !IsRetryable(err) {
+			return err
+		}
+		s.logger.Debug("[sharedstorage] retrying storage operation", "operation", operation, "attempt", attempt, "err", err)
+		s.metrics.observeRetry(operation, attempt)
+
+		delay := backoff + time.Duration(rand.Int63n(int64(backoff)/2+1))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,78 @@
+package blobstore_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type apiError struct {
+	code   string
+	status int
+}
+
+func (e *apiError) Error() string       { return e.code }
+func (e *apiError) ErrorCode() string   { return e.code }
+func (e *apiError) HTTPStatusCode() int { return e.status }
+
+// flakyS3Client fails the first downloads with a transient error
+type flakyS3Client struct {
+	*mock.S3Client
+	failures int
+	err      error
+}
+
+func (c *flakyS3Client) DownloadObject(ctx context.Context, bucket string, key string) ([]byte, error) {
+	if c.failures > 0 {
+		c.failures--
+		return nil, c.err
+	}
+	return c.S3Client.DownloadObject(ctx, bucket, key)
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, blobstore.IsRetryable(&apiError{code: "SlowDown", status: http.StatusServiceUnavailable}))
+	assert.True(t, blobstore.IsRetryable(fmt.Errorf("wrapped: %w", &apiError{code: "ProvisionedThroughputExceededException", status: http.StatusBadRequest})))
+	assert.True(t, blobstore.IsRetryable(&apiError{code: "Unknown", status: http.StatusTooManyRequests}))
+	assert.False(t, blobstore.IsRetryable(&apiError{code: "ValidationException", status: http.StatusBadRequest}))
+	assert.False(t, blobstore.IsRetryable(errors.New("not found")))
+	assert.False(t, blobstore.IsRetryable(context.DeadlineExceeded))
+	assert.False(t, blobstore.IsRetryable(nil))
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	ctx := context.Background()
+	objects := &flakyS3Client{S3Client: mock.NewS3Client(), failures: 2, err: &apiError{code: "SlowDown", status: http.StatusServiceUnavailable}}
+	metrics := blobstore.NewMetrics(prometheus.NewRegistry(), "test")
+	sharedStorage := blobstore.NewSharedStorage(bucketName, objects, true, nil, nil, nil, 0, "", metrics, logger, blobstore.WithRetry(2, time.Millisecond))
+
+	metadata := &disperser.BlobMetadata{MetadataHash: "metadata", RequestMetadata: &disperser.RequestMetadata{}}
+	require.NoError(t, objects.UploadObject(ctx, bucketName, metadata.MetadataHash, []byte("blob content")))
+	data, err := sharedStorage.GetBlobContent(ctx, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("blob content"), data)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.StorageRetries.WithLabelValues("DownloadObject", "1")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.StorageRetries.WithLabelValues("DownloadObject", "2")))
+
+	// the retries are bounded
+	objects.failures = 3
+	_, err = sharedStorage.GetBlobContent(ctx, metadata)
+	assert.ErrorIs(t, err, objects.err)
+
+	// the errors which aren't transient aren't retried
+	objects.failures, objects.err = 1, &apiError{code: "AccessDenied", status: http.StatusForbidden}
+	_, err = sharedStorage.GetBlobContent(ctx, metadata)
+	assert.ErrorIs(t, err, objects.err)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.StorageRetries.WithLabelValues("DownloadObject", "1")))
+}
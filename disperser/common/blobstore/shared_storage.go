@@ -6,17 +6,34 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
 	"github.com/0glabs/0g-data-avail/common/aws/s3"
+	"github.com/0glabs/0g-data-avail/common/tracing"
 	"github.com/0glabs/0g-data-avail/core"
 	"github.com/0glabs/0g-data-avail/disperser"
 	"github.com/gammazero/workerpool"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
 	maxS3BlobFetchWorkers = 64
+
+	// shadowUploadTimeout bounds the best-effort uploads to the shadow bucket, which outlive the request
+	shadowUploadTimeout = 30 * time.Second
+
+	defaultPrefetchConcurrency = 8
+
+	// ExpiryTagKey is the tag of the blob objects holding their expiry in unix seconds, once it was extended
+	ExpiryTagKey = "zgda-expiry"
+
+	// defaultStatusResultWarnThreshold is the number of metadata returned by GetBlobMetadataByStatus above which a warning is logged
+	defaultStatusResultWarnThreshold = 10000
 )
 
 // The shared blob store that the disperser is operating on.
@@ -40,18 +57,167 @@ const (
 // See blob_metadata_store.go for more details on BlobMetadataStore.
 type SharedBlobStore struct {
 	bucketName            string
-	s3Client              *s3.Client
-	blobMetadataStore     *BlobMetadataStore
+	shadowBucketName      string
+	s3Client              s3.ObjectStorage
+	blobMetadataStore     MetadataStore
+	batchHeaderStore      *BatchHeaderStore
 	metadataHashAsBlobKey bool
-	logger                common.Logger
+	quorumRetentionDays   map[core.QuorumID]int
+	maxConcurrentUploads  int
+	metrics               *Metrics
+	// uploads coalesces concurrent uploads of the same object
+	uploads singleflight.Group
+	logger  common.Logger
+
+	// diskCache holds the prefetched blob contents, prefetching is disabled if nil
+	diskCache           *DiskCache
+	prefetchConcurrency int
+	prefetchMu          sync.Mutex
+	// prefetched are the object keys being prefetched (false) or prefetched and not read yet (true)
+	prefetched map[string]bool
+
+	// statusResultWarnThreshold is the number of metadata returned by GetBlobMetadataByStatus above which a warning is logged
+	statusResultWarnThreshold int
+
+	// verifyContentHash checks the downloaded blob contents against the blob hash of their metadata
+	verifyContentHash bool
+	// deduplicateBlobs skips the upload of the blobs whose object already exists
+	deduplicateBlobs bool
+
+	// maxRetries is the number of times the retryable S3 and DynamoDB requests are retried, see withRetry
+	maxRetries     int
+	initialBackoff time.Duration
+
+	// compressBlobs compresses the blob data with zstd before it's uploaded
+	compressBlobs bool
+}
+
+// MetadataStore stores the blob metadata of the SharedBlobStore, it is implemented by BlobMetadataStore and
+// BufferedBlobMetadataStore
+type MetadataStore interface {
+	TTL() time.Duration
+	QueueNewBlobMetadataConditional(ctx context.Context, blobMetadata *disperser.BlobMetadata) (bool, error)
+	RemoveBlobMetadata(ctx context.Context, blobMetadata *disperser.BlobMetadata) error
+	GetBlobMetadata(ctx context.Context, metadataKey disperser.BlobKey) (*disperser.BlobMetadata, error)
+	GetBlobMetadataByBlobHash(ctx context.Context, blobHash disperser.BlobHash) ([]*disperser.BlobMetadata, error)
+	GetBlobMetadataByHashPrefix(ctx context.Context, hashPrefix string, limit int) ([]*disperser.BlobMetadata, error)
+	GetBlobMetadataByStatus(ctx context.Context, status disperser.BlobStatus) ([]*disperser.BlobMetadata, error)
+	GetBlobMetadataByStatusPaginated(ctx context.Context, status disperser.BlobStatus, pageSize int32, exclusiveStartKey *disperser.BlobStoreExclusiveStartKey) ([]*disperser.BlobMetadata, *disperser.BlobStoreExclusiveStartKey, error)
+	GetBlobMetadataUploadedBetween(ctx context.Context, since uint64, until uint64, pageSize int, pageToken string) ([]*disperser.BlobMetadata, *BlobPageInfo, error)
+	GetBlobMetadataByStatusAndTimeRange(ctx context.Context, status disperser.BlobStatus, from, to time.Time) ([]*disperser.BlobMetadata, error)
+	GetAllBlobMetadataByBatch(ctx context.Context, batchHeaderHash [32]byte) ([]*disperser.BlobMetadata, error)
+	GetBlobMetadataInBatch(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, error)
+	IncrementNumRetries(ctx context.Context, existingMetadata *disperser.BlobMetadata, maxRetry uint) error
+	ConditionalUpdateExpiry(ctx context.Context, metadataKey disperser.BlobKey, minCurrentExpiry, newExpiry uint64) (bool, error)
+	UpdateExpiry(ctx context.Context, metadataKey disperser.BlobKey, newExpiry uint64) error
+	UpdateBlobMetadata(ctx context.Context, metadataKey disperser.BlobKey, updated *disperser.BlobMetadata) error
+	UpdateBlobMetadataWithoutBatchHeader(ctx context.Context, metadataKey disperser.BlobKey, updated *disperser.BlobMetadata) error
+	SetBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, status disperser.BlobStatus, reason string) error
+	TransitionBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, from, to disperser.BlobStatus, reason string) error
+	SetBlobStatuses(ctx context.Context, metadataKeys []disperser.BlobKey, status disperser.BlobStatus) error
+	SetStorageNodeReceipts(ctx context.Context, metadataKey disperser.BlobKey, receipts []disperser.StorageNodeReceipt) error
+}
+
+// SharedStorageOption configures optional features of the SharedBlobStore
+type SharedStorageOption func(*SharedBlobStore)
+
+// WithDiskCache enables PrefetchBlobs, which downloads blob contents into the cache with at most
+// prefetchConcurrency parallel downloads, it defaults to 8 if not positive
+func WithDiskCache(cache *DiskCache, prefetchConcurrency int) SharedStorageOption {
+	return func(s *SharedBlobStore) {
+		if prefetchConcurrency <= 0 {
+			prefetchConcurrency = defaultPrefetchConcurrency
+		}
+		s.diskCache = cache
+		s.prefetchConcurrency = prefetchConcurrency
+	}
+}
+
+// WithStatusResultWarnThreshold sets the number of metadata returned by GetBlobMetadataByStatus above which a warning is logged,
+// it defaults to 10000 if not positive
+func WithStatusResultWarnThreshold(threshold int) SharedStorageOption {
+	return func(s *SharedBlobStore) {
+		if threshold > 0 {
+			s.statusResultWarnThreshold = threshold
+		}
+	}
+}
+
+// WithContentHashVerification checks the blob contents downloaded by GetBlobContent and GetBlobsByMetadata against
+// the blob hash of their metadata, a content which doesn't match is returned as ErrBlobCorrupted
+func WithContentHashVerification() SharedStorageOption {
+	return func(s *SharedBlobStore) {
+		s.verifyContentHash = true
+	}
+}
+
+// WithBlobDeduplication makes StoreBlob skip the upload of a blob whose object already exists, so the dispersals of the
+// same data share one object. It doesn't apply if the metadata hash is the blob key, the objects are per request then.
+func WithBlobDeduplication() SharedStorageOption {
+	return func(s *SharedBlobStore) {
+		s.deduplicateBlobs = true
+	}
 }
 
 type Config struct {
 	BucketName            string
 	TableName             string
+	BatchHeaderTableName  string
 	MetadataHashAsBlobKey bool
 	InMemory              bool
 	MemoryDBSize          uint64
+	// Local stores the blobs on the local filesystem under DataDir instead of S3 and DynamoDB, see LocalBlobStore
+	Local   bool
+	DataDir string
+	// ShadowBucketName is the bucket blobs are additionally copied to, for disaster recovery. No copy is made if empty.
+	ShadowBucketName string
+	// QuorumRetentionDays is the number of days blobs of each quorum are retained, 0 for no expiry.
+	// Blobs in quorums without a retention use the TTL of the metadata store, and never expire if it is 0.
+	QuorumRetentionDays map[core.QuorumID]int
+	// MaxConcurrentUploads is the maximum number of parallel S3 operations of GetBlobsByMetadata,
+	// it defaults to 64 if not positive
+	MaxConcurrentUploads int
+	// CacheDir is the directory blob contents are prefetched into, prefetching is disabled if empty
+	CacheDir string
+	// PrefetchConcurrency is the maximum number of parallel downloads of PrefetchBlobs, it defaults to 8 if not positive
+	PrefetchConcurrency int
+	// StatusResultWarnThreshold is the number of metadata returned by GetBlobMetadataByStatus above which a warning is logged,
+	// it defaults to 10000 if not positive
+	StatusResultWarnThreshold int
+	// VerifyContentHash checks the downloaded blob contents against the blob hash of their metadata
+	VerifyContentHash bool
+	// DeduplicateBlobs skips the upload of the blobs whose object already exists. It doesn't apply if MetadataHashAsBlobKey
+	// is set, since the object keys then include the request time.
+	DeduplicateBlobs bool
+	// ExpiryCleanupInterval is the interval at which the expired blobs are removed, they are not removed if 0
+	ExpiryCleanupInterval time.Duration
+	// MaxBatchSize is the number of buffered metadata writes which are flushed together, see BufferedBlobMetadataStore.
+	// It defaults to 25 if not positive.
+	MaxBatchSize int
+	// FlushInterval is the interval at which the buffered metadata writes are flushed, the writes aren't buffered if 0
+	FlushInterval time.Duration
+	// MetadataCacheSize is the number of blob metadata cached in memory, the cache is disabled if 0
+	MetadataCacheSize int
+	// TimeRangeQueryLimit is the maximum number of blob metadata returned by a query by status and time range,
+	// it defaults to 10000 if 0
+	TimeRangeQueryLimit int
+	// BackfillUploadIndex sets the RequestedAtDay attribute of the metadata written before the UploadIndex was added at
+	// startup, so that they are returned by the queries by upload time, see BlobMetadataStore.BackfillRequestedAtDay
+	BackfillUploadIndex bool
+	// MultipartThresholdBytes is the size above which blobs are uploaded to S3 in parts of PartSize, with up to
+	// MaxConcurrentParts parts uploaded in parallel. Blobs are uploaded in a single request if 0.
+	MultipartThresholdBytes int64
+	PartSize                int64
+	MaxConcurrentParts      int
+	// MaxRetries is the number of times the S3 and DynamoDB requests failing with a transient error are retried, with an
+	// exponential backoff starting at InitialBackoff
+	MaxRetries     int
+	InitialBackoff time.Duration
+	// CompressBlobs compresses the blob data with zstd before it's uploaded to S3
+	CompressBlobs bool
+	// SerializationFormat is the format the blob metadata are serialized in, "gob" or "json". The metadata serialized
+	// in either format are read, so the format can be changed without migrating the stored metadata.
+	SerializationFormat string
 }
 
 // This represents the s3 fetch result for a blob.
@@ -65,31 +231,79 @@ type blobResultOrError struct {
 	blobRequestHeader core.BlobRequestHeader
 }
 
+// ErrRenameConflict is returned by RenameBlob if an object with the new key already exists
+var ErrRenameConflict = errors.New("an object with the new key of the blob already exists")
+
+// ErrBlobCorrupted is returned when the content hash verification is enabled and a downloaded blob content doesn't
+// match the blob hash of its metadata
+var ErrBlobCorrupted = errors.New("blob content doesn't match its hash")
+
 var _ disperser.BlobStore = (*SharedBlobStore)(nil)
 
-func NewSharedStorage(bucketName string, s3Client *s3.Client, MetadataHashAsBlobKey bool, blobMetadataStore *BlobMetadataStore, logger common.Logger) *SharedBlobStore {
-	return &SharedBlobStore{
+func NewSharedStorage(bucketName string, s3Client s3.ObjectStorage, MetadataHashAsBlobKey bool, quorumRetentionDays map[core.QuorumID]int, blobMetadataStore MetadataStore, batchHeaderStore *BatchHeaderStore, maxConcurrentUploads int, shadowBucketName string, metrics *Metrics, logger common.Logger, opts ...SharedStorageOption) *SharedBlobStore {
+	if maxConcurrentUploads <= 0 {
+		maxConcurrentUploads = maxS3BlobFetchWorkers
+	}
+	s := &SharedBlobStore{
 		bucketName:            bucketName,
+		shadowBucketName:      shadowBucketName,
 		s3Client:              s3Client,
 		blobMetadataStore:     blobMetadataStore,
+		batchHeaderStore:      batchHeaderStore,
 		metadataHashAsBlobKey: MetadataHashAsBlobKey,
+		quorumRetentionDays:   quorumRetentionDays,
+		maxConcurrentUploads:  maxConcurrentUploads,
+		metrics:               metrics,
 		logger:                logger,
+		prefetched:            make(map[string]bool),
+
+		statusResultWarnThreshold: defaultStatusResultWarnThreshold,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *SharedBlobStore) MetadataHashAsBlobKey() bool {
 	return s.metadataHashAsBlobKey
 }
 
+// RemoveBlob removes the metadata and the content of the blob. When the blob hash is the blob key, the dispersals of the
+// same data share its object, which is only deleted along with the metadata of the last of them.
 func (s *SharedBlobStore) RemoveBlob(ctx context.Context, metadata *disperser.BlobMetadata) error {
-	err := s.s3Client.DeleteObject(ctx, s.bucketName, metadata.MetadataHash)
-	if err != nil {
+	if s.metadataHashAsBlobKey {
+		if err := s.s3Client.DeleteObject(ctx, s.bucketName, s.objectKey(metadata)); err != nil {
+			return err
+		}
+		return s.blobMetadataStore.RemoveBlobMetadata(ctx, metadata)
+	}
+
+	// the metadata is removed first, so that a dispersal of the same data stored meanwhile is found below
+	if err := s.blobMetadataStore.RemoveBlobMetadata(ctx, metadata); err != nil {
 		return err
 	}
-	return s.blobMetadataStore.RemoveBlobMetadata(ctx, metadata)
+	dispersals, err := s.blobMetadataStore.GetBlobMetadataByBlobHash(ctx, metadata.BlobHash)
+	if err != nil {
+		return fmt.Errorf("failed to look up the other dispersals of blob %s: %w", metadata.BlobHash, err)
+	}
+	for _, dispersal := range dispersals {
+		if dispersal.MetadataHash != metadata.MetadataHash {
+			s.logger.Debug("[sharedstorage] blob content still referenced, keep it", "key", metadata.GetBlobKey().String(), "referencedBy", dispersal.GetBlobKey().String())
+			return nil
+		}
+	}
+	return s.s3Client.DeleteObject(ctx, s.bucketName, s.objectKey(metadata))
 }
 
 func (s *SharedBlobStore) StoreBlob(ctx context.Context, blob *core.Blob, requestedAt uint64) (disperser.BlobKey, error) {
+	ctx, span := tracing.StartSpan(ctx, "SharedBlobStore.StoreBlob")
+	metadataKey, err := s.storeBlob(ctx, blob, requestedAt)
+	tracing.EndSpan(span, err)
+	return metadataKey, err
+}
+
+func (s *SharedBlobStore) storeBlob(ctx context.Context, blob *core.Blob, requestedAt uint64) (disperser.BlobKey, error) {
 	metadataKey := disperser.BlobKey{}
 	if blob == nil {
 		return metadataKey, errors.New("blob is nil")
@@ -104,20 +318,11 @@ func (s *SharedBlobStore) StoreBlob(ctx context.Context, blob *core.Blob, reques
 	metadataKey.BlobHash = blobHash
 	metadataKey.MetadataHash = metadataHash
 
-	if s.metadataHashAsBlobKey {
-		err = s.s3Client.UploadObject(ctx, s.bucketName, metadataHash, blob.Data)
-	} else {
-		err = s.s3Client.UploadObject(ctx, s.bucketName, blobObjectKey(blobHash), blob.Data)
-	}
-	if err != nil {
-		s.logger.Error("[sharedstorage] error uploading blob", "err", err)
-		return metadataKey, err
-	}
-
-	// don't expire if ttl is 0
+	// don't expire if retention is 0
 	expiry := uint64(0)
-	if s.blobMetadataStore.ttl > 0 {
-		expiry = uint64(time.Now().Add(s.blobMetadataStore.ttl).Unix())
+	retention := ResolveRetention(s.quorumRetentionDays, blob.RequestHeader.SecurityParams, s.blobMetadataStore.TTL())
+	if retention > 0 {
+		expiry = uint64(time.Now().Add(retention).Unix())
 	}
 	metadata := disperser.BlobMetadata{
 		BlobHash:     blobHash,
@@ -131,31 +336,324 @@ func (s *SharedBlobStore) StoreBlob(ctx context.Context, blob *core.Blob, reques
 			RequestedAt:       requestedAt,
 		},
 	}
-	err = s.blobMetadataStore.QueueNewBlobMetadata(ctx, &metadata)
+	// the metadata is stored first, so a blob which is already known isn't uploaded again
+	var stored, failed bool
+	err = s.withRetry(ctx, "QueueNewBlobMetadata", func() error {
+		var err error
+		stored, err = s.blobMetadataStore.QueueNewBlobMetadataConditional(ctx, &metadata)
+		// a failed attempt may have stored the metadata, the blob is uploaded then
+		stored = stored || (err == nil && failed)
+		failed = err != nil
+		return err
+	})
 	if err != nil {
 		s.logger.Error("[sharedstorage] error uploading blob metadata", "err", err)
 		return metadataKey, err
 	}
+	if !stored {
+		s.logger.Debug("[sharedstorage] blob already stored, skip", "key", metadataKey.String())
+		return metadataKey, nil
+	}
+
+	objectKey := blobObjectKey(blobHash)
+	if s.metadataHashAsBlobKey {
+		objectKey = metadataHash
+	} else if s.deduplicateBlobs && s.objectExists(ctx, objectKey) {
+		s.logger.Debug("[sharedstorage] blob content already stored, skip upload", "key", metadataKey.String(), "objectKey", objectKey)
+		s.metrics.observeDeduplicatedBlob(len(blob.Data))
+		return metadataKey, nil
+	}
+	err = s.uploadObject(ctx, objectKey, blob.Data)
+	if err != nil {
+		s.logger.Error("[sharedstorage] error uploading blob", "err", err)
+		// remove the metadata so the blob can be stored again
+		if removeErr := s.blobMetadataStore.RemoveBlobMetadata(ctx, &metadata); removeErr != nil {
+			s.logger.Error("[sharedstorage] error removing blob metadata", "err", removeErr)
+		}
+		return metadataKey, err
+	}
+	if s.shadowBucketName != "" {
+		go s.uploadShadowObject(objectKey, blob.Data)
+	}
 
 	return metadataKey, nil
 }
 
+// uploadObject uploads the blob data unless the object already holds the same data, e.g. when StoreBlob is retried
+// after a partial failure. Concurrent uploads of the same object are coalesced.
+func (s *SharedBlobStore) uploadObject(ctx context.Context, key string, data []byte) error {
+	_, err, _ := s.uploads.Do(key, func() (interface{}, error) {
+		data, metadata := s.compressObject(data)
+		var uploaded bool
+		err := s.withRetry(ctx, "UploadObject", func() error {
+			var err error
+			uploaded, err = s3.UploadObjectIfChangedWithMetadata(ctx, s.s3Client, s.bucketName, key, data, metadata)
+			return err
+		})
+		if err == nil && !uploaded {
+			s.logger.Debug("[sharedstorage] blob already uploaded, skip", "key", key)
+		}
+		return nil, err
+	})
+	return err
+}
+
+// objectExists returns whether the object exists in the bucket. It returns false if that can't be checked, the blob
+// is uploaded then.
+func (s *SharedBlobStore) objectExists(ctx context.Context, key string) bool {
+	_, err := s.s3Client.HeadObject(ctx, s.bucketName, key)
+	if err != nil && !errors.Is(err, s3.ErrObjectNotFound) {
+		s.logger.Warn("[sharedstorage] failed to check if the blob object exists", "key", key, "err", err)
+	}
+	return err == nil
+}
+
+// uploadShadowObject copies the blob data to the shadow bucket. It is best-effort, failures are only logged.
+func (s *SharedBlobStore) uploadShadowObject(key string, data []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), shadowUploadTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.s3Client.UploadObject(ctx, s.shadowBucketName, key, data)
+	s.metrics.observeShadowUpload(time.Since(start), err)
+	if err != nil {
+		s.logger.Warn("[sharedstorage] failed to upload blob to the shadow bucket", "key", key, "err", err)
+	}
+}
+
+// RecoverFromShadow reads the blob content from the shadow bucket, for when the primary bucket is unavailable.
+func (s *SharedBlobStore) RecoverFromShadow(ctx context.Context, blobHash disperser.BlobHash) ([]byte, error) {
+	if s.shadowBucketName == "" {
+		return nil, errors.New("no shadow bucket is configured")
+	}
+	if !s.metadataHashAsBlobKey {
+		return s.s3Client.DownloadObject(ctx, s.shadowBucketName, blobObjectKey(blobHash))
+	}
+
+	metadata, err := s.blobMetadataStore.GetBlobMetadataByBlobHash(ctx, blobHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata) == 0 {
+		return nil, disperser.ErrBlobNotFound
+	}
+	return s.s3Client.DownloadObject(ctx, s.shadowBucketName, metadata[0].MetadataHash)
+}
+
 // GetBlobContent retrieves blob content by the blob key.
 func (s *SharedBlobStore) GetBlobContent(ctx context.Context, metadata *disperser.BlobMetadata) ([]byte, error) {
+	key := s.objectKey(metadata)
+	data, ok := s.readPrefetched(key)
+	if !ok {
+		var err error
+		data, err = s.downloadObject(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.verifyContent(metadata.GetBlobKey(), key, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// downloadObject downloads the object from the bucket, retrying the transient errors, and decompresses it if it was
+// compressed
+func (s *SharedBlobStore) downloadObject(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := s.withRetry(ctx, "DownloadObject", func() error {
+		var err error
+		data, err = s.s3Client.DownloadObject(ctx, s.bucketName, key)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.decompressObject(ctx, key, data)
+}
+
+// verifyContent returns ErrBlobCorrupted if the content hash verification is enabled and the content of the object
+// doesn't match the blob hash of the metadata
+func (s *SharedBlobStore) verifyContent(metadataKey disperser.BlobKey, objectKey string, data []byte) error {
+	if !s.verifyContentHash {
+		return nil
+	}
+	if hash := getBlobHash(&core.Blob{Data: data}); hash != metadataKey.BlobHash {
+		s.logger.Error("[sharedstorage] blob content doesn't match its hash", "metadataKey", metadataKey.String(), "objectKey", objectKey, "contentHash", hash)
+		s.metrics.observeIntegrityFailure()
+		return fmt.Errorf("%w: %s", ErrBlobCorrupted, metadataKey.String())
+	}
+	return nil
+}
+
+// PrefetchBlobs downloads the contents of the given blobs into the disk cache in the background, so that reading
+// them later on, e.g. when assembling the next batch, doesn't wait for S3. A prefetched content is served once and
+// removed from the cache then. The prefetched contents which are not requested again by the next call are
+// considered wasted and removed. It is a no-op if the disk cache isn't enabled.
+func (s *SharedBlobStore) PrefetchBlobs(ctx context.Context, keys []disperser.BlobKey) {
+	if s.diskCache == nil {
+		return
+	}
+
+	objectKeys := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		objectKeys[s.blobKeyObjectKey(key)] = struct{}{}
+	}
+
+	s.prefetchMu.Lock()
+	wasted := 0
+	for key, done := range s.prefetched {
+		if _, ok := objectKeys[key]; ok || !done {
+			continue
+		}
+		delete(s.prefetched, key)
+		wasted++
+		if err := s.diskCache.Delete(key); err != nil {
+			s.logger.Warn("[sharedstorage] failed to remove a prefetched blob from the disk cache", "key", key, "err", err)
+		}
+	}
+	toFetch := make([]string, 0, len(objectKeys))
+	for key := range objectKeys {
+		if _, ok := s.prefetched[key]; !ok {
+			s.prefetched[key] = false
+			toFetch = append(toFetch, key)
+		}
+	}
+	s.prefetchMu.Unlock()
+	s.metrics.observePrefetch(0, wasted)
+
+	pool := workerpool.New(s.prefetchConcurrency)
+	for _, key := range toFetch {
+		key := key
+		pool.Submit(func() {
+			s.prefetchBlob(ctx, key)
+		})
+	}
+	go pool.StopWait()
+}
+
+// prefetchBlob downloads the content of an object into the disk cache
+func (s *SharedBlobStore) prefetchBlob(ctx context.Context, key string) {
+	done := s.metrics.startS3Operation()
+	data, err := s.downloadObject(ctx, key)
+	done()
+	if err == nil {
+		err = s.diskCache.Put(key, data)
+	}
+
+	s.prefetchMu.Lock()
+	defer s.prefetchMu.Unlock()
+	if err != nil {
+		s.logger.Warn("[sharedstorage] failed to prefetch blob", "key", key, "err", err)
+		delete(s.prefetched, key)
+		return
+	}
+	s.prefetched[key] = true
+}
+
+// readPrefetched returns the prefetched content of an object and removes it from the disk cache,
+// ok is false if the object isn't prefetched or is still being downloaded
+func (s *SharedBlobStore) readPrefetched(key string) (data []byte, ok bool) {
+	if s.diskCache == nil {
+		return nil, false
+	}
+	s.prefetchMu.Lock()
+	done := s.prefetched[key]
+	if done {
+		delete(s.prefetched, key)
+	}
+	s.prefetchMu.Unlock()
+	if !done {
+		return nil, false
+	}
+
+	data, ok = s.diskCache.Get(key)
+	if err := s.diskCache.Delete(key); err != nil {
+		s.logger.Warn("[sharedstorage] failed to remove a prefetched blob from the disk cache", "key", key, "err", err)
+	}
+	if ok {
+		s.metrics.observePrefetch(1, 0)
+	}
+	return data, ok
+}
+
+// RenameBlob moves the content of the blob to the given object key, e.g. when migrating between key schemes.
+// The object is copied server side before the old object is deleted, the copy is removed if the old object
+// can't be deleted. It returns ErrRenameConflict without modifying anything if the new key already exists.
+func (s *SharedBlobStore) RenameBlob(ctx context.Context, metadata *disperser.BlobMetadata, newKey string) (err error) {
+	oldKey := s.objectKey(metadata)
+	if oldKey == newKey {
+		return nil
+	}
+
+	_, err = s.s3Client.HeadObject(ctx, s.bucketName, newKey)
+	if err == nil {
+		return ErrRenameConflict
+	}
+	if !errors.Is(err, s3.ErrObjectNotFound) {
+		return fmt.Errorf("failed to check the new key of the blob: %w", err)
+	}
+
+	if err = s.s3Client.CopyObject(ctx, s.bucketName, oldKey, newKey); err != nil {
+		return fmt.Errorf("failed to copy the blob to the new key: %w", err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if cleanupErr := s.s3Client.DeleteObject(ctx, s.bucketName, newKey); cleanupErr != nil {
+			s.logger.Warn("[sharedstorage] failed to remove the copy of a blob which could not be renamed", "key", newKey, "err", cleanupErr)
+		}
+	}()
+
+	if err = s.s3Client.DeleteObject(ctx, s.bucketName, oldKey); err != nil {
+		return fmt.Errorf("failed to delete the old key of the blob: %w", err)
+	}
+	return nil
+}
+
+// objectKey returns the key of the S3 object of the blob
+func (s *SharedBlobStore) objectKey(metadata *disperser.BlobMetadata) string {
+	return s.blobKeyObjectKey(metadata.GetBlobKey())
+}
+
+// blobKeyObjectKey returns the key of the S3 object of the blob with the given key
+func (s *SharedBlobStore) blobKeyObjectKey(blobKey disperser.BlobKey) string {
 	if s.metadataHashAsBlobKey {
-		return s.s3Client.DownloadObject(ctx, s.bucketName, metadata.MetadataHash)
-	} else {
-		return s.s3Client.DownloadObject(ctx, s.bucketName, blobObjectKey(metadata.BlobHash))
+		return blobKey.MetadataHash
+	}
+	return blobObjectKey(blobKey.BlobHash)
+}
+
+// GetBlobContentByBlobHash retrieves blob content by the blob hash. When the metadata hash is used as blob key,
+// the metadata of the blob is looked up first to find the object key.
+func (s *SharedBlobStore) GetBlobContentByBlobHash(ctx context.Context, blobHash disperser.BlobHash) ([]byte, error) {
+	if !s.metadataHashAsBlobKey {
+		return s.downloadObject(ctx, blobObjectKey(blobHash))
+	}
+
+	metadata, err := s.blobMetadataStore.GetBlobMetadataByBlobHash(ctx, blobHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata) == 0 {
+		return nil, disperser.ErrBlobNotFound
 	}
+	// the same blob may be dispersed more than once, all dispersals have the same content
+	return s.downloadObject(ctx, metadata[0].MetadataHash)
 }
 
 func (s *SharedBlobStore) getBlobContentParallel(ctx context.Context, blobKey disperser.BlobKey, blobRequestHeader core.BlobRequestHeader, resultChan chan<- blobResultOrError) {
-	var blob []byte
+	key := s.blobKeyObjectKey(blobKey)
+	blob, ok := s.readPrefetched(key)
 	var err error
-	if s.metadataHashAsBlobKey {
-		blob, err = s.s3Client.DownloadObject(ctx, s.bucketName, blobKey.MetadataHash)
-	} else {
-		blob, err = s.s3Client.DownloadObject(ctx, s.bucketName, blobObjectKey(blobKey.BlobHash))
+	if !ok {
+		done := s.metrics.startS3Operation()
+		blob, err = s.downloadObject(ctx, key)
+		done()
+	}
+	if err == nil {
+		err = s.verifyContent(blobKey, key, blob)
 	}
 	if err != nil {
 		resultChan <- blobResultOrError{err: err}
@@ -165,44 +663,126 @@ func (s *SharedBlobStore) getBlobContentParallel(ctx context.Context, blobKey di
 }
 
 func (s *SharedBlobStore) MarkBlobConfirmed(ctx context.Context, existingMetadata *disperser.BlobMetadata, confirmationInfo *disperser.ConfirmationInfo) (*disperser.BlobMetadata, error) {
-	newMetadata := *existingMetadata
+	newMetadata := existingMetadata.Clone()
 	// Update the TTL if needed
-	ttlFromNow := time.Now().Add(s.blobMetadataStore.ttl)
-	if existingMetadata.Expiry < uint64(ttlFromNow.Unix()) {
+	retention := s.blobMetadataStore.TTL()
+	if existingMetadata.RequestMetadata != nil {
+		retention = ResolveRetention(s.quorumRetentionDays, existingMetadata.RequestMetadata.SecurityParams, s.blobMetadataStore.TTL())
+	}
+	now := time.Now()
+	ttlFromNow := now.Add(retention)
+	// blobs without expiry, i.e. stored with a retention of 0, are kept as they are
+	if retention > 0 && existingMetadata.Expiry != 0 && existingMetadata.Expiry < uint64(ttlFromNow.Unix()) {
+		// the blob may have expired meanwhile, it must not be extended then
+		extended, err := s.blobMetadataStore.ConditionalUpdateExpiry(ctx, existingMetadata.GetBlobKey(), uint64(now.Unix()), uint64(ttlFromNow.Unix()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to extend the expiry of blob %s: %w", existingMetadata.GetBlobKey().String(), err)
+		}
+		if !extended {
+			return nil, fmt.Errorf("blob %s expired before it was confirmed: %w", existingMetadata.GetBlobKey().String(), disperser.ErrBlobNotFound)
+		}
 		newMetadata.Expiry = uint64(ttlFromNow.Unix())
 	}
 	newMetadata.BlobStatus = disperser.Confirmed
 	newMetadata.ConfirmationInfo = confirmationInfo
-	return &newMetadata, s.blobMetadataStore.UpdateBlobMetadata(ctx, existingMetadata.GetBlobKey(), &newMetadata)
+	newMetadata.AppendStatusEvent(disperser.Confirmed, "")
+	if s.batchHeaderStore == nil {
+		return newMetadata, s.withRetry(ctx, "UpdateBlobMetadata", func() error {
+			return s.blobMetadataStore.UpdateBlobMetadata(ctx, existingMetadata.GetBlobKey(), newMetadata)
+		})
+	}
+
+	err := s.batchHeaderStore.PutBatchHeaderIfNotExists(ctx, NewBatchHeaderInfo(confirmationInfo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store batch header: %w", err)
+	}
+	return newMetadata, s.withRetry(ctx, "UpdateBlobMetadata", func() error {
+		return s.blobMetadataStore.UpdateBlobMetadataWithoutBatchHeader(ctx, existingMetadata.GetBlobKey(), newMetadata)
+	})
+}
+
+// UpdateBlobExpiry sets the expiry of the blob. The objects of the blobs keyed by metadata hash are tagged with the new expiry,
+// the objects keyed by blob hash aren't, as they are shared by all the requests of the same blob.
+func (s *SharedBlobStore) UpdateBlobExpiry(ctx context.Context, metadata *disperser.BlobMetadata, newExpiry uint64) error {
+	if err := s.blobMetadataStore.UpdateExpiry(ctx, metadata.GetBlobKey(), newExpiry); err != nil {
+		return err
+	}
+	if !s.metadataHashAsBlobKey {
+		return nil
+	}
+	err := s.s3Client.PutObjectTags(ctx, s.bucketName, metadata.MetadataHash, map[string]string{
+		ExpiryTagKey: strconv.FormatUint(newExpiry, 10),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag the object of blob %s with its expiry: %w", metadata.GetBlobKey().String(), err)
+	}
+	return nil
+}
+
+// populateBatchHeaders fills the batch level confirmation info of the confirmed blobs from the BatchHeaderStore
+func (s *SharedBlobStore) populateBatchHeaders(ctx context.Context, metadatas ...*disperser.BlobMetadata) error {
+	if s.batchHeaderStore == nil {
+		return nil
+	}
+	for _, metadata := range metadatas {
+		// blobs confirmed before the batch header store was enabled have the batch header embedded
+		if metadata == nil || metadata.ConfirmationInfo == nil || len(metadata.ConfirmationInfo.BatchRoot) > 0 {
+			continue
+		}
+		header, err := s.batchHeaderStore.GetBatchHeader(ctx, metadata.ConfirmationInfo.BatchHeaderHash)
+		if err != nil {
+			return err
+		}
+		header.Apply(metadata.ConfirmationInfo)
+	}
+	return nil
+}
+
+func (s *SharedBlobStore) SetStorageNodeReceipts(ctx context.Context, metadataKey disperser.BlobKey, receipts []disperser.StorageNodeReceipt) error {
+	return s.blobMetadataStore.SetStorageNodeReceipts(ctx, metadataKey, receipts)
 }
 
 func (s *SharedBlobStore) MarkBlobFinalized(ctx context.Context, metadataKey disperser.BlobKey) error {
-	return s.blobMetadataStore.SetBlobStatus(ctx, metadataKey, disperser.Finalized)
+	return s.blobMetadataStore.SetBlobStatus(ctx, metadataKey, disperser.Finalized, "")
+}
+
+func (s *SharedBlobStore) BatchMarkBlobsFinalized(ctx context.Context, metadataKeys []disperser.BlobKey) error {
+	return s.blobMetadataStore.SetBlobStatuses(ctx, metadataKeys, disperser.Finalized)
 }
 
 func (s *SharedBlobStore) MarkBlobProcessing(ctx context.Context, metadataKey disperser.BlobKey) error {
-	return s.blobMetadataStore.SetBlobStatus(ctx, metadataKey, disperser.Processing)
+	return s.blobMetadataStore.SetBlobStatus(ctx, metadataKey, disperser.Processing, "")
 }
 
 func (s *SharedBlobStore) MarkBlobFailed(ctx context.Context, metadataKey disperser.BlobKey) error {
-	return s.blobMetadataStore.SetBlobStatus(ctx, metadataKey, disperser.Failed)
+	return s.blobMetadataStore.SetBlobStatus(ctx, metadataKey, disperser.Failed, "")
 }
 
-func (s *SharedBlobStore) IncrementBlobRetryCount(ctx context.Context, existingMetadata *disperser.BlobMetadata) error {
-	return s.blobMetadataStore.IncrementNumRetries(ctx, existingMetadata)
+func (s *SharedBlobStore) OverrideBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, status disperser.BlobStatus, reason string) error {
+	return s.blobMetadataStore.SetBlobStatus(ctx, metadataKey, status, reason)
+}
+
+func (s *SharedBlobStore) TransitionBlobStatus(ctx context.Context, metadataKey disperser.BlobKey, from, to disperser.BlobStatus, reason string) error {
+	return s.blobMetadataStore.TransitionBlobStatus(ctx, metadataKey, from, to, reason)
+}
+
+func (s *SharedBlobStore) IncrementBlobRetryCount(ctx context.Context, existingMetadata *disperser.BlobMetadata, maxRetry uint) error {
+	return s.blobMetadataStore.IncrementNumRetries(ctx, existingMetadata, maxRetry)
 }
 
 func (s *SharedBlobStore) GetBlobsByMetadata(ctx context.Context, metadata []*disperser.BlobMetadata) (map[disperser.BlobKey]*core.Blob, error) {
-	pool := workerpool.New(maxS3BlobFetchWorkers)
+	pool := workerpool.New(s.maxConcurrentUploads)
 	resultChan := make(chan blobResultOrError, len(metadata))
 
 	blobs := make(map[disperser.BlobKey]*core.Blob, 0)
 
 	for _, m := range metadata {
 		mCopy := m // avoid capturing loop variable "m" directly by making a copy
+		// the workers and the returned blobs get their own header, so modifying the security params of one doesn't affect the metadata
+		header := mCopy.RequestMetadata.BlobRequestHeader.Clone()
 		pool.Submit(func() {
 			// Fetch blob content from S3
-			s.getBlobContentParallel(ctx, mCopy.GetBlobKey(), mCopy.RequestMetadata.BlobRequestHeader, resultChan)
+			s.getBlobContentParallel(ctx, mCopy.GetBlobKey(), *header, resultChan)
 		})
 	}
 
@@ -223,29 +803,200 @@ func (s *SharedBlobStore) GetBlobsByMetadata(ctx context.Context, metadata []*di
 	return blobs, nil
 }
 
+// GetBlobMetadataByStatus returns all the metadata with the given status, it should only be used for status with a limited number of blobs.
+// A warning is logged if there are more than the configured threshold, GetBlobMetadataByStatusPaginated should be used instead.
 func (s *SharedBlobStore) GetBlobMetadataByStatus(ctx context.Context, blobStatus disperser.BlobStatus) ([]*disperser.BlobMetadata, error) {
-	return s.blobMetadataStore.GetBlobMetadataByStatus(ctx, blobStatus)
+	metadatas, err := s.blobMetadataStore.GetBlobMetadataByStatus(ctx, blobStatus)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadatas) > s.statusResultWarnThreshold {
+		s.logger.Warn("[blobstore] large number of blobs read by status, use pagination", "status", blobStatus.String(), "numBlobs", len(metadatas), "threshold", s.statusResultWarnThreshold)
+	}
+	return metadatas, s.populateBatchHeaders(ctx, metadatas...)
+}
+
+func (s *SharedBlobStore) GetBlobMetadataByStatusPaginated(ctx context.Context, blobStatus disperser.BlobStatus, pageSize int32, exclusiveStartKey *disperser.BlobStoreExclusiveStartKey) ([]*disperser.BlobMetadata, *disperser.BlobStoreExclusiveStartKey, error) {
+	metadatas, nextKey, err := s.blobMetadataStore.GetBlobMetadataByStatusPaginated(ctx, blobStatus, pageSize, exclusiveStartKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return metadatas, nextKey, s.populateBatchHeaders(ctx, metadatas...)
+}
+
+// GetBlobsUploadedBetween returns a page of the metadata of blobs requested within [since, until] (in nanoseconds) regardless of their status,
+// sorted by request time in ascending order. It is meant for reporting, e.g. billing over a calendar month.
+func (s *SharedBlobStore) GetBlobsUploadedBetween(ctx context.Context, since, until uint64, pageSize int, pageToken string) ([]*disperser.BlobMetadata, *BlobPageInfo, error) {
+	metadatas, pageInfo, err := s.blobMetadataStore.GetBlobMetadataUploadedBetween(ctx, since, until, pageSize, pageToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return metadatas, pageInfo, s.populateBatchHeaders(ctx, metadatas...)
+}
+
+// GetBlobMetadataByHashPrefix returns the metadata of up to limit blobs whose blob hash or metadata hash starts with
+// the given prefix, see BlobMetadataStore.GetBlobMetadataByHashPrefix
+func (s *SharedBlobStore) GetBlobMetadataByHashPrefix(ctx context.Context, hashPrefix string, limit int) ([]*disperser.BlobMetadata, error) {
+	metadatas, err := s.blobMetadataStore.GetBlobMetadataByHashPrefix(ctx, hashPrefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	return metadatas, s.populateBatchHeaders(ctx, metadatas...)
+}
+
+// GetBlobMetadataByStatusAndTimeRange returns the metadata of the blobs with the given status requested within [from, to],
+// see BlobMetadataStore.GetBlobMetadataByStatusAndTimeRange
+func (s *SharedBlobStore) GetBlobMetadataByStatusAndTimeRange(ctx context.Context, status disperser.BlobStatus, from, to time.Time) ([]*disperser.BlobMetadata, error) {
+	metadatas, err := s.blobMetadataStore.GetBlobMetadataByStatusAndTimeRange(ctx, status, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return metadatas, s.populateBatchHeaders(ctx, metadatas...)
+}
+
+// GetAllBatches returns the batches ordered by confirmation block, from the batch headers stored when confirming the
+// blobs, see BatchHeaderStore.GetBatchHeaders
+func (s *SharedBlobStore) GetAllBatches(ctx context.Context, pageSize int, lastBatchHeaderHash *[32]byte) ([]*disperser.BatchSummary, error) {
+	if s.batchHeaderStore == nil {
+		return nil, disperser.ErrBatchListingUnavailable
+	}
+	headers, err := s.batchHeaderStore.GetBatchHeaders(ctx, pageSize, lastBatchHeaderHash)
+	if err != nil {
+		return nil, err
+	}
+	return batchSummaries(headers), nil
+}
+
+// GetBatchesConfirmedAfter is GetAllBatches for the batches confirmed after the given block only, see
+// BatchHeaderStore.GetBatchHeadersConfirmedAfter
+func (s *SharedBlobStore) GetBatchesConfirmedAfter(ctx context.Context, blockNumber uint32, pageSize int, lastBatchHeaderHash *[32]byte) ([]*disperser.BatchSummary, error) {
+	if s.batchHeaderStore == nil {
+		return nil, disperser.ErrBatchListingUnavailable
+	}
+	headers, err := s.batchHeaderStore.GetBatchHeadersConfirmedAfter(ctx, blockNumber, pageSize, lastBatchHeaderHash)
+	if err != nil {
+		return nil, err
+	}
+	return batchSummaries(headers), nil
+}
+
+// batchSummaries summarizes the batches of the batch headers
+func batchSummaries(headers []*BatchHeaderInfo) []*disperser.BatchSummary {
+	batches := make([]*disperser.BatchSummary, len(headers))
+	for i, header := range headers {
+		batches[i] = &disperser.BatchSummary{
+			BatchHeaderHash:         header.BatchHeaderHash,
+			ConfirmationBlockNumber: header.ConfirmationBlockNumber,
+			BlobCount:               header.BlobCount,
+			TotalBytes:              header.BatchSize,
+		}
+	}
+	return batches
 }
 
 func (s *SharedBlobStore) GetMetadataInBatch(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, error) {
-	return s.blobMetadataStore.GetBlobMetadataInBatch(ctx, batchHeaderHash, blobIndex)
+	metadata, err := s.blobMetadataStore.GetBlobMetadataInBatch(ctx, batchHeaderHash, blobIndex)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, s.populateBatchHeaders(ctx, metadata)
+}
+
+// GetBlobMetadataAndContent returns the metadata of the blob at the given index of the batch and its content.
+// The S3 object key is derived from the metadata, so the content is downloaded while the batch header
+// of the metadata is read from the BatchHeaderStore rather than after it.
+func (s *SharedBlobStore) GetBlobMetadataAndContent(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, []byte, error) {
+	metadata, err := s.blobMetadataStore.GetBlobMetadataInBatch(ctx, batchHeaderHash, blobIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var data []byte
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		return s.populateBatchHeaders(groupCtx, metadata)
+	})
+	group.Go(func() error {
+		var err error
+		data, err = s.GetBlobContent(groupCtx, metadata)
+		return err
+	})
+	if err := group.Wait(); err != nil {
+		return nil, nil, err
+	}
+	return metadata, data, nil
 }
 
 func (s *SharedBlobStore) GetAllBlobMetadataByBatch(ctx context.Context, batchHeaderHash [32]byte) ([]*disperser.BlobMetadata, error) {
-	return s.blobMetadataStore.GetAllBlobMetadataByBatch(ctx, batchHeaderHash)
+	metadatas, err := s.blobMetadataStore.GetAllBlobMetadataByBatch(ctx, batchHeaderHash)
+	if err != nil {
+		return nil, err
+	}
+	return metadatas, s.populateBatchHeaders(ctx, metadatas...)
 }
 
 // GetMetadata returns a blob metadata given a metadata key
 func (s *SharedBlobStore) GetBlobMetadata(ctx context.Context, metadataKey disperser.BlobKey) (*disperser.BlobMetadata, error) {
-	return s.blobMetadataStore.GetBlobMetadata(ctx, metadataKey)
+	metadata, err := s.blobMetadataStore.GetBlobMetadata(ctx, metadataKey)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, s.populateBatchHeaders(ctx, metadata)
 }
 
 func (s *SharedBlobStore) HandleBlobFailure(ctx context.Context, metadata *disperser.BlobMetadata, maxRetry uint) error {
 	if metadata.NumRetries < maxRetry {
-		return s.IncrementBlobRetryCount(ctx, metadata)
-	} else {
-		return s.MarkBlobFailed(ctx, metadata.GetBlobKey())
+		err := s.IncrementBlobRetryCount(ctx, metadata, maxRetry)
+		if !errors.Is(err, disperser.ErrMaxRetriesReached) {
+			return err
+		}
+		// another batcher has already used up the remaining retries
+	}
+	return s.blobMetadataStore.SetBlobStatus(ctx, metadata.GetBlobKey(), disperser.Failed, disperser.ErrMaxRetriesReached.Error())
+}
+
+// ResolveRetention returns how long a blob is retained, which is the longest retention across its quorums.
+// The quorums without a configured retention are retained for defaultRetention, and a retention of 0 means the blob
+// never expires, so it outlasts any other. It returns 0 if the blob never expires.
+func ResolveRetention(quorumRetentionDays map[core.QuorumID]int, securityParams []*core.SecurityParam, defaultRetention time.Duration) time.Duration {
+	if len(securityParams) == 0 {
+		return defaultRetention
+	}
+	var maxRetention time.Duration
+	for _, param := range securityParams {
+		retention := defaultRetention
+		if days, ok := quorumRetentionDays[param.QuorumID]; ok {
+			retention = time.Duration(days) * 24 * time.Hour
+		}
+		if retention == 0 {
+			return 0
+		}
+		if retention > maxRetention {
+			maxRetention = retention
+		}
+	}
+	return maxRetention
+}
+
+// ParseQuorumRetentionDays parses quorum retentions in the form of "quorumID:days"
+func ParseQuorumRetentionDays(retentions []string) (map[core.QuorumID]int, error) {
+	quorumRetentionDays := make(map[core.QuorumID]int, len(retentions))
+	for _, retention := range retentions {
+		parts := strings.Split(retention, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid quorum retention %q, expected quorumID:days", retention)
+		}
+		quorumID, err := strconv.ParseUint(parts[0], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quorum ID in quorum retention %q: %w", retention, err)
+		}
+		days, err := strconv.Atoi(parts[1])
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("invalid number of days in quorum retention %q", retention)
+		}
+		quorumRetentionDays[core.QuorumID(quorumID)] = days
 	}
+	return quorumRetentionDays, nil
 }
 
 func getMetadataHash(requestedAt uint64, securityParams []*core.SecurityParam) (string, error) {
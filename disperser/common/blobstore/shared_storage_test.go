@@ -0,0 +1,828 @@
+package blobstore_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0glabs/0g-data-avail/common/aws"
+	commondynamodb "github.com/0glabs/0g-data-avail/common/aws/dynamodb"
+	"github.com/0glabs/0g-data-avail/common/aws/s3"
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/blobstore"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRetention(t *testing.T) {
+	day := 24 * time.Hour
+	defaultRetention := 3 * day
+	quorumRetentionDays := map[core.QuorumID]int{
+		0: 7,
+		1: 90,
+	}
+	params := func(quorumIDs ...core.QuorumID) []*core.SecurityParam {
+		securityParams := make([]*core.SecurityParam, len(quorumIDs))
+		for i, quorumID := range quorumIDs {
+			securityParams[i] = &core.SecurityParam{QuorumID: quorumID}
+		}
+		return securityParams
+	}
+
+	assert.Equal(t, 7*day, blobstore.ResolveRetention(quorumRetentionDays, params(0), defaultRetention))
+	assert.Equal(t, 90*day, blobstore.ResolveRetention(quorumRetentionDays, params(1), defaultRetention))
+	// overlapping quorums get the longer retention
+	assert.Equal(t, 90*day, blobstore.ResolveRetention(quorumRetentionDays, params(0, 1), defaultRetention))
+	assert.Equal(t, 90*day, blobstore.ResolveRetention(quorumRetentionDays, params(1, 0, 2), defaultRetention))
+	// quorums without retention fall back to the default
+	assert.Equal(t, defaultRetention, blobstore.ResolveRetention(quorumRetentionDays, params(2), defaultRetention))
+	assert.Equal(t, defaultRetention, blobstore.ResolveRetention(nil, params(0, 1), defaultRetention))
+	// the default is included in the longest retention
+	assert.Equal(t, 90*day, blobstore.ResolveRetention(map[core.QuorumID]int{0: 7}, params(0, 2), 90*day))
+	// quorums without expiry outlast the others
+	assert.Equal(t, time.Duration(0), blobstore.ResolveRetention(map[core.QuorumID]int{0: 7}, params(0, 1), 0))
+	assert.Equal(t, time.Duration(0), blobstore.ResolveRetention(map[core.QuorumID]int{0: 0, 1: 90}, params(0, 1), defaultRetention))
+	assert.Equal(t, time.Duration(0), blobstore.ResolveRetention(nil, params(0), 0))
+}
+
+func TestParseQuorumRetentionDays(t *testing.T) {
+	retentions, err := blobstore.ParseQuorumRetentionDays([]string{"0:7", "1:90"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[core.QuorumID]int{0: 7, 1: 90}, retentions)
+
+	_, err = blobstore.ParseQuorumRetentionDays([]string{"0"})
+	assert.Error(t, err)
+	_, err = blobstore.ParseQuorumRetentionDays([]string{"256:7"})
+	assert.Error(t, err)
+	_, err = blobstore.ParseQuorumRetentionDays([]string{"0:-1"})
+	assert.Error(t, err)
+}
+
+func TestGetBlobContentByBlobHash(t *testing.T) {
+	ctx := context.Background()
+	for _, metadataHashAsBlobKey := range []bool{false, true} {
+		sharedStorage := blobstore.NewSharedStorage(bucketName, s3Client, metadataHashAsBlobKey, nil, blobMetadataStore, nil, 0, "", nil, logger)
+
+		data := []byte("blob content by hash")
+		if metadataHashAsBlobKey {
+			data = []byte("blob content by hash with metadata hash as blob key")
+		}
+		blobKey, err := sharedStorage.StoreBlob(ctx, &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: data,
+		}, uint64(time.Now().UnixNano()))
+		assert.NoError(t, err)
+
+		content, err := sharedStorage.GetBlobContentByBlobHash(ctx, blobKey.BlobHash)
+		assert.NoError(t, err)
+		assert.Equal(t, data, content)
+
+		_, err = sharedStorage.GetBlobContentByBlobHash(ctx, "unknown-blob-hash")
+		assert.Error(t, err)
+		if metadataHashAsBlobKey {
+			assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+		}
+	}
+}
+
+func TestRemoveBlob(t *testing.T) {
+	ctx := context.Background()
+	for _, metadataHashAsBlobKey := range []bool{false, true} {
+		objects := mock.NewS3Client()
+		sharedStorage := blobstore.NewSharedStorage(bucketName, objects, metadataHashAsBlobKey, nil, blobMetadataStore, nil, 0, "", nil, logger)
+
+		// the same data dispersed twice
+		blob := &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: []byte(fmt.Sprintf("removed blob, metadata hash as blob key: %v", metadataHashAsBlobKey)),
+		}
+		requestedAt := uint64(time.Now().UnixNano())
+		removedKey, err := sharedStorage.StoreBlob(ctx, blob, requestedAt)
+		assert.NoError(t, err)
+		keptKey, err := sharedStorage.StoreBlob(ctx, blob, requestedAt+1)
+		assert.NoError(t, err)
+		removed, err := sharedStorage.GetBlobMetadata(ctx, removedKey)
+		assert.NoError(t, err)
+		kept, err := sharedStorage.GetBlobMetadata(ctx, keptKey)
+		assert.NoError(t, err)
+
+		// the content of the other dispersal is kept
+		assert.NoError(t, sharedStorage.RemoveBlob(ctx, removed))
+		stored, err := sharedStorage.GetBlobMetadata(ctx, removedKey)
+		assert.NoError(t, err)
+		assert.Empty(t, stored.MetadataHash)
+		content, err := sharedStorage.GetBlobContent(ctx, kept)
+		assert.NoError(t, err)
+		assert.Equal(t, blob.Data, content)
+		if metadataHashAsBlobKey {
+			_, err = sharedStorage.GetBlobContent(ctx, removed)
+			assert.Error(t, err)
+		}
+
+		// the content is deleted with its last dispersal
+		assert.NoError(t, sharedStorage.RemoveBlob(ctx, kept))
+		_, err = sharedStorage.GetBlobContent(ctx, kept)
+		assert.Error(t, err)
+	}
+}
+
+func TestMarkBlobConfirmedExpiry(t *testing.T) {
+	ctx := context.Background()
+	metadataStore := blobstore.NewBlobMetadataStore(dynamoClient, logger, metadataTableName, time.Hour)
+	sharedStorage := blobstore.NewSharedStorage(bucketName, s3Client, false, nil, metadataStore, nil, 0, "", nil, logger)
+	now := uint64(time.Now().Unix())
+
+	queue := func(name string, expiry uint64) *disperser.BlobMetadata {
+		metadata := &disperser.BlobMetadata{
+			BlobHash:     name + "-blob",
+			MetadataHash: name + "-metadata",
+			BlobStatus:   disperser.Processing,
+			Expiry:       expiry,
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobRequestHeader: core.BlobRequestHeader{
+					SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+				},
+				BlobSize:    100,
+				RequestedAt: uint64(time.Now().UnixNano()),
+			},
+		}
+		assert.NoError(t, metadataStore.QueueNewBlobMetadata(ctx, metadata))
+		return metadata
+	}
+
+	// the retention of a confirmed blob is extended
+	alive := queue("confirmed-alive", now+60)
+	confirmed, err := sharedStorage.MarkBlobConfirmed(ctx, alive, &disperser.ConfirmationInfo{BlobIndex: 1})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, confirmed.Expiry, now+3600)
+	fetched, err := sharedStorage.GetBlobMetadata(ctx, alive.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Confirmed, fetched.BlobStatus)
+	assert.Equal(t, confirmed.Expiry, fetched.Expiry)
+
+	// a blob which expired before it was confirmed isn't brought back
+	expired := queue("confirmed-expired", now-60)
+	_, err = sharedStorage.MarkBlobConfirmed(ctx, expired, &disperser.ConfirmationInfo{BlobIndex: 2})
+	assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+	fetched, err = sharedStorage.GetBlobMetadata(ctx, expired.GetBlobKey())
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Processing, fetched.BlobStatus)
+	assert.Equal(t, now-60, fetched.Expiry)
+}
+
+// blockingS3Client blocks downloads until released and records how many of them run at the same time
+type blockingS3Client struct {
+	*mock.S3Client
+	release     chan struct{}
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *blockingS3Client) DownloadObject(ctx context.Context, bucket string, key string) ([]byte, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mu.Unlock()
+
+	<-c.release
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return c.S3Client.DownloadObject(ctx, bucket, key)
+}
+
+func TestGetBlobsByMetadataMaxConcurrentUploads(t *testing.T) {
+	ctx := context.Background()
+	blockingClient := &blockingS3Client{S3Client: mock.NewS3Client(), release: make(chan struct{})}
+	metrics := blobstore.NewMetrics(prometheus.NewRegistry(), "test")
+	sharedStorage := blobstore.NewSharedStorage(bucketName, blockingClient, true, nil, nil, nil, 2, "", metrics, logger)
+
+	numBlobs := 10
+	metadata := make([]*disperser.BlobMetadata, numBlobs)
+	for i := range metadata {
+		metadataHash := fmt.Sprintf("metadata%d", i)
+		assert.NoError(t, blockingClient.UploadObject(ctx, bucketName, metadataHash, []byte(fmt.Sprintf("blob%d", i))))
+		metadata[i] = &disperser.BlobMetadata{
+			BlobHash:     fmt.Sprintf("blob%d", i),
+			MetadataHash: metadataHash,
+			RequestMetadata: &disperser.RequestMetadata{
+				BlobRequestHeader: core.BlobRequestHeader{
+					SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+				},
+			},
+		}
+	}
+
+	type result struct {
+		blobs map[disperser.BlobKey]*core.Blob
+		err   error
+	}
+	resultChan := make(chan result)
+	go func() {
+		blobs, err := sharedStorage.GetBlobsByMetadata(ctx, metadata)
+		resultChan <- result{blobs, err}
+	}()
+
+	// the downloads block, so no more than the limit may be in flight
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.S3ConcurrentOperations) == 2
+	}, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.S3ConcurrentOperations))
+
+	close(blockingClient.release)
+	res := <-resultChan
+	assert.NoError(t, res.err)
+	assert.Len(t, res.blobs, numBlobs)
+	for i, m := range metadata {
+		assert.Equal(t, []byte(fmt.Sprintf("blob%d", i)), res.blobs[m.GetBlobKey()].Data)
+		// the returned headers don't share the security params with the metadata
+		assert.Equal(t, m.RequestMetadata.BlobRequestHeader, res.blobs[m.GetBlobKey()].RequestHeader)
+		assert.NotSame(t, m.RequestMetadata.SecurityParams[0], res.blobs[m.GetBlobKey()].RequestHeader.SecurityParams[0])
+	}
+	assert.Equal(t, 2, blockingClient.maxInFlight)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.S3ConcurrentOperations))
+}
+
+// bucketS3Client keeps the objects of each bucket apart and fails the requests to unavailable buckets
+type bucketS3Client struct {
+	mu          sync.Mutex
+	buckets     map[string]*mock.S3Client
+	unavailable map[string]bool
+}
+
+var _ s3.ObjectStorage = (*bucketS3Client)(nil)
+
+func newBucketS3Client() *bucketS3Client {
+	return &bucketS3Client{buckets: make(map[string]*mock.S3Client), unavailable: make(map[string]bool)}
+}
+
+func (c *bucketS3Client) setUnavailable(bucket string, unavailable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unavailable[bucket] = unavailable
+}
+
+func (c *bucketS3Client) numObjects(bucket string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client, ok := c.buckets[bucket]
+	if !ok {
+		return 0
+	}
+	objects, _ := client.ListObjects(context.Background(), bucket, "")
+	return len(objects)
+}
+
+// do runs f against the bucket while holding the lock, as the mock client isn't safe for concurrent use
+func (c *bucketS3Client) do(bucket string, f func(client *mock.S3Client) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unavailable[bucket] {
+		return fmt.Errorf("bucket %s is unavailable", bucket)
+	}
+	client, ok := c.buckets[bucket]
+	if !ok {
+		client = mock.NewS3Client()
+		c.buckets[bucket] = client
+	}
+	return f(client)
+}
+
+func (c *bucketS3Client) DownloadObject(ctx context.Context, bucket string, key string) ([]byte, error) {
+	var data []byte
+	err := c.do(bucket, func(client *mock.S3Client) (err error) {
+		data, err = client.DownloadObject(ctx, bucket, key)
+		return err
+	})
+	return data, err
+}
+
+func (c *bucketS3Client) HeadObject(ctx context.Context, bucket string, key string) (*s3.ObjectAttributes, error) {
+	var attributes *s3.ObjectAttributes
+	err := c.do(bucket, func(client *mock.S3Client) (err error) {
+		attributes, err = client.HeadObject(ctx, bucket, key)
+		return err
+	})
+	return attributes, err
+}
+
+func (c *bucketS3Client) UploadObject(ctx context.Context, bucket string, key string, data []byte) error {
+	return c.do(bucket, func(client *mock.S3Client) error {
+		return client.UploadObject(ctx, bucket, key, data)
+	})
+}
+
+func (c *bucketS3Client) PutObject(ctx context.Context, bucket string, key string, data []byte, metadata map[string]string) error {
+	return c.do(bucket, func(client *mock.S3Client) error {
+		return client.PutObject(ctx, bucket, key, data, metadata)
+	})
+}
+
+func (c *bucketS3Client) DeleteObject(ctx context.Context, bucket string, key string) error {
+	return c.do(bucket, func(client *mock.S3Client) error {
+		return client.DeleteObject(ctx, bucket, key)
+	})
+}
+
+func (c *bucketS3Client) CopyObject(ctx context.Context, bucket string, srcKey string, dstKey string) error {
+	return c.do(bucket, func(client *mock.S3Client) error {
+		return client.CopyObject(ctx, bucket, srcKey, dstKey)
+	})
+}
+
+func (c *bucketS3Client) ListObjects(ctx context.Context, bucket string, prefix string) ([]s3.Object, error) {
+	var objects []s3.Object
+	err := c.do(bucket, func(client *mock.S3Client) (err error) {
+		objects, err = client.ListObjects(ctx, bucket, prefix)
+		return err
+	})
+	return objects, err
+}
+
+func (c *bucketS3Client) PutObjectTags(ctx context.Context, bucket string, key string, tags map[string]string) error {
+	return c.do(bucket, func(client *mock.S3Client) error {
+		return client.PutObjectTags(ctx, bucket, key, tags)
+	})
+}
+
+func TestRecoverFromShadow(t *testing.T) {
+	ctx := context.Background()
+	shadowBucketName := "test-blobstore-shadow"
+	objects := newBucketS3Client()
+	metrics := blobstore.NewMetrics(prometheus.NewRegistry(), "test")
+	sharedStorage := blobstore.NewSharedStorage(bucketName, objects, false, nil, blobMetadataStore, nil, 0, shadowBucketName, metrics, logger)
+
+	data := []byte("blob copied to the shadow bucket")
+	blobKey, err := sharedStorage.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: data,
+	}, uint64(time.Now().UnixNano()))
+	assert.NoError(t, err)
+
+	// the shadow copy is uploaded in the background
+	assert.Eventually(t, func() bool {
+		return objects.numObjects(shadowBucketName) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.ShadowUploadFailures))
+
+	// the blob is recovered from the shadow bucket while the primary bucket is down
+	objects.setUnavailable(bucketName, true)
+	_, err = sharedStorage.GetBlobContentByBlobHash(ctx, blobKey.BlobHash)
+	assert.Error(t, err)
+	content, err := sharedStorage.RecoverFromShadow(ctx, blobKey.BlobHash)
+	assert.NoError(t, err)
+	assert.Equal(t, data, content)
+	objects.setUnavailable(bucketName, false)
+
+	// a failed shadow upload doesn't fail the dispersal
+	objects.setUnavailable(shadowBucketName, true)
+	data = []byte("blob not copied to the shadow bucket")
+	blobKey, err = sharedStorage.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: data,
+	}, uint64(time.Now().UnixNano()))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.ShadowUploadFailures) == 1
+	}, time.Second, time.Millisecond)
+	content, err = sharedStorage.GetBlobContentByBlobHash(ctx, blobKey.BlobHash)
+	assert.NoError(t, err)
+	assert.Equal(t, data, content)
+	objects.setUnavailable(shadowBucketName, false)
+	_, err = sharedStorage.RecoverFromShadow(ctx, blobKey.BlobHash)
+	assert.ErrorIs(t, err, s3.ErrObjectNotFound)
+
+	// without a shadow bucket there is nothing to recover from
+	sharedStorage = blobstore.NewSharedStorage(bucketName, objects, false, nil, blobMetadataStore, nil, 0, "", metrics, logger)
+	_, err = sharedStorage.RecoverFromShadow(ctx, blobKey.BlobHash)
+	assert.Error(t, err)
+}
+
+// countingS3Client counts the requests made to check for and upload objects
+type countingS3Client struct {
+	*bucketS3Client
+	requests atomic.Int32
+}
+
+func (c *countingS3Client) HeadObject(ctx context.Context, bucket string, key string) (*s3.ObjectAttributes, error) {
+	c.requests.Add(1)
+	return c.bucketS3Client.HeadObject(ctx, bucket, key)
+}
+
+func (c *countingS3Client) PutObject(ctx context.Context, bucket string, key string, data []byte, metadata map[string]string) error {
+	c.requests.Add(1)
+	return c.bucketS3Client.PutObject(ctx, bucket, key, data, metadata)
+}
+
+func TestStoreBlobDeduplication(t *testing.T) {
+	ctx := context.Background()
+	objects := &countingS3Client{bucketS3Client: newBucketS3Client()}
+	sharedStorage := blobstore.NewSharedStorage(bucketName, objects, false, nil, blobMetadataStore, nil, 0, "", nil, logger)
+
+	blob := &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: []byte("blob stored concurrently"),
+	}
+	requestedAt := uint64(time.Now().UnixNano())
+
+	// concurrent first time stores of the same blob upload it once
+	numStores := 10
+	keys := make([]disperser.BlobKey, numStores)
+	var wg sync.WaitGroup
+	for i := 0; i < numStores; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			keys[i], err = sharedStorage.StoreBlob(ctx, blob, requestedAt)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+	for _, key := range keys {
+		assert.Equal(t, keys[0], key)
+	}
+	assert.Equal(t, int32(2), objects.requests.Load())
+	assert.Equal(t, 1, objects.numObjects(bucketName))
+
+	// a later store returns the first key without touching s3
+	key, err := sharedStorage.StoreBlob(ctx, blob, requestedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, keys[0], key)
+	assert.Equal(t, int32(2), objects.requests.Load())
+	metadata, err := blobMetadataStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Processing, metadata.BlobStatus)
+
+	// the metadata of a blob which failed to upload is removed, so the blob can be stored again
+	objects.setUnavailable(bucketName, true)
+	blob.Data = []byte("blob failed to upload")
+	_, err = sharedStorage.StoreBlob(ctx, blob, requestedAt)
+	assert.Error(t, err)
+	objects.setUnavailable(bucketName, false)
+	key, err = sharedStorage.StoreBlob(ctx, blob, requestedAt)
+	assert.NoError(t, err)
+	content, err := sharedStorage.GetBlobContentByBlobHash(ctx, key.BlobHash)
+	assert.NoError(t, err)
+	assert.Equal(t, blob.Data, content)
+}
+
+func TestStoreBlobSharedObject(t *testing.T) {
+	ctx := context.Background()
+	objects := &countingS3Client{bucketS3Client: newBucketS3Client()}
+	metrics := blobstore.NewMetrics(prometheus.NewRegistry(), "test")
+	sharedStorage := blobstore.NewSharedStorage(bucketName, objects, false, nil, blobMetadataStore, nil, 0, "", metrics, logger, blobstore.WithBlobDeduplication())
+
+	blob := &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: []byte("blob dispersed twice"),
+	}
+	requestedAt := uint64(time.Now().UnixNano())
+	key1, err := sharedStorage.StoreBlob(ctx, blob, requestedAt)
+	assert.NoError(t, err)
+	requests := objects.requests.Load()
+
+	// the second dispersal of the same data only checks that the object exists
+	key2, err := sharedStorage.StoreBlob(ctx, blob, requestedAt+1)
+	assert.NoError(t, err)
+	assert.Equal(t, key1.BlobHash, key2.BlobHash)
+	assert.NotEqual(t, key1.MetadataHash, key2.MetadataHash)
+	assert.Equal(t, requests+1, objects.requests.Load())
+	assert.Equal(t, 1, objects.numObjects(bucketName))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.BlobsDeduplicated))
+	assert.Equal(t, float64(len(blob.Data)), testutil.ToFloat64(metrics.BytesSavedByDedup))
+
+	metadata, err := blobMetadataStore.GetBlobMetadata(ctx, key2)
+	assert.NoError(t, err)
+	content, err := sharedStorage.GetBlobContent(ctx, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, blob.Data, content)
+}
+
+// delayedS3Client adds a latency to the downloads
+type delayedS3Client struct {
+	*mock.S3Client
+	delay time.Duration
+}
+
+func (c *delayedS3Client) DownloadObject(ctx context.Context, bucket string, key string) ([]byte, error) {
+	time.Sleep(c.delay)
+	return c.S3Client.DownloadObject(ctx, bucket, key)
+}
+
+// newDelayedDynamoClient returns a client of the localstack DynamoDB which adds a latency to the GetItem requests
+func newDelayedDynamoClient(t *testing.T, delay time.Duration) *commondynamodb.Client {
+	target, err := url.Parse(fmt.Sprintf("http://0.0.0.0:%s", localStackPort))
+	assert.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.Header.Get("X-Amz-Target"), ".GetItem") {
+			time.Sleep(delay)
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := commondynamodb.NewClient(aws.ClientConfig{
+		Region:          "us-east-1",
+		AccessKey:       "localstack",
+		SecretAccessKey: "localstack",
+		EndpointURL:     server.URL,
+	}, logger)
+	assert.NoError(t, err)
+	return client
+}
+
+func TestGetBlobMetadataAndContent(t *testing.T) {
+	ctx := context.Background()
+	const delay = 300 * time.Millisecond
+	s3Client := &delayedS3Client{S3Client: mock.NewS3Client()}
+	batchHeaderStore, err := blobstore.NewBatchHeaderStore(dynamoClient, logger, batchHeaderTableName)
+	assert.NoError(t, err)
+	sharedStorage := blobstore.NewSharedStorage(bucketName, s3Client, false, nil, blobMetadataStore, batchHeaderStore, 0, "", nil, logger)
+
+	batchHeaderHash := [32]byte{3, 5}
+	data := []byte("metadata and content")
+	key, err := sharedStorage.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: data,
+	}, uint64(time.Now().UnixNano()))
+	assert.NoError(t, err)
+	metadata, err := sharedStorage.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	_, err = sharedStorage.MarkBlobConfirmed(ctx, metadata, newTestConfirmationInfo(batchHeaderHash, 1, 2))
+	assert.NoError(t, err)
+
+	// a new batch header store doesn't have the batch header cached
+	delayedClient := newDelayedDynamoClient(t, delay)
+	delayedBatchHeaderStore, err := blobstore.NewBatchHeaderStore(delayedClient, logger, batchHeaderTableName)
+	assert.NoError(t, err)
+	delayedStorage := blobstore.NewSharedStorage(bucketName, s3Client, false, nil, blobstore.NewBlobMetadataStore(delayedClient, logger, metadataTableName, 0), delayedBatchHeaderStore, 0, "", nil, logger)
+	s3Client.delay = delay
+
+	start := time.Now()
+	metadata, content, err := delayedStorage.GetBlobMetadataAndContent(ctx, batchHeaderHash, 1)
+	elapsed := time.Since(start)
+	assert.NoError(t, err)
+	assert.Equal(t, data, content)
+	assert.Equal(t, key, metadata.GetBlobKey())
+	assert.Equal(t, newTestConfirmationInfo(batchHeaderHash, 1, 2), metadata.ConfirmationInfo)
+	// the batch header and the content are read concurrently
+	assert.GreaterOrEqual(t, elapsed, delay)
+	assert.Less(t, elapsed, 2*delay)
+
+	_, _, err = delayedStorage.GetBlobMetadataAndContent(ctx, batchHeaderHash, 0)
+	assert.Error(t, err)
+}
+
+// renamingS3Client records the copies and deletes, and fails the deletes of failDelete
+type renamingS3Client struct {
+	*mock.S3Client
+	ops        []string
+	failDelete string
+}
+
+func (c *renamingS3Client) CopyObject(ctx context.Context, bucket string, srcKey string, dstKey string) error {
+	c.ops = append(c.ops, "copy "+srcKey+" "+dstKey)
+	return c.S3Client.CopyObject(ctx, bucket, srcKey, dstKey)
+}
+
+func (c *renamingS3Client) DeleteObject(ctx context.Context, bucket string, key string) error {
+	c.ops = append(c.ops, "delete "+key)
+	if key == c.failDelete {
+		return errors.New("access denied")
+	}
+	return c.S3Client.DeleteObject(ctx, bucket, key)
+}
+
+func TestRenameBlob(t *testing.T) {
+	ctx := context.Background()
+	s3Client := &renamingS3Client{S3Client: mock.NewS3Client()}
+	sharedStorage := blobstore.NewSharedStorage(bucketName, s3Client, false, nil, blobMetadataStore, nil, 0, "", nil, logger)
+	metadata := &disperser.BlobMetadata{BlobHash: "blobhash", MetadataHash: "metadatahash"}
+	oldKey := "blob/blobhash.json"
+	data := []byte("renamed blob")
+	assert.NoError(t, s3Client.UploadObject(ctx, bucketName, oldKey, data))
+
+	// the object is copied to the new key before the old key is deleted
+	assert.NoError(t, sharedStorage.RenameBlob(ctx, metadata, "metadatahash"))
+	assert.Equal(t, []string{"copy blob/blobhash.json metadatahash", "delete blob/blobhash.json"}, s3Client.ops)
+	_, err := s3Client.DownloadObject(ctx, bucketName, oldKey)
+	assert.ErrorIs(t, err, s3.ErrObjectNotFound)
+	renamed, err := s3Client.DownloadObject(ctx, bucketName, "metadatahash")
+	assert.NoError(t, err)
+	assert.Equal(t, data, renamed)
+
+	// a conflict is detected before anything is modified
+	s3Client.ops = nil
+	assert.NoError(t, s3Client.UploadObject(ctx, bucketName, oldKey, data))
+	assert.NoError(t, s3Client.UploadObject(ctx, bucketName, "taken", []byte("other blob")))
+	assert.ErrorIs(t, sharedStorage.RenameBlob(ctx, metadata, "taken"), blobstore.ErrRenameConflict)
+	assert.Empty(t, s3Client.ops)
+	taken, err := s3Client.DownloadObject(ctx, bucketName, "taken")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other blob"), taken)
+
+	// the copy is removed if the old key can't be deleted
+	s3Client.failDelete = oldKey
+	assert.Error(t, sharedStorage.RenameBlob(ctx, metadata, "copy"))
+	assert.Equal(t, []string{"copy blob/blobhash.json copy", "delete blob/blobhash.json", "delete copy"}, s3Client.ops)
+	_, err = s3Client.DownloadObject(ctx, bucketName, "copy")
+	assert.ErrorIs(t, err, s3.ErrObjectNotFound)
+	old, err := s3Client.DownloadObject(ctx, bucketName, oldKey)
+	assert.NoError(t, err)
+	assert.Equal(t, data, old)
+}
+
+// downloadCountingS3Client counts the downloads
+type downloadCountingS3Client struct {
+	*mock.S3Client
+	downloads atomic.Int32
+}
+
+func (c *downloadCountingS3Client) DownloadObject(ctx context.Context, bucket string, key string) ([]byte, error) {
+	c.downloads.Add(1)
+	return c.S3Client.DownloadObject(ctx, bucket, key)
+}
+
+func TestPrefetchBlobs(t *testing.T) {
+	ctx := context.Background()
+	objects := &downloadCountingS3Client{S3Client: mock.NewS3Client()}
+	cacheDir := t.TempDir()
+	diskCache, err := blobstore.NewDiskCache(cacheDir)
+	assert.NoError(t, err)
+	metrics := blobstore.NewMetrics(prometheus.NewRegistry(), "test")
+	sharedStorage := blobstore.NewSharedStorage(bucketName, objects, true, nil, nil, nil, 0, "", metrics, logger, blobstore.WithDiskCache(diskCache, 2))
+
+	metadata := make([]*disperser.BlobMetadata, 4)
+	keys := make([]disperser.BlobKey, len(metadata))
+	for i := range metadata {
+		metadataHash := fmt.Sprintf("metadata%d", i)
+		assert.NoError(t, objects.UploadObject(ctx, bucketName, metadataHash, []byte(fmt.Sprintf("blob%d", i))))
+		metadata[i] = &disperser.BlobMetadata{
+			BlobHash:        fmt.Sprintf("blob%d", i),
+			MetadataHash:    metadataHash,
+			RequestMetadata: &disperser.RequestMetadata{},
+		}
+		keys[i] = metadata[i].GetBlobKey()
+	}
+	cachedFiles := func() int {
+		entries, err := os.ReadDir(cacheDir)
+		assert.NoError(t, err)
+		return len(entries)
+	}
+
+	sharedStorage.PrefetchBlobs(ctx, keys[:3])
+	assert.Eventually(t, func() bool { return cachedFiles() == 3 }, time.Second, time.Millisecond)
+	// the prefetched blobs are only served once the downloads are recorded
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(3), objects.downloads.Load())
+
+	// the prefetched blobs are served from the disk cache, once
+	data, err := sharedStorage.GetBlobContent(ctx, metadata[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("blob0"), data)
+	blobs, err := sharedStorage.GetBlobsByMetadata(ctx, metadata[1:2])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("blob1"), blobs[keys[1]].Data)
+	assert.Equal(t, int32(3), objects.downloads.Load())
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.PrefetchHits))
+	assert.Equal(t, 1, cachedFiles())
+
+	data, err = sharedStorage.GetBlobContent(ctx, metadata[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("blob0"), data)
+	assert.Equal(t, int32(4), objects.downloads.Load())
+
+	// the blob which was prefetched but not read, and isn't prefetched again, is wasted
+	sharedStorage.PrefetchBlobs(ctx, keys[3:])
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.PrefetchWasted))
+	assert.Eventually(t, func() bool { return objects.downloads.Load() == 5 && cachedFiles() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	data, err = sharedStorage.GetBlobContent(ctx, metadata[3])
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("blob3"), data)
+	assert.Equal(t, int32(5), objects.downloads.Load())
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.PrefetchHits))
+
+	// prefetching is a no-op without the disk cache
+	uncached := blobstore.NewSharedStorage(bucketName, objects, true, nil, nil, nil, 0, "", nil, logger)
+	uncached.PrefetchBlobs(ctx, keys)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(5), objects.downloads.Load())
+	assert.Equal(t, 0, cachedFiles())
+}
+
+func TestVerifyContentHash(t *testing.T) {
+	ctx := context.Background()
+	objects := mock.NewS3Client()
+	metrics := blobstore.NewMetrics(prometheus.NewRegistry(), "test")
+	sharedStorage := blobstore.NewSharedStorage(bucketName, objects, true, nil, nil, nil, 0, "", metrics, logger, blobstore.WithContentHashVerification())
+
+	content := []byte("blob content")
+	hash := sha256.Sum256(content)
+	metadata := &disperser.BlobMetadata{
+		BlobHash:        hex.EncodeToString(hash[:]),
+		MetadataHash:    "metadata",
+		RequestMetadata: &disperser.RequestMetadata{},
+	}
+	assert.NoError(t, objects.UploadObject(ctx, bucketName, metadata.MetadataHash, content))
+	data, err := sharedStorage.GetBlobContent(ctx, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	// the object was corrupted
+	assert.NoError(t, objects.UploadObject(ctx, bucketName, metadata.MetadataHash, []byte("blob c0ntent")))
+	_, err = sharedStorage.GetBlobContent(ctx, metadata)
+	assert.ErrorIs(t, err, blobstore.ErrBlobCorrupted)
+	_, err = sharedStorage.GetBlobsByMetadata(ctx, []*disperser.BlobMetadata{metadata})
+	assert.ErrorIs(t, err, blobstore.ErrBlobCorrupted)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.IntegrityFailures))
+
+	// the content isn't verified by default
+	unverified := blobstore.NewSharedStorage(bucketName, objects, true, nil, nil, nil, 0, "", metrics, logger)
+	data, err = unverified.GetBlobContent(ctx, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("blob c0ntent"), data)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.IntegrityFailures))
+}
+
+func TestCompressBlobs(t *testing.T) {
+	ctx := context.Background()
+	objects := mock.NewS3Client()
+	metrics := blobstore.NewMetrics(prometheus.NewRegistry(), "test")
+	sharedStorage := blobstore.NewSharedStorage(bucketName, objects, false, nil, blobMetadataStore, nil, 0, "", metrics, logger, blobstore.WithCompression())
+
+	blob := &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: []byte(strings.Repeat("compressible blob ", 100)),
+	}
+	key, err := sharedStorage.StoreBlob(ctx, blob, uint64(time.Now().UnixNano()))
+	assert.NoError(t, err)
+
+	// the blob hash and the size are the ones of the uncompressed data
+	metadata, err := blobMetadataStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	hash := sha256.Sum256(blob.Data)
+	assert.Equal(t, hex.EncodeToString(hash[:]), metadata.BlobHash)
+	assert.Equal(t, uint(len(blob.Data)), metadata.RequestMetadata.BlobSize)
+
+	objectKey := "blob/" + metadata.BlobHash + ".json"
+	stored, err := objects.DownloadObject(ctx, bucketName, objectKey)
+	assert.NoError(t, err)
+	assert.Less(t, len(stored), len(blob.Data))
+	attributes, err := objects.HeadObject(ctx, bucketName, objectKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "zstd", attributes.MetadataValue(blobstore.ContentEncodingMetadataKey))
+	assert.Equal(t, float64(len(blob.Data)-len(stored)), testutil.ToFloat64(metrics.BytesSavedCompression))
+
+	content, err := sharedStorage.GetBlobContent(ctx, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, blob.Data, content)
+	blobs, err := sharedStorage.GetBlobsByMetadata(ctx, []*disperser.BlobMetadata{metadata})
+	assert.NoError(t, err)
+	assert.Equal(t, blob.Data, blobs[key].Data)
+
+	// an object which isn't tagged is read as it is, even if it looks like a zstd frame
+	plain := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x01}
+	assert.NoError(t, objects.UploadObject(ctx, bucketName, objectKey, plain))
+	content, err = sharedStorage.GetBlobContent(ctx, metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, plain, content)
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
 	"github.com/0glabs/0g-data-avail/core"
@@ -77,6 +81,10 @@ func sizeOf(metadata *disperser.BlobMetadata) uint64 {
 		// QuorumResults: 8
 		// BlobQuorumInfos: 24
 		size += 172
+		for _, receipt := range metadata.ConfirmationInfo.StorageNodeReceipts {
+			// NodeID, Signature, Timestamp
+			size += 16 + uint64(len(receipt.NodeID)) + 24 + uint64(len(receipt.Signature)) + 8
+		}
 	}
 	return size
 }
@@ -169,18 +177,39 @@ func (q *SharedBlobStore) MarkBlobConfirmed(ctx context.Context, existingMetadat
 	if _, ok := q.Metadata[blobKey]; !ok {
 		return nil, disperser.ErrBlobNotFound
 	}
-	newMetadata := *existingMetadata
+	newMetadata := existingMetadata.Clone()
 	newMetadata.BlobStatus = disperser.Confirmed
 	newMetadata.ConfirmationInfo = confirmationInfo
+	newMetadata.BlobStatusHistory = q.Metadata[blobKey].Clone().BlobStatusHistory
+	newMetadata.AppendStatusEvent(disperser.Confirmed, "")
 	// update size
 	if existing, ok := q.Metadata[blobKey]; ok {
 		q.size -= sizeOf(existing)
 	}
-	q.size += sizeOf(&newMetadata)
+	q.size += sizeOf(newMetadata)
 	q.logger.Info("[memdb] blob confirmed", "mem db used", q.size, "limit", q.sizeLimit)
 	// don't throw error here
-	q.Metadata[blobKey] = &newMetadata
-	return &newMetadata, nil
+	q.Metadata[blobKey] = newMetadata
+	return newMetadata, nil
+}
+
+func (q *SharedBlobStore) SetStorageNodeReceipts(ctx context.Context, blobKey disperser.BlobKey, receipts []disperser.StorageNodeReceipt) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	existing, ok := q.Metadata[blobKey]
+	if !ok {
+		return disperser.ErrBlobNotFound
+	}
+	if existing.ConfirmationInfo == nil {
+		return fmt.Errorf("blob %s is not confirmed", blobKey.String())
+	}
+
+	newMetadata := existing.Clone()
+	newMetadata.ConfirmationInfo.StorageNodeReceipts = receipts
+	q.size -= sizeOf(existing)
+	q.size += sizeOf(newMetadata)
+	q.Metadata[blobKey] = newMetadata
+	return nil
 }
 
 func (q *SharedBlobStore) MarkBlobFinalized(ctx context.Context, blobKey disperser.BlobKey) error {
@@ -190,7 +219,22 @@ func (q *SharedBlobStore) MarkBlobFinalized(ctx context.Context, blobKey dispers
 		return disperser.ErrBlobNotFound
 	}
 
-	q.Metadata[blobKey].BlobStatus = disperser.Finalized
+	q.setBlobStatus(blobKey, disperser.Finalized, "")
+	return nil
+}
+
+func (q *SharedBlobStore) BatchMarkBlobsFinalized(ctx context.Context, blobKeys []disperser.BlobKey) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, blobKey := range blobKeys {
+		if _, ok := q.Metadata[blobKey]; !ok {
+			return disperser.ErrBlobNotFound
+		}
+	}
+
+	for _, blobKey := range blobKeys {
+		q.setBlobStatus(blobKey, disperser.Finalized, "")
+	}
 	return nil
 }
 
@@ -201,7 +245,7 @@ func (q *SharedBlobStore) MarkBlobProcessing(ctx context.Context, blobKey disper
 		return disperser.ErrBlobNotFound
 	}
 
-	q.Metadata[blobKey].BlobStatus = disperser.Processing
+	q.setBlobStatus(blobKey, disperser.Processing, "")
 	return nil
 }
 
@@ -212,18 +256,75 @@ func (q *SharedBlobStore) MarkBlobFailed(ctx context.Context, blobKey disperser.
 		return disperser.ErrBlobNotFound
 	}
 
-	q.Metadata[blobKey].BlobStatus = disperser.Failed
+	q.setBlobStatus(blobKey, disperser.Failed, "")
+	return nil
+}
+
+func (q *SharedBlobStore) OverrideBlobStatus(ctx context.Context, blobKey disperser.BlobKey, status disperser.BlobStatus, reason string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.Metadata[blobKey]; !ok {
+		return disperser.ErrBlobNotFound
+	}
+
+	q.setBlobStatus(blobKey, status, reason)
 	return nil
 }
 
-func (q *SharedBlobStore) IncrementBlobRetryCount(ctx context.Context, existingMetadata *disperser.BlobMetadata) error {
+func (q *SharedBlobStore) TransitionBlobStatus(ctx context.Context, blobKey disperser.BlobKey, from, to disperser.BlobStatus, reason string) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	if _, ok := q.Metadata[existingMetadata.GetBlobKey()]; !ok {
+	metadata, ok := q.Metadata[blobKey]
+	if !ok {
 		return disperser.ErrBlobNotFound
 	}
+	if metadata.BlobStatus != from {
+		return disperser.ErrUnexpectedBlobStatus
+	}
+
+	q.setBlobStatus(blobKey, to, reason)
+	return nil
+}
+
+func (q *SharedBlobStore) UpdateBlobExpiry(ctx context.Context, metadata *disperser.BlobMetadata, newExpiry uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	blobKey := metadata.GetBlobKey()
+	if _, ok := q.Metadata[blobKey]; !ok {
+		return disperser.ErrBlobNotFound
+	}
+
+	updated := q.Metadata[blobKey].Clone()
+	updated.Expiry = newExpiry
+	q.Metadata[blobKey] = updated
+	return nil
+}
+
+// setBlobStatus replaces the metadata of the blob with a copy with the given status, recorded in its history with the
+// reason. The stored metadata is returned to the callers, so it is never modified in place. It must be called with the
+// lock held.
+func (q *SharedBlobStore) setBlobStatus(blobKey disperser.BlobKey, status disperser.BlobStatus, reason string) {
+	updated := q.Metadata[blobKey].Clone()
+	updated.BlobStatus = status
+	updated.AppendStatusEvent(status, reason)
+	q.Metadata[blobKey] = updated
+}
+
+func (q *SharedBlobStore) IncrementBlobRetryCount(ctx context.Context, existingMetadata *disperser.BlobMetadata, maxRetry uint) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	metadata, ok := q.Metadata[existingMetadata.GetBlobKey()]
+	if !ok {
+		return disperser.ErrBlobNotFound
+	}
+	if metadata.NumRetries >= maxRetry {
+		return disperser.ErrMaxRetriesReached
+	}
 
-	q.Metadata[existingMetadata.GetBlobKey()].NumRetries++
+	updated := metadata.Clone()
+	updated.NumRetries++
+	updated.AppendStatusEvent(updated.BlobStatus, disperser.RetryReason(updated.NumRetries, maxRetry))
+	q.Metadata[existingMetadata.GetBlobKey()] = updated
 	return nil
 }
 
@@ -244,6 +345,9 @@ func (q *SharedBlobStore) GetBlobsByMetadata(ctx context.Context, metadata []*di
 	return blobs, nil
 }
 
+// PrefetchBlobs is a no-op, the blob contents are already in memory
+func (q *SharedBlobStore) PrefetchBlobs(ctx context.Context, keys []disperser.BlobKey) {}
+
 func (q *SharedBlobStore) GetBlobMetadataByStatus(ctx context.Context, status disperser.BlobStatus) ([]*disperser.BlobMetadata, error) {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
@@ -256,6 +360,116 @@ func (q *SharedBlobStore) GetBlobMetadataByStatus(ctx context.Context, status di
 	return metas, nil
 }
 
+func (q *SharedBlobStore) GetBlobMetadataByStatusPaginated(ctx context.Context, status disperser.BlobStatus, pageSize int32, exclusiveStartKey *disperser.BlobStoreExclusiveStartKey) ([]*disperser.BlobMetadata, *disperser.BlobStoreExclusiveStartKey, error) {
+	if pageSize <= 0 {
+		return nil, nil, fmt.Errorf("page size must be greater than 0")
+	}
+	metas, err := q.GetBlobMetadataByStatus(ctx, status)
+	if err != nil {
+		return nil, nil, err
+	}
+	// same order as the status index of the dynamodb store
+	sort.Slice(metas, func(i, j int) bool {
+		return compareStatusIndexKeys(statusIndexKey(metas[i]), statusIndexKey(metas[j])) < 0
+	})
+
+	start := 0
+	if exclusiveStartKey != nil {
+		start = sort.Search(len(metas), func(i int) bool {
+			return compareStatusIndexKeys(statusIndexKey(metas[i]), exclusiveStartKey) > 0
+		})
+	}
+	end := start + int(pageSize)
+	if end >= len(metas) {
+		return metas[start:], nil, nil
+	}
+	return metas[start:end], statusIndexKey(metas[end-1]), nil
+}
+
+func statusIndexKey(meta *disperser.BlobMetadata) *disperser.BlobStoreExclusiveStartKey {
+	return &disperser.BlobStoreExclusiveStartKey{
+		BlobHash:     meta.BlobHash,
+		MetadataHash: meta.MetadataHash,
+		BlobStatus:   meta.BlobStatus,
+		RequestedAt:  meta.RequestMetadata.RequestedAt,
+	}
+}
+
+func compareStatusIndexKeys(a, b *disperser.BlobStoreExclusiveStartKey) int {
+	if a.RequestedAt != b.RequestedAt {
+		if a.RequestedAt < b.RequestedAt {
+			return -1
+		}
+		return 1
+	}
+	if c := strings.Compare(a.BlobHash, b.BlobHash); c != 0 {
+		return c
+	}
+	return strings.Compare(a.MetadataHash, b.MetadataHash)
+}
+
+func (q *SharedBlobStore) GetBlobMetadataByHashPrefix(ctx context.Context, hashPrefix string, limit int) ([]*disperser.BlobMetadata, error) {
+	if len(hashPrefix) < disperser.MinHashPrefixLength {
+		return nil, disperser.ErrHashPrefixTooShort
+	}
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	metas := make([]*disperser.BlobMetadata, 0)
+	for _, meta := range q.Metadata {
+		if len(metas) >= limit {
+			break
+		}
+		if strings.HasPrefix(meta.BlobHash, hashPrefix) || strings.HasPrefix(meta.MetadataHash, hashPrefix) {
+			metas = append(metas, meta)
+		}
+	}
+	return metas, nil
+}
+
+func (q *SharedBlobStore) GetBlobMetadataByStatusAndTimeRange(ctx context.Context, status disperser.BlobStatus, from, to time.Time) ([]*disperser.BlobMetadata, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("invalid time range: from %v is after to %v", from, to)
+	}
+	metas, err := q.GetBlobMetadataByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	inRange := make([]*disperser.BlobMetadata, 0, len(metas))
+	for _, meta := range metas {
+		requestedAt := meta.RequestMetadata.RequestedAt
+		if requestedAt >= uint64(from.UnixNano()) && requestedAt <= uint64(to.UnixNano()) {
+			inRange = append(inRange, meta)
+		}
+	}
+	// same order as the status index of the dynamodb store
+	sort.Slice(inRange, func(i, j int) bool {
+		return compareStatusIndexKeys(statusIndexKey(inRange[i]), statusIndexKey(inRange[j])) < 0
+	})
+	return inRange, nil
+}
+
+func (q *SharedBlobStore) GetAllBatches(ctx context.Context, pageSize int, lastBatchHeaderHash *[32]byte) ([]*disperser.BatchSummary, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	metas := make([]*disperser.BlobMetadata, 0, len(q.Metadata))
+	for _, meta := range q.Metadata {
+		metas = append(metas, meta)
+	}
+	return disperser.PageBatchSummaries(metas, pageSize, lastBatchHeaderHash)
+}
+
+func (q *SharedBlobStore) GetBatchesConfirmedAfter(ctx context.Context, blockNumber uint32, pageSize int, lastBatchHeaderHash *[32]byte) ([]*disperser.BatchSummary, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	metas := make([]*disperser.BlobMetadata, 0, len(q.Metadata))
+	for _, meta := range q.Metadata {
+		if meta.ConfirmationInfo != nil && meta.ConfirmationInfo.ConfirmationBlockNumber > blockNumber {
+			metas = append(metas, meta)
+		}
+	}
+	return disperser.PageBatchSummaries(metas, pageSize, lastBatchHeaderHash)
+}
+
 func (q *SharedBlobStore) GetMetadataInBatch(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, error) {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
@@ -268,6 +482,18 @@ func (q *SharedBlobStore) GetMetadataInBatch(ctx context.Context, batchHeaderHas
 	return nil, disperser.ErrBlobNotFound
 }
 
+func (q *SharedBlobStore) GetBlobMetadataAndContent(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*disperser.BlobMetadata, []byte, error) {
+	metadata, err := q.GetMetadataInBatch(ctx, batchHeaderHash, blobIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := q.GetBlobContent(ctx, metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return metadata, data, nil
+}
+
 func (q *SharedBlobStore) GetAllBlobMetadataByBatch(ctx context.Context, batchHeaderHash [32]byte) ([]*disperser.BlobMetadata, error) {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
@@ -289,10 +515,18 @@ func (q *SharedBlobStore) GetBlobMetadata(ctx context.Context, blobKey disperser
 
 func (q *SharedBlobStore) HandleBlobFailure(ctx context.Context, metadata *disperser.BlobMetadata, maxRetry uint) error {
 	if metadata.NumRetries < maxRetry {
-		return q.IncrementBlobRetryCount(ctx, metadata)
-	} else {
-		return q.MarkBlobFailed(ctx, metadata.GetBlobKey())
+		err := q.IncrementBlobRetryCount(ctx, metadata, maxRetry)
+		if !errors.Is(err, disperser.ErrMaxRetriesReached) {
+			return err
+		}
 	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.Metadata[metadata.GetBlobKey()]; !ok {
+		return disperser.ErrBlobNotFound
+	}
+	q.setBlobStatus(metadata.GetBlobKey(), disperser.Failed, disperser.ErrMaxRetriesReached.Error())
+	return nil
 }
 
 func getBlobHash(blob *core.Blob) disperser.BlobHash {
@@ -0,0 +1,149 @@
+package memorydb_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/common/mock"
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/0glabs/0g-data-avail/disperser/common/memorydb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleBlobFailureConcurrently(t *testing.T) {
+	ctx := context.Background()
+	blobStore := memorydb.NewBlobStore(1024*1024, &mock.Logger{})
+	maxRetry := uint(3)
+
+	key, err := blobStore.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: []byte("retried blob"),
+	}, 1)
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	// all workers see the same stale copy of the metadata
+	staleMetadata := *metadata
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, blobStore.HandleBlobFailure(ctx, &staleMetadata, maxRetry))
+		}()
+	}
+	wg.Wait()
+
+	metadata, err = blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, maxRetry, metadata.NumRetries)
+	assert.Equal(t, disperser.Failed, metadata.BlobStatus)
+
+	err = blobStore.IncrementBlobRetryCount(ctx, metadata, maxRetry)
+	assert.ErrorIs(t, err, disperser.ErrMaxRetriesReached)
+}
+
+func TestMetadataNotModifiedInPlace(t *testing.T) {
+	ctx := context.Background()
+	blobStore := memorydb.NewBlobStore(1024*1024, &mock.Logger{})
+
+	key, err := blobStore.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: []byte("shared blob"),
+	}, 1)
+	assert.NoError(t, err)
+	metadata, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+
+	// the metadata held by a reader doesn't change while the blob is updated
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, disperser.Processing, metadata.BlobStatus)
+			assert.Equal(t, uint(0), metadata.NumRetries)
+		}
+	}()
+	assert.NoError(t, blobStore.IncrementBlobRetryCount(ctx, metadata, 3))
+	assert.NoError(t, blobStore.MarkBlobFailed(ctx, key))
+	assert.NoError(t, blobStore.MarkBlobFinalized(ctx, key))
+	wg.Wait()
+
+	updated, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Finalized, updated.BlobStatus)
+	assert.Equal(t, uint(1), updated.NumRetries)
+	assert.Equal(t, disperser.Processing, metadata.BlobStatus)
+}
+
+func TestTransitionBlobStatus(t *testing.T) {
+	ctx := context.Background()
+	blobStore := memorydb.NewBlobStore(1024*1024, &mock.Logger{})
+
+	key, err := blobStore.StoreBlob(ctx, &core.Blob{
+		RequestHeader: core.BlobRequestHeader{
+			SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+		},
+		Data: []byte("transitioned blob"),
+	}, 1)
+	assert.NoError(t, err)
+
+	assert.NoError(t, blobStore.TransitionBlobStatus(ctx, key, disperser.Processing, disperser.Failed, "cancelled"))
+	assert.ErrorIs(t, blobStore.TransitionBlobStatus(ctx, key, disperser.Processing, disperser.Failed, "cancelled"), disperser.ErrUnexpectedBlobStatus)
+	metadata, err := blobStore.GetBlobMetadata(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, disperser.Failed, metadata.BlobStatus)
+
+	err = blobStore.TransitionBlobStatus(ctx, disperser.BlobKey{BlobHash: "missing"}, disperser.Processing, disperser.Failed, "")
+	assert.ErrorIs(t, err, disperser.ErrBlobNotFound)
+}
+
+func TestGetBlobMetadataByStatusPaginated(t *testing.T) {
+	ctx := context.Background()
+	blobStore := memorydb.NewBlobStore(1024*1024, &mock.Logger{})
+
+	numBlobs := 7
+	keys := make([]disperser.BlobKey, numBlobs)
+	for i := range keys {
+		var err error
+		keys[i], err = blobStore.StoreBlob(ctx, &core.Blob{
+			RequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: 0}},
+			},
+			Data: []byte(fmt.Sprintf("paginated blob %d", i)),
+		}, uint64(numBlobs-i))
+		assert.NoError(t, err)
+	}
+	// not in the processing blobs
+	assert.NoError(t, blobStore.MarkBlobFailed(ctx, keys[3]))
+
+	pages := make([][]disperser.BlobKey, 0)
+	var exclusiveStartKey *disperser.BlobStoreExclusiveStartKey
+	for {
+		metadatas, nextKey, err := blobStore.GetBlobMetadataByStatusPaginated(ctx, disperser.Processing, 4, exclusiveStartKey)
+		assert.NoError(t, err)
+		page := make([]disperser.BlobKey, len(metadatas))
+		for i, metadata := range metadatas {
+			page[i] = metadata.GetBlobKey()
+		}
+		pages = append(pages, page)
+		if nextKey == nil {
+			break
+		}
+		exclusiveStartKey = nextKey
+	}
+	// in the order the blobs were requested in
+	assert.Equal(t, [][]disperser.BlobKey{{keys[6], keys[5], keys[4], keys[2]}, {keys[1], keys[0]}}, pages)
+
+	_, _, err := blobStore.GetBlobMetadataByStatusPaginated(ctx, disperser.Processing, 0, nil)
+	assert.Error(t, err)
+}
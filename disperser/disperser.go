@@ -1,11 +1,16 @@
 package disperser
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
 	"github.com/0glabs/0g-data-avail/core"
@@ -40,7 +45,27 @@ func (bs BlobStatus) String() string {
 	if str, ok := enumStrings[bs]; ok {
 		return str
 	}
-	return "Unknown value"
+	return "Unknown"
+}
+
+// MarshalJSON encodes the status as its human-readable name
+func (bs BlobStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bs.String())
+}
+
+// UnmarshalJSON decodes the status from its human-readable name
+func (bs *BlobStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("blob status must be a string: %w", err)
+	}
+	for status, name := range enumStrings {
+		if name == str {
+			*bs = status
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown blob status %q", str)
 }
 
 type BlobHash = string
@@ -82,12 +107,41 @@ type BlobMetadata struct {
 	// This field is nil if the blob has not been confirmed
 	// This field is omitted when marshalling to DynamoDB attributevalue as this field will be flattened
 	ConfirmationInfo *ConfirmationInfo `json:"blob_confirmation_info" dynamodbav:"-"`
+	// BlobStatusHistory are the transitions of the status of the blob, oldest first, see AppendStatusEvent
+	BlobStatusHistory []BlobStatusEvent `json:"blob_status_history"`
+}
+
+// MaxBlobStatusHistoryLength is the maximum number of events in the status history of a blob. Once the history is full,
+// its last event is replaced, so that it keeps the first transitions of the blob and the latest one.
+const MaxBlobStatusHistoryLength = 50
+
+// BlobStatusEvent is a transition of the status of a blob
+type BlobStatusEvent struct {
+	Status    BlobStatus `json:"status"`
+	Timestamp time.Time  `json:"timestamp"`
+	// Reason is why the status changed, it is empty for the transitions of the normal flow
+	Reason string `json:"reason"`
+}
+
+// NewBlobStatusEvent returns the event of a transition to the status now. The time is in UTC, so that it is the same
+// once the metadata is serialized and read back.
+func NewBlobStatusEvent(status BlobStatus, reason string) BlobStatusEvent {
+	return BlobStatusEvent{
+		Status:    status,
+		Timestamp: time.Now().UTC(),
+		Reason:    reason,
+	}
 }
 
 func (m *BlobMetadata) Serialize() ([]byte, error) {
 	return core.Encode(m)
 }
 
+// SerializeWithFormat serializes the metadata in the given format, Deserialize reads both gob and JSON
+func (m *BlobMetadata) SerializeWithFormat(format core.SerializationFormat) ([]byte, error) {
+	return core.EncodeWithFormat(m, format)
+}
+
 func (m *BlobMetadata) Deserialize(data []byte) (*BlobMetadata, error) {
 	err := core.Decode(data, m)
 	return m, err
@@ -100,6 +154,39 @@ func (m *BlobMetadata) GetBlobKey() BlobKey {
 	}
 }
 
+// Clone returns a deep copy of the metadata, so it can be modified without affecting other holders of the metadata
+func (m *BlobMetadata) Clone() *BlobMetadata {
+	if m == nil {
+		return nil
+	}
+	clone := *m
+	if m.RequestMetadata != nil {
+		requestMetadata := *m.RequestMetadata
+		requestMetadata.BlobRequestHeader = *m.RequestMetadata.BlobRequestHeader.Clone()
+		clone.RequestMetadata = &requestMetadata
+	}
+	clone.ConfirmationInfo = m.ConfirmationInfo.Clone()
+	clone.BlobStatusHistory = slices.Clone(m.BlobStatusHistory)
+	return &clone
+}
+
+// RetryReason is the reason of the status event recorded when a failed blob is retried
+func RetryReason(numRetries, maxRetry uint) string {
+	return fmt.Sprintf("retry %d of %d", numRetries, maxRetry)
+}
+
+// AppendStatusEvent records the transition of the blob to the status in its history, which is capped at
+// MaxBlobStatusHistoryLength events. It doesn't set the status of the blob.
+func (m *BlobMetadata) AppendStatusEvent(status BlobStatus, reason string) {
+	event := NewBlobStatusEvent(status, reason)
+	if len(m.BlobStatusHistory) >= MaxBlobStatusHistoryLength {
+		m.BlobStatusHistory = m.BlobStatusHistory[:MaxBlobStatusHistoryLength]
+		m.BlobStatusHistory[MaxBlobStatusHistoryLength-1] = event
+		return
+	}
+	m.BlobStatusHistory = append(m.BlobStatusHistory, event)
+}
+
 func (m *BlobMetadata) IsConfirmed() (bool, error) {
 	if m.BlobStatus != Confirmed && m.BlobStatus != Finalized {
 		return false, nil
@@ -121,6 +208,7 @@ type ConfirmationInfo struct {
 	BatchHeaderHash         [32]byte                             `json:"batch_header_hash"`
 	BlobIndex               uint32                               `json:"blob_index"`
 	BlobCount               uint32                               `json:"blob_count"`
+	BatchSize               uint64                               `json:"batch_size"`
 	SignatoryRecordHash     [32]byte                             `json:"signatory_record_hash"`
 	ReferenceBlockNumber    uint32                               `json:"reference_block_number"`
 	BatchRoot               []byte                               `json:"batch_root"`
@@ -133,6 +221,53 @@ type ConfirmationInfo struct {
 	Fee                     []byte                               `json:"fee"`
 	QuorumResults           map[core.QuorumID]*core.QuorumResult `json:"quorum_results"`
 	BlobQuorumInfos         []*core.BlobQuorumInfo               `json:"blob_quorum_infos"`
+	StorageNodeReceipts     []StorageNodeReceipt                 `json:"storage_node_receipts"`
+}
+
+// Clone returns a deep copy of the confirmation info
+func (c *ConfirmationInfo) Clone() *ConfirmationInfo {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.BatchRoot = bytes.Clone(c.BatchRoot)
+	clone.BlobInclusionProof = bytes.Clone(c.BlobInclusionProof)
+	clone.CommitmentRoot = bytes.Clone(c.CommitmentRoot)
+	clone.Fee = bytes.Clone(c.Fee)
+	if c.QuorumResults != nil {
+		clone.QuorumResults = make(map[core.QuorumID]*core.QuorumResult, len(c.QuorumResults))
+		for quorumID, result := range c.QuorumResults {
+			if result != nil {
+				resultCopy := *result
+				result = &resultCopy
+			}
+			clone.QuorumResults[quorumID] = result
+		}
+	}
+	if c.BlobQuorumInfos != nil {
+		clone.BlobQuorumInfos = make([]*core.BlobQuorumInfo, len(c.BlobQuorumInfos))
+		for i, info := range c.BlobQuorumInfos {
+			if info != nil {
+				infoCopy := *info
+				clone.BlobQuorumInfos[i] = &infoCopy
+			}
+		}
+	}
+	if c.StorageNodeReceipts != nil {
+		clone.StorageNodeReceipts = make([]StorageNodeReceipt, len(c.StorageNodeReceipts))
+		for i, receipt := range c.StorageNodeReceipts {
+			receipt.Signature = bytes.Clone(receipt.Signature)
+			clone.StorageNodeReceipts[i] = receipt
+		}
+	}
+	return &clone
+}
+
+// StorageNodeReceipt is the acknowledgement of a storage node that it stored the chunks of a confirmed batch
+type StorageNodeReceipt struct {
+	NodeID    string `json:"node_id"`
+	Signature []byte `json:"signature"`
+	Timestamp uint64 `json:"timestamp"`
 }
 
 type BlobStore interface {
@@ -147,20 +282,54 @@ type BlobStore interface {
 	// MarkBlobConfirmed updates blob metadata to Confirmed status with confirmation info
 	// Returns the updated metadata and error
 	MarkBlobConfirmed(ctx context.Context, existingMetadata *BlobMetadata, confirmationInfo *ConfirmationInfo) (*BlobMetadata, error)
+	// SetStorageNodeReceipts sets the storage node receipts of a confirmed blob
+	SetStorageNodeReceipts(ctx context.Context, blobKey BlobKey, receipts []StorageNodeReceipt) error
 	// MarkBlobFinalized marks a blob as finalized
 	MarkBlobFinalized(ctx context.Context, blobKey BlobKey) error
+	// BatchMarkBlobsFinalized marks the blobs as finalized in bulk
+	BatchMarkBlobsFinalized(ctx context.Context, blobKeys []BlobKey) error
+	// UpdateBlobExpiry sets the expiry of a blob, in unix seconds
+	UpdateBlobExpiry(ctx context.Context, metadata *BlobMetadata, newExpiry uint64) error
 	// MarkBlobProcessing marks a blob as processing
 	MarkBlobProcessing(ctx context.Context, blobKey BlobKey) error
 	// MarkBlobFailed marks a blob as failed
 	MarkBlobFailed(ctx context.Context, blobKey BlobKey) error
-	// IncrementBlobRetryCount increments the retry count of a blob
-	IncrementBlobRetryCount(ctx context.Context, existingMetadata *BlobMetadata) error
+	// OverrideBlobStatus sets the status of a blob whatever its current status, recording the reason in its history.
+	// It lets the operators recover the blobs stuck in a status, the transitions are checked by the caller.
+	OverrideBlobStatus(ctx context.Context, blobKey BlobKey, status BlobStatus, reason string) error
+	// TransitionBlobStatus sets the status of a blob to the given status only if it has the status from, recording the
+	// reason in its history. It returns ErrUnexpectedBlobStatus if the blob has another status, checked atomically
+	// with the update, so the transition isn't made over a concurrent update of the blob.
+	TransitionBlobStatus(ctx context.Context, blobKey BlobKey, from, to BlobStatus, reason string) error
+	// IncrementBlobRetryCount increments the retry count of a blob if it's below maxRetry
+	// Returns ErrMaxRetriesReached if the retry count has already reached maxRetry
+	IncrementBlobRetryCount(ctx context.Context, existingMetadata *BlobMetadata, maxRetry uint) error
 	// GetBlobsByMetadata retrieves a list of blobs given a list of metadata
 	GetBlobsByMetadata(ctx context.Context, metadata []*BlobMetadata) (map[BlobKey]*core.Blob, error)
+	// PrefetchBlobs downloads the contents of the given blobs in the background, so that they are read faster later on.
+	// It is a hint, stores which don't cache the contents ignore it.
+	PrefetchBlobs(ctx context.Context, keys []BlobKey)
 	// GetBlobMetadataByStatus returns a list of blob metadata for blobs with the given status
 	GetBlobMetadataByStatus(ctx context.Context, blobStatus BlobStatus) ([]*BlobMetadata, error)
+	// GetBlobMetadataByStatusPaginated returns up to pageSize metadata of blobs with the given status, in the order they
+	// were requested in, starting after exclusiveStartKey, or from the first one if it is nil.
+	// The returned key is where the next page starts, it is nil if there are no more blobs.
+	GetBlobMetadataByStatusPaginated(ctx context.Context, blobStatus BlobStatus, pageSize int32, exclusiveStartKey *BlobStoreExclusiveStartKey) ([]*BlobMetadata, *BlobStoreExclusiveStartKey, error)
+	// GetBlobMetadataByHashPrefix returns the metadata of up to limit blobs whose hash starts with the given prefix.
+	// The prefix must be at least MinHashPrefixLength characters, as the lookup scans all the blobs.
+	GetBlobMetadataByHashPrefix(ctx context.Context, hashPrefix string, limit int) ([]*BlobMetadata, error)
+	// GetBlobMetadataByStatusAndTimeRange returns the metadata of the blobs with the given status requested within
+	// [from, to], in the order they were requested in. The number of returned metadata may be limited by the store.
+	GetBlobMetadataByStatusAndTimeRange(ctx context.Context, status BlobStatus, from, to time.Time) ([]*BlobMetadata, error)
 	// GetMetadataInBatch returns the metadata in a given batch at given index.
 	GetMetadataInBatch(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*BlobMetadata, error)
+	// GetBlobMetadataAndContent returns the metadata in a given batch at given index and the content of the blob.
+	GetBlobMetadataAndContent(ctx context.Context, batchHeaderHash [32]byte, blobIndex uint32) (*BlobMetadata, []byte, error)
+	// GetAllBatches returns up to pageSize confirmed batches, ordered by confirmation block number, starting after the
+	// batch lastBatchHeaderHash, or from the first one if it is nil.
+	GetAllBatches(ctx context.Context, pageSize int, lastBatchHeaderHash *[32]byte) ([]*BatchSummary, error)
+	// GetBatchesConfirmedAfter is GetAllBatches for the batches confirmed after the given block number only.
+	GetBatchesConfirmedAfter(ctx context.Context, blockNumber uint32, pageSize int, lastBatchHeaderHash *[32]byte) ([]*BatchSummary, error)
 	// GetAllBlobMetadataByBatch returns the metadata of all the blobs in the batch.
 	GetAllBlobMetadataByBatch(ctx context.Context, batchHeaderHash [32]byte) ([]*BlobMetadata, error)
 	// GetBlobMetadata returns a blob metadata given a metadata key
@@ -169,6 +338,91 @@ type BlobStore interface {
 	HandleBlobFailure(ctx context.Context, metadata *BlobMetadata, maxRetry uint) error
 }
 
+// BlobStoreExclusiveStartKey is the position of a blob in the blobs of its status, the pages of
+// GetBlobMetadataByStatusPaginated start after it
+type BlobStoreExclusiveStartKey struct {
+	BlobHash     BlobHash
+	MetadataHash MetadataHash
+	BlobStatus   BlobStatus
+	RequestedAt  uint64
+}
+
+// BatchSummary describes a confirmed batch, as listed by GetAllBatches
+type BatchSummary struct {
+	BatchHeaderHash         [32]byte
+	ConfirmationBlockNumber uint32
+	BlobCount               uint32
+	// TotalBytes is the total size of the blobs of the batch
+	TotalBytes uint64
+}
+
+// PageBatchSummaries summarizes the batches of the confirmed blobs among the metadata, and returns the page of up to
+// pageSize of them following the batch lastBatchHeaderHash, see BlobStore.GetAllBatches. It is meant for the stores
+// holding all the metadata at hand, which don't index the batches.
+func PageBatchSummaries(metadatas []*BlobMetadata, pageSize int, lastBatchHeaderHash *[32]byte) ([]*BatchSummary, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be greater than 0")
+	}
+	batches := make(map[[32]byte]*BatchSummary)
+	for _, metadata := range metadatas {
+		if metadata.ConfirmationInfo == nil || (metadata.BlobStatus != Confirmed && metadata.BlobStatus != Finalized) {
+			continue
+		}
+		batchHeaderHash := metadata.ConfirmationInfo.BatchHeaderHash
+		batch, ok := batches[batchHeaderHash]
+		if !ok {
+			batch = &BatchSummary{
+				BatchHeaderHash:         batchHeaderHash,
+				ConfirmationBlockNumber: metadata.ConfirmationInfo.ConfirmationBlockNumber,
+			}
+			batches[batchHeaderHash] = batch
+		}
+		batch.BlobCount++
+		if metadata.RequestMetadata != nil {
+			batch.TotalBytes += uint64(metadata.RequestMetadata.BlobSize)
+		}
+	}
+
+	summaries := make([]*BatchSummary, 0, len(batches))
+	for _, batch := range batches {
+		summaries = append(summaries, batch)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].ConfirmationBlockNumber != summaries[j].ConfirmationBlockNumber {
+			return summaries[i].ConfirmationBlockNumber < summaries[j].ConfirmationBlockNumber
+		}
+		return bytes.Compare(summaries[i].BatchHeaderHash[:], summaries[j].BatchHeaderHash[:]) < 0
+	})
+
+	start := 0
+	if lastBatchHeaderHash != nil {
+		last, ok := batches[*lastBatchHeaderHash]
+		if !ok {
+			return nil, ErrBatchNotFound
+		}
+		for summaries[start] != last {
+			start++
+		}
+		start++
+	}
+	end := start + pageSize
+	if end > len(summaries) {
+		end = len(summaries)
+	}
+	return summaries[start:end], nil
+}
+
+// EncodingQueue exposes the occupancy of the encoding request queue so that
+// the api server can apply backpressure before accepting new blobs
+type EncodingQueue interface {
+	// QueueLength returns the number of encoding requests waiting to be processed
+	QueueLength() int
+	// QueueCapacity returns the maximum number of encoding requests that can be queued
+	QueueCapacity() int
+	// EstimatedDrainTime returns an estimate of how long it takes to drain the current queue
+	EstimatedDrainTime() time.Duration
+}
+
 type Dispatcher interface {
 	DisperseBatch(ctx context.Context, batchHeaderHash [32]byte, batchHeader *core.BatchHeader, extendedMatrix []*core.ExtendedMatrix, blobHeaders []*core.BlobHeader, proofs []*merkletree.Proof) (eth_common.Hash, error)
 }
@@ -202,6 +456,9 @@ func FromBlobStatusProto(status disperser_rpc.BlobStatus) (*BlobStatus, error) {
 	case disperser_rpc.BlobStatus_FINALIZED:
 		res = Finalized
 		return &res, nil
+	case disperser_rpc.BlobStatus_INSUFFICIENT_SIGNATURES:
+		res = InsufficientSignatures
+		return &res, nil
 	}
 
 	return nil, fmt.Errorf("unknown blob status: %v", status)
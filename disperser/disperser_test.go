@@ -0,0 +1,133 @@
+package disperser_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobStatusJSON(t *testing.T) {
+	statuses := map[disperser.BlobStatus]string{
+		disperser.Processing:             `"Processing"`,
+		disperser.Confirmed:              `"Confirmed"`,
+		disperser.Failed:                 `"Failed"`,
+		disperser.Finalized:              `"Finalized"`,
+		disperser.InsufficientSignatures: `"InsufficientSignatures"`,
+	}
+	for status, expected := range statuses {
+		data, err := json.Marshal(status)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(data))
+
+		var decoded disperser.BlobStatus
+		err = json.Unmarshal(data, &decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, status, decoded)
+	}
+
+	data, err := json.Marshal(disperser.BlobStatus(100))
+	assert.NoError(t, err)
+	assert.Equal(t, `"Unknown"`, string(data))
+	assert.Equal(t, "Unknown", disperser.BlobStatus(100).String())
+
+	var decoded disperser.BlobStatus
+	assert.Error(t, json.Unmarshal([]byte(`"Unknown"`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`1`), &decoded))
+}
+
+func TestBlobStatusJSONInStruct(t *testing.T) {
+	type response struct {
+		Status disperser.BlobStatus `json:"status"`
+	}
+	data, err := json.Marshal(response{Status: disperser.Confirmed})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"status":"Confirmed"}`, string(data))
+
+	var decoded response
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, disperser.Confirmed, decoded.Status)
+}
+
+func newFuzzBlobMetadata(blobHash string, status uint8, numRetries uint, accountID string, quorumID uint8, batchRoot []byte, signature []byte) *disperser.BlobMetadata {
+	return &disperser.BlobMetadata{
+		BlobHash:     blobHash,
+		MetadataHash: blobHash + "-metadata",
+		BlobStatus:   disperser.BlobStatus(status % 5),
+		NumRetries:   numRetries,
+		RequestMetadata: &disperser.RequestMetadata{
+			BlobRequestHeader: core.BlobRequestHeader{
+				SecurityParams: []*core.SecurityParam{{QuorumID: quorumID, AdversaryThreshold: 50, QuorumThreshold: 80}},
+				AccountID:      accountID,
+			},
+			BlobSize:    uint(len(batchRoot)),
+			RequestedAt: uint64(numRetries),
+		},
+		ConfirmationInfo: &disperser.ConfirmationInfo{
+			BatchHeaderHash:    [32]byte{quorumID},
+			BatchRoot:          append([]byte{}, batchRoot...),
+			BlobInclusionProof: append([]byte{}, signature...),
+			CommitmentRoot:     append([]byte{}, batchRoot...),
+			Fee:                append([]byte{}, signature...),
+			QuorumResults:      map[core.QuorumID]*core.QuorumResult{quorumID: {QuorumID: quorumID, PercentSigned: status}},
+			BlobQuorumInfos:    []*core.BlobQuorumInfo{{SecurityParam: core.SecurityParam{QuorumID: quorumID}, ChunkLength: numRetries}},
+			StorageNodeReceipts: []disperser.StorageNodeReceipt{
+				{NodeID: accountID, Signature: append([]byte{}, signature...), Timestamp: uint64(numRetries)},
+			},
+		},
+	}
+}
+
+func FuzzBlobMetadataClone(f *testing.F) {
+	f.Add("blob", uint8(0), uint(0), "account", uint8(0), []byte{1, 2, 3}, []byte{4, 5})
+	f.Add("", uint8(2), uint(7), "", uint8(255), []byte{}, []byte(nil))
+
+	f.Fuzz(func(t *testing.T, blobHash string, status uint8, numRetries uint, accountID string, quorumID uint8, batchRoot []byte, signature []byte) {
+		original := newFuzzBlobMetadata(blobHash, status, numRetries, accountID, quorumID, batchRoot, signature)
+		clone := original.Clone()
+		assert.Equal(t, original, clone)
+
+		// modify every field of the clone
+		clone.BlobHash += "x"
+		clone.BlobStatus++
+		clone.NumRetries++
+		clone.RequestMetadata.AccountID += "x"
+		clone.RequestMetadata.SecurityParams[0].QuorumID++
+		clone.RequestMetadata.SecurityParams = append(clone.RequestMetadata.SecurityParams, &core.SecurityParam{})
+		clone.ConfirmationInfo.BatchHeaderHash[0]++
+		clone.ConfirmationInfo.BatchRoot = append(clone.ConfirmationInfo.BatchRoot[:0], 9)
+		clone.ConfirmationInfo.BlobInclusionProof = append(clone.ConfirmationInfo.BlobInclusionProof[:0], 9)
+		clone.ConfirmationInfo.CommitmentRoot = append(clone.ConfirmationInfo.CommitmentRoot[:0], 9)
+		clone.ConfirmationInfo.Fee = append(clone.ConfirmationInfo.Fee[:0], 9)
+		clone.ConfirmationInfo.QuorumResults[quorumID].PercentSigned++
+		clone.ConfirmationInfo.QuorumResults[quorumID+1] = &core.QuorumResult{}
+		clone.ConfirmationInfo.BlobQuorumInfos[0].ChunkLength++
+		clone.ConfirmationInfo.StorageNodeReceipts[0].NodeID += "x"
+		clone.ConfirmationInfo.StorageNodeReceipts[0].Signature = append(clone.ConfirmationInfo.StorageNodeReceipts[0].Signature[:0], 9)
+
+		assert.Equal(t, newFuzzBlobMetadata(blobHash, status, numRetries, accountID, quorumID, batchRoot, signature), original)
+	})
+}
+
+func TestAppendStatusEvent(t *testing.T) {
+	metadata := &disperser.BlobMetadata{BlobStatus: disperser.Processing}
+	metadata.AppendStatusEvent(disperser.Processing, disperser.RetryReason(1, 3))
+	metadata.AppendStatusEvent(disperser.Failed, "max retries reached")
+	assert.Len(t, metadata.BlobStatusHistory, 2)
+	assert.Equal(t, disperser.Processing, metadata.BlobStatusHistory[0].Status)
+	assert.Equal(t, "retry 1 of 3", metadata.BlobStatusHistory[0].Reason)
+	assert.Equal(t, disperser.Failed, metadata.BlobStatusHistory[1].Status)
+	// the status itself is not changed
+	assert.Equal(t, disperser.Processing, metadata.BlobStatus)
+
+	// once the history is full, the last event is replaced
+	for i := 0; i < disperser.MaxBlobStatusHistoryLength; i++ {
+		metadata.AppendStatusEvent(disperser.Processing, "")
+	}
+	metadata.AppendStatusEvent(disperser.Finalized, "")
+	assert.Len(t, metadata.BlobStatusHistory, disperser.MaxBlobStatusHistoryLength)
+	assert.Equal(t, "retry 1 of 3", metadata.BlobStatusHistory[0].Reason)
+	assert.Equal(t, disperser.Finalized, metadata.BlobStatusHistory[disperser.MaxBlobStatusHistoryLength-1].Status)
+}
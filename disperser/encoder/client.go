@@ -10,6 +10,7 @@ import (
 	pb "github.com/0glabs/0g-data-avail/disperser/api/grpc/encoder"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type client struct {
@@ -57,14 +58,30 @@ func ExtendedMatrixFromReply(reply *pb.EncodeBlobReply, blobLength uint) (*core.
 	}, nil
 }
 
-func (c client) EncodeBlob(ctx context.Context, data []byte, dims core.MatrixDimsions) (*core.ExtendedMatrix, error) {
+// ReceiptFromReply returns the encoding receipt included in the reply, or nil if the encoder didn't sign the reply
+func ReceiptFromReply(reply *pb.EncodeBlobReply) (*disperser.BlobEncodingReceipt, error) {
+	if len(reply.EncoderSignature) == 0 {
+		return nil, nil
+	}
+	if len(reply.BlobHash) != 32 || len(reply.ChunkMerkleRoot) != 32 {
+		return nil, fmt.Errorf("%w: blob hash and chunk merkle root must be 32 bytes", disperser.ErrInvalidEncodingReceipt)
+	}
+	receipt := &disperser.BlobEncodingReceipt{
+		EncoderSignature: reply.EncoderSignature,
+	}
+	copy(receipt.BlobHash[:], reply.BlobHash)
+	copy(receipt.ChunkMerkleRoot[:], reply.ChunkMerkleRoot)
+	return receipt, nil
+}
+
+func (c client) EncodeBlob(ctx context.Context, data []byte, dims core.MatrixDimsions) (*core.ExtendedMatrix, *disperser.BlobEncodingReceipt, error) {
 	conn, err := grpc.Dial(
 		c.addr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1024*1024*1024)), // 1 GiB
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to dial encoder: %w", err)
+		return nil, nil, fmt.Errorf("failed to dial encoder: %w", err)
 	}
 	defer conn.Close()
 
@@ -74,7 +91,33 @@ func (c client) EncodeBlob(ctx context.Context, data []byte, dims core.MatrixDim
 		Cols: uint32(dims.Cols),
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	extendedMatrix, err := ExtendedMatrixFromReply(reply, core.GetBlobLength(uint(len(data))))
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt, err := ReceiptFromReply(reply)
+	if err != nil {
+		return nil, nil, err
+	}
+	return extendedMatrix, receipt, nil
+}
+
+// ProbeEncoder checks the encoder at the given address is serving through the grpc health check
+func ProbeEncoder(ctx context.Context, addr string) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to dial encoder: %w", err)
+	}
+	defer conn.Close()
+
+	reply, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if reply.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("encoder is not serving: %v", reply.Status)
 	}
-	return ExtendedMatrixFromReply(reply, core.GetBlobLength(uint(len(data))))
+	return nil
 }
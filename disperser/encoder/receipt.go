@@ -0,0 +1,27 @@
+package encoder
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignEncodingReceipt creates the receipt for the encoding of the blob data, signed with the encoder key
+func SignEncodingReceipt(data []byte, matrix *core.ExtendedMatrix, key *ecdsa.PrivateKey) (*disperser.BlobEncodingReceipt, error) {
+	chunkMerkleRoot, err := matrix.GetChunkMerkleRoot()
+	if err != nil {
+		return nil, err
+	}
+	receipt := &disperser.BlobEncodingReceipt{
+		BlobHash:        disperser.GetBlobHash(data),
+		ChunkMerkleRoot: chunkMerkleRoot,
+	}
+	hash := receipt.SigningHash()
+	receipt.EncoderSignature, err = crypto.Sign(hash[:], key)
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
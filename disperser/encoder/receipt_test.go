@@ -0,0 +1,117 @@
+package encoder_test
+
+import (
+	"testing"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/0glabs/0g-data-avail/disperser"
+	pb "github.com/0glabs/0g-data-avail/disperser/api/grpc/encoder"
+	"github.com/0glabs/0g-data-avail/disperser/encoder"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeExtendedMatrix(rows, cols int) *core.ExtendedMatrix {
+	matrix := &core.ExtendedMatrix{Length: uint(rows * cols)}
+	for i := 0; i < rows; i++ {
+		row := make(core.EncodedRow, cols)
+		for j := range row {
+			row[j][0] = byte(i)
+			row[j][1] = byte(j)
+		}
+		var commitment core.Commitment
+		commitment[0] = byte(i)
+		matrix.Rows = append(matrix.Rows, row)
+		matrix.Commitments = append(matrix.Commitments, commitment)
+	}
+	return matrix
+}
+
+func TestEncodingReceipt(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	data := []byte("encoding receipt")
+	matrix := makeExtendedMatrix(4, 2)
+
+	receipt, err := encoder.SignEncodingReceipt(data, matrix, key)
+	assert.NoError(t, err)
+	assert.NoError(t, receipt.Verify(data, matrix, &key.PublicKey))
+
+	// the public key can be configured in both compressed and uncompressed form
+	for _, pubkey := range []string{
+		hexutil.Encode(crypto.FromECDSAPub(&key.PublicKey)),
+		hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)),
+	} {
+		parsed, err := disperser.ParseEncoderPublicKey(pubkey)
+		assert.NoError(t, err)
+		assert.NoError(t, receipt.Verify(data, matrix, parsed))
+	}
+
+	// receipt round trip through the encoder reply
+	reply := &pb.EncodeBlobReply{
+		BlobHash:         receipt.BlobHash[:],
+		ChunkMerkleRoot:  receipt.ChunkMerkleRoot[:],
+		EncoderSignature: receipt.EncoderSignature,
+	}
+	fromReply, err := encoder.ReceiptFromReply(reply)
+	assert.NoError(t, err)
+	assert.Equal(t, receipt, fromReply)
+
+	// unsigned reply
+	fromReply, err = encoder.ReceiptFromReply(&pb.EncodeBlobReply{})
+	assert.NoError(t, err)
+	assert.Nil(t, fromReply)
+}
+
+func TestEncodingReceiptTampered(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	data := []byte("encoding receipt")
+	matrix := makeExtendedMatrix(4, 2)
+
+	sign := func() *disperser.BlobEncodingReceipt {
+		receipt, err := encoder.SignEncodingReceipt(data, matrix, key)
+		assert.NoError(t, err)
+		return receipt
+	}
+
+	// missing receipt
+	var missing *disperser.BlobEncodingReceipt
+	assert.ErrorIs(t, missing.Verify(data, matrix, &key.PublicKey), disperser.ErrInvalidEncodingReceipt)
+
+	// signed by another encoder
+	receipt := sign()
+	assert.ErrorIs(t, receipt.Verify(data, matrix, &otherKey.PublicKey), disperser.ErrInvalidEncodingReceipt)
+
+	// receipt for other blob data
+	assert.ErrorIs(t, receipt.Verify([]byte("other blob"), matrix, &key.PublicKey), disperser.ErrInvalidEncodingReceipt)
+
+	// tampered chunks
+	tampered := makeExtendedMatrix(4, 2)
+	tampered.Rows[1][0][0] ^= 0xff
+	assert.ErrorIs(t, receipt.Verify(data, tampered, &key.PublicKey), disperser.ErrInvalidEncodingReceipt)
+
+	// tampered commitment
+	tampered = makeExtendedMatrix(4, 2)
+	tampered.Commitments[3][1] ^= 0xff
+	assert.ErrorIs(t, receipt.Verify(data, tampered, &key.PublicKey), disperser.ErrInvalidEncodingReceipt)
+
+	// tampered chunk merkle root, consistent with tampered chunks
+	receipt = sign()
+	receipt.ChunkMerkleRoot, err = tampered.GetChunkMerkleRoot()
+	assert.NoError(t, err)
+	assert.ErrorIs(t, receipt.Verify(data, tampered, &key.PublicKey), disperser.ErrInvalidEncodingReceipt)
+
+	// tampered signature
+	receipt = sign()
+	receipt.EncoderSignature[10] ^= 0xff
+	assert.ErrorIs(t, receipt.Verify(data, matrix, &key.PublicKey), disperser.ErrInvalidEncodingReceipt)
+
+	// truncated signature
+	receipt = sign()
+	receipt.EncoderSignature = receipt.EncoderSignature[:32]
+	assert.ErrorIs(t, receipt.Verify(data, matrix, &key.PublicKey), disperser.ErrInvalidEncodingReceipt)
+}
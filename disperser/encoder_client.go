@@ -2,10 +2,71 @@ package disperser
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"strings"
 
 	"github.com/0glabs/0g-data-avail/core"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 type EncoderClient interface {
-	EncodeBlob(ctx context.Context, data []byte, dims core.MatrixDimsions) (*core.ExtendedMatrix, error)
+	EncodeBlob(ctx context.Context, data []byte, dims core.MatrixDimsions) (*core.ExtendedMatrix, *BlobEncodingReceipt, error)
+}
+
+// BlobEncodingReceipt is returned by the encoder alongside the encoded chunks, the encoder signs over
+// the hash of the blob and the Merkle root of the chunks to attest the chunks are the encoding of the blob
+type BlobEncodingReceipt struct {
+	BlobHash         [32]byte
+	ChunkMerkleRoot  [32]byte
+	EncoderSignature []byte
+}
+
+// GetBlobHash returns the hash of the blob data the receipt refers to
+func GetBlobHash(data []byte) [32]byte {
+	return crypto.Keccak256Hash(data)
+}
+
+// SigningHash returns sha3(blobHash || chunkMerkleRoot), the message signed by the encoder
+func (r *BlobEncodingReceipt) SigningHash() [32]byte {
+	return crypto.Keccak256Hash(r.BlobHash[:], r.ChunkMerkleRoot[:])
+}
+
+// Verify checks the receipt refers to the given blob data and encoded matrix, and that it's signed by the encoder key
+func (r *BlobEncodingReceipt) Verify(data []byte, matrix *core.ExtendedMatrix, encoderPublicKey *ecdsa.PublicKey) error {
+	if r == nil {
+		return fmt.Errorf("%w: missing receipt", ErrInvalidEncodingReceipt)
+	}
+	if r.BlobHash != GetBlobHash(data) {
+		return fmt.Errorf("%w: blob hash mismatch", ErrInvalidEncodingReceipt)
+	}
+	chunkMerkleRoot, err := matrix.GetChunkMerkleRoot()
+	if err != nil {
+		return fmt.Errorf("%w: failed to compute chunk merkle root: %v", ErrInvalidEncodingReceipt, err)
+	}
+	if r.ChunkMerkleRoot != chunkMerkleRoot {
+		return fmt.Errorf("%w: chunk merkle root mismatch", ErrInvalidEncodingReceipt)
+	}
+	// drop the recovery id, if any
+	if len(r.EncoderSignature) != crypto.SignatureLength && len(r.EncoderSignature) != crypto.SignatureLength-1 {
+		return fmt.Errorf("%w: invalid signature length %v", ErrInvalidEncodingReceipt, len(r.EncoderSignature))
+	}
+	hash := r.SigningHash()
+	if !crypto.VerifySignature(crypto.FromECDSAPub(encoderPublicKey), hash[:], r.EncoderSignature[:crypto.SignatureLength-1]) {
+		return fmt.Errorf("%w: invalid encoder signature", ErrInvalidEncodingReceipt)
+	}
+	return nil
+}
+
+// ParseEncoderPublicKey parses a hex encoded (optionally 0x prefixed) secp256k1 public key, either compressed or uncompressed
+func ParseEncoderPublicKey(pubkey string) (*ecdsa.PublicKey, error) {
+	pubkeyBytes, err := hex.DecodeString(strings.TrimPrefix(pubkey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encoder public key: %w", err)
+	}
+	if len(pubkeyBytes) == 33 {
+		return crypto.DecompressPubkey(pubkeyBytes)
+	}
+	return crypto.UnmarshalPubkey(pubkeyBytes)
 }
@@ -1,8 +1,27 @@
 package disperser
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
+
+// MinHashPrefixLength is the minimum length of the hash prefix blobs are looked up by, as the lookup scans all the blobs
+const MinHashPrefixLength = 8
 
 var (
 	ErrBlobNotFound   = errors.New("blob not found")
 	ErrMemoryDbIsFull = errors.New("memory db is full")
+	// ErrMaxRetriesReached is returned when the retry count of a blob can't be incremented any further
+	ErrMaxRetriesReached = errors.New("max number of retries reached")
+	// ErrInvalidEncodingReceipt is returned when the receipt of an encoded blob doesn't match the blob or isn't signed by the encoder
+	ErrInvalidEncodingReceipt = errors.New("invalid blob encoding receipt")
+	// ErrHashPrefixTooShort is returned when looking up blobs by a hash prefix shorter than MinHashPrefixLength
+	ErrHashPrefixTooShort = fmt.Errorf("hash prefix must be at least %d characters", MinHashPrefixLength)
+	// ErrBatchNotFound is returned when a confirmed batch is looked up by the hash of a batch header which wasn't stored
+	ErrBatchNotFound = errors.New("batch not found")
+	// ErrBatchListingUnavailable is returned when listing the batches of a store which doesn't keep the batch headers
+	ErrBatchListingUnavailable = errors.New("batches are only listed by the stores with a batch header table")
+	// ErrUnexpectedBlobStatus is returned by TransitionBlobStatus when the blob doesn't have the status it is moved from,
+	// e.g. because it was updated concurrently
+	ErrUnexpectedBlobStatus = errors.New("unexpected blob status")
 )
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/0glabs/0g-data-avail/common"
 	"github.com/prometheus/client_golang/prometheus"
@@ -24,6 +26,60 @@ type Metrics struct {
 	BlobSize        *prometheus.GaugeVec
 	Latency         *prometheus.SummaryVec
 
+	RateLimitRejections     *prometheus.CounterVec
+	AdmissionGateRejections prometheus.Counter
+	OnchainFallbackHits     prometheus.Counter
+	LoadShedRejections      prometheus.Counter
+	DedupWindowRejections   *prometheus.CounterVec
+
+	// RateLimitAllowed, RateLimitDenied and RateLimitErrors are the number of rate limit checks by outcome, labeled by
+	// account_type (system or individual) and limit_type (system or account)
+	RateLimitAllowed *prometheus.CounterVec
+	RateLimitDenied  *prometheus.CounterVec
+	RateLimitErrors  *prometheus.CounterVec
+	// RateBucketFillRatio is the fill ratio, in [0, 1], of the least filled bucket of the requesters checked last,
+	// labeled by a truncated hash of the requester ID
+	RateBucketFillRatio *prometheus.GaugeVec
+
+	// KVReadRetries is the number of retried reads of the blob metadata from the kv node
+	KVReadRetries prometheus.Counter
+	// KVReadLatency is the latency of the reads of the blob metadata from the kv node
+	KVReadLatency prometheus.Histogram
+
+	// InFlightRequests is the number of grpc requests being handled
+	InFlightRequests prometheus.Gauge
+	// ShutdownDrainDuration is the time it takes the in-flight requests to finish once the server is shut down
+	ShutdownDrainDuration prometheus.Histogram
+
+	// ServerLoadPercent is the consumed percentage of the system rate limit, see DispersalServer.UpdateServerLoad
+	ServerLoadPercent prometheus.Gauge
+
+	// BatchRequestBlobs is the number of blobs of the batch dispersal requests
+	BatchRequestBlobs prometheus.Histogram
+	// RequeuedBlobs is the number of failed blobs dispersed again by RequeueBlob
+	RequeuedBlobs prometheus.Counter
+	// RPCFallbackUsed is the number of times the latest finalized block was fetched from a fallback RPC endpoint,
+	// by the index of the fallback
+	RPCFallbackUsed *prometheus.CounterVec
+	// RPCFailures is the number of failed calls to the RPC endpoints, fallbacks included
+	RPCFailures prometheus.Counter
+	// FinalizationReorgs is the number of times the finalized block moved back or changed hash
+	FinalizationReorgs prometheus.Counter
+	// ConnectionsRejectedPerIP is the number of requests rejected because their client IP had too many active requests
+	ConnectionsRejectedPerIP prometheus.Counter
+
+	// BulkGetBlobStatusSize is the number of request IDs of the bulk blob status requests
+	BulkGetBlobStatusSize prometheus.Histogram
+
+	// GrpcMaxConcurrentStreams is the configured maximum number of concurrent grpc streams per connection
+	GrpcMaxConcurrentStreams prometheus.Gauge
+
+	// BatchMetrics are the per-batch aggregates of the batches processed along with the server, see ObserveBatch
+	*BatchMetrics
+
+	// handlers are additional http handlers served along with the metrics, e.g. the readiness probe
+	handlers map[string]http.Handler
+
 	httpPort string
 	logger   common.Logger
 }
@@ -60,18 +116,194 @@ func NewMetrics(httpPort string, logger common.Logger) *Metrics {
 			},
 			[]string{"method"},
 		),
-		registry: reg,
-		httpPort: httpPort,
-		logger:   logger,
+		RateLimitRejections: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rate_limit_rejections_total",
+				Help:      "the number of blob requests rejected by the rate limits, by the limit which was exceeded",
+			},
+			[]string{"reason"},
+		),
+		RateLimitAllowed: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rate_limit_allowed_total",
+				Help:      "the number of rate limit checks which allowed the request",
+			},
+			[]string{"account_type", "limit_type"},
+		),
+		RateLimitDenied: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rate_limit_denied_total",
+				Help:      "the number of rate limit checks which denied the request",
+			},
+			[]string{"account_type", "limit_type"},
+		),
+		RateLimitErrors: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rate_limit_errors_total",
+				Help:      "the number of rate limit checks which failed",
+			},
+			[]string{"account_type", "limit_type"},
+		),
+		RateBucketFillRatio: promauto.With(reg).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "rate_bucket_fill_ratio",
+				Help:      "the fill ratio of the least filled rate bucket of the requesters checked last, by truncated requester hash",
+			},
+			[]string{"requester_hash"},
+		),
+		AdmissionGateRejections: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "admission_gate_rejections_total",
+				Help:      "the number of blob requests rejected because the encoding queue is backed up",
+			},
+		),
+		OnchainFallbackHits: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "onchain_fallback_hits_total",
+				Help:      "the number of blob status requests answered from the on-chain blob confirmation",
+			},
+		),
+		LoadShedRejections: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "load_shed_rejections_total",
+				Help:      "the number of blob requests rejected because the process is under memory or GC pressure",
+			},
+		),
+		DedupWindowRejections: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "dedup_window_rejections_total",
+				Help:      "the number of blob requests rejected because the account submitted the same blob within the dedup window",
+			},
+			[]string{"account_id"},
+		),
+		KVReadRetries: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "kv_read_retries_total",
+				Help:      "the number of retried reads of the blob metadata from the kv node",
+			},
+		),
+		KVReadLatency: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "kv_read_latency_ms",
+				Help:      "the latency in milliseconds of the reads of the blob metadata from the kv node",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+			},
+		),
+		InFlightRequests: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "in_flight_requests",
+				Help:      "the number of grpc requests being handled",
+			},
+		),
+		ShutdownDrainDuration: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "shutdown_drain_duration_ms",
+				Help:      "the time in milliseconds from the server shutdown until there are no in-flight requests",
+				Buckets:   prometheus.ExponentialBuckets(10, 2, 14),
+			},
+		),
+		ServerLoadPercent: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "server_load_percent",
+				Help:      "the consumed percentage of the system rate limit",
+			},
+		),
+		GrpcMaxConcurrentStreams: promauto.With(reg).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "grpc_max_concurrent_streams",
+				Help:      "the maximum number of concurrent grpc streams per client connection",
+			},
+		),
+		BatchRequestBlobs: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "batch_request_blobs",
+				Help:      "the number of blobs of the batch dispersal requests",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 8),
+			},
+		),
+		RequeuedBlobs: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "requeue_total",
+				Help:      "the number of failed blobs dispersed again by RequeueBlob",
+			},
+		),
+		RPCFallbackUsed: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rpc_fallback_used_total",
+				Help:      "the number of times the latest finalized block was fetched from a fallback rpc endpoint",
+			},
+			[]string{"fallback"},
+		),
+		RPCFailures: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rpc_failures_total",
+				Help:      "the number of failed calls to the rpc endpoints, fallbacks included",
+			},
+		),
+		FinalizationReorgs: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "finalization_reorgs_total",
+				Help:      "the number of times the finalized block moved back or changed hash",
+			},
+		),
+		ConnectionsRejectedPerIP: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "connections_rejected_per_ip_total",
+				Help:      "the number of requests rejected because their client IP had too many active requests",
+			},
+		),
+		BulkGetBlobStatusSize: promauto.With(reg).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "bulk_get_blob_status_size",
+				Help:      "the number of request IDs of the bulk blob status requests",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 8),
+			},
+		),
+		BatchMetrics: NewBatchMetrics(reg, namespace),
+		registry:     reg,
+		httpPort:     httpPort,
+		logger:       logger,
 	}
 	return metrics
 }
 
+// Registry returns the registry the metrics are served from, so other components can register their own collectors
+func (g *Metrics) Registry() *prometheus.Registry {
+	return g.registry
+}
+
 // ObserveLatency observes the latency of a stage in 'stage
 func (g *Metrics) ObserveLatency(method string, latencyMs float64) {
 	g.Latency.WithLabelValues(method).Observe(latencyMs)
 }
 
+// ObserveBatch observes the per-batch aggregates of a processed batch
+func (g *Metrics) ObserveBatch(event BatchMetricsEvent) {
+	g.RecordBatchProcessed(event)
+}
+
 // IncrementSuccessfulBlobRequestNum increments the number of successful blob requests
 func (g *Metrics) IncrementSuccessfulBlobRequestNum(method string) {
 	g.NumBlobRequests.With(prometheus.Labels{
@@ -130,7 +362,112 @@ func (g *Metrics) HandleAccountRateLimitedRequest(blobBytes int, method string)
 	}).Add(float64(blobBytes))
 }
 
+// HandleRateLimitRejectedRequest updates the number of requests rejected by the rate limits for the given reason
+// and the size of the blob
+func (g *Metrics) HandleRateLimitRejectedRequest(reason string, systemLimit bool, blobBytes int, method string) {
+	g.RateLimitRejections.WithLabelValues(reason).Inc()
+	if systemLimit {
+		g.HandleSystemRateLimitedRequest(blobBytes, method)
+	} else {
+		g.HandleAccountRateLimitedRequest(blobBytes, method)
+	}
+}
+
+// HandleRateLimitCheck updates the number of rate limit checks which allowed or denied the request
+func (g *Metrics) HandleRateLimitCheck(accountType string, limitType string, allowed bool) {
+	if allowed {
+		g.RateLimitAllowed.WithLabelValues(accountType, limitType).Inc()
+	} else {
+		g.RateLimitDenied.WithLabelValues(accountType, limitType).Inc()
+	}
+}
+
+// HandleRateLimitError updates the number of rate limit checks which failed
+func (g *Metrics) HandleRateLimitError(accountType string, limitType string) {
+	g.RateLimitErrors.WithLabelValues(accountType, limitType).Inc()
+}
+
+// SetRateBucketFillRatio records the fill ratio of the buckets of the requesters with the given truncated hash
+func (g *Metrics) SetRateBucketFillRatio(requesterHash string, ratio float64) {
+	g.RateBucketFillRatio.WithLabelValues(requesterHash).Set(ratio)
+}
+
+// HandleDuplicateRejectedRequest updates the number of requests rejected as duplicates of the account and the size of the blob
+func (g *Metrics) HandleDuplicateRejectedRequest(accountID string, blobBytes int, method string) {
+	g.DedupWindowRejections.WithLabelValues(accountID).Inc()
+	g.HandleFailedRequest(blobBytes, method)
+}
+
+// HandleAdmissionGateRejectedRequest updates the number of requests rejected by the admission gate and the size of the blob
+func (g *Metrics) HandleAdmissionGateRejectedRequest(blobBytes int, method string) {
+	g.AdmissionGateRejections.Inc()
+	g.HandleSystemRateLimitedRequest(blobBytes, method)
+}
+
+// HandleLoadShedRequest updates the number of requests rejected by the load shedder and the size of the blob
+func (g *Metrics) HandleLoadShedRequest(blobBytes int, method string) {
+	g.LoadShedRejections.Inc()
+	g.HandleSystemRateLimitedRequest(blobBytes, method)
+}
+
+// HandleBatchRequest observes the latency of a batch dispersal request, the latencies of its blobs under method_blob,
+// and the number of blobs of the request. The outcome of each blob is recorded by the blob level handlers.
+func (g *Metrics) HandleBatchRequest(method string, batchLatency time.Duration, blobLatencies []time.Duration) {
+	g.ObserveLatency(method, float64(batchLatency.Microseconds())/1000)
+	for _, latency := range blobLatencies {
+		g.ObserveLatency(method+"_blob", float64(latency.Microseconds())/1000)
+	}
+	g.BatchRequestBlobs.Observe(float64(len(blobLatencies)))
+}
+
 // Start starts the metrics server
+// IncrementOnchainFallbackHits increments the number of blob statuses found on chain
+func (g *Metrics) IncrementOnchainFallbackHits() {
+	g.OnchainFallbackHits.Inc()
+}
+
+// IncrementRequeuedBlobs increments the number of failed blobs dispersed again
+func (g *Metrics) IncrementRequeuedBlobs() {
+	g.RequeuedBlobs.Inc()
+}
+
+// IncrementRPCFallbackUsed increments the number of times the fallback RPC endpoint of the index was used
+func (g *Metrics) IncrementRPCFallbackUsed(fallback int) {
+	g.RPCFallbackUsed.WithLabelValues(strconv.Itoa(fallback)).Inc()
+}
+
+// IncrementRPCFailures increments the number of failed calls to the RPC endpoints
+func (g *Metrics) IncrementRPCFailures() {
+	g.RPCFailures.Inc()
+}
+
+// IncrementFinalizationReorgs increments the number of finalization reorgs
+func (g *Metrics) IncrementFinalizationReorgs() {
+	g.FinalizationReorgs.Inc()
+}
+
+// IncrementConnectionsRejectedPerIP increments the number of requests rejected by the per IP limit
+func (g *Metrics) IncrementConnectionsRejectedPerIP() {
+	g.ConnectionsRejectedPerIP.Inc()
+}
+
+func (g *Metrics) IncrementKVReadRetries() {
+	g.KVReadRetries.Inc()
+}
+
+// ObserveKVReadLatency observes the latency of a read from the kv node
+func (g *Metrics) ObserveKVReadLatency(latency time.Duration) {
+	g.KVReadLatency.Observe(float64(latency.Milliseconds()))
+}
+
+// Handle registers an additional http handler on the metrics server, it must be called before Start
+func (g *Metrics) Handle(pattern string, handler http.Handler) {
+	if g.handlers == nil {
+		g.handlers = make(map[string]http.Handler)
+	}
+	g.handlers[pattern] = handler
+}
+
 func (g *Metrics) Start(ctx context.Context) {
 	g.logger.Info("Starting metrics server at ", "port", g.httpPort)
 	addr := fmt.Sprintf(":%s", g.httpPort)
@@ -141,6 +478,9 @@ func (g *Metrics) Start(ctx context.Context) {
 			g.registry,
 			promhttp.HandlerOpts{},
 		))
+		for pattern, handler := range g.handlers {
+			mux.Handle(pattern, handler)
+		}
 		err := http.ListenAndServe(addr, mux)
 		log.Error("Prometheus server failed", "err", err)
 	}()
@@ -0,0 +1,88 @@
+package disperser
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/0glabs/0g-data-avail/core"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DisperserReceipt is the receipt of an accepted blob, ABI encoded as
+// struct DisperserReceipt { bytes32 metadataHash; uint64 requestedAt; bytes32 blobHash; uint8[] quorumIds; }
+// so it can be verified by smart contracts.
+type DisperserReceipt struct {
+	// MetadataHash is keccak256 of the metadata hash of the blob key, which isn't 32 bytes long
+	MetadataHash [32]byte
+	RequestedAt  uint64
+	BlobHash     [32]byte
+	QuorumIds    []uint8
+}
+
+var receiptArguments = func() abi.Arguments {
+	receiptType, err := abi.NewType("tuple", "struct DisperserReceipt", []abi.ArgumentMarshaling{
+		{Name: "metadataHash", Type: "bytes32"},
+		{Name: "requestedAt", Type: "uint64"},
+		{Name: "blobHash", Type: "bytes32"},
+		{Name: "quorumIds", Type: "uint8[]"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return abi.Arguments{{Type: receiptType}}
+}()
+
+// NewDisperserReceipt makes the receipt of the blob stored with the given key
+func NewDisperserReceipt(blobKey BlobKey, requestedAt uint64, securityParams []*core.SecurityParam) (*DisperserReceipt, error) {
+	blobHash, err := hex.DecodeString(blobKey.BlobHash)
+	if err != nil || len(blobHash) != 32 {
+		return nil, fmt.Errorf("invalid blob hash %s", blobKey.BlobHash)
+	}
+	receipt := &DisperserReceipt{
+		MetadataHash: crypto.Keccak256Hash([]byte(blobKey.MetadataHash)),
+		RequestedAt:  requestedAt,
+		QuorumIds:    make([]uint8, len(securityParams)),
+	}
+	copy(receipt.BlobHash[:], blobHash)
+	for i, param := range securityParams {
+		receipt.QuorumIds[i] = param.QuorumID
+	}
+	return receipt, nil
+}
+
+// Encode returns the ABI encoding of the receipt
+func (r *DisperserReceipt) Encode() ([]byte, error) {
+	return receiptArguments.Pack(r)
+}
+
+// DecodeDisperserReceipt decodes an ABI encoded receipt
+func DecodeDisperserReceipt(data []byte) (*DisperserReceipt, error) {
+	values, err := receiptArguments.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode disperser receipt: %w", err)
+	}
+	return abi.ConvertType(values[0], new(DisperserReceipt)).(*DisperserReceipt), nil
+}
+
+// SignDisperserReceipt signs keccak256 of the encoded receipt with the disperser key
+func SignDisperserReceipt(encodedReceipt []byte, key *ecdsa.PrivateKey) ([]byte, error) {
+	signature, err := crypto.Sign(crypto.Keccak256(encodedReceipt), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign disperser receipt: %w", err)
+	}
+	return signature, nil
+}
+
+// VerifyDisperserReceipt checks the signature of the encoded receipt against the disperser public key,
+// either compressed or uncompressed
+func VerifyDisperserReceipt(encodedReceipt []byte, signature []byte, pubkey []byte) error {
+	if len(signature) != crypto.SignatureLength {
+		return fmt.Errorf("invalid receipt signature length %v", len(signature))
+	}
+	if !crypto.VerifySignature(pubkey, crypto.Keccak256(encodedReceipt), signature[:crypto.SignatureLength-1]) {
+		return fmt.Errorf("invalid receipt signature")
+	}
+	return nil
+}
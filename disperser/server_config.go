@@ -1,9 +1,128 @@
 package disperser
 
+import "time"
+
 const (
 	Localhost = "0.0.0.0"
 )
 
 type ServerConfig struct {
 	GrpcPort string
+	// HttpPort is the port of the HTTP/JSON gateway of the dispersal APIs, the gateway is not served if empty
+	HttpPort string
+	// MaxConcurrentStreams is the maximum number of concurrent grpc streams per client connection,
+	// the streams are not limited if 0
+	MaxConcurrentStreams uint32
+	// AdmissionBackpressureThreshold is the fraction of the encoding queue capacity above which
+	// new dispersal requests are rejected
+	AdmissionBackpressureThreshold float64
+	// SkipSchemaValidation disables the readiness check of the blob metadata table schema
+	SkipSchemaValidation bool
+	// EnableOnchainFallback makes GetBlobStatus read the blob confirmation from OnchainFallbackContract
+	// when the blob is not found locally
+	EnableOnchainFallback   bool
+	OnchainFallbackContract string
+	// AttestationKeyFile is the file of the hex encoded ECDSA key used to attest the time blobs are received at
+	AttestationKeyFile string
+	// LoadShedHeapPct is the percentage of the memory limit used by the heap above which new blobs are rejected
+	LoadShedHeapPct float64
+	// LoadShedGCPause is the recent GC pause above which new blobs are rejected
+	LoadShedGCPause time.Duration
+	// AdminToken is the bearer token required to call the admin APIs, which are not served if empty
+	AdminToken string
+	// AllowPrefixScan enables the admin API looking up blobs by a hash prefix, which scans all the blobs
+	AllowPrefixScan bool
+	// BlobSizePolicyURL is the Open Policy Agent endpoint deciding whether to accept the size of new blobs,
+	// only the protocol blob size limit is enforced if empty
+	BlobSizePolicyURL string
+	// DedupWindow is the time window within which an account submitting the same blob again is rejected,
+	// duplicate blobs are not rejected if 0
+	DedupWindow time.Duration
+	// IdempotencyWindowDuration is the time window within which a request retried with the same idempotency key by
+	// the same account returns the reply of the original request, the idempotency keys are ignored if 0
+	IdempotencyWindowDuration time.Duration
+	// MaxStreamBufferSize is the maximum number of bytes of a blob streamed to DisperseBlobStream,
+	// the blobs are limited to core.MaxBlobSize if 0
+	MaxStreamBufferSize int
+	// WatchTimeout is the maximum duration of a blob status watch, the watches last 10 minutes if 0
+	WatchTimeout time.Duration
+	// DeleteOnCancel makes CancelBlob remove the cancelled blobs, instead of only marking them as failed
+	DeleteOnCancel bool
+	// MaxTTLExtension is the maximum duration ExtendBlobTTL adds to the expiry of a blob in a single call,
+	// the TTL of blobs can't be extended if 0
+	MaxTTLExtension time.Duration
+	// RateLimitStatusRate is the number of GetRateLimitStatus calls per second allowed to each caller,
+	// the calls are not limited if 0
+	RateLimitStatusRate float64
+	// AdminPort is the internal port of the admin http endpoints, they are not served if empty
+	AdminPort string
+	// RateConfigFile is a JSON rate config loaded at startup and reloaded when it changes, see apiserver.RateConfigFile
+	RateConfigFile string
+	// AuditLogPath is the file the audit events of the requests are written to, the requests are not audited if empty
+	AuditLogPath string
+	// APIKeyFile is the JSON file of the API keys of the callers and their account IDs, reloaded on SIGHUP.
+	// The APIs are open to all callers if empty.
+	APIKeyFile string
+	// EnableBlobCallbacks serves RegisterBlobCallback, which POSTs the final status of the blobs to the callback URLs
+	EnableBlobCallbacks bool
+	// CallbackMaxRetries is the number of times a callback is retried when the POST fails or gets a non-2xx response
+	CallbackMaxRetries int
+	// CallbackURLAllowlist are the prefixes the callback URLs must start with, no callback can be registered if empty
+	CallbackURLAllowlist []string
+	// ShutdownTimeout is the time the in-flight requests are waited for on shutdown before they are cancelled,
+	// 30 seconds if 0
+	ShutdownTimeout time.Duration
+	// MaxRecvMsgSizeMiB is the maximum size of the messages received by the grpc server and of the HTTP gateway requests,
+	// in MiB. The grpc default of 4 MiB is used if 0 or negative.
+	MaxRecvMsgSizeMiB int
+	// MaxSendMsgSizeMiB is the maximum size of the messages sent by the grpc server, e.g. the blobs of RetrieveBlob,
+	// in MiB. The grpc default is used if 0 or negative.
+	MaxSendMsgSizeMiB int
+	// DefaultRequestTimeout is the deadline of the requests without one, and the maximum deadline of DisperseBlob.
+	// The requests are not bounded if 0.
+	DefaultRequestTimeout time.Duration
+	// PerMethodTimeouts overrides DefaultRequestTimeout for the methods it maps, by their name e.g. DisperseBlob
+	PerMethodTimeouts map[string]time.Duration
+	// FallbackRPCEndpoints are the RPC endpoints the latest finalized block is fetched from, in order,
+	// when the primary RPC endpoint fails
+	FallbackRPCEndpoints []string
+	// FinalizationDepth is the number of blocks below the latest block a block is considered finalized at, for the chains
+	// without the finalized block tag. The finalized block tag is used if 0.
+	FinalizationDepth uint32
+	// MinBlobSize is the minimum size of the dispersed blobs in bytes, e.g. to reject the test or misconfigured requests
+	MinBlobSize int
+	// MaxConnectionsPerIP is the maximum number of concurrent requests of a client IP, the requests are not limited if 0
+	MaxConnectionsPerIP int
+	// TLSConfig is the TLS of the grpc server, it serves plaintext if the certificate is not set
+	TLSConfig TLSConfig
+	// KeepaliveConfig is the keepalive of the grpc connections, e.g. to keep the idle connections open behind a load balancer
+	KeepaliveConfig KeepaliveConfig
+}
+
+// KeepaliveConfig holds the keepalive parameters of the grpc server, the grpc defaults are used for the fields which are 0
+type KeepaliveConfig struct {
+	// ServerKeepaliveTime is the time without activity after which the server pings the client, at least 1 second
+	ServerKeepaliveTime time.Duration
+	// ServerKeepaliveTimeout is the time the server waits for the ping ack before closing the connection
+	ServerKeepaliveTimeout time.Duration
+	// MaxConnectionIdle is the time without any stream after which a connection is closed, never if 0
+	MaxConnectionIdle time.Duration
+	// MaxConnectionAge is the time after which a connection is closed, never if 0
+	MaxConnectionAge time.Duration
+	// MaxConnectionAgeGrace is the time the streams of a connection closed by MaxConnectionAge are waited for, forever if 0
+	MaxConnectionAgeGrace time.Duration
+	// MinClientKeepaliveTime is the minimum time between the pings of a client, the connection of a client pinging more
+	// often is closed with GOAWAY. It must be lower than the keepalive time of the clients.
+	MinClientKeepaliveTime time.Duration
+}
+
+// TLSConfig holds the PEM files of the grpc server TLS, and of the mutual TLS if ClientCAFile is set
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the CA bundle the client certificates are verified with
+	ClientCAFile string
+	// RequireClientCert rejects the clients which don't present a certificate signed by the client CA,
+	// otherwise the clients may connect without a certificate
+	RequireClientCert bool
 }
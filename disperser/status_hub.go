@@ -0,0 +1,140 @@
+package disperser
+
+import (
+	"context"
+	"sync"
+)
+
+// BlobStatusHub notifies the subscribers of a blob when its status changes.
+// The notifications carry no payload, the subscribers read the new status from the blob store.
+type BlobStatusHub struct {
+	mu          sync.Mutex
+	subscribers map[BlobKey]map[chan struct{}]struct{}
+}
+
+func NewBlobStatusHub() *BlobStatusHub {
+	return &BlobStatusHub{
+		subscribers: make(map[BlobKey]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns a channel notified when the status of the blob changes, and the function which unsubscribes it.
+// Notifications are coalesced if the subscriber is not keeping up. The unsubscribe function must be called.
+func (h *BlobStatusHub) Subscribe(key BlobKey) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers[key] == nil {
+		h.subscribers[key] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[key][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[key], ch)
+		if len(h.subscribers[key]) == 0 {
+			delete(h.subscribers, key)
+		}
+	}
+}
+
+// Publish notifies the subscribers of the blobs, it never blocks
+func (h *BlobStatusHub) Publish(keys ...BlobKey) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range keys {
+		for ch := range h.subscribers[key] {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// NumSubscribers returns the number of subscriptions to the blob
+func (h *BlobStatusHub) NumSubscribers(key BlobKey) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers[key])
+}
+
+// notifyingBlobStore publishes the status changes made through the blob store to a BlobStatusHub
+type notifyingBlobStore struct {
+	BlobStore
+	hub *BlobStatusHub
+}
+
+// NewNotifyingBlobStore wraps the blob store so that the blob status changes, e.g. made by the batcher,
+// are published to the hub
+func NewNotifyingBlobStore(store BlobStore, hub *BlobStatusHub) BlobStore {
+	return &notifyingBlobStore{
+		BlobStore: store,
+		hub:       hub,
+	}
+}
+
+func (s *notifyingBlobStore) MarkBlobConfirmed(ctx context.Context, existingMetadata *BlobMetadata, confirmationInfo *ConfirmationInfo) (*BlobMetadata, error) {
+	metadata, err := s.BlobStore.MarkBlobConfirmed(ctx, existingMetadata, confirmationInfo)
+	if err == nil {
+		s.hub.Publish(existingMetadata.GetBlobKey())
+	}
+	return metadata, err
+}
+
+func (s *notifyingBlobStore) MarkBlobFinalized(ctx context.Context, blobKey BlobKey) error {
+	err := s.BlobStore.MarkBlobFinalized(ctx, blobKey)
+	if err == nil {
+		s.hub.Publish(blobKey)
+	}
+	return err
+}
+
+func (s *notifyingBlobStore) BatchMarkBlobsFinalized(ctx context.Context, blobKeys []BlobKey) error {
+	err := s.BlobStore.BatchMarkBlobsFinalized(ctx, blobKeys)
+	// some of the blobs may have been marked even if it failed, the subscribers re-read the status anyway
+	s.hub.Publish(blobKeys...)
+	return err
+}
+
+func (s *notifyingBlobStore) MarkBlobProcessing(ctx context.Context, blobKey BlobKey) error {
+	err := s.BlobStore.MarkBlobProcessing(ctx, blobKey)
+	if err == nil {
+		s.hub.Publish(blobKey)
+	}
+	return err
+}
+
+func (s *notifyingBlobStore) MarkBlobFailed(ctx context.Context, blobKey BlobKey) error {
+	err := s.BlobStore.MarkBlobFailed(ctx, blobKey)
+	if err == nil {
+		s.hub.Publish(blobKey)
+	}
+	return err
+}
+
+func (s *notifyingBlobStore) OverrideBlobStatus(ctx context.Context, blobKey BlobKey, status BlobStatus, reason string) error {
+	err := s.BlobStore.OverrideBlobStatus(ctx, blobKey, status, reason)
+	if err == nil {
+		s.hub.Publish(blobKey)
+	}
+	return err
+}
+
+func (s *notifyingBlobStore) TransitionBlobStatus(ctx context.Context, blobKey BlobKey, from, to BlobStatus, reason string) error {
+	err := s.BlobStore.TransitionBlobStatus(ctx, blobKey, from, to, reason)
+	if err == nil {
+		s.hub.Publish(blobKey)
+	}
+	return err
+}
+
+func (s *notifyingBlobStore) HandleBlobFailure(ctx context.Context, metadata *BlobMetadata, maxRetry uint) error {
+	err := s.BlobStore.HandleBlobFailure(ctx, metadata, maxRetry)
+	if err == nil {
+		s.hub.Publish(metadata.GetBlobKey())
+	}
+	return err
+}